@@ -22,6 +22,12 @@ type Zeroer interface {
 	IsZero() bool
 }
 
+// Defaulter allows custom struct types to populate fields that were missing from a document with
+// application defaults. SetDefaults is called on a pointer to the struct after it is decoded.
+type Defaulter interface {
+	SetDefaults()
+}
+
 // D represents a BSON Document. This type can be used to represent BSON in a concise and readable
 // manner. It should generally be used when serializing to BSON. For deserializing, the Raw or
 // Document types should be used.
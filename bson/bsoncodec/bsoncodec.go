@@ -107,6 +107,10 @@ func (vde ValueDecoderError) Error() string {
 type EncodeContext struct {
 	*Registry
 	MinSize bool
+	// SortMapKeys causes the keys of Go maps to be encoded in sorted order, so that encoding the
+	// same map always produces the same bytes. Otherwise they are encoded in the random order of
+	// map iteration.
+	SortMapKeys bool
 }
 
 // DecodeContext is the contextual information required for a Codec to decode a
@@ -119,6 +123,10 @@ type DecodeContext struct {
 	// Ancestor is a bson.M, BSON embedded document values being decoded into an empty interface
 	// will be decoded into a bson.M.
 	Ancestor reflect.Type
+	// DisallowUnknownFields causes an error to be returned when a document being decoded into a
+	// struct contains a field that does not match any field of the struct and the struct does not
+	// have an inline map to hold it. It applies to embedded structs as well.
+	DisallowUnknownFields bool
 }
 
 // ValueCodec is the interface that groups the methods to encode and decode
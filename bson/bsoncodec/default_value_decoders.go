@@ -599,7 +599,7 @@ func (dvd DefaultValueDecoders) URLDecodeValue(dc DecodeContext, vr bsonrw.Value
 	return nil
 }
 
-// TimeDecodeValue is the ValueDecoderFunc for time.Time.
+// TimeDecodeValue is the ValueDecoderFunc for time.Time. Decoded times are in UTC.
 func (dvd DefaultValueDecoders) TimeDecodeValue(dc DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
 	if vr.Type() != bsontype.DateTime {
 		return fmt.Errorf("cannot decode %v into a time.Time", vr.Type())
@@ -618,6 +618,21 @@ func (dvd DefaultValueDecoders) TimeDecodeValue(dc DecodeContext, vr bsonrw.Valu
 	return nil
 }
 
+// LocalTimeDecodeValue is a ValueDecoderFunc for time.Time that decodes times in the local time
+// zone instead of UTC. It is not registered by default; register it for time.Time on a
+// RegistryBuilder to use it.
+func (dvd DefaultValueDecoders) LocalTimeDecodeValue(dc DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != tTime {
+		return ValueDecoderError{Name: "LocalTimeDecodeValue", Types: []reflect.Type{tTime}, Received: val}
+	}
+
+	if err := dvd.TimeDecodeValue(dc, vr, val); err != nil {
+		return err
+	}
+	val.Set(reflect.ValueOf(val.Interface().(time.Time).Local()))
+	return nil
+}
+
 // ByteSliceDecodeValue is the ValueDecoderFunc for []byte.
 func (dvd DefaultValueDecoders) ByteSliceDecodeValue(dc DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
 	if vr.Type() != bsontype.Binary && vr.Type() != bsontype.Null {
@@ -13,6 +13,7 @@ import (
 	"math"
 	"net/url"
 	"reflect"
+	"sort"
 	"sync"
 	"time"
 
@@ -295,6 +296,9 @@ func (dve DefaultValueEncoders) mapEncodeValue(ec EncodeContext, dw bsonrw.Docum
 	}
 
 	keys := val.MapKeys()
+	if ec.SortMapKeys {
+		sort.Sort(mapKeys(keys))
+	}
 	for _, key := range keys {
 		if collisionFn != nil && collisionFn(key.String()) {
 			return fmt.Errorf("Key %s of inlined map conflicts with a struct field name", key)
@@ -320,6 +324,13 @@ func (dve DefaultValueEncoders) mapEncodeValue(ec EncodeContext, dw bsonrw.Docum
 	return dw.WriteDocumentEnd()
 }
 
+// mapKeys sorts the string keys of a map.
+type mapKeys []reflect.Value
+
+func (mk mapKeys) Len() int           { return len(mk) }
+func (mk mapKeys) Less(i, j int) bool { return mk[i].String() < mk[j].String() }
+func (mk mapKeys) Swap(i, j int)      { mk[i], mk[j] = mk[j], mk[i] }
+
 // ArrayEncodeValue is the ValueEncoderFunc for array types.
 func (dve DefaultValueEncoders) ArrayEncodeValue(ec EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
 	if !val.IsValid() || val.Kind() != reflect.Array {
@@ -79,6 +79,10 @@ type Registry struct {
 	typeEncoders map[reflect.Type]ValueEncoder
 	typeDecoders map[reflect.Type]ValueDecoder
 
+	// registeredTypes are the types encoders were registered for. typeEncoders also caches the
+	// encoders looked up for other types.
+	registeredTypes map[reflect.Type]bool
+
 	interfaceEncoders []interfaceValueEncoder
 	interfaceDecoders []interfaceValueDecoder
 
@@ -188,6 +192,16 @@ func (rb *RegistryBuilder) RegisterDefaultDecoder(kind reflect.Kind, dec ValueDe
 	return rb
 }
 
+// RegisterStructTagParser registers a StructCodec that uses p for struct tag parsing as the default
+// encoder and decoder for structs. It panics if p is nil.
+func (rb *RegistryBuilder) RegisterStructTagParser(p StructTagParser) *RegistryBuilder {
+	sc, err := NewStructCodec(p)
+	if err != nil {
+		panic(err)
+	}
+	return rb.RegisterDefaultEncoder(reflect.Struct, sc).RegisterDefaultDecoder(reflect.Struct, sc)
+}
+
 // RegisterTypeMapEntry will register the provided type to the BSON type. The primary usage for this
 // mapping is decoding situations where an empty interface is used and a default type needs to be
 // created and decoded into.
@@ -207,8 +221,10 @@ func (rb *RegistryBuilder) Build() *Registry {
 	registry := new(Registry)
 
 	registry.typeEncoders = make(map[reflect.Type]ValueEncoder)
+	registry.registeredTypes = make(map[reflect.Type]bool)
 	for t, enc := range rb.typeEncoders {
 		registry.typeEncoders[t] = enc
+		registry.registeredTypes[t] = true
 	}
 
 	registry.typeDecoders = make(map[reflect.Type]ValueDecoder)
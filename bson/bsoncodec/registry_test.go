@@ -298,6 +298,23 @@ func TestRegistry(t *testing.T) {
 				})
 			}
 		})
+		t.Run("StructTagParser", func(t *testing.T) {
+			rb := NewRegistryBuilder().RegisterStructTagParser(JSONFallbackStructTagParser)
+			sc, ok := rb.kindEncoders[reflect.Struct].(*StructCodec)
+			if !ok || sc.parser == nil {
+				t.Fatalf("Expected a StructCodec with a parser to be registered. got %v", rb.kindEncoders[reflect.Struct])
+			}
+			if rb.kindDecoders[reflect.Struct] != ValueDecoder(sc) {
+				t.Errorf("Expected the StructCodec to be registered as the struct decoder. got %v", rb.kindDecoders[reflect.Struct])
+			}
+
+			defer func() {
+				if err := recover(); err == nil {
+					t.Error("Expected registering a nil StructTagParser to panic")
+				}
+			}()
+			NewRegistryBuilder().RegisterStructTagParser(nil)
+		})
 	})
 	t.Run("Type Map", func(t *testing.T) {
 		reg := NewRegistryBuilder().
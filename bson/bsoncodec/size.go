@@ -0,0 +1,297 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bsoncodec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// EstimateSize returns the number of bytes val takes up when it is encoded as a BSON value with the
+// encoders of ec.Registry, such as the size of the document a struct is encoded as. Structs are
+// measured with the cached descriptions of the StructCodec, and strings, numbers, maps, slices and
+// the types of the primitive package are measured without encoding them. Values with an encoder
+// registered for their type or for an interface they implement, such as Marshalers, are encoded to
+// measure them, so the size is exact as long as the kind encoders of the registry are the default
+// ones.
+func EstimateSize(ec EncodeContext, val reflect.Value) (int, error) {
+	if ec.Registry == nil {
+		return 0, errors.New("an EncodeContext with a Registry must be provided to EstimateSize")
+	}
+	return estimateValueSize(ec, val)
+}
+
+func estimateValueSize(ec EncodeContext, val reflect.Value) (int, error) {
+	if !val.IsValid() {
+		return 0, nil // null
+	}
+
+	switch t := val.Type(); t {
+	case tTime, tDateTime, tTimestamp:
+		return 8, nil
+	case tOID:
+		return 12, nil
+	case tDecimal:
+		return 16, nil
+	case tNull, tUndefined, tMinKey, tMaxKey:
+		return 0, nil
+	case tByteSlice:
+		if val.IsNil() {
+			return 0, nil
+		}
+		return binarySize(0x00, val.Len()), nil
+	case tBinary:
+		b := val.Interface().(primitive.Binary)
+		return binarySize(b.Subtype, len(b.Data)), nil
+	case tRegex:
+		re := val.Interface().(primitive.Regex)
+		return len(re.Pattern) + 1 + len(re.Options) + 1, nil
+	case tJavaScript, tSymbol:
+		return stringSize(val.String()), nil
+	case tDBPointer:
+		return stringSize(val.Interface().(primitive.DBPointer).DB) + 12, nil
+	case tCodeWithScope:
+		cws := val.Interface().(primitive.CodeWithScope)
+		scope, err := estimateValueSize(ec, reflect.ValueOf(cws.Scope))
+		if err != nil {
+			return 0, err
+		}
+		return 4 + stringSize(string(cws.Code)) + scope, nil
+	case tCoreDocument:
+		return len(val.Interface().(bsoncore.Document)), nil
+	case tURL:
+		u := val.Interface().(url.URL)
+		return stringSize(u.String()), nil
+	case tJSONNumber:
+		if i64, err := val.Interface().(json.Number).Int64(); err == nil {
+			return intSize(i64, ec.MinSize), nil
+		}
+		return 8, nil
+	}
+
+	if encodedByType(ec.Registry, val.Type()) {
+		return estimateEncodedSize(ec, val)
+	}
+
+	switch val.Kind() {
+	case reflect.Bool:
+		return 1, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return 4, nil
+	case reflect.Int:
+		return intSize(val.Int(), true), nil
+	case reflect.Int64:
+		return intSize(val.Int(), ec.MinSize), nil
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		if ec.MinSize && val.Uint() <= math.MaxInt32 {
+			return 4, nil
+		}
+		return 8, nil
+	case reflect.Float32, reflect.Float64:
+		return 8, nil
+	case reflect.String:
+		return stringSize(val.String()), nil
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return 0, nil
+		}
+		return estimateValueSize(ec, val.Elem())
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return 0, ValueEncoderError{Name: "MapEncodeValue", Kinds: []reflect.Kind{reflect.Map}, Received: val}
+		}
+		if val.IsNil() {
+			return 0, nil
+		}
+		size, err := estimateMapElementsSize(ec, val, nil)
+		return 4 + size + 1, err
+	case reflect.Slice:
+		if val.IsNil() {
+			return 0, nil
+		}
+		if val.Type().ConvertibleTo(tD) {
+			return estimateDSize(ec, val.Convert(tD).Interface().(primitive.D))
+		}
+		return estimateArraySize(ec, val)
+	case reflect.Array:
+		if val.Type().Elem() == tE {
+			d := make(primitive.D, val.Len())
+			reflect.Copy(reflect.ValueOf(d), val)
+			return estimateDSize(ec, d)
+		}
+		return estimateArraySize(ec, val)
+	case reflect.Struct:
+		if sc, ok := ec.Registry.kindEncoders[reflect.Struct].(*StructCodec); ok {
+			return sc.estimateSize(ec, val)
+		}
+		return estimateEncodedSize(ec, val)
+	}
+
+	return 0, ErrNoEncoder{Type: val.Type()}
+}
+
+// estimateSize returns the size of the document the struct val is encoded as, mirroring EncodeValue.
+func (sc *StructCodec) estimateSize(ec EncodeContext, val reflect.Value) (int, error) {
+	sd, err := sc.describeStruct(ec.Registry, val.Type())
+	if err != nil {
+		return 0, err
+	}
+
+	size := 4 + 1
+	var rv reflect.Value
+	for _, desc := range sd.fl {
+		if desc.inline == nil {
+			rv = val.Field(desc.idx)
+		} else {
+			rv = val.FieldByIndex(desc.inline)
+		}
+
+		if desc.encoder == nil {
+			return 0, ErrNoEncoder{Type: rv.Type()}
+		}
+
+		iszero := sc.isZero
+		if iz, ok := desc.encoder.(CodecZeroer); ok {
+			iszero = iz.IsTypeZero
+		}
+		if desc.omitEmpty && iszero(rv.Interface()) {
+			continue
+		}
+
+		ectx := EncodeContext{Registry: ec.Registry, MinSize: desc.minSize, SortMapKeys: ec.SortMapKeys}
+		vsize, err := estimateValueSize(ectx, rv)
+		if err != nil {
+			return 0, err
+		}
+		size += elementSize(desc.name, vsize)
+	}
+
+	if sd.inlineMap >= 0 {
+		collisionFn := func(key string) bool {
+			_, exists := sd.fm[key]
+			return exists
+		}
+		msize, err := estimateMapElementsSize(ec, val.Field(sd.inlineMap), collisionFn)
+		if err != nil {
+			return 0, err
+		}
+		size += msize
+	}
+	return size, nil
+}
+
+func estimateMapElementsSize(ec EncodeContext, val reflect.Value, collisionFn func(string) bool) (int, error) {
+	var size int
+	for _, key := range val.MapKeys() {
+		if collisionFn != nil && collisionFn(key.String()) {
+			return 0, fmt.Errorf("Key %s of inlined map conflicts with a struct field name", key)
+		}
+		vsize, err := estimateValueSize(ec, val.MapIndex(key))
+		if err != nil {
+			return 0, err
+		}
+		size += elementSize(key.String(), vsize)
+	}
+	return size, nil
+}
+
+func estimateDSize(ec EncodeContext, d primitive.D) (int, error) {
+	size := 4 + 1
+	for _, e := range d {
+		vsize, err := estimateValueSize(ec, reflect.ValueOf(e.Value))
+		if err != nil {
+			return 0, err
+		}
+		size += elementSize(e.Key, vsize)
+	}
+	return size, nil
+}
+
+func estimateArraySize(ec EncodeContext, val reflect.Value) (int, error) {
+	size := 4 + 1
+	for idx := 0; idx < val.Len(); idx++ {
+		vsize, err := estimateValueSize(ec, val.Index(idx))
+		if err != nil {
+			return 0, err
+		}
+		size += elementSize(strconv.Itoa(idx), vsize)
+	}
+	return size, nil
+}
+
+// estimateEncodedSize measures val by encoding it as the only element of a document with an empty
+// key, which adds the length, type, key and terminator of the document to the size of the value.
+func estimateEncodedSize(ec EncodeContext, val reflect.Value) (int, error) {
+	encoder, err := ec.LookupEncoder(val.Type())
+	if err != nil {
+		return 0, err
+	}
+
+	sw := sliceWriterPool.Get().(*bsonrw.SliceWriter)
+	defer sliceWriterPool.Put(sw)
+	*sw = (*sw)[:0]
+
+	vw := bvwPool.Get(sw)
+	defer bvwPool.Put(vw)
+
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return 0, err
+	}
+	evw, err := dw.WriteDocumentElement("")
+	if err != nil {
+		return 0, err
+	}
+	if err = encoder.EncodeValue(ec, evw, val); err != nil {
+		return 0, err
+	}
+	if err = dw.WriteDocumentEnd(); err != nil {
+		return 0, err
+	}
+	return len(*sw) - elementSize("", 0) - 4 - 1, nil
+}
+
+// encodedByType returns true if the values of type t are encoded by an encoder registered for t or
+// for an interface t implements, rather than by the encoder for its kind.
+func encodedByType(r *Registry, t reflect.Type) bool {
+	if r.registeredTypes[t] {
+		return true
+	}
+	_, found := r.lookupInterfaceEncoder(t)
+	return found
+}
+
+func elementSize(key string, valueSize int) int {
+	return 1 + len(key) + 1 + valueSize
+}
+
+func stringSize(s string) int {
+	return 4 + len(s) + 1
+}
+
+func binarySize(subtype byte, n int) int {
+	if subtype == 0x02 {
+		return 4 + 1 + 4 + n
+	}
+	return 4 + 1 + n
+}
+
+func intSize(i64 int64, minSize bool) int {
+	if minSize && fitsIn32Bits(i64) {
+		return 4
+	}
+	return 8
+}
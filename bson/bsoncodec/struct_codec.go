@@ -28,6 +28,14 @@ type Zeroer interface {
 	IsZero() bool
 }
 
+// Defaulter allows custom struct types to populate fields with application defaults after they are
+// decoded. SetDefaults is called on a pointer to the struct once all of the elements of the document
+// have been decoded, so fields that were missing from the document still have the values they had
+// before decoding, usually their zero values.
+type Defaulter interface {
+	SetDefaults()
+}
+
 // StructCodec is the Codec used for struct values.
 type StructCodec struct {
 	cache  map[reflect.Type]*structDescription
@@ -93,7 +101,7 @@ func (sc *StructCodec) EncodeValue(r EncodeContext, vw bsonrw.ValueWriter, val r
 			return err
 		}
 
-		ectx := EncodeContext{Registry: r.Registry, MinSize: desc.minSize}
+		ectx := EncodeContext{Registry: r.Registry, MinSize: desc.minSize, SortMapKeys: r.SortMapKeys}
 		err = encoder.EncodeValue(ectx, vw2, rv)
 		if err != nil {
 			return err
@@ -139,9 +147,12 @@ func (sc *StructCodec) DecodeValue(r DecodeContext, vr bsonrw.ValueReader, val r
 		if inlineMap.IsNil() {
 			inlineMap.Set(reflect.MakeMap(inlineMap.Type()))
 		}
-		decoder, err = r.LookupDecoder(inlineMap.Type().Elem())
-		if err != nil {
-			return err
+		decoder = sd.inlineMapDecoder
+		if decoder == nil {
+			decoder, err = r.LookupDecoder(inlineMap.Type().Elem())
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -162,8 +173,11 @@ func (sc *StructCodec) DecodeValue(r DecodeContext, vr bsonrw.ValueReader, val r
 		fd, exists := sd.fm[name]
 		if !exists {
 			if sd.inlineMap < 0 {
-				// The encoding/json package requires a flag to return on error for non-existent fields.
-				// This functionality seems appropriate for the struct codec.
+				// Like the encoding/json package, non-existent fields are skipped unless the
+				// DisallowUnknownFields flag is set.
+				if r.DisallowUnknownFields {
+					return fmt.Errorf("cannot decode element '%s' into %s; the struct has no matching field", name, val.Type())
+				}
 				err = vr.Skip()
 				if err != nil {
 					return err
@@ -195,7 +209,7 @@ func (sc *StructCodec) DecodeValue(r DecodeContext, vr bsonrw.ValueReader, val r
 		}
 		field = field.Addr()
 
-		dctx := DecodeContext{Registry: r.Registry, Truncate: fd.truncate}
+		dctx := DecodeContext{Registry: r.Registry, Truncate: fd.truncate, DisallowUnknownFields: r.DisallowUnknownFields}
 		if fd.decoder == nil {
 			return ErrNoDecoder{Type: field.Elem().Type()}
 		}
@@ -213,6 +227,9 @@ func (sc *StructCodec) DecodeValue(r DecodeContext, vr bsonrw.ValueReader, val r
 		}
 	}
 
+	if sd.defaulter {
+		val.Addr().Interface().(Defaulter).SetDefaults()
+	}
 	return nil
 }
 
@@ -246,10 +263,14 @@ func (sc *StructCodec) isZero(i interface{}) bool {
 	return false
 }
 
+// structDescription is the cached decoding and encoding plan of a struct type, so that the struct
+// tags and the codecs of the fields are only looked up once per type.
 type structDescription struct {
-	fm        map[string]fieldDescription
-	fl        []fieldDescription
-	inlineMap int
+	fm               map[string]fieldDescription
+	fl               []fieldDescription
+	inlineMap        int
+	inlineMapDecoder ValueDecoder // decodes the elements of the inline map
+	defaulter        bool         // whether pointers to the struct implement Defaulter
 }
 
 type fieldDescription struct {
@@ -278,6 +299,7 @@ func (sc *StructCodec) describeStruct(r *Registry, t reflect.Type) (*structDescr
 		fm:        make(map[string]fieldDescription, numFields),
 		fl:        make([]fieldDescription, 0, numFields),
 		inlineMap: -1,
+		defaulter: reflect.PtrTo(t).Implements(tDefaulter),
 	}
 
 	for i := 0; i < numFields; i++ {
@@ -320,6 +342,7 @@ func (sc *StructCodec) describeStruct(r *Registry, t reflect.Type) (*structDescr
 					return nil, errors.New("(struct " + t.String() + ") inline map must have a string keys")
 				}
 				sd.inlineMap = description.idx
+				sd.inlineMapDecoder, _ = r.LookupDecoder(sf.Type.Elem())
 			case reflect.Struct:
 				inlinesf, err := sc.describeStruct(r, sf.Type)
 				if err != nil {
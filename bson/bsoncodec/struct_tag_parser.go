@@ -86,11 +86,27 @@ type StructTags struct {
 // value consisting entirely of '-' will return a StructTags with Skip true and
 // the remaining fields will be their default values.
 var DefaultStructTagParser StructTagParserFunc = func(sf reflect.StructField) (StructTags, error) {
-	key := strings.ToLower(sf.Name)
 	tag, ok := sf.Tag.Lookup("bson")
 	if !ok && !strings.Contains(string(sf.Tag), ":") && len(sf.Tag) > 0 {
 		tag = string(sf.Tag)
 	}
+	return parseTags(strings.ToLower(sf.Name), tag)
+}
+
+// JSONFallbackStructTagParser is a StructTagParser that handles the bson struct tag like the
+// DefaultStructTagParser, but uses the json struct tag for fields that have no bson struct tag. This
+// allows structs that are already tagged for encoding/json to be used without adding bson tags.
+// The json tag options that have no bson equivalent, such as "string", are ignored.
+var JSONFallbackStructTagParser StructTagParserFunc = func(sf reflect.StructField) (StructTags, error) {
+	if _, ok := sf.Tag.Lookup("bson"); !ok {
+		if tag, ok := sf.Tag.Lookup("json"); ok {
+			return parseTags(strings.ToLower(sf.Name), tag)
+		}
+	}
+	return DefaultStructTagParser(sf)
+}
+
+func parseTags(key string, tag string) (StructTags, error) {
 	var st StructTags
 	if tag == "-" {
 		st.Skip = true
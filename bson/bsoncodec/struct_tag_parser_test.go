@@ -71,3 +71,47 @@ func TestDefaultStructTagParser(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONFallbackStructTagParser(t *testing.T) {
+	testCases := []struct {
+		name string
+		sf   reflect.StructField
+		want StructTags
+	}{
+		{
+			"no tag",
+			reflect.StructField{Name: "Foo"},
+			StructTags{Name: "foo"},
+		},
+		{
+			"json tag",
+			reflect.StructField{Name: "foo", Tag: reflect.StructTag(`json:"bar,omitempty,string"`)},
+			StructTags{Name: "bar", OmitEmpty: true},
+		},
+		{
+			"json tag only dash",
+			reflect.StructField{Name: "foo", Tag: reflect.StructTag(`json:"-"`)},
+			StructTags{Skip: true},
+		},
+		{
+			"bson tag takes precedence",
+			reflect.StructField{Name: "foo", Tag: reflect.StructTag(`bson:"bar" json:"baz,omitempty"`)},
+			StructTags{Name: "bar"},
+		},
+		{
+			"no bson or json tag",
+			reflect.StructField{Name: "foo", Tag: reflect.StructTag("bar,minsize")},
+			StructTags{Name: "bar", MinSize: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := JSONFallbackStructTagParser(tc.sf)
+			noerr(t, err)
+			if !cmp.Equal(got, tc.want) {
+				t.Errorf("Returned struct tags do not match. got %#v; want %#v", got, tc.want)
+			}
+		})
+	}
+}
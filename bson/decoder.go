@@ -25,6 +25,10 @@ var decPool = sync.Pool{
 	},
 }
 
+// This pool is used to keep the allocations of the ValueReaders used by the Unmarshal* functions
+// down.
+var bvrPool = bsonrw.NewBSONValueReaderPool()
+
 // A Decoder reads and decodes BSON documents from a stream. It reads from a bsonrw.ValueReader as
 // the source of BSON data.
 type Decoder struct {
@@ -93,6 +97,12 @@ func (d *Decoder) Reset(vr bsonrw.ValueReader) error {
 	return nil
 }
 
+// DisallowUnknownFields causes the Decoder to return an error when the destination is a struct and
+// the document contains a field that does not match any field of the struct.
+func (d *Decoder) DisallowUnknownFields() {
+	d.dc.DisallowUnknownFields = true
+}
+
 // SetRegistry replaces the current registry of the decoder with r.
 func (d *Decoder) SetRegistry(r *bsoncodec.Registry) error {
 	d.dc.Registry = r
@@ -192,6 +192,45 @@ func TestDecoderv2(t *testing.T) {
 			t.Errorf("Results do not match. got %+v; want %+v", got, want)
 		}
 	})
+	t.Run("DisallowUnknownFields", func(t *testing.T) {
+		type inner struct {
+			A int32
+		}
+		type outer struct {
+			Item  string
+			Inner inner
+		}
+		type withInlineMap struct {
+			Item  string
+			Extra map[string]interface{} `bson:",inline"`
+		}
+		testCases := []struct {
+			name    string
+			data    []byte
+			val     interface{}
+			wantErr bool
+		}{
+			{"known fields", docToBytes(D{{"item", "canvas"}, {"inner", D{{"a", int32(1)}}}}), &outer{}, false},
+			{"unknown field", docToBytes(D{{"item", "canvas"}, {"qty", 4}}), &outer{}, true},
+			{"unknown embedded field", docToBytes(D{{"inner", D{{"a", int32(1)}, {"b", int32(2)}}}}), &outer{}, true},
+			{"inline map", docToBytes(D{{"item", "canvas"}, {"qty", 4}}), &withInlineMap{}, false},
+			{"map", docToBytes(D{{"item", "canvas"}, {"qty", 4}}), &M{}, false},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				dec, err := NewDecoder(bsonrw.NewBSONDocumentReader(tc.data))
+				noerr(t, err)
+				dec.DisallowUnknownFields()
+				err = dec.Decode(tc.val)
+				if tc.wantErr && err == nil {
+					t.Errorf("Expected an error decoding an unknown field, got <nil>")
+				}
+				if !tc.wantErr {
+					noerr(t, err)
+				}
+			})
+		}
+	})
 	t.Run("Reset", func(t *testing.T) {
 		vr1, vr2 := bsonrw.NewBSONDocumentReader([]byte{}), bsonrw.NewBSONDocumentReader([]byte{})
 		dc := bsoncodec.DecodeContext{Registry: DefaultRegistry}
@@ -86,6 +86,12 @@ func (e *Encoder) Reset(vw bsonrw.ValueWriter) error {
 	return nil
 }
 
+// SortMapKeys causes the Encoder to encode the keys of Go maps in sorted order, so that encoding the
+// same value always produces the same bytes.
+func (e *Encoder) SortMapKeys() {
+	e.ec.SortMapKeys = true
+}
+
 // SetRegistry replaces the current registry of the encoder with r.
 func (e *Encoder) SetRegistry(r *bsoncodec.Registry) error {
 	e.ec.Registry = r
@@ -119,6 +119,33 @@ func TestEncoderEncode(t *testing.T) {
 	})
 }
 
+func TestEncoderSortMapKeys(t *testing.T) {
+	val := struct {
+		M     map[string]int32
+		Items []M
+	}{
+		M:     map[string]int32{"d": 4, "b": 2, "a": 1, "c": 3, "e": 5},
+		Items: []M{{"z": "last", "y": M{"q": int32(2), "p": int32(1)}}},
+	}
+	want := docToBytes(D{
+		{"m", D{{"a", int32(1)}, {"b", int32(2)}, {"c", int32(3)}, {"d", int32(4)}, {"e", int32(5)}}},
+		{"items", A{D{{"y", D{{"p", int32(1)}, {"q", int32(2)}}}, {"z", "last"}}}},
+	})
+
+	for i := 0; i < 10; i++ {
+		got := make(bsonrw.SliceWriter, 0, 1024)
+		vw, err := bsonrw.NewBSONValueWriter(&got)
+		noerr(t, err)
+		enc, err := NewEncoder(vw)
+		noerr(t, err)
+		enc.SortMapKeys()
+		noerr(t, enc.Encode(val))
+		if !bytes.Equal(got, want) {
+			t.Fatalf("Expected map keys to be sorted. got %v; want %v", Raw(got), Raw(want))
+		}
+	}
+}
+
 type testMarshaler struct {
 	buf []byte
 	err error
@@ -0,0 +1,46 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// defaultIndent is the indentation used by Stringify.
+const defaultIndent = "  "
+
+// Indent appends to dst an indented form of the BSON document doc rendered as canonical extended
+// JSON. Canonical extended JSON annotates every value whose type cannot be inferred from plain JSON
+// (e.g. {"$numberInt": "1"}), which makes the output suitable for diagnostics where the exact BSON
+// types matter. Each element begins on a new line beginning with prefix followed by one or more
+// copies of indent according to the nesting depth.
+func Indent(dst *bytes.Buffer, doc Raw, prefix, indent string) error {
+	if err := doc.Validate(); err != nil {
+		return err
+	}
+
+	ejson, err := MarshalExtJSON(doc, true, false)
+	if err != nil {
+		return err
+	}
+
+	return json.Indent(dst, ejson, prefix, indent)
+}
+
+// Stringify returns an indented canonical extended JSON representation of the BSON document doc.
+// It is intended for log statements and test failure messages, so it never fails: if doc cannot be
+// rendered, the returned string describes the error instead.
+func Stringify(doc Raw) string {
+	var buf bytes.Buffer
+	if err := Indent(&buf, doc, "", defaultIndent); err != nil {
+		return fmt.Sprintf("<invalid BSON document: %v>", err)
+	}
+
+	return buf.String()
+}
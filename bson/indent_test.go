@@ -0,0 +1,38 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package bson
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndent(t *testing.T) {
+	doc, err := Marshal(D{{"a", int32(1)}, {"b", D{{"c", "hello"}}}})
+	require.NoError(t, err)
+
+	t.Run("renders canonical extended JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Indent(&buf, doc, ">", "\t"))
+
+		expected := "{\n>\t\"a\": {\n>\t\t\"$numberInt\": \"1\"\n>\t},\n>\t\"b\": {\n>\t\t\"c\": \"hello\"\n>\t}\n>}"
+		require.Equal(t, expected, buf.String())
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Error(t, Indent(&buf, Raw{0x05, 0x00}, "", "  "))
+	})
+
+	t.Run("Stringify", func(t *testing.T) {
+		require.Equal(t, "{\n  \"a\": {\n    \"$numberInt\": \"1\"\n  },\n  \"b\": {\n    \"c\": \"hello\"\n  }\n}", Stringify(doc))
+		require.True(t, strings.HasPrefix(Stringify(Raw{0x05, 0x00}), "<invalid BSON document:"))
+	})
+}
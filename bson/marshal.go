@@ -7,6 +7,10 @@
 package bson
 
 import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
@@ -95,11 +99,40 @@ func MarshalAppendWithContext(ec bsoncodec.EncodeContext, dst []byte, val interf
 	return *sw, nil
 }
 
+// EstimateSize returns the length of the BSON encoding of val without marshaling it, so that the
+// size of a document can be checked against the 16MiB limit of the server cheaply before it is
+// sent. See bsoncodec.EstimateSize for how the size is computed.
+func EstimateSize(val interface{}) (int, error) {
+	return EstimateSizeWithRegistry(DefaultRegistry, val)
+}
+
+// EstimateSizeWithRegistry returns the length of the BSON encoding of val using Registry r without
+// marshaling it.
+func EstimateSizeWithRegistry(r *bsoncodec.Registry, val interface{}) (int, error) {
+	return bsoncodec.EstimateSize(bsoncodec.EncodeContext{Registry: r}, reflect.ValueOf(val))
+}
+
 // MarshalExtJSON returns the extended JSON encoding of val.
 func MarshalExtJSON(val interface{}, canonical, escapeHTML bool) ([]byte, error) {
 	return MarshalExtJSONWithRegistry(DefaultRegistry, val, canonical, escapeHTML)
 }
 
+// MarshalExtJSONIndent returns the extended JSON encoding of val with each JSON element on a new
+// line beginning with prefix followed by one or more copies of indent according to the nesting
+// depth. This is useful for writing human readable fixtures.
+func MarshalExtJSONIndent(val interface{}, canonical, escapeHTML bool, prefix, indent string) ([]byte, error) {
+	ejson, err := MarshalExtJSON(val, canonical, escapeHTML)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err = json.Indent(&buf, ejson, prefix, indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // MarshalExtJSONAppend will append the extended JSON encoding of val to dst.
 // If dst is not large enough to hold the extended JSON encoding of val, dst
 // will be grown.
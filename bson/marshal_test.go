@@ -9,6 +9,7 @@ package bson
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/require"
@@ -149,6 +150,24 @@ func TestMarshalExtJSONWithContext(t *testing.T) {
 	})
 }
 
+func TestMarshalExtJSONIndent(t *testing.T) {
+	val := D{{"foo", int32(1)}, {"bar", A{"baz"}}}
+
+	got, err := MarshalExtJSONIndent(val, true, false, "", "  ")
+	noerr(t, err)
+	want := "{\n  \"foo\": {\n    \"$numberInt\": \"1\"\n  },\n  \"bar\": [\n    \"baz\"\n  ]\n}"
+	if string(got) != want {
+		t.Errorf("Canonical output is not equal. got\n%s\nwant\n%s", got, want)
+	}
+
+	got, err = MarshalExtJSONIndent(val, false, false, "", "\t")
+	noerr(t, err)
+	want = "{\n\t\"foo\": 1,\n\t\"bar\": [\n\t\t\"baz\"\n\t]\n}"
+	if string(got) != want {
+		t.Errorf("Relaxed output is not equal. got\n%s\nwant\n%s", got, want)
+	}
+}
+
 func TestMarshal_roundtripFromBytes(t *testing.T) {
 	before := []byte{
 		// length
@@ -207,3 +226,161 @@ func TestMarshal_roundtripFromDoc(t *testing.T) {
 		t.Errorf("Documents to not match. got %v; want %v", after, before)
 	}
 }
+
+func TestMarshal_roundtripFromStruct(t *testing.T) {
+	type address struct {
+		City string `bson:"city"`
+	}
+	type Meta struct {
+		Version int32 `bson:"version"`
+	}
+	type person struct {
+		Meta     `bson:",inline"`
+		Name     string                 `bson:"name"`
+		Nickname string                 `bson:"nickname,omitempty"`
+		Born     time.Time              `bson:"born"`
+		Address  *address               `bson:"address"`
+		Tags     []string               `bson:"tags"`
+		Scores   map[string]int64       `bson:"scores"`
+		Extra    interface{}            `bson:"extra"`
+		Rest     map[string]interface{} `bson:",inline"`
+	}
+
+	born := time.Date(1990, time.May, 4, 12, 30, 0, 0, time.UTC)
+	before := person{
+		Meta:    Meta{Version: 2},
+		Name:    "ada",
+		Born:    born,
+		Address: &address{City: "London"},
+		Tags:    []string{"a", "b"},
+		Scores:  map[string]int64{"math": 10},
+		Extra:   D{{"x", int32(1)}},
+		Rest:    map[string]interface{}{"other": "value"},
+	}
+
+	b, err := Marshal(before)
+	require.NoError(t, err)
+	_, err = Raw(b).LookupErr("nickname")
+	require.Error(t, err, "empty field tagged omitempty should not be encoded")
+	require.Equal(t, int32(2), Raw(b).Lookup("version").Int32())
+	require.Equal(t, "value", Raw(b).Lookup("other").StringValue())
+
+	var after person
+	require.NoError(t, Unmarshal(b, &after))
+	require.True(t, born.Equal(after.Born))
+	after.Born = born
+	require.Equal(t, before, after)
+
+	var m M
+	require.NoError(t, Unmarshal(b, &m))
+	require.Equal(t, M{"city": "London"}, m["address"])
+	require.Equal(t, A{"a", "b"}, m["tags"])
+}
+
+func TestMarshal_jsonStructTags(t *testing.T) {
+	type model struct {
+		ID      string `json:"id"`
+		Name    string `bson:"full_name" json:"name"`
+		Email   string `json:"email,omitempty"`
+		Secret  string `json:"-"`
+		Created int64
+	}
+	reg := NewRegistryBuilder().RegisterStructTagParser(bsoncodec.JSONFallbackStructTagParser).Build()
+
+	got, err := MarshalWithRegistry(reg, model{ID: "a", Name: "b", Secret: "c", Created: 1})
+	require.NoError(t, err)
+	want, err := Marshal(D{{"id", "a"}, {"full_name", "b"}, {"created", int64(1)}})
+	require.NoError(t, err)
+	require.Equal(t, Raw(want), Raw(got))
+
+	var m model
+	require.NoError(t, UnmarshalWithRegistry(reg, got, &m))
+	require.Equal(t, model{ID: "a", Name: "b", Created: 1}, m)
+}
+
+type sizeMarshaler struct{}
+
+func (sizeMarshaler) MarshalBSON() ([]byte, error) {
+	return Marshal(D{{"marshaled", true}})
+}
+
+func TestEstimateSize(t *testing.T) {
+	type address struct {
+		City string
+		Zip  *string `bson:",omitempty"`
+	}
+	type embedded struct {
+		Region string
+	}
+	type payload struct {
+		ID       primitive.ObjectID `bson:"_id"`
+		Name     string
+		Age      int
+		Count    int64 `bson:",minsize"`
+		Big      uint64
+		Score    float64
+		Active   bool
+		Born     time.Time
+		Data     []byte
+		Legacy   primitive.Binary
+		Tags     []string
+		Scores   [2]int32
+		Address  *address
+		NoAddr   *address
+		Any      interface{}
+		Doc      D
+		Meta     M
+		Raw      Raw
+		Custom   sizeMarshaler
+		Regex    primitive.Regex
+		Decimal  primitive.Decimal128
+		Empty    string `bson:",omitempty"`
+		embedded `bson:",inline"`
+		Extra    map[string]interface{} `bson:",inline"`
+	}
+
+	raw, err := Marshal(D{{"x", int32(1)}})
+	require.NoError(t, err)
+	zip := "12345"
+	values := map[string]interface{}{
+		"empty struct": struct{}{},
+		"D":            D{{"a", "b"}, {"n", nil}, {"arr", A{1, "two", 3.0}}},
+		"map":          M{"k": M{"nested": []interface{}{true, int64(1 << 40)}}},
+		"struct": &payload{
+			ID:      primitive.NewObjectID(),
+			Name:    "name",
+			Age:     1 << 33,
+			Count:   7,
+			Big:     1,
+			Score:   1.5,
+			Born:    time.Now(),
+			Data:    []byte{1, 2, 3},
+			Legacy:  primitive.Binary{Subtype: 0x02, Data: []byte{1, 2}},
+			Tags:    []string{"a", "bc"},
+			Address: &address{City: "London", Zip: &zip},
+			Any:     M{"a": 1},
+			Doc:     D{{"d", int32(1)}},
+			Meta:    M{"m": "v"},
+			Raw:     raw,
+			Regex:   primitive.Regex{Pattern: "^a", Options: "i"},
+			Extra:   map[string]interface{}{"extra": "value"},
+			embedded: embedded{
+				Region: "eu",
+			},
+		},
+	}
+	for name, val := range values {
+		t.Run(name, func(t *testing.T) {
+			b, err := Marshal(val)
+			require.NoError(t, err)
+			size, err := EstimateSize(val)
+			require.NoError(t, err)
+			require.Equal(t, len(b), size)
+		})
+	}
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := EstimateSize(M{"c": make(chan int)})
+		require.Error(t, err)
+	})
+}
@@ -10,11 +10,33 @@
 package primitive
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 )
 
+// These constants are the minimum and maximum exponents of a Decimal128.
+const (
+	MinDecimal128Exp = -6176
+	MaxDecimal128Exp = 6111
+)
+
+// These errors are returned by Decimal128.BigInt for values that do not have a finite significand.
+var (
+	ErrDecimal128NaN    = errors.New("decimal128 value is NaN")
+	ErrDecimal128Inf    = errors.New("decimal128 value is Infinity")
+	ErrDecimal128NegInf = errors.New("decimal128 value is -Infinity")
+)
+
+var (
+	bigTen             = big.NewInt(10)
+	maxDecimal128Coeff = new(big.Int).Sub(new(big.Int).Exp(bigTen, big.NewInt(34), nil), big.NewInt(1))
+	bigWordMask        = new(big.Int).SetUint64(^uint64(0))
+)
+
 // Decimal128 holds decimal128 BSON values.
 type Decimal128 struct {
 	h, l uint64
@@ -114,6 +136,124 @@ Loop:
 	return string(repr[last+pos:])
 }
 
+// IsNaN returns whether d is NaN.
+func (d Decimal128) IsNaN() bool {
+	return d.h>>58&(1<<5-1) == 0x1F
+}
+
+// IsInf returns +1 if d is Infinity, -1 if d is -Infinity, and 0 otherwise.
+func (d Decimal128) IsInf() int {
+	if d.h>>58&(1<<5-1) != 0x1E {
+		return 0
+	}
+	if d.h>>63&1 == 0 {
+		return 1
+	}
+	return -1
+}
+
+// BigInt returns the significand and the exponent of d, so that d equals significand * 10^exponent.
+// An error is returned if d is NaN or infinite. The sign of a negative zero is not preserved.
+func (d Decimal128) BigInt() (*big.Int, int, error) {
+	switch {
+	case d.IsNaN():
+		return nil, 0, ErrDecimal128NaN
+	case d.IsInf() > 0:
+		return nil, 0, ErrDecimal128Inf
+	case d.IsInf() < 0:
+		return nil, 0, ErrDecimal128NegInf
+	}
+
+	var e int
+	var h, l uint64
+	if d.h>>61&3 == 3 {
+		// Bits: 1*sign 2*ignored 14*exponent 111*significand.
+		// Spec says all of these values are out of range, so the significand is zero.
+		e = int(d.h>>47&(1<<14-1)) + MinDecimal128Exp
+	} else {
+		// Bits: 1*sign 14*exponent 113*significand
+		e = int(d.h>>49&(1<<14-1)) + MinDecimal128Exp
+		h, l = d.h&(1<<49-1), d.l
+	}
+
+	bi := new(big.Int).SetUint64(h)
+	bi.Lsh(bi, 64)
+	bi.Or(bi, new(big.Int).SetUint64(l))
+	if d.h>>63&1 == 1 {
+		bi.Neg(bi)
+	}
+	return bi, e, nil
+}
+
+// ParseDecimal128FromBigInt returns the Decimal128 equal to bi * 10^exp. The second return value is
+// false if the value cannot be represented exactly as a Decimal128.
+func ParseDecimal128FromBigInt(bi *big.Int, exp int) (Decimal128, bool) {
+	neg := bi.Sign() < 0
+	coeff := new(big.Int).Abs(bi)
+	q, r := new(big.Int), new(big.Int)
+
+	// Drop trailing zeros until the significand fits, raising the exponent to compensate.
+	for coeff.Cmp(maxDecimal128Coeff) > 0 || exp < MinDecimal128Exp {
+		if coeff.Sign() == 0 {
+			exp = MinDecimal128Exp
+			break
+		}
+		q.QuoRem(coeff, bigTen, r)
+		if r.Sign() != 0 {
+			return Decimal128{}, false
+		}
+		coeff, q = q, coeff
+		exp++
+	}
+	// Clamp large exponents by adding trailing zeros to the significand.
+	for exp > MaxDecimal128Exp {
+		coeff.Mul(coeff, bigTen)
+		if coeff.Cmp(maxDecimal128Coeff) > 0 {
+			return Decimal128{}, false
+		}
+		exp--
+	}
+
+	l := new(big.Int).And(coeff, bigWordMask).Uint64()
+	h := new(big.Int).Rsh(coeff, 64).Uint64()
+	h |= uint64(exp-MinDecimal128Exp) & uint64(1<<14-1) << 49
+	if neg {
+		h |= 1 << 63
+	}
+	return Decimal128{h: h, l: l}, true
+}
+
+// MarshalJSON returns the decimal as a JSON string, which preserves its exact value.
+func (d Decimal128) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON parses a JSON string, or an extended JSON object of the form
+// {"$numberDecimal": "..."}, into the decimal. A JSON null leaves the decimal unchanged.
+func (d *Decimal128) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		var ext struct {
+			NumberDecimal *string `json:"$numberDecimal"`
+		}
+		if err = json.Unmarshal(b, &ext); err != nil || ext.NumberDecimal == nil {
+			return fmt.Errorf("cannot unmarshal %s into a decimal128", b)
+		}
+		str = *ext.NumberDecimal
+	}
+
+	dec, err := ParseDecimal128(str)
+	if err != nil {
+		return err
+	}
+	*d = dec
+	return nil
+}
+
 func divmod(h, l uint64, div uint32) (qh, ql uint64, rem uint32) {
 	div64 := uint64(div)
 	a := h >> 32
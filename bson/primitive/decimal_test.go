@@ -0,0 +1,105 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package primitive
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimal128BigInt(t *testing.T) {
+	testCases := []struct {
+		s     string
+		coeff string
+		exp   int
+	}{
+		{"0", "0", 0},
+		{"1.23", "123", -2},
+		{"-1.23E+10", "-123", 8},
+		{"9999999999999999999999999999999999", "9999999999999999999999999999999999", 0},
+		{"1E-6176", "1", -6176},
+		{"1E+6111", "1", 6111},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.s, func(t *testing.T) {
+			d, err := ParseDecimal128(tc.s)
+			require.NoError(t, err)
+
+			bi, exp, err := d.BigInt()
+			require.NoError(t, err)
+			require.Equal(t, tc.coeff, bi.String())
+			require.Equal(t, tc.exp, exp)
+
+			got, ok := ParseDecimal128FromBigInt(bi, exp)
+			require.True(t, ok)
+			require.Equal(t, d.String(), got.String())
+		})
+	}
+
+	for s, want := range map[string]error{"NaN": ErrDecimal128NaN, "Inf": ErrDecimal128Inf, "-Inf": ErrDecimal128NegInf} {
+		d, err := ParseDecimal128(s)
+		require.NoError(t, err)
+		_, _, err = d.BigInt()
+		require.Equal(t, want, err)
+	}
+}
+
+func TestParseDecimal128FromBigInt(t *testing.T) {
+	t.Run("removes trailing zeros", func(t *testing.T) {
+		bi, _ := new(big.Int).SetString("12300000000000000000000000000000000000", 10)
+		d, ok := ParseDecimal128FromBigInt(bi, -6180)
+		require.True(t, ok)
+		require.Equal(t, "1.230000000000000000000000000000000E-6143", d.String())
+	})
+	t.Run("clamps large exponents", func(t *testing.T) {
+		d, ok := ParseDecimal128FromBigInt(big.NewInt(1), 6112)
+		require.True(t, ok)
+		require.Equal(t, "1.0E+6112", d.String())
+	})
+	t.Run("inexact", func(t *testing.T) {
+		bi, _ := new(big.Int).SetString("12345678901234567890123456789012345", 10)
+		_, ok := ParseDecimal128FromBigInt(bi, 0)
+		require.False(t, ok)
+		_, ok = ParseDecimal128FromBigInt(big.NewInt(3), -6177)
+		require.False(t, ok)
+	})
+}
+
+func TestDecimal128JSON(t *testing.T) {
+	d, err := ParseDecimal128("-12.50")
+	require.NoError(t, err)
+
+	b, err := json.Marshal(struct{ Price Decimal128 }{d})
+	require.NoError(t, err)
+	require.Equal(t, `{"Price":"-12.50"}`, string(b))
+
+	var got struct{ Price Decimal128 }
+	require.NoError(t, json.Unmarshal(b, &got))
+	require.Equal(t, d, got.Price)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"Price":{"$numberDecimal":"1E+3"}}`), &got))
+	require.Equal(t, "1E+3", got.Price.String())
+
+	require.Error(t, json.Unmarshal([]byte(`{"Price":1.5}`), &got))
+	require.Error(t, json.Unmarshal([]byte(`{"Price":"abc"}`), &got))
+}
+
+func TestDecimal128Special(t *testing.T) {
+	nan, _ := ParseDecimal128("NaN")
+	inf, _ := ParseDecimal128("Infinity")
+	negInf, _ := ParseDecimal128("-Infinity")
+	one, _ := ParseDecimal128("1")
+
+	require.True(t, nan.IsNaN())
+	require.False(t, one.IsNaN())
+	require.Equal(t, 1, inf.IsInf())
+	require.Equal(t, -1, negInf.IsInf())
+	require.Equal(t, 0, one.IsInf())
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -34,6 +35,70 @@ var NilObjectID ObjectID
 var objectIDCounter = readRandomUint32()
 var processUnique = processUniqueBytes()
 
+// ObjectIDGenerator generates ObjectIDs. A custom generator can be installed with
+// SetObjectIDGenerator to control the ObjectIDs created by NewObjectID and
+// NewObjectIDFromTimestamp.
+type ObjectIDGenerator interface {
+	NewObjectIDFromTimestamp(timestamp time.Time) ObjectID
+}
+
+// generatorHolder wraps the installed ObjectIDGenerator so that it can be stored in an
+// atomic.Value, which requires every stored value to have the same concrete type.
+type generatorHolder struct {
+	ObjectIDGenerator
+}
+
+var objectIDGenerator atomic.Value
+
+// objectIDGeneratorLock serializes calls to SetObjectIDGenerator so that each returns the generator
+// it replaced.
+var objectIDGeneratorLock sync.Mutex
+
+// SetObjectIDGenerator installs g as the generator used by NewObjectID and NewObjectIDFromTimestamp
+// and returns the previously installed generator. Passing nil restores the default generator,
+// which uses a random process unique value and counter seed chosen when the program starts.
+func SetObjectIDGenerator(g ObjectIDGenerator) ObjectIDGenerator {
+	objectIDGeneratorLock.Lock()
+	defer objectIDGeneratorLock.Unlock()
+
+	var prev ObjectIDGenerator = defaultObjectIDGenerator{}
+	if h, ok := objectIDGenerator.Load().(generatorHolder); ok && h.ObjectIDGenerator != nil {
+		prev = h.ObjectIDGenerator
+	}
+	objectIDGenerator.Store(generatorHolder{g})
+	return prev
+}
+
+// ObjectIDSource is an ObjectIDGenerator with a fixed process unique value and counter seed. It can
+// be used to derive ObjectIDs from a stable identity, such as the name of the host or container,
+// or to create deterministic ObjectIDs in tests.
+type ObjectIDSource struct {
+	processUnique [5]byte
+	counter       uint32
+}
+
+// NewObjectIDSource creates an ObjectIDSource that uses processUnique as bytes 4 through 8 of each
+// ObjectID. The counter starts at counter and is incremented before each ObjectID is generated, so
+// the first ObjectID has a counter value of counter+1. Only the low three bytes of the counter are
+// used.
+func NewObjectIDSource(processUnique [5]byte, counter uint32) *ObjectIDSource {
+	return &ObjectIDSource{processUnique: processUnique, counter: counter}
+}
+
+// NewObjectIDFromTimestamp generates a new ObjectID based on the given time. It is safe for
+// concurrent use.
+func (s *ObjectIDSource) NewObjectIDFromTimestamp(timestamp time.Time) ObjectID {
+	return newObjectID(timestamp, s.processUnique, atomic.AddUint32(&s.counter, 1))
+}
+
+// defaultObjectIDGenerator generates ObjectIDs from the package level process unique value and
+// counter.
+type defaultObjectIDGenerator struct{}
+
+func (defaultObjectIDGenerator) NewObjectIDFromTimestamp(timestamp time.Time) ObjectID {
+	return newObjectID(timestamp, processUnique, atomic.AddUint32(&objectIDCounter, 1))
+}
+
 // NewObjectID generates a new ObjectID.
 func NewObjectID() ObjectID {
 	return NewObjectIDFromTimestamp(time.Now())
@@ -41,11 +106,18 @@ func NewObjectID() ObjectID {
 
 // NewObjectIDFromTimestamp generates a new ObjectID based on the given time.
 func NewObjectIDFromTimestamp(timestamp time.Time) ObjectID {
+	if h, ok := objectIDGenerator.Load().(generatorHolder); ok && h.ObjectIDGenerator != nil {
+		return h.NewObjectIDFromTimestamp(timestamp)
+	}
+	return defaultObjectIDGenerator{}.NewObjectIDFromTimestamp(timestamp)
+}
+
+func newObjectID(timestamp time.Time, processUnique [5]byte, counter uint32) ObjectID {
 	var b [12]byte
 
 	binary.BigEndian.PutUint32(b[0:4], uint32(timestamp.Unix()))
 	copy(b[4:9], processUnique[:])
-	putUint24(b[9:12], atomic.AddUint32(&objectIDCounter, 1))
+	putUint24(b[9:12], counter)
 
 	return b
 }
@@ -88,6 +160,23 @@ func ObjectIDFromHex(s string) (ObjectID, error) {
 	return oid, nil
 }
 
+// MarshalText returns the hex encoding of the ObjectID. It allows an ObjectID to be used as a
+// map key when encoding JSON and with other packages that use encoding.TextMarshaler.
+func (id ObjectID) MarshalText() ([]byte, error) {
+	return []byte(id.Hex()), nil
+}
+
+// UnmarshalText populates the ObjectID from its hex encoding. It returns an error if the text is
+// not a valid ObjectID.
+func (id *ObjectID) UnmarshalText(b []byte) error {
+	oid, err := ObjectIDFromHex(string(b))
+	if err != nil {
+		return err
+	}
+	*id = oid
+	return nil
+}
+
 // MarshalJSON returns the ObjectID as a string
 func (id ObjectID) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id.Hex())
@@ -11,6 +11,8 @@ import (
 
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
+	"sync"
 	"time"
 
 	"github.com/stretchr/testify/require"
@@ -148,3 +150,82 @@ func TestCounterOverflow(t *testing.T) {
 	NewObjectID()
 	require.Equal(t, uint32(0), objectIDCounter)
 }
+
+func TestObjectIDSource(t *testing.T) {
+	src := NewObjectIDSource([5]byte{1, 2, 3, 4, 5}, 0x00FFFFFE)
+	ts := time.Unix(0x5c000000, 0)
+
+	require.Equal(t, "5c0000000102030405ffffff", src.NewObjectIDFromTimestamp(ts).Hex())
+	require.Equal(t, "5c0000000102030405000000", src.NewObjectIDFromTimestamp(ts).Hex())
+}
+
+func TestSetObjectIDGenerator(t *testing.T) {
+	prev := SetObjectIDGenerator(NewObjectIDSource([5]byte{0xa, 0xb, 0xc, 0xd, 0xe}, 0))
+	defer SetObjectIDGenerator(prev)
+
+	id := NewObjectIDFromTimestamp(time.Unix(0x5c000000, 0))
+	require.Equal(t, "5c0000000a0b0c0d0e000001", id.Hex())
+	require.Equal(t, "0a0b0c0d0e000002", NewObjectID().Hex()[8:])
+
+	// nil restores the default generator
+	SetObjectIDGenerator(nil)
+	id = NewObjectID()
+	require.Equal(t, processUnique[:], id[4:9])
+}
+
+func TestSetObjectIDGeneratorConcurrent(t *testing.T) {
+	const n = 50
+	initial := SetObjectIDGenerator(nil)
+
+	sources := make([]ObjectIDGenerator, n)
+	replaced := make([]ObjectIDGenerator, n)
+	var wg sync.WaitGroup
+	for i := range sources {
+		sources[i] = NewObjectIDSource([5]byte{byte(i)}, 0)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			replaced[i] = SetObjectIDGenerator(sources[i])
+		}(i)
+	}
+	wg.Wait()
+	last := SetObjectIDGenerator(initial)
+
+	// each generator must be returned exactly once, either by the call that replaced it or by the
+	// final call.
+	seen := make(map[ObjectIDGenerator]int)
+	for _, g := range append(replaced, last) {
+		seen[g]++
+	}
+	require.Len(t, seen, n+1)
+	require.Equal(t, 1, seen[defaultObjectIDGenerator{}])
+	for _, src := range sources {
+		require.Equal(t, 1, seen[src])
+	}
+}
+
+func TestObjectIDText(t *testing.T) {
+	id := NewObjectID()
+
+	text, err := id.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, id.Hex(), string(text))
+
+	var got ObjectID
+	require.NoError(t, got.UnmarshalText(text))
+	require.Equal(t, id, got)
+
+	require.Equal(t, ErrInvalidHex, got.UnmarshalText([]byte("5ef7fdd91c19e3222b41b8")))
+	require.Error(t, got.UnmarshalText([]byte("not hex")))
+
+	t.Run("json map key", func(t *testing.T) {
+		m := map[ObjectID]int{id: 1}
+		b, err := json.Marshal(m)
+		require.NoError(t, err)
+		require.Equal(t, `{"`+id.Hex()+`":1}`, string(b))
+
+		var decoded map[ObjectID]int
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		require.Equal(t, m, decoded)
+	})
+}
@@ -285,3 +285,24 @@ func (rv RawValue) Decimal128() primitive.Decimal128 { return convertToCoreValue
 func (rv RawValue) Decimal128OK() (primitive.Decimal128, bool) {
 	return convertToCoreValue(rv).Decimal128OK()
 }
+
+// AsInt32 returns a BSON number as an int32, converting doubles and int64s. It panics if the
+// value is not a double, int32, or int64.
+func (rv RawValue) AsInt32() int32 { return convertToCoreValue(rv).AsInt32() }
+
+// AsInt32OK is the same as AsInt32, except that it returns a boolean instead of panicking.
+func (rv RawValue) AsInt32OK() (int32, bool) { return convertToCoreValue(rv).AsInt32OK() }
+
+// AsInt64 returns a BSON number as an int64, converting doubles and int32s. It panics if the
+// value is not a double, int32, or int64.
+func (rv RawValue) AsInt64() int64 { return convertToCoreValue(rv).AsInt64() }
+
+// AsInt64OK is the same as AsInt64, except that it returns a boolean instead of panicking.
+func (rv RawValue) AsInt64OK() (int64, bool) { return convertToCoreValue(rv).AsInt64OK() }
+
+// AsFloat64 returns a BSON number as a float64, converting int32s and int64s. It panics if the
+// value is not a double, int32, or int64.
+func (rv RawValue) AsFloat64() float64 { return convertToCoreValue(rv).AsFloat64() }
+
+// AsFloat64OK is the same as AsFloat64, except that it returns a boolean instead of panicking.
+func (rv RawValue) AsFloat64OK() (float64, bool) { return convertToCoreValue(rv).AsFloat64OK() }
@@ -41,16 +41,14 @@ func Unmarshal(data []byte, val interface{}) error {
 // stores the result in the value pointed to by val. If val is nil or not
 // a pointer, UnmarshalWithRegistry returns InvalidUnmarshalError.
 func UnmarshalWithRegistry(r *bsoncodec.Registry, data []byte, val interface{}) error {
-	vr := bsonrw.NewBSONDocumentReader(data)
-	return unmarshalFromReader(bsoncodec.DecodeContext{Registry: r}, vr, val)
+	return unmarshalBSON(bsoncodec.DecodeContext{Registry: r}, data, val)
 }
 
 // UnmarshalWithContext parses the BSON-encoded data using DecodeContext dc and
 // stores the result in the value pointed to by val. If val is nil or not
 // a pointer, UnmarshalWithRegistry returns InvalidUnmarshalError.
 func UnmarshalWithContext(dc bsoncodec.DecodeContext, data []byte, val interface{}) error {
-	vr := bsonrw.NewBSONDocumentReader(data)
-	return unmarshalFromReader(dc, vr, val)
+	return unmarshalBSON(dc, data, val)
 }
 
 // UnmarshalExtJSON parses the extended JSON-encoded data and stores the result
@@ -84,9 +82,21 @@ func UnmarshalExtJSONWithContext(dc bsoncodec.DecodeContext, data []byte, canoni
 	return unmarshalFromReader(dc, ejvr, val)
 }
 
+// unmarshalBSON decodes data into val with a ValueReader from bvrPool, so that unmarshaling a
+// document does not allocate a reader.
+func unmarshalBSON(dc bsoncodec.DecodeContext, data []byte, val interface{}) error {
+	vr := bvrPool.Get(data)
+	defer bvrPool.Put(vr)
+	return unmarshalFromReader(dc, vr, val)
+}
+
 func unmarshalFromReader(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, val interface{}) error {
 	dec := decPool.Get().(*Decoder)
-	defer decPool.Put(dec)
+	defer func() {
+		// The reader is not kept in the pool with the decoder, because it may be pooled too.
+		dec.vr = nil
+		decPool.Put(dec)
+	}()
 
 	err := dec.Reset(vr)
 	if err != nil {
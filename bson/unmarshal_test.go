@@ -8,11 +8,15 @@ package bson
 
 import (
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestUnmarshal(t *testing.T) {
@@ -106,3 +110,86 @@ func TestUnmarshalExtJSONWithContext(t *testing.T) {
 		}
 	})
 }
+
+func TestUnmarshalWithCustomRegistry(t *testing.T) {
+	type upper string
+	tUpper := reflect.TypeOf(upper(""))
+
+	reg := NewRegistryBuilder().
+		RegisterDecoder(tTime, bsoncodec.ValueDecoderFunc(bsoncodec.DefaultValueDecoders{}.LocalTimeDecodeValue)).
+		RegisterDecoder(tUpper, bsoncodec.ValueDecoderFunc(func(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+			s, err := vr.ReadString()
+			if err != nil {
+				return err
+			}
+			val.SetString(strings.ToUpper(s))
+			return nil
+		})).
+		RegisterTypeMapEntry(bsontype.EmbeddedDocument, reflect.TypeOf(M{})).
+		Build()
+
+	now := time.Now().Truncate(time.Millisecond)
+	data := docToBytes(D{
+		{"when", primitive.NewDateTimeFromTime(now)},
+		{"name", "ada"},
+		{"extra", D{{"nested", D{{"a", int32(1)}}}}},
+	})
+
+	var got struct {
+		When  time.Time
+		Name  upper
+		Extra interface{}
+	}
+	noerr(t, UnmarshalWithRegistry(reg, data, &got))
+	if got.When.Location() != time.Local || !got.When.Equal(now) {
+		t.Errorf("Expected %v in the local time zone, got %v", now, got.When)
+	}
+	if got.Name != "ADA" {
+		t.Errorf("Expected the custom decoder to be used, got %q", got.Name)
+	}
+	if want := (M{"nested": M{"a": int32(1)}}); !cmp.Equal(got.Extra, want) {
+		t.Errorf("Expected embedded documents to decode into M. got %v; want %v", got.Extra, want)
+	}
+
+	noerr(t, Unmarshal(data, &got))
+	if got.When.Location() != time.UTC {
+		t.Errorf("Expected the default registry to decode times in UTC, got %v", got.When.Location())
+	}
+}
+
+type defaultedSettings struct {
+	Name    string
+	Retries int32
+	Limits  *defaultedLimits
+}
+
+func (s *defaultedSettings) SetDefaults() {
+	if s.Retries == 0 {
+		s.Retries = 3
+	}
+}
+
+type defaultedLimits struct {
+	Max int32
+}
+
+func (l *defaultedLimits) SetDefaults() {
+	if l.Max == 0 {
+		l.Max = 100
+	}
+}
+
+func TestUnmarshalDefaulter(t *testing.T) {
+	var got defaultedSettings
+	noerr(t, Unmarshal(docToBytes(D{{"name", "ada"}, {"limits", D{}}}), &got))
+	want := defaultedSettings{Name: "ada", Retries: 3, Limits: &defaultedLimits{Max: 100}}
+	if !cmp.Equal(got, want) {
+		t.Errorf("Expected missing fields to be defaulted. got %+v; want %+v", got, want)
+	}
+
+	got = defaultedSettings{}
+	noerr(t, Unmarshal(docToBytes(D{{"retries", int32(5)}}), &got))
+	if got.Retries != 5 || got.Limits != nil {
+		t.Errorf("Expected decoded fields to be kept, got %+v", got)
+	}
+}
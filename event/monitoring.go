@@ -10,15 +10,19 @@ import (
 	"context"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/description"
 )
 
 // CommandStartedEvent represents an event generated when a command is sent to a server.
 type CommandStartedEvent struct {
-	Command      bson.Raw
-	DatabaseName string
-	CommandName  string
-	RequestID    int64
-	ConnectionID string
+	Command       bson.Raw
+	DatabaseName  string
+	CommandName   string
+	RequestID     int64
+	ConnectionID  string
+	ServerAddress string
 }
 
 // CommandFinishedEvent represents a generic command finishing.
@@ -27,6 +31,7 @@ type CommandFinishedEvent struct {
 	CommandName   string
 	RequestID     int64
 	ConnectionID  string
+	ServerAddress string
 }
 
 // CommandSucceededEvent represents an event generated when a command's execution succeeds.
@@ -47,3 +52,180 @@ type CommandMonitor struct {
 	Succeeded func(context.Context, *CommandSucceededEvent)
 	Failed    func(context.Context, *CommandFailedEvent)
 }
+
+// ServerDescriptionChangedEvent represents a server description change.
+type ServerDescriptionChangedEvent struct {
+	Address             address.Address
+	TopologyID          primitive.ObjectID // A unique identifier for the topology this server is a part of
+	PreviousDescription description.Server
+	NewDescription      description.Server
+}
+
+// ServerOpeningEvent is an event generated when the server is initialized.
+type ServerOpeningEvent struct {
+	Address    address.Address
+	TopologyID primitive.ObjectID // A unique identifier for the topology this server is a part of
+}
+
+// ServerClosedEvent is an event generated when the server is closed.
+type ServerClosedEvent struct {
+	Address    address.Address
+	TopologyID primitive.ObjectID // A unique identifier for the topology this server is a part of
+}
+
+// TopologyDescriptionChangedEvent represents a topology description change.
+type TopologyDescriptionChangedEvent struct {
+	TopologyID          primitive.ObjectID // A unique identifier for the topology
+	PreviousDescription description.Topology
+	NewDescription      description.Topology
+}
+
+// TopologyOpeningEvent is an event generated when the topology is initialized.
+type TopologyOpeningEvent struct {
+	TopologyID primitive.ObjectID // A unique identifier for the topology
+}
+
+// TopologyClosedEvent is an event generated when the topology is closed.
+type TopologyClosedEvent struct {
+	TopologyID primitive.ObjectID // A unique identifier for the topology
+}
+
+// ServerHeartbeatStartedEvent is an event generated when the isMaster command is started.
+type ServerHeartbeatStartedEvent struct {
+	ConnectionID string // The address this heartbeat was sent to with a unique identifier
+	Awaited      bool   // Whether the heartbeat is an awaitable isMaster of the streaming protocol
+}
+
+// ServerHeartbeatSucceededEvent is an event generated when the isMaster succeeds.
+type ServerHeartbeatSucceededEvent struct {
+	DurationNanos int64
+	Reply         description.Server
+	ConnectionID  string // The address this heartbeat was sent to with a unique identifier
+	Awaited       bool   // Whether the heartbeat is an awaitable isMaster of the streaming protocol
+}
+
+// ServerHeartbeatFailedEvent is an event generated when the isMaster fails.
+type ServerHeartbeatFailedEvent struct {
+	DurationNanos int64
+	Failure       error
+	ConnectionID  string // The address this heartbeat was sent to with a unique identifier
+	Awaited       bool   // Whether the heartbeat is an awaitable isMaster of the streaming protocol
+}
+
+// SRVPollFailedEvent is an event generated when polling the SRV records of a mongodb+srv connection
+// string fails, either because the lookup failed or because it returned no valid hosts. The
+// topology keeps the hosts it knows about until the records can be resolved again.
+type SRVPollFailedEvent struct {
+	TopologyID          primitive.ObjectID // A unique identifier for the topology
+	Hosts               []string           // The hosts kept in the topology
+	ConsecutiveFailures int
+	Failure             error
+}
+
+// ServerMonitor represents a monitor that is triggered for different server discovery and
+// monitoring events. Each callback is optional and is called synchronously from the goroutine
+// that monitors the server or topology, so it should not block.
+//
+// TopologyDescriptionChanged is called while the topology holds the lock that orders its updates,
+// which guarantees that the events are delivered in the order the changes happened. The callback
+// therefore must not call methods of the client that change the topology, such as Connect or
+// Disconnect, or it will deadlock.
+type ServerMonitor struct {
+	ServerDescriptionChanged   func(*ServerDescriptionChangedEvent)
+	ServerOpening              func(*ServerOpeningEvent)
+	ServerClosed               func(*ServerClosedEvent)
+	TopologyDescriptionChanged func(*TopologyDescriptionChangedEvent)
+	TopologyOpening            func(*TopologyOpeningEvent)
+	TopologyClosed             func(*TopologyClosedEvent)
+	ServerHeartbeatStarted     func(*ServerHeartbeatStartedEvent)
+	ServerHeartbeatSucceeded   func(*ServerHeartbeatSucceededEvent)
+	ServerHeartbeatFailed      func(*ServerHeartbeatFailedEvent)
+	SRVPollFailed              func(*SRVPollFailedEvent)
+}
+
+// Stages of establishing a connection reported to a ConnectionMonitor, in the order they happen.
+const (
+	StageDNSResolution  = "dnsResolution"
+	StageTCPConnect     = "tcpConnect"
+	StageTLSHandshake   = "tlsHandshake"
+	StageHandshake      = "handshake"
+	StageAuthentication = "authentication"
+)
+
+// ConnectionStageStartedEvent represents an event generated when a stage of establishing a
+// connection starts.
+type ConnectionStageStartedEvent struct {
+	Stage         string
+	ServerAddress string
+	ConnectionID  string
+}
+
+// ConnectionStageFinishedEvent represents an event generated when a stage of establishing a
+// connection finishes. Failure is nil if the stage succeeded.
+type ConnectionStageFinishedEvent struct {
+	Stage         string
+	ServerAddress string
+	ConnectionID  string
+	DurationNanos int64
+	Failure       error
+}
+
+// ConnectionMonitor represents a monitor that is triggered as each stage of establishing a
+// connection starts and finishes, so that the time spent connecting can be attributed to DNS
+// resolution, the TCP connect, the TLS handshake, the MongoDB handshake or authentication. The
+// context passed to the callbacks is the one the connection is established with, and the
+// ConnectionID matches the one of the command events of the connection.
+//
+// DNS resolution is only reported when the driver resolves host names itself, which is not the
+// case when a custom dialer without a DNS resolver is used.
+type ConnectionMonitor struct {
+	StageStarted  func(context.Context, *ConnectionStageStartedEvent)
+	StageFinished func(context.Context, *ConnectionStageFinishedEvent)
+}
+
+// strings for pool command monitoring reasons
+const (
+	ReasonIdle              = "idle"
+	ReasonPoolClosed        = "poolClosed"
+	ReasonPoolFull          = "poolFull"
+	ReasonStale             = "stale"
+	ReasonConnectionErrored = "connectionError"
+	ReasonTimedOut          = "timeout"
+)
+
+// strings for pool command monitoring types
+const (
+	PoolCreated        = "ConnectionPoolCreated"
+	PoolCleared        = "ConnectionPoolCleared"
+	PoolClosedEvent    = "ConnectionPoolClosed"
+	ConnectionCreated  = "ConnectionCreated"
+	ConnectionReady    = "ConnectionReady"
+	ConnectionClosed   = "ConnectionClosed"
+	GetStarted         = "ConnectionCheckOutStarted"
+	GetFailed          = "ConnectionCheckOutFailed"
+	GetSucceeded       = "ConnectionCheckedOut"
+	ConnectionReturned = "ConnectionCheckedIn"
+)
+
+// MonitorPoolOptions contains pool options as formatted in pool events
+type MonitorPoolOptions struct {
+	MaxPoolSize     uint64 `json:"maxPoolSize"`
+	MaxIdlePoolSize uint64 `json:"maxIdlePoolSize"`
+}
+
+// PoolEvent contains all information summarizing a pool event
+type PoolEvent struct {
+	Type         string              `json:"type"`
+	Address      string              `json:"address"`
+	ConnectionID uint64              `json:"connectionId"`
+	PoolOptions  *MonitorPoolOptions `json:"options"`
+	Reason       string              `json:"reason"`
+	// Compressor is the name of the compressor negotiated for the connection. It is only set on
+	// ConnectionReady events, and is empty if messages sent on the connection are not compressed.
+	Compressor string `json:"compressor,omitempty"`
+}
+
+// PoolMonitor is a function that allows the user to gain access to events occurring in the pool
+type PoolMonitor struct {
+	Event func(*PoolEvent)
+}
@@ -0,0 +1,54 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package event
+
+import "context"
+
+// The names of the spans started by the driver, other than the spans of commands, which are named
+// after the command, such as "find" or "insert".
+const (
+	SpanServerSelection    = "mongodb.serverSelection"
+	SpanConnectionCheckOut = "mongodb.connectionCheckOut"
+)
+
+// The keys of the attributes of spans. They follow the OpenTelemetry semantic conventions for
+// database clients.
+const (
+	AttributeDBSystem     = "db.system"             // Always "mongodb".
+	AttributeDBName       = "db.name"               // The database of a command.
+	AttributeDBOperation  = "db.operation"          // The name of a command.
+	AttributeDBCollection = "db.mongodb.collection" // The collection of a command, if it has one.
+	AttributeNetPeerName  = "net.peer.name"         // The host of the server.
+	AttributeNetPeerPort  = "net.peer.port"         // The port of the server, as an int.
+)
+
+// SpanAttribute is a key-value pair that describes a span. Values are strings or ints.
+type SpanAttribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span is a unit of work started by a Tracer. Both methods must be safe to call from a different
+// goroutine than the one that started the span.
+type Span interface {
+	// SetAttributes adds attributes to the span that were not known when it was started, such as
+	// the address of a selected server.
+	SetAttributes(attrs ...SpanAttribute)
+	// End finishes the span with the error the work failed with, or nil. It is called once.
+	End(err error)
+}
+
+// Tracer starts spans around server selection, connection checkout and the commands sent to
+// servers. It can be implemented with an adapter around an OpenTelemetry tracer so that the work of
+// the driver appears in the traces of an application.
+//
+// Start is called with the context of the operation and returns the context of the new span, which
+// is passed to the work done within the span. Spans of commands are started when the command is
+// sent and ended when its reply is read. Start must be safe to call concurrently.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...SpanAttribute) (context.Context, Span)
+}
@@ -0,0 +1,290 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package logger contains the structured logging subsystem of the driver. Log messages are
+// grouped into components, each of which has its own verbosity level, and are emitted as a message
+// with a list of alternating keys and values to a Sink.
+//
+// Logging is configured programmatically through the LoggerOptions of a client or through the
+// following environment variables. Programmatic configuration takes precedence.
+//
+//	MONGODB_LOG_ALL                  The default level of every component.
+//	MONGODB_LOG_COMMAND              The level of the command component.
+//	MONGODB_LOG_TOPOLOGY             The level of the topology component.
+//	MONGODB_LOG_SERVER_SELECTION     The level of the serverSelection component.
+//	MONGODB_LOG_CONNECTION           The level of the connection component.
+//	MONGODB_LOG_PATH                 "stderr", "stdout", or the path of a file to append messages to.
+//	MONGODB_LOG_MAX_DOCUMENT_LENGTH  The length at which logged documents are truncated.
+package logger // import "go.mongodb.org/mongo-driver/logger"
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DefaultMaxDocumentLength is the length at which logged documents are truncated when no maximum
+// is configured.
+const DefaultMaxDocumentLength uint = 1000
+
+// TruncationSuffix is appended to a logged document that was truncated.
+const TruncationSuffix = "..."
+
+// Component is a group of related log messages whose verbosity is configured together.
+type Component int
+
+// These constants are the components of the driver that emit log messages.
+const (
+	ComponentAll Component = iota
+	ComponentCommand
+	ComponentTopology
+	ComponentServerSelection
+	ComponentConnection
+)
+
+var componentEnvVars = map[Component]string{
+	ComponentAll:             "MONGODB_LOG_ALL",
+	ComponentCommand:         "MONGODB_LOG_COMMAND",
+	ComponentTopology:        "MONGODB_LOG_TOPOLOGY",
+	ComponentServerSelection: "MONGODB_LOG_SERVER_SELECTION",
+	ComponentConnection:      "MONGODB_LOG_CONNECTION",
+}
+
+// String implements the fmt.Stringer interface.
+func (c Component) String() string {
+	switch c {
+	case ComponentAll:
+		return "all"
+	case ComponentCommand:
+		return "command"
+	case ComponentTopology:
+		return "topology"
+	case ComponentServerSelection:
+		return "serverSelection"
+	case ComponentConnection:
+		return "connection"
+	}
+	return "unknown"
+}
+
+// Level is the verbosity of a log message or the maximum verbosity enabled for a component.
+type Level int
+
+// These constants are the supported log levels, from least to most verbose.
+const (
+	LevelOff Level = iota
+	LevelInfo
+	LevelDebug
+)
+
+// String implements the fmt.Stringer interface.
+func (l Level) String() string {
+	switch l {
+	case LevelOff:
+		return "off"
+	case LevelInfo:
+		return "info"
+	case LevelDebug:
+		return "debug"
+	}
+	return "unknown"
+}
+
+// ParseLevel returns the Level for one of the severity names of the logging specification. The
+// names are matched case-insensitively. Severities up to "info" map to LevelInfo and "debug" and
+// "trace" map to LevelDebug.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "off":
+		return LevelOff, nil
+	case "emergency", "alert", "critical", "error", "warn", "notice", "info":
+		return LevelInfo, nil
+	case "debug", "trace":
+		return LevelDebug, nil
+	}
+	return LevelOff, fmt.Errorf("unknown log level %q", s)
+}
+
+// Sink is the destination of log messages. keysAndValues alternate between string keys and their
+// values. Implementations must be safe for concurrent use.
+type Sink interface {
+	Log(component Component, level Level, msg string, keysAndValues ...interface{})
+}
+
+// Logger filters log messages by the level of their component and writes the rest to a Sink. A nil
+// *Logger is valid and discards every message.
+type Logger struct {
+	levels            map[Component]Level
+	sink              Sink
+	maxDocumentLength uint
+	structured        bool
+}
+
+// Option configures a Logger.
+type Option func(*Logger)
+
+// WithStructuredTruncation causes logged documents that are longer than the maximum document
+// length to be truncated with TruncateDocument, which keeps them valid extended JSON, instead of
+// being cut at the maximum length.
+func WithStructuredTruncation() Option {
+	return func(l *Logger) {
+		l.structured = true
+	}
+}
+
+// New creates a Logger. The level of each component is taken from levels, then from the
+// environment, and defaults to LevelOff. ComponentAll in levels or MONGODB_LOG_ALL sets the level of
+// the components that are not configured individually. If sink is nil, messages are written to the
+// destination in MONGODB_LOG_PATH, or standard error. If maxDocumentLength is 0, the length in
+// MONGODB_LOG_MAX_DOCUMENT_LENGTH, or DefaultMaxDocumentLength, is used.
+//
+// New returns nil if every component is off.
+func New(sink Sink, maxDocumentLength uint, levels map[Component]Level, opts ...Option) (*Logger, error) {
+	resolved, err := resolveLevels(levels)
+	if err != nil {
+		return nil, err
+	}
+	if len(resolved) == 0 {
+		return nil, nil
+	}
+
+	if maxDocumentLength == 0 {
+		maxDocumentLength = DefaultMaxDocumentLength
+		if env := os.Getenv("MONGODB_LOG_MAX_DOCUMENT_LENGTH"); env != "" {
+			length, err := strconv.ParseUint(env, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid MONGODB_LOG_MAX_DOCUMENT_LENGTH %q: %v", env, err)
+			}
+			maxDocumentLength = uint(length)
+		}
+	}
+
+	if sink == nil {
+		sink, err = envSink()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	l := &Logger{levels: resolved, sink: sink, maxDocumentLength: maxDocumentLength}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l, nil
+}
+
+// resolveLevels returns the enabled level of each component, omitting the components that are off.
+func resolveLevels(levels map[Component]Level) (map[Component]Level, error) {
+	lookup := func(c Component) (Level, bool, error) {
+		if l, ok := levels[c]; ok {
+			return l, true, nil
+		}
+		env := os.Getenv(componentEnvVars[c])
+		if env == "" {
+			return LevelOff, false, nil
+		}
+		l, err := ParseLevel(env)
+		if err != nil {
+			return LevelOff, false, fmt.Errorf("invalid %s: %v", componentEnvVars[c], err)
+		}
+		return l, true, nil
+	}
+
+	all, _, err := lookup(ComponentAll)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[Component]Level)
+	for _, c := range []Component{ComponentCommand, ComponentTopology, ComponentServerSelection, ComponentConnection} {
+		l, ok, err := lookup(c)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			l = all
+		}
+		if l > LevelOff {
+			resolved[c] = l
+		}
+	}
+	return resolved, nil
+}
+
+// envSink returns a sink for the destination in MONGODB_LOG_PATH.
+func envSink() (Sink, error) {
+	switch path := os.Getenv("MONGODB_LOG_PATH"); strings.ToLower(path) {
+	case "", "stderr":
+		return NewIOSink(os.Stderr), nil
+	case "stdout":
+		return NewIOSink(os.Stdout), nil
+	default:
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open MONGODB_LOG_PATH: %v", err)
+		}
+		return NewIOSink(f), nil
+	}
+}
+
+// Enabled reports whether messages of component at level are logged.
+func (l *Logger) Enabled(component Component, level Level) bool {
+	if l == nil || level == LevelOff {
+		return false
+	}
+	return level <= l.levels[component]
+}
+
+// Print logs msg with the given keys and values if component is enabled at level.
+func (l *Logger) Print(component Component, level Level, msg string, keysAndValues ...interface{}) {
+	if !l.Enabled(component, level) {
+		return
+	}
+	l.sink.Log(component, level, msg, keysAndValues...)
+}
+
+// FormatDocument returns the extended JSON representation of doc, truncated to the maximum document
+// length of the logger.
+func (l *Logger) FormatDocument(doc bson.Raw) string {
+	width := DefaultMaxDocumentLength
+	if l != nil {
+		width = l.maxDocumentLength
+		if l.structured {
+			return TruncateDocument(doc, width)
+		}
+	}
+	return Truncate(doc.String(), width)
+}
+
+// Close closes the sink of the logger if it implements io.Closer.
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	if c, ok := l.sink.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Truncate returns str cut to at most width bytes followed by TruncationSuffix if it is longer than
+// width. The cut never splits a multi-byte UTF-8 character.
+func Truncate(str string, width uint) string {
+	if uint(len(str)) <= width {
+		return str
+	}
+
+	cut := int(width)
+	// back up to the start of a rune so the result remains valid UTF-8.
+	for cut > 0 && str[cut]&0xC0 == 0x80 {
+		cut--
+	}
+	return str[:cut] + TruncationSuffix
+}
@@ -0,0 +1,201 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+type message struct {
+	component     Component
+	level         Level
+	msg           string
+	keysAndValues []interface{}
+}
+
+type testSink struct {
+	l        sync.Mutex
+	messages []message
+}
+
+func (ts *testSink) Log(component Component, level Level, msg string, keysAndValues ...interface{}) {
+	ts.l.Lock()
+	defer ts.l.Unlock()
+	ts.messages = append(ts.messages, message{component, level, msg, keysAndValues})
+}
+
+func setenv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, name := range componentEnvVars {
+		require.NoError(t, os.Unsetenv(name))
+	}
+	require.NoError(t, os.Unsetenv("MONGODB_LOG_MAX_DOCUMENT_LENGTH"))
+	for k, v := range env {
+		require.NoError(t, os.Setenv(k, v))
+	}
+}
+
+func TestNew(t *testing.T) {
+	defer setenv(t, nil)
+
+	t.Run("off by default", func(t *testing.T) {
+		setenv(t, nil)
+		l, err := New(&testSink{}, 0, nil)
+		require.NoError(t, err)
+		require.Nil(t, l)
+		require.False(t, l.Enabled(ComponentCommand, LevelInfo))
+	})
+	t.Run("environment", func(t *testing.T) {
+		setenv(t, map[string]string{
+			"MONGODB_LOG_ALL":                 "info",
+			"MONGODB_LOG_COMMAND":             "Debug",
+			"MONGODB_LOG_MAX_DOCUMENT_LENGTH": "5",
+		})
+		l, err := New(&testSink{}, 0, nil)
+		require.NoError(t, err)
+		require.True(t, l.Enabled(ComponentCommand, LevelDebug))
+		require.True(t, l.Enabled(ComponentTopology, LevelInfo))
+		require.False(t, l.Enabled(ComponentTopology, LevelDebug))
+		require.Equal(t, uint(5), l.maxDocumentLength)
+	})
+	t.Run("programmatic overrides environment", func(t *testing.T) {
+		setenv(t, map[string]string{"MONGODB_LOG_ALL": "debug", "MONGODB_LOG_CONNECTION": "debug"})
+		l, err := New(&testSink{}, 0, map[Component]Level{ComponentAll: LevelInfo, ComponentCommand: LevelOff})
+		require.NoError(t, err)
+		require.False(t, l.Enabled(ComponentCommand, LevelInfo))
+		require.True(t, l.Enabled(ComponentConnection, LevelDebug))
+		require.False(t, l.Enabled(ComponentTopology, LevelDebug))
+		require.Equal(t, DefaultMaxDocumentLength, l.maxDocumentLength)
+	})
+	t.Run("invalid level", func(t *testing.T) {
+		setenv(t, map[string]string{"MONGODB_LOG_COMMAND": "verbose"})
+		_, err := New(&testSink{}, 0, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	require.Equal(t, "hello", Truncate("hello", 5))
+	require.Equal(t, "hel...", Truncate("hello", 3))
+	// the two byte "é" is not split.
+	require.Equal(t, "h...", Truncate("héllo", 2))
+}
+
+func TestTruncateDocument(t *testing.T) {
+	marshal := func(v interface{}) bson.Raw {
+		doc, err := bson.Marshal(v)
+		require.NoError(t, err)
+		return doc
+	}
+	parse := func(str string) bson.D {
+		var d bson.D
+		require.NoError(t, bson.UnmarshalExtJSON([]byte(str), false, &d), str)
+		return d
+	}
+
+	t.Run("short documents are unchanged", func(t *testing.T) {
+		doc := marshal(bson.D{{"insert", "coll"}})
+		require.Equal(t, doc.String(), TruncateDocument(doc, 1000))
+	})
+	t.Run("drops array elements", func(t *testing.T) {
+		docs := make(bson.A, 100)
+		for i := range docs {
+			docs[i] = bson.D{{"_id", int32(i)}}
+		}
+		str := TruncateDocument(marshal(bson.D{{"insert", "coll"}, {"documents", docs}}), 200)
+		require.True(t, len(str) <= 200, str)
+
+		d := parse(str)
+		require.Equal(t, "insert", d[0].Key)
+		require.Equal(t, "documents", d[1].Key)
+		kept := d[1].Value.(bson.A)
+		require.True(t, len(kept) > 1)
+		require.Equal(t, TruncationSuffix+" "+strconv.Itoa(100-len(kept)+1)+" more elements", kept[len(kept)-1])
+	})
+	t.Run("drops fields", func(t *testing.T) {
+		doc := marshal(bson.D{{"a", int32(1)}, {"b", int32(2)}, {"c", int32(3)}, {"d", int32(4)}, {"e", int32(5)}})
+		str := TruncateDocument(doc, 60)
+		require.True(t, len(str) <= 60, str)
+		d := parse(str)
+		require.Equal(t, TruncationSuffix, d[len(d)-1].Key)
+	})
+	t.Run("shortens strings", func(t *testing.T) {
+		str := TruncateDocument(marshal(bson.D{{"filter", strings.Repeat("x", 500)}}), 100)
+		require.True(t, len(str) <= 100, str)
+		d := parse(str)
+		require.Equal(t, "filter", d[0].Key)
+		require.True(t, strings.HasSuffix(d[0].Value.(string), "x"+TruncationSuffix))
+	})
+	t.Run("logger option", func(t *testing.T) {
+		doc := marshal(bson.D{{"filter", strings.Repeat("x", 500)}})
+		l, err := New(&testSink{}, 100, map[Component]Level{ComponentCommand: LevelDebug}, WithStructuredTruncation())
+		require.NoError(t, err)
+		require.Equal(t, TruncateDocument(doc, 100), l.FormatDocument(doc))
+
+		l, err = New(&testSink{}, 100, map[Component]Level{ComponentCommand: LevelDebug})
+		require.NoError(t, err)
+		require.Equal(t, Truncate(doc.String(), 100), l.FormatDocument(doc))
+	})
+}
+
+func TestIOSink(t *testing.T) {
+	var buf bytes.Buffer
+	NewIOSink(&buf).Log(ComponentCommand, LevelDebug, "Command started", "commandName", "ping", "requestId", int64(3))
+
+	require.True(t, strings.HasSuffix(buf.String(), "}\n"))
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	require.Equal(t, "debug", line["s"])
+	require.Equal(t, "command", line["c"])
+	require.Equal(t, "Command started", line["message"])
+	require.Equal(t, "ping", line["commandName"])
+	require.Equal(t, float64(3), line["requestId"])
+}
+
+func TestCommandMonitor(t *testing.T) {
+	sink := &testSink{}
+	l, err := New(sink, 20, map[Component]Level{ComponentCommand: LevelDebug})
+	require.NoError(t, err)
+
+	var forwarded int
+	monitor := l.CommandMonitor(&event.CommandMonitor{
+		Started: func(context.Context, *event.CommandStartedEvent) { forwarded++ },
+	})
+
+	cmd := bsoncore.BuildDocument(nil, bsoncore.AppendStringElement(nil, "find", "a very long collection name"))
+	monitor.Started(context.Background(), &event.CommandStartedEvent{
+		Command:       cmd,
+		CommandName:   "find",
+		DatabaseName:  "db",
+		ServerAddress: "localhost:27017",
+	})
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{})
+
+	require.Equal(t, 1, forwarded)
+	require.Len(t, sink.messages, 2)
+	require.Equal(t, "Command started", sink.messages[0].msg)
+	kv := sink.messages[0].keysAndValues
+	require.Equal(t, []interface{}{"command", `{"find": "a very lon...`}, kv[:2])
+	require.Equal(t, []interface{}{"serverHost", "localhost", "serverPort", 27017}, kv[len(kv)-4:])
+	require.Equal(t, "Command succeeded", sink.messages[1].msg)
+
+	// a disabled component returns the original monitor.
+	next := &event.PoolMonitor{}
+	require.True(t, next == l.PoolMonitor(next))
+}
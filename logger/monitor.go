@@ -0,0 +1,210 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package logger
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// CommandMonitor returns a monitor that logs command events to the command component and then
+// forwards them to next, which may be nil. If the command component is disabled, next is returned.
+func (l *Logger) CommandMonitor(next *event.CommandMonitor) *event.CommandMonitor {
+	if !l.Enabled(ComponentCommand, LevelDebug) {
+		return next
+	}
+	if next == nil {
+		next = &event.CommandMonitor{}
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			host, port := splitAddress(evt.ServerAddress)
+			l.Print(ComponentCommand, LevelDebug, "Command started",
+				"command", l.FormatDocument(evt.Command),
+				"databaseName", evt.DatabaseName,
+				"commandName", evt.CommandName,
+				"requestId", evt.RequestID,
+				"driverConnectionId", evt.ConnectionID,
+				"serverHost", host,
+				"serverPort", port,
+			)
+			if next.Started != nil {
+				next.Started(ctx, evt)
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			host, port := splitAddress(evt.ServerAddress)
+			l.Print(ComponentCommand, LevelDebug, "Command succeeded",
+				"durationMS", evt.DurationNanos/1e6,
+				"reply", l.FormatDocument(evt.Reply),
+				"commandName", evt.CommandName,
+				"requestId", evt.RequestID,
+				"driverConnectionId", evt.ConnectionID,
+				"serverHost", host,
+				"serverPort", port,
+			)
+			if next.Succeeded != nil {
+				next.Succeeded(ctx, evt)
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			host, port := splitAddress(evt.ServerAddress)
+			l.Print(ComponentCommand, LevelDebug, "Command failed",
+				"durationMS", evt.DurationNanos/1e6,
+				"failure", evt.Failure,
+				"commandName", evt.CommandName,
+				"requestId", evt.RequestID,
+				"driverConnectionId", evt.ConnectionID,
+				"serverHost", host,
+				"serverPort", port,
+			)
+			if next.Failed != nil {
+				next.Failed(ctx, evt)
+			}
+		},
+	}
+}
+
+var poolMessages = map[string]string{
+	event.PoolCreated:        "Connection pool created",
+	event.PoolCleared:        "Connection pool cleared",
+	event.PoolClosedEvent:    "Connection pool closed",
+	event.ConnectionCreated:  "Connection created",
+	event.ConnectionReady:    "Connection ready",
+	event.ConnectionClosed:   "Connection closed",
+	event.GetStarted:         "Connection checkout started",
+	event.GetFailed:          "Connection checkout failed",
+	event.GetSucceeded:       "Connection checked out",
+	event.ConnectionReturned: "Connection checked in",
+}
+
+// PoolMonitor returns a monitor that logs connection pool events to the connection component and
+// then forwards them to next, which may be nil. If the connection component is disabled, next is
+// returned.
+func (l *Logger) PoolMonitor(next *event.PoolMonitor) *event.PoolMonitor {
+	if !l.Enabled(ComponentConnection, LevelDebug) {
+		return next
+	}
+
+	return &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			if msg, ok := poolMessages[evt.Type]; ok {
+				host, port := splitAddress(evt.Address)
+				kv := []interface{}{"serverHost", host, "serverPort", port}
+				if evt.ConnectionID != 0 {
+					kv = append(kv, "driverConnectionId", evt.ConnectionID)
+				}
+				if evt.Reason != "" {
+					kv = append(kv, "reason", evt.Reason)
+				}
+				if evt.PoolOptions != nil {
+					kv = append(kv, "maxPoolSize", evt.PoolOptions.MaxPoolSize, "maxIdlePoolSize", evt.PoolOptions.MaxIdlePoolSize)
+				}
+				l.Print(ComponentConnection, LevelDebug, msg, kv...)
+			}
+			if next != nil && next.Event != nil {
+				next.Event(evt)
+			}
+		},
+	}
+}
+
+// ServerMonitor returns a monitor that logs server discovery and monitoring events to the topology
+// component and then forwards them to next, which may be nil. If the topology component is
+// disabled, next is returned.
+func (l *Logger) ServerMonitor(next *event.ServerMonitor) *event.ServerMonitor {
+	if !l.Enabled(ComponentTopology, LevelDebug) {
+		return next
+	}
+	if next == nil {
+		next = &event.ServerMonitor{}
+	}
+
+	sm := *next
+	sm.ServerOpening = func(evt *event.ServerOpeningEvent) {
+		host, port := splitAddress(evt.Address.String())
+		l.Print(ComponentTopology, LevelDebug, "Starting server monitoring",
+			"topologyId", evt.TopologyID.Hex(), "serverHost", host, "serverPort", port)
+		if next.ServerOpening != nil {
+			next.ServerOpening(evt)
+		}
+	}
+	sm.ServerClosed = func(evt *event.ServerClosedEvent) {
+		host, port := splitAddress(evt.Address.String())
+		l.Print(ComponentTopology, LevelDebug, "Stopped server monitoring",
+			"topologyId", evt.TopologyID.Hex(), "serverHost", host, "serverPort", port)
+		if next.ServerClosed != nil {
+			next.ServerClosed(evt)
+		}
+	}
+	sm.TopologyOpening = func(evt *event.TopologyOpeningEvent) {
+		l.Print(ComponentTopology, LevelDebug, "Starting topology monitoring", "topologyId", evt.TopologyID.Hex())
+		if next.TopologyOpening != nil {
+			next.TopologyOpening(evt)
+		}
+	}
+	sm.TopologyClosed = func(evt *event.TopologyClosedEvent) {
+		l.Print(ComponentTopology, LevelDebug, "Stopped topology monitoring", "topologyId", evt.TopologyID.Hex())
+		if next.TopologyClosed != nil {
+			next.TopologyClosed(evt)
+		}
+	}
+	sm.TopologyDescriptionChanged = func(evt *event.TopologyDescriptionChangedEvent) {
+		l.Print(ComponentTopology, LevelDebug, "Topology description changed",
+			"topologyId", evt.TopologyID.Hex(),
+			"previousDescription", evt.PreviousDescription.String(),
+			"newDescription", evt.NewDescription.String(),
+		)
+		if next.TopologyDescriptionChanged != nil {
+			next.TopologyDescriptionChanged(evt)
+		}
+	}
+	sm.ServerHeartbeatStarted = func(evt *event.ServerHeartbeatStartedEvent) {
+		l.Print(ComponentTopology, LevelDebug, "Server heartbeat started", "driverConnectionId", evt.ConnectionID)
+		if next.ServerHeartbeatStarted != nil {
+			next.ServerHeartbeatStarted(evt)
+		}
+	}
+	sm.ServerHeartbeatSucceeded = func(evt *event.ServerHeartbeatSucceededEvent) {
+		l.Print(ComponentTopology, LevelDebug, "Server heartbeat succeeded",
+			"driverConnectionId", evt.ConnectionID,
+			"durationMS", evt.DurationNanos/1e6,
+			"serverType", evt.Reply.Kind.String(),
+		)
+		if next.ServerHeartbeatSucceeded != nil {
+			next.ServerHeartbeatSucceeded(evt)
+		}
+	}
+	sm.ServerHeartbeatFailed = func(evt *event.ServerHeartbeatFailedEvent) {
+		l.Print(ComponentTopology, LevelDebug, "Server heartbeat failed",
+			"driverConnectionId", evt.ConnectionID,
+			"durationMS", evt.DurationNanos/1e6,
+			"failure", evt.Failure,
+		)
+		if next.ServerHeartbeatFailed != nil {
+			next.ServerHeartbeatFailed(evt)
+		}
+	}
+	return &sm
+}
+
+// splitAddress splits a "host:port" address into the host and the numeric port. If the address has
+// no port, such as a unix domain socket, the port is nil.
+func splitAddress(addr string) (string, interface{}) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, nil
+	}
+	if p, err := strconv.Atoi(port); err == nil {
+		return host, p
+	}
+	return host, port
+}
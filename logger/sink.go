@@ -0,0 +1,86 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// IOSink is a Sink that writes each message as a single line of JSON to an io.Writer. The line
+// contains the time, level, component, and message followed by the keys and values in order:
+//
+//	{"t":"2019-06-01T12:00:00Z","s":"debug","c":"command","message":"Command started","commandName":"ping"}
+type IOSink struct {
+	l sync.Mutex
+	w io.Writer
+}
+
+// NewIOSink creates an IOSink that writes to w.
+func NewIOSink(w io.Writer) *IOSink {
+	return &IOSink{w: w}
+}
+
+// Log implements the Sink interface.
+func (s *IOSink) Log(component Component, level Level, msg string, keysAndValues ...interface{}) {
+	var buf bytes.Buffer
+	buf.WriteString(`{"t":`)
+	writeJSON(&buf, time.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"s":`)
+	writeJSON(&buf, level.String())
+	buf.WriteString(`,"c":`)
+	writeJSON(&buf, component.String())
+	buf.WriteString(`,"message":`)
+	writeJSON(&buf, msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		buf.WriteByte(',')
+		writeJSON(&buf, fmt.Sprint(keysAndValues[i]))
+		buf.WriteByte(':')
+		if i+1 < len(keysAndValues) {
+			writeJSON(&buf, keysAndValues[i+1])
+		} else {
+			buf.WriteString("null")
+		}
+	}
+	buf.WriteString("}\n")
+
+	s.l.Lock()
+	defer s.l.Unlock()
+	_, _ = s.w.Write(buf.Bytes())
+}
+
+// Close closes the underlying writer if it implements io.Closer and is not standard output or
+// standard error.
+func (s *IOSink) Close() error {
+	if s.w == os.Stdout || s.w == os.Stderr {
+		return nil
+	}
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) {
+	switch t := v.(type) {
+	case error:
+		v = t.Error()
+	case fmt.Stringer:
+		v = t.String()
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(v))
+	}
+	buf.Write(b)
+}
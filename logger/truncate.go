@@ -0,0 +1,140 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package logger
+
+import (
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// TruncateDocument returns the extended JSON representation of doc cut to at most width bytes
+// without breaking its structure, so that the result can still be parsed. The elements that do
+// not fit are dropped from the end of documents and arrays and replaced by a marker saying how
+// many were dropped, such as "...": "3 more fields" or "... 95 more elements", and a string that
+// does not fit is shortened and ends with TruncationSuffix. The result may be longer than width
+// if width does not leave room for the markers.
+func TruncateDocument(doc bson.Raw, width uint) string {
+	str := doc.String()
+	if uint(len(str)) <= width {
+		return str
+	}
+
+	truncated := truncateDocument(bsoncore.Document(doc), false, int(width))
+	if truncated == nil {
+		return Truncate(str, width)
+	}
+	return truncated.String()
+}
+
+// truncateDocument returns a copy of the document or array d whose extended JSON representation
+// is at most budget bytes long, or nil if d is malformed.
+func truncateDocument(d bsoncore.Document, isArray bool, budget int) bsoncore.Document {
+	elems, err := d.Elements()
+	if err != nil {
+		return nil
+	}
+
+	// Markers are at most this long, since fewer elements are dropped than there are elements.
+	reserve := 1 + elementLen(markerElement(isArray, len(elems), len(elems)), isArray)
+
+	idx, out := bsoncore.AppendDocumentStart(nil)
+	used := 2 // the enclosing braces or brackets
+	kept := 0
+	for i, elem := range elems {
+		sep := 0
+		if i > 0 {
+			sep = 1
+		}
+		if cost := sep + elementLen(elem, isArray); used+cost <= budget-reserve || (i == len(elems)-1 && used+cost <= budget) {
+			out = append(out, elem...)
+			used += cost
+			kept++
+			continue
+		}
+
+		key := elem.Key()
+		if isArray {
+			key = strconv.Itoa(kept)
+		}
+		keyLen := 0
+		if !isArray {
+			keyLen = len(elem.String()) - len(elem.Value().String())
+		}
+		avail := budget - reserve - used - sep - keyLen
+
+		val := elem.Value()
+		switch val.Type {
+		case bsontype.String:
+			if s, ok := elideString(val.StringValue(), avail); ok {
+				out = bsoncore.AppendStringElement(out, key, s)
+				kept++
+			}
+		case bsontype.EmbeddedDocument, bsontype.Array:
+			sub := truncateDocument(val.Data, val.Type == bsontype.Array, avail)
+			if sub != nil && len(bsoncore.Value{Type: val.Type, Data: sub}.String()) <= avail {
+				if val.Type == bsontype.Array {
+					out = bsoncore.AppendArrayElement(out, key, sub)
+				} else {
+					out = bsoncore.AppendDocumentElement(out, key, sub)
+				}
+				kept++
+			}
+		}
+		if dropped := len(elems) - kept; dropped > 0 {
+			out = append(out, markerElement(isArray, kept, dropped)...)
+		}
+		break
+	}
+
+	out, err = bsoncore.AppendDocumentEnd(out, idx)
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// elementLen returns the length of the extended JSON representation of elem in a document, or of
+// its value in an array.
+func elementLen(elem bsoncore.Element, isArray bool) int {
+	if isArray {
+		return len(elem.Value().String())
+	}
+	return len(elem.String())
+}
+
+// markerElement returns the element that replaces the dropped elements of a document or array. idx
+// is the index of the marker in an array.
+func markerElement(isArray bool, idx, dropped int) bsoncore.Element {
+	if isArray {
+		return bsoncore.AppendStringElement(nil, strconv.Itoa(idx), TruncationSuffix+" "+strconv.Itoa(dropped)+" more elements")
+	}
+	return bsoncore.AppendStringElement(nil, TruncationSuffix, strconv.Itoa(dropped)+" more fields")
+}
+
+// elideString returns a prefix of str followed by TruncationSuffix whose extended JSON
+// representation is at most width bytes long, and false if there is no such non-empty prefix.
+func elideString(str string, width int) (string, bool) {
+	cut := len(str)
+	if cut > width {
+		cut = width
+	}
+	for cut > 0 {
+		// back up to the start of a rune so the result remains valid UTF-8.
+		for cut > 0 && cut < len(str) && str[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		s := str[:cut] + TruncationSuffix
+		if len(bsoncore.Value{Type: bsontype.String, Data: bsoncore.AppendString(nil, s)}.String()) <= width {
+			return s, true
+		}
+		cut--
+	}
+	return "", false
+}
@@ -20,7 +20,9 @@ import (
 	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
 	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/description"
 )
 
@@ -61,6 +63,7 @@ type ChangeStream struct {
 	streamType  StreamType
 	client      *Client
 	sess        Session
+	releaseSess func() // releases the lazy session used by the change stream, if any
 	readPref    *readpref.ReadPref
 	readConcern *readconcern.ReadConcern
 	registry    *bsoncodec.Registry
@@ -210,7 +213,21 @@ func (cs *ChangeStream) runCommand(ctx context.Context, replaceOptions bool) err
 		ReadConcern: cs.readConcern,
 	}
 
+	var retryConn connection.Connection
 	rdr, err := readCmd.RoundTrip(ctx, desc, conn)
+	if err != nil && cs.client.retryReads {
+		ss, err = driverlegacy.RetryRead(ctx, cs.client.topology, cs.db.writeSelector, readCmd.Session, ss, err,
+			func(desc description.SelectedServer, conn connection.Connection) error {
+				var rtErr error
+				rdr, rtErr = readCmd.RoundTrip(ctx, desc, conn)
+				retryConn = topology.PinConnection(conn)
+				return rtErr
+			})
+	}
+	if retryConn != nil {
+		defer retryConn.Close()
+		conn = retryConn
+	}
 	if err != nil {
 		cs.sess.EndSession(ctx)
 		return replaceErrors(err)
@@ -221,6 +238,7 @@ func (cs *ChangeStream) runCommand(ctx context.Context, replaceOptions bool) err
 		cs.sess.EndSession(ctx)
 		return replaceErrors(err)
 	}
+	batchCursor.PinConnection(conn)
 	cursor, err := newCursor(batchCursor, cs.registry)
 	if err != nil {
 		cs.sess.EndSession(ctx)
@@ -251,8 +269,10 @@ func newChangeStream(ctx context.Context, coll *Collection, pipeline interface{}
 	if err != nil {
 		return nil, err
 	}
+	ctx, releaseSess := useLazySession(ctx)
 	sess, err := getSession(ctx, coll.client)
 	if err != nil {
+		releaseSess()
 		return nil, err
 	}
 
@@ -271,6 +291,7 @@ func newChangeStream(ctx context.Context, coll *Collection, pipeline interface{}
 	cs := &ChangeStream{
 		client:      coll.client,
 		sess:        sess,
+		releaseSess: releaseSess,
 		cmd:         cmd,
 		pipeline:    pipelineArr,
 		coll:        coll,
@@ -286,6 +307,7 @@ func newChangeStream(ctx context.Context, coll *Collection, pipeline interface{}
 
 	err = cs.runCommand(ctx, false)
 	if err != nil {
+		releaseSess()
 		return nil, err
 	}
 
@@ -305,8 +327,10 @@ func newDbChangeStream(ctx context.Context, db *Database, pipeline interface{},
 	if err != nil {
 		return nil, err
 	}
+	ctx, releaseSess := useLazySession(ctx)
 	sess, err := getSession(ctx, db.client)
 	if err != nil {
+		releaseSess()
 		return nil, err
 	}
 
@@ -326,6 +350,7 @@ func newDbChangeStream(ctx context.Context, db *Database, pipeline interface{},
 		client:      db.client,
 		db:          db,
 		sess:        sess,
+		releaseSess: releaseSess,
 		cmd:         cmd,
 		pipeline:    pipelineArr,
 		streamType:  DatabaseStream,
@@ -339,6 +364,7 @@ func newDbChangeStream(ctx context.Context, db *Database, pipeline interface{},
 
 	err = cs.runCommand(ctx, false)
 	if err != nil {
+		releaseSess()
 		return nil, err
 	}
 
@@ -358,8 +384,10 @@ func newClientChangeStream(ctx context.Context, client *Client, pipeline interfa
 	if err != nil {
 		return nil, err
 	}
+	ctx, releaseSess := useLazySession(ctx)
 	sess, err := getSession(ctx, client)
 	if err != nil {
+		releaseSess()
 		return nil, err
 	}
 
@@ -379,6 +407,7 @@ func newClientChangeStream(ctx context.Context, client *Client, pipeline interfa
 		client:      client,
 		db:          client.Database("admin"),
 		sess:        sess,
+		releaseSess: releaseSess,
 		cmd:         cmd,
 		pipeline:    pipelineArr,
 		streamType:  ClientStream,
@@ -392,6 +421,7 @@ func newClientChangeStream(ctx context.Context, client *Client, pipeline interfa
 
 	err = cs.runCommand(ctx, false)
 	if err != nil {
+		releaseSess()
 		return nil, err
 	}
 
@@ -450,25 +480,80 @@ func (cs *ChangeStream) Next(ctx context.Context) bool {
 			return true
 		}
 
-		err := cs.cursor.Err()
-		if err == nil {
+		if !cs.resume(ctx) {
 			return false
 		}
+	}
+}
 
-		switch t := err.(type) {
-		case command.Error:
-			if t.Code == errorInterrupted || t.Code == errorCappedPositionLost || t.Code == errorCursorKilled {
-				return false
+// ChangeStreamBatch is a batch of change documents, as returned by the server.
+type ChangeStreamBatch struct {
+	// Documents are the BSON bytes of the change documents. They are only valid until the next call
+	// to Next, NextBatch or Close.
+	Documents []bson.Raw
+
+	// ResumeToken is the token to resume the change stream after the batch. It is the
+	// postBatchResumeToken of the batch if the server reported one, and the resume token of its last
+	// document otherwise.
+	ResumeToken bson.Raw
+}
+
+// NextBatch gets the change documents of the current batch that Next has not returned yet or, if
+// there are none, those of the next batch that has documents. It returns false if the change
+// stream has no more documents or an error occurred, in which case Err returns it. Consumers that
+// process changes in bulk can store the resume token of each batch instead of that of each document.
+func (cs *ChangeStream) NextBatch(ctx context.Context) (ChangeStreamBatch, bool) {
+	for {
+		if cs.cursor == nil {
+			return ChangeStreamBatch{}, false
+		}
+
+		if docs, ok := cs.cursor.nextBatchDocuments(ctx); ok {
+			for _, doc := range docs {
+				if _, ok := doc.Lookup("_id").DocumentOK(); !ok {
+					_ = cs.Close(context.Background())
+					cs.err = ErrMissingResumeToken
+					return ChangeStreamBatch{}, false
+				}
+			}
+			if err := cs.storeResumeToken(); err != nil {
+				cs.err = err
+				return ChangeStreamBatch{}, false
+			}
+
+			cs.Current = cs.cursor.Current
+			token := cs.cursor.PostBatchResumeToken()
+			if token == nil {
+				token = cs.Current.Lookup("_id").Document()
 			}
+			return ChangeStreamBatch{Documents: docs, ResumeToken: token}, true
+		}
+
+		if !cs.resume(ctx) {
+			return ChangeStreamBatch{}, false
 		}
+	}
+}
 
-		_, _ = driverlegacy.KillCursors(ctx, cs.ns, cs.cursor.bc.Server(), cs.ID())
+// resume recreates the cursor of the change stream after its iteration failed with a resumable
+// error. It returns false if the iteration ended or failed with an error that is not resumable.
+func (cs *ChangeStream) resume(ctx context.Context) bool {
+	err := cs.cursor.err
+	if err == nil {
+		return false
+	}
 
-		cs.err = cs.runCommand(ctx, true)
-		if cs.err != nil {
+	switch t := err.(type) {
+	case command.Error:
+		if t.Code == errorInterrupted || t.Code == errorCappedPositionLost || t.Code == errorCursorKilled {
 			return false
 		}
 	}
+
+	_, _ = driverlegacy.KillCursors(ctx, cs.ns, cs.cursor.bc.Server(), cs.ID())
+
+	cs.err = cs.runCommand(ctx, true)
+	return cs.err == nil
 }
 
 // Decode will decode the current document into val.
@@ -494,6 +579,9 @@ func (cs *ChangeStream) Err() error {
 
 // Close closes this cursor.
 func (cs *ChangeStream) Close(ctx context.Context) error {
+	if cs.releaseSess != nil {
+		cs.releaseSess()
+	}
 	if cs.cursor == nil {
 		return nil // cursor is already closed
 	}
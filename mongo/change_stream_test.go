@@ -20,6 +20,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy"
 )
 
@@ -702,3 +703,61 @@ func ensureResumeToken(t *testing.T, coll *Collection, cs *ChangeStream) {
 		t.Fatal("Next returned false, expected true")
 	}
 }
+
+func TestChangeStreamNextBatch(t *testing.T) {
+	changeBatch := func(tokens ...int32) *bsoncore.DocumentSequence {
+		var data []byte
+		for _, token := range tokens {
+			data = bsoncore.BuildDocumentFromElements(data,
+				bsoncore.AppendDocumentElement(nil, "_id", bsoncore.BuildDocumentFromElements(nil,
+					bsoncore.AppendInt32Element(nil, "token", token),
+				)),
+				bsoncore.AppendStringElement(nil, "operationType", "insert"),
+			)
+		}
+		return &bsoncore.DocumentSequence{Style: bsoncore.SequenceStyle, Data: data}
+	}
+	newStream := func(batches ...*bsoncore.DocumentSequence) *ChangeStream {
+		cursor, err := newCursor(&testBatchCursor{batches: batches}, nil)
+		require.NoError(t, err)
+		return &ChangeStream{cursor: cursor, registry: bson.DefaultRegistry}
+	}
+	tokenOf := func(raw bson.Raw) int32 {
+		return raw.Lookup("token").Int32()
+	}
+
+	t.Run("returns each batch with the token of its last document", func(t *testing.T) {
+		cs := newStream(changeBatch(1, 2, 3), changeBatch(), changeBatch(4))
+
+		batch, ok := cs.NextBatch(context.Background())
+		require.True(t, ok)
+		require.Len(t, batch.Documents, 3)
+		require.Equal(t, int32(3), tokenOf(batch.ResumeToken))
+
+		batch, ok = cs.NextBatch(context.Background())
+		require.True(t, ok, "expected the empty batch to be skipped")
+		require.Len(t, batch.Documents, 1)
+		require.Equal(t, int32(4), tokenOf(batch.ResumeToken))
+
+		_, ok = cs.NextBatch(context.Background())
+		require.False(t, ok)
+		require.NoError(t, cs.Err())
+	})
+	t.Run("returns the rest of a batch partially iterated by Next", func(t *testing.T) {
+		cs := newStream(changeBatch(1, 2, 3))
+		require.True(t, cs.Next(context.Background()))
+
+		batch, ok := cs.NextBatch(context.Background())
+		require.True(t, ok)
+		require.Len(t, batch.Documents, 2)
+		require.Equal(t, int32(2), tokenOf(batch.Documents[0].Lookup("_id").Document()))
+	})
+	t.Run("missing resume token", func(t *testing.T) {
+		doc := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendStringElement(nil, "operationType", "insert"))
+		cs := newStream(&bsoncore.DocumentSequence{Style: bsoncore.SequenceStyle, Data: doc})
+
+		_, ok := cs.NextBatch(context.Background())
+		require.False(t, ok)
+		require.Equal(t, ErrMissingResumeToken, cs.Err())
+	})
+}
@@ -8,18 +8,21 @@ package mongo
 
 import (
 	"context"
+	"net"
 	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/logger"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/auth"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/mongocrypt"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
@@ -27,6 +30,7 @@ import (
 	"go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/connstring"
 	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/ocsp"
 )
 
 const defaultLocalThreshold = 15 * time.Millisecond
@@ -38,13 +42,28 @@ type Client struct {
 	topology        *topology.Topology
 	connString      connstring.ConnString
 	localThreshold  time.Duration
+	retryReads      bool
 	retryWrites     bool
 	clock           *session.ClusterClock
 	readPreference  *readpref.ReadPref
 	readConcern     *readconcern.ReadConcern
 	writeConcern    *writeconcern.WriteConcern
+	checkWC         bool
 	registry        *bsoncodec.Registry
 	marshaller      BSONAppender
+	logger          *logger.Logger
+	events          *eventRing
+	timeout         *time.Duration
+	docValidator    options.DocumentValidator
+	stagePolicies   []pipelinePolicy
+	cursors         *cursorReaper
+
+	// Automatic client-side field level encryption. The internal clients do not encrypt.
+	crypt              *driverlegacy.Crypt
+	encryptedFieldsMap map[string]interface{}
+	internalClient     *Client
+	keyVaultClient     *Client
+	mongocryptd        *mcryptClient
 }
 
 // Connect creates a new Client and then initializes it using the Connect method.
@@ -80,7 +99,7 @@ func NewClient(opts ...*options.ClientOptions) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client := &Client{id: id}
+	client := &Client{id: id, cursors: newCursorReaper()}
 
 	err = client.configure(clientOpt)
 	if err != nil {
@@ -103,25 +122,119 @@ func (c *Client) Connect(ctx context.Context) error {
 		return replaceErrors(err)
 	}
 
+	for _, client := range c.encryptionClients() {
+		if err = client.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
 	return nil
 
 }
 
-// Disconnect closes sockets to the topology referenced by this Client. It will
-// shut down any monitoring goroutines, close the idle connection pool, and will
-// wait until all the in use connections have been returned to the connection
-// pool and closed before returning. If the context expires via cancellation,
-// deadline, or timeout before the in use connections have returned, the in use
-// connections will be closed, resulting in the failure of any in flight read
-// or write operations. If this method returns with no errors, all connections
-// associated with this Client have been closed.
+// Disconnect closes sockets to the topology referenced by this Client. Cursors that are still
+// open are closed first, killing their server cursors. Disconnect then waits until the operations
+// in progress have returned their connections, ends the server sessions of the Client, shuts down
+// the monitoring goroutines, and closes the connection pools. If the context expires via
+// cancellation, deadline, or timeout before the in use connections have returned, the in use
+// connections will be closed, resulting in the failure of any in flight read or write operations.
+// If this method returns with no errors, all connections associated with this Client have been
+// closed. Operations run after Disconnect return ErrClientDisconnected. The progress of Disconnect
+// can be reported with WithDisconnectProgress.
 func (c *Client) Disconnect(ctx context.Context) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
+	progress := newDisconnectReporter(ctx, c)
+	defer progress.stop()
+
+	progress.enter(DisconnectClosingCursors)
+	c.cursors.close(ctx)
+	// Operations that are still running may check in their sessions, which are then ended too.
+	progress.enter(DisconnectWaitingForOperations)
+	_ = c.topology.WaitIdle(ctx)
+	progress.enter(DisconnectEndingSessions)
 	c.endSessions(ctx)
-	return replaceErrors(c.topology.Disconnect(ctx))
+	progress.enter(DisconnectClosingPools)
+	err := c.topology.Disconnect(ctx)
+	// The internal clients do not report their progress.
+	internalCtx := context.WithValue(ctx, disconnectProgressKey{}, disconnectProgressConfig{})
+	for _, client := range c.encryptionClients() {
+		_ = client.Disconnect(internalCtx)
+	}
+	if c.crypt != nil {
+		c.crypt.Close()
+	}
+	_ = c.logger.Close()
+	return replaceErrors(err)
+}
+
+// ResetAfterFork replaces the connection pools, monitoring goroutines, and session pool of the
+// Client with new ones, reconnecting if the Client was connected. It is meant to be called in a
+// child process after a fork, where the connections inherited from the parent must not be
+// shared, and by test harnesses that need a Client with fresh state.
+//
+// Unlike Disconnect, ResetAfterFork does not end the server sessions of the Client, because they
+// may still be in use by the parent process. Sessions, cursors, change streams, and clones created
+// before the reset must not be used afterwards. ResetAfterFork must not be called concurrently with
+// other operations on the Client or on the Databases and Collections created from it.
+func (c *Client) ResetAfterFork(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	topo, err := topology.New(c.topologyOptions...)
+	if err != nil {
+		return replaceErrors(err)
+	}
+
+	for _, client := range c.encryptionClients() {
+		if err = client.ResetAfterFork(ctx); err != nil {
+			return err
+		}
+	}
+
+	old := c.topology
+	c.topology = topo
+
+	// Disconnecting a topology that was never connected returns ErrTopologyClosed.
+	err = old.Disconnect(ctx)
+	if err == topology.ErrTopologyClosed {
+		return nil
+	}
+	if err != nil {
+		return replaceErrors(err)
+	}
+
+	return replaceErrors(topo.Connect(ctx))
+}
+
+// CloneWith returns a Client that shares the connection pools, monitoring goroutines, and session
+// pool of c but runs operations with the read concern, write concern, read preference, registry,
+// and timeout of opts, falling back to those of c for the options that are not set. The clone does
+// not need to be connected, and because it shares the connections of c, disconnecting either of
+// them disconnects both. Sessions started from c can be used with the clone and vice versa.
+func (c *Client) CloneWith(opts ...*options.ClientCloneOptions) *Client {
+	cloneOpts := options.MergeClientCloneOptions(opts...)
+
+	clone := *c
+	if cloneOpts.ReadConcern != nil {
+		clone.readConcern = cloneOpts.ReadConcern
+	}
+	if cloneOpts.WriteConcern != nil {
+		clone.writeConcern = cloneOpts.WriteConcern
+	}
+	if cloneOpts.ReadPreference != nil {
+		clone.readPreference = cloneOpts.ReadPreference
+	}
+	if cloneOpts.Registry != nil {
+		clone.registry = cloneOpts.Registry
+	}
+	if cloneOpts.Timeout != nil {
+		clone.timeout = cloneOpts.Timeout
+	}
+	return &clone
 }
 
 // Ping verifies that the client can connect to the topology.
@@ -164,6 +277,14 @@ func (c *Client) StartSession(opts ...*options.SessionOptions) (Session, error)
 	if sopts.DefaultReadPreference != nil {
 		coreOpts.DefaultReadPreference = sopts.DefaultReadPreference
 	}
+	if sopts.Snapshot != nil {
+		coreOpts.Snapshot = sopts.Snapshot
+	}
+	if hook := sopts.AuditHook; hook != nil {
+		coreOpts.AuditHook = func(op session.Operation) {
+			hook(options.SessionOperation(op))
+		}
+	}
 
 	sess, err := session.NewClientSession(c.topology.SessionPool, c.id, session.Explicit, coreOpts)
 	if err != nil {
@@ -173,8 +294,10 @@ func (c *Client) StartSession(opts ...*options.SessionOptions) (Session, error)
 	sess.RetryWrite = c.retryWrites
 
 	return &sessionImpl{
-		Client: sess,
-		topo:   c.topology,
+		Client:  sess,
+		topo:    c.topology,
+		clock:   c.clock,
+		timeout: c.timeout,
 	}, nil
 }
 
@@ -201,11 +324,39 @@ func (c *Client) configure(opts *options.ClientOptions) error {
 
 	// TODO(GODRIVER-814): Add tests for topology, server, and connection related options.
 
+	// AddressMap
+	if len(opts.AddressMap) > 0 {
+		connOpts = append(connOpts, connection.WithAddressMap(
+			func(map[string]string) map[string]string { return opts.AddressMap },
+		))
+	}
+	// AllowedNamespaces & DeniedNamespaces
+	if len(opts.AllowedNamespaces) > 0 || len(opts.DeniedNamespaces) > 0 {
+		filter, err := newNamespaceFilter(opts.AllowedNamespaces, opts.DeniedNamespaces)
+		if err != nil {
+			return err
+		}
+		serverOpts = append(serverOpts, topology.WithNamespaceFilter(
+			func(topology.NamespaceFilter) topology.NamespaceFilter { return filter },
+		))
+	}
 	// AppName
 	var appName string
 	if opts.AppName != nil {
+		if len(*opts.AppName) > command.MaxAppNameLength {
+			return ErrAppNameTooLong
+		}
 		appName = *opts.AppName
 	}
+	// DriverInfo
+	var driverInfo command.DriverInfo
+	if opts.DriverInfo != nil {
+		driverInfo = command.DriverInfo{
+			Name:     opts.DriverInfo.Name,
+			Version:  opts.DriverInfo.Version,
+			Platform: opts.DriverInfo.Platform,
+		}
+	}
 	// Compressors & ZlibLevel
 	var comps []string
 	if len(opts.Compressors) > 0 {
@@ -229,9 +380,36 @@ func (c *Client) configure(opts *options.ClientOptions) error {
 			func(opts ...string) []string { return append(opts, comps...) },
 		))
 	}
+	// CompressorSelector
+	if opts.CompressorSelector != nil {
+		connOpts = append(connOpts, connection.WithCompressorSelector(
+			func(func(string, []string) string) func(string, []string) string { return opts.CompressorSelector },
+		))
+	}
+	// CompressionAllowList & CompressionDenyList & CompressionMinSize
+	if len(opts.CompressionAllowList) > 0 {
+		connOpts = append(connOpts, connection.WithCompressionAllowList(
+			func([]string) []string { return opts.CompressionAllowList },
+		))
+	}
+	if len(opts.CompressionDenyList) > 0 {
+		connOpts = append(connOpts, connection.WithCompressionDenyList(
+			func([]string) []string { return opts.CompressionDenyList },
+		))
+	}
+	if opts.CompressionMinSize != nil {
+		connOpts = append(connOpts, connection.WithCompressionMinSize(
+			func(int) int { return *opts.CompressionMinSize },
+		))
+	}
 	// Handshaker
+	loadBalanced := opts.LoadBalanced != nil && *opts.LoadBalanced
 	var handshaker = func(connection.Handshaker) connection.Handshaker {
-		return &command.Handshake{Client: command.ClientDoc(appName), Compressors: comps}
+		return &command.Handshake{
+			Client:       command.ClientDocWithDriverInfo(appName, driverInfo),
+			Compressors:  comps,
+			LoadBalanced: loadBalanced,
+		}
 	}
 	// Auth & Database & Password & Username
 	if opts.Auth != nil {
@@ -241,12 +419,15 @@ func (c *Client) configure(opts *options.ClientOptions) error {
 			PasswordSet: opts.Auth.PasswordSet,
 			Props:       opts.Auth.AuthMechanismProperties,
 			Source:      opts.Auth.AuthSource,
+
+			OIDCMachineCallback: oidcCallback(opts.Auth.OIDCMachineCallback),
+			OIDCHumanCallback:   oidcCallback(opts.Auth.OIDCHumanCallback),
 		}
 		mechanism := opts.Auth.AuthMechanism
 
 		if len(cred.Source) == 0 {
 			switch strings.ToUpper(mechanism) {
-			case auth.MongoDBX509, auth.GSSAPI, auth.PLAIN:
+			case auth.MongoDBX509, auth.GSSAPI, auth.PLAIN, auth.MongoDBOIDC:
 				cred.Source = "$external"
 			default:
 				cred.Source = "admin"
@@ -257,11 +438,16 @@ func (c *Client) configure(opts *options.ClientOptions) error {
 		if err != nil {
 			return err
 		}
+		serverOpts = append(serverOpts, topology.WithAuthenticator(
+			func(auth.Authenticator) auth.Authenticator { return authenticator },
+		))
 
 		handshakeOpts := &auth.HandshakeOptions{
 			AppName:       appName,
+			DriverInfo:    driverInfo,
 			Authenticator: authenticator,
 			Compressors:   comps,
+			LoadBalanced:  loadBalanced,
 		}
 		if mechanism == "" {
 			// Required for SASL mechanism negotiation during handshake
@@ -279,6 +465,23 @@ func (c *Client) configure(opts *options.ClientOptions) error {
 		}
 	}
 	connOpts = append(connOpts, connection.WithHandshaker(handshaker))
+	// CheckWriteConcern
+	if opts.CheckWriteConcern != nil {
+		c.checkWC = *opts.CheckWriteConcern
+	}
+	// CommandInterceptors
+	if len(opts.CommandInterceptors) > 0 {
+		intercept := chainCommandInterceptors(opts.CommandInterceptors)
+		serverOpts = append(serverOpts, topology.WithCommandInterceptor(
+			func(topology.CommandInterceptor) topology.CommandInterceptor { return intercept },
+		))
+	}
+	// ConnectionMonitor
+	if opts.ConnectionMonitor != nil {
+		connOpts = append(connOpts, connection.WithConnectionMonitor(
+			func(*event.ConnectionMonitor) *event.ConnectionMonitor { return opts.ConnectionMonitor },
+		))
+	}
 	// ConnectTimeout
 	if opts.ConnectTimeout != nil {
 		serverOpts = append(serverOpts, topology.WithHeartbeatTimeout(
@@ -288,14 +491,42 @@ func (c *Client) configure(opts *options.ClientOptions) error {
 			func(time.Duration) time.Duration { return *opts.ConnectTimeout },
 		))
 	}
+	// DiagnosticEventCount
+	eventCount := defaultDiagnosticEventCount
+	if opts.DiagnosticEventCount != nil {
+		eventCount = *opts.DiagnosticEventCount
+	}
+	c.events = newEventRing(eventCount)
 	// Dialer
 	if opts.Dialer != nil {
 		connOpts = append(connOpts, connection.WithDialer(
 			func(connection.Dialer) connection.Dialer { return opts.Dialer },
 		))
 	}
+	// DNSResolver
+	if opts.DNSResolver != nil {
+		connOpts = append(connOpts, connection.WithHostResolver(
+			func(connection.HostResolver) connection.HostResolver { return opts.DNSResolver },
+		))
+	}
+	// DNSCacheTTL wraps the resolver set above, so the cache is shared by all connections.
+	if opts.DNSCacheTTL != nil && *opts.DNSCacheTTL > 0 {
+		var resolver connection.HostResolver = net.DefaultResolver
+		if opts.DNSResolver != nil {
+			resolver = opts.DNSResolver
+		}
+		cache := connection.NewHostCache(resolver, *opts.DNSCacheTTL)
+		connOpts = append(connOpts, connection.WithHostResolver(
+			func(connection.HostResolver) connection.HostResolver { return cache },
+		))
+	}
+	// DocumentValidator
+	c.docValidator = opts.DocumentValidator
 	// Direct
 	if opts.Direct != nil && *opts.Direct {
+		if len(opts.Hosts) > 1 {
+			return ErrDirectConnectionMultipleHosts
+		}
 		topologyOpts = append(topologyOpts, topology.WithMode(
 			func(topology.MonitorMode) topology.MonitorMode { return topology.SingleMode },
 		))
@@ -314,28 +545,100 @@ func (c *Client) configure(opts *options.ClientOptions) error {
 	topologyOpts = append(topologyOpts, topology.WithSeedList(
 		func(...string) []string { return hosts },
 	))
+	// LoadBalanced
+	if loadBalanced {
+		if len(hosts) > 1 || opts.ReplicaSet != nil || (opts.Direct != nil && *opts.Direct) {
+			return ErrInvalidLoadBalancedOptions
+		}
+		topologyOpts = append(topologyOpts, topology.WithMode(
+			func(topology.MonitorMode) topology.MonitorMode { return topology.LoadBalancedMode },
+		))
+	}
 	// LocalThreshold
 	if opts.LocalThreshold != nil {
 		c.localThreshold = *opts.LocalThreshold
 	}
+	// LoggerOptions
+	lo := options.MergeLoggerOptions(opts.LoggerOptions)
+	var maxDocumentLength uint
+	if lo.MaxDocumentLength != nil {
+		maxDocumentLength = *lo.MaxDocumentLength
+	}
+	var logOpts []logger.Option
+	if lo.StructuredTruncation != nil && *lo.StructuredTruncation {
+		logOpts = append(logOpts, logger.WithStructuredTruncation())
+	}
+	log, err := logger.New(lo.Sink, maxDocumentLength, lo.ComponentLevels, logOpts...)
+	if err != nil {
+		return err
+	}
+	c.logger = log
+	if log != nil {
+		topologyOpts = append(topologyOpts, topology.WithLogger(
+			func(*logger.Logger) *logger.Logger { return log },
+		))
+	}
 	// MaxConIdleTime
 	if opts.MaxConnIdleTime != nil {
 		connOpts = append(connOpts, connection.WithIdleTimeout(
 			func(time.Duration) time.Duration { return *opts.MaxConnIdleTime },
 		))
 	}
+	// MaxInFlightOperations
+	if opts.MaxInFlightOperations != nil && *opts.MaxInFlightOperations > 0 {
+		limiter := topology.NewOperationLimiter(int(*opts.MaxInFlightOperations))
+		serverOpts = append(serverOpts, topology.WithOperationLimiter(
+			func(*topology.OperationLimiter) *topology.OperationLimiter { return limiter },
+		))
+	}
 	// MaxPoolSize
 	if opts.MaxPoolSize != nil {
+		// Pools without a max size must still be able to hold their min size of idle connections.
+		maxIdle := *opts.MaxPoolSize
+		if maxIdle == 0 && opts.MinPoolSize != nil {
+			maxIdle = *opts.MinPoolSize
+		}
 		serverOpts = append(
 			serverOpts,
 			topology.WithMaxConnections(func(uint16) uint16 { return *opts.MaxPoolSize }),
-			topology.WithMaxIdleConnections(func(uint16) uint16 { return *opts.MaxPoolSize }),
+			topology.WithMaxIdleConnections(func(uint16) uint16 { return maxIdle }),
 		)
 	}
+	// MinPoolSize
+	if opts.MinPoolSize != nil {
+		if opts.MaxPoolSize != nil && *opts.MaxPoolSize != 0 && *opts.MinPoolSize > *opts.MaxPoolSize {
+			return ErrMinPoolSizeGreaterThanMax
+		}
+		connOpts = append(connOpts, connection.WithMinPoolSize(
+			func(uint64) uint64 { return uint64(*opts.MinPoolSize) },
+		))
+	}
 	// Monitor
-	if opts.Monitor != nil {
+	if monitor := c.events.commandMonitor(c.logger.CommandMonitor(opts.Monitor)); monitor != nil {
 		connOpts = append(connOpts, connection.WithMonitor(
-			func(*event.CommandMonitor) *event.CommandMonitor { return opts.Monitor },
+			func(*event.CommandMonitor) *event.CommandMonitor { return monitor },
+		))
+	}
+	// OperationGate
+	if opts.OperationGate != nil {
+		gate := opts.OperationGate
+		serverOpts = append(serverOpts, topology.WithOperationGate(
+			func(topology.OperationGate) topology.OperationGate {
+				return func(ctx context.Context, desc description.Server, database, command string) (func(error), error) {
+					return gate.Enter(ctx, options.OperationInfo{
+						ServerAddress: desc.Addr.String(),
+						ServerKind:    desc.Kind.String(),
+						DatabaseName:  database,
+						CommandName:   command,
+					})
+				}
+			},
+		))
+	}
+	// PoolMonitor
+	if poolMonitor := c.events.poolMonitor(c.logger.PoolMonitor(opts.PoolMonitor)); poolMonitor != nil {
+		connOpts = append(connOpts, connection.WithPoolMonitor(
+			func(*event.PoolMonitor) *event.PoolMonitor { return poolMonitor },
 		))
 	}
 	// ReadConcern
@@ -343,6 +646,10 @@ func (c *Client) configure(opts *options.ClientOptions) error {
 	if opts.ReadConcern != nil {
 		c.readConcern = opts.ReadConcern
 	}
+	// ReadOnly
+	if opts.ReadOnly != nil {
+		serverOpts = append(serverOpts, topology.WithReadOnly(func(bool) bool { return *opts.ReadOnly }))
+	}
 	// ReadPreference
 	c.readPreference = readpref.Primary()
 	if opts.ReadPreference != nil {
@@ -353,16 +660,50 @@ func (c *Client) configure(opts *options.ClientOptions) error {
 	if opts.Registry != nil {
 		c.registry = opts.Registry
 	}
+	// PipelinePolicies
+	if len(opts.PipelinePolicies) > 0 {
+		c.stagePolicies, err = newPipelinePolicies(c.registry, opts.PipelinePolicies)
+		if err != nil {
+			return err
+		}
+	}
 	// ReplicaSet
 	if opts.ReplicaSet != nil {
 		topologyOpts = append(topologyOpts, topology.WithReplicaSetName(
 			func(string) string { return *opts.ReplicaSet },
 		))
 	}
+	// RetryReads
+	c.retryReads = true
+	if opts.RetryReads != nil {
+		c.retryReads = *opts.RetryReads
+	}
+	// RetryWriteConcernTimeouts
+	if opts.RetryWriteConcernTimeouts != nil {
+		retry := *opts.RetryWriteConcernTimeouts
+		topologyOpts = append(topologyOpts, topology.WithRetryWriteConcernTimeouts(func(bool) bool { return retry }))
+	}
 	// RetryWrites
 	if opts.RetryWrites != nil {
 		c.retryWrites = *opts.RetryWrites
 	}
+	// ServerAPIOptions
+	if opts.ServerAPIOptions != nil {
+		serverAPI := &connection.ServerAPI{
+			Version:           opts.ServerAPIOptions.ServerAPIVersion,
+			Strict:            opts.ServerAPIOptions.Strict,
+			DeprecationErrors: opts.ServerAPIOptions.DeprecationErrors,
+		}
+		connOpts = append(connOpts, connection.WithServerAPI(
+			func(*connection.ServerAPI) *connection.ServerAPI { return serverAPI },
+		))
+	}
+	// ServerMonitor
+	if serverMonitor := c.events.serverMonitor(c.logger.ServerMonitor(opts.ServerMonitor)); serverMonitor != nil {
+		serverOpts = append(serverOpts, topology.WithServerMonitor(
+			func(*event.ServerMonitor) *event.ServerMonitor { return serverMonitor },
+		))
+	}
 	// ServerSelectionTimeout
 	if opts.ServerSelectionTimeout != nil {
 		topologyOpts = append(topologyOpts, topology.WithServerSelectionTimeout(
@@ -377,6 +718,26 @@ func (c *Client) configure(opts *options.ClientOptions) error {
 			connection.WithWriteTimeout(func(time.Duration) time.Duration { return *opts.SocketTimeout }),
 		)
 	}
+	// SRVMaxHosts
+	if opts.SRVMaxHosts != nil {
+		topologyOpts = append(topologyOpts, topology.WithSRVMaxHosts(
+			func(int) int { return *opts.SRVMaxHosts },
+		))
+	}
+	// SRVRemovalThreshold
+	if opts.SRVRemovalThreshold != nil {
+		topologyOpts = append(topologyOpts, topology.WithSRVRemovalThreshold(
+			func(int) int { return *opts.SRVRemovalThreshold },
+		))
+	}
+	// SRVServiceName
+	if opts.SRVServiceName != nil {
+		topologyOpts = append(topologyOpts, topology.WithSRVServiceName(
+			func(string) string { return *opts.SRVServiceName },
+		))
+	}
+	// Timeout
+	c.timeout = opts.Timeout
 	// TLSConfig
 	if opts.TLSConfig != nil {
 		connOpts = append(connOpts, connection.WithTLSConfig(
@@ -384,12 +745,46 @@ func (c *Client) configure(opts *options.ClientOptions) error {
 				return &connection.TLSConfig{Config: opts.TLSConfig}
 			},
 		))
+
+		if opts.DisableCertificateRevocationCheck == nil || !*opts.DisableCertificateRevocationCheck {
+			ocspCfg := &ocsp.Config{Cache: ocsp.NewCache()}
+			if opts.DisableOCSPEndpointCheck != nil {
+				ocspCfg.DisableEndpointChecking = *opts.DisableOCSPEndpointCheck
+			}
+			connOpts = append(connOpts, connection.WithOCSP(
+				func(*ocsp.Config) *ocsp.Config { return ocspCfg },
+			))
+		}
+	}
+	// URI
+	if uri := opts.GetURI(); uri != "" {
+		topologyOpts = append(topologyOpts, topology.WithURI(func(string) string { return uri }))
+	}
+	// Tracer
+	if opts.Tracer != nil {
+		tracer := opts.Tracer
+		topologyOpts = append(topologyOpts, topology.WithTracer(
+			func(event.Tracer) event.Tracer { return tracer },
+		))
+		serverOpts = append(serverOpts, topology.WithServerTracer(
+			func(event.Tracer) event.Tracer { return tracer },
+		))
 	}
 	// WriteConcern
 	if opts.WriteConcern != nil {
 		c.writeConcern = opts.WriteConcern
 	}
 
+	// AutoEncryptionOptions
+	if opts.AutoEncryptionOptions != nil {
+		if err := c.configureAutoEncryption(opts); err != nil {
+			return err
+		}
+		connOpts = append(connOpts, connection.WithCrypt(
+			func(connection.Crypt) connection.Crypt { return c.crypt },
+		))
+	}
+
 	// ClusterClock
 	c.clock = new(session.ClusterClock)
 
@@ -405,6 +800,85 @@ func (c *Client) configure(opts *options.ClientOptions) error {
 	return nil
 }
 
+// configureAutoEncryption creates the Crypt used to automatically encrypt commands and decrypt
+// replies, along with the internal clients it uses to fetch collection information, data keys, and
+// markings from mongocryptd.
+func (c *Client) configureAutoEncryption(clientOpts *options.ClientOptions) error {
+	aeo := clientOpts.AutoEncryptionOptions
+	// Create the MongoCrypt first so that building without the cse tag fails before mongocryptd is
+	// spawned.
+	mongoCrypt, err := newMongoCrypt(c.registry, aeo.KmsProviders, aeo.SchemaMap, aeo.EncryptedFieldsMap)
+	if err != nil {
+		return err
+	}
+
+	cryptOpts, err := c.createCryptOptions(clientOpts, mongoCrypt)
+	if err != nil {
+		mongoCrypt.Close()
+		return err
+	}
+	c.crypt = driverlegacy.NewCrypt(cryptOpts)
+	c.encryptedFieldsMap = aeo.EncryptedFieldsMap
+	return nil
+}
+
+func (c *Client) createCryptOptions(clientOpts *options.ClientOptions, mongoCrypt *mongocrypt.MongoCrypt) (*driverlegacy.CryptOptions, error) {
+	aeo := clientOpts.AutoEncryptionOptions
+
+	internalOpts := *clientOpts
+	internalOpts.AutoEncryptionOptions = nil
+	internalClient, err := NewClient(&internalOpts)
+	if err != nil {
+		return nil, err
+	}
+	c.internalClient = internalClient
+
+	c.keyVaultClient = internalClient
+	if aeo.KeyVaultClientOptions != nil {
+		if c.keyVaultClient, err = NewClient(aeo.KeyVaultClientOptions); err != nil {
+			return nil, err
+		}
+	}
+	keyVaultColl, err := keyVaultCollection(c.keyVaultClient, aeo.KeyVaultNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	cryptOpts := &driverlegacy.CryptOptions{
+		MongoCrypt:           mongoCrypt,
+		CollInfoFn:           (&collInfoRetriever{client: internalClient}).cryptCollInfo,
+		CollInfoCacheTTL:     defaultSchemaCacheTTL,
+		KeyFn:                (&keyRetriever{coll: keyVaultColl}).cryptKeys,
+		BypassAutoEncryption: aeo.BypassAutoEncryption != nil && *aeo.BypassAutoEncryption,
+	}
+	if aeo.SchemaCacheTTL != nil {
+		cryptOpts.CollInfoCacheTTL = *aeo.SchemaCacheTTL
+	}
+	// Commands are never marked when auto encryption is bypassed, so mongocryptd is not needed.
+	if !cryptOpts.BypassAutoEncryption {
+		if c.mongocryptd, err = newMcryptClient(aeo); err != nil {
+			return nil, err
+		}
+		cryptOpts.MarkFn = c.mongocryptd.markCommand
+	}
+	return cryptOpts, nil
+}
+
+// encryptionClients returns the internal clients used for automatic encryption.
+func (c *Client) encryptionClients() []*Client {
+	var clients []*Client
+	if c.internalClient != nil {
+		clients = append(clients, c.internalClient)
+	}
+	if c.keyVaultClient != nil && c.keyVaultClient != c.internalClient {
+		clients = append(clients, c.keyVaultClient)
+	}
+	if c.mongocryptd != nil {
+		clients = append(clients, c.mongocryptd.client)
+	}
+	return clients
+}
+
 // validSession returns an error if the session doesn't belong to the client
 func (c *Client) validSession(sess *session.Client) error {
 	if sess != nil && !uuid.Equal(sess.ClientID, c.id) {
@@ -413,6 +887,24 @@ func (c *Client) validSession(sess *session.Client) error {
 	return nil
 }
 
+// operationContext returns ctx, or context.Background if ctx is nil, limited to timeout, the
+// timeout of the client running the operation. If ctx carries a lazy session, the operation holds
+// it until it completes. The returned CancelFunc must be called once the operation completes.
+func operationContext(ctx context.Context, timeout *time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, release := useLazySession(ctx)
+	if timeout == nil || *timeout <= 0 {
+		return ctx, release
+	}
+	ctx, cancel := connection.WithOperationTimeout(ctx, *timeout)
+	return ctx, func() {
+		cancel()
+		release()
+	}
+}
+
 // Database returns a handle for a given database.
 func (c *Client) Database(name string, opts ...*options.DatabaseOptions) *Database {
 	return newDatabase(c, name, opts...)
@@ -420,9 +912,8 @@ func (c *Client) Database(name string, opts ...*options.DatabaseOptions) *Databa
 
 // ListDatabases returns a ListDatabasesResult.
 func (c *Client) ListDatabases(ctx context.Context, filter interface{}, opts ...*options.ListDatabasesOptions) (ListDatabasesResult, error) {
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, cancel := operationContext(ctx, c.timeout)
+	defer cancel()
 
 	sess := sessionFromContext(ctx)
 
@@ -454,6 +945,7 @@ func (c *Client) ListDatabases(ctx context.Context, filter interface{}, opts ...
 		c.topology.SessionPool,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 	if err != nil {
 		return ListDatabasesResult{}, replaceErrors(err)
 	}
@@ -537,3 +1029,64 @@ func (c *Client) Watch(ctx context.Context, pipeline interface{},
 
 	return newClientChangeStream(ctx, c, pipeline, opts...)
 }
+
+// chainCommandInterceptors returns a topology.CommandInterceptor that calls the BeforeSend methods of
+// interceptors in order and the AfterReply methods of those that succeeded in reverse order.
+func chainCommandInterceptors(interceptors []options.CommandInterceptor) topology.CommandInterceptor {
+	return func(ctx context.Context, desc description.Server, database string, cmd bson.Raw) (bson.Raw, func(bson.Raw, error), error) {
+		info := options.OperationInfo{
+			ServerAddress: desc.Addr.String(),
+			ServerKind:    desc.Kind.String(),
+			DatabaseName:  database,
+		}
+		called := make([]options.CommandInterceptor, 0, len(interceptors))
+		afterReply := func(reply bson.Raw, err error) {
+			for i := len(called) - 1; i >= 0; i-- {
+				called[i].AfterReply(ctx, info, reply, err)
+			}
+		}
+
+		var modified bson.Raw
+		for _, interceptor := range interceptors {
+			if elem, err := cmd.IndexErr(0); err == nil {
+				info.CommandName = elem.Key()
+			}
+			next, err := interceptor.BeforeSend(ctx, info, cmd)
+			if err != nil {
+				afterReply(nil, err)
+				return nil, nil, err
+			}
+			called = append(called, interceptor)
+			if next != nil {
+				cmd, modified = next, next
+			}
+		}
+		return modified, afterReply, nil
+	}
+}
+
+// oidcCallback adapts an options.OIDCCallback to an auth.OIDCCallback.
+func oidcCallback(callback options.OIDCCallback) auth.OIDCCallback {
+	if callback == nil {
+		return nil
+	}
+	return func(ctx context.Context, args *auth.OIDCArgs) (*auth.OIDCCredential, error) {
+		optsArgs := &options.OIDCArgs{Version: args.Version, RefreshToken: args.RefreshToken}
+		if args.IDPInfo != nil {
+			optsArgs.IDPInfo = &options.IDPInfo{
+				Issuer:        args.IDPInfo.Issuer,
+				ClientID:      args.IDPInfo.ClientID,
+				RequestScopes: args.IDPInfo.RequestScopes,
+			}
+		}
+		cred, err := callback(ctx, optsArgs)
+		if err != nil || cred == nil {
+			return nil, err
+		}
+		return &auth.OIDCCredential{
+			AccessToken:  cred.AccessToken,
+			ExpiresAt:    cred.ExpiresAt,
+			RefreshToken: cred.RefreshToken,
+		}, nil
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/mongocrypt"
+)
+
+// ClientEncryption is used to create data keys and explicitly encrypt and decrypt BSON values.
+// It requires the driver to be built with the cse build tag.
+type ClientEncryption struct {
+	crypt          *driverlegacy.Crypt
+	keyVaultClient *Client
+	keyVaultColl   *Collection
+}
+
+// NewClientEncryption creates a new ClientEncryption instance configured with the given options.
+// keyVaultClient is used to read and write data keys and must already be connected.
+func NewClientEncryption(keyVaultClient *Client, opts ...*options.ClientEncryptionOptions) (*ClientEncryption, error) {
+	if keyVaultClient == nil {
+		return nil, errors.New("keyVaultClient must not be nil")
+	}
+
+	ceo := options.MergeClientEncryptionOptions(opts...)
+	mongoCrypt, err := newMongoCrypt(keyVaultClient.registry, ceo.KmsProviders, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	keyVaultColl, err := keyVaultCollection(keyVaultClient, ceo.KeyVaultNamespace)
+	if err != nil {
+		mongoCrypt.Close()
+		return nil, err
+	}
+
+	return &ClientEncryption{
+		crypt: driverlegacy.NewCrypt(&driverlegacy.CryptOptions{
+			MongoCrypt: mongoCrypt,
+			KeyFn:      (&keyRetriever{coll: keyVaultColl}).cryptKeys,
+		}),
+		keyVaultClient: keyVaultClient,
+		keyVaultColl:   keyVaultColl,
+	}, nil
+}
+
+// CreateDataKey creates a new data key with the given KMS provider, either "aws" or "local",
+// inserts it into the key vault collection, and returns its _id.
+func (ce *ClientEncryption) CreateDataKey(ctx context.Context, kmsProvider string, opts ...*options.DataKeyOptions) (primitive.Binary, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	dko := options.MergeDataKeyOptions(opts...)
+	coreOpts := &mongocrypt.DataKeyOptions{
+		KeyAltNames: dko.KeyAltNames,
+	}
+	if dko.MasterKey != nil {
+		masterKey, err := bson.MarshalWithRegistry(ce.keyVaultClient.registry, dko.MasterKey)
+		if err != nil {
+			return primitive.Binary{}, MarshalError{Value: dko.MasterKey, Err: err}
+		}
+		coreOpts.MasterKey = masterKey
+	}
+
+	dataKey, err := ce.crypt.CreateDataKey(ctx, kmsProvider, coreOpts)
+	if err != nil {
+		return primitive.Binary{}, replaceErrors(err)
+	}
+
+	if _, err = ce.keyVaultColl.InsertOne(ctx, bson.Raw(dataKey)); err != nil {
+		return primitive.Binary{}, err
+	}
+
+	subtype, data, ok := dataKey.Lookup("_id").BinaryOK()
+	if !ok {
+		return primitive.Binary{}, errors.New("data key _id is not a binary value")
+	}
+	return primitive.Binary{Subtype: subtype, Data: data}, nil
+}
+
+// Encrypt encrypts val with the data key and algorithm given in opts. The returned value is a
+// BSON binary of subtype 6.
+func (ce *ClientEncryption) Encrypt(ctx context.Context, val bson.RawValue, opts ...*options.EncryptOptions) (primitive.Binary, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	coreOpts := explicitEncryptionOptions(options.MergeEncryptOptions(opts...))
+	subtype, data, err := ce.crypt.EncryptExplicit(ctx, bsoncore.Value{Type: val.Type, Data: val.Value}, coreOpts)
+	if err != nil {
+		return primitive.Binary{}, replaceErrors(err)
+	}
+	return primitive.Binary{Subtype: subtype, Data: data}, nil
+}
+
+// EncryptExpression encrypts the bounds of expr, a range query expression on a field encrypted with
+// the Range algorithm, and decodes the encrypted expression into result. expr must be of the form
+// {$and: [{<field>: {$gt: <min>}}, {<field>: {$lt: <max>}}]}, where $gte and $lte may be used
+// instead, or the equivalent aggregation expression. The Algorithm and QueryType of opts must be
+// AlgorithmRange and QueryTypeRange.
+func (ce *ClientEncryption) EncryptExpression(ctx context.Context, expr interface{}, result interface{}, opts ...*options.EncryptOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	exprDoc, err := bson.MarshalWithRegistry(ce.keyVaultClient.registry, expr)
+	if err != nil {
+		return MarshalError{Value: expr, Err: err}
+	}
+
+	coreOpts := explicitEncryptionOptions(options.MergeEncryptOptions(opts...))
+	encrypted, err := ce.crypt.EncryptExplicitExpression(ctx, exprDoc, coreOpts)
+	if err != nil {
+		return replaceErrors(err)
+	}
+	return bson.UnmarshalWithRegistry(ce.keyVaultClient.registry, encrypted, result)
+}
+
+// Decrypt decrypts val, a value encrypted by Encrypt or by automatic encryption.
+func (ce *ClientEncryption) Decrypt(ctx context.Context, val primitive.Binary) (bson.RawValue, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	decrypted, err := ce.crypt.DecryptExplicit(ctx, val.Subtype, val.Data)
+	if err != nil {
+		return bson.RawValue{}, replaceErrors(err)
+	}
+	return bson.RawValue{Type: decrypted.Type, Value: decrypted.Data}, nil
+}
+
+// RewrapManyDataKey decrypts the data keys matched by filter and encrypts them again with the master
+// key given in opts, or with their current master keys if no provider is given, and updates them in
+// the key vault collection. This allows master keys to be rotated without decrypting and encrypting
+// the values encrypted with the data keys. The result is nil if no data keys match filter.
+func (ce *ClientEncryption) RewrapManyDataKey(ctx context.Context, filter interface{},
+	opts ...*options.RewrapManyDataKeyOptions) (*RewrapManyDataKeyResult, error) {
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rmdko := options.MergeRewrapManyDataKeyOptions(opts...)
+	if rmdko.MasterKey != nil && rmdko.Provider == nil {
+		return nil, errors.New("a provider must be set to rewrap data keys with a new master key")
+	}
+
+	filterDoc, err := bson.MarshalWithRegistry(ce.keyVaultClient.registry, filter)
+	if err != nil {
+		return nil, MarshalError{Value: filter, Err: err}
+	}
+
+	coreOpts := &mongocrypt.RewrapManyDataKeyOptions{}
+	if rmdko.Provider != nil {
+		coreOpts.Provider = *rmdko.Provider
+	}
+	if rmdko.MasterKey != nil {
+		masterKey, err := bson.MarshalWithRegistry(ce.keyVaultClient.registry, rmdko.MasterKey)
+		if err != nil {
+			return nil, MarshalError{Value: rmdko.MasterKey, Err: err}
+		}
+		coreOpts.MasterKey = masterKey
+	}
+
+	keys, err := ce.crypt.RewrapDataKey(ctx, filterDoc, coreOpts)
+	if err != nil {
+		return nil, replaceErrors(err)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	models := make([]WriteModel, 0, len(keys))
+	for _, key := range keys {
+		models = append(models, NewUpdateOneModel().
+			SetFilter(bson.D{{"_id", toRawValue(key.Lookup("_id"))}}).
+			SetUpdate(bson.D{
+				{"$set", bson.D{
+					{"masterKey", toRawValue(key.Lookup("masterKey"))},
+					{"keyMaterial", toRawValue(key.Lookup("keyMaterial"))},
+				}},
+				{"$currentDate", bson.D{{"updateDate", true}}},
+			}))
+	}
+
+	bwr, err := ce.keyVaultColl.BulkWrite(ctx, models)
+	return &RewrapManyDataKeyResult{BulkWriteResult: bwr}, err
+}
+
+// Close cleans up any resources associated with the ClientEncryption instance. It does not
+// disconnect the key vault client.
+func (ce *ClientEncryption) Close(ctx context.Context) error {
+	ce.crypt.Close()
+	return nil
+}
+
+// explicitEncryptionOptions converts eo to the options used by libmongocrypt.
+func explicitEncryptionOptions(eo *options.EncryptOptions) *mongocrypt.ExplicitEncryptionOptions {
+	coreOpts := &mongocrypt.ExplicitEncryptionOptions{
+		KeyID:            eo.KeyID,
+		KeyAltName:       eo.KeyAltName,
+		Algorithm:        eo.Algorithm,
+		QueryType:        eo.QueryType,
+		ContentionFactor: eo.ContentionFactor,
+	}
+	if ro := eo.RangeOptions; ro != nil {
+		coreOpts.RangeOptions = &mongocrypt.ExplicitRangeOptions{
+			Sparsity:  ro.Sparsity,
+			Precision: ro.Precision,
+		}
+		if ro.Min != nil {
+			coreOpts.RangeOptions.Min = &bsoncore.Value{Type: ro.Min.Type, Data: ro.Min.Value}
+		}
+		if ro.Max != nil {
+			coreOpts.RangeOptions.Max = &bsoncore.Value{Type: ro.Max.Type, Data: ro.Max.Value}
+		}
+	}
+	return coreOpts
+}
+
+// toRawValue converts a bsoncore.Value to a bson.RawValue.
+func toRawValue(val bsoncore.Value) bson.RawValue {
+	return bson.RawValue{Type: val.Type, Value: val.Data}
+}
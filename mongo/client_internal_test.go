@@ -13,6 +13,7 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -27,8 +28,10 @@ import (
 	"go.mongodb.org/mongo-driver/tag"
 	"go.mongodb.org/mongo-driver/x/bsonx"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
 	"go.mongodb.org/mongo-driver/x/network/connstring"
+	"go.mongodb.org/mongo-driver/x/network/description"
 )
 
 func createTestClient(t *testing.T) *Client {
@@ -71,6 +74,32 @@ func TestNewClient(t *testing.T) {
 	require.NotNil(t, c.topology)
 }
 
+func TestClient_Stats(t *testing.T) {
+	c, err := NewClient(options.Client().ApplyURI("mongodb://localhost:27017,localhost:27018").
+		SetMaxPoolSize(7).SetServerSelectionTimeout(100 * time.Millisecond))
+	require.NoError(t, err)
+	require.Empty(t, c.Stats().Servers)
+
+	require.NoError(t, c.Connect(context.Background()))
+	defer func() { _ = c.Disconnect(context.Background()) }()
+
+	stats := c.Stats()
+	require.Len(t, stats.Servers, 2)
+	require.Equal(t, "localhost:27017", stats.Servers[0].Address)
+	require.Equal(t, "localhost:27018", stats.Servers[1].Address)
+	for _, s := range stats.Servers {
+		require.Equal(t, uint64(7), s.Pool.MaxSize)
+		require.Equal(t, uint64(0), s.Pool.InUse)
+		require.Equal(t, len(s.Pool.CheckOutLatency.Bounds), len(s.Pool.CheckOutLatency.Counts))
+	}
+
+	server, ok := c.ServerStats("localhost:27018")
+	require.True(t, ok)
+	require.Equal(t, "localhost:27018", server.Address)
+	_, ok = c.ServerStats("localhost:27019")
+	require.False(t, ok)
+}
+
 func TestClient_Database(t *testing.T) {
 	t.Parallel()
 
@@ -442,7 +471,7 @@ func TestClient_ReadPreference(t *testing.T) {
 		},
 	}
 	baseConnString := testutil.ConnString(t)
-	cs := testutil.AddOptionsToURI(baseConnString.String(), "readpreference=secondary&readPreferenceTags=one:1&readPreferenceTags=two:2&maxStaleness=5")
+	cs := testutil.AddOptionsToURI(baseConnString.String(), "readpreference=secondary&readPreferenceTags=one:1&readPreferenceTags=two:2&maxStaleness=120")
 
 	c, err := NewClient(options.Client().ApplyURI(cs))
 	require.NoError(t, err)
@@ -451,7 +480,7 @@ func TestClient_ReadPreference(t *testing.T) {
 	require.Equal(t, tags, c.readPreference.TagSets())
 	d, flag := c.readPreference.MaxStaleness()
 	require.True(t, flag)
-	require.Equal(t, time.Duration(5)*time.Second, d)
+	require.Equal(t, time.Duration(120)*time.Second, d)
 }
 
 func TestClient_ReadPreferenceAbsent(t *testing.T) {
@@ -545,3 +574,200 @@ func TestClient_Watch_Disconnected(t *testing.T) {
 	require.Nil(t, change)
 	require.Equal(t, err, ErrClientDisconnected)
 }
+
+func TestClient_ResetAfterFork(t *testing.T) {
+	newClient := func(t *testing.T) *Client {
+		c, err := NewClient(
+			options.Client().
+				SetServerSelectionTimeout(1 * time.Millisecond).
+				SetHosts([]string{"not-a-valid-hostanme.wrong:12345"}),
+		)
+		require.NoError(t, err)
+		return c
+	}
+
+	t.Run("disconnected", func(t *testing.T) {
+		c := newClient(t)
+		old := c.topology
+
+		err := c.ResetAfterFork(ctx)
+		require.NoError(t, err)
+		require.True(t, old != c.topology, "expected the topology to be replaced")
+
+		_, err = c.StartSession()
+		require.Equal(t, ErrClientDisconnected, err)
+	})
+	t.Run("connected", func(t *testing.T) {
+		c := newClient(t)
+		require.NoError(t, c.Connect(ctx))
+		old := c.topology
+		oldPool := old.SessionPool
+
+		sess, err := c.StartSession()
+		require.NoError(t, err)
+		sess.EndSession(ctx)
+
+		err = c.ResetAfterFork(ctx)
+		require.NoError(t, err)
+		require.True(t, old != c.topology, "expected the topology to be replaced")
+		require.NotNil(t, c.topology.SessionPool)
+		require.True(t, oldPool != c.topology.SessionPool, "expected the session pool to be replaced")
+
+		_, err = old.SelectServer(ctx, description.WriteSelector())
+		require.Equal(t, topology.ErrTopologyClosed, err)
+
+		require.NoError(t, c.Disconnect(ctx))
+	})
+}
+
+func TestClient_Timeout(t *testing.T) {
+	timeout := 50 * time.Millisecond
+
+	t.Run("operationContext", func(t *testing.T) {
+		ctx, cancel := operationContext(nil, &timeout)
+		defer cancel()
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok, "expected the context to have a deadline")
+		require.True(t, deadline.Sub(time.Now()) <= timeout)
+
+		parent, parentCancel := context.WithTimeout(context.Background(), time.Hour)
+		defer parentCancel()
+		ctx, cancel = operationContext(parent, &timeout)
+		defer cancel()
+		got, _ := ctx.Deadline()
+		want, _ := parent.Deadline()
+		require.Equal(t, want, got, "expected the deadline of the context to be kept")
+
+		zero := time.Duration(0)
+		for _, to := range []*time.Duration{nil, &zero} {
+			ctx, cancel = operationContext(context.Background(), to)
+			cancel()
+			require.Equal(t, context.Background(), ctx)
+		}
+	})
+	t.Run("timeoutError", func(t *testing.T) {
+		otherErr := errors.New("other error")
+		maxTimeErr := CommandError{Code: maxTimeMSExpiredCode, Message: "operation exceeded time limit"}
+
+		ctx, cancel := operationContext(context.Background(), &timeout)
+		defer cancel()
+		require.Equal(t, otherErr, timeoutError(ctx, otherErr))
+		require.Equal(t, TimeoutError{Wrapped: maxTimeErr}, timeoutError(ctx, maxTimeErr))
+		require.Equal(t, maxTimeErr, timeoutError(context.Background(), maxTimeErr),
+			"expected errors without an operation timeout to be unchanged")
+
+		<-ctx.Done()
+		require.Equal(t, TimeoutError{Wrapped: otherErr}, timeoutError(ctx, otherErr))
+		require.Equal(t, TimeoutError{Wrapped: otherErr}, timeoutError(ctx, TimeoutError{Wrapped: otherErr}))
+		require.NoError(t, timeoutError(ctx, nil))
+	})
+}
+
+func TestClient_LoadBalanced(t *testing.T) {
+	invalid := []*options.ClientOptions{
+		options.Client().SetHosts([]string{"localhost:27017", "localhost:27018"}),
+		options.Client().SetReplicaSet("rs0"),
+		options.Client().SetDirect(true),
+	}
+	for _, opts := range invalid {
+		_, err := NewClient(opts.SetLoadBalanced(true))
+		require.Equal(t, ErrInvalidLoadBalancedOptions, err)
+	}
+
+	_, err := NewClient(options.Client().ApplyURI("mongodb://localhost:27017/?loadBalanced=true"))
+	require.NoError(t, err)
+}
+
+func TestClient_MinPoolSize(t *testing.T) {
+	_, err := NewClient(options.Client().SetMinPoolSize(20).SetMaxPoolSize(10))
+	require.Equal(t, ErrMinPoolSizeGreaterThanMax, err)
+
+	_, err = NewClient(options.Client().SetMinPoolSize(20).SetMaxPoolSize(0))
+	require.NoError(t, err)
+	_, err = NewClient(options.Client().ApplyURI("mongodb://localhost:27017/?minPoolSize=5"))
+	require.NoError(t, err)
+}
+
+func TestClient_AppName(t *testing.T) {
+	_, err := NewClient(options.Client().SetAppName(strings.Repeat("a", 129)))
+	require.Equal(t, ErrAppNameTooLong, err)
+
+	_, err = NewClient(options.Client().SetAppName(strings.Repeat("a", 128)))
+	require.NoError(t, err)
+}
+
+func TestClient_RetryStats(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+
+	client.topology.RecordRetry(topology.RetryWrite, 10107, nil)
+	client.topology.RecordRetry(topology.RetryRead, 0, errors.New("network error"))
+	require.Equal(t, RetryStats{
+		Reads:  RetryCounts{Retried: 1, Failed: 1, ErrorCodes: map[int32]uint64{0: 1}},
+		Writes: RetryCounts{Retried: 1, Succeeded: 1, ErrorCodes: map[int32]uint64{10107: 1}},
+	}, client.RetryStats())
+}
+
+func TestClient_DirectConnection(t *testing.T) {
+	_, err := NewClient(options.Client().SetHosts([]string{"localhost:27017", "localhost:27018"}).SetDirect(true))
+	require.Equal(t, ErrDirectConnectionMultipleHosts, err)
+
+	_, err = NewClient(options.Client().ApplyURI("mongodb://localhost:27017/?directConnection=true"))
+	require.NoError(t, err)
+}
+
+type testInterceptor struct {
+	name   string
+	err    error
+	calls  *[]string
+	modify func(bson.Raw) bson.Raw
+}
+
+func (ti testInterceptor) BeforeSend(ctx context.Context, info options.OperationInfo, cmd bson.Raw) (bson.Raw, error) {
+	*ti.calls = append(*ti.calls, "before "+ti.name+" "+info.CommandName)
+	if ti.modify != nil {
+		return ti.modify(cmd), ti.err
+	}
+	return nil, ti.err
+}
+
+func (ti testInterceptor) AfterReply(ctx context.Context, info options.OperationInfo, reply bson.Raw, err error) {
+	*ti.calls = append(*ti.calls, fmt.Sprintf("after %s %v", ti.name, err))
+}
+
+func TestClient_CommandInterceptors(t *testing.T) {
+	cmd, err := bson.Marshal(bson.D{{"find", "coll"}, {"$db", "db"}})
+	require.NoError(t, err)
+	withComment, err := bson.Marshal(bson.D{{"find", "coll"}, {"$db", "db"}, {"comment", "policy"}})
+	require.NoError(t, err)
+	addComment := func(bson.Raw) bson.Raw { return withComment }
+	desc := description.Server{Addr: "localhost:27017"}
+
+	t.Run("chain", func(t *testing.T) {
+		var calls []string
+		intercept := chainCommandInterceptors([]options.CommandInterceptor{
+			testInterceptor{name: "first", calls: &calls, modify: addComment},
+			testInterceptor{name: "second", calls: &calls, modify: func(got bson.Raw) bson.Raw {
+				require.Equal(t, bson.Raw(withComment), got)
+				return nil
+			}},
+		})
+		sent, done, err := intercept(context.Background(), desc, "db", cmd)
+		require.NoError(t, err)
+		require.Equal(t, bson.Raw(withComment), sent)
+		done(nil, nil)
+		require.Equal(t, []string{"before first find", "before second find", "after second <nil>", "after first <nil>"}, calls)
+	})
+	t.Run("rejected", func(t *testing.T) {
+		var calls []string
+		rejected := errors.New("rejected")
+		intercept := chainCommandInterceptors([]options.CommandInterceptor{
+			testInterceptor{name: "first", calls: &calls},
+			testInterceptor{name: "second", calls: &calls, err: rejected},
+			testInterceptor{name: "third", calls: &calls},
+		})
+		_, _, err := intercept(context.Background(), desc, "db", cmd)
+		require.Equal(t, rejected, err)
+		require.Equal(t, []string{"before first find", "before second find", "after first rejected"}, calls)
+	})
+}
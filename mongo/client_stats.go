@@ -0,0 +1,127 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
+)
+
+// LatencyHistogram is a histogram of durations with cumulative buckets, in the form used by
+// Prometheus histograms.
+type LatencyHistogram struct {
+	// Bounds are the upper bounds of the buckets and Counts[i] is the number of durations that were
+	// at most Bounds[i].
+	Bounds []time.Duration
+	Counts []uint64
+
+	// Count is the number of all durations and Sum is their total.
+	Count uint64
+	Sum   time.Duration
+}
+
+// PoolStats is a snapshot of the state of the connection pool of a server.
+type PoolStats struct {
+	MaxSize   uint64 // The maximum number of connections of the pool.
+	Open      uint64 // The number of open connections, idle or in use.
+	InUse     uint64 // The number of connections checked out of the pool.
+	WaitQueue uint64 // The number of checkouts in progress.
+
+	// CheckOutLatency is the histogram of the time taken by successful checkouts, including the time
+	// taken to create a connection when there was no idle one.
+	CheckOutLatency LatencyHistogram
+}
+
+// TrafficStats counts the wire messages exchanged with a server by the connections of its pool and
+// their total size in bytes, as sent over the network, after compression.
+type TrafficStats struct {
+	BytesSent        uint64
+	BytesReceived    uint64
+	MessagesSent     uint64
+	MessagesReceived uint64
+}
+
+// AverageSentSize returns the average size in bytes of the messages sent, or 0 if none were sent.
+func (ts TrafficStats) AverageSentSize() float64 {
+	if ts.MessagesSent == 0 {
+		return 0
+	}
+	return float64(ts.BytesSent) / float64(ts.MessagesSent)
+}
+
+// AverageReceivedSize returns the average size in bytes of the messages received, or 0 if none
+// were received.
+func (ts TrafficStats) AverageReceivedSize() float64 {
+	if ts.MessagesReceived == 0 {
+		return 0
+	}
+	return float64(ts.BytesReceived) / float64(ts.MessagesReceived)
+}
+
+// ServerStats is a snapshot of the state of a server.
+type ServerStats struct {
+	Address    string
+	Kind       string
+	AverageRTT time.Duration // The average round trip time of the heartbeats of the server.
+	Pool       PoolStats
+	Traffic    TrafficStats
+}
+
+// ClientStats is a snapshot of the state of the servers of a client.
+type ClientStats struct {
+	Servers []ServerStats // Sorted by address.
+}
+
+// Stats returns a snapshot of the connection pools, traffic and heartbeat round trip times of the
+// servers the client is connected to. It is cheap enough to be called whenever metrics are scraped.
+func (c *Client) Stats() ClientStats {
+	servers := c.topology.ServerStats()
+	stats := ClientStats{Servers: make([]ServerStats, 0, len(servers))}
+	for _, s := range servers {
+		stats.Servers = append(stats.Servers, serverStatsFromTopology(s))
+	}
+	return stats
+}
+
+// ServerStats returns a snapshot of the state of the server with the given address, and false if
+// the client is not connected to such a server.
+func (c *Client) ServerStats(address string) (ServerStats, bool) {
+	for _, s := range c.topology.ServerStats() {
+		if s.Address.String() == address {
+			return serverStatsFromTopology(s), true
+		}
+	}
+	return ServerStats{}, false
+}
+
+func serverStatsFromTopology(s topology.ServerStats) ServerStats {
+	latency := s.Pool.CheckOutLatency
+	return ServerStats{
+		Address:    s.Address.String(),
+		Kind:       s.Kind.String(),
+		AverageRTT: s.AverageRTT,
+		Pool: PoolStats{
+			MaxSize:   s.Pool.MaxSize,
+			Open:      s.Pool.Open,
+			InUse:     s.Pool.InUse,
+			WaitQueue: s.Pool.WaitQueue,
+			CheckOutLatency: LatencyHistogram{
+				Bounds: latency.Bounds,
+				Counts: latency.Counts,
+				Count:  latency.Count,
+				Sum:    latency.Sum,
+			},
+		},
+		Traffic: TrafficStats{
+			BytesSent:        s.Pool.Traffic.BytesSent,
+			BytesReceived:    s.Pool.Traffic.BytesReceived,
+			MessagesSent:     s.Pool.Traffic.MessagesSent,
+			MessagesReceived: s.Pool.Traffic.MessagesReceived,
+		},
+	}
+}
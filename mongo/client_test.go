@@ -71,6 +71,25 @@ func TestClient(t *testing.T) {
 			t.Errorf("Couldn't configure ReadPreference. got %v; want %v", got, want)
 		}
 	})
+	t.Run("RetryReads defaults to true", func(t *testing.T) {
+		client := new(Client)
+		err := client.configure(options.Client())
+		noerr(t, err)
+		want, got := true, client.retryReads
+		if got != want {
+			t.Errorf("Unexpected default for RetryReads. got %v; want %v", got, want)
+		}
+	})
+	t.Run("Can configure RetryReads", func(t *testing.T) {
+		opts := options.Client().SetRetryReads(false)
+		client := new(Client)
+		err := client.configure(opts)
+		noerr(t, err)
+		want, got := false, client.retryReads
+		if got != want {
+			t.Errorf("Couldn't configure RetryReads. got %v; want %v", got, want)
+		}
+	})
 	t.Run("Can configure RetryWrites", func(t *testing.T) {
 		opts := options.Client().SetRetryWrites(true)
 		client := new(Client)
@@ -96,4 +115,27 @@ func TestClient(t *testing.T) {
 			t.Errorf("Couldn't configure WriteConcern. got %v; want %v", got, want)
 		}
 	})
+	t.Run("CloneWith overrides options", func(t *testing.T) {
+		wc := writeconcern.New(writeconcern.W(1))
+		client, err := NewClient(options.Client().SetWriteConcern(wc).SetTimeout(time.Second))
+		noerr(t, err)
+		rp := readpref.Secondary()
+		clone := client.CloneWith(options.ClientClone().SetReadPreference(rp).SetTimeout(0))
+
+		if clone.topology != client.topology {
+			t.Errorf("Clone does not share the topology of the client.")
+		}
+		if clone.readPreference != rp {
+			t.Errorf("Couldn't override ReadPreference. got %v; want %v", clone.readPreference, rp)
+		}
+		if clone.writeConcern != wc {
+			t.Errorf("WriteConcern not copied from the client. got %v; want %v", clone.writeConcern, wc)
+		}
+		if clone.timeout == nil || *clone.timeout != 0 {
+			t.Errorf("Couldn't override Timeout. got %v; want 0", clone.timeout)
+		}
+		if client.readPreference == rp || *client.timeout != time.Second {
+			t.Errorf("Client was modified by CloneWith.")
+		}
+	})
 }
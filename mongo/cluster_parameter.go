@@ -0,0 +1,128 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// ChangeStreamOptionsParameter is the name of the cluster parameter that configures change streams.
+const ChangeStreamOptionsParameter = "changeStreamOptions"
+
+// ClusterParameter is a cluster parameter, as reported by the getClusterParameter command. Raw holds
+// the whole document, including the fields specific to the parameter.
+type ClusterParameter struct {
+	Name                 string              `bson:"_id"`
+	ClusterParameterTime primitive.Timestamp `bson:"clusterParameterTime"`
+	Raw                  bson.Raw            `bson:"-"`
+
+	registry *bsoncodec.Registry
+}
+
+// Decode unmarshals the parameter document into v.
+func (p *ClusterParameter) Decode(v interface{}) error {
+	return bson.UnmarshalWithRegistry(p.registry, p.Raw, v)
+}
+
+// ChangeStreamOptions is the value of the changeStreamOptions cluster parameter.
+type ChangeStreamOptions struct {
+	PreAndPostImages struct {
+		// ExpireAfterSeconds is the number of seconds pre- and post-images are kept for, or the
+		// string "off" if they are kept as long as the oplog entries they belong to.
+		ExpireAfterSeconds interface{} `bson:"expireAfterSeconds"`
+	} `bson:"preAndPostImages"`
+}
+
+// GetClusterParameter returns the cluster parameter with the given name. Cluster parameters require
+// server version 6.0 or later.
+//
+// See https://docs.mongodb.com/manual/reference/command/getClusterParameter/.
+func (c *Client) GetClusterParameter(ctx context.Context, name string) (*ClusterParameter, error) {
+	params, err := c.getClusterParameters(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(params) == 0 {
+		return nil, ErrNoDocuments
+	}
+	return &params[0], nil
+}
+
+// GetClusterParameters returns the cluster parameters with the given names, or all of them if no
+// names are given.
+//
+// See https://docs.mongodb.com/manual/reference/command/getClusterParameter/.
+func (c *Client) GetClusterParameters(ctx context.Context, names ...string) ([]ClusterParameter, error) {
+	if len(names) == 0 {
+		return c.getClusterParameters(ctx, "*")
+	}
+	return c.getClusterParameters(ctx, names)
+}
+
+func (c *Client) getClusterParameters(ctx context.Context, names interface{}) ([]ClusterParameter, error) {
+	res, err := c.runAdminCommand(ctx, readpref.Primary(), bson.D{{"getClusterParameter", names}})
+	if err != nil {
+		return nil, err
+	}
+	return decodeClusterParameters(c.registry, res)
+}
+
+// SetClusterParameter sets the fields of the cluster parameter with the given name to those of
+// value, which must be a document. Cluster parameters require server version 6.0 or later.
+//
+// See https://docs.mongodb.com/manual/reference/command/setClusterParameter/.
+func (c *Client) SetClusterParameter(ctx context.Context, name string, value interface{}) error {
+	if name == "" {
+		return errors.New("cluster parameter name cannot be empty")
+	}
+	doc, err := transformDocument(c.registry, value)
+	if err != nil {
+		return err
+	}
+	_, err = c.runAdminCommand(ctx, readpref.Primary(), bson.D{{"setClusterParameter", bson.D{{name, doc}}}})
+	return err
+}
+
+// SetChangeStreamPreAndPostImagesExpiration sets how long the pre- and post-images of change
+// streams are kept for. They are kept as long as the oplog entries they belong to if d is 0.
+func (c *Client) SetChangeStreamPreAndPostImagesExpiration(ctx context.Context, d time.Duration) error {
+	var expireAfter interface{} = "off"
+	if d > 0 {
+		expireAfter = int64(d / time.Second)
+	}
+	return c.SetClusterParameter(ctx, ChangeStreamOptionsParameter, bson.D{
+		{"preAndPostImages", bson.D{{"expireAfterSeconds", expireAfter}}},
+	})
+}
+
+// decodeClusterParameters decodes the parameters in the clusterParameters array of a
+// getClusterParameter reply.
+func decodeClusterParameters(registry *bsoncodec.Registry, reply bson.Raw) ([]ClusterParameter, error) {
+	var res struct {
+		Params []bson.Raw `bson:"clusterParameters"`
+	}
+	if err := bson.UnmarshalWithRegistry(registry, reply, &res); err != nil {
+		return nil, err
+	}
+
+	params := make([]ClusterParameter, 0, len(res.Params))
+	for _, doc := range res.Params {
+		p := ClusterParameter{Raw: doc, registry: registry}
+		if err := bson.UnmarshalWithRegistry(registry, doc, &p); err != nil {
+			return nil, err
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
@@ -0,0 +1,41 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDecodeClusterParameters(t *testing.T) {
+	reply, err := bson.Marshal(bson.D{
+		{"clusterParameters", bson.A{
+			bson.D{
+				{"_id", ChangeStreamOptionsParameter},
+				{"clusterParameterTime", primitive.Timestamp{T: 10, I: 1}},
+				{"preAndPostImages", bson.D{{"expireAfterSeconds", int64(3600)}}},
+			},
+			bson.D{{"_id", "auditConfig"}, {"auditAuthorizationSuccess", false}},
+		}},
+		{"ok", 1.0},
+	})
+	require.NoError(t, err)
+
+	params, err := decodeClusterParameters(bson.DefaultRegistry, reply)
+	require.NoError(t, err)
+	require.Len(t, params, 2)
+	require.Equal(t, ChangeStreamOptionsParameter, params[0].Name)
+	require.Equal(t, primitive.Timestamp{T: 10, I: 1}, params[0].ClusterParameterTime)
+	require.Equal(t, "auditConfig", params[1].Name)
+
+	var opts ChangeStreamOptions
+	require.NoError(t, params[0].Decode(&opts))
+	require.Equal(t, int64(3600), opts.PreAndPostImages.ExpireAfterSeconds)
+}
@@ -9,8 +9,11 @@ package mongo
 import (
 	"context"
 	"errors"
+	"io"
+	"reflect"
 	"strings"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
@@ -35,6 +38,9 @@ type Collection struct {
 	readSelector   description.ServerSelector
 	writeSelector  description.ServerSelector
 	registry       *bsoncodec.Registry
+	collation      *options.Collation
+
+	disallowUnknownFields bool
 }
 
 func newCollection(db *Database, name string, opts ...*options.CollectionOptions) *Collection {
@@ -60,6 +66,8 @@ func newCollection(db *Database, name string, opts ...*options.CollectionOptions
 		reg = collOpt.Registry
 	}
 
+	disallowUnknownFields := collOpt.DisallowUnknownFields != nil && *collOpt.DisallowUnknownFields
+
 	readSelector := description.CompositeSelector([]description.ServerSelector{
 		description.ReadPrefSelector(rp),
 		description.LatencySelector(db.client.localThreshold),
@@ -80,6 +88,9 @@ func newCollection(db *Database, name string, opts ...*options.CollectionOptions
 		readSelector:   readSelector,
 		writeSelector:  writeSelector,
 		registry:       reg,
+		collation:      collOpt.Collation,
+
+		disallowUnknownFields: disallowUnknownFields,
 	}
 
 	return coll
@@ -96,6 +107,9 @@ func (coll *Collection) copy() *Collection {
 		readSelector:   coll.readSelector,
 		writeSelector:  coll.writeSelector,
 		registry:       coll.registry,
+		collation:      coll.collation,
+
+		disallowUnknownFields: coll.disallowUnknownFields,
 	}
 }
 
@@ -120,6 +134,14 @@ func (coll *Collection) Clone(opts ...*options.CollectionOptions) (*Collection,
 		copyColl.registry = optsColl.Registry
 	}
 
+	if optsColl.Collation != nil {
+		copyColl.collation = optsColl.Collation
+	}
+
+	if optsColl.DisallowUnknownFields != nil {
+		copyColl.disallowUnknownFields = *optsColl.DisallowUnknownFields
+	}
+
 	copyColl.readSelector = description.CompositeSelector([]description.ServerSelector{
 		description.ReadPrefSelector(copyColl.readPreference),
 		description.LatencySelector(copyColl.client.localThreshold),
@@ -138,6 +160,100 @@ func (coll *Collection) namespace() command.Namespace {
 	return command.NewNamespace(coll.db.name, coll.name)
 }
 
+// validateDocument calls the DocumentValidator of the client, if any, with doc, the document at
+// index of an operation that inserts or replaces documents.
+func (coll *Collection) validateDocument(ctx context.Context, op string, index int, doc bsonx.Doc) error {
+	validator := coll.client.docValidator
+	if validator == nil {
+		return nil
+	}
+	raw, err := doc.MarshalBSON()
+	if err != nil {
+		return err
+	}
+	return coll.validateRawDocument(ctx, op, index, raw)
+}
+
+func (coll *Collection) validateRawDocument(ctx context.Context, op string, index int, raw bson.Raw) error {
+	validator := coll.client.docValidator
+	if validator == nil {
+		return nil
+	}
+	info := options.DocumentInfo{
+		DatabaseName:   coll.db.name,
+		CollectionName: coll.name,
+		Operation:      op,
+		Index:          index,
+	}
+	if err := validator.ValidateDocument(ctx, info, raw); err != nil {
+		return DocumentValidationError{
+			Namespace: coll.db.name + "." + coll.name,
+			Operation: op,
+			Index:     index,
+			Wrapped:   err,
+		}
+	}
+	return nil
+}
+
+// newCursor creates a cursor for bc, created by an operation run with ctx, that decodes documents
+// using the registry and decoding settings of the collection.
+func (coll *Collection) newCursor(ctx context.Context, bc batchCursor) (*Cursor, error) {
+	cursor, err := newCursor(retainSession(ctx, bc), coll.registry)
+	if err != nil {
+		return nil, err
+	}
+	cursor.disallowUnknownFields = coll.disallowUnknownFields
+	cursor.timeout = coll.client.timeout
+	coll.client.cursors.track(cursor)
+	return cursor, nil
+}
+
+// newSingleResult creates a SingleResult for the document doc that decodes it using the registry
+// and decoding settings of the collection.
+func (coll *Collection) newSingleResult(doc bson.Raw) *SingleResult {
+	return &SingleResult{rdr: doc, reg: coll.registry, disallowUnknownFields: coll.disallowUnknownFields}
+}
+
+// readConcernFor returns the read concern for a read operation executed using sess. The read
+// concern rc specified for the operation, if any, overrides the read concern of the collection.
+// Operations in a transaction use the read concern of the transaction, so specifying one for such
+// an operation is an error.
+func (coll *Collection) readConcernFor(sess *session.Client, rc *readconcern.ReadConcern) (*readconcern.ReadConcern, error) {
+	if sess.TransactionRunning() {
+		if rc != nil {
+			return nil, ErrReadConcernInTransaction
+		}
+		return nil, nil
+	}
+	if rc != nil {
+		return rc, nil
+	}
+	return coll.readConcern, nil
+}
+
+// writeConcernFor returns the write concern for a write operation executed using sess. The write
+// concern wc specified for the operation, if any, overrides the write concern of the collection.
+// Operations in a transaction use the write concern of the transaction, so specifying one for
+// such an operation is an error.
+func (coll *Collection) writeConcernFor(sess *session.Client, wc *writeconcern.WriteConcern) (*writeconcern.WriteConcern, error) {
+	if sess.TransactionRunning() {
+		if wc != nil {
+			return nil, ErrWriteConcernInTransaction
+		}
+		return nil, nil
+	}
+	if wc == nil {
+		wc = coll.writeConcern
+	}
+	if coll.client != nil && coll.client.checkWC {
+		if err := checkWriteConcern(coll.client.topology.Description(), wc); err != nil {
+			return nil, err
+		}
+	}
+	return wc, nil
+}
+
 // Database provides access to the database that contains the collection.
 func (coll *Collection) Database() *Database {
 	return coll.db
@@ -153,9 +269,8 @@ func (coll *Collection) BulkWrite(ctx context.Context, models []WriteModel,
 		return nil, ErrEmptySlice
 	}
 
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
 
 	sess := sessionFromContext(ctx)
 
@@ -164,14 +279,42 @@ func (coll *Collection) BulkWrite(ctx context.Context, models []WriteModel,
 		return nil, err
 	}
 
+	insertedIDs := make(map[int64]interface{})
 	dispatchModels := make([]driverlegacy.WriteModel, len(models))
 	for i, model := range models {
 		if model == nil {
 			return nil, ErrNilDocument
 		}
+		if iom, ok := model.(*InsertOneModel); ok {
+			doc, id, err := transformAndEnsureID(coll.registry, iom.Document)
+			if err != nil {
+				return nil, err
+			}
+			if err = coll.validateDocument(ctx, "insert", i, doc); err != nil {
+				return nil, err
+			}
+			dispatchModels[i] = driverlegacy.InsertOneModel{Document: doc}
+			insertedIDs[int64(i)] = id
+			continue
+		}
+		if rom, ok := model.(*ReplaceOneModel); ok && coll.client.docValidator != nil {
+			doc, err := transformDocument(coll.registry, rom.Replacement)
+			if err != nil {
+				return nil, err
+			}
+			if err = coll.validateDocument(ctx, "replace", i, doc); err != nil {
+				return nil, err
+			}
+		}
 		dispatchModels[i] = model.convertModel()
 	}
 
+	bwo := options.MergeBulkWriteOptions(opts...)
+	wc, err := coll.writeConcernFor(sess, bwo.WriteConcern)
+	if err != nil {
+		return nil, err
+	}
+
 	res, err := driverlegacy.BulkWrite(
 		ctx,
 		coll.namespace(),
@@ -182,11 +325,12 @@ func (coll *Collection) BulkWrite(ctx context.Context, models []WriteModel,
 		coll.client.topology.SessionPool,
 		coll.client.retryWrites,
 		sess,
-		coll.writeConcern,
+		wc,
 		coll.client.clock,
 		coll.registry,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 	result := BulkWriteResult{
 		InsertedCount: res.InsertedCount,
 		MatchedCount:  res.MatchedCount,
@@ -196,21 +340,61 @@ func (coll *Collection) BulkWrite(ctx context.Context, models []WriteModel,
 		UpsertedIDs:   res.UpsertedIDs,
 	}
 
-	return &result, replaceErrors(err)
+	err = replaceErrors(err)
+	result.Acknowledged = err != ErrUnacknowledgedWrite
+	ordered := bwo.Ordered == nil || *bwo.Ordered
+	result.InsertedIDs = removeFailedInserts(insertedIDs, err, ordered)
+	return &result, err
+}
+
+// removeFailedInserts removes the indexes of the models that were not written because of err from
+// insertedIDs, the _id fields of the InsertOneModels passed to BulkWrite, and returns it.
+func removeFailedInserts(insertedIDs map[int64]interface{}, err error, ordered bool) map[int64]interface{} {
+	switch conv := err.(type) {
+	case nil:
+	case BulkWriteException:
+		if len(conv.WriteErrors) == 0 {
+			break
+		}
+		first := conv.WriteErrors[0].Index
+		for _, we := range conv.WriteErrors {
+			delete(insertedIDs, int64(we.Index))
+			if we.Index < first {
+				first = we.Index
+			}
+		}
+		// An ordered bulk write stops at the first write error.
+		if ordered {
+			for idx := range insertedIDs {
+				if idx > int64(first) {
+					delete(insertedIDs, idx)
+				}
+			}
+		}
+	default:
+		// The write was either unacknowledged or it is unknown which documents were inserted.
+		if err != ErrUnacknowledgedWrite {
+			return make(map[int64]interface{})
+		}
+	}
+	return insertedIDs
 }
 
 // InsertOne inserts a single document into the collection.
 func (coll *Collection) InsertOne(ctx context.Context, document interface{},
 	opts ...*options.InsertOneOptions) (*InsertOneResult, error) {
 
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+	ctx, server := recordServer(ctx)
 
 	doc, insertedID, err := transformAndEnsureID(coll.registry, document)
 	if err != nil {
 		return nil, err
 	}
+	if err = coll.validateDocument(ctx, "insert", 0, doc); err != nil {
+		return nil, err
+	}
 
 	sess := sessionFromContext(ctx)
 
@@ -219,9 +403,9 @@ func (coll *Collection) InsertOne(ctx context.Context, document interface{},
 		return nil, err
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := coll.writeConcernFor(sess, options.MergeInsertOneOptions(opts...).WriteConcern)
+	if err != nil {
+		return nil, err
 	}
 	oldns := coll.namespace()
 	cmd := command.Insert{
@@ -248,22 +432,99 @@ func (coll *Collection) InsertOne(ctx context.Context, document interface{},
 		coll.client.retryWrites,
 		insertOpts...,
 	)
+	err = timeoutError(ctx, err)
+
+	rr, err := processWriteError(res.WriteConcernError, res.WriteErrors, res.ErrorLabels, err)
+	if rr&rrOne == 0 {
+		return nil, err
+	}
+
+	return &InsertOneResult{
+		InsertedID:   insertedID,
+		Acknowledged: err != ErrUnacknowledgedWrite,
+		ServerToken:  *server,
+		Concerns:     operationConcerns(sess, nil, wc, nil),
+	}, err
+}
+
+// InsertOneFromReader inserts the BSON document read from r. The document is read into a buffer
+// sized from its length prefix and sent to the server without being decoded, so large documents
+// are only held in memory once. An ObjectID _id is added to the document if it does not have one.
+//
+// To build a document incrementally, r can be the reader of an io.Pipe whose writer is used by a
+// bsonrw.ValueWriter created with bsonrw.NewBSONValueWriter.
+func (coll *Collection) InsertOneFromReader(ctx context.Context, r io.Reader,
+	opts ...*options.InsertOneOptions) (*InsertOneResult, error) {
+
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+	ctx, server := recordServer(ctx)
+
+	doc, insertedID, err := readDocumentWithID(coll.registry, r)
+	if err != nil {
+		return nil, err
+	}
+	if err = coll.validateRawDocument(ctx, "insert", 0, doc); err != nil {
+		return nil, err
+	}
+
+	sess := sessionFromContext(ctx)
+
+	err = coll.client.validSession(sess)
+	if err != nil {
+		return nil, err
+	}
+
+	wc, err := coll.writeConcernFor(sess, options.MergeInsertOneOptions(opts...).WriteConcern)
+	if err != nil {
+		return nil, err
+	}
+	oldns := coll.namespace()
+	cmd := command.Insert{
+		NS:           command.Namespace{DB: oldns.DB, Collection: oldns.Collection},
+		RawDocs:      []bson.Raw{doc},
+		WriteConcern: wc,
+		Session:      sess,
+		Clock:        coll.client.clock,
+	}
+
+	insertOpts := make([]*options.InsertManyOptions, len(opts))
+	for i, opt := range opts {
+		insertOpts[i] = options.InsertMany()
+		insertOpts[i].BypassDocumentValidation = opt.BypassDocumentValidation
+	}
+
+	res, err := driverlegacy.Insert(
+		ctx, cmd,
+		coll.client.topology,
+		coll.writeSelector,
+		coll.client.id,
+		coll.client.topology.SessionPool,
+		coll.client.retryWrites,
+		insertOpts...,
+	)
+	err = timeoutError(ctx, err)
 
-	rr, err := processWriteError(res.WriteConcernError, res.WriteErrors, err)
+	rr, err := processWriteError(res.WriteConcernError, res.WriteErrors, res.ErrorLabels, err)
 	if rr&rrOne == 0 {
 		return nil, err
 	}
 
-	return &InsertOneResult{InsertedID: insertedID}, err
+	return &InsertOneResult{
+		InsertedID:   insertedID,
+		Acknowledged: err != ErrUnacknowledgedWrite,
+		ServerToken:  *server,
+		Concerns:     operationConcerns(sess, nil, wc, nil),
+	}, err
 }
 
 // InsertMany inserts the provided documents.
 func (coll *Collection) InsertMany(ctx context.Context, documents []interface{},
 	opts ...*options.InsertManyOptions) (*InsertManyResult, error) {
 
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+	ctx, server := recordServer(ctx)
 
 	if len(documents) == 0 {
 		return nil, ErrEmptySlice
@@ -280,6 +541,9 @@ func (coll *Collection) InsertMany(ctx context.Context, documents []interface{},
 		if err != nil {
 			return nil, err
 		}
+		if err = coll.validateDocument(ctx, "insert", i, bdoc); err != nil {
+			return nil, err
+		}
 
 		docs[i] = bdoc
 		result[i] = insertedID
@@ -292,9 +556,9 @@ func (coll *Collection) InsertMany(ctx context.Context, documents []interface{},
 		return nil, err
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := coll.writeConcernFor(sess, options.MergeInsertManyOptions(opts...).WriteConcern)
+	if err != nil {
+		return nil, err
 	}
 
 	oldns := coll.namespace()
@@ -315,11 +579,12 @@ func (coll *Collection) InsertMany(ctx context.Context, documents []interface{},
 		coll.client.retryWrites,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 
 	switch err {
 	case nil:
 	case command.ErrUnacknowledgedWrite:
-		return &InsertManyResult{InsertedIDs: result}, ErrUnacknowledgedWrite
+		return &InsertManyResult{InsertedIDs: result, ServerToken: *server, Concerns: operationConcerns(sess, nil, wc, nil)}, ErrUnacknowledgedWrite
 	default:
 		return nil, replaceErrors(err)
 	}
@@ -339,20 +604,30 @@ func (coll *Collection) InsertMany(ctx context.Context, documents []interface{},
 		err = BulkWriteException{
 			WriteErrors:       bwErrors,
 			WriteConcernError: convertWriteConcernError(res.WriteConcernError),
+			Labels:            res.ErrorLabels,
 		}
 	}
 
-	return &InsertManyResult{InsertedIDs: result}, err
+	return &InsertManyResult{
+		InsertedIDs:  result,
+		Acknowledged: true,
+		ServerToken:  *server,
+		Concerns:     operationConcerns(sess, nil, wc, nil),
+	}, err
 }
 
 // DeleteOne deletes a single document from the collection.
 func (coll *Collection) DeleteOne(ctx context.Context, filter interface{},
 	opts ...*options.DeleteOptions) (*DeleteResult, error) {
 
-	if ctx == nil {
-		ctx = context.Background()
+	if coll.collation != nil {
+		opts = append([]*options.DeleteOptions{options.Delete().SetCollation(coll.collation)}, opts...)
 	}
 
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+	ctx, server := recordServer(ctx)
+
 	f, err := transformDocument(coll.registry, filter)
 	if err != nil {
 		return nil, err
@@ -371,9 +646,9 @@ func (coll *Collection) DeleteOne(ctx context.Context, filter interface{},
 		return nil, err
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := coll.writeConcernFor(sess, options.MergeDeleteOptions(opts...).WriteConcern)
+	if err != nil {
+		return nil, err
 	}
 
 	oldns := coll.namespace()
@@ -394,22 +669,32 @@ func (coll *Collection) DeleteOne(ctx context.Context, filter interface{},
 		coll.client.retryWrites,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 
-	rr, err := processWriteError(res.WriteConcernError, res.WriteErrors, err)
+	rr, err := processWriteError(res.WriteConcernError, res.WriteErrors, res.ErrorLabels, err)
 	if rr&rrOne == 0 {
 		return nil, err
 	}
-	return &DeleteResult{DeletedCount: int64(res.N)}, err
+	return &DeleteResult{
+		DeletedCount: int64(res.N),
+		Acknowledged: err != ErrUnacknowledgedWrite,
+		ServerToken:  *server,
+		Concerns:     operationConcerns(sess, nil, wc, nil),
+	}, err
 }
 
 // DeleteMany deletes multiple documents from the collection.
 func (coll *Collection) DeleteMany(ctx context.Context, filter interface{},
 	opts ...*options.DeleteOptions) (*DeleteResult, error) {
 
-	if ctx == nil {
-		ctx = context.Background()
+	if coll.collation != nil {
+		opts = append([]*options.DeleteOptions{options.Delete().SetCollation(coll.collation)}, opts...)
 	}
 
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+	ctx, server := recordServer(ctx)
+
 	f, err := transformDocument(coll.registry, filter)
 	if err != nil {
 		return nil, err
@@ -423,9 +708,9 @@ func (coll *Collection) DeleteMany(ctx context.Context, filter interface{},
 		return nil, err
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := coll.writeConcernFor(sess, options.MergeDeleteOptions(opts...).WriteConcern)
+	if err != nil {
+		return nil, err
 	}
 
 	oldns := coll.namespace()
@@ -446,33 +731,43 @@ func (coll *Collection) DeleteMany(ctx context.Context, filter interface{},
 		false,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 
-	rr, err := processWriteError(res.WriteConcernError, res.WriteErrors, err)
+	rr, err := processWriteError(res.WriteConcernError, res.WriteErrors, res.ErrorLabels, err)
 	if rr&rrMany == 0 {
 		return nil, err
 	}
-	return &DeleteResult{DeletedCount: int64(res.N)}, err
+	return &DeleteResult{
+		DeletedCount: int64(res.N),
+		Acknowledged: err != ErrUnacknowledgedWrite,
+		ServerToken:  *server,
+		Concerns:     operationConcerns(sess, nil, wc, nil),
+	}, err
 }
 
-func (coll *Collection) updateOrReplaceOne(ctx context.Context, filter,
-	update bsonx.Doc, sess *session.Client, opts ...*options.UpdateOptions) (*UpdateResult, error) {
+func (coll *Collection) updateOrReplaceOne(ctx context.Context, filter bsonx.Doc,
+	update bsonx.Val, sess *session.Client, opts ...*options.UpdateOptions) (*UpdateResult, error) {
 
 	// TODO: should session be taken from ctx or left as argument?
-	if ctx == nil {
-		ctx = context.Background()
+	if coll.collation != nil {
+		opts = append([]*options.UpdateOptions{options.Update().SetCollation(coll.collation)}, opts...)
 	}
 
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+	ctx, server := recordServer(ctx)
+
 	updateDocs := []bsonx.Doc{
 		{
 			{"q", bsonx.Document(filter)},
-			{"u", bsonx.Document(update)},
+			{"u", update},
 			{"multi", bsonx.Boolean(false)},
 		},
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := coll.writeConcernFor(sess, options.MergeUpdateOptions(opts...).WriteConcern)
+	if err != nil {
+		return nil, err
 	}
 
 	oldns := coll.namespace()
@@ -493,6 +788,7 @@ func (coll *Collection) updateOrReplaceOne(ctx context.Context, filter,
 		coll.client.retryWrites,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 	if err != nil && err != command.ErrUnacknowledgedWrite {
 		return nil, replaceErrors(err)
 	}
@@ -501,41 +797,40 @@ func (coll *Collection) updateOrReplaceOne(ctx context.Context, filter,
 		MatchedCount:  r.MatchedCount,
 		ModifiedCount: r.ModifiedCount,
 		UpsertedCount: int64(len(r.Upserted)),
+		ServerToken:   *server,
+		Concerns:      operationConcerns(sess, nil, wc, nil),
 	}
 	if len(r.Upserted) > 0 {
 		res.UpsertedID = r.Upserted[0].ID
 		res.MatchedCount--
 	}
 
-	rr, err := processWriteError(r.WriteConcernError, r.WriteErrors, err)
+	rr, err := processWriteError(r.WriteConcernError, r.WriteErrors, r.ErrorLabels, err)
 	if rr&rrOne == 0 {
 		return nil, err
 	}
+	res.Acknowledged = err != ErrUnacknowledgedWrite
 	return res, err
 }
 
-// UpdateOne updates a single document in the collection.
+// UpdateOne updates a single document in the collection. The update is either a document of update
+// operators or, for MongoDB 4.2 and later, an aggregation pipeline of update stages.
 func (coll *Collection) UpdateOne(ctx context.Context, filter interface{}, update interface{},
 	opts ...*options.UpdateOptions) (*UpdateResult, error) {
 
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
 
 	f, err := transformDocument(coll.registry, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := transformDocument(coll.registry, update)
+	u, err := transformUpdateValue(coll.registry, update)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := ensureDollarKey(u); err != nil {
-		return nil, err
-	}
-
 	sess := sessionFromContext(ctx)
 
 	err = coll.client.validSession(sess)
@@ -546,32 +841,33 @@ func (coll *Collection) UpdateOne(ctx context.Context, filter interface{}, updat
 	return coll.updateOrReplaceOne(ctx, f, u, sess, opts...)
 }
 
-// UpdateMany updates multiple documents in the collection.
+// UpdateMany updates multiple documents in the collection. The update is either a document of update
+// operators or, for MongoDB 4.2 and later, an aggregation pipeline of update stages.
 func (coll *Collection) UpdateMany(ctx context.Context, filter interface{}, update interface{},
 	opts ...*options.UpdateOptions) (*UpdateResult, error) {
 
-	if ctx == nil {
-		ctx = context.Background()
+	if coll.collation != nil {
+		opts = append([]*options.UpdateOptions{options.Update().SetCollation(coll.collation)}, opts...)
 	}
 
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+	ctx, server := recordServer(ctx)
+
 	f, err := transformDocument(coll.registry, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := transformDocument(coll.registry, update)
+	u, err := transformUpdateValue(coll.registry, update)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = ensureDollarKey(u); err != nil {
-		return nil, err
-	}
-
 	updateDocs := []bsonx.Doc{
 		{
 			{"q", bsonx.Document(f)},
-			{"u", bsonx.Document(u)},
+			{"u", u},
 			{"multi", bsonx.Boolean(true)},
 		},
 	}
@@ -583,9 +879,9 @@ func (coll *Collection) UpdateMany(ctx context.Context, filter interface{}, upda
 		return nil, err
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := coll.writeConcernFor(sess, options.MergeUpdateOptions(opts...).WriteConcern)
+	if err != nil {
+		return nil, err
 	}
 
 	oldns := coll.namespace()
@@ -606,6 +902,7 @@ func (coll *Collection) UpdateMany(ctx context.Context, filter interface{}, upda
 		false,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 	if err != nil && err != command.ErrUnacknowledgedWrite {
 		return nil, replaceErrors(err)
 	}
@@ -613,6 +910,8 @@ func (coll *Collection) UpdateMany(ctx context.Context, filter interface{}, upda
 		MatchedCount:  r.MatchedCount,
 		ModifiedCount: r.ModifiedCount,
 		UpsertedCount: int64(len(r.Upserted)),
+		ServerToken:   *server,
+		Concerns:      operationConcerns(sess, nil, wc, nil),
 	}
 	// TODO(skriptble): Is this correct? Do we only return the first upserted ID for an UpdateMany?
 	if len(r.Upserted) > 0 {
@@ -620,10 +919,11 @@ func (coll *Collection) UpdateMany(ctx context.Context, filter interface{}, upda
 		res.MatchedCount--
 	}
 
-	rr, err := processWriteError(r.WriteConcernError, r.WriteErrors, err)
+	rr, err := processWriteError(r.WriteConcernError, r.WriteErrors, r.ErrorLabels, err)
 	if rr&rrMany == 0 {
 		return nil, err
 	}
+	res.Acknowledged = err != ErrUnacknowledgedWrite
 	return res, err
 }
 
@@ -631,9 +931,8 @@ func (coll *Collection) UpdateMany(ctx context.Context, filter interface{}, upda
 func (coll *Collection) ReplaceOne(ctx context.Context, filter interface{},
 	replacement interface{}, opts ...*options.ReplaceOptions) (*UpdateResult, error) {
 
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
 
 	f, err := transformDocument(coll.registry, filter)
 	if err != nil {
@@ -648,6 +947,9 @@ func (coll *Collection) ReplaceOne(ctx context.Context, filter interface{},
 	if len(r) > 0 && strings.HasPrefix(r[0].Key, "$") {
 		return nil, errors.New("replacement document cannot contains keys beginning with '$")
 	}
+	if err = coll.validateDocument(ctx, "replace", 0, r); err != nil {
+		return nil, err
+	}
 
 	sess := sessionFromContext(ctx)
 
@@ -661,11 +963,15 @@ func (coll *Collection) ReplaceOne(ctx context.Context, filter interface{},
 		uOpts := options.Update()
 		uOpts.BypassDocumentValidation = opt.BypassDocumentValidation
 		uOpts.Collation = opt.Collation
+		uOpts.Comment = opt.Comment
+		uOpts.Hint = opt.Hint
+		uOpts.Let = opt.Let
 		uOpts.Upsert = opt.Upsert
+		uOpts.WriteConcern = opt.WriteConcern
 		updateOptions = append(updateOptions, uOpts)
 	}
 
-	return coll.updateOrReplaceOne(ctx, f, r, sess, updateOptions...)
+	return coll.updateOrReplaceOne(ctx, f, bsonx.Document(r), sess, updateOptions...)
 }
 
 // Aggregate runs an aggregation framework pipeline.
@@ -674,14 +980,18 @@ func (coll *Collection) ReplaceOne(ctx context.Context, filter interface{},
 func (coll *Collection) Aggregate(ctx context.Context, pipeline interface{},
 	opts ...*options.AggregateOptions) (*Cursor, error) {
 
-	if ctx == nil {
-		ctx = context.Background()
+	if coll.collation != nil {
+		opts = append([]*options.AggregateOptions{options.Aggregate().SetCollation(coll.collation)}, opts...)
 	}
 
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+
 	pipelineArr, err := transformAggregatePipeline(coll.registry, pipeline)
 	if err != nil {
 		return nil, err
 	}
+	pipelineArr = applyPipelinePolicies(coll.client.stagePolicies, coll.db.name, coll.name, pipelineArr)
 
 	aggOpts := options.MergeAggregateOptions(opts...)
 
@@ -692,12 +1002,13 @@ func (coll *Collection) Aggregate(ctx context.Context, pipeline interface{},
 		return nil, err
 	}
 
+	rc, err := coll.readConcernFor(sess, aggOpts.ReadConcern)
+	if err != nil {
+		return nil, err
+	}
 	wc := coll.writeConcern
-	rc := coll.readConcern
-
 	if sess.TransactionRunning() {
 		wc = nil
-		rc = nil
 	}
 
 	oldns := coll.namespace()
@@ -719,8 +1030,10 @@ func (coll *Collection) Aggregate(ctx context.Context, pipeline interface{},
 		coll.client.id,
 		coll.client.topology.SessionPool,
 		coll.registry,
+		coll.client.retryReads,
 		aggOpts,
 	)
+	err = timeoutError(ctx, err)
 	if err != nil {
 		if wce, ok := err.(result.WriteConcernError); ok {
 			return nil, *convertWriteConcernError(&wce)
@@ -728,24 +1041,37 @@ func (coll *Collection) Aggregate(ctx context.Context, pipeline interface{},
 		return nil, replaceErrors(err)
 	}
 
-	cursor, err := newCursor(batchCursor, coll.registry)
-	return cursor, replaceErrors(err)
+	cursor, err := coll.newCursor(ctx, batchCursor)
+	if err != nil {
+		return nil, replaceErrors(err)
+	}
+	cursor.concerns = operationConcerns(sess, rc, wc, coll.readPreference)
+	return cursor, nil
 }
 
-// CountDocuments gets the number of documents matching the filter.
+// CountDocuments gets the number of documents matching the filter. It runs an aggregation that matches the
+// filter, applies the skip and limit of the options and counts the remaining documents with a $group stage,
+// so unlike EstimatedDocumentCount it is accurate and can be used in transactions.
 func (coll *Collection) CountDocuments(ctx context.Context, filter interface{},
 	opts ...*options.CountOptions) (int64, error) {
 
-	if ctx == nil {
-		ctx = context.Background()
+	if coll.collation != nil {
+		opts = append([]*options.CountOptions{options.Count().SetCollation(coll.collation)}, opts...)
 	}
 
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+
 	countOpts := options.MergeCountOptions(opts...)
 
 	pipelineArr, err := countDocumentsAggregatePipeline(coll.registry, filter, countOpts)
 	if err != nil {
 		return 0, err
 	}
+	// The stages of the pipeline policies are added around the $match stage of the filter, before the
+	// stages that count the documents.
+	filterStage := applyPipelinePolicies(coll.client.stagePolicies, coll.db.name, coll.name, pipelineArr[:1])
+	pipelineArr = append(filterStage[:len(filterStage):len(filterStage)], pipelineArr[1:]...)
 
 	sess := sessionFromContext(ctx)
 
@@ -754,9 +1080,9 @@ func (coll *Collection) CountDocuments(ctx context.Context, filter interface{},
 		return 0, err
 	}
 
-	rc := coll.readConcern
-	if sess.TransactionRunning() {
-		rc = nil
+	rc, err := coll.readConcernFor(sess, countOpts.ReadConcern)
+	if err != nil {
+		return 0, err
 	}
 
 	oldns := coll.namespace()
@@ -776,19 +1102,23 @@ func (coll *Collection) CountDocuments(ctx context.Context, filter interface{},
 		coll.client.id,
 		coll.client.topology.SessionPool,
 		coll.registry,
+		coll.client.retryReads,
 		countOpts,
 	)
+	err = timeoutError(ctx, err)
 
 	return count, replaceErrors(err)
 }
 
 // EstimatedDocumentCount gets an estimate of the count of documents in a collection using collection metadata.
+// It runs the count command without a filter, which reads the count from the metadata of the collection
+// instead of scanning it, so it is fast but may be inaccurate after an unclean shutdown or while
+// orphaned documents or chunk migrations exist on a sharded cluster.
 func (coll *Collection) EstimatedDocumentCount(ctx context.Context,
 	opts ...*options.EstimatedDocumentCountOptions) (int64, error) {
 
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
 
 	sess := sessionFromContext(ctx)
 
@@ -797,9 +1127,10 @@ func (coll *Collection) EstimatedDocumentCount(ctx context.Context,
 		return 0, err
 	}
 
-	rc := coll.readConcern
-	if sess != nil && (sess.TransactionInProgress()) {
-		rc = nil
+	edco := options.MergeEstimatedDocumentCountOptions(opts...)
+	rc, err := coll.readConcernFor(sess, edco.ReadConcern)
+	if err != nil {
+		return 0, err
 	}
 
 	oldns := coll.namespace()
@@ -813,8 +1144,8 @@ func (coll *Collection) EstimatedDocumentCount(ctx context.Context,
 	}
 
 	countOpts := options.Count()
-	if len(opts) >= 1 {
-		countOpts = countOpts.SetMaxTime(*opts[len(opts)-1].MaxTime)
+	if edco.MaxTime != nil {
+		countOpts = countOpts.SetMaxTime(*edco.MaxTime)
 	}
 
 	count, err := driverlegacy.Count(
@@ -824,8 +1155,10 @@ func (coll *Collection) EstimatedDocumentCount(ctx context.Context,
 		coll.client.id,
 		coll.client.topology.SessionPool,
 		coll.registry,
+		coll.client.retryReads,
 		countOpts,
 	)
+	err = timeoutError(ctx, err)
 
 	return count, replaceErrors(err)
 }
@@ -835,10 +1168,13 @@ func (coll *Collection) EstimatedDocumentCount(ctx context.Context,
 func (coll *Collection) Distinct(ctx context.Context, fieldName string, filter interface{},
 	opts ...*options.DistinctOptions) ([]interface{}, error) {
 
-	if ctx == nil {
-		ctx = context.Background()
+	if coll.collation != nil {
+		opts = append([]*options.DistinctOptions{options.Distinct().SetCollation(coll.collation)}, opts...)
 	}
 
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+
 	f, err := transformDocument(coll.registry, filter)
 	if err != nil {
 		return nil, err
@@ -851,9 +1187,9 @@ func (coll *Collection) Distinct(ctx context.Context, fieldName string, filter i
 		return nil, err
 	}
 
-	rc := coll.readConcern
-	if sess.TransactionRunning() {
-		rc = nil
+	rc, err := coll.readConcernFor(sess, options.MergeDistinctOptions(opts...).ReadConcern)
+	if err != nil {
+		return nil, err
 	}
 
 	oldns := coll.namespace()
@@ -873,8 +1209,10 @@ func (coll *Collection) Distinct(ctx context.Context, fieldName string, filter i
 		coll.readSelector,
 		coll.client.id,
 		coll.client.topology.SessionPool,
+		coll.client.retryReads,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 	if err != nil {
 		return nil, replaceErrors(err)
 	}
@@ -882,14 +1220,50 @@ func (coll *Collection) Distinct(ctx context.Context, fieldName string, filter i
 	return res.Values, nil
 }
 
+// DistinctDecode finds the distinct values for a specified field across a single collection, like
+// Distinct, and decodes them into results using the registry of the collection. The results
+// parameter must be a pointer to a slice, such as a *[]string, which is overwritten with the values.
+func (coll *Collection) DistinctDecode(ctx context.Context, fieldName string, filter interface{}, results interface{},
+	opts ...*options.DistinctOptions) error {
+
+	values, err := coll.Distinct(ctx, fieldName, filter, opts...)
+	if err != nil {
+		return err
+	}
+	return decodeDistinctValues(coll.registry, values, results)
+}
+
+// decodeDistinctValues decodes values into the slice pointed to by results.
+func decodeDistinctValues(registry *bsoncodec.Registry, values []interface{}, results interface{}) error {
+	resultsVal := reflect.ValueOf(results)
+	if resultsVal.Kind() != reflect.Ptr || resultsVal.Elem().Kind() != reflect.Slice {
+		return errors.New("results argument must be a pointer to a slice")
+	}
+
+	doc, err := bson.MarshalWithRegistry(registry, bson.D{{"values", values}})
+	if err != nil {
+		return err
+	}
+	// Decode into a new slice so that results is overwritten rather than appended to.
+	sliceVal := reflect.New(resultsVal.Elem().Type())
+	if err = bson.Raw(doc).Lookup("values").UnmarshalWithRegistry(registry, sliceVal.Interface()); err != nil {
+		return err
+	}
+	resultsVal.Elem().Set(sliceVal.Elem())
+	return nil
+}
+
 // Find finds the documents matching a model.
 func (coll *Collection) Find(ctx context.Context, filter interface{},
 	opts ...*options.FindOptions) (*Cursor, error) {
 
-	if ctx == nil {
-		ctx = context.Background()
+	if coll.collation != nil {
+		opts = append([]*options.FindOptions{options.Find().SetCollation(coll.collation)}, opts...)
 	}
 
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+
 	f, err := transformDocument(coll.registry, filter)
 	if err != nil {
 		return nil, err
@@ -902,9 +1276,9 @@ func (coll *Collection) Find(ctx context.Context, filter interface{},
 		return nil, err
 	}
 
-	rc := coll.readConcern
-	if sess.TransactionRunning() {
-		rc = nil
+	rc, err := coll.readConcernFor(sess, options.MergeFindOptions(opts...).ReadConcern)
+	if err != nil {
+		return nil, err
 	}
 
 	oldns := coll.namespace()
@@ -924,24 +1298,33 @@ func (coll *Collection) Find(ctx context.Context, filter interface{},
 		coll.client.id,
 		coll.client.topology.SessionPool,
 		coll.registry,
+		coll.client.retryReads,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 	if err != nil {
 		return nil, replaceErrors(err)
 	}
 
-	cursor, err := newCursor(batchCursor, coll.registry)
-	return cursor, replaceErrors(err)
+	cursor, err := coll.newCursor(ctx, batchCursor)
+	if err != nil {
+		return nil, replaceErrors(err)
+	}
+	cursor.concerns = operationConcerns(sess, rc, nil, coll.readPreference)
+	return cursor, nil
 }
 
 // FindOne returns up to one document that matches the model.
 func (coll *Collection) FindOne(ctx context.Context, filter interface{},
 	opts ...*options.FindOneOptions) *SingleResult {
 
-	if ctx == nil {
-		ctx = context.Background()
+	if coll.collation != nil {
+		opts = append([]*options.FindOneOptions{options.FindOne().SetCollation(coll.collation)}, opts...)
 	}
 
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+
 	f, err := transformDocument(coll.registry, filter)
 	if err != nil {
 		return &SingleResult{err: err}
@@ -954,10 +1337,11 @@ func (coll *Collection) FindOne(ctx context.Context, filter interface{},
 		return &SingleResult{err: err}
 	}
 
-	rc := coll.readConcern
-	if sess.TransactionRunning() {
-		rc = nil
+	rc, err := coll.readConcernFor(sess, options.MergeFindOneOptions(opts...).ReadConcern)
+	if err != nil {
+		return &SingleResult{err: err}
 	}
+	concerns := operationConcerns(sess, rc, nil, coll.readPreference)
 
 	oldns := coll.namespace()
 	cmd := command.Find{
@@ -978,6 +1362,7 @@ func (coll *Collection) FindOne(ctx context.Context, filter interface{},
 			Comment:             opt.Comment,
 			CursorType:          opt.CursorType,
 			Hint:                opt.Hint,
+			Let:                 opt.Let,
 			Max:                 opt.Max,
 			MaxAwaitTime:        opt.MaxAwaitTime,
 			Min:                 opt.Min,
@@ -999,14 +1384,16 @@ func (coll *Collection) FindOne(ctx context.Context, filter interface{},
 		coll.client.id,
 		coll.client.topology.SessionPool,
 		coll.registry,
+		coll.client.retryReads,
 		findOpts...,
 	)
+	err = timeoutError(ctx, err)
 	if err != nil {
-		return &SingleResult{err: replaceErrors(err)}
+		return &SingleResult{concerns: concerns, err: replaceErrors(err)}
 	}
 
-	cursor, err := newCursor(batchCursor, coll.registry)
-	return &SingleResult{cur: cursor, reg: coll.registry, err: replaceErrors(err)}
+	cursor, err := coll.newCursor(ctx, batchCursor)
+	return &SingleResult{cur: cursor, reg: coll.registry, concerns: concerns, err: replaceErrors(err)}
 }
 
 // FindOneAndDelete find a single document and deletes it, returning the
@@ -1014,10 +1401,14 @@ func (coll *Collection) FindOne(ctx context.Context, filter interface{},
 func (coll *Collection) FindOneAndDelete(ctx context.Context, filter interface{},
 	opts ...*options.FindOneAndDeleteOptions) *SingleResult {
 
-	if ctx == nil {
-		ctx = context.Background()
+	if coll.collation != nil {
+		opts = append([]*options.FindOneAndDeleteOptions{options.FindOneAndDelete().SetCollation(coll.collation)}, opts...)
 	}
 
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+	ctx, server := recordServer(ctx)
+
 	f, err := transformDocument(coll.registry, filter)
 	if err != nil {
 		return &SingleResult{err: err}
@@ -1031,10 +1422,11 @@ func (coll *Collection) FindOneAndDelete(ctx context.Context, filter interface{}
 	}
 
 	oldns := coll.namespace()
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := coll.writeConcernFor(sess, options.MergeFindOneAndDeleteOptions(opts...).WriteConcern)
+	if err != nil {
+		return &SingleResult{err: err}
 	}
+	concerns := operationConcerns(sess, nil, wc, nil)
 
 	cmd := command.FindOneAndDelete{
 		NS:           command.Namespace{DB: oldns.DB, Collection: oldns.Collection},
@@ -1054,16 +1446,20 @@ func (coll *Collection) FindOneAndDelete(ctx context.Context, filter interface{}
 		coll.registry,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 
 	if err != nil {
-		return &SingleResult{err: replaceErrors(err)}
+		return &SingleResult{concerns: concerns, err: replaceErrors(err)}
 	}
 
 	if res.WriteConcernError != nil {
-		return &SingleResult{err: *convertWriteConcernError(res.WriteConcernError)}
+		return &SingleResult{concerns: concerns, err: *convertWriteConcernError(res.WriteConcernError)}
 	}
 
-	return &SingleResult{rdr: res.Value, reg: coll.registry}
+	sr := coll.newSingleResult(res.Value)
+	sr.server = *server
+	sr.concerns = concerns
+	return sr
 }
 
 // FindOneAndReplace finds a single document and replaces it, returning either
@@ -1071,10 +1467,14 @@ func (coll *Collection) FindOneAndDelete(ctx context.Context, filter interface{}
 func (coll *Collection) FindOneAndReplace(ctx context.Context, filter interface{},
 	replacement interface{}, opts ...*options.FindOneAndReplaceOptions) *SingleResult {
 
-	if ctx == nil {
-		ctx = context.Background()
+	if coll.collation != nil {
+		opts = append([]*options.FindOneAndReplaceOptions{options.FindOneAndReplace().SetCollation(coll.collation)}, opts...)
 	}
 
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+	ctx, server := recordServer(ctx)
+
 	f, err := transformDocument(coll.registry, filter)
 	if err != nil {
 		return &SingleResult{err: err}
@@ -1088,6 +1488,9 @@ func (coll *Collection) FindOneAndReplace(ctx context.Context, filter interface{
 	if len(r) > 0 && strings.HasPrefix(r[0].Key, "$") {
 		return &SingleResult{err: errors.New("replacement document cannot contains keys beginning with '$")}
 	}
+	if err = coll.validateDocument(ctx, "replace", 0, r); err != nil {
+		return &SingleResult{err: err}
+	}
 
 	sess := sessionFromContext(ctx)
 
@@ -1096,10 +1499,11 @@ func (coll *Collection) FindOneAndReplace(ctx context.Context, filter interface{
 		return &SingleResult{err: err}
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := coll.writeConcernFor(sess, options.MergeFindOneAndReplaceOptions(opts...).WriteConcern)
+	if err != nil {
+		return &SingleResult{err: err}
 	}
+	concerns := operationConcerns(sess, nil, wc, nil)
 
 	oldns := coll.namespace()
 	cmd := command.FindOneAndReplace{
@@ -1121,43 +1525,45 @@ func (coll *Collection) FindOneAndReplace(ctx context.Context, filter interface{
 		coll.registry,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 	if err != nil {
-		return &SingleResult{err: replaceErrors(err)}
+		return &SingleResult{concerns: concerns, err: replaceErrors(err)}
 	}
 
 	if res.WriteConcernError != nil {
-		return &SingleResult{err: *convertWriteConcernError(res.WriteConcernError)}
+		return &SingleResult{concerns: concerns, err: *convertWriteConcernError(res.WriteConcernError)}
 	}
 
-	return &SingleResult{rdr: res.Value, reg: coll.registry}
+	sr := coll.newSingleResult(res.Value)
+	sr.server = *server
+	sr.concerns = concerns
+	return sr
 }
 
 // FindOneAndUpdate finds a single document and updates it, returning either
-// the original or the updated.
+// the original or the updated. The update is either a document of update operators or, for
+// MongoDB 4.2 and later, an aggregation pipeline of update stages.
 func (coll *Collection) FindOneAndUpdate(ctx context.Context, filter interface{},
 	update interface{}, opts ...*options.FindOneAndUpdateOptions) *SingleResult {
 
-	if ctx == nil {
-		ctx = context.Background()
+	if coll.collation != nil {
+		opts = append([]*options.FindOneAndUpdateOptions{options.FindOneAndUpdate().SetCollation(coll.collation)}, opts...)
 	}
 
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+	ctx, server := recordServer(ctx)
+
 	f, err := transformDocument(coll.registry, filter)
 	if err != nil {
 		return &SingleResult{err: err}
 	}
 
-	u, err := transformDocument(coll.registry, update)
+	u, err := transformUpdateValue(coll.registry, update)
 	if err != nil {
 		return &SingleResult{err: err}
 	}
 
-	err = ensureDollarKey(u)
-	if err != nil {
-		return &SingleResult{
-			err: err,
-		}
-	}
-
 	sess := sessionFromContext(ctx)
 
 	err = coll.client.validSession(sess)
@@ -1165,10 +1571,11 @@ func (coll *Collection) FindOneAndUpdate(ctx context.Context, filter interface{}
 		return &SingleResult{err: err}
 	}
 
-	wc := coll.writeConcern
-	if sess.TransactionRunning() {
-		wc = nil
+	wc, err := coll.writeConcernFor(sess, options.MergeFindOneAndUpdateOptions(opts...).WriteConcern)
+	if err != nil {
+		return &SingleResult{err: err}
 	}
+	concerns := operationConcerns(sess, nil, wc, nil)
 
 	oldns := coll.namespace()
 	cmd := command.FindOneAndUpdate{
@@ -1190,15 +1597,19 @@ func (coll *Collection) FindOneAndUpdate(ctx context.Context, filter interface{}
 		coll.registry,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 	if err != nil {
-		return &SingleResult{err: replaceErrors(err)}
+		return &SingleResult{concerns: concerns, err: replaceErrors(err)}
 	}
 
 	if res.WriteConcernError != nil {
-		return &SingleResult{err: *convertWriteConcernError(res.WriteConcernError)}
+		return &SingleResult{concerns: concerns, err: *convertWriteConcernError(res.WriteConcernError)}
 	}
 
-	return &SingleResult{rdr: res.Value, reg: coll.registry}
+	sr := coll.newSingleResult(res.Value)
+	sr.server = *server
+	sr.concerns = concerns
+	return sr
 }
 
 // Watch returns a change stream cursor used to receive notifications of changes to the collection.
@@ -1218,9 +1629,8 @@ func (coll *Collection) Indexes() IndexView {
 
 // Drop drops this collection from database.
 func (coll *Collection) Drop(ctx context.Context) error {
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
 
 	sess := sessionFromContext(ctx)
 
@@ -1248,6 +1658,7 @@ func (coll *Collection) Drop(ctx context.Context) error {
 		coll.client.id,
 		coll.client.topology.SessionPool,
 	)
+	err = timeoutError(ctx, err)
 	if err != nil && !command.IsNotFound(err) {
 		return replaceErrors(err)
 	}
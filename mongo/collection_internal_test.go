@@ -23,11 +23,13 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/internal/testutil"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
 	"go.mongodb.org/mongo-driver/x/network/command"
 )
 
@@ -485,6 +487,206 @@ func TestCollection_InsertOne_WriteConcernError(t *testing.T) {
 	}
 }
 
+func TestCollection_InsertOne_OperationWriteConcernError(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	if os.Getenv("TOPOLOGY") != "replica_set" {
+		t.Skip()
+	}
+
+	doc := bsonx.Doc{{"_id", bsonx.ObjectID(primitive.NewObjectID())}}
+	coll := createTestCollection(t, nil, nil)
+
+	_, err := coll.InsertOne(context.Background(), doc, options.InsertOne().SetWriteConcern(impossibleWriteConcern))
+	writeErr, ok := err.(WriteException)
+	if !ok {
+		t.Errorf("incorrect error type returned: %T", writeErr)
+	}
+	if writeErr.WriteConcernError == nil {
+		t.Errorf("write concern error is nil: %+v", writeErr)
+	}
+}
+
+func TestCollection_OperationWriteConcernError(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	if os.Getenv("TOPOLOGY") != "replica_set" {
+		t.Skip()
+	}
+
+	filter := bsonx.Doc{{"x", bsonx.Int32(1)}}
+	replacement := bsonx.Doc{{"x", bsonx.Int32(1)}, {"pi", bsonx.Double(3.14159)}}
+
+	checkErr := func(t *testing.T, err error) {
+		t.Helper()
+		var wce *WriteConcernError
+		switch e := err.(type) {
+		case WriteException:
+			wce = e.WriteConcernError
+		case BulkWriteException:
+			wce = e.WriteConcernError
+		case WriteConcernError:
+			wce = &e
+		default:
+			t.Fatalf("incorrect error type returned: %T", err)
+		}
+		if wce == nil {
+			t.Errorf("write concern error is nil: %+v", err)
+		}
+	}
+
+	t.Run("BulkWrite", func(t *testing.T) {
+		coll := createTestCollection(t, nil, nil)
+		models := []WriteModel{NewInsertOneModel().SetDocument(filter)}
+		_, err := coll.BulkWrite(context.Background(), models, options.BulkWrite().SetWriteConcern(impossibleWriteConcern))
+		checkErr(t, err)
+	})
+	t.Run("ReplaceOne", func(t *testing.T) {
+		coll := createTestCollection(t, nil, nil)
+		_, err := coll.InsertOne(ctx, filter)
+		require.NoError(t, err)
+
+		_, err = coll.ReplaceOne(context.Background(), filter, replacement, options.Replace().SetWriteConcern(impossibleWriteConcern))
+		checkErr(t, err)
+	})
+	t.Run("DeleteMany", func(t *testing.T) {
+		coll := createTestCollection(t, nil, nil)
+		_, err := coll.InsertOne(ctx, filter)
+		require.NoError(t, err)
+
+		_, err = coll.DeleteMany(context.Background(), filter, options.Delete().SetWriteConcern(impossibleWriteConcern))
+		checkErr(t, err)
+	})
+	t.Run("FindOneAndUpdate", func(t *testing.T) {
+		coll := createTestCollection(t, nil, nil)
+		_, err := coll.InsertOne(ctx, filter)
+		require.NoError(t, err)
+
+		update := bsonx.Doc{{"$set", bsonx.Document(bsonx.Doc{{"pi", bsonx.Double(3.14159)}})}}
+		err = coll.FindOneAndUpdate(context.Background(), filter, update,
+			options.FindOneAndUpdate().SetWriteConcern(impossibleWriteConcern)).Err()
+		checkErr(t, err)
+	})
+}
+
+func TestCollection_OperationWriteConcernInTransaction(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+	coll := client.Database("db").Collection("coll")
+
+	sc, err := session.NewClientSession(session.NewPool(nil), client.id, session.Explicit)
+	require.NoError(t, err)
+	require.NoError(t, sc.StartTransaction(nil))
+	sctx := contextWithSession(context.Background(), &sessionImpl{Client: sc})
+
+	wc := writeconcern.New(writeconcern.WMajority())
+	doc := bsonx.Doc{{"x", bsonx.Int32(1)}}
+	update := bsonx.Doc{{"$set", bsonx.Document(doc)}}
+
+	_, err = coll.InsertOne(sctx, doc, options.InsertOne().SetWriteConcern(wc))
+	require.Equal(t, ErrWriteConcernInTransaction, err)
+	_, err = coll.BulkWrite(sctx, []WriteModel{NewInsertOneModel().SetDocument(doc)}, options.BulkWrite().SetWriteConcern(wc))
+	require.Equal(t, ErrWriteConcernInTransaction, err)
+	_, err = coll.ReplaceOne(sctx, doc, doc, options.Replace().SetWriteConcern(wc))
+	require.Equal(t, ErrWriteConcernInTransaction, err)
+	_, err = coll.DeleteMany(sctx, doc, options.Delete().SetWriteConcern(wc))
+	require.Equal(t, ErrWriteConcernInTransaction, err)
+	err = coll.FindOneAndUpdate(sctx, doc, update, options.FindOneAndUpdate().SetWriteConcern(wc)).Err()
+	require.Equal(t, ErrWriteConcernInTransaction, err)
+	err = coll.FindOneAndReplace(sctx, doc, doc, options.FindOneAndReplace().SetWriteConcern(wc)).Err()
+	require.Equal(t, ErrWriteConcernInTransaction, err)
+	err = coll.FindOneAndDelete(sctx, doc, options.FindOneAndDelete().SetWriteConcern(wc)).Err()
+	require.Equal(t, ErrWriteConcernInTransaction, err)
+}
+
+func TestCollection_writeConcernFor(t *testing.T) {
+	collWC := writeconcern.New(writeconcern.W(1))
+	opWC := writeconcern.New(writeconcern.WMajority())
+	coll := &Collection{writeConcern: collWC}
+
+	wc, err := coll.writeConcernFor(nil, nil)
+	require.NoError(t, err)
+	require.True(t, wc == collWC)
+
+	wc, err = coll.writeConcernFor(nil, opWC)
+	require.NoError(t, err)
+	require.True(t, wc == opWC)
+}
+
+func TestCollection_OperationReadConcern(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	skipIfBelow32(t)
+
+	var started []*event.CommandStartedEvent
+	monitor := &event.CommandMonitor{
+		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
+			if evt.CommandName == "find" {
+				started = append(started, evt)
+			}
+		},
+	}
+	cs := testutil.ConnString(t)
+	client, err := Connect(context.Background(), options.Client().ApplyURI(cs.String()).SetMonitor(monitor))
+	require.NoError(t, err)
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	coll := client.Database(testutil.DBName(t)).Collection(testutil.ColName(t),
+		options.Collection().SetReadConcern(readconcern.Local()))
+	cursor, err := coll.Find(context.Background(), bsonx.Doc{}, options.Find().SetReadConcern(readconcern.Majority()))
+	require.NoError(t, err)
+	require.NoError(t, cursor.Close(context.Background()))
+
+	require.Len(t, started, 1)
+	level, err := started[0].Command.LookupErr("readConcern", "level")
+	require.NoError(t, err)
+	require.Equal(t, "majority", level.StringValue())
+}
+
+func TestCollection_OperationReadConcernInTransaction(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+	coll := client.Database("db").Collection("coll")
+
+	sc, err := session.NewClientSession(session.NewPool(nil), client.id, session.Explicit)
+	require.NoError(t, err)
+	require.NoError(t, sc.StartTransaction(nil))
+	sctx := contextWithSession(context.Background(), &sessionImpl{Client: sc})
+
+	rc := readconcern.Majority()
+	filter := bsonx.Doc{{"x", bsonx.Int32(1)}}
+
+	_, err = coll.Find(sctx, filter, options.Find().SetReadConcern(rc))
+	require.Equal(t, ErrReadConcernInTransaction, err)
+	err = coll.FindOne(sctx, filter, options.FindOne().SetReadConcern(rc)).Err()
+	require.Equal(t, ErrReadConcernInTransaction, err)
+	_, err = coll.Aggregate(sctx, bsonx.Arr{}, options.Aggregate().SetReadConcern(rc))
+	require.Equal(t, ErrReadConcernInTransaction, err)
+	_, err = coll.CountDocuments(sctx, filter, options.Count().SetReadConcern(rc))
+	require.Equal(t, ErrReadConcernInTransaction, err)
+	_, err = coll.Distinct(sctx, "x", filter, options.Distinct().SetReadConcern(rc))
+	require.Equal(t, ErrReadConcernInTransaction, err)
+}
+
+func TestCollection_readConcernFor(t *testing.T) {
+	collRC := readconcern.Local()
+	opRC := readconcern.Majority()
+	coll := &Collection{readConcern: collRC}
+
+	rc, err := coll.readConcernFor(nil, nil)
+	require.NoError(t, err)
+	require.True(t, rc == collRC)
+
+	rc, err = coll.readConcernFor(nil, opRC)
+	require.NoError(t, err)
+	require.True(t, rc == opRC)
+}
+
 func TestCollection_NilDocumentError(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode")
@@ -1636,6 +1838,38 @@ func TestCollection_Distinct_withOption(t *testing.T) {
 	require.Equal(t, results, []interface{}{int32(1), int32(2), int32(3), int32(4), int32(5)})
 }
 
+func TestCollection_DistinctDecode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	coll := createTestCollection(t, nil, nil)
+	initCollection(t, coll)
+
+	results := []int{42}
+	err := coll.DistinctDecode(context.Background(), "x", bsonx.Doc{}, &results)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, results)
+}
+
+func TestDecodeDistinctValues(t *testing.T) {
+	reg := bson.DefaultRegistry
+
+	var names []string
+	require.NoError(t, decodeDistinctValues(reg, []interface{}{"a", "b"}, &names))
+	require.Equal(t, []string{"a", "b"}, names)
+
+	type point struct {
+		X int32 `bson:"x"`
+	}
+	points := []point{{9}}
+	require.NoError(t, decodeDistinctValues(reg, []interface{}{bson.D{{"x", int32(1)}}}, &points))
+	require.Equal(t, []point{{1}}, points)
+
+	require.Error(t, decodeDistinctValues(reg, []interface{}{"a"}, names))
+	require.Error(t, decodeDistinctValues(reg, []interface{}{"a"}, &[]int{}))
+}
+
 func TestCollection_Find_found(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode")
@@ -2125,3 +2359,55 @@ func TestCollection_BulkWrite(t *testing.T) {
 		}
 	})
 }
+
+func TestRemoveFailedInserts(t *testing.T) {
+	ids := func() map[int64]interface{} {
+		return map[int64]interface{}{0: "a", 2: "b", 3: "c", 5: "d"}
+	}
+	bwe := BulkWriteException{
+		WriteErrors: []BulkWriteError{
+			{WriteError: WriteError{Index: 3, Code: 11000}},
+			{WriteError: WriteError{Index: 2, Code: 11000}},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		err      error
+		ordered  bool
+		expected map[int64]interface{}
+	}{
+		{"success", nil, true, ids()},
+		{"unacknowledged", ErrUnacknowledgedWrite, true, ids()},
+		{"other error", errors.New("network error"), false, map[int64]interface{}{}},
+		{"unordered write errors", bwe, false, map[int64]interface{}{0: "a", 5: "d"}},
+		{"ordered write errors", bwe, true, map[int64]interface{}{0: "a"}},
+		{"write concern error", BulkWriteException{WriteConcernError: &WriteConcernError{Code: 64}}, true, ids()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, removeFailedInserts(ids(), tc.err, tc.ordered))
+		})
+	}
+}
+
+func TestCollectionDefaultCollation(t *testing.T) {
+	client, err := NewClient(options.Client())
+	require.NoError(t, err)
+	db := client.Database("test")
+
+	caseInsensitive := &options.Collation{Locale: "en", Strength: 2}
+	coll := db.Collection("users", options.Collection().SetCollation(caseInsensitive))
+	require.Equal(t, caseInsensitive, coll.collation)
+	require.Nil(t, db.Collection("users").collation)
+
+	clone, err := coll.Clone()
+	require.NoError(t, err)
+	require.Equal(t, caseInsensitive, clone.collation)
+
+	simple := &options.Collation{Locale: "simple"}
+	clone, err = coll.Clone(options.Collection().SetCollation(simple))
+	require.NoError(t, err)
+	require.Equal(t, simple, clone.collation)
+}
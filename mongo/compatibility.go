@@ -0,0 +1,186 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy"
+	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/description"
+)
+
+// CompatibilityReport is the result of CheckCompatibility.
+type CompatibilityReport struct {
+	Topology string                // The kind of the deployment.
+	Servers  []ServerCompatibility // Sorted by address.
+	Problems []string              // The requirements that are not met, empty if all of them are.
+}
+
+// Err returns an error listing the problems of the report, or nil if there are none.
+func (r *CompatibilityReport) Err() error {
+	if len(r.Problems) == 0 {
+		return nil
+	}
+	return errors.New("incompatible deployment: " + strings.Join(r.Problems, "; "))
+}
+
+// ServerCompatibility describes a server checked by CheckCompatibility.
+type ServerCompatibility struct {
+	Address     string
+	Kind        string
+	Version     string // The version reported by the buildInfo command.
+	WireVersion int32  // The maximum wire version of the server.
+	Err         error  // The error connecting to, authenticating to, or running buildInfo on the server.
+
+	versionArray []int32
+	kind         description.ServerKind
+}
+
+// CheckCompatibility connects and authenticates to every selectable server of the deployment, runs
+// the buildInfo command on it, and checks that it meets the requirements of opts. It is meant to be
+// called at startup, so that a misconfigured deployment or wrong credentials fail a deploy instead of
+// the first requests. The returned error is only non-nil if the check could not be made, such as
+// when no server was selectable before ctx expired; the problems found are listed in the report.
+func (c *Client) CheckCompatibility(ctx context.Context, opts ...*options.CompatibilityOptions) (*CompatibilityReport, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	co := options.MergeCompatibilityOptions(opts...)
+	var minVersion []int32
+	if co.MinServerVersion != nil {
+		var err error
+		if minVersion, err = parseServerVersion(*co.MinServerVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	// wait until the deployment is discovered.
+	if _, err := c.topology.SelectServer(ctx, description.ReadPrefSelector(readpref.Nearest())); err != nil {
+		return nil, replaceErrors(err)
+	}
+	desc := c.topology.Description()
+
+	report := &CompatibilityReport{Topology: desc.Kind.String()}
+	for _, s := range desc.Servers {
+		switch s.Kind {
+		case description.Standalone, description.RSPrimary, description.RSSecondary, description.Mongos, description.LoadBalancer:
+		default:
+			continue
+		}
+		sc := ServerCompatibility{Address: s.Addr.String(), Kind: s.Kind.String(), kind: s.Kind}
+		if s.WireVersion != nil {
+			sc.WireVersion = s.WireVersion.Max
+		}
+		sc.Err = c.buildInfo(ctx, s.Addr, &sc)
+		report.Servers = append(report.Servers, sc)
+	}
+	sort.Slice(report.Servers, func(i, j int) bool { return report.Servers[i].Address < report.Servers[j].Address })
+
+	for _, sc := range report.Servers {
+		report.Problems = append(report.Problems, serverCompatibilityProblems(sc, co, minVersion)...)
+	}
+	return report, nil
+}
+
+// buildInfo runs the buildInfo command on the server with the given address and records the
+// version it reports in sc.
+func (c *Client) buildInfo(ctx context.Context, addr address.Address, sc *ServerCompatibility) error {
+	cmd := command.Read{
+		DB:       "admin",
+		Command:  bsonx.Doc{{"buildInfo", bsonx.Int32(1)}},
+		ReadPref: readpref.Nearest(),
+		Clock:    c.clock,
+	}
+	res, err := driverlegacy.Read(ctx, cmd, c.topology, addressSelector(addr), c.id, c.topology.SessionPool)
+	if err != nil {
+		return replaceErrors(err)
+	}
+
+	var info struct {
+		Version      string  `bson:"version"`
+		VersionArray []int32 `bson:"versionArray"`
+	}
+	if err = bson.UnmarshalWithRegistry(c.registry, res, &info); err != nil {
+		return err
+	}
+	sc.Version = info.Version
+	sc.versionArray = info.VersionArray
+	return nil
+}
+
+// serverCompatibilityProblems returns the requirements of co that the server of sc does not meet.
+func serverCompatibilityProblems(sc ServerCompatibility, co *options.CompatibilityOptions, minVersion []int32) []string {
+	if sc.Err != nil {
+		return []string{fmt.Sprintf("server %s: %v", sc.Address, sc.Err)}
+	}
+
+	var problems []string
+	if minVersion != nil && !versionAtLeast(sc.versionArray, minVersion) {
+		problems = append(problems, fmt.Sprintf("server %s has version %s, older than %s", sc.Address, sc.Version, *co.MinServerVersion))
+	}
+	if co.RequireChangeStreams != nil && *co.RequireChangeStreams && !supportsChangeStreams(sc) {
+		problems = append(problems, fmt.Sprintf("server %s (%s %s) does not support change streams", sc.Address, sc.Kind, sc.Version))
+	}
+	if co.RequireTransactions != nil && *co.RequireTransactions && !supportsTransactions(sc) {
+		problems = append(problems, fmt.Sprintf("server %s (%s %s) does not support transactions", sc.Address, sc.Kind, sc.Version))
+	}
+	return problems
+}
+
+func supportsChangeStreams(sc ServerCompatibility) bool {
+	return sc.kind != description.Standalone && sc.WireVersion >= 6
+}
+
+func supportsTransactions(sc ServerCompatibility) bool {
+	switch sc.kind {
+	case description.RSPrimary, description.RSSecondary:
+		return sc.WireVersion >= 7
+	case description.Mongos, description.LoadBalancer:
+		return sc.WireVersion >= 8
+	}
+	return false
+}
+
+// parseServerVersion parses a version such as "4.2" or "4.0.3" into its parts.
+func parseServerVersion(version string) ([]int32, error) {
+	fields := strings.Split(version, ".")
+	parts := make([]int32, 0, len(fields))
+	for _, f := range fields {
+		part, err := strconv.ParseInt(f, 10, 32)
+		if err != nil || part < 0 {
+			return nil, fmt.Errorf("invalid server version %q", version)
+		}
+		parts = append(parts, int32(part))
+	}
+	return parts, nil
+}
+
+// versionAtLeast returns true if the version with the given parts is at least min. Missing parts
+// are 0.
+func versionAtLeast(parts, min []int32) bool {
+	for i, m := range min {
+		var p int32
+		if i < len(parts) {
+			p = parts[i]
+		}
+		if p != m {
+			return p > m
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/network/description"
+)
+
+func TestServerCompatibilityProblems(t *testing.T) {
+	minVersion, err := parseServerVersion("4.2")
+	require.NoError(t, err)
+	co := options.Compatibility().SetMinServerVersion("4.2").SetRequireTransactions(true).SetRequireChangeStreams(true)
+
+	testCases := []struct {
+		name     string
+		sc       ServerCompatibility
+		problems int
+	}{
+		{"compatible", ServerCompatibility{kind: description.RSPrimary, WireVersion: 8, versionArray: []int32{4, 2, 1, 0}}, 0},
+		{"newer major", ServerCompatibility{kind: description.Mongos, WireVersion: 13, versionArray: []int32{5, 0, 0, 0}}, 0},
+		{"old version", ServerCompatibility{kind: description.RSSecondary, WireVersion: 7, versionArray: []int32{4, 0, 12, 0}}, 1},
+		{"old mongos", ServerCompatibility{kind: description.Mongos, WireVersion: 7, versionArray: []int32{4, 0, 12, 0}}, 2},
+		{"standalone", ServerCompatibility{kind: description.Standalone, WireVersion: 8, versionArray: []int32{4, 2, 0, 0}}, 2},
+		{"unreachable", ServerCompatibility{kind: description.RSPrimary, Err: errors.New("auth error")}, 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			problems := serverCompatibilityProblems(tc.sc, co, minVersion)
+			require.Len(t, problems, tc.problems, "%v", problems)
+		})
+	}
+
+	t.Run("no requirements", func(t *testing.T) {
+		sc := ServerCompatibility{kind: description.Standalone, WireVersion: 2}
+		require.Empty(t, serverCompatibilityProblems(sc, options.Compatibility(), nil))
+	})
+	t.Run("invalid version", func(t *testing.T) {
+		_, err := parseServerVersion("4.x")
+		require.Error(t, err)
+	})
+	t.Run("report error", func(t *testing.T) {
+		require.NoError(t, (&CompatibilityReport{}).Err())
+		require.Error(t, (&CompatibilityReport{Problems: []string{"server a:27017 is unreachable"}}).Err())
+	})
+}
@@ -0,0 +1,146 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/mongocrypt"
+)
+
+// keyRetriever fetches data keys from the key vault collection.
+type keyRetriever struct {
+	coll *Collection
+}
+
+func (kr *keyRetriever) cryptKeys(ctx context.Context, filter bsoncore.Document) ([]bsoncore.Document, error) {
+	cursor, err := kr.coll.Find(ctx, bson.Raw(filter))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []bsoncore.Document
+	for cursor.Next(ctx) {
+		keys = append(keys, append(bsoncore.Document(nil), cursor.Current...))
+	}
+	return keys, cursor.Err()
+}
+
+// defaultSchemaCacheTTL is how long the collection information fetched for automatic encryption is
+// cached by default.
+const defaultSchemaCacheTTL = time.Minute
+
+// collInfoRetriever fetches the collection information, including the JSON schema, of the
+// collections that commands are run against.
+type collInfoRetriever struct {
+	client *Client
+}
+
+func (cir *collInfoRetriever) cryptCollInfo(ctx context.Context, db string, filter bsoncore.Document) (bsoncore.Document, error) {
+	cursor, err := cir.client.Database(db).ListCollections(ctx, bson.Raw(filter))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		return nil, cursor.Err()
+	}
+	return append(bsoncore.Document(nil), cursor.Current...), nil
+}
+
+// keyVaultCollection returns the key vault collection with the namespace ns, of the form
+// db.collection. Data keys are read and written with majority read and write concerns.
+func keyVaultCollection(client *Client, ns string) (*Collection, error) {
+	idx := strings.Index(ns, ".")
+	if idx <= 0 || idx == len(ns)-1 {
+		return nil, fmt.Errorf("invalid key vault namespace %q, it must be of the form db.collection", ns)
+	}
+
+	return client.Database(ns[:idx]).Collection(ns[idx+1:], &options.CollectionOptions{
+		ReadConcern:  readconcern.Majority(),
+		WriteConcern: writeconcern.New(writeconcern.WMajority()),
+	}), nil
+}
+
+// newMongoCrypt creates a MongoCrypt configured with the given KMS providers, schema map, and
+// encrypted fields map.
+func newMongoCrypt(registry *bsoncodec.Registry, kmsProviders map[string]map[string]interface{},
+	schemaMap, encryptedFieldsMap map[string]interface{}) (*mongocrypt.MongoCrypt, error) {
+
+	opts := &mongocrypt.MongoCryptOptions{}
+	for provider, conf := range kmsProviders {
+		switch provider {
+		case "aws":
+			keyID, _ := conf["accessKeyId"].(string)
+			secret, _ := conf["secretAccessKey"].(string)
+			if keyID == "" || secret == "" {
+				return nil, fmt.Errorf("the aws KMS provider requires accessKeyId and secretAccessKey strings")
+			}
+			opts.AwsProviderOpts = &mongocrypt.AwsKmsProviderOptions{AccessKeyID: keyID, SecretAccessKey: secret}
+		case "local":
+			var key []byte
+			switch k := conf["key"].(type) {
+			case []byte:
+				key = k
+			case primitive.Binary:
+				key = k.Data
+			default:
+				return nil, fmt.Errorf("the local KMS provider requires a binary key, got %T", k)
+			}
+			opts.LocalProviderOpts = &mongocrypt.LocalKmsProviderOptions{MasterKey: key}
+		default:
+			return nil, fmt.Errorf("unsupported KMS provider %q", provider)
+		}
+	}
+
+	var err error
+	if opts.LocalSchemaMap, err = marshalNamespaceMap(registry, schemaMap); err != nil {
+		return nil, err
+	}
+	if opts.EncryptedFieldsMap, err = marshalNamespaceMap(registry, encryptedFieldsMap); err != nil {
+		return nil, err
+	}
+	for ns, ef := range opts.EncryptedFieldsMap {
+		if _, ok := ef.Lookup("fields").ArrayOK(); !ok {
+			return nil, fmt.Errorf("the encryptedFields of namespace %q must contain a fields array", ns)
+		}
+	}
+
+	return mongocrypt.NewMongoCrypt(opts)
+}
+
+// marshalNamespaceMap marshals the documents of a map keyed by namespace.
+func marshalNamespaceMap(registry *bsoncodec.Registry, m map[string]interface{}) (map[string]bsoncore.Document, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	docs := make(map[string]bsoncore.Document, len(m))
+	for ns, val := range m {
+		if idx := strings.Index(ns, "."); idx <= 0 || idx == len(ns)-1 {
+			return nil, fmt.Errorf("invalid namespace %q, it must be of the form db.collection", ns)
+		}
+		doc, err := bson.MarshalWithRegistry(registry, val)
+		if err != nil {
+			return nil, MarshalError{Value: val, Err: err}
+		}
+		docs[ns] = doc
+	}
+	return docs, nil
+}
@@ -0,0 +1,109 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+func TestCrypt(t *testing.T) {
+	t.Run("createSpawnArgs", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			args     []string
+			expected []string
+		}{
+			{"no args", nil, []string{"--idleShutdownTimeoutSecs=60"}},
+			{"other args", []string{"--port=27021"}, []string{"--port=27021", "--idleShutdownTimeoutSecs=60"}},
+			{"timeout set", []string{"--idleShutdownTimeoutSecs=5"}, []string{"--idleShutdownTimeoutSecs=5"}},
+			{"timeout set separately", []string{"--idleShutdownTimeoutSecs", "5"}, []string{"--idleShutdownTimeoutSecs", "5"}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				require.Equal(t, tc.expected, createSpawnArgs(tc.args))
+			})
+		}
+	})
+	t.Run("invalid KMS providers", func(t *testing.T) {
+		testCases := []struct {
+			name      string
+			providers map[string]map[string]interface{}
+		}{
+			{"unknown provider", map[string]map[string]interface{}{"gcp": {}}},
+			{"aws missing secret", map[string]map[string]interface{}{"aws": {"accessKeyId": "id"}}},
+			{"local key not binary", map[string]map[string]interface{}{"local": {"key": "key"}}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				aeo := options.AutoEncryption().SetKmsProviders(tc.providers).SetKeyVaultNamespace("keyvault.datakeys")
+				_, err := NewClient(options.Client().SetAutoEncryptionOptions(aeo))
+				require.Error(t, err)
+			})
+		}
+	})
+	t.Run("invalid key vault namespace", func(t *testing.T) {
+		client, err := NewClient()
+		require.NoError(t, err)
+
+		for _, ns := range []string{"", "keyvault", ".datakeys", "keyvault."} {
+			_, err = keyVaultCollection(client, ns)
+			require.Error(t, err, "expected an error for namespace %q", ns)
+		}
+
+		coll, err := keyVaultCollection(client, "keyvault.datakeys")
+		require.NoError(t, err)
+		require.Equal(t, "keyvault", coll.Database().Name())
+		require.Equal(t, "datakeys", coll.Name())
+	})
+	t.Run("unmarshalable schema", func(t *testing.T) {
+		_, err := newMongoCrypt(bson.DefaultRegistry, nil, map[string]interface{}{"db.coll": 1}, nil)
+		_, ok := err.(MarshalError)
+		require.True(t, ok, "expected a MarshalError, got %v", err)
+	})
+	t.Run("invalid namespace maps", func(t *testing.T) {
+		_, err := newMongoCrypt(bson.DefaultRegistry, nil, map[string]interface{}{"coll": bson.D{}}, nil)
+		require.Error(t, err)
+		_, err = newMongoCrypt(bson.DefaultRegistry, nil, nil, map[string]interface{}{"db.coll": bson.D{{"escCollection", "esc"}}})
+		require.EqualError(t, err, `the encryptedFields of namespace "db.coll" must contain a fields array`)
+	})
+}
+
+func TestQueryableEncryption(t *testing.T) {
+	t.Run("encryptedStateCollectionName", func(t *testing.T) {
+		ef, err := bson.Marshal(bson.D{{"escCollection", "custom.esc"}, {"fields", bson.A{}}})
+		require.NoError(t, err)
+
+		require.Equal(t, "custom.esc", encryptedStateCollectionName(ef, "coll", "esc"))
+		require.Equal(t, "enxcol_.coll.ecoc", encryptedStateCollectionName(ef, "coll", "ecoc"))
+	})
+	t.Run("explicitEncryptionOptions", func(t *testing.T) {
+		min := bson.RawValue{Type: bsontype.Int32, Value: bsoncore.AppendInt32(nil, 0)}
+		eo := options.Encrypt().SetAlgorithm(options.AlgorithmRange).SetQueryType(options.QueryTypeRange).
+			SetContentionFactor(4).SetRangeOptions(options.RangeOptions{Min: &min})
+
+		coreOpts := explicitEncryptionOptions(eo)
+		require.Equal(t, options.AlgorithmRange, coreOpts.Algorithm)
+		require.Equal(t, options.QueryTypeRange, coreOpts.QueryType)
+		require.Equal(t, int64(4), *coreOpts.ContentionFactor)
+		require.NotNil(t, coreOpts.RangeOptions)
+		require.Equal(t, bsoncore.Value{Type: bsontype.Int32, Data: min.Value}, *coreOpts.RangeOptions.Min)
+		require.Nil(t, coreOpts.RangeOptions.Max)
+	})
+	t.Run("RewrapManyDataKey master key requires provider", func(t *testing.T) {
+		ce := &ClientEncryption{}
+		_, err := ce.RewrapManyDataKey(context.Background(), bson.D{},
+			options.RewrapManyDataKey().SetMasterKey(bson.D{{"region", "us-east-1"}}))
+		require.Error(t, err)
+	})
+}
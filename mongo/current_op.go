@@ -0,0 +1,111 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CurrentOperation is an operation in progress on a server, as reported by the currentOp command.
+// The fields reported depend on the type of operation and the server version, so Raw holds the
+// whole document.
+type CurrentOperation struct {
+	// OpID identifies the operation in KillOp. It is an int32 for a mongod and a string of the form
+	// "shard:opid" for an operation of a shard listed through a mongos.
+	OpID             interface{} `bson:"opid"`
+	Type             string      `bson:"type"`
+	Active           bool        `bson:"active"`
+	Host             string      `bson:"host"`
+	Shard            string      `bson:"shard,omitempty"`
+	Desc             string      `bson:"desc"`
+	ConnectionID     int64       `bson:"connectionId"`
+	Client           string      `bson:"client"`
+	AppName          string      `bson:"appName"`
+	Op               string      `bson:"op"`
+	Namespace        string      `bson:"ns"`
+	Command          bson.Raw    `bson:"command"`
+	PlanSummary      string      `bson:"planSummary"`
+	SecsRunning      int64       `bson:"secs_running"`
+	MicrosecsRunning int64       `bson:"microsecs_running"`
+	WaitingForLock   bool        `bson:"waitingForLock"`
+	Raw              bson.Raw    `bson:"-"`
+}
+
+// CurrentOp lists the operations in progress on the server selected with the primary read
+// preference, or on every shard for a sharded cluster. The user must have the inprog privilege to
+// list the operations of other users.
+//
+// See https://docs.mongodb.com/manual/reference/command/currentOp/.
+func (c *Client) CurrentOp(ctx context.Context, opts ...*options.CurrentOpOptions) ([]CurrentOperation, error) {
+	res, err := c.Database("admin").RunCommand(ctx, currentOpCommand(options.MergeCurrentOpOptions(opts...))).DecodeBytes()
+	if err != nil {
+		return nil, err
+	}
+	return decodeCurrentOps(c.registry, res)
+}
+
+// KillOp kills the operation with the given ID, as reported by CurrentOp. Killing an operation that
+// already finished is not an error. The user must have the killop privilege to kill the operations
+// of other users.
+//
+// See https://docs.mongodb.com/manual/reference/command/killOp/.
+func (c *Client) KillOp(ctx context.Context, opID interface{}) error {
+	return c.Database("admin").RunCommand(ctx, bson.D{{"killOp", 1}, {"op", opID}}).Err()
+}
+
+// currentOpCommand returns the currentOp command for the given options. Namespace and running time
+// filters are given as conditions on the ns and secs_running fields of the operations.
+func currentOpCommand(co *options.CurrentOpOptions) bson.D {
+	cmd := bson.D{{"currentOp", 1}}
+	if co.IdleConnections != nil {
+		cmd = append(cmd, bson.E{"$all", *co.IdleConnections})
+	}
+	if co.AllUsers != nil {
+		cmd = append(cmd, bson.E{"$ownOps", !*co.AllUsers})
+	}
+	if co.Namespace != nil {
+		ns := *co.Namespace
+		if strings.Contains(ns, ".") {
+			cmd = append(cmd, bson.E{"ns", ns})
+		} else {
+			cmd = append(cmd, bson.E{"ns", primitive.Regex{Pattern: "^" + regexp.QuoteMeta(ns) + `\.`}})
+		}
+	}
+	if co.MinRunningTime != nil {
+		cmd = append(cmd, bson.E{"secs_running", bson.D{{"$gte", int64(*co.MinRunningTime / time.Second)}}})
+	}
+	return cmd
+}
+
+// decodeCurrentOps decodes the operations in the inprog array of a currentOp reply.
+func decodeCurrentOps(registry *bsoncodec.Registry, reply bson.Raw) ([]CurrentOperation, error) {
+	var res struct {
+		InProg []bson.Raw `bson:"inprog"`
+	}
+	if err := bson.UnmarshalWithRegistry(registry, reply, &res); err != nil {
+		return nil, err
+	}
+
+	ops := make([]CurrentOperation, 0, len(res.InProg))
+	for _, doc := range res.InProg {
+		var op CurrentOperation
+		if err := bson.UnmarshalWithRegistry(registry, doc, &op); err != nil {
+			return nil, err
+		}
+		op.Raw = doc
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestCurrentOpCommand(t *testing.T) {
+	t.Run("no options", func(t *testing.T) {
+		cmd := currentOpCommand(options.MergeCurrentOpOptions())
+		require.Equal(t, bson.D{{"currentOp", 1}}, cmd)
+	})
+	t.Run("collection", func(t *testing.T) {
+		opts := options.CurrentOp().SetAllUsers(true).SetIdleConnections(false).
+			SetNamespace("db.coll").SetMinRunningTime(2500 * time.Millisecond)
+		require.Equal(t, bson.D{
+			{"currentOp", 1},
+			{"$all", false},
+			{"$ownOps", false},
+			{"ns", "db.coll"},
+			{"secs_running", bson.D{{"$gte", int64(2)}}},
+		}, currentOpCommand(options.MergeCurrentOpOptions(opts)))
+	})
+	t.Run("database", func(t *testing.T) {
+		opts := options.CurrentOp().SetAllUsers(false).SetNamespace("my-db")
+		require.Equal(t, bson.D{
+			{"currentOp", 1},
+			{"$ownOps", true},
+			{"ns", primitive.Regex{Pattern: `^my-db\.`}},
+		}, currentOpCommand(options.MergeCurrentOpOptions(opts)))
+	})
+}
+
+func TestDecodeCurrentOps(t *testing.T) {
+	reply, err := bson.Marshal(bson.D{
+		{"inprog", bson.A{
+			bson.D{
+				{"opid", int32(12)},
+				{"active", true},
+				{"op", "query"},
+				{"ns", "db.coll"},
+				{"command", bson.D{{"find", "coll"}}},
+				{"secs_running", int32(3)},
+				{"microsecs_running", int64(3000042)},
+				{"lockStats", bson.D{}},
+			},
+			bson.D{{"opid", "shard01:7"}, {"op", "none"}},
+		}},
+		{"ok", 1.0},
+	})
+	require.NoError(t, err)
+
+	ops, err := decodeCurrentOps(bson.DefaultRegistry, reply)
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	require.Equal(t, int32(12), ops[0].OpID)
+	require.True(t, ops[0].Active)
+	require.Equal(t, "db.coll", ops[0].Namespace)
+	require.Equal(t, int64(3), ops[0].SecsRunning)
+	require.Equal(t, int64(3000042), ops[0].MicrosecsRunning)
+	require.Equal(t, "coll", ops[0].Command.Lookup("find").StringValue())
+	require.NotNil(t, ops[0].Raw.Lookup("lockStats").Document())
+	require.Equal(t, "shard01:7", ops[1].OpID)
+
+	reply, err = bson.Marshal(bson.D{{"ok", 1.0}})
+	require.NoError(t, err)
+	ops, err = decodeCurrentOps(bson.DefaultRegistry, reply)
+	require.NoError(t, err)
+	require.Empty(t, ops)
+}
@@ -11,11 +11,18 @@ import (
 	"errors"
 	"io"
 	"reflect"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx"
 	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/description"
 )
 
 // Cursor is used to iterate a stream of documents. Each document is decoded into the result
@@ -43,16 +50,35 @@ import (
 type Cursor struct {
 	// Current is the BSON bytes of the current document. This property is only valid until the next
 	// call to Next or Close. If continued access is required to the bson.Raw, you must make a copy
-	// of it.
+	// of it. Fields can be read from it lazily with Lookup, and it can be forwarded as is without
+	// decoding and encoding it again.
 	Current bson.Raw
 
 	bc       batchCursor
 	batch    *bsoncore.DocumentSequence
 	registry *bsoncodec.Registry
+	timeout  *time.Duration // the timeout of the client, applied to fetching each batch
+	reaper   *cursorReaper  // the reaper of the client if the cursor is tracked
+	concerns OperationConcerns
+
+	disallowUnknownFields bool
+	skipDecodeErrors      bool
+	decodeErrors          []DecodeError
 
 	err error
 }
 
+// DecodeError is a document that a Cursor could not decode.
+type DecodeError struct {
+	Document bson.Raw // A copy of the document.
+	Err      error    // The error returned when decoding the document.
+}
+
+// Error implements the error interface.
+func (de DecodeError) Error() string {
+	return "cannot decode document: " + de.Err.Error()
+}
+
 func newCursor(bc batchCursor, registry *bsoncodec.Registry) (*Cursor, error) {
 	if registry == nil {
 		registry = bson.DefaultRegistry
@@ -70,6 +96,152 @@ func newEmptyCursor() *Cursor {
 // ID returns the ID of this cursor.
 func (c *Cursor) ID() int64 { return c.bc.ID() }
 
+// ServerToken returns a token identifying the server the cursor was created on, or the zero
+// ServerToken if the cursor has no server.
+func (c *Cursor) ServerToken() ServerToken {
+	if c.bc == nil {
+		return ServerToken{}
+	}
+	if server := c.bc.Server(); server != nil {
+		return ServerToken{addr: server.Description().Addr}
+	}
+	return ServerToken{}
+}
+
+// Concerns returns the concerns of the operation that created the cursor.
+func (c *Cursor) Concerns() OperationConcerns { return c.concerns }
+
+// PostBatchResumeToken returns the postBatchResumeToken of the most recent batch of the cursor that
+// had one, or nil if none did. A reader that resumes from the token, such as a reader of the oplog,
+// can use it to checkpoint its position even when batches are empty.
+func (c *Cursor) PostBatchResumeToken() bson.Raw {
+	if bc, ok := c.driverBatchCursor(); ok && bc.PostBatchResumeToken() != nil {
+		return bson.Raw(bc.PostBatchResumeToken())
+	}
+	return nil
+}
+
+// OperationTime returns the operationTime of the response to the most recent batch of the cursor
+// that had one, or nil if none did.
+func (c *Cursor) OperationTime() *primitive.Timestamp {
+	if bc, ok := c.driverBatchCursor(); ok {
+		return bc.OperationTime()
+	}
+	return nil
+}
+
+// driverBatchCursor returns the driverlegacy.BatchCursor of the cursor, if it has one.
+func (c *Cursor) driverBatchCursor() (*driverlegacy.BatchCursor, bool) {
+	bc := c.bc
+	if sbc, ok := bc.(*sessionBatchCursor); ok {
+		bc = sbc.batchCursor
+	}
+	dbc, ok := bc.(*driverlegacy.BatchCursor)
+	return dbc, ok
+}
+
+// CursorHandoff describes a server cursor so that another process can continue iterating it with
+// NewCursorFromID. It is returned by Cursor.Handoff and can be marshaled to BSON or JSON to send it
+// to that process.
+type CursorHandoff struct {
+	ID         int64    `bson:"id" json:"id"`
+	Database   string   `bson:"db" json:"db"`
+	Collection string   `bson:"coll" json:"coll"`
+	Server     string   `bson:"server" json:"server"`                 // the address of the server the cursor is open on
+	SessionID  bson.Raw `bson:"lsid,omitempty" json:"lsid,omitempty"` // the ID of the session the cursor was created in
+}
+
+// Handoff detaches the cursor from its server cursor, without killing it, so that another process,
+// such as a worker of a work distribution system, can continue iterating it with NewCursorFromID.
+// The documents the server already returned, including the rest of the current batch, are still
+// returned by Next, but no more are requested and Close does nothing. Cursors created in an
+// explicit session or by a client connected to a load balancer cannot be handed off.
+//
+// The server cursor is closed once it is exhausted or killed by the other process, or when it times
+// out on the server if neither happens.
+func (c *Cursor) Handoff() (CursorHandoff, error) {
+	bc, ok := c.driverBatchCursor()
+	if !ok || bc.Server() == nil {
+		return CursorHandoff{}, errors.New("cursor cannot be handed off")
+	}
+	addr := bc.Server().Description().Addr
+	ns := bc.Namespace()
+	id := bc.ID()
+
+	sessionID, err := bc.Detach()
+	if err != nil {
+		return CursorHandoff{}, err
+	}
+	handoff := CursorHandoff{
+		ID:         id,
+		Database:   ns.DB,
+		Collection: ns.Collection,
+		Server:     addr.String(),
+	}
+	if sessionID != nil {
+		if handoff.SessionID, err = sessionID.MarshalBSON(); err != nil {
+			return CursorHandoff{}, err
+		}
+	}
+	return handoff, nil
+}
+
+// NewCursorFromID creates a Cursor that continues iterating the server cursor described by handoff,
+// which was returned by Cursor.Handoff, usually in another process. The cursor is continued on the
+// server it was opened on, so client must be connected to the same deployment, and ctx bounds the
+// time spent waiting for that server to be discovered. Documents are decoded using the registry of
+// client.
+func NewCursorFromID(ctx context.Context, client *Client, handoff CursorHandoff) (*Cursor, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if handoff.ID == 0 {
+		return nil, errors.New("cursor ID must not be zero")
+	}
+	ns := command.Namespace{DB: handoff.Database, Collection: handoff.Collection}
+	if err := ns.Validate(); err != nil {
+		return nil, err
+	}
+
+	addr := address.Address(handoff.Server).Canonicalize()
+	ss, err := client.topology.SelectServer(ctx, addressSelector(addr))
+	if err != nil {
+		return nil, replaceErrors(err)
+	}
+
+	var sess *session.Client
+	if len(handoff.SessionID) > 0 {
+		sessionID, err := bsonx.ReadDoc(handoff.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		sess = session.NewClientSessionFromID(client.id, sessionID)
+	}
+
+	bc := driverlegacy.NewBatchCursorFromID(ns, handoff.ID, sess, client.clock, ss.Server)
+	cursor, err := newCursor(bc, client.registry)
+	if err != nil {
+		return nil, err
+	}
+	cursor.timeout = client.timeout
+	client.cursors.track(cursor)
+	return cursor, nil
+}
+
+// addressSelector selects the server with the given address.
+func addressSelector(addr address.Address) description.ServerSelector {
+	return description.ServerSelectorFunc(
+		func(_ description.Topology, candidates []description.Server) ([]description.Server, error) {
+			for _, candidate := range candidates {
+				if candidate.Addr == addr {
+					return []description.Server{candidate}, nil
+				}
+			}
+			return nil, nil
+		},
+	)
+}
+
 // Next gets the next result from this cursor. Returns true if there were no errors and the next
 // result is available for decoding.
 func (c *Cursor) Next(ctx context.Context) bool {
@@ -91,9 +263,10 @@ func (c *Cursor) Next(ctx context.Context) bool {
 	// the context times out.
 	for {
 		// If we don't have a next batch
-		if !c.bc.Next(ctx) {
+		more, err := c.nextBatch(ctx)
+		if !more {
 			// Do we have an error? If so we return false.
-			c.err = c.bc.Err()
+			c.err = err
 			if c.err != nil {
 				return false
 			}
@@ -119,20 +292,100 @@ func (c *Cursor) Next(ctx context.Context) bool {
 	}
 }
 
-// Decode will decode the current document into val.
+// nextBatch advances the batch cursor, running a getMore if the server cursor has more documents,
+// within the timeout of the client that created the cursor.
+func (c *Cursor) nextBatch(ctx context.Context) (bool, error) {
+	ctx, cancel := operationContext(ctx, c.timeout)
+	defer cancel()
+
+	if c.bc.Next(ctx) {
+		return true, nil
+	}
+	return false, timeoutError(ctx, c.bc.Err())
+}
+
+// nextBatchDocuments returns the documents of the current batch that Next has not returned yet or,
+// if there are none, those of the next batch that has documents. Current is set to the last of
+// them. The documents are only valid until the next batch is fetched.
+func (c *Cursor) nextBatchDocuments(ctx context.Context) ([]bson.Raw, bool) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for {
+		var docs []bson.Raw
+		for {
+			doc, err := c.batch.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				c.err = err
+				return nil, false
+			}
+			docs = append(docs, bson.Raw(doc))
+		}
+		if len(docs) > 0 {
+			c.Current = docs[len(docs)-1]
+			return docs, true
+		}
+
+		more, err := c.nextBatch(ctx)
+		if !more {
+			c.err = err
+			if c.err != nil || c.bc.ID() == 0 {
+				return nil, false
+			}
+			continue
+		}
+		c.batch = c.bc.Batch()
+	}
+}
+
+// Decode will decode the current document into val. A failure to decode does not affect the
+// cursor, so iteration can continue with the next document.
 func (c *Cursor) Decode(val interface{}) error {
-	return bson.UnmarshalWithRegistry(c.registry, c.Current, val)
+	err := c.unmarshal(c.Current, val)
+	if err != nil && c.skipDecodeErrors {
+		c.recordDecodeError(c.Current, err)
+	}
+	return err
+}
+
+// SkipDecodeErrors causes All to skip documents that cannot be decoded instead of returning an
+// error. The documents that All or Decode failed to decode are recorded and can be retrieved with
+// DecodeErrors. This allows a scan over many documents to complete even if a few of them do not
+// match the result type.
+func (c *Cursor) SkipDecodeErrors() { c.skipDecodeErrors = true }
+
+// DecodeErrors returns the documents that could not be decoded since SkipDecodeErrors was called.
+func (c *Cursor) DecodeErrors() []DecodeError { return c.decodeErrors }
+
+func (c *Cursor) recordDecodeError(doc []byte, err error) {
+	c.decodeErrors = append(c.decodeErrors, DecodeError{Document: append(bson.Raw(nil), doc...), Err: err})
+}
+
+// unmarshal decodes doc into val using the registry and decoding settings of the cursor.
+func (c *Cursor) unmarshal(doc []byte, val interface{}) error {
+	dc := bsoncodec.DecodeContext{Registry: c.registry, DisallowUnknownFields: c.disallowUnknownFields}
+	return bson.UnmarshalWithContext(dc, doc, val)
 }
 
 // Err returns the current error.
-func (c *Cursor) Err() error { return c.err }
+func (c *Cursor) Err() error { return replaceErrors(c.err) }
 
-// Close closes this cursor.
-func (c *Cursor) Close(ctx context.Context) error { return c.bc.Close(ctx) }
+// Close closes this cursor. Closing a cursor of a disconnected client does nothing, because the
+// cursor was already closed when the client was disconnected.
+func (c *Cursor) Close(ctx context.Context) error {
+	if c.reaper != nil && !c.reaper.take(c.bc) {
+		return nil
+	}
+	return c.bc.Close(ctx)
+}
 
 // All iterates the cursor and decodes each document into results.
 // The results parameter must be a pointer to a slice. The slice pointed to by results will be completely overwritten.
 // If the cursor has been iterated, any previously iterated documents will not be included in results.
+// If SkipDecodeErrors was called, documents that cannot be decoded are left out of results.
 func (c *Cursor) All(ctx context.Context, results interface{}) error {
 	resultsVal := reflect.ValueOf(results)
 	if resultsVal.Kind() != reflect.Ptr {
@@ -151,14 +404,15 @@ func (c *Cursor) All(ctx context.Context, results interface{}) error {
 			return err
 		}
 
-		if !c.bc.Next(ctx) {
+		var more bool
+		if more, err = c.nextBatch(ctx); !more {
 			break
 		}
 
 		batch = c.bc.Batch()
 	}
 
-	if err = c.bc.Err(); err != nil {
+	if err != nil {
 		return err
 	}
 
@@ -167,16 +421,35 @@ func (c *Cursor) All(ctx context.Context, results interface{}) error {
 }
 
 // addFromBatch adds all documents from batch to sliceVal starting at the given index. It returns the new slice value,
-// the next empty index in the slice, and an error if one occurs.
+// the next empty index in the slice, and an error if one occurs. The slice is grown once for the whole batch, and
+// the documents are decoded directly from the batch.
 func (c *Cursor) addFromBatch(sliceVal reflect.Value, elemType reflect.Type, batch *bsoncore.DocumentSequence,
 	index int) (reflect.Value, int, error) {
 
-	docs, err := batch.Documents()
-	if err != nil {
-		return sliceVal, index, err
+	if n := index + batch.DocumentCount(); n > sliceVal.Len() {
+		if n > sliceVal.Cap() {
+			grown := reflect.MakeSlice(sliceVal.Type(), n, n)
+			reflect.Copy(grown, sliceVal)
+			sliceVal = grown
+		} else {
+			// the elements past the length may hold stale values.
+			oldLen := sliceVal.Len()
+			sliceVal = sliceVal.Slice(0, n)
+			for i := oldLen; i < n; i++ {
+				sliceVal.Index(i).Set(reflect.Zero(elemType))
+			}
+		}
 	}
 
-	for _, doc := range docs {
+	for {
+		doc, err := batch.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return sliceVal, index, err
+		}
+
 		if sliceVal.Len() == index {
 			// slice is full
 			newElem := reflect.New(elemType)
@@ -185,8 +458,14 @@ func (c *Cursor) addFromBatch(sliceVal reflect.Value, elemType reflect.Type, bat
 		}
 
 		currElem := sliceVal.Index(index).Addr().Interface()
-		if err = bson.UnmarshalWithRegistry(c.registry, doc, currElem); err != nil {
-			return sliceVal, index, err
+		if err = c.unmarshal(doc, currElem); err != nil {
+			if !c.skipDecodeErrors {
+				return sliceVal, index, err
+			}
+			// discard anything partially decoded so the element can be reused.
+			c.recordDecodeError(doc, err)
+			sliceVal.Index(index).Set(reflect.Zero(elemType))
+			continue
 		}
 
 		index++
@@ -194,3 +473,81 @@ func (c *Cursor) addFromBatch(sliceVal reflect.Value, elemType reflect.Type, bat
 
 	return sliceVal, index, nil
 }
+
+// streamCloseTimeout is the maximum time Stream waits for the cursor to be closed.
+const streamCloseTimeout = 10 * time.Second
+
+// Stream iterates the cursor in a new goroutine and sends a copy of each document on the returned
+// document channel, which is buffered to hold at most bufferSize documents. Once the buffer is full
+// the cursor is not advanced until a receiver catches up, so at most bufferSize documents are held in
+// memory ahead of the consumers. The document channel can safely be shared by multiple worker
+// goroutines.
+//
+// The cursor is closed and the document channel is closed once the cursor is exhausted, an error
+// occurs, or ctx is cancelled. The cursor is closed with its own context, so the server cursor is
+// killed even if ctx was cancelled. If iteration stopped because of an error or cancellation, that error
+// is sent on the error channel before it is closed. Callers should drain the document channel and
+// then receive from the error channel:
+//
+//		docs, errs := cur.Stream(ctx, 100)
+//		for doc := range docs {
+//			// do something with doc....
+//		}
+//		if err := <-errs; err != nil {
+//			log.Fatal(err)
+//		}
+//
+// The cursor must not be used by the caller after calling Stream.
+func (c *Cursor) Stream(ctx context.Context, bufferSize int) (<-chan bson.Raw, <-chan error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+
+	docs := make(chan bson.Raw, bufferSize)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(docs)
+
+		err := c.stream(ctx, docs)
+
+		// ctx may already be cancelled, so the cursor is closed with a separate context to make sure
+		// the server cursor is killed.
+		closeCtx, cancel := context.WithTimeout(context.Background(), streamCloseTimeout)
+		closeErr := c.Close(closeCtx)
+		cancel()
+		if err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			errs <- replaceErrors(err)
+		}
+	}()
+
+	return docs, errs
+}
+
+// stream sends a copy of every remaining document in the cursor on docs. It returns the first error
+// encountered while iterating or ctx.Err() if ctx is cancelled before a document can be sent.
+func (c *Cursor) stream(ctx context.Context, docs chan<- bson.Raw) error {
+	for c.Next(ctx) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		doc := make(bson.Raw, len(c.Current))
+		copy(doc, c.Current)
+
+		select {
+		case docs <- doc:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return c.Err()
+}
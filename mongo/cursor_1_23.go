@@ -0,0 +1,82 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+//go:build go1.23
+// +build go1.23
+
+package mongo
+
+import (
+	"context"
+	"iter"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Documents returns an iterator over the documents in this cursor that can be used with a range
+// statement:
+//
+//	for doc := range cur.Documents(ctx) {
+//		// do something with doc....
+//	}
+//
+//	if err := cur.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// The cursor is closed when iteration stops, either because the cursor was exhausted, an error
+// occurred, or the loop body exited early. Any error encountered, including one from closing the
+// cursor, is available from Err once the loop has finished. Each yielded bson.Raw is only valid
+// until the next iteration; callers that need to retain it must make a copy.
+//
+// Documents is the iterator counterpart of All, which decodes all the documents into a slice.
+func (c *Cursor) Documents(ctx context.Context) iter.Seq[bson.Raw] {
+	return func(yield func(bson.Raw) bool) {
+		defer func() {
+			if err := c.Close(ctx); err != nil && c.err == nil {
+				c.err = replaceErrors(err)
+			}
+		}()
+
+		for c.Next(ctx) {
+			if !yield(c.Current) {
+				return
+			}
+		}
+	}
+}
+
+// Events returns an iterator over the change documents in this change stream that can be used with
+// a range statement:
+//
+//	for event := range cs.Events(ctx) {
+//		// do something with event....
+//	}
+//
+//	if err := cs.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// The change stream is closed when iteration stops. Iteration stops when the change stream
+// encounters a non-resumable error, the context is cancelled, or the loop body exits early. Any
+// error encountered, including one from closing the change stream, is available from Err once the
+// loop has finished. Each yielded bson.Raw is only valid until the next iteration; callers that need
+// to retain it must make a copy.
+func (cs *ChangeStream) Events(ctx context.Context) iter.Seq[bson.Raw] {
+	return func(yield func(bson.Raw) bool) {
+		defer func() {
+			if err := cs.Close(ctx); err != nil && cs.err == nil {
+				cs.err = err
+			}
+		}()
+
+		for cs.Next(ctx) {
+			if !yield(cs.Current) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+//go:build go1.23
+// +build go1.23
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+func TestCursorDocuments(t *testing.T) {
+	t.Run("yields all documents across batches", func(t *testing.T) {
+		cursor, err := newCursor(newTestBatchCursor(2, 5), nil)
+		require.Nil(t, err)
+
+		var index int32
+		for doc := range cursor.Documents(context.Background()) {
+			var d bson.D
+			require.Nil(t, bson.Unmarshal(doc, &d))
+			require.Equal(t, bson.D{{"foo", index}}, d)
+			index++
+		}
+		require.Nil(t, cursor.Err())
+		require.Equal(t, int32(10), index)
+	})
+
+	t.Run("stops when loop exits early", func(t *testing.T) {
+		tbc := newTestBatchCursor(2, 5)
+		cursor, err := newCursor(tbc, nil)
+		require.Nil(t, err)
+
+		var count int
+		for range cursor.Documents(context.Background()) {
+			count++
+			if count == 3 {
+				break
+			}
+		}
+		require.Nil(t, cursor.Err())
+		require.Equal(t, 3, count)
+		require.Equal(t, 1, tbc.closeCalls)
+	})
+}
+
+// newTestChangeStream creates a change stream over a single batch of numEvents change documents.
+func newTestChangeStream(t *testing.T, numEvents int) (*ChangeStream, *testBatchCursor) {
+	t.Helper()
+
+	var events []byte
+	for i := 0; i < numEvents; i++ {
+		token := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "token", int32(i)))
+		events = bsoncore.BuildDocumentFromElements(events,
+			bsoncore.AppendDocumentElement(nil, "_id", token),
+			bsoncore.AppendInt32Element(nil, "foo", int32(i)),
+		)
+	}
+	tbc := &testBatchCursor{batches: []*bsoncore.DocumentSequence{{Style: bsoncore.SequenceStyle, Data: events}}}
+
+	cursor, err := newCursor(tbc, nil)
+	require.Nil(t, err)
+	return &ChangeStream{cursor: cursor}, tbc
+}
+
+func TestChangeStreamEvents(t *testing.T) {
+	t.Run("yields all events", func(t *testing.T) {
+		cs, tbc := newTestChangeStream(t, 4)
+
+		var index int32
+		for event := range cs.Events(context.Background()) {
+			require.Equal(t, index, event.Lookup("foo").Int32())
+			index++
+		}
+		require.Nil(t, cs.Err())
+		require.Equal(t, int32(4), index)
+		require.Equal(t, 1, tbc.closeCalls)
+	})
+
+	t.Run("stops when loop exits early", func(t *testing.T) {
+		cs, tbc := newTestChangeStream(t, 4)
+
+		var count int
+		for range cs.Events(context.Background()) {
+			count++
+			if count == 2 {
+				break
+			}
+		}
+		require.Nil(t, cs.Err())
+		require.Equal(t, 2, count)
+		require.Equal(t, 1, tbc.closeCalls)
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// cursorReaper tracks the open cursors of a Client and closes, in a background goroutine, those that
+// are abandoned: cursors garbage collected without being closed, such as a cursor dropped after its
+// getMore was interrupted by the cancellation of its context. Closing them kills the server cursors,
+// which would otherwise stay open until the server times them out. Only unreachable cursors are
+// closed in the background, so a cursor is never closed while its Next may run, and a cursor whose
+// getMore timed out can still be iterated. The open cursors are closed when the Client is
+// disconnected.
+//
+// Cursors are tracked by their batch cursor rather than by the Cursor itself so that an abandoned
+// Cursor can be garbage collected. A nil *cursorReaper, as used by Clients that are not created
+// with NewClient, does not track cursors.
+type cursorReaper struct {
+	mu      sync.Mutex
+	open    map[batchCursor]struct{}
+	queue   []batchCursor
+	closing int // the number of cursors taken from open or queue that are being closed
+	running bool
+	idle    *sync.Cond // signaled when the worker goroutine exits
+}
+
+func newCursorReaper() *cursorReaper {
+	r := &cursorReaper{
+		open: make(map[batchCursor]struct{}),
+	}
+	r.idle = sync.NewCond(&r.mu)
+	return r
+}
+
+// track starts tracking the cursor c and closes it in the background if it is garbage collected
+// while open.
+func (r *cursorReaper) track(c *Cursor) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.open[c.bc] = struct{}{}
+	c.reaper = r
+	runtime.SetFinalizer(c, func(c *Cursor) { c.reaper.abandon(c.bc) })
+}
+
+// take stops tracking bc. It returns true if bc was tracked, in which case the caller is
+// responsible for closing it.
+func (r *cursorReaper) take(bc batchCursor) bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.open[bc]; !ok {
+		return false
+	}
+	delete(r.open, bc)
+	return true
+}
+
+// abandon closes bc in the background if it is still tracked.
+func (r *cursorReaper) abandon(bc batchCursor) {
+	if !r.take(bc) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queue = append(r.queue, bc)
+	if !r.running {
+		r.running = true
+		go r.work()
+	}
+}
+
+// work closes queued cursors until the queue is empty.
+func (r *cursorReaper) work() {
+	for {
+		r.mu.Lock()
+		if len(r.queue) == 0 {
+			r.running = false
+			r.idle.Broadcast()
+			r.mu.Unlock()
+			return
+		}
+		bc := r.queue[0]
+		r.queue = r.queue[1:]
+		r.closing++
+		r.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), streamCloseTimeout)
+		_ = bc.Close(ctx)
+		cancel()
+		r.closed()
+	}
+}
+
+// closed records that one of the cursors being closed has been closed.
+func (r *cursorReaper) closed() {
+	r.mu.Lock()
+	r.closing--
+	r.mu.Unlock()
+}
+
+// pending returns the number of cursors that are open, queued, or being closed.
+func (r *cursorReaper) pending() int {
+	if r == nil {
+		return 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.open) + len(r.queue) + r.closing
+}
+
+// close closes the open cursors with ctx and waits for the queued ones to be closed. It is called
+// when the Client is disconnected.
+func (r *cursorReaper) close(ctx context.Context) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	open := make([]batchCursor, 0, len(r.open))
+	for bc := range r.open {
+		open = append(open, bc)
+	}
+	r.open = make(map[batchCursor]struct{})
+	r.closing += len(open)
+	r.mu.Unlock()
+
+	for _, bc := range open {
+		_ = bc.Close(ctx)
+		r.closed()
+	}
+
+	r.mu.Lock()
+	for r.running {
+		r.idle.Wait()
+	}
+	r.mu.Unlock()
+}
@@ -13,6 +13,9 @@ import (
 type testBatchCursor struct {
 	batches []*bsoncore.DocumentSequence
 	batch   *bsoncore.DocumentSequence
+
+	closeCalls  int   // the number of times Close was called
+	closeCtxErr error // the error of the context passed to the last Close call
 }
 
 func newTestBatchCursor(numBatches, batchSize int) *testBatchCursor {
@@ -73,7 +76,9 @@ func (tbc *testBatchCursor) Err() error {
 	return nil
 }
 
-func (tbc *testBatchCursor) Close(context.Context) error {
+func (tbc *testBatchCursor) Close(ctx context.Context) error {
+	tbc.closeCalls++
+	tbc.closeCtxErr = ctx.Err()
 	return nil
 }
 
@@ -133,5 +138,232 @@ func TestCursor(t *testing.T) {
 				require.Equal(t, doc, bson.D{{"foo", int32(index)}})
 			}
 		})
+
+		t.Run("previously iterated documents are not included", func(t *testing.T) {
+			cursor, err := newCursor(newTestBatchCursor(2, 5), nil)
+			require.Nil(t, err)
+			require.True(t, cursor.Next(context.Background()))
+
+			var docs []bson.D
+			err = cursor.All(context.Background(), &docs)
+			require.Nil(t, err)
+			require.Equal(t, 9, len(docs))
+			require.Equal(t, bson.D{{"foo", int32(1)}}, docs[0])
+		})
+
+		t.Run("existing elements and capacity are overwritten", func(t *testing.T) {
+			cursor, err := newCursor(newTestBatchCursor(1, 5), nil)
+			require.Nil(t, err)
+
+			type Document struct {
+				Foo int32 `bson:"foo"`
+				Bar int32 `bson:"bar"`
+			}
+			docs := []Document{{Bar: 1}, {Bar: 2}, {Bar: 3}}[:1]
+			err = cursor.All(context.Background(), &docs)
+			require.Nil(t, err)
+			require.Equal(t, 5, len(docs))
+			require.Equal(t, Document{Foo: 0, Bar: 1}, docs[0])
+			for index, doc := range docs[1:] {
+				require.Equal(t, Document{Foo: int32(index + 1)}, doc)
+			}
+		})
+	})
+
+	t.Run("disallows unknown fields", func(t *testing.T) {
+		type Document struct {
+			Bar int32 `bson:"bar"`
+		}
+
+		cursor, err := newCursor(newTestBatchCursor(1, 5), nil)
+		require.Nil(t, err)
+		cursor.disallowUnknownFields = true
+		require.True(t, cursor.Next(context.Background()))
+		var doc Document
+		require.NotNil(t, cursor.Decode(&doc))
+
+		cursor, err = newCursor(newTestBatchCursor(1, 5), nil)
+		require.Nil(t, err)
+		cursor.disallowUnknownFields = true
+		var docs []Document
+		require.NotNil(t, cursor.All(context.Background(), &docs))
+
+		sr := &SingleResult{rdr: bson.Raw(bsoncore.BuildDocument(nil, bsoncore.AppendInt32Element(nil, "foo", 1))), reg: bson.DefaultRegistry, disallowUnknownFields: true}
+		require.NotNil(t, sr.Decode(&doc))
+		sr.disallowUnknownFields = false
+		require.Nil(t, sr.Decode(&doc))
+	})
+}
+
+func TestCursorSkipDecodeErrors(t *testing.T) {
+	type Document struct {
+		Foo int32 `bson:"foo"`
+	}
+	newCursorWithDocs := func(t *testing.T) *Cursor {
+		var data []byte
+		for _, val := range []interface{}{int32(1), "two", int32(3)} {
+			doc, err := bson.Marshal(bson.D{{"foo", val}})
+			require.Nil(t, err)
+			data = append(data, doc...)
+		}
+		tbc := &testBatchCursor{batches: []*bsoncore.DocumentSequence{{Style: bsoncore.SequenceStyle, Data: data}}}
+		cursor, err := newCursor(tbc, nil)
+		require.Nil(t, err)
+		return cursor
+	}
+
+	t.Run("All", func(t *testing.T) {
+		cursor := newCursorWithDocs(t)
+		var docs []Document
+		require.NotNil(t, cursor.All(context.Background(), &docs))
+
+		cursor = newCursorWithDocs(t)
+		cursor.SkipDecodeErrors()
+		require.Nil(t, cursor.All(context.Background(), &docs))
+		require.Equal(t, []Document{{1}, {3}}, docs)
+		require.Len(t, cursor.DecodeErrors(), 1)
+		require.Equal(t, "two", cursor.DecodeErrors()[0].Document.Lookup("foo").StringValue())
+	})
+	t.Run("Decode", func(t *testing.T) {
+		cursor := newCursorWithDocs(t)
+		cursor.SkipDecodeErrors()
+
+		var decoded []Document
+		for cursor.Next(context.Background()) {
+			var doc Document
+			if err := cursor.Decode(&doc); err != nil {
+				continue
+			}
+			decoded = append(decoded, doc)
+		}
+		require.Nil(t, cursor.Err())
+		require.Equal(t, []Document{{1}, {3}}, decoded)
+		require.Len(t, cursor.DecodeErrors(), 1)
+		require.NotNil(t, cursor.DecodeErrors()[0].Err)
+	})
+}
+
+func TestCursorStream(t *testing.T) {
+	t.Run("sends all documents", func(t *testing.T) {
+		cursor, err := newCursor(newTestBatchCursor(2, 5), nil)
+		require.Nil(t, err)
+
+		docs, errs := cursor.Stream(context.Background(), 2)
+
+		var index int32
+		for doc := range docs {
+			require.Equal(t, index, doc.Lookup("foo").Int32())
+			index++
+		}
+		require.Nil(t, <-errs)
+		require.Equal(t, int32(10), index)
+	})
+
+	t.Run("returns context error on cancellation", func(t *testing.T) {
+		tbc := newTestBatchCursor(2, 5)
+		cursor, err := newCursor(tbc, nil)
+		require.Nil(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		docs, errs := cursor.Stream(ctx, 0)
+
+		<-docs
+		cancel()
+		for range docs {
+		}
+		require.Equal(t, context.Canceled, <-errs)
+		// the cursor must be closed with a live context so that killCursors is still sent.
+		require.Equal(t, 1, tbc.closeCalls)
+		require.Nil(t, tbc.closeCtxErr)
+	})
+}
+
+func TestCursorHandoff(t *testing.T) {
+	t.Run("unsupported batch cursor", func(t *testing.T) {
+		cursor, err := newCursor(newTestBatchCursor(1, 1), nil)
+		require.Nil(t, err)
+		_, err = cursor.Handoff()
+		require.Error(t, err)
+	})
+	t.Run("invalid handoff", func(t *testing.T) {
+		_, err := NewCursorFromID(context.Background(), nil, CursorHandoff{Database: "db", Collection: "coll"})
+		require.Error(t, err)
+		_, err = NewCursorFromID(context.Background(), nil, CursorHandoff{ID: 42, Collection: "coll"})
+		require.Error(t, err)
+	})
+}
+
+// cancelableBatchCursor is a testBatchCursor whose getMores fail once their context is cancelled.
+type cancelableBatchCursor struct {
+	*testBatchCursor
+	err error
+}
+
+func (cbc *cancelableBatchCursor) Next(ctx context.Context) bool {
+	if cbc.err = ctx.Err(); cbc.err != nil {
+		return false
+	}
+	return cbc.testBatchCursor.Next(ctx)
+}
+
+func (cbc *cancelableBatchCursor) Err() error {
+	return cbc.err
+}
+
+func TestCursorReaper(t *testing.T) {
+	t.Run("close does not kill closed cursors", func(t *testing.T) {
+		reaper := newCursorReaper()
+		tbc := newTestBatchCursor(2, 5)
+		cursor, err := newCursor(tbc, nil)
+		require.Nil(t, err)
+		reaper.track(cursor)
+
+		require.Nil(t, cursor.Close(context.Background()))
+		reaper.close(context.Background())
+		require.Equal(t, 1, tbc.closeCalls)
+	})
+	t.Run("close kills open cursors", func(t *testing.T) {
+		reaper := newCursorReaper()
+		tbc := newTestBatchCursor(2, 5)
+		cursor, err := newCursor(tbc, nil)
+		require.Nil(t, err)
+		reaper.track(cursor)
+
+		reaper.close(context.Background())
+		require.Equal(t, 1, tbc.closeCalls)
+		require.Nil(t, cursor.Close(context.Background()))
+		require.Equal(t, 1, tbc.closeCalls)
+	})
+	t.Run("cancelled getMore does not kill cursor", func(t *testing.T) {
+		reaper := newCursorReaper()
+		tbc := newTestBatchCursor(2, 5)
+		cursor, err := newCursor(&cancelableBatchCursor{testBatchCursor: tbc}, nil)
+		require.Nil(t, err)
+		reaper.track(cursor)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.False(t, cursor.Next(ctx))
+		require.Equal(t, context.Canceled, cursor.Err())
+		require.Equal(t, 1, reaper.pending())
+		require.Equal(t, 0, tbc.closeCalls)
+
+		// the cursor can still be iterated, as when polling a tailable cursor with a short timeout
+		require.True(t, cursor.Next(context.Background()))
+		require.Nil(t, cursor.Close(context.Background()))
+		require.Equal(t, 1, tbc.closeCalls)
+	})
+	t.Run("abandoned cursor is killed", func(t *testing.T) {
+		reaper := newCursorReaper()
+		tbc := newTestBatchCursor(2, 5)
+		cursor, err := newCursor(tbc, nil)
+		require.Nil(t, err)
+		reaper.track(cursor)
+
+		reaper.abandon(cursor.bc)          // as the finalizer of an unreachable cursor does
+		reaper.close(context.Background()) // waits for the background kill
+		require.Equal(t, 1, tbc.closeCalls)
+		require.Nil(t, tbc.closeCtxErr)
+		require.Equal(t, 0, reaper.pending())
 	})
 }
@@ -8,15 +8,22 @@ package mongo
 
 import (
 	"context"
+	"errors"
+	"reflect"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/x/bsonx"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
 	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/result"
 )
 
 // Database performs operations on a given database.
@@ -49,13 +56,18 @@ func newDatabase(client *Client, name string, opts ...*options.DatabaseOptions)
 		wc = dbOpt.WriteConcern
 	}
 
+	reg := client.registry
+	if dbOpt.Registry != nil {
+		reg = dbOpt.Registry
+	}
+
 	db := &Database{
 		client:         client,
 		name:           name,
 		readPreference: rp,
 		readConcern:    rc,
 		writeConcern:   wc,
-		registry:       client.registry,
+		registry:       reg,
 	}
 
 	db.readSelector = description.CompositeSelector([]description.ServerSelector{
@@ -71,6 +83,19 @@ func newDatabase(client *Client, name string, opts ...*options.DatabaseOptions)
 	return db
 }
 
+func (db *Database) copy() *Database {
+	return &Database{
+		client:         db.client,
+		name:           db.name,
+		readConcern:    db.readConcern,
+		writeConcern:   db.writeConcern,
+		readPreference: db.readPreference,
+		readSelector:   db.readSelector,
+		writeSelector:  db.writeSelector,
+		registry:       db.registry,
+	}
+}
+
 // Client returns the Client the database was created from.
 func (db *Database) Client() *Client {
 	return db.client
@@ -110,10 +135,16 @@ func (db *Database) processRunCommand(ctx context.Context, cmd interface{}, opts
 		}
 	}
 
+	if isUnorderedCommand(cmd) {
+		return command.Read{}, nil, ErrMapForOrderedArgument
+	}
 	runCmdDoc, err := transformDocument(db.registry, cmd)
 	if err != nil {
 		return command.Read{}, nil, err
 	}
+	if err = validateRunCommandConcerns(runCmdDoc, sess, runCmd); err != nil {
+		return command.Read{}, nil, err
+	}
 
 	readSelect := description.CompositeSelector([]description.ServerSelector{
 		description.ReadPrefSelector(rp),
@@ -121,20 +152,85 @@ func (db *Database) processRunCommand(ctx context.Context, cmd interface{}, opts
 	})
 
 	return command.Read{
-		DB:       db.Name(),
-		Command:  runCmdDoc,
-		ReadPref: rp,
-		Session:  sess,
-		Clock:    db.client.clock,
+		DB:           db.Name(),
+		Command:      runCmdDoc,
+		ReadPref:     rp,
+		ReadConcern:  runCmd.ReadConcern,
+		WriteConcern: runCmd.WriteConcern,
+		Session:      sess,
+		Clock:        db.client.clock,
 	}, readSelect, nil
 }
 
+// runCommandContext returns ctx with an operation timeout if opts ask for the maxTimeMS of the
+// command to be derived from the deadline of ctx and ctx has one, so that it is set like for the
+// commands of a client with a timeout.
+func runCommandContext(ctx context.Context, opts ...*options.RunCmdOptions) context.Context {
+	fromDeadline := options.MergeRunCmdOptions(opts...).MaxTimeFromDeadline
+	if fromDeadline == nil || !*fromDeadline || connection.HasOperationTimeout(ctx) {
+		return ctx
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return ctx
+	}
+	// The context has a deadline, so the returned context does not need to be cancelled.
+	ctx, _ = connection.WithOperationTimeout(ctx, 0)
+	return ctx
+}
+
+// isUnorderedCommand returns true if cmd is a map with more than one key, so the command name may
+// not be encoded first.
+func isUnorderedCommand(cmd interface{}) bool {
+	val := reflect.ValueOf(cmd)
+	return val.Kind() == reflect.Map && val.Len() > 1
+}
+
+// validateRunCommandConcerns returns an error if the read or write concern of opts cannot be added
+// to the command cmd run using sess.
+func validateRunCommandConcerns(cmd bsonx.Doc, sess *session.Client, opts *options.RunCmdOptions) error {
+	if opts.ReadConcern == nil && opts.WriteConcern == nil {
+		return nil
+	}
+	if sess.TransactionRunning() {
+		if opts.ReadConcern != nil {
+			return ErrReadConcernInTransaction
+		}
+		return ErrWriteConcernInTransaction
+	}
+	if len(cmd) > 0 && cmd[0].Key == "getMore" {
+		return errors.New("getMore commands use the read and write concern of the command that created the cursor")
+	}
+	if _, err := cmd.LookupErr("readConcern"); err == nil && opts.ReadConcern != nil {
+		return errors.New("command already contains a readConcern")
+	}
+	if _, err := cmd.LookupErr("writeConcern"); err == nil && opts.WriteConcern != nil {
+		return errors.New("command already contains a writeConcern")
+	}
+	return nil
+}
+
+// newCursor creates a cursor for bc, created by an operation run with ctx, that decodes documents
+// using the registry of the database.
+func (db *Database) newCursor(ctx context.Context, bc batchCursor) (*Cursor, error) {
+	cursor, err := newCursor(retainSession(ctx, bc), db.registry)
+	if err != nil {
+		return nil, err
+	}
+	cursor.timeout = db.client.timeout
+	db.client.cursors.track(cursor)
+	return cursor, nil
+}
+
 // RunCommand runs a command on the database. A user can supply a custom
 // context to this method, or nil to default to context.Background().
+//
+// The command must be an ordered document, such as a bson.D, whose first element is the command
+// name. Maps with more than one key are rejected with ErrMapForOrderedArgument. The command is sent
+// to a server selected with the read preference of the options, or the primary if none is given.
 func (db *Database) RunCommand(ctx context.Context, runCommand interface{}, opts ...*options.RunCmdOptions) *SingleResult {
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, cancel := operationContext(ctx, db.client.timeout)
+	defer cancel()
+	ctx = runCommandContext(ctx, opts...)
 
 	readCmd, readSelect, err := db.processRunCommand(ctx, runCommand, opts...)
 	if err != nil {
@@ -148,16 +244,19 @@ func (db *Database) RunCommand(ctx context.Context, runCommand interface{}, opts
 		db.client.id,
 		db.client.topology.SessionPool,
 	)
+	err = timeoutError(ctx, err)
 
 	return &SingleResult{err: replaceErrors(err), rdr: doc, reg: db.registry}
 }
 
 // RunCommandCursor runs a command on the database and returns a cursor over the resulting reader. A user can supply
-// a custom context to this method, or nil to default to context.Background().
+// a custom context to this method, or nil to default to context.Background(). The command must
+// return a cursor, as find, aggregate and listCollections do, and is given and routed in the same
+// way as for RunCommand.
 func (db *Database) RunCommandCursor(ctx context.Context, runCommand interface{}, opts ...*options.RunCmdOptions) (*Cursor, error) {
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, cancel := operationContext(ctx, db.client.timeout)
+	defer cancel()
+	ctx = runCommandContext(ctx, opts...)
 
 	readCmd, readSelect, err := db.processRunCommand(ctx, runCommand, opts...)
 	if err != nil {
@@ -172,19 +271,131 @@ func (db *Database) RunCommandCursor(ctx context.Context, runCommand interface{}
 		db.client.id,
 		db.client.topology.SessionPool,
 	)
+	err = timeoutError(ctx, err)
+	if err != nil {
+		return nil, replaceErrors(err)
+	}
+
+	cursor, err := db.newCursor(ctx, batchCursor)
+	return cursor, replaceErrors(err)
+}
+
+// RunCommandExtJSON runs a command given as an extended JSON document on the database and returns
+// the server's reply as extended JSON, in canonical form if canonical is true and in relaxed form
+// otherwise. The command is routed and associated with a session in the same way as RunCommand. It
+// is intended for tools such as shells and admin consoles that work with commands as text.
+func (db *Database) RunCommandExtJSON(ctx context.Context, cmd string, canonical bool, opts ...*options.RunCmdOptions) (string, error) {
+	var doc bson.D
+	if err := bson.UnmarshalExtJSONWithRegistry(db.registry, []byte(cmd), false, &doc); err != nil {
+		return "", err
+	}
+
+	res, err := db.RunCommand(ctx, doc, opts...).DecodeBytes()
+	if err != nil {
+		return "", err
+	}
+
+	out, err := bson.MarshalExtJSONWithRegistry(db.registry, res, canonical, false)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// Aggregate runs an aggregation framework pipeline against the database rather than a collection.
+// This is used for pipelines that start with a stage that does not read from a collection, such as
+// $currentOp, $listLocalSessions, or $documents. Pipelines that start with $currentOp are always run
+// against the admin database, as the server requires.
+//
+// See https://docs.mongodb.com/manual/reference/command/aggregate/.
+func (db *Database) Aggregate(ctx context.Context, pipeline interface{},
+	opts ...*options.AggregateOptions) (*Cursor, error) {
+
+	ctx, cancel := operationContext(ctx, db.client.timeout)
+	defer cancel()
+
+	pipelineArr, err := transformAggregatePipeline(db.registry, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	aggOpts := options.MergeAggregateOptions(opts...)
+
+	sess := sessionFromContext(ctx)
+
+	err = db.client.validSession(sess)
+	if err != nil {
+		return nil, err
+	}
+
+	rc := db.readConcern
+	if aggOpts.ReadConcern != nil {
+		rc = aggOpts.ReadConcern
+	}
+	wc := db.writeConcern
+	if sess.TransactionRunning() {
+		if aggOpts.ReadConcern != nil {
+			return nil, ErrReadConcernInTransaction
+		}
+		rc = nil
+		wc = nil
+	}
+
+	dbName := db.name
+	if firstStageName(pipelineArr) == "$currentOp" {
+		dbName = "admin"
+	}
+
+	cmd := command.Aggregate{
+		NS:           command.Namespace{DB: dbName},
+		Pipeline:     pipelineArr,
+		ReadPref:     db.readPreference,
+		WriteConcern: wc,
+		ReadConcern:  rc,
+		Session:      sess,
+		Clock:        db.client.clock,
+	}
+
+	batchCursor, err := driverlegacy.Aggregate(
+		ctx, cmd,
+		db.client.topology,
+		db.readSelector,
+		db.writeSelector,
+		db.client.id,
+		db.client.topology.SessionPool,
+		db.registry,
+		db.client.retryReads,
+		aggOpts,
+	)
+	err = timeoutError(ctx, err)
 	if err != nil {
+		if wce, ok := err.(result.WriteConcernError); ok {
+			return nil, *convertWriteConcernError(&wce)
+		}
 		return nil, replaceErrors(err)
 	}
 
-	cursor, err := newCursor(batchCursor, db.registry)
+	cursor, err := db.newCursor(ctx, batchCursor)
 	return cursor, replaceErrors(err)
 }
 
+// firstStageName returns the name of the first stage of pipeline, or an empty string if the pipeline
+// is empty.
+func firstStageName(pipeline bsonx.Arr) string {
+	if len(pipeline) == 0 {
+		return ""
+	}
+	stage, ok := pipeline[0].DocumentOK()
+	if !ok || len(stage) == 0 {
+		return ""
+	}
+	return stage[0].Key
+}
+
 // Drop drops this database from mongodb.
 func (db *Database) Drop(ctx context.Context) error {
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	ctx, cancel := operationContext(ctx, db.client.timeout)
+	defer cancel()
 
 	sess := sessionFromContext(ctx)
 
@@ -205,18 +416,153 @@ func (db *Database) Drop(ctx context.Context) error {
 		db.client.id,
 		db.client.topology.SessionPool,
 	)
+	err = timeoutError(ctx, err)
 	if err != nil && !command.IsNotFound(err) {
 		return replaceErrors(err)
 	}
 	return nil
 }
 
-// ListCollections list collections from mongodb database.
-func (db *Database) ListCollections(ctx context.Context, filter interface{}, opts ...*options.ListCollectionsOptions) (*Cursor, error) {
-	if ctx == nil {
-		ctx = context.Background()
+// CreateCollection creates a collection named name with the given options. If the collection uses
+// Queryable Encryption, because EncryptedFields is set or because the encrypted fields map of the
+// client contains its namespace, the ESC and ECOC metadata collections that store the encryption
+// state and the __safeContent__ index are created along with it.
+func (db *Database) CreateCollection(ctx context.Context, name string, opts ...*options.CreateCollectionOptions) error {
+	ctx, cancel := operationContext(ctx, db.client.timeout)
+	defer cancel()
+
+	cco := options.MergeCreateCollectionOptions(opts...)
+	ef := cco.EncryptedFields
+	if ef == nil {
+		ef = db.client.encryptedFieldsMap[db.name+"."+name]
+	}
+	if ef == nil {
+		return db.createCollection(ctx, name, cco, nil)
+	}
+
+	efDoc, err := bson.MarshalWithRegistry(db.registry, ef)
+	if err != nil {
+		return MarshalError{Value: ef, Err: err}
+	}
+
+	// The metadata collections are clustered on _id, as the server expects.
+	for _, suffix := range []string{"esc", "ecoc"} {
+		stateName := encryptedStateCollectionName(efDoc, name, suffix)
+		clustered := bson.D{{"clusteredIndex", bson.D{{"key", bson.D{{"_id", 1}}}, {"unique", true}}}}
+		if err = db.createCollection(ctx, stateName, options.CreateCollection(), clustered); err != nil {
+			return err
+		}
+	}
+
+	if err = db.createCollection(ctx, name, cco, bson.D{{"encryptedFields", bson.Raw(efDoc)}}); err != nil {
+		return err
+	}
+	_, err = db.Collection(name).Indexes().CreateOne(ctx, IndexModel{Keys: bson.D{{"__safeContent__", 1}}})
+	return err
+}
+
+// createCollection runs the create command for the collection named name with the options in cco
+// and the fields in extra.
+func (db *Database) createCollection(ctx context.Context, name string, cco *options.CreateCollectionOptions, extra bson.D) error {
+	cmd := append(createCollectionCommand(name, cco), extra...)
+	return db.runCreate(ctx, cmd)
+}
+
+// createCollectionCommand returns the create command for the collection named name with the
+// options in cco.
+func createCollectionCommand(name string, cco *options.CreateCollectionOptions) bson.D {
+	cmd := bson.D{{"create", name}}
+	if cco.Capped != nil {
+		cmd = append(cmd, bson.E{"capped", *cco.Capped})
+	}
+	if cco.SizeInBytes != nil {
+		cmd = append(cmd, bson.E{"size", *cco.SizeInBytes})
+	}
+	if cco.MaxDocuments != nil {
+		cmd = append(cmd, bson.E{"max", *cco.MaxDocuments})
+	}
+	if cco.Validator != nil {
+		cmd = append(cmd, bson.E{"validator", cco.Validator})
+	}
+	if cco.ValidationLevel != nil {
+		cmd = append(cmd, bson.E{"validationLevel", *cco.ValidationLevel})
+	}
+	if cco.ValidationAction != nil {
+		cmd = append(cmd, bson.E{"validationAction", *cco.ValidationAction})
+	}
+	if cco.Collation != nil {
+		cmd = append(cmd, bson.E{"collation", cco.Collation.ToDocument()})
+	}
+	if ts := cco.TimeSeries; ts != nil {
+		tsDoc := bson.D{{"timeField", ts.TimeField}}
+		if ts.MetaField != nil {
+			tsDoc = append(tsDoc, bson.E{"metaField", *ts.MetaField})
+		}
+		if ts.Granularity != nil {
+			tsDoc = append(tsDoc, bson.E{"granularity", *ts.Granularity})
+		}
+		if ts.BucketMaxSpanSeconds != nil {
+			// The server requires the rounding of the buckets to be given with their maximum span.
+			tsDoc = append(tsDoc,
+				bson.E{"bucketMaxSpanSeconds", *ts.BucketMaxSpanSeconds},
+				bson.E{"bucketRoundingSeconds", *ts.BucketMaxSpanSeconds},
+			)
+		}
+		cmd = append(cmd, bson.E{"timeseries", tsDoc})
+	}
+	if cco.ExpireAfterSeconds != nil {
+		cmd = append(cmd, bson.E{"expireAfterSeconds", *cco.ExpireAfterSeconds})
+	}
+	return cmd
+}
+
+// CreateView creates a view named viewName on the collection or view named viewOn. Queries on the
+// view return the documents of viewOn transformed by the aggregation pipeline.
+//
+// See https://docs.mongodb.com/manual/core/views/.
+func (db *Database) CreateView(ctx context.Context, viewName, viewOn string, pipeline interface{},
+	opts ...*options.CreateViewOptions) error {
+
+	ctx, cancel := operationContext(ctx, db.client.timeout)
+	defer cancel()
+
+	pipelineArr, err := transformAggregatePipeline(db.registry, pipeline)
+	if err != nil {
+		return err
 	}
 
+	cmd := bson.D{{"create", viewName}, {"viewOn", viewOn}, {"pipeline", pipelineArr}}
+	if cvo := options.MergeCreateViewOptions(opts...); cvo.Collation != nil {
+		cmd = append(cmd, bson.E{"collation", cvo.Collation.ToDocument()})
+	}
+	return db.runCreate(ctx, cmd)
+}
+
+// runCreate runs a create command with the write concern of the database.
+func (db *Database) runCreate(ctx context.Context, cmd bson.D) error {
+	runCmdOpts := options.RunCmd()
+	if sess := sessionFromContext(ctx); db.writeConcern != nil && !sess.TransactionRunning() {
+		runCmdOpts.SetWriteConcern(db.writeConcern)
+	}
+	return db.RunCommand(ctx, cmd, runCmdOpts).Err()
+}
+
+// encryptedStateCollectionName returns the name of the metadata collection of the collection named
+// name using Queryable Encryption with the given suffix, either "esc" or "ecoc". The name can be
+// overridden by the escCollection or ecocCollection field of encryptedFields.
+func encryptedStateCollectionName(encryptedFields bson.Raw, name, suffix string) string {
+	if override, ok := encryptedFields.Lookup(suffix + "Collection").StringValueOK(); ok {
+		return override
+	}
+	return "enxcol_." + name + "." + suffix
+}
+
+// ListCollections list collections from mongodb database. The collections are returned by a
+// cursor in batches when the server supports it, which is the case for wire version 3 and later.
+func (db *Database) ListCollections(ctx context.Context, filter interface{}, opts ...*options.ListCollectionsOptions) (*Cursor, error) {
+	ctx, cancel := operationContext(ctx, db.client.timeout)
+	defer cancel()
+
 	sess := sessionFromContext(ctx)
 
 	err := db.client.validSession(sess)
@@ -247,16 +593,71 @@ func (db *Database) ListCollections(ctx context.Context, filter interface{}, opt
 		readSelector,
 		db.client.id,
 		db.client.topology.SessionPool,
+		db.client.retryReads,
 		opts...,
 	)
+	err = timeoutError(ctx, err)
 	if err != nil {
 		return nil, replaceErrors(err)
 	}
 
-	cursor, err := newCursor(batchCursor, db.registry)
+	cursor, err := db.newCursor(ctx, batchCursor)
 	return cursor, replaceErrors(err)
 }
 
+// ListCollectionNames returns the names of the collections in the database that match filter. The
+// names are read from a cursor, so databases with many collections are listed in batches.
+func (db *Database) ListCollectionNames(ctx context.Context, filter interface{}, opts ...*options.ListCollectionsOptions) ([]string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	opts = append(opts, options.ListCollections().SetNameOnly(true))
+
+	cursor, err := db.ListCollections(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	names := make([]string, 0)
+	for cursor.Next(ctx) {
+		name, ok := cursor.Current.Lookup("name").StringValueOK()
+		if !ok {
+			return nil, errors.New("listCollections result has no collection name")
+		}
+		names = append(names, name)
+	}
+
+	return names, replaceErrors(cursor.Err())
+}
+
+// ListCollectionSpecifications returns the specifications of the collections and views in the
+// database that match filter, including the options they were created with.
+func (db *Database) ListCollectionSpecifications(ctx context.Context, filter interface{},
+	opts ...*options.ListCollectionsOptions) ([]*CollectionSpecification, error) {
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cursor, err := db.ListCollections(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	specs := make([]*CollectionSpecification, 0)
+	for cursor.Next(ctx) {
+		spec, err := newCollectionSpecification(cursor.Current)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+
+	return specs, replaceErrors(cursor.Err())
+}
+
 // ReadConcern returns the read concern of this database.
 func (db *Database) ReadConcern() *readconcern.ReadConcern {
 	return db.readConcern
@@ -280,3 +681,28 @@ func (db *Database) Watch(ctx context.Context, pipeline interface{},
 
 	return newDbChangeStream(ctx, db, pipeline, opts...)
 }
+
+// UseReadYourWritesSession creates a causally consistent session and calls fn with a SessionContext
+// for that session and a copy of this database configured with majority read and write concerns. The
+// other options of the database, such as its read preference and registry, are kept.
+// Operations run through the provided database and the SessionContext are guaranteed to observe
+// the effects of earlier writes made in fn, even when reads are routed to secondaries. The session
+// also uses majority concerns as the defaults for any transaction started in fn. The session is
+// ended when fn returns.
+func (db *Database) UseReadYourWritesSession(ctx context.Context, fn func(SessionContext, *Database) error) error {
+	rc := readconcern.Majority()
+	wc := writeconcern.New(writeconcern.WMajority())
+
+	sessOpts := options.Session().
+		SetCausalConsistency(true).
+		SetDefaultReadConcern(rc).
+		SetDefaultWriteConcern(wc)
+
+	rywDB := db.copy()
+	rywDB.readConcern = rc
+	rywDB.writeConcern = wc
+
+	return db.client.UseSessionWithOptions(ctx, sessOpts, func(sessCtx SessionContext) error {
+		return fn(sessCtx, rywDB)
+	})
+}
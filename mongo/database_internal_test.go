@@ -13,6 +13,8 @@ import (
 
 	"fmt"
 	"os"
+	"sort"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
@@ -23,6 +25,8 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/connstring"
 	"go.mongodb.org/mongo-driver/x/network/description"
 )
@@ -106,6 +110,22 @@ func TestDatabase_InheritOptions(t *testing.T) {
 	}
 }
 
+func TestDatabase_Registry(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+	legacy := bson.NewRegistryBuilder().Build()
+
+	db := client.Database("legacy", options.Database().SetRegistry(legacy))
+	require.Equal(t, legacy, db.registry)
+	require.Equal(t, legacy, db.Collection("coll").registry, "collections should inherit the registry of the database")
+	require.Equal(t, bson.DefaultRegistry, db.Collection("coll", options.Collection().SetRegistry(bson.DefaultRegistry)).registry)
+
+	other := client.Database("other")
+	require.Equal(t, bson.DefaultRegistry, other.registry)
+	require.Equal(t, legacy, other.Collection("coll", options.Collection().SetRegistry(legacy)).registry)
+	require.Equal(t, bson.DefaultRegistry, other.Collection("coll").registry)
+}
+
 func TestDatabase_ReplaceTopologyError(t *testing.T) {
 	t.Parallel()
 
@@ -166,6 +186,26 @@ func TestDatabase_RunCommand_DecodeStruct(t *testing.T) {
 	require.Equal(t, result.Ok, 1.0)
 }
 
+func TestDatabase_RunCommandExtJSON(t *testing.T) {
+	t.Parallel()
+
+	db := createTestDatabase(t, nil)
+
+	t.Run("invalid extended JSON", func(t *testing.T) {
+		_, err := db.RunCommandExtJSON(context.Background(), `{"ismaster": }`, false)
+		require.Error(t, err)
+	})
+	t.Run("relaxed reply", func(t *testing.T) {
+		res, err := db.RunCommandExtJSON(context.Background(), `{"ismaster": {"$numberInt": "1"}}`, false)
+		require.NoError(t, err)
+
+		var reply bson.M
+		require.NoError(t, bson.UnmarshalExtJSON([]byte(res), false, &reply))
+		require.Equal(t, true, reply["ismaster"])
+		require.Equal(t, 1.0, reply["ok"])
+	})
+}
+
 func TestDatabase_NilDocumentError(t *testing.T) {
 	t.Parallel()
 
@@ -181,6 +221,20 @@ func TestDatabase_NilDocumentError(t *testing.T) {
 	require.Equal(t, err, ErrNilDocument)
 }
 
+func TestDatabase_RunCommandUnordered(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+	db := client.Database("test")
+
+	cmd := map[string]interface{}{"count": "coll", "query": bson.D{}}
+	require.Equal(t, ErrMapForOrderedArgument, db.RunCommand(context.Background(), cmd).Err())
+	_, err = db.RunCommandCursor(context.Background(), cmd)
+	require.Equal(t, ErrMapForOrderedArgument, err)
+
+	_, _, err = db.processRunCommand(context.Background(), bson.M{"ping": 1})
+	require.NoError(t, err)
+}
+
 func TestDatabase_Drop(t *testing.T) {
 	t.Parallel()
 
@@ -383,3 +437,223 @@ func TestDatabase_ListCollections(t *testing.T) {
 		})
 	}
 }
+
+func TestDatabase_ListCollectionNames(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	db := createTestDatabase(t, nil, options.Database().SetWriteConcern(wcMajority))
+	defer func() {
+		_ = db.Drop(context.Background())
+	}()
+
+	var want []string
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("coll%d", i)
+		require.NoError(t, db.CreateCollection(context.Background(), name))
+		want = append(want, name)
+	}
+
+	names, err := db.ListCollectionNames(context.Background(), bson.D{}, options.ListCollections().SetBatchSize(2))
+	require.NoError(t, err)
+	sort.Strings(names)
+	require.Equal(t, want, names)
+
+	names, err = db.ListCollectionNames(context.Background(), bson.D{{"name", "coll1"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"coll1"}, names)
+}
+
+func TestCreateCollectionCommand(t *testing.T) {
+	validator := bson.D{{"x", bson.D{{"$exists", true}}}}
+	collation := &options.Collation{Locale: "fr"}
+
+	testCases := []struct {
+		name string
+		opts *options.CreateCollectionOptions
+		want bson.D
+	}{
+		{"no options", options.CreateCollection(), bson.D{{"create", "coll"}}},
+		{
+			"capped",
+			options.CreateCollection().SetCapped(true).SetSizeInBytes(4096).SetMaxDocuments(10),
+			bson.D{{"create", "coll"}, {"capped", true}, {"size", int64(4096)}, {"max", int64(10)}},
+		},
+		{
+			"validator",
+			options.CreateCollection().SetValidator(validator).SetValidationLevel("moderate").SetValidationAction("warn"),
+			bson.D{{"create", "coll"}, {"validator", validator}, {"validationLevel", "moderate"}, {"validationAction", "warn"}},
+		},
+		{
+			"collation",
+			options.CreateCollection().SetCollation(collation),
+			bson.D{{"create", "coll"}, {"collation", collation.ToDocument()}},
+		},
+		{
+			"time series",
+			options.CreateCollection().
+				SetTimeSeries(options.TimeSeries().SetTimeField("ts").SetMetaField("sensor").SetGranularity("minutes")).
+				SetExpireAfterSeconds(3600),
+			bson.D{
+				{"create", "coll"},
+				{"timeseries", bson.D{{"timeField", "ts"}, {"metaField", "sensor"}, {"granularity", "minutes"}}},
+				{"expireAfterSeconds", int64(3600)},
+			},
+		},
+		{
+			"time series bucket span",
+			options.CreateCollection().SetTimeSeries(options.TimeSeries().SetTimeField("ts").SetBucketMaxSpanSeconds(60)),
+			bson.D{
+				{"create", "coll"},
+				{"timeseries", bson.D{{"timeField", "ts"}, {"bucketMaxSpanSeconds", int64(60)}, {"bucketRoundingSeconds", int64(60)}}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, createCollectionCommand("coll", tc.opts))
+		})
+	}
+}
+
+func TestDatabase_CreateView(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := createTestDatabase(t, nil, options.Database().SetWriteConcern(wcMajority))
+	skipIfBelow34(t, db)
+	defer func() {
+		_ = db.Drop(context.Background())
+	}()
+
+	coll := db.Collection("source")
+	_, err := coll.InsertMany(context.Background(), []interface{}{bson.D{{"x", 1}}, bson.D{{"x", 2}}})
+	require.NoError(t, err)
+
+	pipeline := bson.A{bson.D{{"$match", bson.D{{"x", bson.D{{"$gt", 1}}}}}}}
+	require.NoError(t, db.CreateView(context.Background(), "view", "source", pipeline))
+
+	count, err := db.Collection("view").CountDocuments(context.Background(), bson.D{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestDatabase_UseReadYourWritesSession(t *testing.T) {
+	skipIfBelow36(t)
+
+	name := "TestDatabase_UseReadYourWritesSession"
+	reg := bson.NewRegistryBuilder().Build()
+	db := createTestDatabase(t, &name, options.Database().SetReadPreference(readpref.Secondary()).SetRegistry(reg))
+	defer func() {
+		_ = db.Drop(context.Background())
+	}()
+
+	err := db.UseReadYourWritesSession(context.Background(), func(sctx SessionContext, rywDB *Database) error {
+		require.Equal(t, readconcern.Majority(), rywDB.ReadConcern())
+		require.Equal(t, writeconcern.New(writeconcern.WMajority()), rywDB.WriteConcern())
+		require.Equal(t, db.ReadPreference(), rywDB.ReadPreference())
+		require.True(t, rywDB.registry == reg, "the registry of the database should be kept")
+
+		coll := rywDB.Collection("coll")
+		_, err := coll.InsertOne(sctx, bson.D{{"x", 1}})
+		require.NoError(t, err)
+
+		count, err := coll.CountDocuments(sctx, bson.D{{"x", 1}})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestDatabase_Aggregate(t *testing.T) {
+	skipIfBelow36(t)
+
+	db := createTestDatabase(t, nil)
+
+	t.Run("currentOp is run against admin", func(t *testing.T) {
+		cursor, err := db.Aggregate(context.Background(), bson.A{
+			bson.D{{"$currentOp", bson.D{}}},
+			bson.D{{"$limit", 1}},
+		})
+		require.NoError(t, err)
+		defer cursor.Close(context.Background())
+		require.True(t, cursor.Next(context.Background()))
+	})
+	t.Run("listLocalSessions", func(t *testing.T) {
+		cursor, err := db.Aggregate(context.Background(), bson.A{bson.D{{"$listLocalSessions", bson.D{}}}})
+		require.NoError(t, err)
+		require.NoError(t, cursor.Close(context.Background()))
+	})
+}
+
+func TestFirstStageName(t *testing.T) {
+	require.Equal(t, "", firstStageName(bsonx.Arr{}))
+	require.Equal(t, "", firstStageName(bsonx.Arr{bsonx.Document(bsonx.Doc{})}))
+	require.Equal(t, "$currentOp", firstStageName(bsonx.Arr{
+		bsonx.Document(bsonx.Doc{{"$currentOp", bsonx.Document(bsonx.Doc{})}}),
+		bsonx.Document(bsonx.Doc{{"$match", bsonx.Document(bsonx.Doc{})}}),
+	}))
+}
+
+func TestValidateRunCommandConcerns(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+	txn, err := session.NewClientSession(session.NewPool(nil), client.id, session.Explicit)
+	require.NoError(t, err)
+	require.NoError(t, txn.StartTransaction(nil))
+
+	rc := options.RunCmd().SetReadConcern(readconcern.Majority())
+	wc := options.RunCmd().SetWriteConcern(writeconcern.New(writeconcern.WMajority()))
+	find := bsonx.Doc{{"find", bsonx.String("coll")}}
+
+	testCases := []struct {
+		name string
+		cmd  bsonx.Doc
+		sess *session.Client
+		opts *options.RunCmdOptions
+		err  error
+	}{
+		{"no concerns", find, txn, options.RunCmd(), nil},
+		{"read concern", find, nil, rc, nil},
+		{"write concern", bsonx.Doc{{"insert", bsonx.String("coll")}}, nil, wc, nil},
+		{"read concern in transaction", find, txn, rc, ErrReadConcernInTransaction},
+		{"write concern in transaction", find, txn, wc, ErrWriteConcernInTransaction},
+		{"getMore", bsonx.Doc{{"getMore", bsonx.Int64(1)}}, nil, rc, errors.New("getMore commands use the read and write concern of the command that created the cursor")},
+		{"command has read concern", append(find.Copy(), bsonx.Elem{"readConcern", bsonx.Document(bsonx.Doc{})}), nil, rc, errors.New("command already contains a readConcern")},
+		{"command has write concern", bsonx.Doc{{"insert", bsonx.String("coll")}, {"writeConcern", bsonx.Document(bsonx.Doc{})}}, nil, wc, errors.New("command already contains a writeConcern")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.err, validateRunCommandConcerns(tc.cmd, tc.sess, tc.opts))
+		})
+	}
+}
+
+func TestRunCommandContext(t *testing.T) {
+	withDeadline, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	fromDeadline := options.RunCmd().SetMaxTimeFromDeadline(true)
+
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		opts    []*options.RunCmdOptions
+		timeout bool
+	}{
+		{"deadline", withDeadline, []*options.RunCmdOptions{fromDeadline}, true},
+		{"not requested", withDeadline, nil, false},
+		{"disabled", withDeadline, []*options.RunCmdOptions{fromDeadline, options.RunCmd().SetMaxTimeFromDeadline(false)}, false},
+		{"no deadline", context.Background(), []*options.RunCmdOptions{fromDeadline}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := runCommandContext(tc.ctx, tc.opts...)
+			require.Equal(t, tc.timeout, connection.HasOperationTimeout(ctx))
+			deadline, _ := tc.ctx.Deadline()
+			actual, _ := ctx.Deadline()
+			require.Equal(t, deadline, actual)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DisconnectPhase is a step of Client.Disconnect.
+type DisconnectPhase string
+
+// These constants are the phases of Client.Disconnect, in the order they run.
+const (
+	// DisconnectClosingCursors is the phase in which the open cursors are closed.
+	DisconnectClosingCursors DisconnectPhase = "closingCursors"
+	// DisconnectWaitingForOperations is the phase in which Disconnect waits for the operations in
+	// progress to return their connections.
+	DisconnectWaitingForOperations DisconnectPhase = "waitingForOperations"
+	// DisconnectEndingSessions is the phase in which the server sessions are ended.
+	DisconnectEndingSessions DisconnectPhase = "endingSessions"
+	// DisconnectClosingPools is the phase in which the monitoring goroutines are stopped and the
+	// connection pools are closed.
+	DisconnectClosingPools DisconnectPhase = "closingPools"
+	// DisconnectComplete is reported once Disconnect has finished.
+	DisconnectComplete DisconnectPhase = "complete"
+)
+
+// DisconnectProgress reports what a Client.Disconnect in progress is waiting for.
+type DisconnectProgress struct {
+	Phase   DisconnectPhase
+	Elapsed time.Duration // The time since Disconnect was called.
+
+	InUseConnections int // The number of connections checked out by operations in progress.
+	PendingCursors   int // The number of cursors that are not closed yet.
+	// OpenTransactions is the number of transactions that have been started and have not been
+	// committed, aborted, or ended with their session yet.
+	OpenTransactions int
+}
+
+type disconnectProgressKey struct{}
+
+type disconnectProgressConfig struct {
+	interval time.Duration
+	fn       func(DisconnectProgress)
+}
+
+// WithDisconnectProgress returns a context that makes a Client.Disconnect called with it report its
+// progress to fn. fn is called when each phase starts, then every interval while the phase runs, and
+// once more with the DisconnectComplete phase when Disconnect returns. This lets an application log
+// why a shutdown is taking long and decide when to give up by canceling the context, which closes
+// the connections that are still in use. An interval of 0 or less only reports the phase changes.
+// The calls to fn are not concurrent, and Disconnect waits for fn to return.
+func WithDisconnectProgress(ctx context.Context, interval time.Duration, fn func(DisconnectProgress)) context.Context {
+	return context.WithValue(ctx, disconnectProgressKey{}, disconnectProgressConfig{interval: interval, fn: fn})
+}
+
+// disconnectReporter reports the progress of a Client.Disconnect. A nil *disconnectReporter, as
+// returned when the context of Disconnect has no progress callback, reports nothing.
+type disconnectReporter struct {
+	client *Client
+	cfg    disconnectProgressConfig
+	start  time.Time
+
+	mu    sync.Mutex // serializes calls to cfg.fn and protects phase
+	phase DisconnectPhase
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func newDisconnectReporter(ctx context.Context, c *Client) *disconnectReporter {
+	cfg, ok := ctx.Value(disconnectProgressKey{}).(disconnectProgressConfig)
+	if !ok || cfg.fn == nil {
+		return nil
+	}
+
+	r := &disconnectReporter{
+		client:  c,
+		cfg:     cfg,
+		start:   time.Now(),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if cfg.interval <= 0 {
+		close(r.stopped)
+		return r
+	}
+	go r.tick()
+	return r
+}
+
+// tick reports the progress of the current phase every interval until stop is called.
+func (r *disconnectReporter) tick() {
+	defer close(r.stopped)
+
+	ticker := time.NewTicker(r.cfg.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.mu.Lock()
+			r.report()
+			r.mu.Unlock()
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// enter starts the phase and reports it.
+func (r *disconnectReporter) enter(phase DisconnectPhase) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.phase = phase
+	r.report()
+}
+
+// stop stops the periodic reports and reports the DisconnectComplete phase.
+func (r *disconnectReporter) stop() {
+	if r == nil {
+		return
+	}
+
+	close(r.done)
+	<-r.stopped
+	r.enter(DisconnectComplete)
+}
+
+// report calls cfg.fn with the current progress. It must be called with mu held.
+func (r *disconnectReporter) report() {
+	if r.phase == "" {
+		return
+	}
+
+	r.cfg.fn(DisconnectProgress{
+		Phase:            r.phase,
+		Elapsed:          time.Since(r.start),
+		InUseConnections: r.client.topology.InUseConnections(),
+		PendingCursors:   r.client.cursors.pending(),
+		OpenTransactions: r.client.topology.SessionPool.OpenTransactions(),
+	})
+}
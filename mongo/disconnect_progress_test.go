@@ -0,0 +1,48 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisconnectProgress(t *testing.T) {
+	client, err := NewClient()
+	require.Nil(t, err)
+	tbc := newTestBatchCursor(2, 5)
+	cursor, err := newCursor(tbc, nil)
+	require.Nil(t, err)
+	client.cursors.track(cursor)
+
+	var reports []DisconnectProgress
+	ctx := WithDisconnectProgress(context.Background(), 0, func(p DisconnectProgress) {
+		reports = append(reports, p)
+	})
+	_ = client.Disconnect(ctx)
+
+	phases := make([]DisconnectPhase, 0, len(reports))
+	for _, p := range reports {
+		phases = append(phases, p.Phase)
+	}
+	require.Equal(t, []DisconnectPhase{
+		DisconnectClosingCursors,
+		DisconnectWaitingForOperations,
+		DisconnectEndingSessions,
+		DisconnectClosingPools,
+		DisconnectComplete,
+	}, phases)
+	require.Equal(t, 1, reports[0].PendingCursors)
+	require.Equal(t, 0, reports[1].PendingCursors)
+	require.Equal(t, 1, tbc.closeCalls)
+	for _, p := range reports {
+		require.Equal(t, 0, p.InUseConnections)
+		require.Equal(t, 0, p.OpenTransactions)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var errMissingName = errors.New("name is required")
+
+func TestDocumentValidator(t *testing.T) {
+	var infos []options.DocumentInfo
+	validator := options.DocumentValidatorFunc(func(ctx context.Context, info options.DocumentInfo, doc bson.Raw) error {
+		infos = append(infos, info)
+		if info.Operation == "insert" {
+			if _, err := doc.LookupErr("_id"); err != nil {
+				return errors.New("_id was not added")
+			}
+		}
+		if _, err := doc.LookupErr("name"); err != nil {
+			return errMissingName
+		}
+		return nil
+	})
+	client, err := NewClient(options.Client().SetDocumentValidator(validator))
+	require.NoError(t, err)
+	coll := client.Database("test").Collection("people")
+	ctx := context.Background()
+
+	requireValidationError := func(t *testing.T, err error, op string, index int) {
+		t.Helper()
+		require.Equal(t, DocumentValidationError{
+			Namespace: "test.people",
+			Operation: op,
+			Index:     index,
+			Wrapped:   errMissingName,
+		}, err)
+		require.Equal(t, options.DocumentInfo{
+			DatabaseName:   "test",
+			CollectionName: "people",
+			Operation:      op,
+			Index:          index,
+		}, infos[len(infos)-1])
+	}
+
+	t.Run("InsertOne", func(t *testing.T) {
+		_, err := coll.InsertOne(ctx, bson.D{{"age", 30}})
+		requireValidationError(t, err, "insert", 0)
+	})
+	t.Run("InsertMany", func(t *testing.T) {
+		_, err := coll.InsertMany(ctx, []interface{}{bson.D{{"name", "a"}}, bson.D{{"age", 30}}})
+		requireValidationError(t, err, "insert", 1)
+	})
+	t.Run("ReplaceOne", func(t *testing.T) {
+		_, err := coll.ReplaceOne(ctx, bson.D{}, bson.D{{"age", 30}})
+		requireValidationError(t, err, "replace", 0)
+	})
+	t.Run("FindOneAndReplace", func(t *testing.T) {
+		err := coll.FindOneAndReplace(ctx, bson.D{}, bson.D{{"age", 30}}).Err()
+		requireValidationError(t, err, "replace", 0)
+	})
+	t.Run("BulkWrite", func(t *testing.T) {
+		_, err := coll.BulkWrite(ctx, []WriteModel{
+			NewInsertOneModel().SetDocument(bson.D{{"name", "a"}}),
+			NewDeleteOneModel().SetFilter(bson.D{}),
+			NewReplaceOneModel().SetFilter(bson.D{}).SetReplacement(bson.D{{"age", 30}}),
+		})
+		requireValidationError(t, err, "replace", 2)
+	})
+	t.Run("Unwrap", func(t *testing.T) {
+		_, err := coll.InsertOne(ctx, bson.D{{"age", 30}})
+		require.Equal(t, errMissingName, err.(DocumentValidationError).Unwrap())
+	})
+}
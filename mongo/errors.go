@@ -8,13 +8,21 @@ package mongo
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/mongocrypt"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
 	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/connection"
+	"go.mongodb.org/mongo-driver/x/network/description"
 	"go.mongodb.org/mongo-driver/x/network/result"
 )
 
@@ -34,10 +42,157 @@ var ErrNilDocument = errors.New("document is nil")
 // to a function wehere the field is required.
 var ErrEmptySlice = errors.New("must provide at least one element in input slice")
 
+// ErrReadConcernInTransaction is returned when a read concern is specified for an operation
+// executed in a transaction. The read concern of a transaction is set when it is started.
+var ErrReadConcernInTransaction = errors.New("cannot set read concern for an operation in a transaction")
+
+// ErrWriteConcernInTransaction is returned when a write concern is specified for an operation
+// executed in a transaction. The write concern of a transaction is set when it is started.
+var ErrWriteConcernInTransaction = errors.New("cannot set write concern for an operation in a transaction")
+
+// ErrMapForOrderedArgument is returned when a map with more than one key is passed as a command.
+// Map keys are not ordered, but the first key of a command document must be the command name, so
+// commands must be given as an ordered document such as bson.D.
+var ErrMapForOrderedArgument = errors.New("multi-key map passed in for ordered parameter")
+
+// ReadOnlyError is returned when a client configured with ClientOptions.SetReadOnly is used to run a
+// command that may modify data. The command is not sent to the server.
+type ReadOnlyError struct {
+	Command string // The name of the rejected command.
+}
+
+// Error implements the error interface.
+func (e ReadOnlyError) Error() string {
+	return fmt.Sprintf("%s is a write command and cannot be run by a read-only client", e.Command)
+}
+
+// NamespaceDeniedError is returned when a client configured with ClientOptions.SetAllowedNamespaces
+// or ClientOptions.SetDeniedNamespaces is used to run a command on a namespace it does not allow.
+// The command is not sent to the server.
+type NamespaceDeniedError struct {
+	Namespace string // The namespace that is not allowed, with the collection "*" for a whole database.
+	Command   string // The name of the rejected command.
+}
+
+// Error implements the error interface.
+func (e NamespaceDeniedError) Error() string {
+	return fmt.Sprintf("%s command on namespace %s is not allowed by the namespace filter of the client", e.Command, e.Namespace)
+}
+
+// DocumentValidationError is returned when the DocumentValidator of a client configured with
+// ClientOptions.SetDocumentValidator rejects a document that is about to be written. Nothing is
+// sent to the server.
+type DocumentValidationError struct {
+	Namespace string // The namespace the document was to be written to.
+	Operation string // "insert" or "replace".
+	Index     int    // The position of the document in the documents or models passed to the operation.
+	Wrapped   error  // The error returned by the validator.
+}
+
+// Error implements the error interface.
+func (e DocumentValidationError) Error() string {
+	return fmt.Sprintf("document %d for %s on namespace %s is invalid: %v", e.Index, e.Operation, e.Namespace, e.Wrapped)
+}
+
+// Unwrap returns the underlying error.
+func (e DocumentValidationError) Unwrap() error {
+	return e.Wrapped
+}
+
+// OperationLimitError is returned when a command of a client configured with
+// ClientOptions.SetMaxInFlightOperations waited for a slot until its context was done. The command
+// is not sent to the server.
+type OperationLimitError struct {
+	Limit   int   // The maximum number of commands in flight.
+	Wrapped error // The error of the context.
+}
+
+// Error implements the error interface.
+func (e OperationLimitError) Error() string {
+	return fmt.Sprintf("gave up waiting for one of %d in-flight operation slots: %v", e.Limit, e.Wrapped)
+}
+
+// Unwrap returns the underlying error.
+func (e OperationLimitError) Unwrap() error {
+	return e.Wrapped
+}
+
+// UnsatisfiableWriteConcernError is returned by writes when ClientOptions.CheckWriteConcern is set
+// and their write concern requires acknowledgement from more members than the replica set currently
+// has data-bearing members available, so the write could not be acknowledged until members recover.
+type UnsatisfiableWriteConcernError struct {
+	W                  int
+	DataBearingMembers int
+}
+
+// Error implements the error interface.
+func (e UnsatisfiableWriteConcernError) Error() string {
+	return fmt.Sprintf("write concern w:%d cannot be satisfied: the replica set has %d available data-bearing members",
+		e.W, e.DataBearingMembers)
+}
+
+// ErrDirectConnectionMultipleHosts is returned when a client is configured to connect directly to a
+// server and more than one host is given.
+var ErrDirectConnectionMultipleHosts = errors.New("a direct connection cannot be made to multiple hosts")
+
+// ErrInvalidLoadBalancedOptions is returned when a client is configured to connect to a load
+// balancer along with multiple hosts, a replica set name, or a direct connection.
+var ErrInvalidLoadBalancedOptions = errors.New("loadBalanced cannot be combined with multiple hosts, a replica set name, or a direct connection")
+
+// ErrMinPoolSizeGreaterThanMax is returned when a client is configured with a min pool size greater
+// than its max pool size.
+var ErrMinPoolSizeGreaterThanMax = errors.New("min pool size cannot be greater than max pool size")
+
+// ErrAppNameTooLong is returned when a client is configured with an application name longer than
+// the 128 bytes servers accept in the handshake.
+var ErrAppNameTooLong = errors.New("app name cannot be longer than 128 bytes")
+
+// These errors are matched by the CommandError, WriteError, and WriteConcernError values that the
+// server returns with the corresponding codes, so that they can be checked for with errors.Is
+// instead of by code. Errors that wrap such values, like WriteException, TimeoutError, and
+// BulkWriteException, match them too.
+var (
+	ErrNamespaceNotFound = errors.New("namespace not found")
+	ErrDuplicateKey      = errors.New("duplicate key")
+	ErrCursorNotFound    = errors.New("cursor not found")
+	ErrWriteConflict     = errors.New("write conflict")
+	ErrMaxTimeMSExpired  = errors.New("operation exceeded time limit")
+)
+
+// isServerError returns true if target is the error that corresponds to the server error code and
+// message.
+func isServerError(target error, code int, message string) bool {
+	switch target {
+	case ErrNamespaceNotFound:
+		return code == 26
+	case ErrDuplicateKey:
+		return isDuplicateKeyCode(code, message)
+	case ErrCursorNotFound:
+		return code == 43
+	case ErrWriteConflict:
+		return code == 112
+	case ErrMaxTimeMSExpired:
+		return code == maxTimeMSExpiredCode
+	}
+	return false
+}
+
 func replaceErrors(err error) error {
 	if err == topology.ErrTopologyClosed {
 		return ErrClientDisconnected
 	}
+	if err == command.ErrUnacknowledgedWrite {
+		return ErrUnacknowledgedWrite
+	}
+	if nde, ok := err.(command.NamespaceDeniedError); ok {
+		return NamespaceDeniedError{Namespace: nde.Namespace, Command: nde.Command}
+	}
+	if roe, ok := err.(command.ReadOnlyError); ok {
+		return ReadOnlyError{Command: roe.Command}
+	}
+	if ole, ok := err.(command.OperationLimitError); ok {
+		return OperationLimitError{Limit: ole.Limit, Wrapped: ole.Wrapped}
+	}
 	if ce, ok := err.(command.Error); ok {
 		return CommandError{Code: ce.Code, Message: ce.Message, Labels: ce.Labels, Name: ce.Name}
 	}
@@ -45,12 +200,83 @@ func replaceErrors(err error) error {
 		return BulkWriteException{
 			WriteConcernError: convertWriteConcernError(conv.WriteConcernError),
 			WriteErrors:       convertBulkWriteErrors(conv.WriteErrors),
+			Labels:            conv.Labels,
 		}
 	}
 
+	if me, ok := err.(mongocrypt.Error); ok {
+		return MongocryptError{Code: me.Code, Message: me.Message}
+	}
+
 	return err
 }
 
+// maxTimeMSExpiredCode is the code of the error returned by the server when a command exceeds its
+// maxTimeMS.
+const maxTimeMSExpiredCode = 50
+
+// TimeoutError is returned when an operation does not complete within the timeout of its client.
+type TimeoutError struct {
+	Wrapped error // The error the operation failed with when it timed out.
+}
+
+// Error implements the error interface.
+func (e TimeoutError) Error() string {
+	return "operation timed out: " + e.Wrapped.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e TimeoutError) Unwrap() error {
+	return e.Wrapped
+}
+
+// timeoutError converts err to a TimeoutError if the operation timeout of ctx has expired, or if
+// the server stopped the command because it exceeded the maxTimeMS derived from that timeout.
+// Other errors are returned unchanged.
+func timeoutError(ctx context.Context, err error) error {
+	if err == nil || !connection.HasOperationTimeout(ctx) {
+		return err
+	}
+	if _, ok := err.(TimeoutError); ok {
+		return err
+	}
+
+	replaced := replaceErrors(err)
+	if ce, ok := replaced.(CommandError); ok && ce.Code == maxTimeMSExpiredCode {
+		return TimeoutError{Wrapped: replaced}
+	}
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+		return TimeoutError{Wrapped: replaced}
+	}
+	return err
+}
+
+// MongocryptError represents an error from libmongocrypt while encrypting or decrypting.
+type MongocryptError struct {
+	Code    int32
+	Message string
+}
+
+// Error implements the error interface.
+func (m MongocryptError) Error() string {
+	return fmt.Sprintf("mongocrypt error %d: %v", m.Code, m.Message)
+}
+
+// MongocryptdError represents an error while communicating with or spawning mongocryptd.
+type MongocryptdError struct {
+	Wrapped error
+}
+
+// Error implements the error interface.
+func (e MongocryptdError) Error() string {
+	return fmt.Sprintf("mongocryptd communication error: %v", e.Wrapped)
+}
+
+// Unwrap returns the underlying error.
+func (e MongocryptdError) Unwrap() error {
+	return e.Wrapped
+}
+
 // CommandError represents an error in execution of a command against the database.
 type CommandError struct {
 	Code    int32
@@ -69,11 +295,19 @@ func (e CommandError) Error() string {
 
 // HasErrorLabel returns true if the error contains the specified label.
 func (e CommandError) HasErrorLabel(label string) bool {
-	if e.Labels != nil {
-		for _, l := range e.Labels {
-			if l == label {
-				return true
-			}
+	return hasLabel(e.Labels, label)
+}
+
+// Is returns true if target is the error, like ErrNamespaceNotFound, that corresponds to the code
+// of the error.
+func (e CommandError) Is(target error) bool {
+	return isServerError(target, int(e.Code), e.Message)
+}
+
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
 		}
 	}
 	return false
@@ -89,6 +323,12 @@ type WriteError struct {
 
 func (we WriteError) Error() string { return we.Message }
 
+// Is returns true if target is the error, like ErrDuplicateKey, that corresponds to the code of the
+// error.
+func (we WriteError) Is(target error) bool {
+	return isServerError(target, we.Code, we.Message)
+}
+
 // WriteErrors is a group of non-write concern failures that occurred as a result
 // of a write operation.
 type WriteErrors []WriteError
@@ -124,10 +364,21 @@ type WriteConcernError struct {
 
 func (wce WriteConcernError) Error() string { return wce.Message }
 
+// Is returns true if target is the error that corresponds to the code of the error.
+func (wce WriteConcernError) Is(target error) bool {
+	return isServerError(target, wce.Code, wce.Message)
+}
+
 // WriteException is an error for a non-bulk write operation.
 type WriteException struct {
 	WriteConcernError *WriteConcernError
 	WriteErrors       WriteErrors
+	Labels            []string // The error labels the server attached to the response.
+}
+
+// HasErrorLabel returns true if the error contains the specified label.
+func (mwe WriteException) HasErrorLabel(label string) bool {
+	return hasLabel(mwe.Labels, label)
 }
 
 func (mwe WriteException) Error() string {
@@ -138,6 +389,18 @@ func (mwe WriteException) Error() string {
 	return buf.String()
 }
 
+// Unwrap returns the write errors and the write concern error, if any.
+func (mwe WriteException) Unwrap() []error {
+	errs := make([]error, 0, len(mwe.WriteErrors)+1)
+	for _, we := range mwe.WriteErrors {
+		errs = append(errs, we)
+	}
+	if mwe.WriteConcernError != nil {
+		errs = append(errs, *mwe.WriteConcernError)
+	}
+	return errs
+}
+
 func convertBulkWriteErrors(errors []driverlegacy.BulkWriteError) []BulkWriteError {
 	bwErrors := make([]BulkWriteError, 0, len(errors))
 	for _, err := range errors {
@@ -178,6 +441,12 @@ func (bwe BulkWriteError) Error() string {
 type BulkWriteException struct {
 	WriteConcernError *WriteConcernError
 	WriteErrors       []BulkWriteError
+	Labels            []string // The error labels the server attached to the responses.
+}
+
+// HasErrorLabel returns true if the error contains the specified label.
+func (bwe BulkWriteException) HasErrorLabel(label string) bool {
+	return hasLabel(bwe.Labels, label)
 }
 
 func (bwe BulkWriteException) Error() string {
@@ -188,6 +457,101 @@ func (bwe BulkWriteException) Error() string {
 	return buf.String()
 }
 
+// Unwrap returns the write errors and the write concern error, if any.
+func (bwe BulkWriteException) Unwrap() []error {
+	errs := make([]error, 0, len(bwe.WriteErrors)+1)
+	for _, we := range bwe.WriteErrors {
+		errs = append(errs, we)
+	}
+	if bwe.WriteConcernError != nil {
+		errs = append(errs, *bwe.WriteConcernError)
+	}
+	return errs
+}
+
+// IsDuplicateKeyError returns true if err, or an error it wraps, is a duplicate key error. This
+// includes write errors and write concern errors in a WriteException or BulkWriteException.
+func IsDuplicateKeyError(err error) bool {
+	return anyError(err, func(err error) bool {
+		switch e := err.(type) {
+		case CommandError:
+			return isDuplicateKeyCode(int(e.Code), e.Message)
+		case WriteError:
+			return isDuplicateKeyCode(e.Code, e.Message)
+		case BulkWriteError:
+			return isDuplicateKeyCode(e.Code, e.Message)
+		case WriteConcernError:
+			return isDuplicateKeyCode(e.Code, e.Message)
+		}
+		return false
+	})
+}
+
+// isDuplicateKeyCode returns true if code and message are those of a duplicate key error. Some
+// servers report a duplicate key error from a sharded cluster with code 16460 and the E11000 message.
+func isDuplicateKeyCode(code int, message string) bool {
+	switch code {
+	case 11000, 11001, 12582:
+		return true
+	case 16460:
+		return strings.Contains(message, " E11000 ")
+	}
+	return false
+}
+
+// IsTimeout returns true if err, or an error it wraps, is a timeout. This includes a TimeoutError,
+// an expired context deadline, a server selection timeout, a command that exceeded its maxTimeMS,
+// and a network timeout.
+func IsTimeout(err error) bool {
+	return anyError(err, func(err error) bool {
+		switch e := err.(type) {
+		case TimeoutError:
+			return true
+		case CommandError:
+			return e.Code == maxTimeMSExpiredCode
+		case net.Error:
+			return e.Timeout()
+		}
+		return err == context.DeadlineExceeded || err == topology.ErrServerSelectionTimeout
+	})
+}
+
+// IsNetworkError returns true if err, or an error it wraps, is an error reading from or writing to
+// a connection to the server.
+func IsNetworkError(err error) bool {
+	return anyError(err, func(err error) bool {
+		switch e := err.(type) {
+		case CommandError:
+			return e.HasErrorLabel(command.NetworkError)
+		case connection.Error, connection.NetworkError, topology.ConnectionError, *net.OpError:
+			return true
+		}
+		return false
+	})
+}
+
+// anyError returns true if fn returns true for err or any error that err wraps, following both
+// Unwrap() error and Unwrap() []error.
+func anyError(err error, fn func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+	if fn(err) {
+		return true
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return anyError(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range u.Unwrap() {
+			if anyError(wrapped, fn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // returnResult is used to determine if a function calling processWriteError should return
 // the result or return nil. Since the processWriteError function is used by many different
 // methods, both *One and *Many, we need a way to differentiate if the method should return
@@ -206,8 +570,9 @@ const (
 // the calling method's type, it should return the result object in addition to the error.
 // This function will wrap the errors from other packages and return them as errors from this package.
 //
-// WriteConcernError will be returned over WriteErrors if both are present.
-func processWriteError(wce *result.WriteConcernError, wes []result.WriteError, err error) (returnResult, error) {
+// WriteConcernError will be returned over WriteErrors if both are present, and labels are the error
+// labels of the response.
+func processWriteError(wce *result.WriteConcernError, wes []result.WriteError, labels []string, err error) (returnResult, error) {
 	switch {
 	case err == command.ErrUnacknowledgedWrite:
 		return rrAll, ErrUnacknowledgedWrite
@@ -217,8 +582,37 @@ func processWriteError(wce *result.WriteConcernError, wes []result.WriteError, e
 		return rrMany, WriteException{
 			WriteConcernError: convertWriteConcernError(wce),
 			WriteErrors:       writeErrorsFromResult(wes),
+			Labels:            labels,
 		}
 	default:
 		return rrAll, nil
 	}
 }
+
+// checkWriteConcern returns an UnsatisfiableWriteConcernError if wc has a numeric w greater than the
+// number of data-bearing members of the replica set described by desc that are available. Members
+// that have not been checked yet, while the client starts or after they are discovered, are counted
+// as available, but members that are Unknown because their last check failed are not. Other
+// topologies are not checked because their members do not acknowledge writes individually.
+func checkWriteConcern(desc description.Topology, wc *writeconcern.WriteConcern) error {
+	if desc.Kind != description.ReplicaSetWithPrimary && desc.Kind != description.ReplicaSetNoPrimary {
+		return nil
+	}
+	if wc == nil {
+		return nil
+	}
+	w, ok := wc.GetW().(int)
+	if !ok || w <= 1 {
+		return nil
+	}
+	var members int
+	for _, s := range desc.Servers {
+		if s.DataBearing() || (s.Kind == description.Unknown && s.LastError == nil) {
+			members++
+		}
+	}
+	if w > members {
+		return UnsatisfiableWriteConcernError{W: w, DataBearingMembers: members}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
+	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/connection"
+	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/result"
+)
+
+func TestErrorHelpers(t *testing.T) {
+	dupKey := WriteException{WriteErrors: WriteErrors{{Code: 11000, Message: "E11000 duplicate key error"}}}
+	selection := topology.ServerSelectionError{Wrapped: topology.ErrServerSelectionTimeout}
+	network := connection.Error{ConnectionID: "localhost:27017[-1]", Wrapped: errors.New("connection reset")}
+
+	testCases := []struct {
+		name      string
+		err       error
+		dupKey    bool
+		timeout   bool
+		networkEr bool
+	}{
+		{"nil", nil, false, false, false},
+		{"write exception", dupKey, true, false, false},
+		{"bulk write exception", BulkWriteException{WriteErrors: []BulkWriteError{{WriteError: WriteError{Code: 11001}}}}, true, false, false},
+		{"write concern error", WriteException{WriteConcernError: &WriteConcernError{Code: 11000}}, true, false, false},
+		{"sharded duplicate key", CommandError{Code: 16460, Message: "error inserting: E11000 duplicate key"}, true, false, false},
+		{"other write error", WriteException{WriteErrors: WriteErrors{{Code: 121}}}, false, false, false},
+		{"timeout error", TimeoutError{Wrapped: context.DeadlineExceeded}, false, true, false},
+		{"max time expired", CommandError{Code: maxTimeMSExpiredCode}, false, true, false},
+		{"server selection timeout", selection, false, true, false},
+		{"network error", network, false, false, true},
+		{"network error label", CommandError{Labels: []string{command.NetworkError}}, false, false, true},
+		{"wrapped", fmt.Errorf("insert failed: %w", dupKey), true, false, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.dupKey, IsDuplicateKeyError(tc.err), "IsDuplicateKeyError")
+			require.Equal(t, tc.timeout, IsTimeout(tc.err), "IsTimeout")
+			require.Equal(t, tc.networkEr, IsNetworkError(tc.err), "IsNetworkError")
+		})
+	}
+
+	t.Run("errors.As", func(t *testing.T) {
+		var we WriteError
+		require.True(t, errors.As(dupKey, &we))
+		require.Equal(t, 11000, we.Code)
+
+		var sse topology.ServerSelectionError
+		require.True(t, errors.As(TimeoutError{Wrapped: selection}, &sse))
+		require.True(t, errors.Is(sse, topology.ErrServerSelectionTimeout))
+	})
+}
+
+func TestServerErrorSentinels(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		target error
+	}{
+		{"namespace not found", CommandError{Code: 26, Message: "ns not found"}, ErrNamespaceNotFound},
+		{"duplicate key", WriteException{WriteErrors: WriteErrors{{Code: 11000}}}, ErrDuplicateKey},
+		{"bulk duplicate key", BulkWriteException{WriteErrors: []BulkWriteError{{WriteError: WriteError{Code: 11000}}}}, ErrDuplicateKey},
+		{"cursor not found", replaceErrors(command.Error{Code: 43, Message: "cursor id 42 not found"}), ErrCursorNotFound},
+		{"write conflict", fmt.Errorf("transfer: %w", CommandError{Code: 112}), ErrWriteConflict},
+		{"max time expired", TimeoutError{Wrapped: CommandError{Code: maxTimeMSExpiredCode}}, ErrMaxTimeMSExpired},
+	}
+	targets := []error{ErrNamespaceNotFound, ErrDuplicateKey, ErrCursorNotFound, ErrWriteConflict, ErrMaxTimeMSExpired}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, target := range targets {
+				require.Equal(t, target == tc.target, errors.Is(tc.err, target), "errors.Is(%v)", target)
+			}
+		})
+	}
+}
+
+func TestCheckWriteConcern(t *testing.T) {
+	rs := description.Topology{
+		Kind: description.ReplicaSetWithPrimary,
+		Servers: []description.Server{
+			{Kind: description.RSPrimary},
+			{Kind: description.RSSecondary},
+			{Kind: description.RSArbiter},
+			{Kind: description.Unknown, LastError: errors.New("connection refused")},
+		},
+	}
+	starting := description.Topology{
+		Kind: description.ReplicaSetNoPrimary,
+		Servers: []description.Server{
+			{Kind: description.RSSecondary},
+			{Kind: description.Unknown},
+			{Kind: description.Unknown},
+		},
+	}
+	sharded := description.Topology{Kind: description.Sharded, Servers: []description.Server{{Kind: description.Mongos}}}
+
+	testCases := []struct {
+		name string
+		desc description.Topology
+		wc   *writeconcern.WriteConcern
+		err  error
+	}{
+		{"nil", rs, nil, nil},
+		{"w:2", rs, writeconcern.New(writeconcern.W(2)), nil},
+		{"w:3 with a member down", rs, writeconcern.New(writeconcern.W(3)), UnsatisfiableWriteConcernError{W: 3, DataBearingMembers: 2}},
+		{"unchecked members", starting, writeconcern.New(writeconcern.W(3)), nil},
+		{"majority", rs, writeconcern.New(writeconcern.WMajority()), nil},
+		{"sharded", sharded, writeconcern.New(writeconcern.W(3)), nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.err, checkWriteConcern(tc.desc, tc.wc))
+		})
+	}
+}
+
+func TestReplaceReadOnlyError(t *testing.T) {
+	err := replaceErrors(command.ReadOnlyError{Command: "insert"})
+	require.Equal(t, ReadOnlyError{Command: "insert"}, err)
+	require.False(t, IsNetworkError(err))
+}
+
+func TestReplaceOperationLimitError(t *testing.T) {
+	err := replaceErrors(command.OperationLimitError{Limit: 10, Wrapped: context.Canceled})
+	require.Equal(t, OperationLimitError{Limit: 10, Wrapped: context.Canceled}, err)
+	require.False(t, IsNetworkError(err))
+	require.Equal(t, context.Canceled, err.(OperationLimitError).Unwrap())
+}
+
+func TestWriteErrorLabels(t *testing.T) {
+	var res result.Insert
+	b, err := bson.Marshal(bson.D{
+		{"ok", 1},
+		{"n", 1},
+		{"writeConcernError", bson.D{{"code", 91}, {"errmsg", "shutdown in progress"}}},
+		{"errorLabels", bson.A{"RetryableWriteError"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, bson.Unmarshal(b, &res))
+
+	rr, err := processWriteError(res.WriteConcernError, res.WriteErrors, res.ErrorLabels, nil)
+	require.Equal(t, rrMany, rr)
+	we, ok := err.(WriteException)
+	require.True(t, ok)
+	require.True(t, we.HasErrorLabel("RetryableWriteError"))
+	require.False(t, we.HasErrorLabel("TransientTransactionError"))
+
+	err = replaceErrors(driverlegacy.BulkWriteException{
+		WriteConcernError: res.WriteConcernError,
+		Labels:            res.ErrorLabels,
+	})
+	bwe, ok := err.(BulkWriteException)
+	require.True(t, ok)
+	require.True(t, bwe.HasErrorLabel("RetryableWriteError"))
+
+	rr, err = processWriteError(nil, nil, nil, command.ErrUnacknowledgedWrite)
+	require.Equal(t, rrAll, rr)
+	require.Equal(t, ErrUnacknowledgedWrite, err)
+	require.Equal(t, ErrUnacknowledgedWrite, replaceErrors(command.ErrUnacknowledgedWrite))
+}
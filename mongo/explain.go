@@ -0,0 +1,218 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy"
+	"go.mongodb.org/mongo-driver/x/network/command"
+)
+
+// ExplainResult is the output of the explain command for a find or an aggregate. Raw holds the whole
+// output, including the parts that have no field of their own, such as the plans of each shard.
+type ExplainResult struct {
+	QueryPlanner   ExplainQueryPlanner    `bson:"queryPlanner"`
+	ExecutionStats *ExplainExecutionStats `bson:"executionStats"` // Not reported for the QueryPlanner verbosity.
+	Raw            bson.Raw               `bson:"-"`
+}
+
+// UnmarshalBSON implements the bson.Unmarshaler interface. Servers that explain an aggregate whose
+// first stage reads from the collection report the query plan of that stage in the $cursor field of
+// the first element of stages, which is decoded in the same way as the output for a find.
+func (er *ExplainResult) UnmarshalBSON(b []byte) error {
+	type result ExplainResult
+	var r result
+
+	doc := bson.Raw(b)
+	if _, err := doc.LookupErr("queryPlanner"); err != nil {
+		if cursor, ok := doc.Lookup("stages", "0", "$cursor").DocumentOK(); ok {
+			doc = cursor
+		}
+	}
+	if err := bson.Unmarshal(doc, &r); err != nil {
+		return err
+	}
+	*er = ExplainResult(r)
+	er.Raw = append(bson.Raw(nil), b...)
+	return nil
+}
+
+// ExplainQueryPlanner is the plan the query optimizer chose for an explained command.
+type ExplainQueryPlanner struct {
+	Namespace      string         `bson:"namespace"`
+	IndexFilterSet bool           `bson:"indexFilterSet"`
+	ParsedQuery    bson.Raw       `bson:"parsedQuery"`
+	WinningPlan    *ExplainPlan   `bson:"winningPlan"`
+	RejectedPlans  []*ExplainPlan `bson:"rejectedPlans"`
+}
+
+// ExplainPlan is a stage of a query plan, such as COLLSCAN or IXSCAN, and the stages it reads from.
+type ExplainPlan struct {
+	Stage       string         `bson:"stage"`
+	IndexName   string         `bson:"indexName"` // Only reported by IXSCAN stages.
+	KeyPattern  bson.Raw       `bson:"keyPattern"`
+	InputStage  *ExplainPlan   `bson:"inputStage"`
+	InputStages []*ExplainPlan `bson:"inputStages"`
+}
+
+// UnmarshalBSON implements the bson.Unmarshaler interface. Servers that run a plan with the slot
+// based execution engine report its stages in the queryPlan field of the plan, which is decoded in
+// the same way as the plans of other servers.
+func (ep *ExplainPlan) UnmarshalBSON(b []byte) error {
+	type plan ExplainPlan
+	var p plan
+
+	doc := bson.Raw(b)
+	if queryPlan, ok := doc.Lookup("queryPlan").DocumentOK(); ok {
+		doc = queryPlan
+	}
+	if err := bson.Unmarshal(doc, &p); err != nil {
+		return err
+	}
+	*ep = ExplainPlan(p)
+	ep.KeyPattern = append(bson.Raw(nil), ep.KeyPattern...)
+	return nil
+}
+
+// Stages returns the names of the stages of the plan, starting with the stage itself and listing
+// the stages each stage reads from after it, such as [FETCH IXSCAN].
+func (ep *ExplainPlan) Stages() []string {
+	if ep == nil {
+		return nil
+	}
+
+	stages := []string{ep.Stage}
+	stages = append(stages, ep.InputStage.Stages()...)
+	for _, input := range ep.InputStages {
+		stages = append(stages, input.Stages()...)
+	}
+	return stages
+}
+
+// ExplainExecutionStats are the statistics of running the winning plan of an explained command.
+type ExplainExecutionStats struct {
+	ExecutionSuccess    bool         `bson:"executionSuccess"`
+	NReturned           int64        `bson:"nReturned"`
+	ExecutionTimeMillis int64        `bson:"executionTimeMillis"`
+	TotalKeysExamined   int64        `bson:"totalKeysExamined"`
+	TotalDocsExamined   int64        `bson:"totalDocsExamined"`
+	ExecutionStages     *ExplainPlan `bson:"executionStages"`
+}
+
+// ExplainFind explains the find command Find runs for filter and opts at the given verbosity, without
+// returning a cursor. The ExecutionStats and AllPlansExecution verbosities run the query.
+func (coll *Collection) ExplainFind(ctx context.Context, filter interface{}, verbosity options.ExplainVerbosity,
+	opts ...*options.FindOptions) (*ExplainResult, error) {
+
+	if coll.collation != nil {
+		opts = append([]*options.FindOptions{options.Find().SetCollation(coll.collation)}, opts...)
+	}
+
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+
+	f, err := transformDocument(coll.registry, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sess := sessionFromContext(ctx)
+
+	err = coll.client.validSession(sess)
+	if err != nil {
+		return nil, err
+	}
+
+	oldns := coll.namespace()
+	cmd := command.Find{
+		NS:       command.Namespace{DB: oldns.DB, Collection: oldns.Collection},
+		Filter:   f,
+		ReadPref: coll.readPreference,
+		Session:  sess,
+		Clock:    coll.client.clock,
+	}
+
+	res, err := driverlegacy.ExplainFind(
+		ctx, cmd, string(verbosity),
+		coll.client.topology,
+		coll.readSelector,
+		coll.client.id,
+		coll.client.topology.SessionPool,
+		coll.registry,
+		opts...,
+	)
+	err = timeoutError(ctx, err)
+	if err != nil {
+		return nil, replaceErrors(err)
+	}
+
+	return decodeExplainResult(res)
+}
+
+// ExplainAggregate explains the aggregate command Aggregate runs for pipeline and opts at the given
+// verbosity, without returning a cursor. The pipeline includes the stages of the pipeline policies
+// of the client.
+func (coll *Collection) ExplainAggregate(ctx context.Context, pipeline interface{}, verbosity options.ExplainVerbosity,
+	opts ...*options.AggregateOptions) (*ExplainResult, error) {
+
+	if coll.collation != nil {
+		opts = append([]*options.AggregateOptions{options.Aggregate().SetCollation(coll.collation)}, opts...)
+	}
+
+	ctx, cancel := operationContext(ctx, coll.client.timeout)
+	defer cancel()
+
+	pipelineArr, err := transformAggregatePipeline(coll.registry, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	pipelineArr = applyPipelinePolicies(coll.client.stagePolicies, coll.db.name, coll.name, pipelineArr)
+
+	sess := sessionFromContext(ctx)
+
+	err = coll.client.validSession(sess)
+	if err != nil {
+		return nil, err
+	}
+
+	oldns := coll.namespace()
+	cmd := command.Aggregate{
+		NS:       command.Namespace{DB: oldns.DB, Collection: oldns.Collection},
+		Pipeline: pipelineArr,
+		ReadPref: coll.readPreference,
+		Session:  sess,
+		Clock:    coll.client.clock,
+	}
+
+	res, err := driverlegacy.ExplainAggregate(
+		ctx, cmd, string(verbosity),
+		coll.client.topology,
+		coll.readSelector,
+		coll.writeSelector,
+		coll.client.id,
+		coll.client.topology.SessionPool,
+		coll.registry,
+		opts...,
+	)
+	err = timeoutError(ctx, err)
+	if err != nil {
+		return nil, replaceErrors(err)
+	}
+
+	return decodeExplainResult(res)
+}
+
+func decodeExplainResult(res bson.Raw) (*ExplainResult, error) {
+	er := new(ExplainResult)
+	if err := bson.Unmarshal(res, er); err != nil {
+		return nil, err
+	}
+	return er, nil
+}
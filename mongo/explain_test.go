@@ -0,0 +1,98 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestExplainResult(t *testing.T) {
+	ixscan := bson.D{
+		{"stage", "FETCH"},
+		{"inputStage", bson.D{
+			{"stage", "IXSCAN"},
+			{"indexName", "x_1"},
+			{"keyPattern", bson.D{{"x", 1}}},
+		}},
+	}
+	queryPlanner := bson.D{
+		{"plannerVersion", 1},
+		{"namespace", "db.coll"},
+		{"indexFilterSet", false},
+		{"parsedQuery", bson.D{{"x", bson.D{{"$eq", 1}}}}},
+		{"winningPlan", ixscan},
+		{"rejectedPlans", bson.A{bson.D{{"stage", "COLLSCAN"}}}},
+	}
+
+	t.Run("find", func(t *testing.T) {
+		doc, err := bson.Marshal(bson.D{
+			{"queryPlanner", queryPlanner},
+			{"executionStats", bson.D{
+				{"executionSuccess", true},
+				{"nReturned", int32(1)},
+				{"executionTimeMillis", int32(0)},
+				{"totalKeysExamined", int32(1)},
+				{"totalDocsExamined", int32(1)},
+				{"executionStages", ixscan},
+			}},
+			{"ok", 1.0},
+		})
+		require.NoError(t, err)
+
+		res, err := decodeExplainResult(doc)
+		require.NoError(t, err)
+		require.Equal(t, "db.coll", res.QueryPlanner.Namespace)
+		require.Equal(t, []string{"FETCH", "IXSCAN"}, res.QueryPlanner.WinningPlan.Stages())
+		require.Equal(t, "x_1", res.QueryPlanner.WinningPlan.InputStage.IndexName)
+		keyPattern, err := bson.Marshal(bson.D{{"x", 1}})
+		require.NoError(t, err)
+		require.Equal(t, bson.Raw(keyPattern), res.QueryPlanner.WinningPlan.InputStage.KeyPattern)
+		require.Len(t, res.QueryPlanner.RejectedPlans, 1)
+		require.Equal(t, "COLLSCAN", res.QueryPlanner.RejectedPlans[0].Stage)
+		require.NotNil(t, res.ExecutionStats)
+		require.Equal(t, int64(1), res.ExecutionStats.NReturned)
+		require.Equal(t, int64(1), res.ExecutionStats.TotalKeysExamined)
+		require.Equal(t, []string{"FETCH", "IXSCAN"}, res.ExecutionStats.ExecutionStages.Stages())
+		require.Equal(t, bson.Raw(doc), res.Raw)
+	})
+	t.Run("aggregate stages", func(t *testing.T) {
+		doc, err := bson.Marshal(bson.D{
+			{"stages", bson.A{
+				bson.D{{"$cursor", bson.D{{"queryPlanner", queryPlanner}}}},
+				bson.D{{"$group", bson.D{{"_id", "$x"}}}},
+			}},
+			{"ok", 1.0},
+		})
+		require.NoError(t, err)
+
+		res, err := decodeExplainResult(doc)
+		require.NoError(t, err)
+		require.Equal(t, "db.coll", res.QueryPlanner.Namespace)
+		require.Equal(t, []string{"FETCH", "IXSCAN"}, res.QueryPlanner.WinningPlan.Stages())
+		require.Nil(t, res.ExecutionStats)
+		require.Equal(t, bson.Raw(doc), res.Raw)
+	})
+	t.Run("query plan", func(t *testing.T) {
+		doc, err := bson.Marshal(bson.D{
+			{"queryPlanner", bson.D{
+				{"namespace", "db.coll"},
+				{"winningPlan", bson.D{
+					{"queryPlan", ixscan},
+					{"slotBasedPlan", bson.D{{"stages", "..."}}},
+				}},
+			}},
+		})
+		require.NoError(t, err)
+
+		res, err := decodeExplainResult(doc)
+		require.NoError(t, err)
+		require.Equal(t, []string{"FETCH", "IXSCAN"}, res.QueryPlanner.WinningPlan.Stages())
+	})
+}
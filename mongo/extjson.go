@@ -0,0 +1,181 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultImportBatchSize is the number of documents inserted at once by Import when no batch size
+// is given.
+const defaultImportBatchSize = 1000
+
+// maxExtJSONLineSize is the maximum length of a line read by Import. Extended JSON is larger than
+// the BSON it encodes, so this is well above the maximum size of a document.
+const maxExtJSONLineSize = 64 * 1024 * 1024
+
+// Export writes the documents of the collection that match filter to w as extended JSON, one
+// document per line, and returns the number of documents written. Relaxed extended JSON is written
+// unless the Canonical option is set. Use Import to load the documents back.
+//
+// Export is meant for small datasets, such as test fixtures; it is not a substitute for mongodump.
+func (coll *Collection) Export(ctx context.Context, w io.Writer, filter interface{},
+	opts ...*options.ExportOptions) (int64, error) {
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	eo := options.MergeExportOptions(opts...)
+	findOpts := options.Find()
+	if eo.BatchSize != nil {
+		findOpts.SetBatchSize(*eo.BatchSize)
+	}
+	if eo.Sort != nil {
+		findOpts.SetSort(eo.Sort)
+	}
+	canonical := eo.Canonical != nil && *eo.Canonical
+
+	cursor, err := coll.Find(ctx, filter, findOpts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	bw := bufio.NewWriter(w)
+	var n int64
+	var line []byte
+	for cursor.Next(ctx) {
+		line, err = bson.MarshalExtJSONAppendWithRegistry(coll.registry, line[:0], cursor.Current, canonical, false)
+		if err != nil {
+			return n, err
+		}
+		line = append(line, '\n')
+		if _, err = bw.Write(line); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err = cursor.Err(); err != nil {
+		return n, replaceErrors(err)
+	}
+	return n, bw.Flush()
+}
+
+// Import inserts the documents read from r, which must hold one extended JSON document per line as
+// written by Export, into the collection and returns the number of documents inserted. Blank lines
+// are skipped. Documents are inserted in batches of the BatchSize option.
+//
+// If the Ordered option is false, the documents that cannot be inserted are skipped and their
+// errors are returned as a BulkWriteException once all the others were inserted, with the index of
+// each error being the index of the document among the documents read. Otherwise Import stops at
+// the first document that cannot be inserted. Import always stops at the first line that is not
+// valid extended JSON and at errors other than write errors.
+func (coll *Collection) Import(ctx context.Context, r io.Reader, opts ...*options.ImportOptions) (int64, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	imo := options.MergeImportOptions(opts...)
+	batchSize := defaultImportBatchSize
+	if imo.BatchSize != nil && *imo.BatchSize > 0 {
+		batchSize = int(*imo.BatchSize)
+	}
+	ordered := imo.Ordered == nil || *imo.Ordered
+
+	imp := &importer{coll: coll, ordered: ordered}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxExtJSONLineSize)
+	batch := make([]interface{}, 0, batchSize)
+	var lineNum int
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var doc bson.Raw
+		if err := bson.UnmarshalExtJSONWithRegistry(coll.registry, line, false, &doc); err != nil {
+			return imp.inserted, fmt.Errorf("invalid extended JSON on line %d: %v", lineNum, err)
+		}
+		batch = append(batch, doc)
+		if len(batch) == batchSize {
+			if err := imp.insert(ctx, batch); err != nil {
+				return imp.inserted, err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return imp.inserted, err
+	}
+	if len(batch) > 0 {
+		if err := imp.insert(ctx, batch); err != nil {
+			return imp.inserted, err
+		}
+	}
+	return imp.inserted, imp.err()
+}
+
+// importer inserts the batches of documents of an import and keeps track of the documents inserted
+// and the write errors of an unordered import.
+type importer struct {
+	coll     *Collection
+	ordered  bool
+	inserted int64
+	offset   int // The index of the first document of the next batch among all the documents.
+	errs     BulkWriteException
+}
+
+// insert inserts batch. It returns an error if the import must stop.
+func (imp *importer) insert(ctx context.Context, batch []interface{}) error {
+	_, err := imp.coll.InsertMany(ctx, batch, options.InsertMany().SetOrdered(imp.ordered))
+	bwe, ok := err.(BulkWriteException)
+	switch {
+	case err == nil:
+		imp.inserted += int64(len(batch))
+	case !ok:
+		return err
+	case imp.ordered:
+		imp.inserted += int64(len(batch))
+		if len(bwe.WriteErrors) > 0 {
+			imp.inserted -= int64(len(batch) - bwe.WriteErrors[0].Index)
+		}
+		imp.offsetErrors(bwe.WriteErrors)
+		return bwe
+	default:
+		imp.inserted += int64(len(batch) - len(bwe.WriteErrors))
+		imp.offsetErrors(bwe.WriteErrors)
+		imp.errs.WriteErrors = append(imp.errs.WriteErrors, bwe.WriteErrors...)
+		if bwe.WriteConcernError != nil {
+			imp.errs.WriteConcernError = bwe.WriteConcernError
+		}
+	}
+	imp.offset += len(batch)
+	return nil
+}
+
+// offsetErrors changes the indexes of errs from indexes in the current batch to indexes among all
+// the documents.
+func (imp *importer) offsetErrors(errs []BulkWriteError) {
+	for i := range errs {
+		errs[i].Index += imp.offset
+	}
+}
+
+// err returns the write errors of an unordered import, if any.
+func (imp *importer) err() error {
+	if len(imp.errs.WriteErrors) == 0 && imp.errs.WriteConcernError == nil {
+		return nil
+	}
+	return imp.errs
+}
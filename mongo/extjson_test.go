@@ -0,0 +1,80 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestCollection_ExportImport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	coll := createTestCollection(t, nil, nil)
+	initCollection(t, coll)
+
+	var buf bytes.Buffer
+	n, err := coll.Export(context.Background(), &buf, bson.D{}, options.Export().SetSort(bson.D{{"x", 1}}).SetBatchSize(2))
+	require.NoError(t, err)
+	require.Equal(t, int64(5), n)
+	require.Equal(t, 5, strings.Count(buf.String(), "\n"))
+	exported := buf.String()
+
+	target := createTestCollection(t, nil, nil)
+	n, err = target.Import(context.Background(), strings.NewReader(exported), options.Import().SetBatchSize(2))
+	require.NoError(t, err)
+	require.Equal(t, int64(5), n)
+
+	buf.Reset()
+	_, err = target.Export(context.Background(), &buf, bson.D{}, options.Export().SetSort(bson.D{{"x", 1}}))
+	require.NoError(t, err)
+	require.Equal(t, exported, buf.String())
+
+	t.Run("unordered", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(exported), "\n")
+		input := lines[0] + "\n" + `{"x": 6}` + "\n" + lines[1] + "\n" + `{"x": 7}` + "\n"
+		n, err := target.Import(context.Background(), strings.NewReader(input),
+			options.Import().SetBatchSize(2).SetOrdered(false))
+		require.Equal(t, int64(2), n)
+		bwe, ok := err.(BulkWriteException)
+		require.True(t, ok, "expected a BulkWriteException, got %v", err)
+		require.Len(t, bwe.WriteErrors, 2)
+		require.Equal(t, 0, bwe.WriteErrors[0].Index)
+		require.Equal(t, 2, bwe.WriteErrors[1].Index)
+	})
+	t.Run("ordered", func(t *testing.T) {
+		input := `{"x": 8}` + "\n" + strings.SplitN(exported, "\n", 2)[0] + "\n" + `{"x": 9}` + "\n"
+		n, err := target.Import(context.Background(), strings.NewReader(input))
+		require.Equal(t, int64(1), n)
+		bwe, ok := err.(BulkWriteException)
+		require.True(t, ok, "expected a BulkWriteException, got %v", err)
+		require.Len(t, bwe.WriteErrors, 1)
+		require.Equal(t, 1, bwe.WriteErrors[0].Index)
+	})
+}
+
+func TestCollection_ImportInvalid(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+	coll := client.Database("test").Collection("import")
+
+	n, err := coll.Import(context.Background(), strings.NewReader("\n  \n"))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), n)
+
+	_, err = coll.Import(context.Background(), strings.NewReader("\n{\"x\": 1}\n{\"x\": \n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 3")
+}
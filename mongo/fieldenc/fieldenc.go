@@ -0,0 +1,383 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package fieldenc maps encrypt struct tags to explicit encryption calls so that individual fields
+// are encrypted when a struct is marshaled and decrypted when a document is unmarshaled, without
+// calling Encrypt and Decrypt for every field by hand.
+//
+// A field is marked for encryption by adding an encrypt tag next to its bson tag. The first tag
+// value is the algorithm, either "deterministic" or "random", followed by the data key to use,
+// given either by alternate name or by base64 encoded UUID:
+//
+//	type Patient struct {
+//		Name  string `bson:"name"`
+//		SSN   string `bson:"ssn" encrypt:"deterministic,keyAltName=pii"`
+//		Notes string `bson:"notes" encrypt:"random,keyId=AAAAAAAAAAAAAAAAAAAAAA=="`
+//	}
+//
+//	c := fieldenc.NewCodec(encrypter, nil)
+//	doc, err := c.Marshal(ctx, patient)
+//	_, err = coll.InsertOne(ctx, doc)
+//
+// Tagged fields of embedded structs, including inline structs, recursive structs, and structs
+// stored in slices, arrays, and maps, are encrypted as well. The encryption itself is performed by
+// an Encrypter, which is usually backed by a key vault and a KMS provider.
+package fieldenc // import "go.mongodb.org/mongo-driver/mongo/fieldenc"
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// These constants are the names of the supported encryption algorithms.
+const (
+	AlgorithmDeterministic = "AEAD_AES_256_CBC_HMAC_SHA_512-Deterministic"
+	AlgorithmRandom        = "AEAD_AES_256_CBC_HMAC_SHA_512-Random"
+)
+
+// EncryptedSubtype is the BSON binary subtype of an encrypted value.
+const EncryptedSubtype byte = 0x06
+
+// EncryptOptions represents the options for encrypting a single value.
+type EncryptOptions struct {
+	Algorithm  string            // The encryption algorithm, either AlgorithmDeterministic or AlgorithmRandom.
+	KeyAltName *string           // The alternate name of the data key. Either KeyAltName or KeyID is set.
+	KeyID      *primitive.Binary // The UUID of the data key. Either KeyAltName or KeyID is set.
+}
+
+// Encrypter performs explicit encryption and decryption of single BSON values.
+type Encrypter interface {
+	// Encrypt encrypts val and returns the ciphertext as a binary value with EncryptedSubtype.
+	Encrypt(ctx context.Context, val bson.RawValue, opts *EncryptOptions) (primitive.Binary, error)
+	// Decrypt decrypts a binary value with EncryptedSubtype and returns the original value.
+	Decrypt(ctx context.Context, val primitive.Binary) (bson.RawValue, error)
+}
+
+// Codec marshals and unmarshals structs, encrypting and decrypting the fields tagged with encrypt.
+type Codec struct {
+	encrypter Encrypter
+	registry  *bsoncodec.Registry
+
+	l     sync.RWMutex
+	cache map[reflect.Type]*fieldSpec
+}
+
+// NewCodec creates a Codec that uses encrypter to encrypt and decrypt values and registry to encode
+// and decode documents. If registry is nil, bson.DefaultRegistry is used.
+func NewCodec(encrypter Encrypter, registry *bsoncodec.Registry) *Codec {
+	if registry == nil {
+		registry = bson.DefaultRegistry
+	}
+	return &Codec{encrypter: encrypter, registry: registry, cache: make(map[reflect.Type]*fieldSpec)}
+}
+
+// Marshal returns the BSON encoding of val with every tagged field replaced by its encrypted value.
+// val must be a struct or a pointer to a struct.
+func (c *Codec) Marshal(ctx context.Context, val interface{}) (bson.Raw, error) {
+	spec, err := c.specFor(reflect.TypeOf(val))
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := bson.MarshalWithRegistry(c.registry, val)
+	if err != nil {
+		return nil, err
+	}
+	if spec == nil {
+		return doc, nil
+	}
+
+	return c.transform(ctx, doc, spec, c.encryptValue)
+}
+
+// Unmarshal decrypts every tagged field of the BSON document data and decodes the result into val,
+// which must be a pointer to a struct.
+func (c *Codec) Unmarshal(ctx context.Context, data []byte, val interface{}) error {
+	rval := reflect.ValueOf(val)
+	if rval.Kind() != reflect.Ptr || rval.IsNil() {
+		return fmt.Errorf("argument to Unmarshal must be a non-nil pointer to a struct, but got %T", val)
+	}
+
+	spec, err := c.specFor(rval.Type())
+	if err != nil {
+		return err
+	}
+
+	doc := bson.Raw(data)
+	if spec != nil {
+		doc, err = c.transform(ctx, doc, spec, c.decryptValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return bson.UnmarshalWithRegistry(c.registry, doc, val)
+}
+
+func (c *Codec) encryptValue(ctx context.Context, val bson.RawValue, opts *EncryptOptions) (bson.RawValue, error) {
+	if val.Type == bsontype.Null || val.Type == bsontype.Undefined {
+		// there is nothing to protect in an empty value, and it can't be encrypted deterministically.
+		return val, nil
+	}
+
+	bin, err := c.encrypter.Encrypt(ctx, val, opts)
+	if err != nil {
+		return bson.RawValue{}, err
+	}
+	return bson.RawValue{Type: bsontype.Binary, Value: bsoncore.AppendBinary(nil, bin.Subtype, bin.Data)}, nil
+}
+
+func (c *Codec) decryptValue(ctx context.Context, val bson.RawValue, _ *EncryptOptions) (bson.RawValue, error) {
+	subtype, data, ok := val.BinaryOK()
+	if !ok || subtype != EncryptedSubtype {
+		// values that were never encrypted, such as null, are decoded as they are.
+		return val, nil
+	}
+
+	return c.encrypter.Decrypt(ctx, primitive.Binary{Subtype: subtype, Data: data})
+}
+
+type valueTransformer func(context.Context, bson.RawValue, *EncryptOptions) (bson.RawValue, error)
+
+// transform rebuilds the document or array doc, applying fn to every value with encryption options
+// in spec and recursing into the embedded documents and arrays that contain such values.
+func (c *Codec) transform(ctx context.Context, doc bson.Raw, spec *fieldSpec, fn valueTransformer) (bson.Raw, error) {
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	idx, dst := bsoncore.AppendDocumentStart(nil)
+	for _, elem := range elems {
+		key := elem.Key()
+		val := elem.Value()
+
+		if sub := spec.lookup(key); sub != nil {
+			val, err = c.transformValue(ctx, val, sub, fn)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %v", key, err)
+			}
+		}
+
+		dst = bsoncore.AppendHeader(dst, val.Type, key)
+		dst = append(dst, val.Value...)
+	}
+
+	dst, err = bsoncore.AppendDocumentEnd(dst, idx)
+	if err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+func (c *Codec) transformValue(ctx context.Context, val bson.RawValue, spec *fieldSpec, fn valueTransformer) (bson.RawValue, error) {
+	if spec.opts != nil {
+		return fn(ctx, val, spec.opts)
+	}
+
+	switch val.Type {
+	case bsontype.EmbeddedDocument, bsontype.Array:
+		sub, err := c.transform(ctx, val.Value, spec, fn)
+		if err != nil {
+			return bson.RawValue{}, err
+		}
+		return bson.RawValue{Type: val.Type, Value: sub}, nil
+	}
+	return val, nil
+}
+
+// fieldSpec describes where the encrypted values of a type are. A spec either has encryption
+// options for the value itself, describes the fields of a struct, or describes every element of a
+// slice or array or every value of a map.
+type fieldSpec struct {
+	opts   *EncryptOptions
+	fields map[string]*fieldSpec
+	values *fieldSpec
+}
+
+// lookup returns the spec of the element with the given key, or nil if it has no encrypted values.
+func (fs *fieldSpec) lookup(key string) *fieldSpec {
+	if fs.values != nil {
+		return fs.values
+	}
+	return fs.fields[key]
+}
+
+// specFor returns the fieldSpec of t, or nil if t has no encrypted fields.
+func (c *Codec) specFor(t reflect.Type) (*fieldSpec, error) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.New("fieldenc: a struct or a pointer to a struct is required")
+	}
+
+	c.l.RLock()
+	spec, ok := c.cache[t]
+	c.l.RUnlock()
+	if ok {
+		return spec, nil
+	}
+
+	spec, err := buildSpec(t, make(map[reflect.Type]*fieldSpec))
+	if err != nil {
+		return nil, err
+	}
+
+	c.l.Lock()
+	c.cache[t] = spec
+	c.l.Unlock()
+	return spec, nil
+}
+
+// buildSpec returns the spec of the struct type t, or nil if it has no encrypted fields. building
+// holds the specs of the struct types being built so that a recursive type refers to its own spec.
+func buildSpec(t reflect.Type, building map[reflect.Type]*fieldSpec) (*fieldSpec, error) {
+	if spec, ok := building[t]; ok {
+		return spec, nil
+	}
+
+	spec := &fieldSpec{fields: make(map[string]*fieldSpec)}
+	building[t] = spec
+	defer delete(building, t)
+
+	if err := addFields(spec, t, building); err != nil {
+		return nil, err
+	}
+	if len(spec.fields) == 0 {
+		return nil, nil
+	}
+	return spec, nil
+}
+
+// typeSpec returns the spec of a value of type t, or nil if it has no encrypted values.
+func typeSpec(t reflect.Type, building map[reflect.Type]*fieldSpec) (*fieldSpec, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return buildSpec(t, building)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		values, err := typeSpec(t.Elem(), building)
+		if err != nil || values == nil {
+			return nil, err
+		}
+		return &fieldSpec{values: values}, nil
+	}
+	return nil, nil
+}
+
+func addFields(spec *fieldSpec, t reflect.Type, building map[reflect.Type]*fieldSpec) error {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		tags, err := bsoncodec.DefaultStructTagParser.ParseStructTags(sf)
+		if err != nil {
+			return err
+		}
+		if tags.Skip {
+			continue
+		}
+
+		if tag, ok := sf.Tag.Lookup("encrypt"); ok {
+			if tags.Inline {
+				return fmt.Errorf("fieldenc: inline field %s of %s cannot be encrypted", sf.Name, t)
+			}
+			opts, err := parseEncryptTag(tag)
+			if err != nil {
+				return fmt.Errorf("fieldenc: field %s of %s: %v", sf.Name, t, err)
+			}
+			spec.fields[tags.Name] = &fieldSpec{opts: opts}
+			continue
+		}
+
+		if tags.Inline {
+			ft := sf.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if _, recursive := building[ft]; recursive {
+				// the fields of a recursively inlined struct have already been added.
+				continue
+			}
+			if ft.Kind() == reflect.Struct {
+				if err = addFields(spec, ft, building); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		sub, err := typeSpec(sf.Type, building)
+		if err != nil {
+			return err
+		}
+		if sub != nil {
+			spec.fields[tags.Name] = sub
+		}
+	}
+	return nil
+}
+
+// parseEncryptTag parses an encrypt struct tag of the form
+// "<deterministic|random>,keyAltName=<name>" or "<deterministic|random>,keyId=<base64 UUID>".
+func parseEncryptTag(tag string) (*EncryptOptions, error) {
+	parts := strings.Split(tag, ",")
+
+	opts := &EncryptOptions{}
+	switch strings.TrimSpace(parts[0]) {
+	case "deterministic":
+		opts.Algorithm = AlgorithmDeterministic
+	case "random":
+		opts.Algorithm = AlgorithmRandom
+	default:
+		return nil, fmt.Errorf("unknown encryption algorithm %q, must be deterministic or random", parts[0])
+	}
+
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, fmt.Errorf("invalid encrypt tag option %q", part)
+		}
+		switch kv[0] {
+		case "keyAltName":
+			name := kv[1]
+			opts.KeyAltName = &name
+		case "keyId":
+			id, err := base64.StdEncoding.DecodeString(kv[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid keyId %q: %v", kv[1], err)
+			}
+			if len(id) != 16 {
+				return nil, fmt.Errorf("invalid keyId %q: a UUID must be 16 bytes", kv[1])
+			}
+			opts.KeyID = &primitive.Binary{Subtype: 0x04, Data: id}
+		default:
+			return nil, fmt.Errorf("unknown encrypt tag option %q", kv[0])
+		}
+	}
+
+	if (opts.KeyAltName == nil) == (opts.KeyID == nil) {
+		return nil, errors.New("exactly one of keyAltName or keyId must be specified")
+	}
+	return opts, nil
+}
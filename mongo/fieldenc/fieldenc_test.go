@@ -0,0 +1,175 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package fieldenc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testEncrypter "encrypts" a value by storing its type and bytes in the ciphertext.
+type testEncrypter struct {
+	opts []*EncryptOptions
+}
+
+func (te *testEncrypter) Encrypt(_ context.Context, val bson.RawValue, opts *EncryptOptions) (primitive.Binary, error) {
+	te.opts = append(te.opts, opts)
+	return primitive.Binary{Subtype: EncryptedSubtype, Data: append([]byte{byte(val.Type)}, val.Value...)}, nil
+}
+
+func (te *testEncrypter) Decrypt(_ context.Context, val primitive.Binary) (bson.RawValue, error) {
+	return bson.RawValue{Type: bsontype.Type(val.Data[0]), Value: val.Data[1:]}, nil
+}
+
+type contact struct {
+	Phone string `bson:"phone" encrypt:"random,keyId=AAAAAAAAAAAAAAAAAAAAAA=="`
+	City  string `bson:"city"`
+}
+
+type Audit struct {
+	Author string `bson:"author" encrypt:"deterministic,keyAltName=audit"`
+}
+
+type patient struct {
+	Name    string   `bson:"name"`
+	SSN     string   `bson:"ssn" encrypt:"deterministic,keyAltName=pii"`
+	Contact *contact `bson:"contact"`
+	Note    *string  `bson:"note" encrypt:"random,keyAltName=pii"`
+	Audit   `bson:",inline"`
+}
+
+func TestCodec(t *testing.T) {
+	enc := &testEncrypter{}
+	c := NewCodec(enc, nil)
+	ctx := context.Background()
+
+	in := patient{
+		Name:    "Jane",
+		SSN:     "123-45-6789",
+		Contact: &contact{Phone: "555-0100", City: "Springfield"},
+		Audit:   Audit{Author: "admin"},
+	}
+	doc, err := c.Marshal(ctx, &in)
+	require.NoError(t, err)
+
+	require.Equal(t, "Jane", doc.Lookup("name").StringValue())
+	require.Equal(t, "Springfield", doc.Lookup("contact", "city").StringValue())
+	require.Equal(t, bsontype.Null, doc.Lookup("note").Type)
+	for _, path := range [][]string{{"ssn"}, {"contact", "phone"}, {"author"}} {
+		subtype, _, ok := doc.Lookup(path...).BinaryOK()
+		require.True(t, ok, "expected %v to be encrypted", path)
+		require.Equal(t, EncryptedSubtype, subtype)
+	}
+
+	require.Len(t, enc.opts, 3)
+	require.Equal(t, AlgorithmDeterministic, enc.opts[0].Algorithm)
+	require.Equal(t, "pii", *enc.opts[0].KeyAltName)
+	require.Equal(t, AlgorithmRandom, enc.opts[1].Algorithm)
+	require.Equal(t, make([]byte, 16), enc.opts[1].KeyID.Data)
+
+	var out patient
+	require.NoError(t, c.Unmarshal(ctx, doc, &out))
+	require.Equal(t, in, out)
+}
+
+type node struct {
+	SSN   string `bson:"ssn" encrypt:"deterministic,keyAltName=pii"`
+	Child *node  `bson:"child"`
+}
+
+type item struct {
+	SSN string `bson:"ssn" encrypt:"deterministic,keyAltName=pii"`
+}
+
+type holder struct {
+	Items  []item           `bson:"items"`
+	Nested [][]item         `bson:"nested"`
+	ByName map[string]*item `bson:"byName"`
+}
+
+func requireEncrypted(t *testing.T, val bson.RawValue) {
+	t.Helper()
+	subtype, _, ok := val.BinaryOK()
+	require.True(t, ok, "expected an encrypted value but got %s", val)
+	require.Equal(t, EncryptedSubtype, subtype)
+}
+
+func TestCodecRecursiveType(t *testing.T) {
+	c := NewCodec(&testEncrypter{}, nil)
+	ctx := context.Background()
+
+	in := node{SSN: "1", Child: &node{SSN: "2", Child: &node{SSN: "3"}}}
+	doc, err := c.Marshal(ctx, in)
+	require.NoError(t, err)
+	requireEncrypted(t, doc.Lookup("ssn"))
+	requireEncrypted(t, doc.Lookup("child", "ssn"))
+	requireEncrypted(t, doc.Lookup("child", "child", "ssn"))
+
+	var out node
+	require.NoError(t, c.Unmarshal(ctx, doc, &out))
+	require.Equal(t, in, out)
+}
+
+func TestCodecCollections(t *testing.T) {
+	c := NewCodec(&testEncrypter{}, nil)
+	ctx := context.Background()
+
+	in := holder{
+		Items:  []item{{"1"}, {"2"}},
+		Nested: [][]item{{{"3"}}},
+		ByName: map[string]*item{"a": {"4"}},
+	}
+	doc, err := c.Marshal(ctx, in)
+	require.NoError(t, err)
+	requireEncrypted(t, doc.Lookup("items", "0", "ssn"))
+	requireEncrypted(t, doc.Lookup("items", "1", "ssn"))
+	requireEncrypted(t, doc.Lookup("nested", "0", "0", "ssn"))
+	requireEncrypted(t, doc.Lookup("byName", "a", "ssn"))
+
+	var out holder
+	require.NoError(t, c.Unmarshal(ctx, doc, &out))
+	require.Equal(t, in, out)
+}
+
+func TestCodecNoEncryptedFields(t *testing.T) {
+	c := NewCodec(&testEncrypter{}, nil)
+	doc, err := c.Marshal(context.Background(), struct{ A int32 }{1})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), doc.Lookup("a").Int32())
+}
+
+func TestParseEncryptTag(t *testing.T) {
+	testCases := []struct {
+		name string
+		tag  string
+		err  bool
+	}{
+		{"keyAltName", "deterministic,keyAltName=pii", false},
+		{"keyId", "random,keyId=AAAAAAAAAAAAAAAAAAAAAA==", false},
+		{"unknown algorithm", "aes,keyAltName=pii", true},
+		{"missing key", "random", true},
+		{"both keys", "random,keyAltName=pii,keyId=AAAAAAAAAAAAAAAAAAAAAA==", true},
+		{"short keyId", "random,keyId=AAAA", true},
+		{"unknown option", "random,keyAltName=pii,foo=bar", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseEncryptTag(tc.tag)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package geo provides GeoJSON geometries and constructors for geospatial query operators. The
+// geometries marshal to and unmarshal from GeoJSON objects, so they can be stored in fields with a
+// 2dsphere index and used in queries:
+//
+//	type Place struct {
+//		Name     string    `bson:"name"`
+//		Location geo.Point `bson:"location"`
+//	}
+//
+//	filter := bson.D{{"location", geo.Near(geo.NewPoint(-73.97, 40.77), geo.NewNearOptions().SetMaxDistance(500))}}
+//	cursor, err := coll.Find(ctx, filter)
+package geo // import "go.mongodb.org/mongo-driver/mongo/geo"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Position is a GeoJSON position: a longitude followed by a latitude, in degrees.
+type Position [2]float64
+
+// Geometry is a GeoJSON geometry. It is implemented by Point, LineString, Polygon and
+// MultiPolygon.
+type Geometry interface {
+	bson.Marshaler
+	geometryType() string
+}
+
+// Point is a GeoJSON Point.
+type Point struct {
+	Coordinates Position
+}
+
+// NewPoint creates a Point at the given longitude and latitude.
+func NewPoint(longitude, latitude float64) Point {
+	return Point{Coordinates: Position{longitude, latitude}}
+}
+
+func (Point) geometryType() string { return "Point" }
+
+// MarshalBSON implements the bson.Marshaler interface.
+func (p Point) MarshalBSON() ([]byte, error) {
+	return marshalGeometry(p.geometryType(), p.Coordinates)
+}
+
+// UnmarshalBSON implements the bson.Unmarshaler interface.
+func (p *Point) UnmarshalBSON(data []byte) error {
+	return unmarshalGeometry(data, p.geometryType(), &p.Coordinates)
+}
+
+// LineString is a GeoJSON LineString of two or more positions.
+type LineString struct {
+	Coordinates []Position
+}
+
+func (LineString) geometryType() string { return "LineString" }
+
+// MarshalBSON implements the bson.Marshaler interface.
+func (ls LineString) MarshalBSON() ([]byte, error) {
+	if len(ls.Coordinates) < 2 {
+		return nil, errors.New("geo: a LineString must have at least 2 positions")
+	}
+	return marshalGeometry(ls.geometryType(), ls.Coordinates)
+}
+
+// UnmarshalBSON implements the bson.Unmarshaler interface.
+func (ls *LineString) UnmarshalBSON(data []byte) error {
+	return unmarshalGeometry(data, ls.geometryType(), &ls.Coordinates)
+}
+
+// Polygon is a GeoJSON Polygon. The first ring is the exterior ring and any others are holes. Each
+// ring must be closed, with its last position equal to its first, and have at least 4 positions.
+type Polygon struct {
+	Coordinates [][]Position
+}
+
+func (Polygon) geometryType() string { return "Polygon" }
+
+// MarshalBSON implements the bson.Marshaler interface.
+func (p Polygon) MarshalBSON() ([]byte, error) {
+	if err := validatePolygon(p.Coordinates); err != nil {
+		return nil, err
+	}
+	return marshalGeometry(p.geometryType(), p.Coordinates)
+}
+
+// UnmarshalBSON implements the bson.Unmarshaler interface.
+func (p *Polygon) UnmarshalBSON(data []byte) error {
+	return unmarshalGeometry(data, p.geometryType(), &p.Coordinates)
+}
+
+// MultiPolygon is a GeoJSON MultiPolygon.
+type MultiPolygon struct {
+	Coordinates [][][]Position
+}
+
+func (MultiPolygon) geometryType() string { return "MultiPolygon" }
+
+// MarshalBSON implements the bson.Marshaler interface.
+func (mp MultiPolygon) MarshalBSON() ([]byte, error) {
+	for _, polygon := range mp.Coordinates {
+		if err := validatePolygon(polygon); err != nil {
+			return nil, err
+		}
+	}
+	return marshalGeometry(mp.geometryType(), mp.Coordinates)
+}
+
+// UnmarshalBSON implements the bson.Unmarshaler interface.
+func (mp *MultiPolygon) UnmarshalBSON(data []byte) error {
+	return unmarshalGeometry(data, mp.geometryType(), &mp.Coordinates)
+}
+
+func validatePolygon(rings [][]Position) error {
+	if len(rings) == 0 {
+		return errors.New("geo: a Polygon must have at least 1 ring")
+	}
+	for i, ring := range rings {
+		if len(ring) < 4 {
+			return fmt.Errorf("geo: ring %d of a Polygon must have at least 4 positions", i)
+		}
+		if ring[0] != ring[len(ring)-1] {
+			return fmt.Errorf("geo: ring %d of a Polygon is not closed", i)
+		}
+	}
+	return nil
+}
+
+type geoJSON struct {
+	Type        string      `bson:"type"`
+	Coordinates interface{} `bson:"coordinates"`
+}
+
+func marshalGeometry(typ string, coordinates interface{}) ([]byte, error) {
+	return bson.Marshal(geoJSON{Type: typ, Coordinates: coordinates})
+}
+
+func unmarshalGeometry(data []byte, typ string, coordinates interface{}) error {
+	var doc struct {
+		Type        string        `bson:"type"`
+		Coordinates bson.RawValue `bson:"coordinates"`
+	}
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if doc.Type != typ {
+		return fmt.Errorf("geo: cannot unmarshal a GeoJSON object of type %q into a %s", doc.Type, typ)
+	}
+	return doc.Coordinates.Unmarshal(coordinates)
+}
+
+// NearOptions represents all possible options for the $near and $nearSphere operators.
+type NearOptions struct {
+	MinDistance *float64 // The minimum distance from the point, in meters.
+	MaxDistance *float64 // The maximum distance from the point, in meters.
+}
+
+// NewNearOptions returns a pointer to a new NearOptions.
+func NewNearOptions() *NearOptions {
+	return &NearOptions{}
+}
+
+// SetMinDistance specifies the minimum distance from the point, in meters, of matching documents.
+func (no *NearOptions) SetMinDistance(meters float64) *NearOptions {
+	no.MinDistance = &meters
+	return no
+}
+
+// SetMaxDistance specifies the maximum distance from the point, in meters, of matching documents.
+func (no *NearOptions) SetMaxDistance(meters float64) *NearOptions {
+	no.MaxDistance = &meters
+	return no
+}
+
+// Near creates a $near operator that matches documents near p and sorts them from nearest to
+// farthest. The field it is applied to must have a 2dsphere index.
+func Near(p Point, opts ...*NearOptions) bson.D {
+	return near("$near", p, opts)
+}
+
+// NearSphere creates a $nearSphere operator that matches documents near p, calculating distances
+// on a sphere, and sorts them from nearest to farthest.
+func NearSphere(p Point, opts ...*NearOptions) bson.D {
+	return near("$nearSphere", p, opts)
+}
+
+func near(operator string, p Point, opts []*NearOptions) bson.D {
+	var min, max *float64
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.MinDistance != nil {
+			min = opt.MinDistance
+		}
+		if opt.MaxDistance != nil {
+			max = opt.MaxDistance
+		}
+	}
+
+	doc := bson.D{{"$geometry", p}}
+	if min != nil {
+		doc = append(doc, bson.E{"$minDistance", *min})
+	}
+	if max != nil {
+		doc = append(doc, bson.E{"$maxDistance", *max})
+	}
+	return bson.D{{operator, doc}}
+}
+
+// GeoWithin creates a $geoWithin operator that matches documents whose geometries are entirely
+// within g, which should be a Polygon or MultiPolygon.
+func GeoWithin(g Geometry) bson.D {
+	return bson.D{{"$geoWithin", bson.D{{"$geometry", g}}}}
+}
+
+// GeoWithinCenterSphere creates a $geoWithin operator that matches documents whose geometries are
+// entirely within the circle on a sphere with the given center and radius, in radians. A distance
+// in meters on the Earth can be converted to radians by dividing it by the radius of the Earth,
+// 6378100 meters.
+func GeoWithinCenterSphere(center Position, radius float64) bson.D {
+	return bson.D{{"$geoWithin", bson.D{{"$centerSphere", bson.A{center, radius}}}}}
+}
+
+// GeoIntersects creates a $geoIntersects operator that matches documents whose geometries
+// intersect g.
+func GeoIntersects(g Geometry) bson.D {
+	return bson.D{{"$geoIntersects", bson.D{{"$geometry", g}}}}
+}
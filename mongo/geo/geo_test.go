@@ -0,0 +1,164 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var square = []Position{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}
+
+func TestGeometries(t *testing.T) {
+	testCases := []struct {
+		name     string
+		geometry Geometry
+		decoded  interface{}
+		expected bson.D
+	}{
+		{"Point", NewPoint(-73.97, 40.77), &Point{}, bson.D{{"type", "Point"}, {"coordinates", bson.A{-73.97, 40.77}}}},
+		{
+			"LineString",
+			LineString{Coordinates: []Position{{0, 0}, {1, 1}}},
+			&LineString{},
+			bson.D{{"type", "LineString"}, {"coordinates", bson.A{bson.A{0.0, 0.0}, bson.A{1.0, 1.0}}}},
+		},
+		{
+			"Polygon",
+			Polygon{Coordinates: [][]Position{square}},
+			&Polygon{},
+			bson.D{{"type", "Polygon"}, {"coordinates", bson.A{ring(square)}}},
+		},
+		{
+			"MultiPolygon",
+			MultiPolygon{Coordinates: [][][]Position{{square}, {square}}},
+			&MultiPolygon{},
+			bson.D{{"type", "MultiPolygon"}, {"coordinates", bson.A{bson.A{ring(square)}, bson.A{ring(square)}}}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := bson.Marshal(bson.D{{"location", tc.geometry}})
+			require.NoError(t, err)
+			expected, err := bson.Marshal(bson.D{{"location", tc.expected}})
+			require.NoError(t, err)
+			require.Equal(t, bson.Raw(expected), bson.Raw(b))
+
+			require.NoError(t, bson.Raw(b).Lookup("location").Unmarshal(tc.decoded))
+			require.Equal(t, tc.geometry, geometryValue(tc.decoded))
+		})
+	}
+
+	t.Run("struct field", func(t *testing.T) {
+		type place struct {
+			Name     string `bson:"name"`
+			Location Point  `bson:"location"`
+		}
+		b, err := bson.Marshal(place{Name: "park", Location: NewPoint(1, 2)})
+		require.NoError(t, err)
+		var decoded place
+		require.NoError(t, bson.Unmarshal(b, &decoded))
+		require.Equal(t, place{Name: "park", Location: NewPoint(1, 2)}, decoded)
+	})
+	t.Run("integer coordinates", func(t *testing.T) {
+		b, err := bson.Marshal(bson.D{{"type", "Point"}, {"coordinates", bson.A{int32(1), int64(2)}}})
+		require.NoError(t, err)
+		var p Point
+		require.NoError(t, bson.Unmarshal(b, &p))
+		require.Equal(t, NewPoint(1, 2), p)
+	})
+	t.Run("wrong type", func(t *testing.T) {
+		b, err := bson.Marshal(NewPoint(1, 2))
+		require.NoError(t, err)
+		require.Error(t, bson.Unmarshal(b, &Polygon{}))
+	})
+}
+
+func TestInvalidGeometries(t *testing.T) {
+	testCases := []struct {
+		name     string
+		geometry Geometry
+	}{
+		{"LineString with one position", LineString{Coordinates: []Position{{0, 0}}}},
+		{"Polygon without rings", Polygon{}},
+		{"Polygon with a short ring", Polygon{Coordinates: [][]Position{{{0, 0}, {1, 1}, {0, 0}}}}},
+		{"Polygon with an open ring", Polygon{Coordinates: [][]Position{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}}}},
+		{"MultiPolygon with an open ring", MultiPolygon{Coordinates: [][][]Position{{square}, {square[:4]}}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := bson.Marshal(bson.D{{"location", tc.geometry}})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestOperators(t *testing.T) {
+	point := NewPoint(1, 2)
+	polygon := Polygon{Coordinates: [][]Position{square}}
+	testCases := []struct {
+		name     string
+		operator bson.D
+		expected bson.D
+	}{
+		{"near", Near(point), bson.D{{"$near", bson.D{{"$geometry", point}}}}},
+		{
+			"near with distances",
+			Near(point, NewNearOptions().SetMinDistance(10), NewNearOptions().SetMaxDistance(500)),
+			bson.D{{"$near", bson.D{{"$geometry", point}, {"$minDistance", 10.0}, {"$maxDistance", 500.0}}}},
+		},
+		{
+			"nearSphere",
+			NearSphere(point, NewNearOptions().SetMaxDistance(5)),
+			bson.D{{"$nearSphere", bson.D{{"$geometry", point}, {"$maxDistance", 5.0}}}},
+		},
+		{"geoWithin", GeoWithin(polygon), bson.D{{"$geoWithin", bson.D{{"$geometry", polygon}}}}},
+		{
+			"geoWithin centerSphere",
+			GeoWithinCenterSphere(Position{1, 2}, 0.1),
+			bson.D{{"$geoWithin", bson.D{{"$centerSphere", bson.A{Position{1, 2}, 0.1}}}}},
+		},
+		{"geoIntersects", GeoIntersects(polygon), bson.D{{"$geoIntersects", bson.D{{"$geometry", polygon}}}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.operator)
+		})
+	}
+
+	t.Run("centerSphere encoding", func(t *testing.T) {
+		b, err := bson.Marshal(GeoWithinCenterSphere(Position{1, 2}, 0.1))
+		require.NoError(t, err)
+		expected, err := bson.Marshal(bson.D{{"$geoWithin", bson.D{{"$centerSphere", bson.A{bson.A{1.0, 2.0}, 0.1}}}}})
+		require.NoError(t, err)
+		require.Equal(t, bson.Raw(expected), bson.Raw(b))
+	})
+}
+
+func ring(positions []Position) bson.A {
+	a := make(bson.A, 0, len(positions))
+	for _, p := range positions {
+		a = append(a, bson.A{p[0], p[1]})
+	}
+	return a
+}
+
+func geometryValue(decoded interface{}) Geometry {
+	switch g := decoded.(type) {
+	case *Point:
+		return *g
+	case *LineString:
+		return *g
+	case *Polygon:
+		return *g
+	case *MultiPolygon:
+		return *g
+	}
+	return nil
+}
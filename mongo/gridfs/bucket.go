@@ -48,6 +48,8 @@ type Bucket struct {
 	rc        *readconcern.ReadConcern
 	rp        *readpref.ReadPref
 
+	downloadParallelism int32
+
 	firstWriteDone bool
 	readBuf        []byte
 	writeBuf       []byte
@@ -89,6 +91,9 @@ func NewBucket(db *mongo.Database, opts ...*options.BucketOptions) (*Bucket, err
 	if bo.ReadPreference != nil {
 		b.rp = bo.ReadPreference
 	}
+	if bo.DownloadParallelism != nil {
+		b.downloadParallelism = *bo.DownloadParallelism
+	}
 
 	var collOpts = options.Collection().SetWriteConcern(b.wc).SetReadConcern(b.rc).SetReadPreference(b.rp)
 
@@ -112,6 +117,16 @@ func (b *Bucket) SetReadDeadline(t time.Time) error {
 	return nil
 }
 
+// GetFilesCollection returns a handle to the collection that stores the file documents for this bucket.
+func (b *Bucket) GetFilesCollection() *mongo.Collection {
+	return b.filesColl
+}
+
+// GetChunksCollection returns a handle to the collection that stores the file chunks for this bucket.
+func (b *Bucket) GetChunksCollection() *mongo.Collection {
+	return b.chunksColl
+}
+
 // OpenUploadStream creates a file ID new upload stream for a file given the filename.
 func (b *Bucket) OpenUploadStream(filename string, opts ...*options.UploadOptions) (*UploadStream, error) {
 	return b.OpenUploadStreamWithID(primitive.NewObjectID(), filename, opts...)
@@ -190,8 +205,17 @@ func (b *Bucket) OpenDownloadStream(fileID interface{}) (*DownloadStream, error)
 }
 
 // DownloadToStream downloads the file with the specified fileID and writes it to the provided io.Writer.
-// Returns the number of bytes written to the steam and an error, or nil if there was no error.
+// Returns the number of bytes written to the steam and an error, or nil if there was no error. If the
+// bucket's download parallelism is greater than 1, ranges of chunks are fetched concurrently.
 func (b *Bucket) DownloadToStream(fileID interface{}, stream io.Writer) (int64, error) {
+	if b.downloadParallelism > 1 {
+		id, err := convertFileID(fileID)
+		if err != nil {
+			return 0, err
+		}
+		return b.parallelDownloadToStream(bsonx.Doc{{"_id", id}}, stream)
+	}
+
 	ds, err := b.OpenDownloadStream(fileID)
 	if err != nil {
 		return 0, err
@@ -202,6 +226,13 @@ func (b *Bucket) DownloadToStream(fileID interface{}, stream io.Writer) (int64,
 
 // OpenDownloadStreamByName opens a download stream for the file with the given filename.
 func (b *Bucket) OpenDownloadStreamByName(filename string, opts ...*options.NameOptions) (*DownloadStream, error) {
+	filter, findOpts := fileByName(filename, opts...)
+	return b.openDownloadStream(filter, findOpts)
+}
+
+// fileByName returns the filter and find options that select the revision of the file with the
+// given filename.
+func fileByName(filename string, opts ...*options.NameOptions) (bsonx.Doc, *options.FindOptions) {
 	var numSkip int32 = -1
 	var sortOrder int32 = 1
 
@@ -217,11 +248,17 @@ func (b *Bucket) OpenDownloadStreamByName(filename string, opts ...*options.Name
 
 	findOpts := options.Find().SetSkip(int64(numSkip)).SetSort(bsonx.Doc{{"uploadDate", bsonx.Int32(sortOrder)}})
 
-	return b.openDownloadStream(bsonx.Doc{{"filename", bsonx.String(filename)}}, findOpts)
+	return bsonx.Doc{{"filename", bsonx.String(filename)}}, findOpts
 }
 
-// DownloadToStreamByName downloads the file with the given name to the given io.Writer.
+// DownloadToStreamByName downloads the file with the given name to the given io.Writer. If the
+// bucket's download parallelism is greater than 1, ranges of chunks are fetched concurrently.
 func (b *Bucket) DownloadToStreamByName(filename string, stream io.Writer, opts ...*options.NameOptions) (int64, error) {
+	if b.downloadParallelism > 1 {
+		filter, findOpts := fileByName(filename, opts...)
+		return b.parallelDownloadToStream(filter, stream, findOpts)
+	}
+
 	ds, err := b.OpenDownloadStreamByName(filename, opts...)
 	if err != nil {
 		return 0, err
@@ -333,28 +370,11 @@ func (b *Bucket) openDownloadStream(filter interface{}, opts ...*options.FindOpt
 		defer cancel()
 	}
 
-	cursor, err := b.findFile(ctx, filter, opts...)
-	if err != nil {
-		return nil, err
-	}
-
-	fileLenElem, err := cursor.Current.LookupErr("length")
-	if err != nil {
-		return nil, err
-	}
-	fileIDElem, err := cursor.Current.LookupErr("_id")
+	fileIDElem, fileLen, err := b.findFileInfo(ctx, filter, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	var fileLen int64
-	switch fileLenElem.Type {
-	case bsontype.Int32:
-		fileLen = int64(fileLenElem.Int32())
-	default:
-		fileLen = fileLenElem.Int64()
-	}
-
 	if fileLen == 0 {
 		return newDownloadStream(nil, b.chunkSize, 0), nil
 	}
@@ -413,6 +433,36 @@ func (b *Bucket) findFile(ctx context.Context, filter interface{}, opts ...*opti
 	return cursor, nil
 }
 
+// findFileInfo returns the _id and length of the file selected by filter and opts.
+func (b *Bucket) findFileInfo(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (bson.RawValue, int64, error) {
+	cursor, err := b.findFile(ctx, filter, opts...)
+	if err != nil {
+		return bson.RawValue{}, 0, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	fileLenElem, err := cursor.Current.LookupErr("length")
+	if err != nil {
+		return bson.RawValue{}, 0, err
+	}
+	fileIDElem, err := cursor.Current.LookupErr("_id")
+	if err != nil {
+		return bson.RawValue{}, 0, err
+	}
+
+	var fileLen int64
+	switch fileLenElem.Type {
+	case bsontype.Int32:
+		fileLen = int64(fileLenElem.Int32())
+	default:
+		fileLen = fileLenElem.Int64()
+	}
+
+	return fileIDElem, fileLen, nil
+}
+
 func (b *Bucket) findChunks(ctx context.Context, fileID interface{}) (*mongo.Cursor, error) {
 	id, err := convertFileID(fileID)
 	if err != nil {
@@ -185,17 +185,7 @@ func (ds *DownloadStream) fillBuffer(ctx context.Context) error {
 	_, dataBytes := data.Binary()
 	copied := copy(ds.buffer, dataBytes)
 
-	bytesLen := int32(len(dataBytes))
-	if ds.expectedChunk == ds.numChunks {
-		// final chunk can be fewer than ds.chunkSize bytes
-		bytesDownloaded := ds.chunkSize * (ds.expectedChunk - 1)
-		bytesRemaining := ds.fileLen - int64(bytesDownloaded)
-
-		if int64(bytesLen) != bytesRemaining {
-			return ErrWrongSize
-		}
-	} else if bytesLen != ds.chunkSize {
-		// all intermediate chunks must have size ds.chunkSize
+	if !validChunkSize(ds.expectedChunk-1, int32(len(dataBytes)), ds.numChunks, ds.chunkSize, ds.fileLen) {
 		return ErrWrongSize
 	}
 
@@ -204,3 +194,14 @@ func (ds *DownloadStream) fillBuffer(ctx context.Context) error {
 
 	return nil
 }
+
+// validChunkSize returns true if the chunk with index n of a file of fileLen bytes stored in numChunks
+// chunks of chunkSize bytes has the given size.
+func validChunkSize(n, size, numChunks, chunkSize int32, fileLen int64) bool {
+	if n == numChunks-1 {
+		// final chunk can be fewer than chunkSize bytes
+		return int64(size) == fileLen-int64(chunkSize)*int64(n)
+	}
+	// all intermediate chunks must have size chunkSize
+	return size == chunkSize
+}
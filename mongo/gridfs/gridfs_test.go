@@ -221,7 +221,7 @@ func TestGridFS(t *testing.T) {
 			p[i] = byte(rand.Intn(100))
 		}
 
-		_, err = bucket.UploadFromStream("filename", bytes.NewReader(p))
+		fileID, err := bucket.UploadFromStream("filename", bytes.NewReader(p))
 		if err != nil {
 			t.Fatalf("Upload failed: %v", err)
 		}
@@ -235,6 +235,30 @@ func TestGridFS(t *testing.T) {
 		if !bytes.Equal(p, w.Bytes()) {
 			t.Errorf("Downloaded file did not match p.")
 		}
+
+		// Test that a parallel download reassembles the chunk ranges in order.
+		parallelBucket, err := NewBucket(db, options.GridFSBucket().SetDownloadParallelism(3))
+		if err != nil {
+			t.Fatalf("Failed to create bucket: %v", err)
+		}
+
+		w.Reset()
+		n, err := parallelBucket.DownloadToStream(fileID, w)
+		if err != nil {
+			t.Fatalf("Parallel download failed: %v", err)
+		}
+		if n != int64(size) || !bytes.Equal(p, w.Bytes()) {
+			t.Errorf("Parallel download did not match p.")
+		}
+
+		w.Reset()
+		_, err = parallelBucket.DownloadToStreamByName("filename", w)
+		if err != nil {
+			t.Fatalf("Parallel download by name failed: %v", err)
+		}
+		if !bytes.Equal(p, w.Bytes()) {
+			t.Errorf("Parallel download by name did not match p.")
+		}
 	})
 
 	err = client.Disconnect(ctx)
@@ -242,3 +266,54 @@ func TestGridFS(t *testing.T) {
 		t.Fatalf("Problem disconnecting from client: %v", err)
 	}
 }
+
+func TestValidChunkSize(t *testing.T) {
+	testCases := []struct {
+		name  string
+		n     int32
+		size  int32
+		valid bool
+	}{
+		{"full intermediate chunk", 0, 10, true},
+		{"short intermediate chunk", 1, 5, false},
+		{"final chunk with the remaining bytes", 2, 5, true},
+		{"final chunk with too many bytes", 2, 10, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// a 25 byte file stored in 3 chunks of 10 bytes
+			require.Equal(t, tc.valid, validChunkSize(tc.n, tc.size, 3, 10, 25))
+		})
+	}
+}
+
+func TestBucketCollections(t *testing.T) {
+	client, err := mongo.NewClient(options.Client().ApplyURI("mongodb://localhost:27017"))
+	require.NoError(t, err)
+	db := client.Database("gridfs")
+
+	testCases := []struct {
+		name   string
+		opts   *options.BucketOptions
+		prefix string
+	}{
+		{"default name", nil, "fs"},
+		{"custom name", options.GridFSBucket().SetName("images"), "images"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bucket, err := NewBucket(db, tc.opts)
+			require.NoError(t, err)
+
+			files := bucket.GetFilesCollection()
+			require.Equal(t, tc.prefix+".files", files.Name())
+			require.Equal(t, "gridfs", files.Database().Name())
+
+			chunks := bucket.GetChunksCollection()
+			require.Equal(t, tc.prefix+".chunks", chunks.Name())
+			require.Equal(t, "gridfs", chunks.Database().Name())
+		})
+	}
+}
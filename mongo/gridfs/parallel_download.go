@@ -0,0 +1,140 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package gridfs
+
+import (
+	"context"
+	"io"
+	"math"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+)
+
+// parallelRangeChunks is the number of chunks fetched by each query of a parallel download.
+const parallelRangeChunks int32 = 4
+
+// chunkRange is the data of a range of chunks fetched by a parallel download.
+type chunkRange struct {
+	data []byte
+	err  error
+}
+
+// parallelDownloadToStream downloads the file selected by filter and opts to stream. Ranges of
+// parallelRangeChunks chunks are fetched by up to b.downloadParallelism concurrent queries and are
+// written to stream in order.
+func (b *Bucket) parallelDownloadToStream(filter interface{}, stream io.Writer, opts ...*options.FindOptions) (int64, error) {
+	ctx, cancel := deadlineContext(b.readDeadline)
+	if cancel != nil {
+		defer cancel()
+	}
+
+	fileID, fileLen, err := b.findFileInfo(ctx, filter, opts...)
+	if err != nil {
+		return 0, err
+	}
+	id, err := convertFileID(fileID)
+	if err != nil {
+		return 0, err
+	}
+	numChunks := int32(math.Ceil(float64(fileLen) / float64(b.chunkSize)))
+
+	// stop fetching ranges once the download fails
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	// a range is queued before it is fetched, so the queue and the range being written bound the
+	// number of ranges in flight to the download parallelism
+	pending := make(chan chan chunkRange, b.downloadParallelism-1)
+	go func() {
+		defer close(pending)
+		for start := int32(0); start < numChunks; start += parallelRangeChunks {
+			end := start + parallelRangeChunks
+			if end > numChunks {
+				end = numChunks
+			}
+
+			result := make(chan chunkRange, 1)
+			select {
+			case pending <- result:
+			case <-ctx.Done():
+				return
+			}
+			go func(start, end int32) {
+				data, err := b.downloadChunkRange(ctx, id, start, end, numChunks, fileLen)
+				result <- chunkRange{data: data, err: err}
+			}(start, end)
+		}
+	}()
+
+	var written int64
+	for result := range pending {
+		r := <-result
+		if r.err != nil {
+			return written, r.err
+		}
+
+		n, err := stream.Write(r.data)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// downloadChunkRange returns the data of the chunks of the file with the given id whose indexes are in
+// [start, end).
+func (b *Bucket) downloadChunkRange(ctx context.Context, id bsonx.Val, start, end, numChunks int32, fileLen int64) ([]byte, error) {
+	cursor, err := b.chunksColl.Find(ctx,
+		bsonx.Doc{
+			{"files_id", id},
+			{"n", bsonx.Document(bsonx.Doc{{"$gte", bsonx.Int32(start)}, {"$lt", bsonx.Int32(end)}})},
+		},
+		options.Find().SetSort(bsonx.Doc{{"n", bsonx.Int32(1)}})) // sort by chunk index
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = cursor.Close(ctx)
+	}()
+
+	data := make([]byte, 0, int64(end-start)*int64(b.chunkSize))
+	expectedChunk := start
+	for cursor.Next(ctx) {
+		chunkIndex, err := cursor.Current.LookupErr("n")
+		if err != nil {
+			return nil, err
+		}
+		if chunkIndex.Int32() != expectedChunk {
+			return nil, ErrWrongIndex
+		}
+
+		chunkData, err := cursor.Current.LookupErr("data")
+		if err != nil {
+			return nil, err
+		}
+		_, dataBytes := chunkData.Binary()
+		if !validChunkSize(expectedChunk, int32(len(dataBytes)), numChunks, b.chunkSize, fileLen) {
+			return nil, ErrWrongSize
+		}
+
+		data = append(data, dataBytes...)
+		expectedChunk++
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// every chunk in the range must exist for the pieces to be reassembled in order
+	if expectedChunk != end {
+		return nil, ErrWrongIndex
+	}
+
+	return data, nil
+}
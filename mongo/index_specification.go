@@ -0,0 +1,187 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IndexSpecification is an index of a collection, as reported by the listIndexes command. Raw holds
+// the whole specification, including the options that have no field of their own.
+type IndexSpecification struct {
+	Name         string   `bson:"name"`
+	Namespace    string   `bson:"ns"` // Only reported by servers older than 4.4.
+	KeysDocument bson.Raw `bson:"key"`
+	Version      int32    `bson:"v"`
+	Raw          bson.Raw `bson:"-"`
+}
+
+// UnmarshalBSON implements the bson.Unmarshaler interface.
+func (is *IndexSpecification) UnmarshalBSON(b []byte) error {
+	type spec IndexSpecification
+	var s spec
+	if err := bson.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*is = IndexSpecification(s)
+	is.KeysDocument = append(bson.Raw(nil), is.KeysDocument...)
+	is.Raw = append(bson.Raw(nil), b...)
+	return nil
+}
+
+// IndexModel returns the IndexModel that creates the index. Comparing it with the IndexModel an
+// application expects tells which indexes need to be created or dropped. The options of the
+// specification that IndexOptions cannot express, such as hidden, are ignored, and options the
+// server adds to those the index was created with, such as v, are included.
+func (is *IndexSpecification) IndexModel() (IndexModel, error) {
+	opts := options.Index()
+	elems, err := is.Raw.Elements()
+	if err != nil {
+		return IndexModel{}, err
+	}
+
+	for _, elem := range elems {
+		key, val := elem.Key(), elem.Value()
+		var ok bool
+		switch key {
+		case "name":
+			var name string
+			name, ok = val.StringValueOK()
+			opts.Name = &name
+		case "v":
+			opts.Version, ok = indexInt32(val)
+		case "background":
+			opts.Background, ok = indexBool(val)
+		case "expireAfterSeconds":
+			opts.ExpireAfterSeconds, ok = indexInt32(val)
+		case "sparse":
+			opts.Sparse, ok = indexBool(val)
+		case "unique":
+			opts.Unique, ok = indexBool(val)
+		case "storageEngine":
+			opts.StorageEngine, ok = val.DocumentOK()
+		case "default_language":
+			var lang string
+			lang, ok = val.StringValueOK()
+			opts.DefaultLanguage = &lang
+		case "language_override":
+			var override string
+			override, ok = val.StringValueOK()
+			opts.LanguageOverride = &override
+		case "textIndexVersion":
+			opts.TextVersion, ok = indexInt32(val)
+		case "weights":
+			opts.Weights, ok = val.DocumentOK()
+		case "2dsphereIndexVersion":
+			opts.SphereVersion, ok = indexInt32(val)
+		case "bits":
+			opts.Bits, ok = indexInt32(val)
+		case "max":
+			opts.Max, ok = indexFloat64(val)
+		case "min":
+			opts.Min, ok = indexFloat64(val)
+		case "bucketSize":
+			opts.BucketSize, ok = indexInt32(val)
+		case "partialFilterExpression":
+			opts.PartialFilterExpression, ok = val.DocumentOK()
+		case "collation":
+			var doc bson.Raw
+			if doc, ok = val.DocumentOK(); ok {
+				opts.Collation, err = collationFromDocument(doc)
+				if err != nil {
+					return IndexModel{}, err
+				}
+			}
+		default:
+			continue
+		}
+		if !ok {
+			return IndexModel{}, fmt.Errorf("index option %s has invalid BSON type %s", key, val.Type)
+		}
+	}
+	return IndexModel{Keys: is.KeysDocument, Options: opts}, nil
+}
+
+// ListSpecifications returns the specifications of all the indexes in the collection.
+func (iv IndexView) ListSpecifications(ctx context.Context, opts ...*options.ListIndexesOptions) ([]*IndexSpecification, error) {
+	cursor, err := iv.List(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make([]*IndexSpecification, 0)
+	if err = cursor.All(ctx, &specs); err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+func indexInt32(val bson.RawValue) (*int32, bool) {
+	i, ok := val.AsInt32OK()
+	return &i, ok
+}
+
+func indexBool(val bson.RawValue) (*bool, bool) {
+	b, ok := val.BooleanOK()
+	return &b, ok
+}
+
+func indexFloat64(val bson.RawValue) (*float64, bool) {
+	if f, ok := val.DoubleOK(); ok {
+		return &f, true
+	}
+	i, ok := val.AsInt64OK()
+	f := float64(i)
+	return &f, ok
+}
+
+// collationFromDocument converts a collation document, as reported by the server, to a Collation.
+func collationFromDocument(doc bson.Raw) (*options.Collation, error) {
+	elems, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &options.Collation{}
+	for _, elem := range elems {
+		key, val := elem.Key(), elem.Value()
+		var ok bool
+		switch key {
+		case "locale":
+			c.Locale, ok = val.StringValueOK()
+		case "caseLevel":
+			c.CaseLevel, ok = val.BooleanOK()
+		case "caseFirst":
+			c.CaseFirst, ok = val.StringValueOK()
+		case "strength":
+			var strength int32
+			strength, ok = val.AsInt32OK()
+			c.Strength = int(strength)
+		case "numericOrdering":
+			c.NumericOrdering, ok = val.BooleanOK()
+		case "alternate":
+			c.Alternate, ok = val.StringValueOK()
+		case "maxVariable":
+			c.MaxVariable, ok = val.StringValueOK()
+		case "normalization":
+			c.Normalization, ok = val.BooleanOK()
+		case "backwards":
+			c.Backwards, ok = val.BooleanOK()
+		default:
+			// The server also reports the version of the collation, which cannot be set.
+			continue
+		}
+		if !ok {
+			return nil, fmt.Errorf("collation option %s has invalid BSON type %s", key, val.Type)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+)
+
+func TestIndexSpecification(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{
+		{"v", int32(2)},
+		{"unique", true},
+		{"key", bson.D{{"a", int32(1)}, {"b", int32(-1)}}},
+		{"name", "a_1_b_-1"},
+		{"ns", "db.coll"},
+		{"expireAfterSeconds", int64(3600)},
+		{"partialFilterExpression", bson.D{{"a", bson.D{{"$gt", int32(5)}}}}},
+		{"collation", bson.D{{"locale", "fr"}, {"caseLevel", false}, {"strength", int32(2)}, {"version", "57.1"}}},
+		{"hidden", true},
+	})
+	require.NoError(t, err)
+
+	var spec IndexSpecification
+	require.NoError(t, bson.Unmarshal(raw, &spec))
+	require.Equal(t, "a_1_b_-1", spec.Name)
+	require.Equal(t, "db.coll", spec.Namespace)
+	require.Equal(t, int32(2), spec.Version)
+	require.Equal(t, bson.Raw(raw), spec.Raw)
+
+	model, err := spec.IndexModel()
+	require.NoError(t, err)
+	require.Equal(t, spec.KeysDocument, model.Keys)
+	require.Equal(t, "a_1_b_-1", *model.Options.Name)
+	require.Equal(t, int32(2), *model.Options.Version)
+	require.True(t, *model.Options.Unique)
+	require.Equal(t, int32(3600), *model.Options.ExpireAfterSeconds)
+	require.Equal(t, &options.Collation{Locale: "fr", Strength: 2}, model.Options.Collation)
+	require.Nil(t, model.Options.Sparse)
+
+	// Creating the index from the model sends the options it was listed with.
+	iv := IndexView{coll: &Collection{registry: bson.DefaultRegistry}}
+	optsDoc, err := iv.createOptionsDoc(model.Options)
+	require.NoError(t, err)
+	want, err := bsonx.ReadDoc(raw)
+	require.NoError(t, err)
+	for _, key := range []string{"v", "unique", "name", "partialFilterExpression"} {
+		require.True(t, want.Lookup(key).Equal(optsDoc.Lookup(key)), "%s: got %v; want %v", key, optsDoc.Lookup(key), want.Lookup(key))
+	}
+	require.Equal(t, int32(3600), optsDoc.Lookup("expireAfterSeconds").Int32())
+
+	t.Run("invalid option", func(t *testing.T) {
+		raw, err := bson.Marshal(bson.D{{"key", bson.D{{"a", 1}}}, {"name", "a_1"}, {"unique", "yes"}})
+		require.NoError(t, err)
+		var spec IndexSpecification
+		require.NoError(t, bson.Unmarshal(raw, &spec))
+		_, err = spec.IndexModel()
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// IndexUsage is the usage of an index on one server, as reported by the $indexStats aggregation
+// stage.
+type IndexUsage struct {
+	Name     string        `bson:"name"`
+	Key      bson.D        `bson:"key"`
+	Host     string        `bson:"host"`
+	Shard    string        `bson:"shard,omitempty"` // Only set for sharded collections.
+	Accesses IndexAccesses `bson:"accesses"`
+	Building bool          `bson:"building,omitempty"` // True if the index is still being built.
+}
+
+// IndexAccesses counts the operations that used an index since the server started or the index was
+// created, whichever is later. The counts are not persisted across server restarts.
+type IndexAccesses struct {
+	Ops   int64     `bson:"ops"`
+	Since time.Time `bson:"since"`
+}
+
+// Stats returns the usage of the indexes of the collection, with one entry for each index on each
+// server the $indexStats stage runs on. For a replica set that is the server selected by the read
+// preference of the collection, and for a sharded collection every shard that owns data of the
+// collection.
+//
+// See https://docs.mongodb.com/manual/reference/operator/aggregation/indexStats/.
+func (iv IndexView) Stats(ctx context.Context) ([]IndexUsage, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cursor, err := iv.coll.Aggregate(ctx, bson.A{bson.D{{"$indexStats", bson.D{}}}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	stats := make([]IndexUsage, 0)
+	for cursor.Next(ctx) {
+		var usage IndexUsage
+		if err = cursor.Decode(&usage); err != nil {
+			return nil, err
+		}
+		stats = append(stats, usage)
+	}
+	return stats, replaceErrors(cursor.Err())
+}
+
+// Unused returns the names of the indexes of the collection that have not been used by any
+// operation on any server reporting their usage, in alphabetical order. Since usage counts are reset
+// when servers restart, an index is only reported once every server has been counting its usage for
+// at least minAge. The _id index and indexes that are still being built are never reported.
+func (iv IndexView) Unused(ctx context.Context, minAge time.Duration) ([]string, error) {
+	stats, err := iv.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return unusedIndexes(stats, time.Now(), minAge), nil
+}
+
+// unusedIndexes returns the names of the indexes in stats that were not used on any server since
+// before now minus minAge.
+func unusedIndexes(stats []IndexUsage, now time.Time, minAge time.Duration) []string {
+	cutoff := now.Add(-minAge)
+	used := make(map[string]bool)
+	for _, usage := range stats {
+		if usage.Accesses.Ops > 0 || usage.Building || usage.Accesses.Since.After(cutoff) {
+			used[usage.Name] = true
+		} else if _, ok := used[usage.Name]; !ok {
+			used[usage.Name] = false
+		}
+	}
+
+	names := make([]string, 0)
+	for name, inUse := range used {
+		if !inUse && name != "_id_" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
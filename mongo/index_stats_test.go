@@ -0,0 +1,56 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestUnusedIndexes(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	usage := func(name, host string, ops int64, since time.Time) IndexUsage {
+		return IndexUsage{Name: name, Host: host, Accesses: IndexAccesses{Ops: ops, Since: since}}
+	}
+
+	stats := []IndexUsage{
+		usage("_id_", "a", 0, old),
+		usage("used", "a", 10, old),
+		usage("unused", "a", 0, old),
+		usage("unused", "b", 0, old),
+		usage("used_on_one_shard", "a", 0, old),
+		usage("used_on_one_shard", "b", 3, old),
+		usage("restarted", "a", 0, old),
+		usage("restarted", "b", 0, now.Add(-time.Hour)),
+		{Name: "building", Building: true, Accesses: IndexAccesses{Since: old}},
+	}
+	require.Equal(t, []string{"unused"}, unusedIndexes(stats, now, 24*time.Hour))
+	require.Equal(t, []string{"restarted", "unused"}, unusedIndexes(stats, now, 0))
+	require.Equal(t, []string{}, unusedIndexes(nil, now, 0))
+}
+
+func TestIndexUsageDecode(t *testing.T) {
+	since := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	doc, err := bson.Marshal(bson.D{
+		{"name", "x_1"},
+		{"key", bson.D{{"x", int32(1)}}},
+		{"host", "localhost:27017"},
+		{"accesses", bson.D{{"ops", int64(7)}, {"since", since}}},
+	})
+	require.NoError(t, err)
+
+	var usage IndexUsage
+	require.NoError(t, bson.Unmarshal(doc, &usage))
+	require.Equal(t, "x_1", usage.Name)
+	require.Equal(t, bson.D{{"x", int32(1)}}, usage.Key)
+	require.Equal(t, int64(7), usage.Accesses.Ops)
+	require.True(t, since.Equal(usage.Accesses.Since))
+}
@@ -45,6 +45,9 @@ type IndexModel struct {
 
 // List returns a cursor iterating over all the indexes in the collection.
 func (iv IndexView) List(ctx context.Context, opts ...*options.ListIndexesOptions) (*Cursor, error) {
+	ctx, cancel := operationContext(ctx, iv.coll.client.timeout)
+	defer cancel()
+
 	sess := sessionFromContext(ctx)
 
 	err := iv.coll.client.validSession(sess)
@@ -68,17 +71,23 @@ func (iv IndexView) List(ctx context.Context, opts ...*options.ListIndexesOption
 		readSelector,
 		iv.coll.client.id,
 		iv.coll.client.topology.SessionPool,
+		iv.coll.client.retryReads,
 		opts...,
 	)
 	if err != nil {
 		if err == command.ErrEmptyCursor {
 			return newEmptyCursor(), nil
 		}
-		return nil, replaceErrors(err)
+		return nil, replaceErrors(timeoutError(ctx, err))
 	}
 
-	cursor, err := newCursor(batchCursor, iv.coll.registry)
-	return cursor, replaceErrors(err)
+	cursor, err := newCursor(retainSession(ctx, batchCursor), iv.coll.registry)
+	if err != nil {
+		return nil, replaceErrors(err)
+	}
+	cursor.timeout = iv.coll.client.timeout
+	iv.coll.client.cursors.track(cursor)
+	return cursor, nil
 }
 
 // CreateOne creates a single index in the collection specified by the model.
@@ -94,6 +103,9 @@ func (iv IndexView) CreateOne(ctx context.Context, model IndexModel, opts ...*op
 // CreateMany creates multiple indexes in the collection specified by the models. The names of the
 // created indexes are returned.
 func (iv IndexView) CreateMany(ctx context.Context, models []IndexModel, opts ...*options.CreateIndexesOptions) ([]string, error) {
+	ctx, cancel := operationContext(ctx, iv.coll.client.timeout)
+	defer cancel()
+
 	names := make([]string, 0, len(models))
 	indexes := bsonx.Arr{}
 
@@ -150,7 +162,7 @@ func (iv IndexView) CreateMany(ctx context.Context, models []IndexModel, opts ..
 		opts...,
 	)
 	if err != nil {
-		return nil, err
+		return nil, timeoutError(ctx, err)
 	}
 
 	return names, nil
@@ -237,6 +249,9 @@ func (iv IndexView) createOptionsDoc(opts *options.IndexOptions) (bsonx.Doc, err
 
 // DropOne drops the index with the given name from the collection.
 func (iv IndexView) DropOne(ctx context.Context, name string, opts ...*options.DropIndexesOptions) (bson.Raw, error) {
+	ctx, cancel := operationContext(ctx, iv.coll.client.timeout)
+	defer cancel()
+
 	if name == "*" {
 		return nil, ErrMultipleIndexDrop
 	}
@@ -255,7 +270,7 @@ func (iv IndexView) DropOne(ctx context.Context, name string, opts ...*options.D
 		Clock:   iv.coll.client.clock,
 	}
 
-	return driverlegacy.DropIndexes(
+	res, err := driverlegacy.DropIndexes(
 		ctx, cmd,
 		iv.coll.client.topology,
 		iv.coll.writeSelector,
@@ -263,10 +278,14 @@ func (iv IndexView) DropOne(ctx context.Context, name string, opts ...*options.D
 		iv.coll.client.topology.SessionPool,
 		opts...,
 	)
+	return res, timeoutError(ctx, err)
 }
 
 // DropAll drops all indexes in the collection.
 func (iv IndexView) DropAll(ctx context.Context, opts ...*options.DropIndexesOptions) (bson.Raw, error) {
+	ctx, cancel := operationContext(ctx, iv.coll.client.timeout)
+	defer cancel()
+
 	sess := sessionFromContext(ctx)
 
 	err := iv.coll.client.validSession(sess)
@@ -281,7 +300,7 @@ func (iv IndexView) DropAll(ctx context.Context, opts ...*options.DropIndexesOpt
 		Clock:   iv.coll.client.clock,
 	}
 
-	return driverlegacy.DropIndexes(
+	res, err := driverlegacy.DropIndexes(
 		ctx, cmd,
 		iv.coll.client.topology,
 		iv.coll.writeSelector,
@@ -289,6 +308,7 @@ func (iv IndexView) DropAll(ctx context.Context, opts ...*options.DropIndexesOpt
 		iv.coll.client.topology.SessionPool,
 		opts...,
 	)
+	return res, timeoutError(ctx, err)
 }
 
 func getOrGenerateIndexName(registry *bsoncodec.Registry, model IndexModel) (string, error) {
@@ -0,0 +1,245 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package jsonschema derives $jsonSchema validator documents from Go structs so that the schema
+// validation of a collection can be kept in sync with the models used to read and write it. Field
+// names and flags are taken from the bson struct tags in the same way the default struct codec
+// does. A field is required unless it is tagged omitempty:
+//
+//	type Person struct {
+//		Name  string   `bson:"name"`
+//		Email *string  `bson:"email,omitempty"`
+//		Tags  []string `bson:"tags"`
+//	}
+//
+//	validator, err := jsonschema.Validator(Person{})
+//	res := db.RunCommand(ctx, bson.D{{"collMod", "people"}, {"validator", validator}})
+package jsonschema // import "go.mongodb.org/mongo-driver/mongo/jsonschema"
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var tTime = reflect.TypeOf(time.Time{})
+var tRaw = reflect.TypeOf(bson.Raw(nil))
+var tByteSlice = reflect.TypeOf([]byte(nil))
+
+// bsonTypes maps types with a fixed BSON representation to their $jsonSchema bsonType alias.
+var bsonTypes = map[reflect.Type]string{
+	tTime:                                    "date",
+	tRaw:                                     "object",
+	tByteSlice:                               "binData",
+	reflect.TypeOf(primitive.Binary{}):       "binData",
+	reflect.TypeOf(primitive.DateTime(0)):    "date",
+	reflect.TypeOf(primitive.Decimal128{}):   "decimal",
+	reflect.TypeOf(primitive.JavaScript("")): "javascript",
+	reflect.TypeOf(primitive.ObjectID{}):     "objectId",
+	reflect.TypeOf(primitive.Regex{}):        "regex",
+	reflect.TypeOf(primitive.Symbol("")):     "symbol",
+	reflect.TypeOf(primitive.Timestamp{}):    "timestamp",
+	reflect.TypeOf(primitive.D{}):            "object",
+	reflect.TypeOf(primitive.M{}):            "object",
+	reflect.TypeOf(primitive.A{}):            "array",
+}
+
+// Validator returns a validator document of the form {$jsonSchema: <schema>} for the struct type of
+// v. The result can be used as the validator option of the create and collMod commands.
+func Validator(v interface{}) (bson.D, error) {
+	schema, err := FromStruct(v)
+	if err != nil {
+		return nil, err
+	}
+	return bson.D{{"$jsonSchema", schema}}, nil
+}
+
+// FromStruct returns the $jsonSchema document describing the struct type of v. v must be a struct
+// or a pointer to a struct.
+func FromStruct(v interface{}) (bson.D, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot derive a JSON schema from %T, a struct is required", v)
+	}
+
+	g := generator{visiting: make(map[reflect.Type]bool)}
+	return g.structSchema(t)
+}
+
+type generator struct {
+	visiting map[reflect.Type]bool // struct types being generated, to stop on recursive types
+}
+
+func (g generator) structSchema(t reflect.Type) (bson.D, error) {
+	if g.visiting[t] {
+		// a recursive type can't be expanded further, so only the type of the field is constrained.
+		return bson.D{{"bsonType", "object"}}, nil
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	var required bson.A
+	var properties bson.D
+	if err := g.addFields(t, &properties, &required); err != nil {
+		return nil, err
+	}
+
+	schema := bson.D{{"bsonType", "object"}}
+	if len(required) > 0 {
+		schema = append(schema, bson.E{Key: "required", Value: required})
+	}
+	if len(properties) > 0 {
+		schema = append(schema, bson.E{Key: "properties", Value: properties})
+	}
+	return schema, nil
+}
+
+func (g generator) addFields(t reflect.Type, properties *bson.D, required *bson.A) error {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" { // unexported fields are not encoded
+			continue
+		}
+
+		tags, err := bsoncodec.DefaultStructTagParser.ParseStructTags(sf)
+		if err != nil {
+			return err
+		}
+		if tags.Skip {
+			continue
+		}
+
+		if tags.Inline {
+			ft := sf.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			switch ft.Kind() {
+			case reflect.Struct:
+				if err = g.addFields(ft, properties, required); err != nil {
+					return err
+				}
+				continue
+			case reflect.Map:
+				// an inline map holds arbitrary extra fields which can't be described by properties.
+				continue
+			}
+			return fmt.Errorf("inline field %s of %s must be a struct, struct pointer, or map", sf.Name, t)
+		}
+
+		schema, err := g.typeSchema(sf.Type, tags.MinSize)
+		if err != nil {
+			return fmt.Errorf("field %s of %s: %v", sf.Name, t, err)
+		}
+		*properties = append(*properties, bson.E{Key: tags.Name, Value: schema})
+		if !tags.OmitEmpty {
+			*required = append(*required, tags.Name)
+		}
+	}
+	return nil
+}
+
+func (g generator) typeSchema(t reflect.Type, minSize bool) (bson.D, error) {
+	if t.Kind() == reflect.Ptr {
+		schema, err := g.typeSchema(t.Elem(), minSize)
+		if err != nil {
+			return nil, err
+		}
+		return nullable(schema), nil
+	}
+
+	if bsonType, ok := bsonTypes[t]; ok {
+		schema := bson.D{{"bsonType", bsonType}}
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+			// a nil []byte, bson.Raw, primitive.D, primitive.M, or primitive.A is encoded as null.
+			return nullable(schema), nil
+		}
+		return schema, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return bson.D{{"bsonType", "bool"}}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return bson.D{{"bsonType", "int"}}, nil
+	case reflect.Int:
+		// an int is encoded as an int32 when it fits and an int64 otherwise.
+		return bson.D{{"bsonType", bson.A{"int", "long"}}}, nil
+	case reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		if minSize {
+			return bson.D{{"bsonType", bson.A{"int", "long"}}}, nil
+		}
+		return bson.D{{"bsonType", "long"}}, nil
+	case reflect.Float32, reflect.Float64:
+		return bson.D{{"bsonType", "double"}}, nil
+	case reflect.String:
+		return bson.D{{"bsonType", "string"}}, nil
+	case reflect.Struct:
+		return g.structSchema(t)
+	case reflect.Interface:
+		// any BSON type may be stored.
+		return bson.D{}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := g.typeSchema(t.Elem(), minSize)
+		if err != nil {
+			return nil, err
+		}
+		schema := bson.D{{"bsonType", "array"}}
+		if len(items) > 0 {
+			schema = append(schema, bson.E{Key: "items", Value: items})
+		}
+		if t.Kind() == reflect.Slice {
+			// a nil slice is encoded as null.
+			return nullable(schema), nil
+		}
+		return schema, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key type must be a string, got %s", t.Key())
+		}
+		values, err := g.typeSchema(t.Elem(), minSize)
+		if err != nil {
+			return nil, err
+		}
+		schema := bson.D{{"bsonType", "object"}}
+		if len(values) > 0 {
+			schema = append(schema, bson.E{Key: "additionalProperties", Value: values})
+		}
+		// a nil map is encoded as null.
+		return nullable(schema), nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// nullable allows null in addition to the bsonType of schema.
+func nullable(schema bson.D) bson.D {
+	if len(schema) == 0 || schema[0].Key != "bsonType" {
+		return schema
+	}
+
+	types := bson.A{}
+	switch bt := schema[0].Value.(type) {
+	case string:
+		types = append(types, bt)
+	case bson.A:
+		types = append(types, bt...)
+	}
+	for _, t := range types {
+		if t == "null" {
+			return schema
+		}
+	}
+
+	return append(bson.D{{"bsonType", append(types, "null")}}, schema[1:]...)
+}
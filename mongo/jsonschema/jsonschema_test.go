@@ -0,0 +1,121 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package jsonschema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type address struct {
+	Street string `bson:"street"`
+	Zip    string `bson:"zip,omitempty"`
+}
+
+type Audit struct {
+	Created time.Time `bson:"created"`
+}
+
+type person struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	Name     string
+	Age      int32          `bson:"age,omitempty"`
+	Score    int64          `bson:"score,minsize"`
+	Email    *string        `bson:"email,omitempty"`
+	Tags     []string       `bson:"tags"`
+	Address  address        `bson:"address"`
+	Extra    map[string]int `bson:"extra,omitempty"`
+	Ignored  string         `bson:"-"`
+	Anything interface{}    `bson:"anything,omitempty"`
+	Manager  *person        `bson:"manager,omitempty"`
+	Audit    `bson:",inline"`
+	private  string
+}
+
+func TestFromStruct(t *testing.T) {
+	schema, err := FromStruct(&person{})
+	require.NoError(t, err)
+
+	expected := bson.D{
+		{"bsonType", "object"},
+		{"required", bson.A{"_id", "name", "score", "tags", "address", "created"}},
+		{"properties", bson.D{
+			{"_id", bson.D{{"bsonType", "objectId"}}},
+			{"name", bson.D{{"bsonType", "string"}}},
+			{"age", bson.D{{"bsonType", "int"}}},
+			{"score", bson.D{{"bsonType", bson.A{"int", "long"}}}},
+			{"email", bson.D{{"bsonType", bson.A{"string", "null"}}}},
+			{"tags", bson.D{{"bsonType", bson.A{"array", "null"}}, {"items", bson.D{{"bsonType", "string"}}}}},
+			{"address", bson.D{
+				{"bsonType", "object"},
+				{"required", bson.A{"street"}},
+				{"properties", bson.D{
+					{"street", bson.D{{"bsonType", "string"}}},
+					{"zip", bson.D{{"bsonType", "string"}}},
+				}},
+			}},
+			{"extra", bson.D{{"bsonType", bson.A{"object", "null"}}, {"additionalProperties", bson.D{{"bsonType", bson.A{"int", "long"}}}}}},
+			{"anything", bson.D{}},
+			{"manager", bson.D{{"bsonType", bson.A{"object", "null"}}}},
+			{"created", bson.D{{"bsonType", "date"}}},
+		}},
+	}
+	require.Equal(t, expected, schema)
+}
+
+func TestFromStructNullableTypes(t *testing.T) {
+	schema, err := FromStruct(struct {
+		Bytes  []byte           `bson:"bytes"`
+		Raw    bson.Raw         `bson:"raw"`
+		D      primitive.D      `bson:"d"`
+		M      primitive.M      `bson:"m"`
+		A      primitive.A      `bson:"a"`
+		Binary primitive.Binary `bson:"binary"`
+		Array  [2]byte          `bson:"array"`
+	}{})
+	require.NoError(t, err)
+
+	expected := bson.D{
+		{"bytes", bson.D{{"bsonType", bson.A{"binData", "null"}}}},
+		{"raw", bson.D{{"bsonType", bson.A{"object", "null"}}}},
+		{"d", bson.D{{"bsonType", bson.A{"object", "null"}}}},
+		{"m", bson.D{{"bsonType", bson.A{"object", "null"}}}},
+		{"a", bson.D{{"bsonType", bson.A{"array", "null"}}}},
+		{"binary", bson.D{{"bsonType", "binData"}}},
+		{"array", bson.D{{"bsonType", "array"}, {"items", bson.D{{"bsonType", "int"}}}}},
+	}
+	require.Equal(t, expected, schema[2].Value)
+}
+
+func TestValidator(t *testing.T) {
+	validator, err := Validator(address{})
+	require.NoError(t, err)
+	require.Equal(t, "$jsonSchema", validator[0].Key)
+
+	// the validator must be marshalable as a command option
+	_, err = bson.Marshal(bson.D{{"collMod", "people"}, {"validator", validator}})
+	require.NoError(t, err)
+}
+
+func TestFromStructErrors(t *testing.T) {
+	_, err := FromStruct(42)
+	require.Error(t, err)
+
+	_, err = FromStruct(struct {
+		M map[int]string
+	}{})
+	require.Error(t, err)
+
+	_, err = FromStruct(struct {
+		C chan int
+	}{})
+	require.Error(t, err)
+}
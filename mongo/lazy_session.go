@@ -0,0 +1,213 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+)
+
+// WithLazySession returns a copy of ctx that carries a session of the client. The session is
+// started by the first operation that is run with the returned context, or a context derived from
+// it, and is used by all the operations run with them afterwards, so that they are causally
+// consistent. The session is ended once ctx is done and the operations, cursors, and change
+// streams using it have completed or been closed. This suits sessions that last for the lifetime of
+// a request, such as the request context of an HTTP handler, which is cancelled when the handler
+// returns.
+//
+// If the session cannot be started, operations run with implicit sessions as if the context did
+// not carry a session. If ctx is never done, such as context.Background, the session is never ended
+// and StartSession should be used instead. Like other sessions, the session must not be used by
+// operations that run concurrently.
+func (c *Client) WithLazySession(ctx context.Context, opts ...*options.SessionOptions) context.Context {
+	ls := &lazySession{client: c, ctx: ctx, opts: options.MergeSessionOptions(opts...)}
+	return context.WithValue(ctx, sessionKey{}, ls)
+}
+
+// lazySession is a session that is started when it is first used and ended once ctx is done and
+// it has no users left.
+type lazySession struct {
+	client *Client
+	ctx    context.Context
+	opts   *options.SessionOptions
+
+	mu    sync.Mutex
+	sess  *sessionImpl
+	users int  // the number of operations, cursors, and change streams holding the session
+	done  bool // true once ctx is done
+	ended bool
+}
+
+// acquire returns the session, starting it if it has not been used yet, and adds a user to it. It
+// returns nil if the session cannot be started or ctx is done. The user must call release once it
+// no longer uses the session.
+func (ls *lazySession) acquire() *session.Client {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.done || ls.ended || ls.ctx.Err() != nil {
+		return nil
+	}
+	if ls.sess == nil {
+		sess, err := ls.client.StartSession(ls.opts)
+		if err != nil {
+			return nil
+		}
+		ls.sess = sess.(*sessionImpl)
+		if done := ls.ctx.Done(); done != nil {
+			go func() {
+				<-done
+				ls.finish()
+			}()
+		}
+	}
+	ls.users++
+	return ls.sess.Client
+}
+
+// retain adds a user to the session. It must only be called by a user holding the session, so it
+// succeeds even once ctx is done.
+func (ls *lazySession) retain() {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.users++
+}
+
+// release removes a user from the session, ending it if it was the last one and ctx is done.
+func (ls *lazySession) release() {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ls.users--
+	if ls.users == 0 && ls.done {
+		ls.end()
+	}
+}
+
+// finish records that ctx is done, ending the session if it has no users.
+func (ls *lazySession) finish() {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ls.done = true
+	if ls.users == 0 {
+		ls.end()
+	}
+}
+
+// end ends the session. It must be called with mu held.
+func (ls *lazySession) end() {
+	if ls.ended {
+		return
+	}
+	ls.ended = true
+	if ls.sess != nil {
+		ls.sess.EndSession(context.Background())
+	}
+}
+
+// lazySessionUse is the use of a lazy session by a single operation or change stream. The session is
+// only acquired when the operation asks for it, so operations that do not use sessions, such as the
+// getMores of a cursor, do not start it.
+type lazySessionUse struct {
+	ls *lazySession
+
+	mu       sync.Mutex
+	sess     *session.Client
+	acquired bool
+	released bool
+}
+
+// useLazySession returns a copy of ctx for a single operation and a function to call once the
+// operation completes. If ctx carries a lazy session that is not used by an operation yet, the
+// operation holds the session from when it first asks for it until the function is called.
+// Otherwise ctx is returned as is.
+func useLazySession(ctx context.Context) (context.Context, func()) {
+	ls, ok := ctx.Value(sessionKey{}).(*lazySession)
+	if !ok {
+		return ctx, func() {}
+	}
+	u := &lazySessionUse{ls: ls}
+	return context.WithValue(ctx, sessionKey{}, u), u.release
+}
+
+// get returns the session, acquiring it on the first call.
+func (u *lazySessionUse) get() *session.Client {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if !u.acquired && !u.released {
+		u.acquired = true
+		u.sess = u.ls.acquire()
+	}
+	return u.sess
+}
+
+// release releases the session if it was acquired.
+func (u *lazySessionUse) release() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.released {
+		return
+	}
+	u.released = true
+	if u.sess != nil {
+		u.ls.release()
+	}
+}
+
+// retainSession returns bc, wrapped so that it holds the lazy session used by the operation that
+// ran with ctx, if any, until bc is exhausted or closed. The session is only retained if the server
+// cursor is open, because no getMores are run otherwise.
+func retainSession(ctx context.Context, bc batchCursor) batchCursor {
+	u, ok := ctx.Value(sessionKey{}).(*lazySessionUse)
+	if !ok || bc.ID() == 0 {
+		return bc
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.sess == nil || u.released {
+		return bc
+	}
+	u.ls.retain()
+	return &sessionBatchCursor{batchCursor: bc, ls: u.ls}
+}
+
+// sessionBatchCursor is a batch cursor that holds a lazy session until it is exhausted or closed.
+type sessionBatchCursor struct {
+	batchCursor
+	ls   *lazySession
+	once sync.Once
+}
+
+// Next returns true if there is a batch available. The session is released once the server
+// cursor is exhausted.
+func (bc *sessionBatchCursor) Next(ctx context.Context) bool {
+	if bc.batchCursor.Next(ctx) {
+		return true
+	}
+	if bc.ID() == 0 {
+		bc.release()
+	}
+	return false
+}
+
+// Close closes the cursor and releases the session.
+func (bc *sessionBatchCursor) Close(ctx context.Context) error {
+	err := bc.batchCursor.Close(ctx)
+	bc.release()
+	return err
+}
+
+func (bc *sessionBatchCursor) release() {
+	bc.once.Do(bc.ls.release)
+}
@@ -0,0 +1,151 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestClient_WithLazySession(t *testing.T) {
+	client, err := NewClient(options.Client().ApplyURI("mongodb://localhost:27017"))
+	require.NoError(t, err)
+	require.NoError(t, client.Connect(context.Background()))
+	defer func() {
+		// There is no server to end the sessions on.
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_ = client.Disconnect(ctx)
+	}()
+
+	waitEnded := func(ls *lazySession) bool {
+		ended := func() bool {
+			ls.mu.Lock()
+			defer ls.mu.Unlock()
+			return ls.ended
+		}
+		for deadline := time.Now().Add(time.Second); !ended() && time.Now().Before(deadline); {
+			time.Sleep(time.Millisecond)
+		}
+		return ended()
+	}
+
+	t.Run("started on first use and ended when done", func(t *testing.T) {
+		reqCtx, cancel := context.WithCancel(context.Background())
+		ctx := client.WithLazySession(reqCtx, options.Session().SetCausalConsistency(true))
+		ls := ctx.Value(sessionKey{}).(*lazySession)
+
+		opCtx, opCancel := operationContext(ctx, nil)
+		require.Nil(t, ls.sess, "session should not be started before it is used")
+		sess := sessionFromContext(opCtx)
+		require.NotNil(t, sess)
+		require.True(t, sess.Consistent)
+		require.NoError(t, client.validSession(sess))
+		opCancel()
+
+		opCtx, opCancel = operationContext(ctx, nil)
+		require.True(t, sessionFromContext(opCtx) == sess, "operations should use the same session")
+		opCancel()
+
+		cancel()
+		require.True(t, waitEnded(ls))
+		require.True(t, sess.Terminated, "session should be ended when the context is done")
+		opCtx, opCancel = operationContext(ctx, nil)
+		defer opCancel()
+		require.Nil(t, sessionFromContext(opCtx))
+	})
+	t.Run("not ended while an operation uses it", func(t *testing.T) {
+		reqCtx, cancel := context.WithCancel(context.Background())
+		ctx := client.WithLazySession(reqCtx)
+		ls := ctx.Value(sessionKey{}).(*lazySession)
+
+		opCtx, opCancel := operationContext(ctx, nil)
+		sess := sessionFromContext(opCtx)
+		require.NotNil(t, sess)
+
+		cancel()
+		require.False(t, waitEnded(ls), "session should not be ended while an operation uses it")
+		require.False(t, sess.Terminated)
+
+		opCancel()
+		require.True(t, waitEnded(ls))
+		require.True(t, sess.Terminated)
+	})
+	t.Run("not ended while a cursor uses it", func(t *testing.T) {
+		reqCtx, cancel := context.WithCancel(context.Background())
+		ctx := client.WithLazySession(reqCtx)
+		ls := ctx.Value(sessionKey{}).(*lazySession)
+
+		opCtx, opCancel := operationContext(ctx, nil)
+		sess := sessionFromContext(opCtx)
+		require.NotNil(t, sess)
+		tbc := newTestBatchCursor(2, 5)
+		cursor, err := newCursor(retainSession(opCtx, tbc), nil)
+		require.NoError(t, err)
+		opCancel()
+
+		cancel()
+		require.False(t, waitEnded(ls), "session should not be ended while a cursor uses it")
+		require.False(t, sess.Terminated)
+
+		for cursor.Next(context.Background()) {
+		}
+		require.NoError(t, cursor.Err())
+		require.True(t, waitEnded(ls), "session should be ended once the cursor is exhausted")
+		require.True(t, sess.Terminated)
+
+		require.NoError(t, cursor.Close(context.Background()))
+		ls.mu.Lock()
+		defer ls.mu.Unlock()
+		require.Equal(t, 0, ls.users, "closing an exhausted cursor should not release the session again")
+	})
+	t.Run("cursor releases it when closed", func(t *testing.T) {
+		reqCtx, cancel := context.WithCancel(context.Background())
+		ctx := client.WithLazySession(reqCtx)
+		ls := ctx.Value(sessionKey{}).(*lazySession)
+
+		opCtx, opCancel := operationContext(ctx, nil)
+		require.NotNil(t, sessionFromContext(opCtx))
+		cursor, err := newCursor(retainSession(opCtx, newTestBatchCursor(2, 5)), nil)
+		require.NoError(t, err)
+		opCancel()
+
+		cancel()
+		require.False(t, waitEnded(ls))
+		require.NoError(t, cursor.Close(context.Background()))
+		require.True(t, waitEnded(ls))
+	})
+	t.Run("not started by operations that do not use it", func(t *testing.T) {
+		reqCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ctx := client.WithLazySession(reqCtx)
+
+		_, opCancel := operationContext(ctx, nil)
+		opCancel()
+		require.Nil(t, ctx.Value(sessionKey{}).(*lazySession).sess)
+	})
+	t.Run("not started when done", func(t *testing.T) {
+		reqCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		ctx := client.WithLazySession(reqCtx)
+		opCtx, opCancel := operationContext(ctx, nil)
+		defer opCancel()
+		require.Nil(t, sessionFromContext(opCtx))
+		require.Nil(t, ctx.Value(sessionKey{}).(*lazySession).sess)
+	})
+	t.Run("disconnected client", func(t *testing.T) {
+		other, err := NewClient()
+		require.NoError(t, err)
+		opCtx, opCancel := operationContext(other.WithLazySession(context.Background()), nil)
+		defer opCancel()
+		require.Nil(t, sessionFromContext(opCtx))
+	})
+}
@@ -8,8 +8,10 @@ package mongo // import "go.mongodb.org/mongo-driver/mongo"
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"reflect"
 	"strings"
@@ -170,6 +172,56 @@ func ensureID(d bsonx.Doc) (bsonx.Doc, interface{}) {
 	return d, id
 }
 
+// maxBSONObjectSize is the largest document accepted by the server.
+const maxBSONObjectSize = 16 * 1024 * 1024
+
+// objectIDElementSize is the size of an _id element holding an ObjectID: the type byte, the
+// null-terminated key and the 12 bytes of the ObjectID.
+const objectIDElementSize = 1 + 4 + 12
+
+// readDocumentWithID reads a BSON document from r and returns it along with its _id. An ObjectID
+// _id is added as the first element of the document if it does not have one.
+func readDocumentWithID(registry *bsoncodec.Registry, r io.Reader) (bson.Raw, interface{}, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, nil, err
+	}
+	length := int32(binary.LittleEndian.Uint32(header[:]))
+	if length < 5 || length > maxBSONObjectSize {
+		return nil, nil, fmt.Errorf("invalid document length %d", length)
+	}
+
+	// The document is read after room for an _id element, so one can be added without copying it.
+	buf := make([]byte, objectIDElementSize+int(length))
+	doc := buf[objectIDElementSize:]
+	copy(doc, header[:])
+	if _, err := io.ReadFull(r, doc[4:]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, nil, err
+	}
+	if err := bson.Raw(doc).Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	if idVal, err := bson.Raw(doc).LookupErr("_id"); err == nil {
+		var id interface{}
+		if err = idVal.UnmarshalWithRegistry(registry, &id); err != nil {
+			return nil, nil, err
+		}
+		return doc, id, nil
+	}
+
+	// The _id element overwrites the length of the document read, which is rewritten before it.
+	oid := primitive.NewObjectID()
+	binary.LittleEndian.PutUint32(buf, uint32(len(buf)))
+	buf[4] = byte(bsontype.ObjectID)
+	copy(buf[5:], "_id\x00")
+	copy(buf[9:], oid[:])
+	return buf, oid, nil
+}
+
 func ensureDollarKey(doc bsonx.Doc) error {
 	if len(doc) == 0 {
 		return errors.New("update document must have at least one element")
@@ -180,6 +232,59 @@ func ensureDollarKey(doc bsonx.Doc) error {
 	return nil
 }
 
+// transformUpdateValue converts update, which is either an update document of update operators or
+// an aggregation pipeline of update stages, such as a bson.A or mongo.Pipeline, into the value sent
+// as the update.
+func transformUpdateValue(registry *bsoncodec.Registry, update interface{}) (bsonx.Val, error) {
+	if update == nil {
+		return bsonx.Val{}, ErrNilDocument
+	}
+
+	if !isUpdatePipeline(update) {
+		u, err := transformDocument(registry, update)
+		if err != nil {
+			return bsonx.Val{}, err
+		}
+		if err = ensureDollarKey(u); err != nil {
+			return bsonx.Val{}, err
+		}
+		return bsonx.Document(u), nil
+	}
+
+	pipeline, err := transformAggregatePipeline(registry, update)
+	if err != nil {
+		return bsonx.Val{}, err
+	}
+	if len(pipeline) == 0 {
+		return bsonx.Val{}, errors.New("update pipeline must have at least one stage")
+	}
+	for _, stage := range pipeline {
+		if err = ensureDollarKey(stage.Document()); err != nil {
+			return bsonx.Val{}, err
+		}
+	}
+	return bsonx.Array(pipeline), nil
+}
+
+// isUpdatePipeline returns true if update is an array of pipeline stages rather than an update
+// document. Slices of elements, such as bson.D, and byte slices are documents.
+func isUpdatePipeline(update interface{}) bool {
+	if vm, ok := update.(bsoncodec.ValueMarshaler); ok {
+		btype, _, err := vm.MarshalBSONValue()
+		return err == nil && btype == bsontype.Array
+	}
+
+	val := reflect.ValueOf(update)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return false
+	}
+	switch elem := val.Type().Elem(); {
+	case elem.Kind() == reflect.Uint8, elem == reflect.TypeOf(primitive.E{}), elem == reflect.TypeOf(bsonx.Elem{}):
+		return false
+	}
+	return true
+}
+
 func transformAggregatePipeline(registry *bsoncodec.Registry, pipeline interface{}) (bsonx.Arr, error) {
 	pipelineArr := bsonx.Arr{}
 	switch t := pipeline.(type) {
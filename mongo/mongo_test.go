@@ -7,8 +7,10 @@
 package mongo
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -151,6 +153,51 @@ func TestTransformAndEnsureID(t *testing.T) {
 	})
 }
 
+func TestTransformUpdateValue(t *testing.T) {
+	set := bsonx.Doc{{"$set", bsonx.Document(bsonx.Doc{{"x", bsonx.Int32(1)}})}}
+	testCases := []struct {
+		name     string
+		update   interface{}
+		expected bsonx.Val
+		err      bool
+	}{
+		{"bson.D", bson.D{{"$set", bson.D{{"x", int32(1)}}}}, bsonx.Document(set), false},
+		{"bson.M", bson.M{"$set": bson.M{"x": int32(1)}}, bsonx.Document(set), false},
+		{"bsonx.Doc", set, bsonx.Document(set), false},
+		{"bson.Raw", bson.Raw(mustMarshal(t, set)), bsonx.Document(set), false},
+		{"Pipeline", Pipeline{{{"$set", bson.D{{"x", int32(1)}}}}}, bsonx.Array(bsonx.Arr{bsonx.Document(set)}), false},
+		{"bson.A", bson.A{bson.D{{"$set", bson.D{{"x", int32(1)}}}}}, bsonx.Array(bsonx.Arr{bsonx.Document(set)}), false},
+		{"bsonx.Arr", bsonx.Arr{bsonx.Document(set)}, bsonx.Array(bsonx.Arr{bsonx.Document(set)}), false},
+		{"replacement document", bson.D{{"x", int32(1)}}, bsonx.Val{}, true},
+		{"empty document", bson.D{}, bsonx.Val{}, true},
+		{"empty pipeline", bson.A{}, bsonx.Val{}, true},
+		{"pipeline with a replacement document", bson.A{bson.D{{"x", int32(1)}}}, bsonx.Val{}, true},
+		{"nil", nil, bsonx.Val{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := transformUpdateValue(nil, tc.update)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			noerr(t, err)
+			if !got.Equal(tc.expected) {
+				t.Errorf("update values do not match. got %v; want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, doc bsonx.Doc) []byte {
+	b, err := doc.MarshalBSON()
+	noerr(t, err)
+	return b
+}
+
 func TestTransformAggregatePipeline(t *testing.T) {
 	index, arr := bsoncore.AppendArrayStart(nil)
 	dindex, arr := bsoncore.AppendDocumentElementStart(arr, "0")
@@ -351,3 +398,50 @@ type bvMarsh struct {
 func (b bvMarsh) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	return b.t, b.data, b.err
 }
+
+func TestReadDocumentWithID(t *testing.T) {
+	t.Run("has id", func(t *testing.T) {
+		doc, err := bson.Marshal(bson.D{{"a", 1}, {"_id", "foo"}})
+		noerr(t, err)
+		got, id, err := readDocumentWithID(bson.DefaultRegistry, bytes.NewReader(doc))
+		noerr(t, err)
+		if !bytes.Equal(got, doc) {
+			t.Errorf("documents do not match. got %v; want %v", got, bson.Raw(doc))
+		}
+		if id != "foo" {
+			t.Errorf("ids do not match. got %v; want %v", id, "foo")
+		}
+	})
+	t.Run("adds id", func(t *testing.T) {
+		doc, err := bson.Marshal(bson.D{{"a", 1}, {"b", "c"}})
+		noerr(t, err)
+		got, id, err := readDocumentWithID(bson.DefaultRegistry, bytes.NewReader(doc))
+		noerr(t, err)
+		oid, ok := id.(primitive.ObjectID)
+		if !ok {
+			t.Fatalf("expected an ObjectID, got %T", id)
+		}
+		want, err := bson.Marshal(bson.D{{"_id", oid}, {"a", 1}, {"b", "c"}})
+		noerr(t, err)
+		if !bytes.Equal(got, want) {
+			t.Errorf("documents do not match. got %v; want %v", got, bson.Raw(want))
+		}
+	})
+	t.Run("errors", func(t *testing.T) {
+		doc, err := bson.Marshal(bson.D{{"a", 1}})
+		noerr(t, err)
+		invalid := append([]byte{}, doc...)
+		invalid[4] = 0x7f
+
+		for name, r := range map[string]io.Reader{
+			"empty":     bytes.NewReader(nil),
+			"truncated": bytes.NewReader(doc[:len(doc)-1]),
+			"length":    bytes.NewReader([]byte{0xff, 0xff, 0xff, 0x7f}),
+			"invalid":   bytes.NewReader(invalid),
+		} {
+			if _, _, err := readDocumentWithID(bson.DefaultRegistry, r); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
+)
+
+const (
+	defaultMongocryptdURI  = "mongodb://localhost:27020"
+	defaultMongocryptdPath = "mongocryptd"
+	defaultIdleShutdown    = "--idleShutdownTimeoutSecs=60"
+	mongocryptdSelectTime  = time.Second
+)
+
+// mcryptClient sends commands to mongocryptd to mark the fields that must be encrypted, spawning
+// mongocryptd if it is not running.
+type mcryptClient struct {
+	bypassSpawn bool
+	client      *Client
+	path        string
+	spawnArgs   []string
+}
+
+func newMcryptClient(opts *options.AutoEncryptionOptions) (*mcryptClient, error) {
+	mc := &mcryptClient{
+		path: defaultMongocryptdPath,
+	}
+	uri := defaultMongocryptdURI
+	var spawnArgs []string
+
+	if val, ok := opts.ExtraOptions["mongocryptdURI"].(string); ok {
+		uri = val
+	}
+	if val, ok := opts.ExtraOptions["mongocryptdBypassSpawn"].(bool); ok {
+		mc.bypassSpawn = val
+	}
+	if val, ok := opts.ExtraOptions["mongocryptdSpawnPath"].(string); ok {
+		mc.path = val
+	}
+	if val, ok := opts.ExtraOptions["mongocryptdSpawnArgs"].([]string); ok {
+		spawnArgs = val
+	}
+	mc.spawnArgs = createSpawnArgs(spawnArgs)
+
+	if !mc.bypassSpawn {
+		if err := mc.spawnProcess(); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := NewClient(options.Client().ApplyURI(uri).
+		SetServerSelectionTimeout(mongocryptdSelectTime))
+	if err != nil {
+		return nil, err
+	}
+	mc.client = client
+	return mc, nil
+}
+
+// createSpawnArgs returns the arguments mongocryptd is spawned with, adding the default idle
+// shutdown timeout if args does not set one.
+func createSpawnArgs(args []string) []string {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--idleShutdownTimeoutSecs") {
+			return args
+		}
+	}
+	return append(append([]string(nil), args...), defaultIdleShutdown)
+}
+
+// markCommand sends cmd to mongocryptd. If mongocryptd cannot be reached it is spawned again and
+// the command is retried once.
+func (mc *mcryptClient) markCommand(ctx context.Context, db string, cmd bsoncore.Document) (bsoncore.Document, error) {
+	runCmdOpts := options.RunCmd().SetReadPreference(readpref.Primary())
+	res, err := mc.client.Database(db).RunCommand(ctx, bson.Raw(cmd), runCmdOpts).DecodeBytes()
+	if err == nil {
+		return bsoncore.Document(res), nil
+	}
+
+	if mc.bypassSpawn || !strings.Contains(err.Error(), topology.ErrServerSelectionTimeout.Error()) {
+		return nil, MongocryptdError{Wrapped: err}
+	}
+	if err = mc.spawnProcess(); err != nil {
+		return nil, err
+	}
+
+	res, err = mc.client.Database(db).RunCommand(ctx, bson.Raw(cmd), runCmdOpts).DecodeBytes()
+	if err != nil {
+		return nil, MongocryptdError{Wrapped: err}
+	}
+	return bsoncore.Document(res), nil
+}
+
+// spawnProcess starts mongocryptd without waiting for it to be ready. Failed commands are retried
+// by the caller instead.
+func (mc *mcryptClient) spawnProcess() error {
+	cmd := exec.Command(mc.path, mc.spawnArgs...)
+	if err := cmd.Start(); err != nil {
+		return MongocryptdError{Wrapped: err}
+	}
+	// Reap the process when it exits so it does not linger as a zombie.
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"fmt"
+	"path"
+
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
+)
+
+// newNamespaceFilter creates a filter that allows the namespaces matching one of the allowed glob
+// patterns, or all namespaces if there are none, unless they match one of the denied patterns.
+func newNamespaceFilter(allowed, denied []string) (topology.NamespaceFilter, error) {
+	for _, pattern := range append(append([]string{}, allowed...), denied...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid namespace pattern %q: %v", pattern, err)
+		}
+	}
+
+	return func(database, collection string) bool {
+		ns := database + "." + collection
+		if matchesNamespace(denied, ns) {
+			return false
+		}
+		return len(allowed) == 0 || matchesNamespace(allowed, ns)
+	}, nil
+}
+
+func matchesNamespace(patterns []string, ns string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, ns); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/network/command"
+)
+
+func TestNamespaceFilter(t *testing.T) {
+	filter, err := newNamespaceFilter([]string{"tenant1.*", "shared.config"}, []string{"*.secrets"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		db, coll string
+		allowed  bool
+	}{
+		{"tenant1", "orders", true},
+		{"tenant1", "*", true},
+		{"tenant1", "secrets", false},
+		{"tenant2", "orders", false},
+		{"shared", "config", true},
+		{"shared", "*", false},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.allowed, filter(tc.db, tc.coll), "%s.%s", tc.db, tc.coll)
+	}
+
+	t.Run("deny only", func(t *testing.T) {
+		filter, err := newNamespaceFilter(nil, []string{"tenant2.*"})
+		require.NoError(t, err)
+		require.True(t, filter("tenant1", "orders"))
+		require.False(t, filter("tenant2", "orders"))
+	})
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := NewClient(options.Client().SetAllowedNamespaces([]string{"tenant1.["}))
+		require.Error(t, err)
+	})
+	t.Run("error", func(t *testing.T) {
+		err := replaceErrors(command.NamespaceDeniedError{Namespace: "tenant2.orders", Command: "find"})
+		require.Equal(t, NamespaceDeniedError{Namespace: "tenant2.orders", Command: "find"}, err)
+	})
+}
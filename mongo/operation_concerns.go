@@ -0,0 +1,37 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+)
+
+// OperationConcerns are the read concern, write concern and read preference an operation was
+// executed with, after those of the client, database and collection were overridden by the options
+// of the operation or by the transaction it was part of. A nil field means that none was specified,
+// so the server default was used, or that it does not apply to the operation, such as the read
+// preference of a write outside a transaction.
+type OperationConcerns struct {
+	ReadConcern    *readconcern.ReadConcern
+	WriteConcern   *writeconcern.WriteConcern
+	ReadPreference *readpref.ReadPref
+}
+
+// operationConcerns returns the concerns of an operation executed using sess with the read concern
+// rc, write concern wc and read preference rp. Operations in a transaction use the concerns of the
+// transaction instead.
+func operationConcerns(sess *session.Client, rc *readconcern.ReadConcern, wc *writeconcern.WriteConcern,
+	rp *readpref.ReadPref) OperationConcerns {
+
+	if sess.TransactionRunning() {
+		return OperationConcerns{ReadConcern: sess.CurrentRc, WriteConcern: sess.CurrentWc, ReadPreference: sess.CurrentRp}
+	}
+	return OperationConcerns{ReadConcern: rc, WriteConcern: wc, ReadPreference: rp}
+}
@@ -0,0 +1,45 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
+)
+
+func TestOperationConcerns(t *testing.T) {
+	rc := readconcern.Local()
+	wc := writeconcern.New(writeconcern.W(1))
+	rp := readpref.Nearest()
+
+	t.Run("no session", func(t *testing.T) {
+		concerns := operationConcerns(nil, rc, wc, rp)
+		require.Equal(t, OperationConcerns{ReadConcern: rc, WriteConcern: wc, ReadPreference: rp}, concerns)
+	})
+	t.Run("transaction", func(t *testing.T) {
+		id, _ := uuid.New()
+		sess, err := session.NewClientSession(&session.Pool{}, id, session.Explicit)
+		require.NoError(t, err)
+		txnOpts := &session.TransactionOptions{
+			ReadConcern:    readconcern.Snapshot(),
+			WriteConcern:   writeconcern.New(writeconcern.WMajority()),
+			ReadPreference: readpref.Primary(),
+		}
+		require.NoError(t, sess.StartTransaction(txnOpts))
+
+		concerns := operationConcerns(sess, rc, wc, nil)
+		require.Equal(t, txnOpts.ReadConcern, concerns.ReadConcern)
+		require.Equal(t, txnOpts.WriteConcern, concerns.WriteConcern)
+		require.Equal(t, txnOpts.ReadPreference, concerns.ReadPreference)
+	})
+}
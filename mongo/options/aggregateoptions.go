@@ -6,18 +6,26 @@
 
 package options
 
-import "time"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+)
 
 // AggregateOptions represents all possible options to the Aggregate() function.
 type AggregateOptions struct {
-	AllowDiskUse             *bool          // Enables writing to temporary files. When set to true, aggregation stages can write data to the _tmp subdirectory in the dbPath directory
-	BatchSize                *int32         // The number of documents to return per batch
-	BypassDocumentValidation *bool          // If true, allows the write to opt-out of document level validation. This only applies when the $out stage is specified
-	Collation                *Collation     // Specifies a collation
-	MaxTime                  *time.Duration // The maximum amount of time to allow the query to run
-	MaxAwaitTime             *time.Duration // The maximum amount of time for the server to wait on new documents to satisfy a tailable cursor query
-	Comment                  *string        // Enables users to specify an arbitrary string to help trace the operation through the database profiler, currentOp and logs.
-	Hint                     interface{}    // The index to use for the aggregation. The hint does not apply to $lookup and $graphLookup stages
+	AllowDiskUse             *bool                    // Enables writing to temporary files. When set to true, aggregation stages can write data to the _tmp subdirectory in the dbPath directory
+	BatchSize                *int32                   // The number of documents to return per batch
+	BypassDocumentValidation *bool                    // If true, allows the write to opt-out of document level validation. This only applies when the $out stage is specified
+	Collation                *Collation               // Specifies a collation
+	MaxTime                  *time.Duration           // The maximum amount of time to allow the query to run
+	MaxAwaitTime             *time.Duration           // The maximum amount of time for the server to wait on new documents to satisfy a tailable cursor query
+	Comment                  interface{}              // Specifies a comment to help trace the operation through the database profiler, currentOp and logs.
+	GetMoreOptions           *GetMoreOptions          // Specifies which options are sent again on getMore commands.
+	Hint                     interface{}              // The index to use for the aggregation. The hint does not apply to $lookup and $graphLookup stages
+	Let                      interface{}              // Specifies a document of parameter names and values that can be accessed in the command with $$name.
+	ReadConcern              *readconcern.ReadConcern // The read concern for the operation. Overrides the read concern of the collection.
+	TargetBatchBytes         *int32                   // The size in bytes that getMore replies should stay near
 }
 
 // Aggregate returns a pointer to a new AggregateOptions
@@ -68,10 +76,17 @@ func (ao *AggregateOptions) SetMaxAwaitTime(d time.Duration) *AggregateOptions {
 	return ao
 }
 
-// SetComment enables users to specify an arbitrary string to help trace the
-// operation through the database profiler, currentOp and logs.
-func (ao *AggregateOptions) SetComment(s string) *AggregateOptions {
-	ao.Comment = &s
+// SetComment specifies a comment to help trace the operation through the database profiler,
+// currentOp and logs. Comments other than strings are valid for server versions >= 4.4.
+func (ao *AggregateOptions) SetComment(comment interface{}) *AggregateOptions {
+	ao.Comment = comment
+	return ao
+}
+
+// SetGetMoreOptions specifies which of the batch size, comment, and max await time are sent again
+// on the getMore commands that fetch the later batches of the cursor.
+func (ao *AggregateOptions) SetGetMoreOptions(gmo *GetMoreOptions) *AggregateOptions {
+	ao.GetMoreOptions = gmo
 	return ao
 }
 
@@ -82,6 +97,29 @@ func (ao *AggregateOptions) SetHint(h interface{}) *AggregateOptions {
 	return ao
 }
 
+// SetLet specifies a document of parameter names and values that can be accessed in the command
+// with $$name. Valid for server versions >= 5.0.
+func (ao *AggregateOptions) SetLet(let interface{}) *AggregateOptions {
+	ao.Let = let
+	return ao
+}
+
+// SetReadConcern specifies a read concern for the operation that overrides the read concern of the
+// collection.
+func (ao *AggregateOptions) SetReadConcern(rc *readconcern.ReadConcern) *AggregateOptions {
+	ao.ReadConcern = rc
+	return ao
+}
+
+// SetTargetBatchBytes specifies the size in bytes that the batches returned by getMore commands
+// should stay near. The batch size of each getMore is derived from the average size of the
+// documents returned so far, overriding the batch size sent on getMore commands. The first batch is
+// not affected.
+func (ao *AggregateOptions) SetTargetBatchBytes(n int32) *AggregateOptions {
+	ao.TargetBatchBytes = &n
+	return ao
+}
+
 // MergeAggregateOptions combines the argued AggregateOptions into a single AggregateOptions in a last-one-wins fashion
 func MergeAggregateOptions(opts ...*AggregateOptions) *AggregateOptions {
 	aggOpts := Aggregate()
@@ -110,9 +148,21 @@ func MergeAggregateOptions(opts ...*AggregateOptions) *AggregateOptions {
 		if ao.Comment != nil {
 			aggOpts.Comment = ao.Comment
 		}
+		if ao.GetMoreOptions != nil {
+			aggOpts.GetMoreOptions = ao.GetMoreOptions
+		}
 		if ao.Hint != nil {
 			aggOpts.Hint = ao.Hint
 		}
+		if ao.Let != nil {
+			aggOpts.Let = ao.Let
+		}
+		if ao.ReadConcern != nil {
+			aggOpts.ReadConcern = ao.ReadConcern
+		}
+		if ao.TargetBatchBytes != nil {
+			aggOpts.TargetBatchBytes = ao.TargetBatchBytes
+		}
 	}
 
 	return aggOpts
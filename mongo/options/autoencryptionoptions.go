@@ -0,0 +1,138 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import "time"
+
+// AutoEncryptionOptions represents all possible options to configure automatic client-side field
+// level encryption and decryption for a Client. Client-side encryption requires the driver to be
+// built with the cse build tag and libmongocrypt to be installed.
+type AutoEncryptionOptions struct {
+	KeyVaultClientOptions *ClientOptions                    // Options for the client used to access the key vault. Defaults to the options of the Client.
+	KeyVaultNamespace     string                            // The namespace of the key vault collection, in the form db.collection. Required.
+	KmsProviders          map[string]map[string]interface{} // The configuration of the "aws" and "local" KMS providers. Required.
+	SchemaMap             map[string]interface{}            // Maps namespaces to JSON schemas used instead of the ones stored on the server.
+	EncryptedFieldsMap    map[string]interface{}            // Maps namespaces to the encryptedFields of collections using Queryable Encryption.
+	BypassAutoEncryption  *bool                             // If true, commands are not encrypted but replies are still decrypted.
+	ExtraOptions          map[string]interface{}            // Options configuring mongocryptd.
+	SchemaCacheTTL        *time.Duration                    // How long the schemas fetched from the server are cached.
+}
+
+// AutoEncryption creates a new *AutoEncryptionOptions.
+func AutoEncryption() *AutoEncryptionOptions {
+	return &AutoEncryptionOptions{}
+}
+
+// SetKeyVaultClientOptions specifies the options for the client used to access the key vault
+// collection.
+func (a *AutoEncryptionOptions) SetKeyVaultClientOptions(opts *ClientOptions) *AutoEncryptionOptions {
+	a.KeyVaultClientOptions = opts
+	return a
+}
+
+// SetKeyVaultNamespace specifies the namespace of the key vault collection, in the form
+// db.collection.
+func (a *AutoEncryptionOptions) SetKeyVaultNamespace(ns string) *AutoEncryptionOptions {
+	a.KeyVaultNamespace = ns
+	return a
+}
+
+// SetKmsProviders specifies the configuration of the KMS providers. The "aws" provider requires
+// the accessKeyId and secretAccessKey fields. The "local" provider requires the key field, a 96
+// byte master key.
+func (a *AutoEncryptionOptions) SetKmsProviders(providers map[string]map[string]interface{}) *AutoEncryptionOptions {
+	a.KmsProviders = providers
+	return a
+}
+
+// SetSchemaMap specifies a map from namespaces to JSON schemas. Schemas in this map are used
+// instead of the ones stored on the server, which protects against a server that has been tampered
+// with to omit encryption.
+func (a *AutoEncryptionOptions) SetSchemaMap(schemaMap map[string]interface{}) *AutoEncryptionOptions {
+	a.SchemaMap = schemaMap
+	return a
+}
+
+// SetEncryptedFieldsMap specifies a map from namespaces to the encryptedFields documents of
+// collections using Queryable Encryption. Like the schema map, it is used instead of the
+// configuration stored on the server. Database.CreateCollection also uses it to create the
+// collections in it along with their metadata collections.
+func (a *AutoEncryptionOptions) SetEncryptedFieldsMap(efMap map[string]interface{}) *AutoEncryptionOptions {
+	a.EncryptedFieldsMap = efMap
+	return a
+}
+
+// SetBypassAutoEncryption specifies whether commands are sent without being encrypted. Replies are
+// still decrypted automatically.
+func (a *AutoEncryptionOptions) SetBypassAutoEncryption(bypass bool) *AutoEncryptionOptions {
+	a.BypassAutoEncryption = &bypass
+	return a
+}
+
+// SetExtraOptions specifies options configuring mongocryptd, the process that marks the fields of
+// commands to encrypt. Supported options are:
+//
+// mongocryptdURI: the URI used to connect to mongocryptd. Defaults to mongodb://localhost:27020.
+//
+// mongocryptdBypassSpawn: if true, mongocryptd is not spawned by the driver. Defaults to false.
+//
+// mongocryptdSpawnPath: the path of the mongocryptd executable. Defaults to mongocryptd.
+//
+// mongocryptdSpawnArgs: the arguments mongocryptd is spawned with, as a []string. Unless one of
+// them sets --idleShutdownTimeoutSecs, --idleShutdownTimeoutSecs=60 is added.
+func (a *AutoEncryptionOptions) SetExtraOptions(extraOpts map[string]interface{}) *AutoEncryptionOptions {
+	a.ExtraOptions = extraOpts
+	return a
+}
+
+// SetSchemaCacheTTL specifies how long the JSON schemas and encryptedFields fetched from the server
+// for a namespace are reused before they are fetched again. The cache is also cleared when a
+// collection is created, dropped or modified through the client, or when the server rejects a
+// command in a way that suggests a schema changed. A duration of 0 disables caching. The default is
+// one minute.
+func (a *AutoEncryptionOptions) SetSchemaCacheTTL(ttl time.Duration) *AutoEncryptionOptions {
+	a.SchemaCacheTTL = &ttl
+	return a
+}
+
+// MergeAutoEncryptionOptions combines the given *AutoEncryptionOptions into a single
+// *AutoEncryptionOptions in a last one wins fashion.
+func MergeAutoEncryptionOptions(opts ...*AutoEncryptionOptions) *AutoEncryptionOptions {
+	aeo := AutoEncryption()
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		if opt.KeyVaultClientOptions != nil {
+			aeo.KeyVaultClientOptions = opt.KeyVaultClientOptions
+		}
+		if opt.KeyVaultNamespace != "" {
+			aeo.KeyVaultNamespace = opt.KeyVaultNamespace
+		}
+		if opt.KmsProviders != nil {
+			aeo.KmsProviders = opt.KmsProviders
+		}
+		if opt.SchemaMap != nil {
+			aeo.SchemaMap = opt.SchemaMap
+		}
+		if opt.EncryptedFieldsMap != nil {
+			aeo.EncryptedFieldsMap = opt.EncryptedFieldsMap
+		}
+		if opt.BypassAutoEncryption != nil {
+			aeo.BypassAutoEncryption = opt.BypassAutoEncryption
+		}
+		if opt.ExtraOptions != nil {
+			aeo.ExtraOptions = opt.ExtraOptions
+		}
+		if opt.SchemaCacheTTL != nil {
+			aeo.SchemaCacheTTL = opt.SchemaCacheTTL
+		}
+	}
+
+	return aeo
+}
@@ -6,13 +6,16 @@
 
 package options
 
+import "go.mongodb.org/mongo-driver/mongo/writeconcern"
+
 // DefaultOrdered is the default order for a BulkWriteOptions struct created from BulkWrite.
 var DefaultOrdered = true
 
 // BulkWriteOptions represent all possible options for a bulkWrite operation.
 type BulkWriteOptions struct {
-	BypassDocumentValidation *bool // If true, allows the write to opt out of document-level validation.
-	Ordered                  *bool // If true, when a write fails, return without performing remaining writes. Defaults to true.
+	BypassDocumentValidation *bool                      // If true, allows the write to opt out of document-level validation.
+	Ordered                  *bool                      // If true, when a write fails, return without performing remaining writes. Defaults to true.
+	WriteConcern             *writeconcern.WriteConcern // The write concern for the operation. Overrides the write concern of the collection.
 }
 
 // BulkWrite creates a new *BulkWriteOptions
@@ -36,6 +39,13 @@ func (b *BulkWriteOptions) SetBypassDocumentValidation(bypass bool) *BulkWriteOp
 	return b
 }
 
+// SetWriteConcern specifies a write concern for the operation that overrides the write concern of
+// the collection.
+func (b *BulkWriteOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *BulkWriteOptions {
+	b.WriteConcern = wc
+	return b
+}
+
 // MergeBulkWriteOptions combines the given *BulkWriteOptions into a single *BulkWriteOptions in a last one wins fashion.
 func MergeBulkWriteOptions(opts ...*BulkWriteOptions) *BulkWriteOptions {
 	b := BulkWrite()
@@ -49,6 +59,9 @@ func MergeBulkWriteOptions(opts ...*BulkWriteOptions) *BulkWriteOptions {
 		if opt.BypassDocumentValidation != nil {
 			b.BypassDocumentValidation = opt.BypassDocumentValidation
 		}
+		if opt.WriteConcern != nil {
+			b.WriteConcern = opt.WriteConcern
+		}
 	}
 
 	return b
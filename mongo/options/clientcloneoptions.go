@@ -0,0 +1,91 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// ClientCloneOptions represent all possible options to the Client.CloneWith() function. Options that
+// are not set are copied from the cloned Client.
+type ClientCloneOptions struct {
+	ReadConcern    *readconcern.ReadConcern   // The read concern for operations run by the clone.
+	WriteConcern   *writeconcern.WriteConcern // The write concern for operations run by the clone.
+	ReadPreference *readpref.ReadPref         // The read preference for operations run by the clone.
+	Registry       *bsoncodec.Registry        // The registry to be used to construct BSON encoders and decoders for the clone.
+	Timeout        *time.Duration             // The amount of time a single operation run by the clone may take.
+}
+
+// ClientClone creates a new ClientCloneOptions instance.
+func ClientClone() *ClientCloneOptions {
+	return &ClientCloneOptions{}
+}
+
+// SetReadConcern sets the read concern for the clone.
+func (c *ClientCloneOptions) SetReadConcern(rc *readconcern.ReadConcern) *ClientCloneOptions {
+	c.ReadConcern = rc
+	return c
+}
+
+// SetWriteConcern sets the write concern for the clone.
+func (c *ClientCloneOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *ClientCloneOptions {
+	c.WriteConcern = wc
+	return c
+}
+
+// SetReadPreference sets the read preference for the clone.
+func (c *ClientCloneOptions) SetReadPreference(rp *readpref.ReadPref) *ClientCloneOptions {
+	c.ReadPreference = rp
+	return c
+}
+
+// SetRegistry sets the bsoncodec Registry for the clone.
+func (c *ClientCloneOptions) SetRegistry(r *bsoncodec.Registry) *ClientCloneOptions {
+	c.Registry = r
+	return c
+}
+
+// SetTimeout specifies the amount of time a single operation run by the clone may take, as
+// ClientOptions.SetTimeout does for a Client. A timeout of 0 means that operations never time out.
+func (c *ClientCloneOptions) SetTimeout(d time.Duration) *ClientCloneOptions {
+	c.Timeout = &d
+	return c
+}
+
+// MergeClientCloneOptions combines the *ClientCloneOptions arguments into a single *ClientCloneOptions
+// in a last one wins fashion.
+func MergeClientCloneOptions(opts ...*ClientCloneOptions) *ClientCloneOptions {
+	c := ClientClone()
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.ReadConcern != nil {
+			c.ReadConcern = opt.ReadConcern
+		}
+		if opt.WriteConcern != nil {
+			c.WriteConcern = opt.WriteConcern
+		}
+		if opt.ReadPreference != nil {
+			c.ReadPreference = opt.ReadPreference
+		}
+		if opt.Registry != nil {
+			c.Registry = opt.Registry
+		}
+		if opt.Timeout != nil {
+			c.Timeout = opt.Timeout
+		}
+	}
+
+	return c
+}
@@ -16,15 +16,18 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
-	"go.mongodb.org/mongo-driver/tag"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/dns"
 	"go.mongodb.org/mongo-driver/x/network/connstring"
 )
 
@@ -33,10 +36,114 @@ type ContextDialer interface {
 	DialContext(ctx context.Context, network, address string) (net.Conn, error)
 }
 
+// DialContextFunc is a function that can be used as a ContextDialer, such as the DialContext method
+// of a SOCKS5 proxy dialer or a function that dials through an SSH tunnel.
+type DialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// DialContext implements the ContextDialer interface.
+func (f DialContextFunc) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return f(ctx, network, address)
+}
+
+// DNSResolver performs the DNS lookups used to discover the hosts of mongodb+srv connection strings
+// and to resolve host names when dialing. *net.Resolver implements DNSResolver.
+type DNSResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// OperationInfo describes a command that is about to be sent to a server as part of an operation.
+// ServerKind is the type of the server as shown in its description, such as "RSPrimary" or "Mongos".
+type OperationInfo struct {
+	ServerAddress string
+	ServerKind    string
+	DatabaseName  string
+	CommandName   string
+}
+
+// OperationGate decides whether the commands of operations may be sent to servers. It can be used to
+// integrate circuit breakers or adaptive concurrency limits that shed load when the deployment is
+// degraded.
+//
+// Enter is called before each command is sent, including the getMore commands of cursors. If it
+// returns an error, the command is not sent and the operation fails with that error. Otherwise the
+// returned function, which may be nil, is called once when the command is finished with the error it
+// failed with, or nil. Enter must be safe to call concurrently.
+type OperationGate interface {
+	Enter(ctx context.Context, info OperationInfo) (done func(error), err error)
+}
+
+// CommandInterceptor intercepts the commands of operations before they are sent and observes their
+// replies. It can be used to enforce policies across an application, such as adding a comment to
+// every command or limiting maxTimeMS, without wrapping every call site. Only commands sent with
+// OP_MSG, which servers support since MongoDB 3.6, are intercepted, including the getMore commands
+// of cursors. The commands of the handshake, authentication and server monitoring are not.
+//
+// BeforeSend is called with the command document, which includes fields added by the driver such
+// as $db and lsid, and returns the document to send instead, or nil to send it unchanged. If it
+// returns an error, the command is not sent and the operation fails with that error. AfterReply is
+// called once the command is finished with the reply document, or nil if there was none, and the
+// error the command failed with, or nil. Both methods must be safe to call concurrently.
+type CommandInterceptor interface {
+	BeforeSend(ctx context.Context, info OperationInfo, cmd bson.Raw) (bson.Raw, error)
+	AfterReply(ctx context.Context, info OperationInfo, reply bson.Raw, err error)
+}
+
+// DocumentInfo describes a document that is about to be written by an operation. Operation is
+// "insert" for documents that are inserted and "replace" for replacement documents. Index is the
+// position of the document in the documents or write models passed to the operation, or 0 for
+// operations that write a single document.
+type DocumentInfo struct {
+	DatabaseName   string
+	CollectionName string
+	Operation      string
+	Index          int
+}
+
+// DocumentValidator validates documents on the client before they are written. It can be used to
+// enforce rules such as a maximum document size, required fields or banned field names across an
+// application without sending invalid documents to the server.
+//
+// ValidateDocument is called with each document passed to InsertOne, InsertMany, ReplaceOne and
+// FindOneAndReplace, and with the documents of the InsertOneModels and ReplaceOneModels passed to
+// BulkWrite, after the driver has added an _id to inserted documents that lack one. If it returns an
+// error for any document, the operation fails with a mongo.DocumentValidationError before anything
+// is sent to the server. ValidateDocument must be safe to call concurrently.
+type DocumentValidator interface {
+	ValidateDocument(ctx context.Context, info DocumentInfo, doc bson.Raw) error
+}
+
+// DocumentValidatorFunc is a function that implements the DocumentValidator interface.
+type DocumentValidatorFunc func(ctx context.Context, info DocumentInfo, doc bson.Raw) error
+
+// ValidateDocument implements the DocumentValidator interface.
+func (f DocumentValidatorFunc) ValidateDocument(ctx context.Context, info DocumentInfo, doc bson.Raw) error {
+	return f(ctx, info, doc)
+}
+
+// PipelinePolicy adds stages to the aggregation pipelines run on the collections it applies to, such
+// as a $match stage that scopes every aggregation of a multi-tenant application to a tenant, so that
+// application code cannot forget it.
+type PipelinePolicy struct {
+	// Namespaces are the collections the policy applies to, as glob patterns of the form
+	// "database.collection" with the syntax of path.Match. The policy applies to every collection
+	// if there are none.
+	Namespaces []string
+
+	// Prepend are the stages added at the start of the pipelines, after a leading stage that must
+	// come first, such as $geoNear or $search.
+	Prepend []interface{}
+
+	// Append are the stages added at the end of the pipelines, before a final $out or $merge stage.
+	Append []interface{}
+}
+
 // Credential holds auth options.
 //
 // AuthMechanism indicates the mechanism to use for authentication.
-// Supported values include "SCRAM-SHA-256", "SCRAM-SHA-1", "MONGODB-CR", "PLAIN", "GSSAPI", and "MONGODB-X509".
+// Supported values include "SCRAM-SHA-256", "SCRAM-SHA-1", "MONGODB-CR", "PLAIN", "GSSAPI", "MONGODB-X509", and
+// "MONGODB-OIDC".
 //
 // AuthMechanismProperties specifies additional configuration options which may be used by certain
 // authentication mechanisms. Supported properties are:
@@ -46,6 +153,9 @@ type ContextDialer interface {
 // SERVICE_REALM: Specifies the realm of the service.
 // SERVICE_HOST: Specifies a hostname for GSSAPI authentication if it is different from the server's address. For
 // authentication mechanisms besides GSSAPI, this property is ignored.
+// ENVIRONMENT: Specifies the environment whose built-in callback provides the tokens for MONGODB-OIDC, either "azure"
+// or "gcp". For azure, Username may specify the client ID of the managed identity.
+// TOKEN_RESOURCE: Specifies the resource, or audience, the MONGODB-OIDC tokens of the environment are requested for.
 //
 // AuthSource specifies the database to authenticate against.
 //
@@ -54,6 +164,13 @@ type ContextDialer interface {
 // Password specifies the password used for authentication.
 //
 // PasswordSet specifies if the password is actually set, since an empty password is a valid password.
+//
+// OIDCMachineCallback specifies the callback that returns the tokens used to authenticate with MONGODB-OIDC without
+// human interaction, such as a token read from the environment of the workload.
+//
+// OIDCHumanCallback specifies the callback that returns the tokens used to authenticate with MONGODB-OIDC after
+// interacting with a person, given the identity provider returned by the server. At most one of the callbacks and the
+// ENVIRONMENT property may be specified.
 type Credential struct {
 	AuthMechanism           string
 	AuthMechanismProperties map[string]string
@@ -61,34 +178,110 @@ type Credential struct {
 	Username                string
 	Password                string
 	PasswordSet             bool
+	OIDCMachineCallback     OIDCCallback
+	OIDCHumanCallback       OIDCCallback
+}
+
+// OIDCCallback returns the credential used to authenticate with MONGODB-OIDC. The context is cancelled if the
+// callback takes too long. The callbacks of a Client are not called concurrently, and the tokens they return are
+// cached until they expire or the server rejects them.
+type OIDCCallback func(context.Context, *OIDCArgs) (*OIDCCredential, error)
+
+// OIDCArgs are the arguments of an OIDCCallback.
+type OIDCArgs struct {
+	Version      int
+	IDPInfo      *IDPInfo // the identity provider returned by the server, only set for human callbacks
+	RefreshToken *string  // the refresh token of the last credential, if any, only set for human callbacks
+}
+
+// OIDCCredential is the credential returned by an OIDCCallback.
+type OIDCCredential struct {
+	AccessToken  string
+	ExpiresAt    *time.Time // when the access token expires, or nil if it is unknown
+	RefreshToken *string
+}
+
+// IDPInfo is the identity provider the server requires a MONGODB-OIDC human callback to authenticate with.
+type IDPInfo struct {
+	Issuer        string
+	ClientID      string
+	RequestScopes []string
+}
+
+// DriverInfo describes a library or framework that wraps the driver. Its fields are appended to the
+// driver name and version and to the platform of the client metadata document sent to the server
+// when connecting, separated by "|", so that the traffic of the library can be told apart in server
+// logs and telemetry. The platform is truncated if the document is longer than the 512 bytes
+// accepted by servers.
+type DriverInfo struct {
+	Name     string
+	Version  string
+	Platform string
 }
 
 // ClientOptions represents all possible options to configure a client.
 type ClientOptions struct {
+	AddressMap             map[string]string
+	AllowedNamespaces      []string
 	AppName                *string
 	Auth                   *Credential
+	AutoEncryptionOptions  *AutoEncryptionOptions
+	CheckWriteConcern      *bool
+	CommandInterceptors    []CommandInterceptor
+	ConnectionMonitor      *event.ConnectionMonitor
 	ConnectTimeout         *time.Duration
 	Compressors            []string
+	CompressionAllowList   []string
+	CompressionDenyList    []string
+	CompressionMinSize     *int
+	CompressorSelector     func(address string, compressors []string) string
+	DeniedNamespaces       []string
+	DiagnosticEventCount   *int
 	Dialer                 ContextDialer
+	DNSCacheTTL            *time.Duration
+	DNSResolver            DNSResolver
+	DocumentValidator      DocumentValidator
+	DriverInfo             *DriverInfo
 	HeartbeatInterval      *time.Duration
 	Hosts                  []string
+	LoadBalanced           *bool
 	LocalThreshold         *time.Duration
+	LoggerOptions          *LoggerOptions
 	MaxConnIdleTime        *time.Duration
+	MaxInFlightOperations  *uint32
 	MaxPoolSize            *uint16
+	MinPoolSize            *uint16
 	Monitor                *event.CommandMonitor
+	OperationGate          OperationGate
+	PipelinePolicies       []PipelinePolicy
+	PoolMonitor            *event.PoolMonitor
 	ReadConcern            *readconcern.ReadConcern
+	ReadOnly               *bool
 	ReadPreference         *readpref.ReadPref
 	Registry               *bsoncodec.Registry
 	ReplicaSet             *string
+	RetryReads             *bool
 	RetryWrites            *bool
+	ServerAPIOptions       *ServerAPIOptions
+	ServerMonitor          *event.ServerMonitor
 	ServerSelectionTimeout *time.Duration
 	Direct                 *bool
 	SocketTimeout          *time.Duration
+	SRVMaxHosts            *int
+	SRVRemovalThreshold    *int
+	SRVServiceName         *string
+	Timeout                *time.Duration
 	TLSConfig              *tls.Config
+	Tracer                 event.Tracer
 	WriteConcern           *writeconcern.WriteConcern
 	ZlibLevel              *int
 
+	DisableCertificateRevocationCheck *bool
+	DisableOCSPEndpointCheck          *bool
+	RetryWriteConcernTimeouts         *bool
+
 	err error
+	uri string
 
 	// Adds an option for internal use only and should not be set. This option is deprecated and is
 	// not part of the stability guarantee. It may be removed in the future.
@@ -114,11 +307,24 @@ func (c *ClientOptions) ApplyURI(uri string) *ClientOptions {
 		return c
 	}
 
-	cs, err := connstring.Parse(uri)
+	resolver := dns.DefaultResolver
+	if c.DNSResolver != nil {
+		resolver = dns.NewResolver(c.DNSResolver)
+	}
+	if strings.HasPrefix(uri, "mongodb+srv://") {
+		if c.SRVMaxHosts != nil {
+			uri = addURIOption(uri, "srvMaxHosts", strconv.Itoa(*c.SRVMaxHosts))
+		}
+		if c.SRVServiceName != nil {
+			uri = addURIOption(uri, "srvServiceName", *c.SRVServiceName)
+		}
+	}
+	cs, err := connstring.ParseWithResolver(uri, resolver)
 	if err != nil {
 		c.err = err
 		return c
 	}
+	c.uri = cs.Original
 
 	if cs.AppName != "" {
 		c.AppName = &cs.AppName
@@ -155,6 +361,10 @@ func (c *ClientOptions) ApplyURI(uri string) *ClientOptions {
 
 	c.Hosts = cs.Hosts
 
+	if cs.LoadBalancedSet {
+		c.LoadBalanced = &cs.LoadBalanced
+	}
+
 	if cs.LocalThresholdSet {
 		c.LocalThreshold = &cs.LocalThreshold
 	}
@@ -167,32 +377,25 @@ func (c *ClientOptions) ApplyURI(uri string) *ClientOptions {
 		c.MaxPoolSize = &cs.MaxPoolSize
 	}
 
+	if cs.MinPoolSizeSet {
+		c.MinPoolSize = &cs.MinPoolSize
+	}
+
 	if cs.ReadConcernLevel != "" {
 		c.ReadConcern = readconcern.New(readconcern.Level(cs.ReadConcernLevel))
 	}
 
-	if cs.ReadPreference != "" || len(cs.ReadPreferenceTagSets) > 0 || cs.MaxStalenessSet {
-		opts := make([]readpref.Option, 0, 1)
-
-		tagSets := tag.NewTagSetsFromMaps(cs.ReadPreferenceTagSets)
-		if len(tagSets) > 0 {
-			opts = append(opts, readpref.WithTagSets(tagSets...))
-		}
-
-		if cs.MaxStaleness != 0 {
-			opts = append(opts, readpref.WithMaxStaleness(cs.MaxStaleness))
-		}
-
-		mode, err := readpref.ModeFromString(cs.ReadPreference)
-		if err != nil {
-			c.err = err
-			return c
-		}
+	rp, err := cs.ReadPref()
+	if err != nil {
+		c.err = err
+		return c
+	}
+	if rp != nil {
+		c.ReadPreference = rp
+	}
 
-		c.ReadPreference, c.err = readpref.New(mode, opts...)
-		if c.err != nil {
-			return c
-		}
+	if cs.RetryReadsSet {
+		c.RetryReads = &cs.RetryReads
 	}
 
 	if cs.RetryWritesSet {
@@ -211,6 +414,18 @@ func (c *ClientOptions) ApplyURI(uri string) *ClientOptions {
 		c.SocketTimeout = &cs.SocketTimeout
 	}
 
+	if cs.SRVMaxHosts != 0 {
+		c.SRVMaxHosts = &cs.SRVMaxHosts
+	}
+
+	if cs.SRVServiceName != "" {
+		c.SRVServiceName = &cs.SRVServiceName
+	}
+
+	if cs.TimeoutSet {
+		c.Timeout = &cs.Timeout
+	}
+
 	if cs.SSL {
 		tlsConfig := new(tls.Config)
 
@@ -225,6 +440,14 @@ func (c *ClientOptions) ApplyURI(uri string) *ClientOptions {
 			tlsConfig.InsecureSkipVerify = true
 		}
 
+		if cs.TLSDisableCertificateRevocationCheckSet {
+			c.DisableCertificateRevocationCheck = &cs.TLSDisableCertificateRevocationCheck
+		}
+
+		if cs.TLSDisableOCSPEndpointCheckSet {
+			c.DisableOCSPEndpointCheck = &cs.TLSDisableOCSPEndpointCheck
+		}
+
 		if cs.SSLClientCertificateKeyFileSet {
 			var keyPasswd string
 			if cs.SSLClientCertificateKeyPasswordSet && cs.SSLClientCertificateKeyPassword != nil {
@@ -277,6 +500,73 @@ func (c *ClientOptions) ApplyURI(uri string) *ClientOptions {
 	return c
 }
 
+// addURIOption returns uri with the option key set to value, unless uri already sets it.
+func addURIOption(uri, key, value string) string {
+	lower := strings.ToLower(uri)
+	if strings.Contains(lower, "?"+strings.ToLower(key)+"=") || strings.Contains(lower, "&"+strings.ToLower(key)+"=") {
+		return uri
+	}
+	option := key + "=" + url.QueryEscape(value)
+	switch {
+	case strings.Contains(uri, "?"):
+		return uri + "&" + option
+	case strings.Contains(uri[len("mongodb+srv://"):], "/"):
+		return uri + "?" + option
+	default:
+		return uri + "/?" + option
+	}
+}
+
+// GetURI returns the connection string last applied with ApplyURI, or "" if ApplyURI was not
+// called.
+func (c *ClientOptions) GetURI() string {
+	return c.uri
+}
+
+// ApplyURIWithEnv works like ApplyURI, but first replaces each ${NAME} placeholder in uri with the
+// value of the environment variable NAME. This allows credentials to be injected from the
+// environment without formatting them into the connection string. Values substituted into the
+// username and password are percent-encoded automatically.
+//
+// Errors that occur in this method, including a placeholder for an environment variable that is not
+// set, can be retrieved by calling Validate.
+func (c *ClientOptions) ApplyURIWithEnv(uri string) *ClientOptions {
+	if c.err != nil {
+		return c
+	}
+
+	expanded, err := connstring.ExpandEnv(uri)
+	if err != nil {
+		c.err = err
+		return c
+	}
+
+	return c.ApplyURI(expanded)
+}
+
+// SetAddressMap specifies a map from server addresses, as they are given in the seed list and
+// advertised by the servers, to the addresses that are dialed to connect to them instead. This
+// allows connecting through SSH tunnels or port forwards to a replica set that advertises hostnames
+// which are not reachable from the client. Server discovery and monitoring, server selection, and
+// TLS hostname verification still use the original addresses.
+func (c *ClientOptions) SetAddressMap(m map[string]string) *ClientOptions {
+	c.AddressMap = m
+	return c
+}
+
+// SetAllowedNamespaces specifies the namespaces the client may run commands on, as glob patterns of
+// the form "database.collection" with the syntax of path.Match, such as "tenant1.*". If it is set,
+// commands on any other namespace fail with a mongo.NamespaceDeniedError without being sent. Commands
+// on a database as a whole, such as dropDatabase or listCollections, are checked against
+// "database.*", so they are only allowed by patterns that match every collection of the database.
+// The collections aggregation pipelines read from or write to and the target of renameCollection
+// commands are checked as well. This lets multi-tenant services guarantee that a client cannot touch
+// the collections of other tenants.
+func (c *ClientOptions) SetAllowedNamespaces(patterns []string) *ClientOptions {
+	c.AllowedNamespaces = patterns
+	return c
+}
+
 // SetAppName specifies the client application name. This value is used by MongoDB when it logs
 // connection information and profile information, such as slow queries.
 func (c *ClientOptions) SetAppName(s string) *ClientOptions {
@@ -290,13 +580,50 @@ func (c *ClientOptions) SetAuth(auth Credential) *ClientOptions {
 	return c
 }
 
-// SetCompressors sets the compressors that can be used when communicating with a server.
+// SetAutoEncryptionOptions configures automatic client-side field level encryption. Commands are
+// encrypted according to the JSON schemas of their collections before they are sent, and encrypted
+// values in replies are decrypted. Automatic encryption requires MongoDB 4.2 or later.
+func (c *ClientOptions) SetAutoEncryptionOptions(opts *AutoEncryptionOptions) *ClientOptions {
+	c.AutoEncryptionOptions = opts
+	return c
+}
+
+// SetCheckWriteConcern specifies whether writes whose write concern requires acknowledgement from
+// more members than the replica set currently has data-bearing members available fail immediately
+// with an UnsatisfiableWriteConcernError, instead of waiting until wtimeout, or forever if it is not
+// set. Members that have not been checked yet are counted as available. Only numeric write concerns
+// are checked, and only when connected to a replica set. The default is false.
+func (c *ClientOptions) SetCheckWriteConcern(b bool) *ClientOptions {
+	c.CheckWriteConcern = &b
+	return c
+}
+
+// SetCommandInterceptors specifies interceptors that are called before each command is sent and
+// after its reply is received. See CommandInterceptor. The BeforeSend methods are called in order,
+// each with the document returned by the previous one, and the AfterReply methods in reverse order,
+// only for the interceptors whose BeforeSend method succeeded.
+func (c *ClientOptions) SetCommandInterceptors(interceptors []CommandInterceptor) *ClientOptions {
+	c.CommandInterceptors = interceptors
+	return c
+}
+
+// SetCompressors sets the compressors that can be used when communicating with a server, in order
+// of preference. Each connection uses the first of them the server supports. Supported
+// compressors are "snappy" and "zlib"; others are ignored. This can also be set with the
+// compressors URI option.
 func (c *ClientOptions) SetCompressors(comps []string) *ClientOptions {
 	c.Compressors = comps
 
 	return c
 }
 
+// SetConnectionMonitor specifies a monitor used to see how long each stage of establishing a
+// connection takes, such as DNS resolution or the TLS handshake.
+func (c *ClientOptions) SetConnectionMonitor(m *event.ConnectionMonitor) *ClientOptions {
+	c.ConnectionMonitor = m
+	return c
+}
+
 // SetConnectTimeout specifies the timeout for an initial connection to a server.
 // If a custom Dialer is used, this method won't be set and the user is
 // responsible for setting the ConnectTimeout for connections on the dialer
@@ -306,6 +633,14 @@ func (c *ClientOptions) SetConnectTimeout(d time.Duration) *ClientOptions {
 	return c
 }
 
+// SetDiagnosticEventCount specifies the number of recent server discovery and monitoring, connection
+// pool, and command events kept for Client.SupportBundle. Command events are kept without their
+// command and reply documents. The default is 100. A count of 0 disables recording.
+func (c *ClientOptions) SetDiagnosticEventCount(n int) *ClientOptions {
+	c.DiagnosticEventCount = &n
+	return c
+}
+
 // SetDialer specifies a custom dialer used to dial new connections to a server.
 // If a custom dialer is not set, a net.Dialer with a 300 second keepalive time will be used by default.
 func (c *ClientOptions) SetDialer(d ContextDialer) *ClientOptions {
@@ -313,8 +648,82 @@ func (c *ClientOptions) SetDialer(d ContextDialer) *ClientOptions {
 	return c
 }
 
+// SetDialContext specifies a function used to dial new connections to a server instead of a
+// net.Dialer, for example to route connections through a SOCKS5 proxy or an SSH tunnel, to set
+// custom TCP options, or to inject failures in tests. It is called with the network and address of
+// the server, and the connection it returns is used as is, so it is responsible for any timeout or
+// keepalive. It replaces the Dialer set with SetDialer.
+func (c *ClientOptions) SetDialContext(fn func(ctx context.Context, network, address string) (net.Conn, error)) *ClientOptions {
+	c.Dialer = DialContextFunc(fn)
+	return c
+}
+
+// SetDisableCertificateRevocationCheck specifies whether checking that server certificates have not
+// been revoked is disabled. When it is enabled, which is the default, a TLS connection is refused if
+// the OCSP response stapled by the server, cached, or requested from an OCSP responder reports its
+// certificate as revoked, or if the certificate requires a stapled response the server did not
+// provide. If no response can be obtained, the certificate is accepted. This can also be set with
+// the tlsDisableCertificateRevocationCheck URI option.
+func (c *ClientOptions) SetDisableCertificateRevocationCheck(b bool) *ClientOptions {
+	c.DisableCertificateRevocationCheck = &b
+	return c
+}
+
+// SetDisableOCSPEndpointCheck specifies whether OCSP responders are not contacted when a server does
+// not staple an OCSP response to the TLS handshake, so that only stapled and cached responses are
+// used to check that its certificate has not been revoked. The default is false. This can also be
+// set with the tlsDisableOCSPEndpointCheck URI option.
+func (c *ClientOptions) SetDisableOCSPEndpointCheck(b bool) *ClientOptions {
+	c.DisableOCSPEndpointCheck = &b
+	return c
+}
+
+// SetDNSCacheTTL specifies how long the addresses that server host names resolve to are cached.
+// While cached, connections are dialed without waiting for a DNS lookup. If none of the cached
+// addresses of a host can be dialed, the host is resolved again, so that DNS failovers such as
+// CNAME changes are picked up without waiting for the cache to expire. The DNS TTLs of the records
+// are not known to the driver, so d should not exceed them. The default is 0, which resolves host
+// names on every dial.
+func (c *ClientOptions) SetDNSCacheTTL(d time.Duration) *ClientOptions {
+	c.DNSCacheTTL = &d
+	return c
+}
+
+// SetDNSResolver specifies a custom resolver for the SRV and TXT lookups of mongodb+srv connection
+// strings and for resolving host names before they are dialed. This allows split-horizon DNS or
+// service discovery systems to be used. SRV and TXT records are looked up when ApplyURI is called,
+// so SetDNSResolver must be called before ApplyURI for the resolver to be used for them.
+func (c *ClientOptions) SetDNSResolver(r DNSResolver) *ClientOptions {
+	c.DNSResolver = r
+	return c
+}
+
+// SetPipelinePolicies specifies policies that add stages to the pipelines of the aggregations and
+// CountDocuments operations the client runs on a collection. The stages of every policy that applies
+// to the collection are added, in the order of the policies. Aggregations run on a database as a
+// whole and change streams are not affected. See PipelinePolicy.
+func (c *ClientOptions) SetPipelinePolicies(policies ...PipelinePolicy) *ClientOptions {
+	c.PipelinePolicies = policies
+	return c
+}
+
+// SetDocumentValidator specifies a validator that is called with each document before it is
+// inserted or used to replace a document. See DocumentValidator.
+func (c *ClientOptions) SetDocumentValidator(v DocumentValidator) *ClientOptions {
+	c.DocumentValidator = v
+	return c
+}
+
+// SetDriverInfo specifies the library or framework that wraps the driver. See DriverInfo.
+func (c *ClientOptions) SetDriverInfo(info *DriverInfo) *ClientOptions {
+	c.DriverInfo = info
+	return c
+}
+
 // SetDirect specifies whether the driver should connect directly to the server instead of
-// auto-discovering other servers in the cluster.
+// auto-discovering other servers in the cluster. Commands are then always sent to that server, such
+// as a hidden secondary, whatever its type. A direct connection can only be made to a single host.
+// This can also be set with the directConnection=true URI option.
 func (c *ClientOptions) SetDirect(b bool) *ClientOptions {
 	c.Direct = &b
 	return c
@@ -332,6 +741,15 @@ func (c *ClientOptions) SetHosts(s []string) *ClientOptions {
 	return c
 }
 
+// SetLoadBalanced specifies whether the driver is connecting to a load balancer in front of a
+// sharded cluster. When it is, server monitoring is disabled, the single host is treated as a load
+// balancer, and cursors and transactions are pinned to the connection they were started on. It
+// cannot be combined with multiple hosts, a replica set name, or a direct connection.
+func (c *ClientOptions) SetLoadBalanced(b bool) *ClientOptions {
+	c.LoadBalanced = &b
+	return c
+}
+
 // SetLocalThreshold specifies how far to distribute queries, beyond the server with the fastest
 // round-trip time. If a server's roundtrip time is more than LocalThreshold slower than the
 // the fastest, the driver will not send queries to that server.
@@ -340,6 +758,12 @@ func (c *ClientOptions) SetLocalThreshold(d time.Duration) *ClientOptions {
 	return c
 }
 
+// SetLoggerOptions specifies the structured logging options for a client.
+func (c *ClientOptions) SetLoggerOptions(lo *LoggerOptions) *ClientOptions {
+	c.LoggerOptions = lo
+	return c
+}
+
 // SetMaxConnIdleTime specifies the maximum number of milliseconds that a connection can remain idle
 // in a connection pool before being removed and closed.
 func (c *ClientOptions) SetMaxConnIdleTime(d time.Duration) *ClientOptions {
@@ -347,18 +771,57 @@ func (c *ClientOptions) SetMaxConnIdleTime(d time.Duration) *ClientOptions {
 	return c
 }
 
+// SetMaxInFlightOperations specifies the maximum number of commands the client sends to servers at
+// once, across all servers. This protects a deployment from being flooded by an application that
+// starts operations faster than they complete, such as one with a goroutine leak. Commands that
+// exceed the limit wait in line for a slot until their context is done, in which case they fail
+// with a mongo.OperationLimitError. The commands of cursors, including getMore, count against the
+// limit while they are in flight. The default is 0, which means there is no limit.
+func (c *ClientOptions) SetMaxInFlightOperations(n uint32) *ClientOptions {
+	c.MaxInFlightOperations = &n
+	return c
+}
+
 // SetMaxPoolSize specifies the max size of a server's connection pool.
 func (c *ClientOptions) SetMaxPoolSize(u uint16) *ClientOptions {
 	c.MaxPoolSize = &u
 	return c
 }
 
+// SetMinPoolSize specifies the minimum number of connections kept open to each server. Connections
+// are created in the background when a server's pool has fewer. It must not be greater than the max
+// pool size.
+func (c *ClientOptions) SetMinPoolSize(u uint16) *ClientOptions {
+	c.MinPoolSize = &u
+	return c
+}
+
 // SetMonitor specifies a command monitor used to see commands for a client.
 func (c *ClientOptions) SetMonitor(m *event.CommandMonitor) *ClientOptions {
 	c.Monitor = m
 	return c
 }
 
+// SetOperationGate specifies a gate that is called before each command is sent to a server and may
+// reject it. See OperationGate.
+func (c *ClientOptions) SetOperationGate(g OperationGate) *ClientOptions {
+	c.OperationGate = g
+	return c
+}
+
+// SetPoolMonitor specifies a monitor used to see connection pool events for a client.
+func (c *ClientOptions) SetPoolMonitor(m *event.PoolMonitor) *ClientOptions {
+	c.PoolMonitor = m
+	return c
+}
+
+// SetServerMonitor specifies an SDAM monitor used to see server and topology changes and server
+// heartbeats for a client.
+func (c *ClientOptions) SetServerMonitor(m *event.ServerMonitor) *ClientOptions {
+	c.ServerMonitor = m
+	return c
+}
+
 // SetReadConcern specifies the read concern.
 func (c *ClientOptions) SetReadConcern(rc *readconcern.ReadConcern) *ClientOptions {
 	c.ReadConcern = rc
@@ -366,6 +829,17 @@ func (c *ClientOptions) SetReadConcern(rc *readconcern.ReadConcern) *ClientOptio
 	return c
 }
 
+// SetReadOnly specifies whether the client rejects every command that may modify data, such as
+// inserts, index builds, drops, user management commands, aggregations that end with $out or $merge
+// and mapReduce commands that do not return their results inline. Rejected commands are not sent
+// and fail with a mongo.ReadOnlyError. This applies to commands run with RunCommand as well, which
+// makes it suitable for analytics services and dashboards that must never modify data. The default
+// is false.
+func (c *ClientOptions) SetReadOnly(b bool) *ClientOptions {
+	c.ReadOnly = &b
+	return c
+}
+
 // SetReadPreference specifies the read preference.
 func (c *ClientOptions) SetReadPreference(rp *readpref.ReadPref) *ClientOptions {
 	c.ReadPreference = rp
@@ -385,6 +859,28 @@ func (c *ClientOptions) SetReplicaSet(s string) *ClientOptions {
 	return c
 }
 
+// SetRetryReads specifies whether the client has retryable reads enabled. When enabled, a read
+// that fails with a retryable error, such as a network error or a "not master" error, is retried
+// once against a newly selected server if the deployment supports sessions. Reads executed within a
+// transaction are never retried. Retryable reads are enabled by default.
+func (c *ClientOptions) SetRetryReads(b bool) *ClientOptions {
+	c.RetryReads = &b
+
+	return c
+}
+
+// SetRetryWriteConcernTimeouts specifies whether retryable writes with a majority write concern are
+// retried once when their write concern times out, for applications that prefer availability over
+// latency. The write was applied by the primary but not replicated to a majority within wtimeout.
+// The retry has the same transaction number, so a server that has the write only waits for the
+// write concern again, and the whole write may take up to twice wtimeout. If the write was rolled
+// back after a failover, however, the new primary applies it again, after any writes it accepted
+// in the meantime. Multi-document updates and deletes are never retried. The default is false.
+func (c *ClientOptions) SetRetryWriteConcernTimeouts(b bool) *ClientOptions {
+	c.RetryWriteConcernTimeouts = &b
+	return c
+}
+
 // SetRetryWrites specifies whether the client has retryable writes enabled.
 func (c *ClientOptions) SetRetryWrites(b bool) *ClientOptions {
 	c.RetryWrites = &b
@@ -392,12 +888,48 @@ func (c *ClientOptions) SetRetryWrites(b bool) *ClientOptions {
 	return c
 }
 
+// SetServerAPIOptions specifies the server API version the client declares on every command it
+// sends, including the commands of the connection handshake. Declaring a version requires
+// MongoDB 5.0 or later.
+func (c *ClientOptions) SetServerAPIOptions(opts *ServerAPIOptions) *ClientOptions {
+	c.ServerAPIOptions = opts
+	return c
+}
+
 // SetServerSelectionTimeout specifies a timeout in milliseconds to block for server selection.
 func (c *ClientOptions) SetServerSelectionTimeout(d time.Duration) *ClientOptions {
 	c.ServerSelectionTimeout = &d
 	return c
 }
 
+// SetSRVMaxHosts specifies the maximum number of hosts found in the SRV records of a mongodb+srv
+// connection string that the client connects to. If more hosts are found, a random subset of them is
+// used, both initially and when the SRV records of a sharded cluster are polled for changes. 0 means
+// there is no limit. SRV records are looked up when ApplyURI is called, so SetSRVMaxHosts must be
+// called before ApplyURI. The srvMaxHosts URI option takes precedence.
+func (c *ClientOptions) SetSRVMaxHosts(n int) *ClientOptions {
+	c.SRVMaxHosts = &n
+	return c
+}
+
+// SetSRVRemovalThreshold specifies the number of consecutive polls of the SRV records of a
+// mongodb+srv connection string a host must be missing from before the client disconnects from it,
+// so that incomplete DNS answers do not tear down a healthy topology. The default is 1. When polling
+// fails, the known hosts are kept and the SRVPollFailed callback of the ServerMonitor is called.
+func (c *ClientOptions) SetSRVRemovalThreshold(n int) *ClientOptions {
+	c.SRVRemovalThreshold = &n
+	return c
+}
+
+// SetSRVServiceName specifies the service name of the SRV records looked up for a mongodb+srv
+// connection string. The default is "mongodb". SRV records are looked up when ApplyURI is called,
+// so SetSRVServiceName must be called before ApplyURI. The srvServiceName URI option takes
+// precedence.
+func (c *ClientOptions) SetSRVServiceName(name string) *ClientOptions {
+	c.SRVServiceName = &name
+	return c
+}
+
 // SetSocketTimeout specifies the time in milliseconds to attempt to send or receive on a socket
 // before the attempt times out.
 func (c *ClientOptions) SetSocketTimeout(d time.Duration) *ClientOptions {
@@ -405,12 +937,30 @@ func (c *ClientOptions) SetSocketTimeout(d time.Duration) *ClientOptions {
 	return c
 }
 
+// SetTimeout specifies the amount of time a single operation may take, including server
+// selection, checking out a connection, the round trips to the server, and retries. The server is
+// sent the time remaining as maxTimeMS, and operations that exceed it return a TimeoutError. Each
+// batch of a cursor is fetched within its own timeout. If the context passed to an operation has a
+// deadline, the deadline is used instead. A timeout of 0 means that operations never time out.
+func (c *ClientOptions) SetTimeout(d time.Duration) *ClientOptions {
+	c.Timeout = &d
+	return c
+}
+
 // SetTLSConfig sets the tls.Config.
 func (c *ClientOptions) SetTLSConfig(cfg *tls.Config) *ClientOptions {
 	c.TLSConfig = cfg
 	return c
 }
 
+// SetTracer specifies a tracer that starts spans around server selection, connection checkout and
+// the commands sent to servers, so that the work of the driver appears in the traces of an
+// application. See event.Tracer.
+func (c *ClientOptions) SetTracer(t event.Tracer) *ClientOptions {
+	c.Tracer = t
+	return c
+}
+
 // SetWriteConcern sets the write concern.
 func (c *ClientOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *ClientOptions {
 	c.WriteConcern = wc
@@ -418,7 +968,50 @@ func (c *ClientOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *ClientOp
 	return c
 }
 
-// SetZlibLevel sets the level for the zlib compressor.
+// SetCompressionAllowList specifies the commands that are compressed when a compressor is
+// negotiated. If it is empty, every command is compressed except those that must never be, such as
+// authentication commands, which are not compressed even if they are in the list.
+func (c *ClientOptions) SetCompressionAllowList(cmds []string) *ClientOptions {
+	c.CompressionAllowList = cmds
+	return c
+}
+
+// SetCompressionDenyList specifies commands that are never compressed, such as commands that are
+// small or whose payloads do not compress well.
+func (c *ClientOptions) SetCompressionDenyList(cmds []string) *ClientOptions {
+	c.CompressionDenyList = cmds
+	return c
+}
+
+// SetDeniedNamespaces specifies namespaces the client may not run commands on, as glob patterns of
+// the form "database.collection". Denied namespaces take precedence over allowed ones. See
+// SetAllowedNamespaces for how commands are checked.
+func (c *ClientOptions) SetDeniedNamespaces(patterns []string) *ClientOptions {
+	c.DeniedNamespaces = patterns
+	return c
+}
+
+// SetCompressionMinSize specifies the size in bytes below which messages are not compressed.
+// Compressing small messages costs more CPU than the bandwidth it saves. The default is 0, which
+// compresses messages of any size.
+func (c *ClientOptions) SetCompressionMinSize(size int) *ClientOptions {
+	c.CompressionMinSize = &size
+	return c
+}
+
+// SetCompressorSelector specifies a function that overrides the compressor negotiated for each
+// connection, for example to only compress messages sent to servers in remote data centers. It is
+// called with the address of the server and the compressors both the client and the server
+// support, in the order set with SetCompressors, and returns the name of the one to use, or an
+// empty string to not compress messages on the connection. The negotiated compressor is reported
+// in the ConnectionReady events of the pool monitor.
+func (c *ClientOptions) SetCompressorSelector(fn func(address string, compressors []string) string) *ClientOptions {
+	c.CompressorSelector = fn
+	return c
+}
+
+// SetZlibLevel sets the level for the zlib compressor, from -1 (the zlib default) to 9. This can
+// also be set with the zlibCompressionLevel URI option.
 func (c *ClientOptions) SetZlibLevel(level int) *ClientOptions {
 	c.ZlibLevel = &level
 
@@ -436,9 +1029,36 @@ func MergeClientOptions(opts ...*ClientOptions) *ClientOptions {
 			continue
 		}
 
+		if opt.AddressMap != nil {
+			c.AddressMap = opt.AddressMap
+		}
+		if opt.AllowedNamespaces != nil {
+			c.AllowedNamespaces = opt.AllowedNamespaces
+		}
+		if opt.DeniedNamespaces != nil {
+			c.DeniedNamespaces = opt.DeniedNamespaces
+		}
+		if opt.DiagnosticEventCount != nil {
+			c.DiagnosticEventCount = opt.DiagnosticEventCount
+		}
 		if opt.Dialer != nil {
 			c.Dialer = opt.Dialer
 		}
+		if opt.DNSCacheTTL != nil {
+			c.DNSCacheTTL = opt.DNSCacheTTL
+		}
+		if opt.DNSResolver != nil {
+			c.DNSResolver = opt.DNSResolver
+		}
+		if opt.DocumentValidator != nil {
+			c.DocumentValidator = opt.DocumentValidator
+		}
+		if opt.DriverInfo != nil {
+			c.DriverInfo = opt.DriverInfo
+		}
+		if opt.PipelinePolicies != nil {
+			c.PipelinePolicies = opt.PipelinePolicies
+		}
 		if opt.AppName != nil {
 			c.AppName = opt.AppName
 		}
@@ -448,9 +1068,33 @@ func MergeClientOptions(opts ...*ClientOptions) *ClientOptions {
 		if opt.AuthenticateToAnything != nil {
 			c.AuthenticateToAnything = opt.AuthenticateToAnything
 		}
+		if opt.AutoEncryptionOptions != nil {
+			c.AutoEncryptionOptions = opt.AutoEncryptionOptions
+		}
+		if opt.CheckWriteConcern != nil {
+			c.CheckWriteConcern = opt.CheckWriteConcern
+		}
+		if opt.CommandInterceptors != nil {
+			c.CommandInterceptors = opt.CommandInterceptors
+		}
 		if opt.Compressors != nil {
 			c.Compressors = opt.Compressors
 		}
+		if opt.CompressionAllowList != nil {
+			c.CompressionAllowList = opt.CompressionAllowList
+		}
+		if opt.CompressionDenyList != nil {
+			c.CompressionDenyList = opt.CompressionDenyList
+		}
+		if opt.CompressionMinSize != nil {
+			c.CompressionMinSize = opt.CompressionMinSize
+		}
+		if opt.CompressorSelector != nil {
+			c.CompressorSelector = opt.CompressorSelector
+		}
+		if opt.ConnectionMonitor != nil {
+			c.ConnectionMonitor = opt.ConnectionMonitor
+		}
 		if opt.ConnectTimeout != nil {
 			c.ConnectTimeout = opt.ConnectTimeout
 		}
@@ -460,21 +1104,45 @@ func MergeClientOptions(opts ...*ClientOptions) *ClientOptions {
 		if len(opt.Hosts) > 0 {
 			c.Hosts = opt.Hosts
 		}
+		if opt.LoadBalanced != nil {
+			c.LoadBalanced = opt.LoadBalanced
+		}
 		if opt.LocalThreshold != nil {
 			c.LocalThreshold = opt.LocalThreshold
 		}
+		if opt.LoggerOptions != nil {
+			c.LoggerOptions = opt.LoggerOptions
+		}
 		if opt.MaxConnIdleTime != nil {
 			c.MaxConnIdleTime = opt.MaxConnIdleTime
 		}
+		if opt.MaxInFlightOperations != nil {
+			c.MaxInFlightOperations = opt.MaxInFlightOperations
+		}
 		if opt.MaxPoolSize != nil {
 			c.MaxPoolSize = opt.MaxPoolSize
 		}
+		if opt.MinPoolSize != nil {
+			c.MinPoolSize = opt.MinPoolSize
+		}
 		if opt.Monitor != nil {
 			c.Monitor = opt.Monitor
 		}
+		if opt.OperationGate != nil {
+			c.OperationGate = opt.OperationGate
+		}
+		if opt.PoolMonitor != nil {
+			c.PoolMonitor = opt.PoolMonitor
+		}
+		if opt.ServerMonitor != nil {
+			c.ServerMonitor = opt.ServerMonitor
+		}
 		if opt.ReadConcern != nil {
 			c.ReadConcern = opt.ReadConcern
 		}
+		if opt.ReadOnly != nil {
+			c.ReadOnly = opt.ReadOnly
+		}
 		if opt.ReadPreference != nil {
 			c.ReadPreference = opt.ReadPreference
 		}
@@ -484,9 +1152,15 @@ func MergeClientOptions(opts ...*ClientOptions) *ClientOptions {
 		if opt.ReplicaSet != nil {
 			c.ReplicaSet = opt.ReplicaSet
 		}
+		if opt.RetryReads != nil {
+			c.RetryReads = opt.RetryReads
+		}
 		if opt.RetryWrites != nil {
 			c.RetryWrites = opt.RetryWrites
 		}
+		if opt.ServerAPIOptions != nil {
+			c.ServerAPIOptions = opt.ServerAPIOptions
+		}
 		if opt.ServerSelectionTimeout != nil {
 			c.ServerSelectionTimeout = opt.ServerSelectionTimeout
 		}
@@ -496,15 +1170,42 @@ func MergeClientOptions(opts ...*ClientOptions) *ClientOptions {
 		if opt.SocketTimeout != nil {
 			c.SocketTimeout = opt.SocketTimeout
 		}
+		if opt.SRVMaxHosts != nil {
+			c.SRVMaxHosts = opt.SRVMaxHosts
+		}
+		if opt.SRVRemovalThreshold != nil {
+			c.SRVRemovalThreshold = opt.SRVRemovalThreshold
+		}
+		if opt.SRVServiceName != nil {
+			c.SRVServiceName = opt.SRVServiceName
+		}
+		if opt.Timeout != nil {
+			c.Timeout = opt.Timeout
+		}
 		if opt.TLSConfig != nil {
 			c.TLSConfig = opt.TLSConfig
 		}
+		if opt.Tracer != nil {
+			c.Tracer = opt.Tracer
+		}
 		if opt.WriteConcern != nil {
 			c.WriteConcern = opt.WriteConcern
 		}
 		if opt.ZlibLevel != nil {
 			c.ZlibLevel = opt.ZlibLevel
 		}
+		if opt.DisableCertificateRevocationCheck != nil {
+			c.DisableCertificateRevocationCheck = opt.DisableCertificateRevocationCheck
+		}
+		if opt.DisableOCSPEndpointCheck != nil {
+			c.DisableOCSPEndpointCheck = opt.DisableOCSPEndpointCheck
+		}
+		if opt.RetryWriteConcernTimeouts != nil {
+			c.RetryWriteConcernTimeouts = opt.RetryWriteConcernTimeouts
+		}
+		if opt.uri != "" {
+			c.uri = opt.uri
+		}
 		if opt.err != nil {
 			c.err = opt.err
 		}
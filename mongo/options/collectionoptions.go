@@ -19,6 +19,10 @@ type CollectionOptions struct {
 	WriteConcern   *writeconcern.WriteConcern // The write concern for operations in the collection.
 	ReadPreference *readpref.ReadPref         // The read preference for operations in the collection.
 	Registry       *bsoncodec.Registry        // The registry to be used to construct BSON encoders and decoders for the collection.
+	Collation      *Collation                 // The collation for operations in the collection that do not specify one.
+	// If true, decoding a document returned by an operation on the collection into a struct fails if
+	// the document contains a field that does not match any field of the struct.
+	DisallowUnknownFields *bool
 }
 
 // Collection creates a new CollectionOptions instance
@@ -50,6 +54,21 @@ func (c *CollectionOptions) SetRegistry(r *bsoncodec.Registry) *CollectionOption
 	return c
 }
 
+// SetCollation sets the collation used by the find, update, delete, count, distinct, and aggregate
+// operations of the collection that do not specify one. Valid for server versions >= 3.4.
+func (c *CollectionOptions) SetCollation(collation *Collation) *CollectionOptions {
+	c.Collation = collation
+	return c
+}
+
+// SetDisallowUnknownFields specifies whether Cursor.Decode, Cursor.All, and SingleResult.Decode return
+// an error when a document returned by an operation on the collection contains a field that does not
+// match any field of the destination struct.
+func (c *CollectionOptions) SetDisallowUnknownFields(b bool) *CollectionOptions {
+	c.DisallowUnknownFields = &b
+	return c
+}
+
 // MergeCollectionOptions combines the *CollectionOptions arguments into a single *CollectionOptions in a last one wins
 // fashion.
 func MergeCollectionOptions(opts ...*CollectionOptions) *CollectionOptions {
@@ -71,6 +90,12 @@ func MergeCollectionOptions(opts ...*CollectionOptions) *CollectionOptions {
 		if opt.Registry != nil {
 			c.Registry = opt.Registry
 		}
+		if opt.Collation != nil {
+			c.Collation = opt.Collation
+		}
+		if opt.DisallowUnknownFields != nil {
+			c.DisallowUnknownFields = opt.DisallowUnknownFields
+		}
 	}
 
 	return c
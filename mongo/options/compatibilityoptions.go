@@ -0,0 +1,60 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+// CompatibilityOptions represents all possible options to the CheckCompatibility() function.
+type CompatibilityOptions struct {
+	MinServerVersion     *string // The minimum version of every server, such as "4.2".
+	RequireChangeStreams *bool   // If true, every server must support change streams.
+	RequireTransactions  *bool   // If true, every server must support transactions.
+}
+
+// Compatibility returns a pointer to a new CompatibilityOptions
+func Compatibility() *CompatibilityOptions {
+	return &CompatibilityOptions{}
+}
+
+// SetMinServerVersion specifies the minimum version, such as "4.2" or "4.0.3", of every server.
+func (co *CompatibilityOptions) SetMinServerVersion(version string) *CompatibilityOptions {
+	co.MinServerVersion = &version
+	return co
+}
+
+// SetRequireChangeStreams specifies whether every server must support change streams, which
+// requires a replica set or sharded cluster of servers 3.6 or later.
+func (co *CompatibilityOptions) SetRequireChangeStreams(b bool) *CompatibilityOptions {
+	co.RequireChangeStreams = &b
+	return co
+}
+
+// SetRequireTransactions specifies whether every server must support transactions, which requires a
+// replica set of servers 4.0 or later or a sharded cluster of servers 4.2 or later.
+func (co *CompatibilityOptions) SetRequireTransactions(b bool) *CompatibilityOptions {
+	co.RequireTransactions = &b
+	return co
+}
+
+// MergeCompatibilityOptions combines the argued CompatibilityOptions into a single CompatibilityOptions in a last-one-wins fashion
+func MergeCompatibilityOptions(opts ...*CompatibilityOptions) *CompatibilityOptions {
+	compatOpts := Compatibility()
+	for _, co := range opts {
+		if co == nil {
+			continue
+		}
+		if co.MinServerVersion != nil {
+			compatOpts.MinServerVersion = co.MinServerVersion
+		}
+		if co.RequireChangeStreams != nil {
+			compatOpts.RequireChangeStreams = co.RequireChangeStreams
+		}
+		if co.RequireTransactions != nil {
+			compatOpts.RequireTransactions = co.RequireTransactions
+		}
+	}
+
+	return compatOpts
+}
@@ -6,15 +6,20 @@
 
 package options
 
-import "time"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+)
 
 // CountOptions represents all possible options to the Count() function.
 type CountOptions struct {
-	Collation *Collation     // Specifies a collation
-	Hint      interface{}    // The index to use
-	Limit     *int64         // The maximum number of documents to count
-	MaxTime   *time.Duration // The maximum amount of time to allow the operation to run
-	Skip      *int64         // The number of documents to skip before counting
+	Collation   *Collation               // Specifies a collation
+	Hint        interface{}              // The index to use
+	Limit       *int64                   // The maximum number of documents to count
+	MaxTime     *time.Duration           // The maximum amount of time to allow the operation to run
+	ReadConcern *readconcern.ReadConcern // The read concern for the operation. Overrides the read concern of the collection.
+	Skip        *int64                   // The number of documents to skip before counting
 }
 
 // Count returns a pointer to a new CountOptions
@@ -53,6 +58,13 @@ func (co *CountOptions) SetSkip(i int64) *CountOptions {
 	return co
 }
 
+// SetReadConcern specifies a read concern for the operation that overrides the read concern of the
+// collection.
+func (co *CountOptions) SetReadConcern(rc *readconcern.ReadConcern) *CountOptions {
+	co.ReadConcern = rc
+	return co
+}
+
 // MergeCountOptions combines the argued CountOptions into a single CountOptions in a last-one-wins fashion
 func MergeCountOptions(opts ...*CountOptions) *CountOptions {
 	countOpts := Count()
@@ -75,6 +87,9 @@ func MergeCountOptions(opts ...*CountOptions) *CountOptions {
 		if co.Skip != nil {
 			countOpts.Skip = co.Skip
 		}
+		if co.ReadConcern != nil {
+			countOpts.ReadConcern = co.ReadConcern
+		}
 	}
 
 	return countOpts
@@ -0,0 +1,212 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+// CreateCollectionOptions represents all possible options to the create command.
+type CreateCollectionOptions struct {
+	Capped             *bool              // If true, the collection is a capped collection. Requires SizeInBytes.
+	SizeInBytes        *int64             // The maximum size of a capped collection in bytes.
+	MaxDocuments       *int64             // The maximum number of documents in a capped collection.
+	Validator          interface{}        // A validation expression that inserted and updated documents must match.
+	ValidationLevel    *string            // How strictly the validator is applied to existing documents.
+	ValidationAction   *string            // Whether invalid documents are rejected or only logged.
+	Collation          *Collation         // The default collation of the collection.
+	TimeSeries         *TimeSeriesOptions // The options of a time series collection.
+	ExpireAfterSeconds *int64             // The time after which documents of a time series collection expire.
+	EncryptedFields    interface{}        // The encryptedFields document of a collection using Queryable Encryption.
+}
+
+// TimeSeriesOptions represents the options of a time series collection.
+type TimeSeriesOptions struct {
+	TimeField            string  // The name of the field that contains the date of each measurement.
+	MetaField            *string // The name of the field that contains the metadata of each measurement.
+	Granularity          *string // The interval between measurements with the same metadata.
+	BucketMaxSpanSeconds *int64  // The maximum time span between the measurements of a bucket.
+}
+
+// TimeSeries creates a new *TimeSeriesOptions.
+func TimeSeries() *TimeSeriesOptions {
+	return &TimeSeriesOptions{}
+}
+
+// SetTimeField specifies the name of the field that contains the date of each measurement. It is
+// required.
+func (ts *TimeSeriesOptions) SetTimeField(field string) *TimeSeriesOptions {
+	ts.TimeField = field
+	return ts
+}
+
+// SetMetaField specifies the name of the field that contains the metadata of each measurement,
+// which identifies the series it belongs to.
+func (ts *TimeSeriesOptions) SetMetaField(field string) *TimeSeriesOptions {
+	ts.MetaField = &field
+	return ts
+}
+
+// SetGranularity specifies the interval between measurements with the same metadata, which is
+// "seconds", "minutes" or "hours". The server uses "seconds" by default.
+func (ts *TimeSeriesOptions) SetGranularity(granularity string) *TimeSeriesOptions {
+	ts.Granularity = &granularity
+	return ts
+}
+
+// SetBucketMaxSpanSeconds specifies the maximum number of seconds between the measurements stored in
+// the same bucket. It requires MongoDB 6.3 or later and cannot be combined with a granularity.
+func (ts *TimeSeriesOptions) SetBucketMaxSpanSeconds(seconds int64) *TimeSeriesOptions {
+	ts.BucketMaxSpanSeconds = &seconds
+	return ts
+}
+
+// CreateCollection creates a new *CreateCollectionOptions.
+func CreateCollection() *CreateCollectionOptions {
+	return &CreateCollectionOptions{}
+}
+
+// SetCapped specifies whether the collection is a capped collection.
+func (c *CreateCollectionOptions) SetCapped(capped bool) *CreateCollectionOptions {
+	c.Capped = &capped
+	return c
+}
+
+// SetSizeInBytes specifies the maximum size of a capped collection in bytes.
+func (c *CreateCollectionOptions) SetSizeInBytes(size int64) *CreateCollectionOptions {
+	c.SizeInBytes = &size
+	return c
+}
+
+// SetMaxDocuments specifies the maximum number of documents in a capped collection.
+func (c *CreateCollectionOptions) SetMaxDocuments(max int64) *CreateCollectionOptions {
+	c.MaxDocuments = &max
+	return c
+}
+
+// SetValidator specifies a validation expression that documents inserted into or updated in the
+// collection must match.
+func (c *CreateCollectionOptions) SetValidator(validator interface{}) *CreateCollectionOptions {
+	c.Validator = validator
+	return c
+}
+
+// SetValidationLevel specifies how strictly the validator is applied to the documents that are
+// updated: "off", "strict" or "moderate". The server uses "strict" by default.
+func (c *CreateCollectionOptions) SetValidationLevel(level string) *CreateCollectionOptions {
+	c.ValidationLevel = &level
+	return c
+}
+
+// SetValidationAction specifies whether documents that do not match the validator are rejected,
+// with "error", or only logged, with "warn". The server uses "error" by default.
+func (c *CreateCollectionOptions) SetValidationAction(action string) *CreateCollectionOptions {
+	c.ValidationAction = &action
+	return c
+}
+
+// SetCollation specifies the default collation of the collection. Indexes created on the collection
+// inherit it unless they specify their own. It requires MongoDB 3.4 or later.
+func (c *CreateCollectionOptions) SetCollation(collation *Collation) *CreateCollectionOptions {
+	c.Collation = collation
+	return c
+}
+
+// SetTimeSeries specifies that the collection is a time series collection with the given options.
+// It requires MongoDB 5.0 or later.
+func (c *CreateCollectionOptions) SetTimeSeries(ts *TimeSeriesOptions) *CreateCollectionOptions {
+	c.TimeSeries = ts
+	return c
+}
+
+// SetExpireAfterSeconds specifies the number of seconds after which the documents of a time series
+// collection are deleted.
+func (c *CreateCollectionOptions) SetExpireAfterSeconds(seconds int64) *CreateCollectionOptions {
+	c.ExpireAfterSeconds = &seconds
+	return c
+}
+
+// SetEncryptedFields specifies the encryptedFields document of a collection using Queryable
+// Encryption. The metadata collections and index Queryable Encryption requires are created along
+// with the collection.
+func (c *CreateCollectionOptions) SetEncryptedFields(encryptedFields interface{}) *CreateCollectionOptions {
+	c.EncryptedFields = encryptedFields
+	return c
+}
+
+// MergeCreateCollectionOptions combines the given *CreateCollectionOptions into a single
+// *CreateCollectionOptions in a last one wins fashion.
+func MergeCreateCollectionOptions(opts ...*CreateCollectionOptions) *CreateCollectionOptions {
+	cc := CreateCollection()
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		if opt.Capped != nil {
+			cc.Capped = opt.Capped
+		}
+		if opt.SizeInBytes != nil {
+			cc.SizeInBytes = opt.SizeInBytes
+		}
+		if opt.MaxDocuments != nil {
+			cc.MaxDocuments = opt.MaxDocuments
+		}
+		if opt.Validator != nil {
+			cc.Validator = opt.Validator
+		}
+		if opt.ValidationLevel != nil {
+			cc.ValidationLevel = opt.ValidationLevel
+		}
+		if opt.ValidationAction != nil {
+			cc.ValidationAction = opt.ValidationAction
+		}
+		if opt.Collation != nil {
+			cc.Collation = opt.Collation
+		}
+		if opt.TimeSeries != nil {
+			cc.TimeSeries = opt.TimeSeries
+		}
+		if opt.ExpireAfterSeconds != nil {
+			cc.ExpireAfterSeconds = opt.ExpireAfterSeconds
+		}
+		if opt.EncryptedFields != nil {
+			cc.EncryptedFields = opt.EncryptedFields
+		}
+	}
+
+	return cc
+}
+
+// CreateViewOptions represents all possible options to the create command for views.
+type CreateViewOptions struct {
+	Collation *Collation // The default collation of the view.
+}
+
+// CreateView creates a new *CreateViewOptions.
+func CreateView() *CreateViewOptions {
+	return &CreateViewOptions{}
+}
+
+// SetCollation specifies the default collation of the view. It requires MongoDB 3.4 or later.
+func (c *CreateViewOptions) SetCollation(collation *Collation) *CreateViewOptions {
+	c.Collation = collation
+	return c
+}
+
+// MergeCreateViewOptions combines the given *CreateViewOptions into a single *CreateViewOptions in
+// a last one wins fashion.
+func MergeCreateViewOptions(opts ...*CreateViewOptions) *CreateViewOptions {
+	cv := CreateView()
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		if opt.Collation != nil {
+			cv.Collation = opt.Collation
+		}
+	}
+
+	return cv
+}
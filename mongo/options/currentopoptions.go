@@ -0,0 +1,73 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import "time"
+
+// CurrentOpOptions represents all possible options to the CurrentOp() function.
+type CurrentOpOptions struct {
+	AllUsers        *bool          // If true, operations of all users are listed instead of only those of the current user.
+	IdleConnections *bool          // If true, idle connections and idle operations are listed too.
+	Namespace       *string        // Only list operations on a database ("db") or collection ("db.coll").
+	MinRunningTime  *time.Duration // Only list operations that have been running for at least this long.
+}
+
+// CurrentOp returns a pointer to a new CurrentOpOptions
+func CurrentOp() *CurrentOpOptions {
+	return &CurrentOpOptions{}
+}
+
+// SetAllUsers specifies whether the operations of all users are listed. Listing the operations of
+// other users requires the inprog privilege.
+func (co *CurrentOpOptions) SetAllUsers(b bool) *CurrentOpOptions {
+	co.AllUsers = &b
+	return co
+}
+
+// SetIdleConnections specifies whether idle connections and idle operations are listed.
+func (co *CurrentOpOptions) SetIdleConnections(b bool) *CurrentOpOptions {
+	co.IdleConnections = &b
+	return co
+}
+
+// SetNamespace specifies the database ("db") or collection ("db.coll") to list the operations of.
+func (co *CurrentOpOptions) SetNamespace(ns string) *CurrentOpOptions {
+	co.Namespace = &ns
+	return co
+}
+
+// SetMinRunningTime specifies the minimum time operations must have been running for to be listed.
+// The server counts running time in whole seconds, so d is rounded down to a whole number of
+// seconds.
+func (co *CurrentOpOptions) SetMinRunningTime(d time.Duration) *CurrentOpOptions {
+	co.MinRunningTime = &d
+	return co
+}
+
+// MergeCurrentOpOptions combines the argued CurrentOpOptions into a single CurrentOpOptions in a last-one-wins fashion
+func MergeCurrentOpOptions(opts ...*CurrentOpOptions) *CurrentOpOptions {
+	currentOpOpts := CurrentOp()
+	for _, co := range opts {
+		if co == nil {
+			continue
+		}
+		if co.AllUsers != nil {
+			currentOpOpts.AllUsers = co.AllUsers
+		}
+		if co.IdleConnections != nil {
+			currentOpOpts.IdleConnections = co.IdleConnections
+		}
+		if co.Namespace != nil {
+			currentOpOpts.Namespace = co.Namespace
+		}
+		if co.MinRunningTime != nil {
+			currentOpOpts.MinRunningTime = co.MinRunningTime
+		}
+	}
+
+	return currentOpOpts
+}
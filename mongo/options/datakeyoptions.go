@@ -0,0 +1,53 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+// DataKeyOptions represents all possible options used to create a data key.
+type DataKeyOptions struct {
+	MasterKey   interface{} // The master key document. Required for the "aws" KMS provider.
+	KeyAltNames []string    // Alternate names that can be used to refer to the data key.
+}
+
+// DataKey creates a new *DataKeyOptions.
+func DataKey() *DataKeyOptions {
+	return &DataKeyOptions{}
+}
+
+// SetMasterKey specifies the master key that encrypts the data key. For the "aws" KMS provider it
+// must contain the region and key fields, the AWS region and the Amazon Resource Name of the
+// customer master key, and may contain an endpoint field.
+func (dk *DataKeyOptions) SetMasterKey(masterKey interface{}) *DataKeyOptions {
+	dk.MasterKey = masterKey
+	return dk
+}
+
+// SetKeyAltNames specifies alternate names that can be used to refer to the data key when
+// encrypting values.
+func (dk *DataKeyOptions) SetKeyAltNames(keyAltNames []string) *DataKeyOptions {
+	dk.KeyAltNames = keyAltNames
+	return dk
+}
+
+// MergeDataKeyOptions combines the given *DataKeyOptions into a single *DataKeyOptions in a last one
+// wins fashion.
+func MergeDataKeyOptions(opts ...*DataKeyOptions) *DataKeyOptions {
+	dko := DataKey()
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		if opt.MasterKey != nil {
+			dko.MasterKey = opt.MasterKey
+		}
+		if opt.KeyAltNames != nil {
+			dko.KeyAltNames = opt.KeyAltNames
+		}
+	}
+
+	return dko
+}
@@ -44,7 +44,8 @@ func (d *DatabaseOptions) SetReadPreference(rp *readpref.ReadPref) *DatabaseOpti
 	return d
 }
 
-// SetRegistry sets the bsoncodec Registry for the database.
+// SetRegistry sets the bsoncodec Registry for the database. Collections of the database use it unless
+// a registry is set in their own options.
 func (d *DatabaseOptions) SetRegistry(r *bsoncodec.Registry) *DatabaseOptions {
 	d.Registry = r
 	return d
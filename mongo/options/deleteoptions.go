@@ -6,9 +6,15 @@
 
 package options
 
+import "go.mongodb.org/mongo-driver/mongo/writeconcern"
+
 // DeleteOptions represents all possible options to the DeleteOne() and DeleteMany() functions.
 type DeleteOptions struct {
-	Collation *Collation // Specifies a collation
+	Collation    *Collation                 // Specifies a collation
+	Comment      interface{}                // Specifies a comment to help trace the operation through the database profiler, currentOp and logs.
+	Hint         interface{}                // The index to use for the operation.
+	Let          interface{}                // Specifies a document of parameter names and values that can be accessed in the command with $$name.
+	WriteConcern *writeconcern.WriteConcern // The write concern for the operation. Overrides the write concern of the collection.
 }
 
 // Delete returns a pointer to a new DeleteOptions
@@ -23,6 +29,34 @@ func (do *DeleteOptions) SetCollation(c *Collation) *DeleteOptions {
 	return do
 }
 
+// SetComment specifies a comment to help trace the operation through the database profiler,
+// currentOp and logs. Valid for server versions >= 4.4.
+func (do *DeleteOptions) SetComment(comment interface{}) *DeleteOptions {
+	do.Comment = comment
+	return do
+}
+
+// SetHint specifies the index to use for the operation, either as the index name or as the index
+// specification document. Valid for server versions >= 4.4.
+func (do *DeleteOptions) SetHint(hint interface{}) *DeleteOptions {
+	do.Hint = hint
+	return do
+}
+
+// SetLet specifies a document of parameter names and values that can be accessed in the command
+// with $$name. Valid for server versions >= 5.0.
+func (do *DeleteOptions) SetLet(let interface{}) *DeleteOptions {
+	do.Let = let
+	return do
+}
+
+// SetWriteConcern specifies a write concern for the operation that overrides the write concern of
+// the collection.
+func (do *DeleteOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *DeleteOptions {
+	do.WriteConcern = wc
+	return do
+}
+
 // MergeDeleteOptions combines the argued DeleteOptions into a single DeleteOptions in a last-one-wins fashion
 func MergeDeleteOptions(opts ...*DeleteOptions) *DeleteOptions {
 	dOpts := Delete()
@@ -33,6 +67,18 @@ func MergeDeleteOptions(opts ...*DeleteOptions) *DeleteOptions {
 		if do.Collation != nil {
 			dOpts.Collation = do.Collation
 		}
+		if do.Comment != nil {
+			dOpts.Comment = do.Comment
+		}
+		if do.Hint != nil {
+			dOpts.Hint = do.Hint
+		}
+		if do.Let != nil {
+			dOpts.Let = do.Let
+		}
+		if do.WriteConcern != nil {
+			dOpts.WriteConcern = do.WriteConcern
+		}
 	}
 
 	return dOpts
@@ -6,12 +6,17 @@
 
 package options
 
-import "time"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+)
 
 // DistinctOptions represents all possible options to the Distinct() function.
 type DistinctOptions struct {
-	Collation *Collation     // Specifies a collation
-	MaxTime   *time.Duration // The maximum amount of time to allow the operation to run
+	Collation   *Collation               // Specifies a collation
+	MaxTime     *time.Duration           // The maximum amount of time to allow the operation to run
+	ReadConcern *readconcern.ReadConcern // The read concern for the operation. Overrides the read concern of the collection.
 }
 
 // Distinct returns a pointer to a new DistinctOptions
@@ -32,6 +37,13 @@ func (do *DistinctOptions) SetMaxTime(d time.Duration) *DistinctOptions {
 	return do
 }
 
+// SetReadConcern specifies a read concern for the operation that overrides the read concern of the
+// collection.
+func (do *DistinctOptions) SetReadConcern(rc *readconcern.ReadConcern) *DistinctOptions {
+	do.ReadConcern = rc
+	return do
+}
+
 // MergeDistinctOptions combines the argued DistinctOptions into a single DistinctOptions in a last-one-wins fashion
 func MergeDistinctOptions(opts ...*DistinctOptions) *DistinctOptions {
 	distinctOpts := Distinct()
@@ -45,6 +57,9 @@ func MergeDistinctOptions(opts ...*DistinctOptions) *DistinctOptions {
 		if do.MaxTime != nil {
 			distinctOpts.MaxTime = do.MaxTime
 		}
+		if do.ReadConcern != nil {
+			distinctOpts.ReadConcern = do.ReadConcern
+		}
 	}
 
 	return distinctOpts
@@ -0,0 +1,125 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// These constants are the algorithms supported for explicit encryption. The Indexed, Unindexed,
+// and Range algorithms are used with Queryable Encryption.
+const (
+	AlgorithmDeterministic = "AEAD_AES_256_CBC_HMAC_SHA_512-Deterministic"
+	AlgorithmRandom        = "AEAD_AES_256_CBC_HMAC_SHA_512-Random"
+	AlgorithmIndexed       = "Indexed"
+	AlgorithmUnindexed     = "Unindexed"
+	AlgorithmRange         = "Range"
+)
+
+// These constants are the query types of values explicitly encrypted to be used in queries against
+// fields encrypted with Queryable Encryption.
+const (
+	QueryTypeEquality = "equality"
+	QueryTypeRange    = "range"
+)
+
+// EncryptOptions represents all possible options used to explicitly encrypt a value. Exactly one of
+// KeyID and KeyAltName must be set.
+type EncryptOptions struct {
+	KeyID            *primitive.Binary // The _id of the data key used to encrypt the value.
+	KeyAltName       *string           // An alternate name of the data key used to encrypt the value.
+	Algorithm        string            // The encryption algorithm. Required.
+	QueryType        string            // The query type of a value used in a query. Only for the Indexed and Range algorithms.
+	ContentionFactor *int64            // The contention factor of the field. Only for the Indexed and Range algorithms.
+	RangeOptions     *RangeOptions     // The index options of the field. Required for the Range algorithm.
+}
+
+// RangeOptions represents the index options of a field encrypted with the Range algorithm. They
+// must match the options in the encryptedFields of the collection.
+type RangeOptions struct {
+	Min       *bson.RawValue // The minimum value of the field.
+	Max       *bson.RawValue // The maximum value of the field.
+	Sparsity  *int64         // The sparsity of the index.
+	Precision *int32         // The number of digits after the decimal point kept for double and decimal values.
+}
+
+// Encrypt creates a new *EncryptOptions.
+func Encrypt() *EncryptOptions {
+	return &EncryptOptions{}
+}
+
+// SetKeyID specifies the _id of the data key used to encrypt the value.
+func (e *EncryptOptions) SetKeyID(keyID primitive.Binary) *EncryptOptions {
+	e.KeyID = &keyID
+	return e
+}
+
+// SetKeyAltName specifies an alternate name of the data key used to encrypt the value.
+func (e *EncryptOptions) SetKeyAltName(keyAltName string) *EncryptOptions {
+	e.KeyAltName = &keyAltName
+	return e
+}
+
+// SetAlgorithm specifies the encryption algorithm, either AlgorithmDeterministic or
+// AlgorithmRandom. Deterministically encrypted values can be queried by equality.
+func (e *EncryptOptions) SetAlgorithm(algorithm string) *EncryptOptions {
+	e.Algorithm = algorithm
+	return e
+}
+
+// SetQueryType specifies the query type of a value that is used to query a field encrypted with the
+// Indexed or Range algorithm, either QueryTypeEquality or QueryTypeRange.
+func (e *EncryptOptions) SetQueryType(queryType string) *EncryptOptions {
+	e.QueryType = queryType
+	return e
+}
+
+// SetContentionFactor specifies the contention factor of a field encrypted with the Indexed or Range
+// algorithm. It must match the contention factor in the encryptedFields of the collection.
+func (e *EncryptOptions) SetContentionFactor(contentionFactor int64) *EncryptOptions {
+	e.ContentionFactor = &contentionFactor
+	return e
+}
+
+// SetRangeOptions specifies the index options of a field encrypted with the Range algorithm.
+func (e *EncryptOptions) SetRangeOptions(ro RangeOptions) *EncryptOptions {
+	e.RangeOptions = &ro
+	return e
+}
+
+// MergeEncryptOptions combines the given *EncryptOptions into a single *EncryptOptions in a last one
+// wins fashion.
+func MergeEncryptOptions(opts ...*EncryptOptions) *EncryptOptions {
+	eo := Encrypt()
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		if opt.KeyID != nil {
+			eo.KeyID = opt.KeyID
+		}
+		if opt.KeyAltName != nil {
+			eo.KeyAltName = opt.KeyAltName
+		}
+		if opt.Algorithm != "" {
+			eo.Algorithm = opt.Algorithm
+		}
+		if opt.QueryType != "" {
+			eo.QueryType = opt.QueryType
+		}
+		if opt.ContentionFactor != nil {
+			eo.ContentionFactor = opt.ContentionFactor
+		}
+		if opt.RangeOptions != nil {
+			eo.RangeOptions = opt.RangeOptions
+		}
+	}
+
+	return eo
+}
@@ -6,11 +6,16 @@
 
 package options
 
-import "time"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+)
 
 // EstimatedDocumentCountOptions represents all possible options to the EstimatedDocumentCount() function.
 type EstimatedDocumentCountOptions struct {
-	MaxTime *time.Duration // The maximum amount of time to allow the operation to run
+	MaxTime     *time.Duration           // The maximum amount of time to allow the operation to run
+	ReadConcern *readconcern.ReadConcern // The read concern for the operation. Overrides the read concern of the collection.
 }
 
 // EstimatedDocumentCount returns a pointer to a new EstimatedDocumentCountOptions
@@ -24,6 +29,13 @@ func (eco *EstimatedDocumentCountOptions) SetMaxTime(d time.Duration) *Estimated
 	return eco
 }
 
+// SetReadConcern specifies a read concern for the operation that overrides the read concern of the
+// collection.
+func (eco *EstimatedDocumentCountOptions) SetReadConcern(rc *readconcern.ReadConcern) *EstimatedDocumentCountOptions {
+	eco.ReadConcern = rc
+	return eco
+}
+
 // MergeEstimatedDocumentCountOptions combines the given *EstimatedDocumentCountOptions into a single
 // *EstimatedDocumentCountOptions in a last one wins fashion.
 func MergeEstimatedDocumentCountOptions(opts ...*EstimatedDocumentCountOptions) *EstimatedDocumentCountOptions {
@@ -36,6 +48,9 @@ func MergeEstimatedDocumentCountOptions(opts ...*EstimatedDocumentCountOptions)
 		if opt.MaxTime != nil {
 			e.MaxTime = opt.MaxTime
 		}
+		if opt.ReadConcern != nil {
+			e.ReadConcern = opt.ReadConcern
+		}
 	}
 
 	return e
@@ -0,0 +1,102 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+// ExportOptions represents all possible options to the Export() function.
+type ExportOptions struct {
+	BatchSize *int32      // The number of documents to return per batch of the cursor.
+	Canonical *bool       // If true, documents are written as canonical instead of relaxed extended JSON.
+	Sort      interface{} // The order in which documents are written.
+}
+
+// Export returns a pointer to a new ExportOptions
+func Export() *ExportOptions {
+	return &ExportOptions{}
+}
+
+// SetBatchSize specifies the number of documents to return per batch of the cursor.
+func (eo *ExportOptions) SetBatchSize(i int32) *ExportOptions {
+	eo.BatchSize = &i
+	return eo
+}
+
+// SetCanonical specifies whether documents are written as canonical extended JSON, which preserves
+// the BSON type of every value, instead of relaxed extended JSON, which is easier to read.
+func (eo *ExportOptions) SetCanonical(b bool) *ExportOptions {
+	eo.Canonical = &b
+	return eo
+}
+
+// SetSort specifies the order in which documents are written.
+func (eo *ExportOptions) SetSort(sort interface{}) *ExportOptions {
+	eo.Sort = sort
+	return eo
+}
+
+// MergeExportOptions combines the argued ExportOptions into a single ExportOptions in a last-one-wins fashion
+func MergeExportOptions(opts ...*ExportOptions) *ExportOptions {
+	exportOpts := Export()
+	for _, eo := range opts {
+		if eo == nil {
+			continue
+		}
+		if eo.BatchSize != nil {
+			exportOpts.BatchSize = eo.BatchSize
+		}
+		if eo.Canonical != nil {
+			exportOpts.Canonical = eo.Canonical
+		}
+		if eo.Sort != nil {
+			exportOpts.Sort = eo.Sort
+		}
+	}
+
+	return exportOpts
+}
+
+// ImportOptions represents all possible options to the Import() function.
+type ImportOptions struct {
+	BatchSize *int32 // The number of documents inserted with each insert operation. The default is 1000.
+	Ordered   *bool  // If true, the import stops at the first document that cannot be inserted. The default is true.
+}
+
+// Import returns a pointer to a new ImportOptions
+func Import() *ImportOptions {
+	return &ImportOptions{}
+}
+
+// SetBatchSize specifies the number of documents inserted with each insert operation.
+func (imo *ImportOptions) SetBatchSize(i int32) *ImportOptions {
+	imo.BatchSize = &i
+	return imo
+}
+
+// SetOrdered specifies whether the import stops at the first document that cannot be inserted. If
+// false, the documents that can be inserted are, and the errors of the others are reported at the
+// end of the import.
+func (imo *ImportOptions) SetOrdered(b bool) *ImportOptions {
+	imo.Ordered = &b
+	return imo
+}
+
+// MergeImportOptions combines the argued ImportOptions into a single ImportOptions in a last-one-wins fashion
+func MergeImportOptions(opts ...*ImportOptions) *ImportOptions {
+	importOpts := Import()
+	for _, imo := range opts {
+		if imo == nil {
+			continue
+		}
+		if imo.BatchSize != nil {
+			importOpts.BatchSize = imo.BatchSize
+		}
+		if imo.Ordered != nil {
+			importOpts.Ordered = imo.Ordered
+		}
+	}
+
+	return importOpts
+}
@@ -8,29 +8,36 @@ package options
 
 import (
 	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 // FindOptions represent all possible options to the Find() function.
 type FindOptions struct {
-	AllowPartialResults *bool          // If true, allows partial results to be returned if some shards are down.
-	BatchSize           *int32         // Specifies the number of documents to return in every batch.
-	Collation           *Collation     // Specifies a collation to be used
-	Comment             *string        // Specifies a string to help trace the operation through the database.
-	CursorType          *CursorType    // Specifies the type of cursor to use
-	Hint                interface{}    // Specifies the index to use.
-	Limit               *int64         // Sets a limit on the number of results to return.
-	Max                 interface{}    // Sets an exclusive upper bound for a specific index
-	MaxAwaitTime        *time.Duration // Specifies the maximum amount of time for the server to wait on new documents.
-	MaxTime             *time.Duration // Specifies the maximum amount of time to allow the query to run.
-	Min                 interface{}    // Specifies the inclusive lower bound for a specific index.
-	NoCursorTimeout     *bool          // If true, prevents cursors from timing out after an inactivity period.
-	OplogReplay         *bool          // Adds an option for internal use only and should not be set.
-	Projection          interface{}    // Limits the fields returned for all documents.
-	ReturnKey           *bool          // If true, only returns index keys for all result documents.
-	ShowRecordID        *bool          // If true, a $recordId field with the record identifier will be added to the returned documents.
-	Skip                *int64         // Specifies the number of documents to skip before returning
-	Snapshot            *bool          // If true, prevents the cursor from returning a document more than once because of an intervening write operation.
-	Sort                interface{}    // Specifies the order in which to return results.
+	AllowPartialResults *bool                    // If true, allows partial results to be returned if some shards are down.
+	BatchSize           *int32                   // Specifies the number of documents to return in every batch.
+	Collation           *Collation               // Specifies a collation to be used
+	Comment             interface{}              // Specifies a comment to help trace the operation through the database profiler, currentOp and logs.
+	CursorType          *CursorType              // Specifies the type of cursor to use
+	GetMoreOptions      *GetMoreOptions          // Specifies which options are sent again on getMore commands.
+	Hint                interface{}              // Specifies the index to use.
+	Let                 interface{}              // Specifies a document of parameter names and values that can be accessed in the command with $$name.
+	Limit               *int64                   // Sets a limit on the number of results to return.
+	Max                 interface{}              // Sets an exclusive upper bound for a specific index
+	MaxAwaitTime        *time.Duration           // Specifies the maximum amount of time for the server to wait on new documents.
+	MaxTime             *time.Duration           // Specifies the maximum amount of time to allow the query to run.
+	Min                 interface{}              // Specifies the inclusive lower bound for a specific index.
+	NoCursorTimeout     *bool                    // If true, prevents cursors from timing out after an inactivity period.
+	OplogReplay         *bool                    // Adds an option for internal use only and should not be set.
+	Projection          interface{}              // Limits the fields returned for all documents.
+	ReadConcern         *readconcern.ReadConcern // The read concern for the operation. Overrides the read concern of the collection.
+	ReturnKey           *bool                    // If true, only returns index keys for all result documents.
+	ShowRecordID        *bool                    // If true, a $recordId field with the record identifier will be added to the returned documents.
+	Skip                *int64                   // Specifies the number of documents to skip before returning
+	Snapshot            *bool                    // If true, prevents the cursor from returning a document more than once because of an intervening write operation.
+	Sort                interface{}              // Specifies the order in which to return results.
+	TargetBatchBytes    *int32                   // Specifies the size in bytes that getMore replies should stay near.
 }
 
 // Find creates a new FindOptions instance.
@@ -58,9 +65,10 @@ func (f *FindOptions) SetCollation(collation *Collation) *FindOptions {
 	return f
 }
 
-// SetComment specifies a string to help trace the operation through the database.
-func (f *FindOptions) SetComment(comment string) *FindOptions {
-	f.Comment = &comment
+// SetComment specifies a comment to help trace the operation through the database profiler,
+// currentOp and logs. Comments other than strings are valid for server versions >= 4.4.
+func (f *FindOptions) SetComment(comment interface{}) *FindOptions {
+	f.Comment = comment
 	return f
 }
 
@@ -70,12 +78,26 @@ func (f *FindOptions) SetCursorType(ct CursorType) *FindOptions {
 	return f
 }
 
+// SetGetMoreOptions specifies which of the batch size, comment, and max await time are sent again
+// on the getMore commands that fetch the later batches of the cursor.
+func (f *FindOptions) SetGetMoreOptions(gmo *GetMoreOptions) *FindOptions {
+	f.GetMoreOptions = gmo
+	return f
+}
+
 // SetHint specifies the index to use.
 func (f *FindOptions) SetHint(hint interface{}) *FindOptions {
 	f.Hint = hint
 	return f
 }
 
+// SetLet specifies a document of parameter names and values that can be accessed in the command
+// with $$name. Valid for server versions >= 5.0.
+func (f *FindOptions) SetLet(let interface{}) *FindOptions {
+	f.Let = let
+	return f
+}
+
 // SetLimit specifies a limit on the number of results.
 // A negative limit implies that only 1 batch should be returned.
 func (f *FindOptions) SetLimit(i int64) *FindOptions {
@@ -161,6 +183,22 @@ func (f *FindOptions) SetSort(sort interface{}) *FindOptions {
 	return f
 }
 
+// SetReadConcern specifies a read concern for the operation that overrides the read concern of the
+// collection.
+func (f *FindOptions) SetReadConcern(rc *readconcern.ReadConcern) *FindOptions {
+	f.ReadConcern = rc
+	return f
+}
+
+// SetTargetBatchBytes specifies the size in bytes that the batches returned by getMore commands
+// should stay near. The batch size of each getMore is derived from the average size of the
+// documents returned so far, overriding the batch size sent on getMore commands. The first batch is
+// not affected.
+func (f *FindOptions) SetTargetBatchBytes(n int32) *FindOptions {
+	f.TargetBatchBytes = &n
+	return f
+}
+
 // MergeFindOptions combines the argued FindOptions into a single FindOptions in a last-one-wins fashion
 func MergeFindOptions(opts ...*FindOptions) *FindOptions {
 	fo := Find()
@@ -183,9 +221,15 @@ func MergeFindOptions(opts ...*FindOptions) *FindOptions {
 		if opt.CursorType != nil {
 			fo.CursorType = opt.CursorType
 		}
+		if opt.GetMoreOptions != nil {
+			fo.GetMoreOptions = opt.GetMoreOptions
+		}
 		if opt.Hint != nil {
 			fo.Hint = opt.Hint
 		}
+		if opt.Let != nil {
+			fo.Let = opt.Let
+		}
 		if opt.Limit != nil {
 			fo.Limit = opt.Limit
 		}
@@ -225,6 +269,12 @@ func MergeFindOptions(opts ...*FindOptions) *FindOptions {
 		if opt.Sort != nil {
 			fo.Sort = opt.Sort
 		}
+		if opt.ReadConcern != nil {
+			fo.ReadConcern = opt.ReadConcern
+		}
+		if opt.TargetBatchBytes != nil {
+			fo.TargetBatchBytes = opt.TargetBatchBytes
+		}
 	}
 
 	return fo
@@ -232,24 +282,26 @@ func MergeFindOptions(opts ...*FindOptions) *FindOptions {
 
 // FindOneOptions represent all possible options to the FindOne() function.
 type FindOneOptions struct {
-	AllowPartialResults *bool          // If true, allows partial results to be returned if some shards are down.
-	BatchSize           *int32         // Specifies the number of documents to return in every batch.
-	Collation           *Collation     // Specifies a collation to be used
-	Comment             *string        // Specifies a string to help trace the operation through the database.
-	CursorType          *CursorType    // Specifies the type of cursor to use
-	Hint                interface{}    // Specifies the index to use.
-	Max                 interface{}    // Sets an exclusive upper bound for a specific index
-	MaxAwaitTime        *time.Duration // Specifies the maximum amount of time for the server to wait on new documents.
-	MaxTime             *time.Duration // Specifies the maximum amount of time to allow the query to run.
-	Min                 interface{}    // Specifies the inclusive lower bound for a specific index.
-	NoCursorTimeout     *bool          // If true, prevents cursors from timing out after an inactivity period.
-	OplogReplay         *bool          // Adds an option for internal use only and should not be set.
-	Projection          interface{}    // Limits the fields returned for all documents.
-	ReturnKey           *bool          // If true, only returns index keys for all result documents.
-	ShowRecordID        *bool          // If true, a $recordId field with the record identifier will be added to the returned documents.
-	Skip                *int64         // Specifies the number of documents to skip before returning
-	Snapshot            *bool          // If true, prevents the cursor from returning a document more than once because of an intervening write operation.
-	Sort                interface{}    // Specifies the order in which to return results.
+	AllowPartialResults *bool                    // If true, allows partial results to be returned if some shards are down.
+	BatchSize           *int32                   // Specifies the number of documents to return in every batch.
+	Collation           *Collation               // Specifies a collation to be used
+	Comment             interface{}              // Specifies a comment to help trace the operation through the database profiler, currentOp and logs.
+	CursorType          *CursorType              // Specifies the type of cursor to use
+	Hint                interface{}              // Specifies the index to use.
+	Let                 interface{}              // Specifies a document of parameter names and values that can be accessed in the command with $$name.
+	Max                 interface{}              // Sets an exclusive upper bound for a specific index
+	MaxAwaitTime        *time.Duration           // Specifies the maximum amount of time for the server to wait on new documents.
+	MaxTime             *time.Duration           // Specifies the maximum amount of time to allow the query to run.
+	Min                 interface{}              // Specifies the inclusive lower bound for a specific index.
+	NoCursorTimeout     *bool                    // If true, prevents cursors from timing out after an inactivity period.
+	OplogReplay         *bool                    // Adds an option for internal use only and should not be set.
+	Projection          interface{}              // Limits the fields returned for all documents.
+	ReadConcern         *readconcern.ReadConcern // The read concern for the operation. Overrides the read concern of the collection.
+	ReturnKey           *bool                    // If true, only returns index keys for all result documents.
+	ShowRecordID        *bool                    // If true, a $recordId field with the record identifier will be added to the returned documents.
+	Skip                *int64                   // Specifies the number of documents to skip before returning
+	Snapshot            *bool                    // If true, prevents the cursor from returning a document more than once because of an intervening write operation.
+	Sort                interface{}              // Specifies the order in which to return results.
 }
 
 // FindOne creates a new FindOneOptions instance.
@@ -275,9 +327,10 @@ func (f *FindOneOptions) SetCollation(collation *Collation) *FindOneOptions {
 	return f
 }
 
-// SetComment specifies a string to help trace the operation through the database.
-func (f *FindOneOptions) SetComment(comment string) *FindOneOptions {
-	f.Comment = &comment
+// SetComment specifies a comment to help trace the operation through the database profiler,
+// currentOp and logs. Comments other than strings are valid for server versions >= 4.4.
+func (f *FindOneOptions) SetComment(comment interface{}) *FindOneOptions {
+	f.Comment = comment
 	return f
 }
 
@@ -293,6 +346,13 @@ func (f *FindOneOptions) SetHint(hint interface{}) *FindOneOptions {
 	return f
 }
 
+// SetLet specifies a document of parameter names and values that can be accessed in the command
+// with $$name. Valid for server versions >= 5.0.
+func (f *FindOneOptions) SetLet(let interface{}) *FindOneOptions {
+	f.Let = let
+	return f
+}
+
 // SetMax specifies an exclusive upper bound for a specific index.
 func (f *FindOneOptions) SetMax(max interface{}) *FindOneOptions {
 	f.Max = max
@@ -367,6 +427,13 @@ func (f *FindOneOptions) SetSort(sort interface{}) *FindOneOptions {
 	return f
 }
 
+// SetReadConcern specifies a read concern for the operation that overrides the read concern of the
+// collection.
+func (f *FindOneOptions) SetReadConcern(rc *readconcern.ReadConcern) *FindOneOptions {
+	f.ReadConcern = rc
+	return f
+}
+
 // MergeFindOneOptions combines the argued FindOneOptions into a single FindOneOptions in a last-one-wins fashion
 func MergeFindOneOptions(opts ...*FindOneOptions) *FindOneOptions {
 	fo := FindOne()
@@ -392,6 +459,9 @@ func MergeFindOneOptions(opts ...*FindOneOptions) *FindOneOptions {
 		if opt.Hint != nil {
 			fo.Hint = opt.Hint
 		}
+		if opt.Let != nil {
+			fo.Let = opt.Let
+		}
 		if opt.Max != nil {
 			fo.Max = opt.Max
 		}
@@ -428,6 +498,9 @@ func MergeFindOneOptions(opts ...*FindOneOptions) *FindOneOptions {
 		if opt.Sort != nil {
 			fo.Sort = opt.Sort
 		}
+		if opt.ReadConcern != nil {
+			fo.ReadConcern = opt.ReadConcern
+		}
 	}
 
 	return fo
@@ -435,13 +508,17 @@ func MergeFindOneOptions(opts ...*FindOneOptions) *FindOneOptions {
 
 // FindOneAndReplaceOptions represent all possible options to the FindOneAndReplace() function.
 type FindOneAndReplaceOptions struct {
-	BypassDocumentValidation *bool           // If true, allows the write to opt out of document-level validation.
-	Collation                *Collation      // Specifies a collation to be used
-	MaxTime                  *time.Duration  // Specifies the maximum amount of time to allow the query to run.
-	Projection               interface{}     // Limits the fields returned for all documents.
-	ReturnDocument           *ReturnDocument // Specifies whether the original or updated document should be returned.
-	Sort                     interface{}     // Specifies the order in which to return results.
-	Upsert                   *bool           // If true, creates a a new document if no document matches the query.
+	BypassDocumentValidation *bool                      // If true, allows the write to opt out of document-level validation.
+	Collation                *Collation                 // Specifies a collation to be used
+	Comment                  interface{}                // Specifies a comment to help trace the operation through the database profiler, currentOp and logs.
+	Hint                     interface{}                // The index to use for the operation.
+	Let                      interface{}                // Specifies a document of parameter names and values that can be accessed in the command with $$name.
+	MaxTime                  *time.Duration             // Specifies the maximum amount of time to allow the query to run.
+	Projection               interface{}                // Limits the fields returned for all documents.
+	ReturnDocument           *ReturnDocument            // Specifies whether the original or updated document should be returned.
+	Sort                     interface{}                // Specifies the order in which to return results.
+	Upsert                   *bool                      // If true, creates a a new document if no document matches the query.
+	WriteConcern             *writeconcern.WriteConcern // The write concern for the operation. Overrides the write concern of the collection.
 }
 
 // FindOneAndReplace creates a new FindOneAndReplaceOptions instance.
@@ -462,6 +539,27 @@ func (f *FindOneAndReplaceOptions) SetCollation(collation *Collation) *FindOneAn
 	return f
 }
 
+// SetComment specifies a comment to help trace the operation through the database profiler,
+// currentOp and logs. Valid for server versions >= 4.4.
+func (f *FindOneAndReplaceOptions) SetComment(comment interface{}) *FindOneAndReplaceOptions {
+	f.Comment = comment
+	return f
+}
+
+// SetHint specifies the index to use for the operation, either as the index name or as the index
+// specification document. Valid for server versions >= 4.4.
+func (f *FindOneAndReplaceOptions) SetHint(hint interface{}) *FindOneAndReplaceOptions {
+	f.Hint = hint
+	return f
+}
+
+// SetLet specifies a document of parameter names and values that can be accessed in the command
+// with $$name. Valid for server versions >= 5.0.
+func (f *FindOneAndReplaceOptions) SetLet(let interface{}) *FindOneAndReplaceOptions {
+	f.Let = let
+	return f
+}
+
 // SetMaxTime specifies the max time to allow the query to run.
 func (f *FindOneAndReplaceOptions) SetMaxTime(d time.Duration) *FindOneAndReplaceOptions {
 	f.MaxTime = &d
@@ -494,6 +592,13 @@ func (f *FindOneAndReplaceOptions) SetUpsert(b bool) *FindOneAndReplaceOptions {
 	return f
 }
 
+// SetWriteConcern specifies a write concern for the operation that overrides the write concern of
+// the collection.
+func (f *FindOneAndReplaceOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *FindOneAndReplaceOptions {
+	f.WriteConcern = wc
+	return f
+}
+
 // MergeFindOneAndReplaceOptions combines the argued FindOneAndReplaceOptions into a single FindOneAndReplaceOptions in a last-one-wins fashion
 func MergeFindOneAndReplaceOptions(opts ...*FindOneAndReplaceOptions) *FindOneAndReplaceOptions {
 	fo := FindOneAndReplace()
@@ -507,6 +612,15 @@ func MergeFindOneAndReplaceOptions(opts ...*FindOneAndReplaceOptions) *FindOneAn
 		if opt.Collation != nil {
 			fo.Collation = opt.Collation
 		}
+		if opt.Comment != nil {
+			fo.Comment = opt.Comment
+		}
+		if opt.Hint != nil {
+			fo.Hint = opt.Hint
+		}
+		if opt.Let != nil {
+			fo.Let = opt.Let
+		}
 		if opt.MaxTime != nil {
 			fo.MaxTime = opt.MaxTime
 		}
@@ -522,6 +636,9 @@ func MergeFindOneAndReplaceOptions(opts ...*FindOneAndReplaceOptions) *FindOneAn
 		if opt.Upsert != nil {
 			fo.Upsert = opt.Upsert
 		}
+		if opt.WriteConcern != nil {
+			fo.WriteConcern = opt.WriteConcern
+		}
 	}
 
 	return fo
@@ -529,14 +646,18 @@ func MergeFindOneAndReplaceOptions(opts ...*FindOneAndReplaceOptions) *FindOneAn
 
 // FindOneAndUpdateOptions represent all possible options to the FindOneAndUpdate() function.
 type FindOneAndUpdateOptions struct {
-	ArrayFilters             *ArrayFilters   // A set of filters specifying to which array elements an update should apply.
-	BypassDocumentValidation *bool           // If true, allows the write to opt out of document-level validation.
-	Collation                *Collation      // Specifies a collation to be used
-	MaxTime                  *time.Duration  // Specifies the maximum amount of time to allow the query to run.
-	Projection               interface{}     // Limits the fields returned for all documents.
-	ReturnDocument           *ReturnDocument // Specifies whether the original or updated document should be returned.
-	Sort                     interface{}     // Specifies the order in which to return results.
-	Upsert                   *bool           // If true, creates a a new document if no document matches the query.
+	ArrayFilters             *ArrayFilters              // A set of filters specifying to which array elements an update should apply.
+	BypassDocumentValidation *bool                      // If true, allows the write to opt out of document-level validation.
+	Collation                *Collation                 // Specifies a collation to be used
+	Comment                  interface{}                // Specifies a comment to help trace the operation through the database profiler, currentOp and logs.
+	Hint                     interface{}                // The index to use for the operation.
+	Let                      interface{}                // Specifies a document of parameter names and values that can be accessed in the command with $$name.
+	MaxTime                  *time.Duration             // Specifies the maximum amount of time to allow the query to run.
+	Projection               interface{}                // Limits the fields returned for all documents.
+	ReturnDocument           *ReturnDocument            // Specifies whether the original or updated document should be returned.
+	Sort                     interface{}                // Specifies the order in which to return results.
+	Upsert                   *bool                      // If true, creates a a new document if no document matches the query.
+	WriteConcern             *writeconcern.WriteConcern // The write concern for the operation. Overrides the write concern of the collection.
 }
 
 // FindOneAndUpdate creates a new FindOneAndUpdateOptions instance.
@@ -562,6 +683,27 @@ func (f *FindOneAndUpdateOptions) SetCollation(collation *Collation) *FindOneAnd
 	return f
 }
 
+// SetComment specifies a comment to help trace the operation through the database profiler,
+// currentOp and logs. Valid for server versions >= 4.4.
+func (f *FindOneAndUpdateOptions) SetComment(comment interface{}) *FindOneAndUpdateOptions {
+	f.Comment = comment
+	return f
+}
+
+// SetHint specifies the index to use for the operation, either as the index name or as the index
+// specification document. Valid for server versions >= 4.4.
+func (f *FindOneAndUpdateOptions) SetHint(hint interface{}) *FindOneAndUpdateOptions {
+	f.Hint = hint
+	return f
+}
+
+// SetLet specifies a document of parameter names and values that can be accessed in the command
+// with $$name. Valid for server versions >= 5.0.
+func (f *FindOneAndUpdateOptions) SetLet(let interface{}) *FindOneAndUpdateOptions {
+	f.Let = let
+	return f
+}
+
 // SetMaxTime specifies the max time to allow the query to run.
 func (f *FindOneAndUpdateOptions) SetMaxTime(d time.Duration) *FindOneAndUpdateOptions {
 	f.MaxTime = &d
@@ -594,6 +736,13 @@ func (f *FindOneAndUpdateOptions) SetUpsert(b bool) *FindOneAndUpdateOptions {
 	return f
 }
 
+// SetWriteConcern specifies a write concern for the operation that overrides the write concern of
+// the collection.
+func (f *FindOneAndUpdateOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *FindOneAndUpdateOptions {
+	f.WriteConcern = wc
+	return f
+}
+
 // MergeFindOneAndUpdateOptions combines the argued FindOneAndUpdateOptions into a single FindOneAndUpdateOptions in a last-one-wins fashion
 func MergeFindOneAndUpdateOptions(opts ...*FindOneAndUpdateOptions) *FindOneAndUpdateOptions {
 	fo := FindOneAndUpdate()
@@ -610,6 +759,15 @@ func MergeFindOneAndUpdateOptions(opts ...*FindOneAndUpdateOptions) *FindOneAndU
 		if opt.Collation != nil {
 			fo.Collation = opt.Collation
 		}
+		if opt.Comment != nil {
+			fo.Comment = opt.Comment
+		}
+		if opt.Hint != nil {
+			fo.Hint = opt.Hint
+		}
+		if opt.Let != nil {
+			fo.Let = opt.Let
+		}
 		if opt.MaxTime != nil {
 			fo.MaxTime = opt.MaxTime
 		}
@@ -625,6 +783,9 @@ func MergeFindOneAndUpdateOptions(opts ...*FindOneAndUpdateOptions) *FindOneAndU
 		if opt.Upsert != nil {
 			fo.Upsert = opt.Upsert
 		}
+		if opt.WriteConcern != nil {
+			fo.WriteConcern = opt.WriteConcern
+		}
 	}
 
 	return fo
@@ -632,10 +793,14 @@ func MergeFindOneAndUpdateOptions(opts ...*FindOneAndUpdateOptions) *FindOneAndU
 
 // FindOneAndDeleteOptions represent all possible options to the FindOneAndDelete() function.
 type FindOneAndDeleteOptions struct {
-	Collation  *Collation     // Specifies a collation to be used
-	MaxTime    *time.Duration // Specifies the maximum amount of time to allow the query to run.
-	Projection interface{}    // Limits the fields returned for all documents.
-	Sort       interface{}    // Specifies the order in which to return results.
+	Collation    *Collation                 // Specifies a collation to be used
+	Comment      interface{}                // Specifies a comment to help trace the operation through the database profiler, currentOp and logs.
+	Hint         interface{}                // The index to use for the operation.
+	Let          interface{}                // Specifies a document of parameter names and values that can be accessed in the command with $$name.
+	MaxTime      *time.Duration             // Specifies the maximum amount of time to allow the query to run.
+	Projection   interface{}                // Limits the fields returned for all documents.
+	Sort         interface{}                // Specifies the order in which to return results.
+	WriteConcern *writeconcern.WriteConcern // The write concern for the operation. Overrides the write concern of the collection.
 }
 
 // FindOneAndDelete creates a new FindOneAndDeleteOptions instance.
@@ -650,6 +815,27 @@ func (f *FindOneAndDeleteOptions) SetCollation(collation *Collation) *FindOneAnd
 	return f
 }
 
+// SetComment specifies a comment to help trace the operation through the database profiler,
+// currentOp and logs. Valid for server versions >= 4.4.
+func (f *FindOneAndDeleteOptions) SetComment(comment interface{}) *FindOneAndDeleteOptions {
+	f.Comment = comment
+	return f
+}
+
+// SetHint specifies the index to use for the operation, either as the index name or as the index
+// specification document. Valid for server versions >= 4.4.
+func (f *FindOneAndDeleteOptions) SetHint(hint interface{}) *FindOneAndDeleteOptions {
+	f.Hint = hint
+	return f
+}
+
+// SetLet specifies a document of parameter names and values that can be accessed in the command
+// with $$name. Valid for server versions >= 5.0.
+func (f *FindOneAndDeleteOptions) SetLet(let interface{}) *FindOneAndDeleteOptions {
+	f.Let = let
+	return f
+}
+
 // SetMaxTime specifies the max time to allow the query to run.
 func (f *FindOneAndDeleteOptions) SetMaxTime(d time.Duration) *FindOneAndDeleteOptions {
 	f.MaxTime = &d
@@ -668,6 +854,13 @@ func (f *FindOneAndDeleteOptions) SetSort(sort interface{}) *FindOneAndDeleteOpt
 	return f
 }
 
+// SetWriteConcern specifies a write concern for the operation that overrides the write concern of
+// the collection.
+func (f *FindOneAndDeleteOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *FindOneAndDeleteOptions {
+	f.WriteConcern = wc
+	return f
+}
+
 // MergeFindOneAndDeleteOptions combines the argued FindOneAndDeleteOptions into a single FindOneAndDeleteOptions in a last-one-wins fashion
 func MergeFindOneAndDeleteOptions(opts ...*FindOneAndDeleteOptions) *FindOneAndDeleteOptions {
 	fo := FindOneAndDelete()
@@ -678,6 +871,15 @@ func MergeFindOneAndDeleteOptions(opts ...*FindOneAndDeleteOptions) *FindOneAndD
 		if opt.Collation != nil {
 			fo.Collation = opt.Collation
 		}
+		if opt.Comment != nil {
+			fo.Comment = opt.Comment
+		}
+		if opt.Hint != nil {
+			fo.Hint = opt.Hint
+		}
+		if opt.Let != nil {
+			fo.Let = opt.Let
+		}
 		if opt.MaxTime != nil {
 			fo.MaxTime = opt.MaxTime
 		}
@@ -687,6 +889,9 @@ func MergeFindOneAndDeleteOptions(opts ...*FindOneAndDeleteOptions) *FindOneAndD
 		if opt.Sort != nil {
 			fo.Sort = opt.Sort
 		}
+		if opt.WriteConcern != nil {
+			fo.WriteConcern = opt.WriteConcern
+		}
 	}
 
 	return fo
@@ -0,0 +1,43 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+// GetMoreOptions represents options that control which options of the command that creates a
+// cursor are sent again on the getMore commands that fetch its later batches. Options that are not
+// set follow the default rules described on each setter.
+type GetMoreOptions struct {
+	BatchSize    *bool // Whether batchSize is sent on getMore commands.
+	Comment      *bool // Whether comment is sent on getMore commands.
+	MaxAwaitTime *bool // Whether MaxAwaitTime is sent as maxTimeMS on getMore commands.
+}
+
+// GetMore creates a new *GetMoreOptions.
+func GetMore() *GetMoreOptions {
+	return &GetMoreOptions{}
+}
+
+// SetBatchSize specifies whether the batch size is sent on getMore commands. By default it is.
+func (gmo *GetMoreOptions) SetBatchSize(b bool) *GetMoreOptions {
+	gmo.BatchSize = &b
+	return gmo
+}
+
+// SetComment specifies whether the comment is sent on getMore commands. By default it is only sent
+// to servers that accept a comment on getMore, which are MongoDB 4.4 and later.
+func (gmo *GetMoreOptions) SetComment(b bool) *GetMoreOptions {
+	gmo.Comment = &b
+	return gmo
+}
+
+// SetMaxAwaitTime specifies whether MaxAwaitTime is sent as maxTimeMS on getMore commands. It is
+// never sent on the initial command. By default it is sent for tailable await cursors created by
+// Find, which are the only find cursors whose getMore commands accept maxTimeMS, and always for
+// Aggregate.
+func (gmo *GetMoreOptions) SetMaxAwaitTime(b bool) *GetMoreOptions {
+	gmo.MaxAwaitTime = &b
+	return gmo
+}
@@ -32,6 +32,10 @@ type BucketOptions struct {
 	WriteConcern   *writeconcern.WriteConcern // The write concern for the bucket. Defaults to the write concern of the database.
 	ReadConcern    *readconcern.ReadConcern   // The read concern for the bucket. Defaults to the read concern of the database.
 	ReadPreference *readpref.ReadPref         // The read preference for the bucket. Defaults to the read preference of the database.
+
+	// The number of chunk ranges fetched concurrently by DownloadToStream and DownloadToStreamByName.
+	// Defaults to 1, which downloads chunks sequentially.
+	DownloadParallelism *int32
 }
 
 // GridFSBucket creates a new *BucketOptions
@@ -72,6 +76,15 @@ func (b *BucketOptions) SetReadPreference(rp *readpref.ReadPref) *BucketOptions
 	return b
 }
 
+// SetDownloadParallelism sets the number of chunk ranges fetched concurrently when a file is
+// downloaded to a stream. The ranges are written to the stream in order, so at most this many ranges
+// are buffered in memory at once. Fetching several ranges at a time improves throughput on
+// high-latency links. Defaults to 1, which downloads chunks sequentially.
+func (b *BucketOptions) SetDownloadParallelism(n int32) *BucketOptions {
+	b.DownloadParallelism = &n
+	return b
+}
+
 // MergeBucketOptions combines the given *BucketOptions into a single *BucketOptions.
 // If the name or chunk size is not set in any of the given *BucketOptions, the resulting *BucketOptions will have
 // name "fs" and chunk size 255KB.
@@ -97,6 +110,9 @@ func MergeBucketOptions(opts ...*BucketOptions) *BucketOptions {
 		if opt.ReadPreference != nil {
 			b.ReadPreference = opt.ReadPreference
 		}
+		if opt.DownloadParallelism != nil {
+			b.DownloadParallelism = opt.DownloadParallelism
+		}
 	}
 
 	return b
@@ -6,9 +6,12 @@
 
 package options
 
+import "go.mongodb.org/mongo-driver/mongo/writeconcern"
+
 // InsertOneOptions represents all possible options to the InsertOne() function.
 type InsertOneOptions struct {
-	BypassDocumentValidation *bool // If true, allows the write to opt-out of document level validation
+	BypassDocumentValidation *bool                      // If true, allows the write to opt-out of document level validation
+	WriteConcern             *writeconcern.WriteConcern // The write concern for the operation. Overrides the write concern of the collection.
 }
 
 // InsertOne returns a pointer to a new InsertOneOptions
@@ -23,6 +26,13 @@ func (ioo *InsertOneOptions) SetBypassDocumentValidation(b bool) *InsertOneOptio
 	return ioo
 }
 
+// SetWriteConcern specifies a write concern for the operation that overrides the write concern of
+// the collection.
+func (ioo *InsertOneOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *InsertOneOptions {
+	ioo.WriteConcern = wc
+	return ioo
+}
+
 // MergeInsertOneOptions combines the argued InsertOneOptions into a single InsertOneOptions in a last-one-wins fashion
 func MergeInsertOneOptions(opts ...*InsertOneOptions) *InsertOneOptions {
 	ioOpts := InsertOne()
@@ -33,6 +43,9 @@ func MergeInsertOneOptions(opts ...*InsertOneOptions) *InsertOneOptions {
 		if ioo.BypassDocumentValidation != nil {
 			ioOpts.BypassDocumentValidation = ioo.BypassDocumentValidation
 		}
+		if ioo.WriteConcern != nil {
+			ioOpts.WriteConcern = ioo.WriteConcern
+		}
 	}
 
 	return ioOpts
@@ -40,8 +53,9 @@ func MergeInsertOneOptions(opts ...*InsertOneOptions) *InsertOneOptions {
 
 // InsertManyOptions represents all possible options to the InsertMany() function.
 type InsertManyOptions struct {
-	BypassDocumentValidation *bool // If true, allows the write to opt-out of document level validation
-	Ordered                  *bool // If true, when an insert fails, return without performing the remaining inserts. Defaults to true.
+	BypassDocumentValidation *bool                      // If true, allows the write to opt-out of document level validation
+	Ordered                  *bool                      // If true, when an insert fails, return without performing the remaining inserts. Defaults to true.
+	WriteConcern             *writeconcern.WriteConcern // The write concern for the operation. Overrides the write concern of the collection.
 }
 
 // InsertMany returns a pointer to a new InsertManyOptions
@@ -65,6 +79,13 @@ func (imo *InsertManyOptions) SetOrdered(b bool) *InsertManyOptions {
 	return imo
 }
 
+// SetWriteConcern specifies a write concern for the operation that overrides the write concern of
+// the collection.
+func (imo *InsertManyOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *InsertManyOptions {
+	imo.WriteConcern = wc
+	return imo
+}
+
 // MergeInsertManyOptions combines the argued InsertManyOptions into a single InsertManyOptions in a last-one-wins fashion
 func MergeInsertManyOptions(opts ...*InsertManyOptions) *InsertManyOptions {
 	imOpts := InsertMany()
@@ -78,6 +99,9 @@ func MergeInsertManyOptions(opts ...*InsertManyOptions) *InsertManyOptions {
 		if imo.Ordered != nil {
 			imOpts.Ordered = imo.Ordered
 		}
+		if imo.WriteConcern != nil {
+			imOpts.WriteConcern = imo.WriteConcern
+		}
 	}
 
 	return imOpts
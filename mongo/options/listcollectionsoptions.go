@@ -8,7 +8,9 @@ package options
 
 // ListCollectionsOptions represents all possible options for a listCollections command.
 type ListCollectionsOptions struct {
-	NameOnly *bool // If true, only the collection names will be returned.
+	AuthorizedCollections *bool  // If true, only the collections the user is authorized to use will be returned.
+	BatchSize             *int32 // The number of collections returned in each batch.
+	NameOnly              *bool  // If true, only the collection names will be returned.
 }
 
 // ListCollections creates a new *ListCollectionsOptions
@@ -16,6 +18,20 @@ func ListCollections() *ListCollectionsOptions {
 	return &ListCollectionsOptions{}
 }
 
+// SetAuthorizedCollections specifies whether to return only the collections the user has
+// privileges for, allowing users without the listCollections privilege to list them. It takes
+// effect when NameOnly is also set and requires MongoDB 4.0 or later.
+func (lc *ListCollectionsOptions) SetAuthorizedCollections(b bool) *ListCollectionsOptions {
+	lc.AuthorizedCollections = &b
+	return lc
+}
+
+// SetBatchSize specifies the number of collections returned in each batch of the cursor.
+func (lc *ListCollectionsOptions) SetBatchSize(size int32) *ListCollectionsOptions {
+	lc.BatchSize = &size
+	return lc
+}
+
 // SetNameOnly specifies whether to return only the collection names.
 func (lc *ListCollectionsOptions) SetNameOnly(b bool) *ListCollectionsOptions {
 	lc.NameOnly = &b
@@ -30,6 +46,12 @@ func MergeListCollectionsOptions(opts ...*ListCollectionsOptions) *ListCollectio
 		if opt == nil {
 			continue
 		}
+		if opt.AuthorizedCollections != nil {
+			lc.AuthorizedCollections = opt.AuthorizedCollections
+		}
+		if opt.BatchSize != nil {
+			lc.BatchSize = opt.BatchSize
+		}
 		if opt.NameOnly != nil {
 			lc.NameOnly = opt.NameOnly
 		}
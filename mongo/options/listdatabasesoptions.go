@@ -8,7 +8,8 @@ package options
 
 // ListDatabasesOptions represents all possible options for a listDatabases command.
 type ListDatabasesOptions struct {
-	NameOnly *bool // If true, only the database names will be returned.
+	AuthorizedDatabases *bool // If true, only the databases the user is authorized to use will be returned.
+	NameOnly            *bool // If true, only the database names will be returned.
 }
 
 // ListDatabases creates a new *ListDatabasesOptions
@@ -16,6 +17,14 @@ func ListDatabases() *ListDatabasesOptions {
 	return &ListDatabasesOptions{}
 }
 
+// SetAuthorizedDatabases specifies whether to return only the databases the user has privileges
+// for. If it is not set, MongoDB 4.0.5 and later return them by default for users without the
+// listDatabases privilege.
+func (ld *ListDatabasesOptions) SetAuthorizedDatabases(b bool) *ListDatabasesOptions {
+	ld.AuthorizedDatabases = &b
+	return ld
+}
+
 // SetNameOnly specifies whether to return only the database names.
 func (ld *ListDatabasesOptions) SetNameOnly(b bool) *ListDatabasesOptions {
 	ld.NameOnly = &b
@@ -27,9 +36,12 @@ func (ld *ListDatabasesOptions) SetNameOnly(b bool) *ListDatabasesOptions {
 func MergeListDatabasesOptions(opts ...*ListDatabasesOptions) *ListDatabasesOptions {
 	ld := ListDatabases()
 	for _, opt := range opts {
-		if opts == nil {
+		if opt == nil {
 			continue
 		}
+		if opt.AuthorizedDatabases != nil {
+			ld.AuthorizedDatabases = opt.AuthorizedDatabases
+		}
 		if opt.NameOnly != nil {
 			ld.NameOnly = opt.NameOnly
 		}
@@ -0,0 +1,83 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"go.mongodb.org/mongo-driver/logger"
+)
+
+// LoggerOptions represent options used to configure the structured logging of a client. Settings
+// that are not specified are taken from the MONGODB_LOG_* environment variables.
+type LoggerOptions struct {
+	ComponentLevels   map[logger.Component]logger.Level // The level of each component. logger.ComponentAll sets the level of the components that are not specified.
+	Sink              logger.Sink                       // The destination of log messages. Defaults to standard error.
+	MaxDocumentLength *uint                             // The length at which logged documents are truncated. Defaults to 1000.
+
+	// If true, logged documents are truncated without breaking their structure so that they remain
+	// valid extended JSON. See logger.TruncateDocument.
+	StructuredTruncation *bool
+}
+
+// Logger creates a new LoggerOptions instance.
+func Logger() *LoggerOptions {
+	return &LoggerOptions{}
+}
+
+// SetComponentLevel specifies the level of messages logged for component.
+func (lo *LoggerOptions) SetComponentLevel(component logger.Component, level logger.Level) *LoggerOptions {
+	if lo.ComponentLevels == nil {
+		lo.ComponentLevels = make(map[logger.Component]logger.Level)
+	}
+	lo.ComponentLevels[component] = level
+	return lo
+}
+
+// SetSink specifies the destination of log messages.
+func (lo *LoggerOptions) SetSink(sink logger.Sink) *LoggerOptions {
+	lo.Sink = sink
+	return lo
+}
+
+// SetMaxDocumentLength specifies the length at which logged documents, such as commands and
+// replies, are truncated.
+func (lo *LoggerOptions) SetMaxDocumentLength(length uint) *LoggerOptions {
+	lo.MaxDocumentLength = &length
+	return lo
+}
+
+// SetStructuredTruncation specifies whether logged documents that are longer than the maximum
+// document length are truncated by dropping the tails of their arrays and documents and shortening
+// long strings, so that they remain valid extended JSON, instead of being cut at the maximum length.
+func (lo *LoggerOptions) SetStructuredTruncation(b bool) *LoggerOptions {
+	lo.StructuredTruncation = &b
+	return lo
+}
+
+// MergeLoggerOptions combines the given *LoggerOptions into a single *LoggerOptions in a last one
+// wins fashion. Component levels are merged per component.
+func MergeLoggerOptions(opts ...*LoggerOptions) *LoggerOptions {
+	lo := Logger()
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		for component, level := range opt.ComponentLevels {
+			lo.SetComponentLevel(component, level)
+		}
+		if opt.Sink != nil {
+			lo.Sink = opt.Sink
+		}
+		if opt.MaxDocumentLength != nil {
+			lo.MaxDocumentLength = opt.MaxDocumentLength
+		}
+		if opt.StructuredTruncation != nil {
+			lo.StructuredTruncation = opt.StructuredTruncation
+		}
+	}
+
+	return lo
+}
@@ -100,6 +100,20 @@ const (
 	UpdateLookup FullDocument = "updateLookup"
 )
 
+// ExplainVerbosity specifies how much information the explain command returns about the execution of
+// the explained command.
+type ExplainVerbosity string
+
+const (
+	// QueryPlanner returns the plan chosen by the query optimizer without running it.
+	QueryPlanner ExplainVerbosity = "queryPlanner"
+	// ExecutionStats also runs the chosen plan and returns statistics about its execution.
+	ExecutionStats ExplainVerbosity = "executionStats"
+	// AllPlansExecution also returns the statistics of the rejected plans gathered while the plan was
+	// chosen.
+	AllPlansExecution ExplainVerbosity = "allPlansExecution"
+)
+
 // ArrayFilters is used to hold filters for the array filters CRUD option. If a registry is nil, bson.DefaultRegistry
 // will be used when converting the filter interfaces to BSON.
 type ArrayFilters struct {
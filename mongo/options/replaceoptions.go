@@ -6,11 +6,17 @@
 
 package options
 
+import "go.mongodb.org/mongo-driver/mongo/writeconcern"
+
 // ReplaceOptions represents all possible options to the ReplaceOne() function.
 type ReplaceOptions struct {
-	BypassDocumentValidation *bool      // If true, allows the write to opt-out of document level validation
-	Collation                *Collation // Specifies a collation
-	Upsert                   *bool      // When true, creates a new document if no document matches the query
+	BypassDocumentValidation *bool                      // If true, allows the write to opt-out of document level validation
+	Collation                *Collation                 // Specifies a collation
+	Comment                  interface{}                // Specifies a comment to help trace the operation through the database profiler, currentOp and logs.
+	Hint                     interface{}                // The index to use for the operation.
+	Let                      interface{}                // Specifies a document of parameter names and values that can be accessed in the command with $$name.
+	Upsert                   *bool                      // When true, creates a new document if no document matches the query
+	WriteConcern             *writeconcern.WriteConcern // The write concern for the operation. Overrides the write concern of the collection.
 }
 
 // Replace returns a pointer to a new ReplaceOptions
@@ -32,12 +38,40 @@ func (ro *ReplaceOptions) SetCollation(c *Collation) *ReplaceOptions {
 	return ro
 }
 
+// SetComment specifies a comment to help trace the operation through the database profiler,
+// currentOp and logs. Valid for server versions >= 4.4.
+func (ro *ReplaceOptions) SetComment(comment interface{}) *ReplaceOptions {
+	ro.Comment = comment
+	return ro
+}
+
+// SetHint specifies the index to use for the operation, either as the index name or as the index
+// specification document. Valid for server versions >= 4.2.
+func (ro *ReplaceOptions) SetHint(hint interface{}) *ReplaceOptions {
+	ro.Hint = hint
+	return ro
+}
+
+// SetLet specifies a document of parameter names and values that can be accessed in the command
+// with $$name. Valid for server versions >= 5.0.
+func (ro *ReplaceOptions) SetLet(let interface{}) *ReplaceOptions {
+	ro.Let = let
+	return ro
+}
+
 // SetUpsert allows the creation of a new document if not document matches the query
 func (ro *ReplaceOptions) SetUpsert(b bool) *ReplaceOptions {
 	ro.Upsert = &b
 	return ro
 }
 
+// SetWriteConcern specifies a write concern for the operation that overrides the write concern of
+// the collection.
+func (ro *ReplaceOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *ReplaceOptions {
+	ro.WriteConcern = wc
+	return ro
+}
+
 // MergeReplaceOptions combines the argued ReplaceOptions into a single ReplaceOptions in a last-one-wins fashion
 func MergeReplaceOptions(opts ...*ReplaceOptions) *ReplaceOptions {
 	rOpts := Replace()
@@ -51,9 +85,21 @@ func MergeReplaceOptions(opts ...*ReplaceOptions) *ReplaceOptions {
 		if ro.Collation != nil {
 			rOpts.Collation = ro.Collation
 		}
+		if ro.Comment != nil {
+			rOpts.Comment = ro.Comment
+		}
+		if ro.Hint != nil {
+			rOpts.Hint = ro.Hint
+		}
+		if ro.Let != nil {
+			rOpts.Let = ro.Let
+		}
 		if ro.Upsert != nil {
 			rOpts.Upsert = ro.Upsert
 		}
+		if ro.WriteConcern != nil {
+			rOpts.WriteConcern = ro.WriteConcern
+		}
 	}
 
 	return rOpts
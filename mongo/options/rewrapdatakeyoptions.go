@@ -0,0 +1,53 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+// RewrapManyDataKeyOptions represents all possible options used to rewrap data keys.
+type RewrapManyDataKeyOptions struct {
+	Provider  *string     // The KMS provider of the new master key. Defaults to the current provider of each key.
+	MasterKey interface{} // The new master key document. Requires Provider.
+}
+
+// RewrapManyDataKey creates a new *RewrapManyDataKeyOptions.
+func RewrapManyDataKey() *RewrapManyDataKeyOptions {
+	return &RewrapManyDataKeyOptions{}
+}
+
+// SetProvider specifies the KMS provider, either "aws" or "local", of the master key that the data
+// keys are encrypted with after rewrapping. If it is not set the keys are encrypted again with
+// their current master keys.
+func (rmdko *RewrapManyDataKeyOptions) SetProvider(provider string) *RewrapManyDataKeyOptions {
+	rmdko.Provider = &provider
+	return rmdko
+}
+
+// SetMasterKey specifies the master key that the data keys are encrypted with after rewrapping. It
+// has the same format as DataKeyOptions.MasterKey.
+func (rmdko *RewrapManyDataKeyOptions) SetMasterKey(masterKey interface{}) *RewrapManyDataKeyOptions {
+	rmdko.MasterKey = masterKey
+	return rmdko
+}
+
+// MergeRewrapManyDataKeyOptions combines the given *RewrapManyDataKeyOptions into a single
+// *RewrapManyDataKeyOptions in a last one wins fashion.
+func MergeRewrapManyDataKeyOptions(opts ...*RewrapManyDataKeyOptions) *RewrapManyDataKeyOptions {
+	rmdko := RewrapManyDataKey()
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		if opt.Provider != nil {
+			rmdko.Provider = opt.Provider
+		}
+		if opt.MasterKey != nil {
+			rmdko.MasterKey = opt.MasterKey
+		}
+	}
+
+	return rmdko
+}
@@ -0,0 +1,52 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import "go.mongodb.org/mongo-driver/event"
+
+// RouterOptions represents all possible options to the NewRouter() function.
+type RouterOptions struct {
+	Monitor     *event.CommandMonitor // A command monitor shared by all clients of the router.
+	PoolMonitor *event.PoolMonitor    // A connection pool monitor shared by all clients of the router.
+}
+
+// Router returns a pointer to a new RouterOptions
+func Router() *RouterOptions {
+	return &RouterOptions{}
+}
+
+// SetMonitor specifies a command monitor that sees the commands of every client of the router. It
+// runs in addition to any monitor set in the options of a client.
+func (ro *RouterOptions) SetMonitor(m *event.CommandMonitor) *RouterOptions {
+	ro.Monitor = m
+	return ro
+}
+
+// SetPoolMonitor specifies a connection pool monitor that sees the pool events of every client of
+// the router. It runs in addition to any pool monitor set in the options of a client.
+func (ro *RouterOptions) SetPoolMonitor(m *event.PoolMonitor) *RouterOptions {
+	ro.PoolMonitor = m
+	return ro
+}
+
+// MergeRouterOptions combines the argued RouterOptions into a single RouterOptions in a last-one-wins fashion
+func MergeRouterOptions(opts ...*RouterOptions) *RouterOptions {
+	routerOpts := Router()
+	for _, ro := range opts {
+		if ro == nil {
+			continue
+		}
+		if ro.Monitor != nil {
+			routerOpts.Monitor = ro.Monitor
+		}
+		if ro.PoolMonitor != nil {
+			routerOpts.PoolMonitor = ro.PoolMonitor
+		}
+	}
+
+	return routerOpts
+}
@@ -6,11 +6,20 @@
 
 package options
 
-import "go.mongodb.org/mongo-driver/mongo/readpref"
+import (
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
 
 // RunCmdOptions represents all possible options for a runCommand operation.
 type RunCmdOptions struct {
-	ReadPreference *readpref.ReadPref // The read preference for the operation.
+	ReadPreference *readpref.ReadPref         // The read preference for the operation.
+	ReadConcern    *readconcern.ReadConcern   // The read concern added to the command.
+	WriteConcern   *writeconcern.WriteConcern // The write concern added to the command.
+	// If true, the command is sent with maxTimeMS set to the time remaining before the deadline of
+	// its context, if any, so that the server stops working on it once the caller gave up waiting.
+	MaxTimeFromDeadline *bool
 }
 
 // RunCmd creates a new *RunCmdOptions
@@ -24,6 +33,31 @@ func (rc *RunCmdOptions) SetReadPreference(rp *readpref.ReadPref) *RunCmdOptions
 	return rc
 }
 
+// SetReadConcern sets the read concern added to the command. The command must not already contain
+// a readConcern field. Neither getMore commands nor commands run in a transaction accept a read
+// concern.
+func (rc *RunCmdOptions) SetReadConcern(concern *readconcern.ReadConcern) *RunCmdOptions {
+	rc.ReadConcern = concern
+	return rc
+}
+
+// SetWriteConcern sets the write concern added to the command. The command must not already contain
+// a writeConcern field. Neither getMore commands nor commands run in a transaction accept a write
+// concern.
+func (rc *RunCmdOptions) SetWriteConcern(concern *writeconcern.WriteConcern) *RunCmdOptions {
+	rc.WriteConcern = concern
+	return rc
+}
+
+// SetMaxTimeFromDeadline sets whether the command is sent with maxTimeMS set to the time remaining
+// before the deadline of its context. It has no effect if the context has no deadline or the command
+// already contains a maxTimeMS field. Commands of a client with a timeout always derive maxTimeMS
+// from the deadline.
+func (rc *RunCmdOptions) SetMaxTimeFromDeadline(b bool) *RunCmdOptions {
+	rc.MaxTimeFromDeadline = &b
+	return rc
+}
+
 // MergeRunCmdOptions combines the given *RunCmdOptions into one *RunCmdOptions in a last one wins fashion.
 func MergeRunCmdOptions(opts ...*RunCmdOptions) *RunCmdOptions {
 	rc := RunCmd()
@@ -34,6 +68,15 @@ func MergeRunCmdOptions(opts ...*RunCmdOptions) *RunCmdOptions {
 		if opt.ReadPreference != nil {
 			rc.ReadPreference = opt.ReadPreference
 		}
+		if opt.ReadConcern != nil {
+			rc.ReadConcern = opt.ReadConcern
+		}
+		if opt.WriteConcern != nil {
+			rc.WriteConcern = opt.WriteConcern
+		}
+		if opt.MaxTimeFromDeadline != nil {
+			rc.MaxTimeFromDeadline = opt.MaxTimeFromDeadline
+		}
 	}
 
 	return rc
@@ -0,0 +1,73 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import (
+	"time"
+)
+
+// DefaultSearchIndexPollInterval is the default time between the first two status checks of a
+// SearchIndexView.WaitQueryable call.
+var DefaultSearchIndexPollInterval = 500 * time.Millisecond
+
+// DefaultSearchIndexMaxPollInterval is the default upper bound of the time between status checks
+// of a SearchIndexView.WaitQueryable call.
+var DefaultSearchIndexMaxPollInterval = 10 * time.Second
+
+// WaitSearchIndexOptions represents all possible options to the SearchIndexView.WaitQueryable() function.
+type WaitSearchIndexOptions struct {
+	PollInterval    *time.Duration // The time between the first two status checks. Doubled after every check.
+	MaxPollInterval *time.Duration // The maximum time to wait between status checks.
+	Timeout         *time.Duration // The maximum amount of time to wait for the index.
+}
+
+// WaitSearchIndex returns a pointer to a new WaitSearchIndexOptions
+func WaitSearchIndex() *WaitSearchIndexOptions {
+	return &WaitSearchIndexOptions{}
+}
+
+// SetPollInterval specifies the time between the first two status checks. The interval is
+// doubled after every check, up to the maximum poll interval.
+func (wo *WaitSearchIndexOptions) SetPollInterval(d time.Duration) *WaitSearchIndexOptions {
+	wo.PollInterval = &d
+	return wo
+}
+
+// SetMaxPollInterval specifies the maximum time to wait between status checks.
+func (wo *WaitSearchIndexOptions) SetMaxPollInterval(d time.Duration) *WaitSearchIndexOptions {
+	wo.MaxPollInterval = &d
+	return wo
+}
+
+// SetTimeout specifies the maximum amount of time to wait for the index. The deadline of the
+// context passed to WaitQueryable also applies.
+func (wo *WaitSearchIndexOptions) SetTimeout(d time.Duration) *WaitSearchIndexOptions {
+	wo.Timeout = &d
+	return wo
+}
+
+// MergeWaitSearchIndexOptions combines the given *WaitSearchIndexOptions into a single
+// *WaitSearchIndexOptions in a last one wins fashion.
+func MergeWaitSearchIndexOptions(opts ...*WaitSearchIndexOptions) *WaitSearchIndexOptions {
+	wOpts := WaitSearchIndex()
+	for _, wo := range opts {
+		if wo == nil {
+			continue
+		}
+		if wo.PollInterval != nil {
+			wOpts.PollInterval = wo.PollInterval
+		}
+		if wo.MaxPollInterval != nil {
+			wOpts.MaxPollInterval = wo.MaxPollInterval
+		}
+		if wo.Timeout != nil {
+			wOpts.Timeout = wo.Timeout
+		}
+	}
+
+	return wOpts
+}
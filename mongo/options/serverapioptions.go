@@ -0,0 +1,39 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+// ServerAPIVersion1 is the first version of the server API.
+const ServerAPIVersion1 = "1"
+
+// ServerAPIOptions represents the options used to declare the server API version a client is
+// written against. Servers that support the declared version guarantee that the behavior of the
+// commands it includes does not change across server upgrades.
+type ServerAPIOptions struct {
+	ServerAPIVersion  string
+	Strict            *bool
+	DeprecationErrors *bool
+}
+
+// ServerAPI creates a new *ServerAPIOptions declaring the given server API version, such as
+// ServerAPIVersion1.
+func ServerAPI(version string) *ServerAPIOptions {
+	return &ServerAPIOptions{ServerAPIVersion: version}
+}
+
+// SetStrict specifies whether the server returns an error for commands that are not part of the
+// declared API version. The default is false.
+func (s *ServerAPIOptions) SetStrict(strict bool) *ServerAPIOptions {
+	s.Strict = &strict
+	return s
+}
+
+// SetDeprecationErrors specifies whether the server returns an error for commands and behaviors
+// that are deprecated in the declared API version. The default is false.
+func (s *ServerAPIOptions) SetDeprecationErrors(deprecationErrors bool) *ServerAPIOptions {
+	s.DeprecationErrors = &deprecationErrors
+	return s
+}
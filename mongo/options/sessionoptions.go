@@ -7,6 +7,8 @@
 package options
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
@@ -15,12 +17,30 @@ import (
 // DefaultCausalConsistency is the default value for the CausalConsistency option.
 var DefaultCausalConsistency = true
 
+// SessionOperation describes a command sent in a session. Namespace is the namespace of the
+// collection the command operates on, such as "db.coll", or the name of the database for commands
+// that do not operate on a collection, such as commitTransaction. Number is the position of the
+// command among those sent in the session, starting at 1.
+type SessionOperation struct {
+	Number      int64
+	Namespace   string
+	CommandName string
+	Time        time.Time
+}
+
+// SessionAuditHook is called with each command sent in a session, before it is sent. It can be used
+// to audit the operations of a multi-step business transaction from end to end. Because a session
+// must not be used concurrently, the hook is never called concurrently for the same session.
+type SessionAuditHook func(SessionOperation)
+
 // SessionOptions represents all possible options for creating a new session.
 type SessionOptions struct {
 	CausalConsistency     *bool                      // Specifies if reads should be causally consistent. Defaults to true.
 	DefaultReadConcern    *readconcern.ReadConcern   // The default read concern for transactions started in the session.
 	DefaultReadPreference *readpref.ReadPref         // The default read preference for transactions started in the session.
 	DefaultWriteConcern   *writeconcern.WriteConcern // The default write concern for transactions started in the session.
+	Snapshot              *bool                      // Specifies if reads should read from a consistent snapshot. Defaults to false.
+	AuditHook             SessionAuditHook           // Called with each command sent in the session.
 }
 
 // Session creates a new *SessionOptions
@@ -54,6 +74,23 @@ func (s *SessionOptions) SetDefaultWriteConcern(wc *writeconcern.WriteConcern) *
 	return s
 }
 
+// SetSnapshot specifies if find, aggregate and distinct operations in a session should read from
+// the same point-in-time snapshot of the data, which is chosen by the first such read. Snapshot
+// sessions require MongoDB 5.0 or later, are not causally consistent and cannot be used for
+// transactions. Defaults to false.
+func (s *SessionOptions) SetSnapshot(b bool) *SessionOptions {
+	s.Snapshot = &b
+	return s
+}
+
+// SetAuditHook specifies a hook that is called with each command sent in a session, including the
+// getMore commands of cursors, the commands that commit and abort transactions and the commands
+// that are sent again when an operation is retried.
+func (s *SessionOptions) SetAuditHook(hook SessionAuditHook) *SessionOptions {
+	s.AuditHook = hook
+	return s
+}
+
 // MergeSessionOptions combines the given *SessionOptions into a single *SessionOptions in a last one wins fashion.
 func MergeSessionOptions(opts ...*SessionOptions) *SessionOptions {
 	s := Session()
@@ -73,6 +110,12 @@ func MergeSessionOptions(opts ...*SessionOptions) *SessionOptions {
 		if opt.DefaultWriteConcern != nil {
 			s.DefaultWriteConcern = opt.DefaultWriteConcern
 		}
+		if opt.Snapshot != nil {
+			s.Snapshot = opt.Snapshot
+		}
+		if opt.AuditHook != nil {
+			s.AuditHook = opt.AuditHook
+		}
 	}
 
 	return s
@@ -0,0 +1,76 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package options
+
+import "time"
+
+// TailOptions represents all possible options to the Tail() function.
+type TailOptions struct {
+	BufferSize   *int           // The number of documents buffered in the channel returned by Tail.
+	MaxAwaitTime *time.Duration // The maximum time the server waits for new documents before replying to a getMore.
+	RestartDelay *time.Duration // The time waited before re-establishing a cursor that was killed or exhausted.
+	StartAfter   interface{}    // The _id of the document after which tailing starts.
+}
+
+// Tail returns a pointer to a new TailOptions
+func Tail() *TailOptions {
+	return &TailOptions{}
+}
+
+// SetBufferSize specifies the number of documents buffered in the channel returned by Tail. The
+// default is 0, which delivers documents unbuffered.
+func (to *TailOptions) SetBufferSize(n int) *TailOptions {
+	to.BufferSize = &n
+	return to
+}
+
+// SetMaxAwaitTime specifies the maximum time the server waits for new documents before replying to
+// a getMore of the tailable cursor.
+func (to *TailOptions) SetMaxAwaitTime(d time.Duration) *TailOptions {
+	to.MaxAwaitTime = &d
+	return to
+}
+
+// SetRestartDelay specifies the time waited before re-establishing the cursor after it was killed,
+// lost its position in the capped collection, or was exhausted because the collection was empty.
+// The default is one second.
+func (to *TailOptions) SetRestartDelay(d time.Duration) *TailOptions {
+	to.RestartDelay = &d
+	return to
+}
+
+// SetStartAfter specifies the _id of the document after which tailing starts, such as the _id of
+// the last document processed before a restart of the application. By default, tailing starts at
+// the beginning of the collection.
+func (to *TailOptions) SetStartAfter(id interface{}) *TailOptions {
+	to.StartAfter = id
+	return to
+}
+
+// MergeTailOptions combines the argued TailOptions into a single TailOptions in a last-one-wins fashion
+func MergeTailOptions(opts ...*TailOptions) *TailOptions {
+	tailOpts := Tail()
+	for _, to := range opts {
+		if to == nil {
+			continue
+		}
+		if to.BufferSize != nil {
+			tailOpts.BufferSize = to.BufferSize
+		}
+		if to.MaxAwaitTime != nil {
+			tailOpts.MaxAwaitTime = to.MaxAwaitTime
+		}
+		if to.RestartDelay != nil {
+			tailOpts.RestartDelay = to.RestartDelay
+		}
+		if to.StartAfter != nil {
+			tailOpts.StartAfter = to.StartAfter
+		}
+	}
+
+	return tailOpts
+}
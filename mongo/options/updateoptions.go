@@ -6,12 +6,18 @@
 
 package options
 
+import "go.mongodb.org/mongo-driver/mongo/writeconcern"
+
 // UpdateOptions represents all possible options to the UpdateOne() and UpdateMany() functions.
 type UpdateOptions struct {
-	ArrayFilters             *ArrayFilters // A set of filters specifying to which array elements an update should apply
-	BypassDocumentValidation *bool         // If true, allows the write to opt-out of document level validation
-	Collation                *Collation    // Specifies a collation
-	Upsert                   *bool         // When true, creates a new document if no document matches the query
+	ArrayFilters             *ArrayFilters              // A set of filters specifying to which array elements an update should apply
+	BypassDocumentValidation *bool                      // If true, allows the write to opt-out of document level validation
+	Collation                *Collation                 // Specifies a collation
+	Comment                  interface{}                // Specifies a comment to help trace the operation through the database profiler, currentOp and logs.
+	Hint                     interface{}                // The index to use for the operation.
+	Let                      interface{}                // Specifies a document of parameter names and values that can be accessed in the command with $$name.
+	Upsert                   *bool                      // When true, creates a new document if no document matches the query
+	WriteConcern             *writeconcern.WriteConcern // The write concern for the operation. Overrides the write concern of the collection.
 }
 
 // Update returns a pointer to a new UpdateOptions
@@ -40,12 +46,40 @@ func (uo *UpdateOptions) SetCollation(c *Collation) *UpdateOptions {
 	return uo
 }
 
+// SetComment specifies a comment to help trace the operation through the database profiler,
+// currentOp and logs. Valid for server versions >= 4.4.
+func (uo *UpdateOptions) SetComment(comment interface{}) *UpdateOptions {
+	uo.Comment = comment
+	return uo
+}
+
+// SetHint specifies the index to use for the operation, either as the index name or as the index
+// specification document. Valid for server versions >= 4.2.
+func (uo *UpdateOptions) SetHint(hint interface{}) *UpdateOptions {
+	uo.Hint = hint
+	return uo
+}
+
+// SetLet specifies a document of parameter names and values that can be accessed in the command
+// with $$name. Valid for server versions >= 5.0.
+func (uo *UpdateOptions) SetLet(let interface{}) *UpdateOptions {
+	uo.Let = let
+	return uo
+}
+
 // SetUpsert allows the creation of a new document if not document matches the query
 func (uo *UpdateOptions) SetUpsert(b bool) *UpdateOptions {
 	uo.Upsert = &b
 	return uo
 }
 
+// SetWriteConcern specifies a write concern for the operation that overrides the write concern of
+// the collection.
+func (uo *UpdateOptions) SetWriteConcern(wc *writeconcern.WriteConcern) *UpdateOptions {
+	uo.WriteConcern = wc
+	return uo
+}
+
 // MergeUpdateOptions combines the argued UpdateOptions into a single UpdateOptions in a last-one-wins fashion
 func MergeUpdateOptions(opts ...*UpdateOptions) *UpdateOptions {
 	uOpts := Update()
@@ -62,9 +96,21 @@ func MergeUpdateOptions(opts ...*UpdateOptions) *UpdateOptions {
 		if uo.Collation != nil {
 			uOpts.Collation = uo.Collation
 		}
+		if uo.Comment != nil {
+			uOpts.Comment = uo.Comment
+		}
+		if uo.Hint != nil {
+			uOpts.Hint = uo.Hint
+		}
+		if uo.Let != nil {
+			uOpts.Let = uo.Let
+		}
 		if uo.Upsert != nil {
 			uOpts.Upsert = uo.Upsert
 		}
+		if uo.WriteConcern != nil {
+			uOpts.WriteConcern = uo.WriteConcern
+		}
 	}
 
 	return uOpts
@@ -0,0 +1,314 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package pipeline provides constructors for aggregation pipeline stages. Each constructor returns
+// a single stage document and New combines stages into a pipeline that can be passed to Aggregate
+// or Watch:
+//
+//	p := pipeline.New(
+//		pipeline.Match(bson.D{{"status", "A"}}),
+//		pipeline.Group("$cust_id", pipeline.Sum("total", "$amount")),
+//		pipeline.Sort(pipeline.Descending("total")),
+//		pipeline.Limit(10),
+//	)
+//	cursor, err := coll.Aggregate(ctx, p)
+package pipeline // import "go.mongodb.org/mongo-driver/mongo/pipeline"
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// New creates an aggregation pipeline from the given stages.
+func New(stages ...bson.D) bson.A {
+	p := make(bson.A, 0, len(stages))
+	for _, stage := range stages {
+		p = append(p, stage)
+	}
+	return p
+}
+
+// Match creates a $match stage that filters documents using the given query filter.
+func Match(filter interface{}) bson.D {
+	return bson.D{{"$match", filter}}
+}
+
+// Accumulator is a computed field in a $group stage.
+type Accumulator struct {
+	Field      string      // The name of the field in the output documents.
+	Operator   string      // The accumulator operator, including the leading "$", e.g. "$sum".
+	Expression interface{} // The expression the operator is applied to.
+}
+
+// Sum creates an Accumulator that stores the sum of expr in field.
+func Sum(field string, expr interface{}) Accumulator {
+	return Accumulator{Field: field, Operator: "$sum", Expression: expr}
+}
+
+// Avg creates an Accumulator that stores the average of expr in field.
+func Avg(field string, expr interface{}) Accumulator {
+	return Accumulator{Field: field, Operator: "$avg", Expression: expr}
+}
+
+// Min creates an Accumulator that stores the minimum value of expr in field.
+func Min(field string, expr interface{}) Accumulator {
+	return Accumulator{Field: field, Operator: "$min", Expression: expr}
+}
+
+// Max creates an Accumulator that stores the maximum value of expr in field.
+func Max(field string, expr interface{}) Accumulator {
+	return Accumulator{Field: field, Operator: "$max", Expression: expr}
+}
+
+// First creates an Accumulator that stores the value of expr for the first document in each group
+// in field.
+func First(field string, expr interface{}) Accumulator {
+	return Accumulator{Field: field, Operator: "$first", Expression: expr}
+}
+
+// Last creates an Accumulator that stores the value of expr for the last document in each group in
+// field.
+func Last(field string, expr interface{}) Accumulator {
+	return Accumulator{Field: field, Operator: "$last", Expression: expr}
+}
+
+// Push creates an Accumulator that stores an array of the values of expr in field.
+func Push(field string, expr interface{}) Accumulator {
+	return Accumulator{Field: field, Operator: "$push", Expression: expr}
+}
+
+// AddToSet creates an Accumulator that stores an array of the unique values of expr in field.
+func AddToSet(field string, expr interface{}) Accumulator {
+	return Accumulator{Field: field, Operator: "$addToSet", Expression: expr}
+}
+
+// Group creates a $group stage that groups documents by the id expression and computes the given
+// accumulators for each group. Use nil as the id to compute the accumulators over all documents.
+func Group(id interface{}, accumulators ...Accumulator) bson.D {
+	group := bson.D{{"_id", id}}
+	for _, acc := range accumulators {
+		group = append(group, bson.E{Key: acc.Field, Value: bson.D{{acc.Operator, acc.Expression}}})
+	}
+	return bson.D{{"$group", group}}
+}
+
+// Project creates a $project stage that reshapes documents using the given projection.
+func Project(projection interface{}) bson.D {
+	return bson.D{{"$project", projection}}
+}
+
+// Lookup creates a $lookup stage that performs an equality join between localField in the input
+// documents and foreignField in the documents of the from collection. The matching documents are
+// stored in an array field named as.
+func Lookup(from, localField, foreignField, as string) bson.D {
+	return bson.D{{"$lookup", bson.D{
+		{"from", from},
+		{"localField", localField},
+		{"foreignField", foreignField},
+		{"as", as},
+	}}}
+}
+
+// UnwindOptions represents all possible options for an $unwind stage.
+type UnwindOptions struct {
+	IncludeArrayIndex          *string // The name of a field to hold the array index of the element.
+	PreserveNullAndEmptyArrays *bool   // If true, documents with a null, missing, or empty array field are output.
+}
+
+// NewUnwindOptions returns a pointer to a new UnwindOptions.
+func NewUnwindOptions() *UnwindOptions {
+	return &UnwindOptions{}
+}
+
+// SetIncludeArrayIndex specifies the name of a field to hold the array index of the element.
+func (uo *UnwindOptions) SetIncludeArrayIndex(field string) *UnwindOptions {
+	uo.IncludeArrayIndex = &field
+	return uo
+}
+
+// SetPreserveNullAndEmptyArrays specifies whether documents with a null, missing, or empty array
+// field are output.
+func (uo *UnwindOptions) SetPreserveNullAndEmptyArrays(b bool) *UnwindOptions {
+	uo.PreserveNullAndEmptyArrays = &b
+	return uo
+}
+
+// Unwind creates an $unwind stage that outputs a document for each element of the array at
+// path. The path may be given with or without the leading "$".
+func Unwind(path string, opts ...*UnwindOptions) bson.D {
+	path = fieldPath(path)
+
+	var includeArrayIndex *string
+	var preserve *bool
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.IncludeArrayIndex != nil {
+			includeArrayIndex = opt.IncludeArrayIndex
+		}
+		if opt.PreserveNullAndEmptyArrays != nil {
+			preserve = opt.PreserveNullAndEmptyArrays
+		}
+	}
+
+	if includeArrayIndex == nil && preserve == nil {
+		return bson.D{{"$unwind", path}}
+	}
+
+	unwind := bson.D{{"path", path}}
+	if includeArrayIndex != nil {
+		unwind = append(unwind, bson.E{Key: "includeArrayIndex", Value: *includeArrayIndex})
+	}
+	if preserve != nil {
+		unwind = append(unwind, bson.E{Key: "preserveNullAndEmptyArrays", Value: *preserve})
+	}
+	return bson.D{{"$unwind", unwind}}
+}
+
+// SortField is a single field in a $sort stage.
+type SortField struct {
+	Field     string
+	Direction int32
+}
+
+// Ascending sorts by field in ascending order.
+func Ascending(field string) SortField {
+	return SortField{Field: field, Direction: 1}
+}
+
+// Descending sorts by field in descending order.
+func Descending(field string) SortField {
+	return SortField{Field: field, Direction: -1}
+}
+
+// Sort creates a $sort stage that orders documents by the given fields. Fields are compared in the
+// order they are given.
+func Sort(fields ...SortField) bson.D {
+	sort := make(bson.D, 0, len(fields))
+	for _, f := range fields {
+		sort = append(sort, bson.E{Key: f.Field, Value: f.Direction})
+	}
+	return bson.D{{"$sort", sort}}
+}
+
+// Limit creates a $limit stage that passes at most n documents to the next stage.
+func Limit(n int64) bson.D {
+	return bson.D{{"$limit", n}}
+}
+
+// MergeOptions represents all possible options for a $merge stage.
+type MergeOptions struct {
+	DB             *string     // The database of the output collection. Defaults to the database being aggregated.
+	On             []string    // The fields that uniquely identify a document in the output collection.
+	Let            interface{} // Variables for use in a WhenMatched pipeline.
+	WhenMatched    interface{} // The action to take when a result document matches an existing document.
+	WhenNotMatched *string     // The action to take when a result document does not match an existing document.
+}
+
+// NewMergeOptions returns a pointer to a new MergeOptions.
+func NewMergeOptions() *MergeOptions {
+	return &MergeOptions{}
+}
+
+// SetDB specifies the database of the output collection.
+func (mo *MergeOptions) SetDB(db string) *MergeOptions {
+	mo.DB = &db
+	return mo
+}
+
+// SetOn specifies the fields that uniquely identify a document in the output collection.
+func (mo *MergeOptions) SetOn(fields ...string) *MergeOptions {
+	mo.On = fields
+	return mo
+}
+
+// SetLet specifies variables for use in a WhenMatched pipeline.
+func (mo *MergeOptions) SetLet(let interface{}) *MergeOptions {
+	mo.Let = let
+	return mo
+}
+
+// SetWhenMatched specifies the action to take when a result document matches an existing document.
+// The action is either one of "replace", "keepExisting", "merge", or "fail", or an update pipeline.
+func (mo *MergeOptions) SetWhenMatched(action interface{}) *MergeOptions {
+	mo.WhenMatched = action
+	return mo
+}
+
+// SetWhenNotMatched specifies the action to take when a result document does not match an existing
+// document. The action is one of "insert", "discard", or "fail".
+func (mo *MergeOptions) SetWhenNotMatched(action string) *MergeOptions {
+	mo.WhenNotMatched = &action
+	return mo
+}
+
+// Merge creates a $merge stage that writes the results of the pipeline into the collection.
+// It must be the last stage in the pipeline. Valid for server versions >= 4.2.
+func Merge(collection string, opts ...*MergeOptions) bson.D {
+	mo := NewMergeOptions()
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.DB != nil {
+			mo.DB = opt.DB
+		}
+		if opt.On != nil {
+			mo.On = opt.On
+		}
+		if opt.Let != nil {
+			mo.Let = opt.Let
+		}
+		if opt.WhenMatched != nil {
+			mo.WhenMatched = opt.WhenMatched
+		}
+		if opt.WhenNotMatched != nil {
+			mo.WhenNotMatched = opt.WhenNotMatched
+		}
+	}
+
+	var into interface{} = collection
+	if mo.DB != nil {
+		into = bson.D{{"db", *mo.DB}, {"coll", collection}}
+	}
+
+	merge := bson.D{{"into", into}}
+	if len(mo.On) == 1 {
+		merge = append(merge, bson.E{Key: "on", Value: mo.On[0]})
+	} else if len(mo.On) > 1 {
+		on := make(bson.A, 0, len(mo.On))
+		for _, field := range mo.On {
+			on = append(on, field)
+		}
+		merge = append(merge, bson.E{Key: "on", Value: on})
+	}
+	if mo.Let != nil {
+		merge = append(merge, bson.E{Key: "let", Value: mo.Let})
+	}
+	if mo.WhenMatched != nil {
+		merge = append(merge, bson.E{Key: "whenMatched", Value: mo.WhenMatched})
+	}
+	if mo.WhenNotMatched != nil {
+		merge = append(merge, bson.E{Key: "whenNotMatched", Value: *mo.WhenNotMatched})
+	}
+	return bson.D{{"$merge", merge}}
+}
+
+// Out creates an $out stage that replaces the contents of the collection with the results of the
+// pipeline. It must be the last stage in the pipeline.
+func Out(collection string) bson.D {
+	return bson.D{{"$out", collection}}
+}
+
+// fieldPath returns path prefixed with "$" if it is not already.
+func fieldPath(path string) string {
+	if strings.HasPrefix(path, "$") {
+		return path
+	}
+	return "$" + path
+}
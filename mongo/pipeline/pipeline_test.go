@@ -0,0 +1,83 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestStages(t *testing.T) {
+	testCases := []struct {
+		name     string
+		stage    bson.D
+		expected bson.D
+	}{
+		{"match", Match(bson.D{{"x", 1}}), bson.D{{"$match", bson.D{{"x", 1}}}}},
+		{
+			"group",
+			Group("$state", Sum("total", "$pop"), Push("cities", "$city")),
+			bson.D{{"$group", bson.D{
+				{"_id", "$state"},
+				{"total", bson.D{{"$sum", "$pop"}}},
+				{"cities", bson.D{{"$push", "$city"}}},
+			}}},
+		},
+		{"group nil id", Group(nil, Avg("avg", "$x")), bson.D{{"$group", bson.D{{"_id", nil}, {"avg", bson.D{{"$avg", "$x"}}}}}}},
+		{"project", Project(bson.D{{"_id", 0}}), bson.D{{"$project", bson.D{{"_id", 0}}}}},
+		{
+			"lookup",
+			Lookup("inventory", "item", "sku", "docs"),
+			bson.D{{"$lookup", bson.D{{"from", "inventory"}, {"localField", "item"}, {"foreignField", "sku"}, {"as", "docs"}}}},
+		},
+		{"unwind", Unwind("sizes"), bson.D{{"$unwind", "$sizes"}}},
+		{"unwind prefixed", Unwind("$sizes"), bson.D{{"$unwind", "$sizes"}}},
+		{
+			"unwind options",
+			Unwind("sizes", NewUnwindOptions().SetIncludeArrayIndex("idx").SetPreserveNullAndEmptyArrays(true)),
+			bson.D{{"$unwind", bson.D{{"path", "$sizes"}, {"includeArrayIndex", "idx"}, {"preserveNullAndEmptyArrays", true}}}},
+		},
+		{
+			"sort",
+			Sort(Descending("total"), Ascending("_id")),
+			bson.D{{"$sort", bson.D{{"total", int32(-1)}, {"_id", int32(1)}}}},
+		},
+		{"limit", Limit(5), bson.D{{"$limit", int64(5)}}},
+		{"merge", Merge("out"), bson.D{{"$merge", bson.D{{"into", "out"}}}}},
+		{
+			"merge options",
+			Merge("out", NewMergeOptions().SetDB("reports").SetOn("a", "b").SetWhenMatched("replace").SetWhenNotMatched("discard")),
+			bson.D{{"$merge", bson.D{
+				{"into", bson.D{{"db", "reports"}, {"coll", "out"}}},
+				{"on", bson.A{"a", "b"}},
+				{"whenMatched", "replace"},
+				{"whenNotMatched", "discard"},
+			}}},
+		},
+		{"merge single on", Merge("out", NewMergeOptions().SetOn("a")), bson.D{{"$merge", bson.D{{"into", "out"}, {"on", "a"}}}}},
+		{"out", Out("out"), bson.D{{"$out", "out"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.stage)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	p := New(Match(bson.D{{"x", 1}}), Limit(1))
+	require.Equal(t, bson.A{bson.D{{"$match", bson.D{{"x", 1}}}}, bson.D{{"$limit", int64(1)}}}, p)
+
+	// the pipeline must marshal as an array of documents
+	doc, err := bson.Marshal(bson.D{{"pipeline", p}})
+	require.NoError(t, err)
+	_, err = bson.Raw(doc).LookupErr("pipeline", "0", "$match")
+	require.NoError(t, err)
+}
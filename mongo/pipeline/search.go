@@ -0,0 +1,235 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package pipeline
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// SearchOperator is an Atlas Search operator used in a $search stage or in a compound clause.
+type SearchOperator struct {
+	Name string // The name of the operator, e.g. "text".
+	Spec bson.D // The operator specification.
+}
+
+// Text creates a text operator that performs a full-text search of query in the given fields.
+func Text(query string, paths ...string) SearchOperator {
+	return SearchOperator{Name: "text", Spec: bson.D{{"query", query}, {"path", searchPath(paths)}}}
+}
+
+// Phrase creates a phrase operator that searches for documents containing query as an ordered
+// sequence of terms in the given fields.
+func Phrase(query string, paths ...string) SearchOperator {
+	return SearchOperator{Name: "phrase", Spec: bson.D{{"query", query}, {"path", searchPath(paths)}}}
+}
+
+// Exists creates an exists operator that matches documents containing the field at path.
+func Exists(path string) SearchOperator {
+	return SearchOperator{Name: "exists", Spec: bson.D{{"path", path}}}
+}
+
+// KnnBeta creates a knnBeta operator that performs a k-nearest neighbor search of vector against
+// the vector field at path, returning at most k documents.
+func KnnBeta(path string, vector []float64, k int32) SearchOperator {
+	return SearchOperator{Name: "knnBeta", Spec: bson.D{{"path", path}, {"vector", vector}, {"k", k}}}
+}
+
+// CompoundClause is a clause of a compound operator.
+type CompoundClause struct {
+	Occurrence string // One of "must", "mustNot", "should", or "filter".
+	Operators  []SearchOperator
+}
+
+// Must creates a compound clause whose operators must all match.
+func Must(ops ...SearchOperator) CompoundClause {
+	return CompoundClause{Occurrence: "must", Operators: ops}
+}
+
+// MustNot creates a compound clause whose operators must not match.
+func MustNot(ops ...SearchOperator) CompoundClause {
+	return CompoundClause{Occurrence: "mustNot", Operators: ops}
+}
+
+// Should creates a compound clause whose operators contribute to the score when they match.
+func Should(ops ...SearchOperator) CompoundClause {
+	return CompoundClause{Occurrence: "should", Operators: ops}
+}
+
+// Filter creates a compound clause whose operators must all match but do not contribute to the
+// score.
+func Filter(ops ...SearchOperator) CompoundClause {
+	return CompoundClause{Occurrence: "filter", Operators: ops}
+}
+
+// Compound creates a compound operator that combines the given clauses.
+func Compound(clauses ...CompoundClause) SearchOperator {
+	spec := make(bson.D, 0, len(clauses))
+	for _, clause := range clauses {
+		ops := make(bson.A, 0, len(clause.Operators))
+		for _, op := range clause.Operators {
+			ops = append(ops, op.document())
+		}
+		spec = append(spec, bson.E{Key: clause.Occurrence, Value: ops})
+	}
+	return SearchOperator{Name: "compound", Spec: spec}
+}
+
+func (so SearchOperator) document() bson.D {
+	return bson.D{{so.Name, so.Spec}}
+}
+
+// SearchOptions represents all possible options for a $search stage.
+type SearchOptions struct {
+	Index        *string // The name of the Atlas Search index. Defaults to "default".
+	ScoreDetails *bool   // If true, a detailed breakdown of the score is available through SearchScoreDetails.
+}
+
+// NewSearchOptions returns a pointer to a new SearchOptions.
+func NewSearchOptions() *SearchOptions {
+	return &SearchOptions{}
+}
+
+// SetIndex specifies the name of the Atlas Search index.
+func (so *SearchOptions) SetIndex(name string) *SearchOptions {
+	so.Index = &name
+	return so
+}
+
+// SetScoreDetails specifies whether a detailed breakdown of the score for each document is
+// returned.
+func (so *SearchOptions) SetScoreDetails(b bool) *SearchOptions {
+	so.ScoreDetails = &b
+	return so
+}
+
+// Search creates a $search stage that performs an Atlas Search query using the given operator. It
+// must be the first stage in the pipeline.
+func Search(op SearchOperator, opts ...*SearchOptions) bson.D {
+	var index *string
+	var scoreDetails *bool
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Index != nil {
+			index = opt.Index
+		}
+		if opt.ScoreDetails != nil {
+			scoreDetails = opt.ScoreDetails
+		}
+	}
+
+	search := bson.D{}
+	if index != nil {
+		search = append(search, bson.E{Key: "index", Value: *index})
+	}
+	search = append(search, bson.E{Key: op.Name, Value: op.Spec})
+	if scoreDetails != nil {
+		search = append(search, bson.E{Key: "scoreDetails", Value: *scoreDetails})
+	}
+	return bson.D{{"$search", search}}
+}
+
+// VectorSearchOptions represents all possible options for a $vectorSearch stage.
+type VectorSearchOptions struct {
+	Exact         *bool       // If true, runs an exact nearest neighbor search instead of an approximate one.
+	Filter        interface{} // A filter on indexed fields applied before the search.
+	NumCandidates *int64      // The number of nearest neighbors to consider for an approximate search.
+}
+
+// NewVectorSearchOptions returns a pointer to a new VectorSearchOptions.
+func NewVectorSearchOptions() *VectorSearchOptions {
+	return &VectorSearchOptions{}
+}
+
+// SetExact specifies whether an exact nearest neighbor search is run.
+func (vso *VectorSearchOptions) SetExact(b bool) *VectorSearchOptions {
+	vso.Exact = &b
+	return vso
+}
+
+// SetFilter specifies a filter on indexed fields applied before the search.
+func (vso *VectorSearchOptions) SetFilter(filter interface{}) *VectorSearchOptions {
+	vso.Filter = filter
+	return vso
+}
+
+// SetNumCandidates specifies the number of nearest neighbors to consider for an approximate
+// search.
+func (vso *VectorSearchOptions) SetNumCandidates(n int64) *VectorSearchOptions {
+	vso.NumCandidates = &n
+	return vso
+}
+
+// VectorSearch creates a $vectorSearch stage that returns the limit documents whose vector field
+// at path is closest to queryVector using the named Atlas Vector Search index. It must be the first
+// stage in the pipeline.
+func VectorSearch(index, path string, queryVector []float64, limit int64, opts ...*VectorSearchOptions) bson.D {
+	vso := NewVectorSearchOptions()
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if opt.Exact != nil {
+			vso.Exact = opt.Exact
+		}
+		if opt.Filter != nil {
+			vso.Filter = opt.Filter
+		}
+		if opt.NumCandidates != nil {
+			vso.NumCandidates = opt.NumCandidates
+		}
+	}
+
+	search := bson.D{
+		{"index", index},
+		{"path", path},
+		{"queryVector", queryVector},
+		{"limit", limit},
+	}
+	if vso.NumCandidates != nil {
+		search = append(search, bson.E{Key: "numCandidates", Value: *vso.NumCandidates})
+	}
+	if vso.Exact != nil {
+		search = append(search, bson.E{Key: "exact", Value: *vso.Exact})
+	}
+	if vso.Filter != nil {
+		search = append(search, bson.E{Key: "filter", Value: vso.Filter})
+	}
+	return bson.D{{"$vectorSearch", search}}
+}
+
+// SearchScore returns an expression that evaluates to the relevance score of a document returned
+// by a $search stage. It can be used as a field value in a $project stage.
+func SearchScore() bson.D {
+	return bson.D{{"$meta", "searchScore"}}
+}
+
+// SearchScoreDetails returns an expression that evaluates to the score breakdown of a document
+// returned by a $search stage with score details enabled.
+func SearchScoreDetails() bson.D {
+	return bson.D{{"$meta", "searchScoreDetails"}}
+}
+
+// VectorSearchScore returns an expression that evaluates to the similarity score of a document
+// returned by a $vectorSearch stage.
+func VectorSearchScore() bson.D {
+	return bson.D{{"$meta", "vectorSearchScore"}}
+}
+
+// searchPath returns the path value for a search operator. A single path is given as a string and
+// multiple paths as an array.
+func searchPath(paths []string) interface{} {
+	if len(paths) == 1 {
+		return paths[0]
+	}
+	arr := make(bson.A, 0, len(paths))
+	for _, p := range paths {
+		arr = append(arr, p)
+	}
+	return arr
+}
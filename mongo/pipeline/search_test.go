@@ -0,0 +1,77 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSearchStages(t *testing.T) {
+	testCases := []struct {
+		name     string
+		stage    bson.D
+		expected bson.D
+	}{
+		{
+			"text",
+			Search(Text("coffee", "title")),
+			bson.D{{"$search", bson.D{{"text", bson.D{{"query", "coffee"}, {"path", "title"}}}}}},
+		},
+		{
+			"text multiple paths with options",
+			Search(Text("coffee", "title", "plot"), NewSearchOptions().SetIndex("movies").SetScoreDetails(true)),
+			bson.D{{"$search", bson.D{
+				{"index", "movies"},
+				{"text", bson.D{{"query", "coffee"}, {"path", bson.A{"title", "plot"}}}},
+				{"scoreDetails", true},
+			}}},
+		},
+		{
+			"compound",
+			Search(Compound(Must(Text("coffee", "title")), MustNot(Phrase("iced tea", "title")), Filter(Exists("year")))),
+			bson.D{{"$search", bson.D{{"compound", bson.D{
+				{"must", bson.A{bson.D{{"text", bson.D{{"query", "coffee"}, {"path", "title"}}}}}},
+				{"mustNot", bson.A{bson.D{{"phrase", bson.D{{"query", "iced tea"}, {"path", "title"}}}}}},
+				{"filter", bson.A{bson.D{{"exists", bson.D{{"path", "year"}}}}}},
+			}}}}},
+		},
+		{
+			"knnBeta",
+			Search(KnnBeta("embedding", []float64{0.1, 0.2}, 5)),
+			bson.D{{"$search", bson.D{{"knnBeta", bson.D{{"path", "embedding"}, {"vector", []float64{0.1, 0.2}}, {"k", int32(5)}}}}}},
+		},
+		{
+			"vectorSearch",
+			VectorSearch("vidx", "embedding", []float64{0.5}, 10, NewVectorSearchOptions().SetNumCandidates(100).SetFilter(bson.D{{"year", 2000}})),
+			bson.D{{"$vectorSearch", bson.D{
+				{"index", "vidx"},
+				{"path", "embedding"},
+				{"queryVector", []float64{0.5}},
+				{"limit", int64(10)},
+				{"numCandidates", int64(100)},
+				{"filter", bson.D{{"year", 2000}}},
+			}}},
+		},
+		{
+			"score projection",
+			Project(bson.D{{"score", SearchScore()}, {"vscore", VectorSearchScore()}}),
+			bson.D{{"$project", bson.D{
+				{"score", bson.D{{"$meta", "searchScore"}}},
+				{"vscore", bson.D{{"$meta", "vectorSearchScore"}}},
+			}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.stage)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"fmt"
+	"path"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+)
+
+// pipelinePolicy is an options.PipelinePolicy with its stages transformed once for all operations.
+type pipelinePolicy struct {
+	namespaces []string
+	prepend    bsonx.Arr
+	append     bsonx.Arr
+}
+
+func newPipelinePolicies(registry *bsoncodec.Registry, policies []options.PipelinePolicy) ([]pipelinePolicy, error) {
+	compiled := make([]pipelinePolicy, 0, len(policies))
+	for _, p := range policies {
+		for _, pattern := range p.Namespaces {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid namespace pattern %q: %v", pattern, err)
+			}
+		}
+		prepend, err := transformAggregatePipeline(registry, p.Prepend)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pipeline policy stages: %v", err)
+		}
+		appended, err := transformAggregatePipeline(registry, p.Append)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pipeline policy stages: %v", err)
+		}
+		compiled = append(compiled, pipelinePolicy{namespaces: p.Namespaces, prepend: prepend, append: appended})
+	}
+	return compiled, nil
+}
+
+// applyPipelinePolicies returns pipeline with the stages of the policies that apply to the
+// collection added. The pipeline is returned as is if no policy applies.
+func applyPipelinePolicies(policies []pipelinePolicy, database, collection string, pipeline bsonx.Arr) bsonx.Arr {
+	ns := database + "." + collection
+	var prepend, appended bsonx.Arr
+	for _, p := range policies {
+		if len(p.namespaces) == 0 || matchesNamespace(p.namespaces, ns) {
+			prepend = append(prepend, p.prepend...)
+			appended = append(appended, p.append...)
+		}
+	}
+	if len(prepend) == 0 && len(appended) == 0 {
+		return pipeline
+	}
+
+	start := 0
+	if len(pipeline) > 0 && isFirstStage(pipeline[0]) {
+		start = 1
+	}
+	end := len(pipeline)
+	if end > start && isLastStage(pipeline[end-1]) {
+		end--
+	}
+
+	res := make(bsonx.Arr, 0, len(pipeline)+len(prepend)+len(appended))
+	res = append(res, pipeline[:start]...)
+	res = append(res, prepend...)
+	res = append(res, pipeline[start:end]...)
+	res = append(res, appended...)
+	return append(res, pipeline[end:]...)
+}
+
+// isFirstStage returns true if the stage must be the first of a pipeline.
+func isFirstStage(stage bsonx.Val) bool {
+	switch stageName(stage) {
+	case "$geoNear", "$search", "$searchMeta", "$vectorSearch", "$collStats", "$indexStats", "$changeStream":
+		return true
+	}
+	return false
+}
+
+// isLastStage returns true if the stage must be the last of a pipeline.
+func isLastStage(stage bsonx.Val) bool {
+	switch stageName(stage) {
+	case "$out", "$merge":
+		return true
+	}
+	return false
+}
+
+func stageName(stage bsonx.Val) string {
+	doc, ok := stage.DocumentOK()
+	if !ok || len(doc) == 0 {
+		return ""
+	}
+	return doc[0].Key
+}
@@ -0,0 +1,70 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+)
+
+func TestPipelinePolicies(t *testing.T) {
+	policies, err := newPipelinePolicies(bson.DefaultRegistry, []options.PipelinePolicy{
+		{
+			Namespaces: []string{"app.*"},
+			Prepend:    []interface{}{bson.D{{"$match", bson.D{{"tenant", "t1"}}}}},
+		},
+		{
+			Namespaces: []string{"app.orders"},
+			Append:     []interface{}{bson.D{{"$project", bson.D{{"secret", 0}}}}},
+		},
+	})
+	require.NoError(t, err)
+
+	tenant := bsonx.Document(bsonx.Doc{{"$match", bsonx.Document(bsonx.Doc{{"tenant", bsonx.String("t1")}})}})
+	project := bsonx.Document(bsonx.Doc{{"$project", bsonx.Document(bsonx.Doc{{"secret", bsonx.Int32(0)}})}})
+	stage := func(name string) bsonx.Val {
+		return bsonx.Document(bsonx.Doc{{name, bsonx.Document(bsonx.Doc{})}})
+	}
+
+	testCases := []struct {
+		name     string
+		coll     string
+		pipeline bsonx.Arr
+		want     bsonx.Arr
+	}{
+		{"all policies", "orders", bsonx.Arr{stage("$sort")}, bsonx.Arr{tenant, stage("$sort"), project}},
+		{"one policy", "users", bsonx.Arr{stage("$sort")}, bsonx.Arr{tenant, stage("$sort")}},
+		{"empty pipeline", "orders", bsonx.Arr{}, bsonx.Arr{tenant, project}},
+		{
+			"first and last stages",
+			"orders",
+			bsonx.Arr{stage("$geoNear"), stage("$sort"), stage("$out")},
+			bsonx.Arr{stage("$geoNear"), tenant, stage("$sort"), project, stage("$out")},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := applyPipelinePolicies(policies, "app", tc.coll, tc.pipeline)
+			require.True(t, tc.want.Equal(got), "got %v; want %v", got, tc.want)
+		})
+	}
+
+	t.Run("no policy applies", func(t *testing.T) {
+		pipeline := bsonx.Arr{stage("$sort")}
+		require.Equal(t, pipeline, applyPipelinePolicies(policies, "other", "orders", pipeline))
+	})
+	t.Run("invalid policies", func(t *testing.T) {
+		_, err := newPipelinePolicies(bson.DefaultRegistry, []options.PipelinePolicy{{Namespaces: []string{"app.["}}})
+		require.Error(t, err)
+		_, err = newPipelinePolicies(bson.DefaultRegistry, []options.PipelinePolicy{{Prepend: []interface{}{1}}})
+		require.Error(t, err)
+	})
+}
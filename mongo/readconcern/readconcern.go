@@ -39,12 +39,14 @@ func Majority() *ReadConcern {
 
 // Linearizable specifies that the query should return data that reflects all successful writes
 // issued with a write concern of "majority" and acknowledged prior to the start of the read operation.
+// Linearizable reads must use a primary read preference and cannot be run in transactions.
 func Linearizable() *ReadConcern {
 	return New(Level("linearizable"))
 }
 
 // Available specifies that the query should return data from the instance with no guarantee
 // that the data has been written to a majority of the replica set members (i.e. may be rolled back).
+// It cannot be used in transactions.
 func Available() *ReadConcern {
 	return New(Level("available"))
 }
@@ -65,6 +67,11 @@ func New(options ...Option) *ReadConcern {
 	return concern
 }
 
+// GetLevel returns the read concern level.
+func (rc *ReadConcern) GetLevel() string {
+	return rc.level
+}
+
 // MarshalBSONValue implements the bson.ValueMarshaler interface.
 func (rc *ReadConcern) MarshalBSONValue() (bsontype.Type, []byte, error) {
 	var elems []byte
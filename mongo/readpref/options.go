@@ -29,6 +29,19 @@ func WithMaxStaleness(ms time.Duration) Option {
 	}
 }
 
+// WithHedgeEnabled sets whether mongos should hedge
+// reads on sharded clusters. Hedged reads are supported
+// by MongoDB 4.4 and later and are not sent to older
+// servers. Reads with a nearest read preference are
+// hedged by default.
+func WithHedgeEnabled(enabled bool) Option {
+	return func(rp *ReadPref) error {
+		rp.hedgeEnabled = enabled
+		rp.hedgeEnabledSet = true
+		return nil
+	}
+}
+
 // WithTags sets a single tag set used to match
 // a server. The last call to WithTags or WithTagSets
 // overrides all previous calls to either method.
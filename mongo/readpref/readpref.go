@@ -78,6 +78,8 @@ type ReadPref struct {
 	maxStalenessSet bool
 	mode            Mode
 	tagSets         []tag.Set
+	hedgeEnabled    bool
+	hedgeEnabledSet bool
 }
 
 // MaxStaleness is the maximum amount of time to allow
@@ -87,6 +89,13 @@ func (r *ReadPref) MaxStaleness() (time.Duration, bool) {
 	return r.maxStaleness, r.maxStalenessSet
 }
 
+// HedgeEnabled indicates whether mongos should send hedged reads,
+// which are sent to two members of each shard and return the first
+// reply. The second return value indicates if this value has been set.
+func (r *ReadPref) HedgeEnabled() (bool, bool) {
+	return r.hedgeEnabled, r.hedgeEnabledSet
+}
+
 // Mode indicates the mode of the read preference.
 func (r *ReadPref) Mode() Mode {
 	return r.mode
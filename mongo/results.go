@@ -21,6 +21,17 @@ type BulkWriteResult struct {
 	DeletedCount  int64
 	UpsertedCount int64
 	UpsertedIDs   map[int64]interface{}
+	// Maps the indexes of the InsertOneModels that were inserted to the _id fields of their
+	// documents. An _id is generated for any document that does not have one.
+	InsertedIDs map[int64]interface{}
+	// Whether the server acknowledged the write. The counts are zero for unacknowledged writes.
+	Acknowledged bool
+}
+
+// RewrapManyDataKeyResult is the result of a ClientEncryption.RewrapManyDataKey operation.
+type RewrapManyDataKeyResult struct {
+	// The result of updating the rewrapped data keys in the key vault collection.
+	*BulkWriteResult
 }
 
 // InsertOneResult is a result of an InsertOne operation.
@@ -29,18 +40,36 @@ type BulkWriteResult struct {
 type InsertOneResult struct {
 	// The identifier that was inserted.
 	InsertedID interface{}
+	// Whether the server acknowledged the write.
+	Acknowledged bool
+	// The server that executed the write.
+	ServerToken ServerToken
+	// The concerns the write was executed with.
+	Concerns OperationConcerns
 }
 
 // InsertManyResult is a result of an InsertMany operation.
 type InsertManyResult struct {
 	// Maps the indexes of inserted documents to their _id fields.
 	InsertedIDs []interface{}
+	// Whether the server acknowledged the write.
+	Acknowledged bool
+	// The server that executed the write.
+	ServerToken ServerToken
+	// The concerns the write was executed with.
+	Concerns OperationConcerns
 }
 
 // DeleteResult is a result of an DeleteOne operation.
 type DeleteResult struct {
 	// The number of documents that were deleted.
 	DeletedCount int64 `bson:"n"`
+	// Whether the server acknowledged the write. The count is zero for unacknowledged writes.
+	Acknowledged bool `bson:"-"`
+	// The server that executed the write.
+	ServerToken ServerToken `bson:"-"`
+	// The concerns the write was executed with.
+	Concerns OperationConcerns `bson:"-"`
 }
 
 // ListDatabasesResult is a result of a ListDatabases operation. Each specification
@@ -70,6 +99,58 @@ type DatabaseSpecification struct {
 	Empty      bool
 }
 
+// CollectionSpecification is the description of a collection or view returned by a
+// ListCollectionSpecifications operation.
+type CollectionSpecification struct {
+	Name     string
+	Type     string   // "collection", "view" or "timeseries".
+	ReadOnly bool     // Whether the collection is read-only, as the views are.
+	Options  bson.Raw // The options the collection or view was created with.
+	// The time series options of the collection, or nil if it is not a time series collection.
+	TimeSeries *TimeSeriesSpecification
+}
+
+// TimeSeriesSpecification is the description of the time series options of a collection.
+type TimeSeriesSpecification struct {
+	TimeField            string `bson:"timeField"`
+	MetaField            string `bson:"metaField"`
+	Granularity          string `bson:"granularity"`
+	BucketMaxSpanSeconds int64  `bson:"bucketMaxSpanSeconds"`
+}
+
+// newCollectionSpecification returns the specification described by doc, a listCollections result.
+func newCollectionSpecification(doc bson.Raw) (*CollectionSpecification, error) {
+	var res struct {
+		Name    string   `bson:"name"`
+		Type    string   `bson:"type"`
+		Options bson.Raw `bson:"options"`
+		Info    struct {
+			ReadOnly bool `bson:"readOnly"`
+		} `bson:"info"`
+	}
+	if err := bson.Unmarshal(doc, &res); err != nil {
+		return nil, err
+	}
+
+	spec := &CollectionSpecification{
+		Name:     res.Name,
+		Type:     res.Type,
+		ReadOnly: res.Info.ReadOnly,
+		Options:  res.Options,
+	}
+	// Servers before 3.4 do not return the type of the collections.
+	if spec.Type == "" {
+		spec.Type = "collection"
+	}
+	if ts, err := res.Options.LookupErr("timeseries"); err == nil {
+		spec.TimeSeries = &TimeSeriesSpecification{}
+		if err = ts.Unmarshal(spec.TimeSeries); err != nil {
+			return nil, err
+		}
+	}
+	return spec, nil
+}
+
 // UpdateResult is a result of an update operation.
 //
 // UpsertedID will be a Go type that corresponds to a BSON type.
@@ -82,6 +163,12 @@ type UpdateResult struct {
 	UpsertedCount int64
 	// The identifier of the inserted document if an upsert took place.
 	UpsertedID interface{}
+	// Whether the server acknowledged the write. The counts are zero for unacknowledged writes.
+	Acknowledged bool
+	// The server that executed the write.
+	ServerToken ServerToken
+	// The concerns the write was executed with.
+	Concerns OperationConcerns
 }
 
 // UnmarshalBSON implements the bson.Unmarshaler interface.
@@ -72,3 +72,43 @@ func TestUpdateOneResult_unmarshalInto(t *testing.T) {
 	require.Equal(t, result.ModifiedCount, int64(2))
 	require.Equal(t, int(result.UpsertedID.(int32)), 3)
 }
+
+func TestNewCollectionSpecification(t *testing.T) {
+	t.Parallel()
+
+	doc, err := bson.Marshal(bson.D{
+		{"name", "metrics"},
+		{"type", "timeseries"},
+		{"options", bson.D{
+			{"timeseries", bson.D{
+				{"timeField", "ts"},
+				{"metaField", "sensor"},
+				{"granularity", "minutes"},
+				{"bucketMaxSpanSeconds", int32(86400)},
+			}},
+			{"expireAfterSeconds", int64(3600)},
+		}},
+		{"info", bson.D{{"readOnly", false}}},
+	})
+	require.NoError(t, err)
+
+	spec, err := newCollectionSpecification(doc)
+	require.NoError(t, err)
+	require.Equal(t, "metrics", spec.Name)
+	require.Equal(t, "timeseries", spec.Type)
+	require.False(t, spec.ReadOnly)
+	require.Equal(t, int64(3600), spec.Options.Lookup("expireAfterSeconds").Int64())
+	require.Equal(t, &TimeSeriesSpecification{
+		TimeField:            "ts",
+		MetaField:            "sensor",
+		Granularity:          "minutes",
+		BucketMaxSpanSeconds: 86400,
+	}, spec.TimeSeries)
+
+	doc, err = bson.Marshal(bson.D{{"name", "coll"}, {"options", bson.D{}}})
+	require.NoError(t, err)
+	spec, err = newCollectionSpecification(doc)
+	require.NoError(t, err)
+	require.Equal(t, "collection", spec.Type)
+	require.Nil(t, spec.TimeSeries)
+}
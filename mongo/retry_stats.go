@@ -0,0 +1,41 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+// RetryCounts are the numbers of transparent retries of one kind of operation.
+type RetryCounts struct {
+	// Retried is the number of operations that were executed a second time because their first
+	// attempt failed with a retryable error.
+	Retried uint64
+
+	// Succeeded and Failed are the numbers of second attempts that succeeded and failed. A retried
+	// write fails if its second attempt returns an error or a write concern error.
+	Succeeded uint64
+	Failed    uint64
+
+	// ErrorCodes is the number of retries by the code of the error that triggered them. Errors
+	// without a code, such as network errors, are counted under 0.
+	ErrorCodes map[int32]uint64
+}
+
+// RetryStats are the retry counts of the reads and writes of a client. They show how often
+// retryable reads and writes masked errors such as elections and network failures.
+type RetryStats struct {
+	Reads  RetryCounts
+	Writes RetryCounts
+}
+
+// RetryStats returns the numbers of reads and writes the client has retried since it was created,
+// or since ResetAfterFork was last called. The commits and aborts of transactions are counted as
+// writes.
+func (c *Client) RetryStats() RetryStats {
+	stats := c.topology.RetryStats()
+	return RetryStats{
+		Reads:  RetryCounts(stats.Reads),
+		Writes: RetryCounts(stats.Writes),
+	}
+}
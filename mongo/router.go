@@ -0,0 +1,195 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNoRouterClients is returned by NewRouter when it is given no clients.
+var ErrNoRouterClients = errors.New("a router must have at least one client")
+
+// UnknownRouteError is returned when the RouteFunc of a Router selects a client the router does
+// not have.
+type UnknownRouteError struct {
+	Name string // The name of the selected client.
+}
+
+// Error implements the error interface.
+func (e UnknownRouteError) Error() string {
+	return fmt.Sprintf("the router has no client named %q", e.Name)
+}
+
+// RouteFunc selects the name of the client of a Router that runs operations on a namespace.
+// collection is empty when a database is selected.
+type RouteFunc func(ctx context.Context, database, collection string) (string, error)
+
+// Router holds several named Clients, such as one per region or per tenant shard, and selects one
+// of them for each database or collection with a RouteFunc.
+type Router struct {
+	clients map[string]*Client
+	names   []string
+	route   RouteFunc
+}
+
+// NewRouter creates a Router with a Client for each entry of clientOpts. The clients are not
+// connected until Connect is called.
+func NewRouter(route RouteFunc, clientOpts map[string]*options.ClientOptions, opts ...*options.RouterOptions) (*Router, error) {
+	if route == nil {
+		return nil, errors.New("a router must have a route function")
+	}
+	if len(clientOpts) == 0 {
+		return nil, ErrNoRouterClients
+	}
+	routerOpts := options.MergeRouterOptions(opts...)
+
+	r := &Router{
+		clients: make(map[string]*Client, len(clientOpts)),
+		route:   route,
+	}
+	for name := range clientOpts {
+		r.names = append(r.names, name)
+	}
+	sort.Strings(r.names)
+
+	for _, name := range r.names {
+		co := options.MergeClientOptions(clientOpts[name])
+		shared := options.Client()
+		if routerOpts.Monitor != nil {
+			shared.SetMonitor(combineCommandMonitors(co.Monitor, routerOpts.Monitor))
+		}
+		if routerOpts.PoolMonitor != nil {
+			shared.SetPoolMonitor(combinePoolMonitors(co.PoolMonitor, routerOpts.PoolMonitor))
+		}
+
+		client, err := NewClient(co, shared)
+		if err != nil {
+			return nil, fmt.Errorf("client %q: %v", name, err)
+		}
+		r.clients[name] = client
+	}
+
+	return r, nil
+}
+
+// Connect connects every client of the router.
+func (r *Router) Connect(ctx context.Context) error {
+	for _, name := range r.names {
+		if err := r.clients[name].Connect(ctx); err != nil {
+			return fmt.Errorf("client %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// Disconnect disconnects every client of the router. Every client is disconnected even if
+// disconnecting one of them fails, and the first error is returned.
+func (r *Router) Disconnect(ctx context.Context) error {
+	var first error
+	for _, name := range r.names {
+		if err := r.clients[name].Disconnect(ctx); err != nil && first == nil {
+			first = fmt.Errorf("client %q: %v", name, err)
+		}
+	}
+	return first
+}
+
+// Client returns the client of the router with the given name, or nil if there is none.
+func (r *Router) Client(name string) *Client {
+	return r.clients[name]
+}
+
+// Select returns the client that runs operations on the given database and collection.
+func (r *Router) Select(ctx context.Context, database, collection string) (*Client, error) {
+	name, err := r.route(ctx, database, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	client, ok := r.clients[name]
+	if !ok {
+		return nil, UnknownRouteError{Name: name}
+	}
+	return client, nil
+}
+
+// Database returns a handle for the database with the given name on the client selected for it.
+func (r *Router) Database(ctx context.Context, name string, opts ...*options.DatabaseOptions) (*Database, error) {
+	client, err := r.Select(ctx, name, "")
+	if err != nil {
+		return nil, err
+	}
+	return client.Database(name, opts...), nil
+}
+
+// Collection returns a handle for the collection with the given name in the given database on the
+// client selected for it.
+func (r *Router) Collection(ctx context.Context, database, collection string, opts ...*options.CollectionOptions) (*Collection, error) {
+	client, err := r.Select(ctx, database, collection)
+	if err != nil {
+		return nil, err
+	}
+	return client.Database(database).Collection(collection, opts...), nil
+}
+
+// combineCommandMonitors returns a monitor that calls both first and second. first may be nil.
+func combineCommandMonitors(first, second *event.CommandMonitor) *event.CommandMonitor {
+	if first == nil {
+		return second
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			if first.Started != nil {
+				first.Started(ctx, e)
+			}
+			if second.Started != nil {
+				second.Started(ctx, e)
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			if first.Succeeded != nil {
+				first.Succeeded(ctx, e)
+			}
+			if second.Succeeded != nil {
+				second.Succeeded(ctx, e)
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			if first.Failed != nil {
+				first.Failed(ctx, e)
+			}
+			if second.Failed != nil {
+				second.Failed(ctx, e)
+			}
+		},
+	}
+}
+
+// combinePoolMonitors returns a monitor that calls both first and second. first may be nil.
+func combinePoolMonitors(first, second *event.PoolMonitor) *event.PoolMonitor {
+	if first == nil {
+		return second
+	}
+
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			if first.Event != nil {
+				first.Event(e)
+			}
+			if second.Event != nil {
+				second.Event(e)
+			}
+		},
+	}
+}
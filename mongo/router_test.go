@@ -0,0 +1,96 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestRouter(t *testing.T) {
+	errNoTenant := errors.New("no tenant")
+	route := func(_ context.Context, database, _ string) (string, error) {
+		parts := strings.SplitN(database, "_", 2)
+		if len(parts) != 2 {
+			return "", errNoTenant
+		}
+		return parts[0], nil
+	}
+	clientOpts := map[string]*options.ClientOptions{
+		"eu": options.Client().ApplyURI("mongodb://eu.example.com:27017"),
+		"us": options.Client().ApplyURI("mongodb://us.example.com:27017"),
+	}
+
+	t.Run("selects clients", func(t *testing.T) {
+		r, err := NewRouter(route, clientOpts)
+		require.NoError(t, err)
+
+		db, err := r.Database(context.Background(), "eu_orders")
+		require.NoError(t, err)
+		require.Equal(t, r.Client("eu"), db.Client())
+		require.Equal(t, "eu_orders", db.Name())
+
+		coll, err := r.Collection(context.Background(), "us_orders", "items")
+		require.NoError(t, err)
+		require.Equal(t, r.Client("us"), coll.Database().Client())
+		require.Equal(t, "items", coll.Name())
+
+		require.NotEqual(t, r.Client("eu"), r.Client("us"))
+		require.Nil(t, r.Client("ap"))
+	})
+	t.Run("route errors", func(t *testing.T) {
+		r, err := NewRouter(route, clientOpts)
+		require.NoError(t, err)
+
+		_, err = r.Database(context.Background(), "orders")
+		require.Equal(t, errNoTenant, err)
+		_, err = r.Collection(context.Background(), "ap_orders", "items")
+		require.Equal(t, UnknownRouteError{Name: "ap"}, err)
+	})
+	t.Run("invalid routers", func(t *testing.T) {
+		_, err := NewRouter(nil, clientOpts)
+		require.Error(t, err)
+		_, err = NewRouter(route, nil)
+		require.Equal(t, ErrNoRouterClients, err)
+		_, err = NewRouter(route, map[string]*options.ClientOptions{"eu": options.Client().ApplyURI("mongodb://")})
+		require.Error(t, err)
+	})
+	t.Run("shared monitors", func(t *testing.T) {
+		var calls []string
+		own := &event.CommandMonitor{
+			Started: func(context.Context, *event.CommandStartedEvent) { calls = append(calls, "own started") },
+		}
+		shared := &event.CommandMonitor{
+			Started:   func(context.Context, *event.CommandStartedEvent) { calls = append(calls, "shared started") },
+			Succeeded: func(context.Context, *event.CommandSucceededEvent) { calls = append(calls, "shared succeeded") },
+		}
+
+		require.Equal(t, shared, combineCommandMonitors(nil, shared))
+		combined := combineCommandMonitors(own, shared)
+		combined.Started(context.Background(), &event.CommandStartedEvent{})
+		combined.Succeeded(context.Background(), &event.CommandSucceededEvent{})
+		combined.Failed(context.Background(), &event.CommandFailedEvent{})
+		require.Equal(t, []string{"own started", "shared started", "shared succeeded"}, calls)
+
+		calls = nil
+		pool := combinePoolMonitors(
+			&event.PoolMonitor{Event: func(*event.PoolEvent) { calls = append(calls, "own") }},
+			&event.PoolMonitor{Event: func(*event.PoolEvent) { calls = append(calls, "shared") }},
+		)
+		pool.Event(&event.PoolEvent{})
+		require.Equal(t, []string{"own", "shared"}, calls)
+
+		_, err := NewRouter(route, clientOpts, options.Router().SetMonitor(shared).SetPoolMonitor(pool))
+		require.NoError(t, err)
+	})
+}
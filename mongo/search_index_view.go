@@ -0,0 +1,137 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrSearchIndexNotFound is returned by SearchIndexView.WaitQueryable when the collection has no
+// search index with the given name.
+var ErrSearchIndexNotFound = errors.New("search index not found")
+
+// SearchIndexView is used to inspect the Atlas Search indexes of a collection.
+type SearchIndexView struct {
+	coll *Collection
+}
+
+// SearchIndexes returns the search index view for this collection.
+func (coll *Collection) SearchIndexes() SearchIndexView {
+	return SearchIndexView{coll: coll}
+}
+
+// List returns a cursor iterating over the search indexes of the collection. If name is not
+// empty, only the index with that name is returned. Each document describes one index, including
+// its status, whether it is queryable, and its latest definition.
+func (siv SearchIndexView) List(ctx context.Context, name string, opts ...*options.AggregateOptions) (*Cursor, error) {
+	stage := bson.D{}
+	if name != "" {
+		stage = append(stage, bson.E{Key: "name", Value: name})
+	}
+	pipeline := bson.A{bson.D{{"$listSearchIndexes", stage}}}
+	return siv.coll.Aggregate(ctx, pipeline, opts...)
+}
+
+// WaitQueryable polls the status of the search index with the given name until it can serve
+// queries using its latest definition, and then returns the document describing it. Search index
+// builds are asynchronous, so this is useful after creating or updating an index. The time between
+// status checks starts at the poll interval and doubles after every check. An error is returned if
+// the index build fails, the index does not exist, or the timeout or the deadline of ctx passes
+// first.
+func (siv SearchIndexView) WaitQueryable(ctx context.Context, name string,
+	opts ...*options.WaitSearchIndexOptions) (bson.Raw, error) {
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	wo := options.MergeWaitSearchIndexOptions(opts...)
+	interval := options.DefaultSearchIndexPollInterval
+	if wo.PollInterval != nil {
+		interval = *wo.PollInterval
+	}
+	maxInterval := options.DefaultSearchIndexMaxPollInterval
+	if wo.MaxPollInterval != nil {
+		maxInterval = *wo.MaxPollInterval
+	}
+	if wo.Timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *wo.Timeout)
+		defer cancel()
+	}
+
+	for {
+		doc, err := siv.status(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		ready, err := searchIndexQueryable(doc)
+		if err != nil || ready {
+			return doc, err
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+		interval = nextPollInterval(interval, maxInterval)
+	}
+}
+
+// status returns the document describing the search index with the given name.
+func (siv SearchIndexView) status(ctx context.Context, name string) (bson.Raw, error) {
+	cursor, err := siv.List(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		if err = cursor.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrSearchIndexNotFound
+	}
+	return append(bson.Raw(nil), cursor.Current...), nil
+}
+
+// searchIndexQueryable reports whether the search index described by doc serves queries using its
+// latest definition. An error is returned if the build of the index failed.
+func searchIndexQueryable(doc bson.Raw) (bool, error) {
+	status, _ := doc.Lookup("status").StringValueOK()
+	if status == "FAILED" {
+		msg, _ := doc.Lookup("message").StringValueOK()
+		return false, fmt.Errorf("search index build failed: %s", msg)
+	}
+
+	queryable := doc.Lookup("queryable")
+	if queryable.Type != bsontype.Boolean || !queryable.Boolean() {
+		return false, nil
+	}
+	// An index that is being rebuilt after an update stays queryable with its previous definition
+	// and is only READY once the latest definition has been built.
+	return status == "" || status == "READY", nil
+}
+
+// nextPollInterval doubles interval, up to max.
+func nextPollInterval(interval, max time.Duration) time.Duration {
+	interval *= 2
+	if interval > max {
+		interval = max
+	}
+	return interval
+}
@@ -0,0 +1,47 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSearchIndexQueryable(t *testing.T) {
+	testCases := []struct {
+		name  string
+		doc   bson.D
+		ready bool
+		err   bool
+	}{
+		{"pending", bson.D{{"name", "default"}, {"status", "PENDING"}, {"queryable", false}}, false, false},
+		{"building", bson.D{{"status", "BUILDING"}, {"queryable", false}}, false, false},
+		{"ready", bson.D{{"status", "READY"}, {"queryable", true}}, true, false},
+		{"rebuilding latest definition", bson.D{{"status", "BUILDING"}, {"queryable", true}}, false, false},
+		{"queryable without status", bson.D{{"queryable", true}}, true, false},
+		{"missing queryable", bson.D{{"status", "READY"}}, false, false},
+		{"failed", bson.D{{"status", "FAILED"}, {"queryable", false}, {"message", "bad mapping"}}, false, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := bson.Marshal(tc.doc)
+			require.NoError(t, err)
+
+			ready, err := searchIndexQueryable(doc)
+			require.Equal(t, tc.ready, ready)
+			require.Equal(t, tc.err, err != nil)
+		})
+	}
+}
+
+func TestNextPollInterval(t *testing.T) {
+	require.Equal(t, 2*time.Second, nextPollInterval(time.Second, 5*time.Second))
+	require.Equal(t, 5*time.Second, nextPollInterval(4*time.Second, 5*time.Second))
+}
@@ -0,0 +1,46 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
+	"go.mongodb.org/mongo-driver/x/network/address"
+)
+
+// ServerToken identifies the server that executed an operation. It is reported by the results of
+// write operations, cursors and single results, and can be passed to WithServerAffinity so that
+// later operations prefer the same server. The zero ServerToken identifies no server.
+type ServerToken struct {
+	addr address.Address
+}
+
+// String returns the address of the server identified by the token.
+func (st ServerToken) String() string { return st.addr.String() }
+
+// IsZero returns true if the token identifies no server.
+func (st ServerToken) IsZero() bool { return st.addr == "" }
+
+// WithServerAffinity returns a context in which operations select the server identified by token
+// whenever it satisfies their read preference and latency window, instead of picking one of the
+// suitable servers at random. This lets a sequence of operations that do not use a causally
+// consistent session read from the same secondary, so they see consistent data more often. It has
+// no effect if token is the zero ServerToken.
+func WithServerAffinity(ctx context.Context, token ServerToken) context.Context {
+	if token.IsZero() {
+		return ctx
+	}
+	return topology.WithPreferredServer(ctx, token.addr)
+}
+
+// recordServer returns a context in which the server selected for an operation is stored in the
+// returned token.
+func recordServer(ctx context.Context) (context.Context, *ServerToken) {
+	token := new(ServerToken)
+	return topology.WithSelectedServerRecorder(ctx, &token.addr), token
+}
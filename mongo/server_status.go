@@ -0,0 +1,170 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// ServerStatus is the commonly monitored part of the reply to the serverStatus command. The sections
+// reported depend on the server version and configuration, so Raw holds the whole reply.
+type ServerStatus struct {
+	Host        string            `bson:"host"`
+	Version     string            `bson:"version"`
+	Process     string            `bson:"process"` // "mongod" or "mongos".
+	Uptime      float64           `bson:"uptime"`  // In seconds.
+	LocalTime   time.Time         `bson:"localTime"`
+	Connections ServerConnections `bson:"connections"`
+	Opcounters  Opcounters        `bson:"opcounters"`
+	Raw         bson.Raw          `bson:"-"`
+}
+
+// ServerConnections counts the incoming connections of a server.
+type ServerConnections struct {
+	Current      int64 `bson:"current"`
+	Available    int64 `bson:"available"`
+	TotalCreated int64 `bson:"totalCreated"`
+	Active       int64 `bson:"active"` // Only reported by servers 4.0 and later.
+}
+
+// Opcounters counts the operations a server received since it started, by type. Each command is
+// counted once, so a bulk insert of several documents counts as one insert.
+type Opcounters struct {
+	Insert  int64 `bson:"insert"`
+	Query   int64 `bson:"query"`
+	Update  int64 `bson:"update"`
+	Delete  int64 `bson:"delete"`
+	GetMore int64 `bson:"getmore"`
+	Command int64 `bson:"command"`
+}
+
+// The states of replica set members, as reported by replSetGetStatus.
+const (
+	MemberStateStartup    = 0
+	MemberStatePrimary    = 1
+	MemberStateSecondary  = 2
+	MemberStateRecovering = 3
+	MemberStateStartup2   = 5
+	MemberStateUnknown    = 6
+	MemberStateArbiter    = 7
+	MemberStateDown       = 8
+	MemberStateRollback   = 9
+	MemberStateRemoved    = 10
+)
+
+// ReplSetStatus is the commonly monitored part of the reply to the replSetGetStatus command, as
+// seen by the member that ran it. Raw holds the whole reply.
+type ReplSetStatus struct {
+	Set     string          `bson:"set"`
+	Date    time.Time       `bson:"date"`
+	MyState int32           `bson:"myState"`
+	Members []ReplSetMember `bson:"members"`
+	Raw     bson.Raw        `bson:"-"`
+}
+
+// ReplSetMember is the status of a replica set member. Health is 1 if the member is up and 0 if it
+// is down. LastHeartbeat and PingMs are not reported for the member that ran the command, for which
+// Self is true instead.
+type ReplSetMember struct {
+	ID             int32     `bson:"_id"`
+	Name           string    `bson:"name"`
+	Health         float64   `bson:"health"`
+	State          int32     `bson:"state"`
+	StateStr       string    `bson:"stateStr"`
+	Uptime         int64     `bson:"uptime"` // In seconds.
+	OptimeDate     time.Time `bson:"optimeDate"`
+	LastHeartbeat  time.Time `bson:"lastHeartbeat"`
+	PingMs         int64     `bson:"pingMs"`
+	SyncSourceHost string    `bson:"syncSourceHost"` // Reported as syncingTo by servers before 4.0.
+	SyncingTo      string    `bson:"syncingTo"`      // Only reported by servers before 4.0.
+	Self           bool      `bson:"self"`
+}
+
+// Primary returns the member that is primary, or nil if there is none.
+func (rs *ReplSetStatus) Primary() *ReplSetMember {
+	for i := range rs.Members {
+		if rs.Members[i].State == MemberStatePrimary {
+			return &rs.Members[i]
+		}
+	}
+	return nil
+}
+
+// Lag returns how far behind the primary the last applied operation of each secondary is, by member
+// name. It returns nil if there is no primary.
+func (rs *ReplSetStatus) Lag() map[string]time.Duration {
+	primary := rs.Primary()
+	if primary == nil {
+		return nil
+	}
+
+	lag := make(map[string]time.Duration)
+	for _, m := range rs.Members {
+		if m.State == MemberStateSecondary {
+			lag[m.Name] = primary.OptimeDate.Sub(m.OptimeDate)
+		}
+	}
+	return lag
+}
+
+// ServerStatus runs the serverStatus command on a server selected with rp, which defaults to the
+// read preference of the client if it is nil.
+//
+// See https://docs.mongodb.com/manual/reference/command/serverStatus/.
+func (c *Client) ServerStatus(ctx context.Context, rp *readpref.ReadPref) (*ServerStatus, error) {
+	res, err := c.runAdminCommand(ctx, rp, bson.D{{"serverStatus", 1}})
+	if err != nil {
+		return nil, err
+	}
+	status := &ServerStatus{}
+	if err = bson.UnmarshalWithRegistry(c.registry, res, status); err != nil {
+		return nil, err
+	}
+	status.Raw = res
+	return status, nil
+}
+
+// ReplSetGetStatus runs the replSetGetStatus command on a replica set member selected with rp, which
+// defaults to the read preference of the client if it is nil.
+//
+// See https://docs.mongodb.com/manual/reference/command/replSetGetStatus/.
+func (c *Client) ReplSetGetStatus(ctx context.Context, rp *readpref.ReadPref) (*ReplSetStatus, error) {
+	res, err := c.runAdminCommand(ctx, rp, bson.D{{"replSetGetStatus", 1}})
+	if err != nil {
+		return nil, err
+	}
+	return decodeReplSetStatus(c.registry, res)
+}
+
+func (c *Client) runAdminCommand(ctx context.Context, rp *readpref.ReadPref, cmd bson.D) (bson.Raw, error) {
+	if rp == nil {
+		rp = c.readPreference
+	}
+	return c.Database("admin").RunCommand(ctx, cmd, options.RunCmd().SetReadPreference(rp)).DecodeBytes()
+}
+
+// decodeReplSetStatus decodes a replSetGetStatus reply, filling in the sync source of members
+// reported by servers before 4.0.
+func decodeReplSetStatus(registry *bsoncodec.Registry, reply bson.Raw) (*ReplSetStatus, error) {
+	status := &ReplSetStatus{}
+	if err := bson.UnmarshalWithRegistry(registry, reply, status); err != nil {
+		return nil, err
+	}
+	for i, m := range status.Members {
+		if m.SyncSourceHost == "" {
+			status.Members[i].SyncSourceHost = m.SyncingTo
+		}
+	}
+	status.Raw = reply
+	return status, nil
+}
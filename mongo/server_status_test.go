@@ -0,0 +1,69 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDecodeServerStatus(t *testing.T) {
+	reply, err := bson.Marshal(bson.D{
+		{"host", "db1:27017"},
+		{"version", "4.2.1"},
+		{"process", "mongod"},
+		{"uptime", 1234.0},
+		{"connections", bson.D{{"current", int32(5)}, {"available", int32(51195)}, {"totalCreated", int64(42)}}},
+		{"opcounters", bson.D{{"insert", int64(10)}, {"query", int32(3)}, {"getmore", int64(1)}, {"command", int64(99)}}},
+		{"ok", 1.0},
+	})
+	require.NoError(t, err)
+
+	var status ServerStatus
+	require.NoError(t, bson.Unmarshal(reply, &status))
+	require.Equal(t, "db1:27017", status.Host)
+	require.Equal(t, 1234.0, status.Uptime)
+	require.Equal(t, ServerConnections{Current: 5, Available: 51195, TotalCreated: 42}, status.Connections)
+	require.Equal(t, Opcounters{Insert: 10, Query: 3, GetMore: 1, Command: 99}, status.Opcounters)
+}
+
+func TestReplSetStatus(t *testing.T) {
+	optime := time.Date(2019, 11, 5, 12, 0, 0, 0, time.UTC)
+	reply, err := bson.Marshal(bson.D{
+		{"set", "rs0"},
+		{"myState", int32(MemberStatePrimary)},
+		{"members", bson.A{
+			bson.D{{"_id", int32(0)}, {"name", "db1:27017"}, {"health", 1.0}, {"state", int32(1)},
+				{"stateStr", "PRIMARY"}, {"optimeDate", optime}, {"self", true}},
+			bson.D{{"_id", int32(1)}, {"name", "db2:27017"}, {"health", 1.0}, {"state", int32(2)},
+				{"stateStr", "SECONDARY"}, {"optimeDate", optime.Add(-3 * time.Second)}, {"pingMs", int64(2)},
+				{"syncSourceHost", "db1:27017"}},
+			bson.D{{"_id", int32(2)}, {"name", "db3:27017"}, {"health", 1.0}, {"state", int32(2)},
+				{"stateStr", "SECONDARY"}, {"optimeDate", optime}, {"syncingTo", "db2:27017"}},
+			bson.D{{"_id", int32(3)}, {"name", "db4:27017"}, {"health", 0.0}, {"state", int32(8)},
+				{"stateStr", "(not reachable/healthy)"}},
+		}},
+		{"ok", 1.0},
+	})
+	require.NoError(t, err)
+
+	status, err := decodeReplSetStatus(bson.DefaultRegistry, reply)
+	require.NoError(t, err)
+	require.Equal(t, "rs0", status.Set)
+	require.Len(t, status.Members, 4)
+	require.Equal(t, "db1:27017", status.Primary().Name)
+	require.Equal(t, "db1:27017", status.Members[1].SyncSourceHost)
+	require.Equal(t, "db2:27017", status.Members[2].SyncSourceHost)
+	require.Equal(t, map[string]time.Duration{"db2:27017": 3 * time.Second, "db3:27017": 0}, status.Lag())
+
+	status.Members[0].State = MemberStateSecondary
+	require.Nil(t, status.Primary())
+	require.Nil(t, status.Lag())
+}
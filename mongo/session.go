@@ -9,6 +9,7 @@ package mongo
 import (
 	"context"
 	"errors"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -24,6 +25,13 @@ import (
 // the method call is using.
 var ErrWrongClient = errors.New("session was not created by this client")
 
+// withTransactionTimeout is the maximum amount of time WithTransaction will spend retrying a
+// transaction before returning the last error to the caller.
+var withTransactionTimeout = 120 * time.Second
+
+// errorCodeMaxTimeMSExpired is the server error code returned when an operation exceeds maxTimeMS.
+const errorCodeMaxTimeMSExpired int32 = 50
+
 // SessionContext is a hybrid interface. It combines a context.Context with
 // a mongo.Session. This type can be used as a regular context.Context or
 // Session type. It is not goroutine safe and should not be used in multiple goroutines concurrently.
@@ -48,10 +56,14 @@ type Session interface {
 	StartTransaction(...*options.TransactionOptions) error
 	AbortTransaction(context.Context) error
 	CommitTransaction(context.Context) error
+	WithTransaction(ctx context.Context, fn func(sessCtx SessionContext) (interface{}, error),
+		opts ...*options.TransactionOptions) (interface{}, error)
 	ClusterTime() bson.Raw
 	AdvanceClusterTime(bson.Raw) error
 	OperationTime() *primitive.Timestamp
 	AdvanceOperationTime(*primitive.Timestamp) error
+	ID() bson.Raw
+	OperationCount() int64
 	session()
 }
 
@@ -59,6 +71,8 @@ type Session interface {
 type sessionImpl struct {
 	*session.Client
 	topo                *topology.Topology
+	clock               *session.ClusterClock
+	timeout             *time.Duration
 	didCommitAfterStart bool // true if commit was called after start with no other operations
 }
 
@@ -71,6 +85,95 @@ func (s *sessionImpl) EndSession(ctx context.Context) {
 	s.Client.EndSession()
 }
 
+// WithTransaction starts a transaction on this session and runs fn within it. The SessionContext
+// passed to fn must be used as the Context for every operation that should be part of the
+// transaction.
+//
+// If fn returns an error labeled TransientTransactionError, the transaction is aborted and the
+// entire transaction, including fn, is retried. If committing the transaction fails with an error
+// labeled UnknownTransactionCommitResult, the commit is retried; if it fails with an error labeled
+// TransientTransactionError, the entire transaction is retried. Retries stop once 120 seconds have
+// passed since WithTransaction was called, and the last error is returned. Because fn may be run
+// more than once, it must be idempotent.
+//
+// Any other error returned by fn aborts the transaction and is returned to the caller along with
+// fn's result. If fn commits or aborts the transaction itself, WithTransaction returns fn's result
+// without committing.
+func (s *sessionImpl) WithTransaction(ctx context.Context, fn func(sessCtx SessionContext) (interface{}, error),
+	opts ...*options.TransactionOptions) (interface{}, error) {
+
+	return s.withTransaction(ctx, fn, s.CommitTransaction, s.AbortTransaction, opts...)
+}
+
+// withTransaction implements WithTransaction, using commit and abort to end the transaction.
+func (s *sessionImpl) withTransaction(ctx context.Context, fn func(sessCtx SessionContext) (interface{}, error),
+	commit, abort func(context.Context) error, opts ...*options.TransactionOptions) (interface{}, error) {
+
+	deadline := time.Now().Add(withTransactionTimeout)
+
+	for {
+		if err := s.StartTransaction(opts...); err != nil {
+			return nil, err
+		}
+
+		res, err := fn(contextWithSession(ctx, s))
+		if err != nil {
+			if s.TransactionRunning() {
+				_ = abort(ctx)
+			}
+
+			if time.Now().Before(deadline) && hasErrorLabel(err, command.TransientTransactionError) {
+				continue
+			}
+			return res, err
+		}
+
+		// fn committed or aborted the transaction itself
+		if s.CheckAbortTransaction() != nil {
+			return res, nil
+		}
+
+		retryTransaction, err := commitWithRetry(ctx, commit, deadline)
+		if retryTransaction {
+			// The server aborted the transaction, but the failed commit left it running in the
+			// session, so it is aborted locally before it is started again.
+			_ = s.Client.AbortTransaction()
+			continue
+		}
+		return res, err
+	}
+}
+
+// commitWithRetry commits a transaction using commit, retrying while the commit result is unknown.
+// Retries stop once deadline has passed. It returns true if the commit failed with a transient error
+// and the whole transaction should be retried.
+func commitWithRetry(ctx context.Context, commit func(context.Context) error, deadline time.Time) (bool, error) {
+	for {
+		err := commit(ctx)
+		if err == nil || !time.Now().Before(deadline) {
+			return false, err
+		}
+
+		cerr, ok := err.(CommandError)
+		if !ok {
+			return false, err
+		}
+		if cerr.HasErrorLabel(command.UnknownTransactionCommitResult) && cerr.Code != errorCodeMaxTimeMSExpired {
+			continue
+		}
+		if cerr.HasErrorLabel(command.TransientTransactionError) {
+			return true, err
+		}
+		return false, err
+	}
+}
+
+// hasErrorLabel reports whether err is a CommandError with the given label.
+func hasErrorLabel(err error, label string) bool {
+	cerr, ok := err.(CommandError)
+	return ok && cerr.HasErrorLabel(label)
+}
+
 // StartTransaction starts a transaction for this session.
 func (s *sessionImpl) StartTransaction(opts ...*options.TransactionOptions) error {
 	err := s.CheckStartTransaction()
@@ -98,11 +201,16 @@ func (s *sessionImpl) AbortTransaction(ctx context.Context) error {
 	}
 
 	cmd := command.AbortTransaction{
+		Clock:   s.clock,
 		Session: s.Client,
 	}
 
+	ctx, cancel := operationContext(ctx, s.timeout)
+	defer cancel()
+
 	s.Aborting = true
 	_, err = driverlegacy.AbortTransaction(ctx, cmd, s.topo, description.WriteSelector())
+	err = timeoutError(ctx, err)
 
 	_ = s.Client.AbortTransaction()
 	return replaceErrors(err)
@@ -126,6 +234,7 @@ func (s *sessionImpl) CommitTransaction(ctx context.Context) error {
 	}
 
 	cmd := command.CommitTransaction{
+		Clock:   s.clock,
 		Session: s.Client,
 	}
 
@@ -136,11 +245,14 @@ func (s *sessionImpl) CommitTransaction(ctx context.Context) error {
 			s.Committing = false
 		}()
 	}
+	ctx, cancel := operationContext(ctx, s.timeout)
+	defer cancel()
+
 	_, err = driverlegacy.CommitTransaction(ctx, cmd, s.topo, description.WriteSelector())
 	if err == nil {
 		return s.Client.CommitTransaction()
 	}
-	return replaceErrors(err)
+	return replaceErrors(timeoutError(ctx, err))
 }
 
 func (s *sessionImpl) ClusterTime() bson.Raw {
@@ -159,15 +271,28 @@ func (s *sessionImpl) AdvanceOperationTime(ts *primitive.Timestamp) error {
 	return s.Client.AdvanceOperationTime(ts)
 }
 
+// ID returns the logical session ID (lsid) document for this session.
+func (s *sessionImpl) ID() bson.Raw {
+	idDoc, err := s.SessionID.MarshalBSON()
+	if err != nil {
+		return nil
+	}
+	return bson.Raw(idDoc)
+}
+
 func (*sessionImpl) session() {
 }
 
 // sessionFromContext checks for a sessionImpl in the argued context and returns the session if it
 // exists
 func sessionFromContext(ctx context.Context) *session.Client {
-	s := ctx.Value(sessionKey{})
-	if ses, ok := s.(*sessionImpl); ses != nil && ok {
-		return ses.Client
+	switch s := ctx.Value(sessionKey{}).(type) {
+	case *sessionImpl:
+		if s != nil {
+			return s.Client
+		}
+	case *lazySessionUse:
+		return s.get()
 	}
 
 	return nil
@@ -713,3 +713,12 @@ func TestSessions(t *testing.T) {
 		}
 	})
 }
+
+func TestSessionID(t *testing.T) {
+	id := bsonx.Doc{{"id", bsonx.Binary(session.UUIDSubtype, []byte{0x01, 0x02, 0x03, 0x04})}}
+	sess := &sessionImpl{Client: &session.Client{Server: &session.Server{SessionID: id}}}
+
+	expected, err := id.MarshalBSON()
+	require.NoError(t, err)
+	require.Equal(t, bson.Raw(expected), sess.ID())
+}
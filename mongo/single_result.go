@@ -26,6 +26,10 @@ type SingleResult struct {
 	cur *Cursor
 	rdr bson.Raw
 	reg *bsoncodec.Registry
+
+	server                ServerToken
+	concerns              OperationConcerns
+	disallowUnknownFields bool
 }
 
 // Decode will attempt to decode the first document into v. If there was an
@@ -39,51 +43,72 @@ func (sr *SingleResult) Decode(v interface{}) error {
 	if sr.reg == nil {
 		return bson.ErrNilRegistry
 	}
-	switch {
-	case sr.rdr != nil:
-		if v == nil {
-			return nil
-		}
-		return bson.UnmarshalWithRegistry(sr.reg, sr.rdr, v)
-	case sr.cur != nil:
-		defer sr.cur.Close(context.TODO())
-		if !sr.cur.Next(context.TODO()) {
-			if err := sr.cur.Err(); err != nil {
-				return err
-			}
-			return ErrNoDocuments
-		}
-		if v == nil {
-			return nil
-		}
-		return sr.cur.Decode(v)
+	doc, err := sr.Raw()
+	if err != nil {
+		return err
 	}
-
-	return ErrNoDocuments
+	if v == nil {
+		return nil
+	}
+	dc := bsoncodec.DecodeContext{Registry: sr.reg, DisallowUnknownFields: sr.disallowUnknownFields}
+	return bson.UnmarshalWithContext(dc, doc, v)
 }
 
-// DecodeBytes will return a copy of the document as a bson.Raw. If there was an
-// error from the operation that created this SingleResult then the error
-// will be returned. If there were no returned documents, ErrNoDocuments is
-// returned.
+// DecodeBytes will return the document as a bson.Raw. It is the same as Raw.
 func (sr *SingleResult) DecodeBytes() (bson.Raw, error) {
-	switch {
-	case sr.err != nil:
+	return sr.Raw()
+}
+
+// Raw returns the BSON bytes of the document without decoding or copying them, so that callers can
+// read a few fields lazily or forward the document as is. If there was an error from the operation
+// that created this SingleResult then the error will be returned. If there were no returned
+// documents, ErrNoDocuments is returned. Raw and Decode can be called several times.
+func (sr *SingleResult) Raw() (bson.Raw, error) {
+	if sr.err != nil {
 		return nil, sr.err
-	case sr.rdr != nil:
-		return sr.rdr, nil
-	case sr.cur != nil:
-		defer sr.cur.Close(context.TODO())
-		if !sr.cur.Next(context.TODO()) {
-			if err := sr.cur.Err(); err != nil {
-				return nil, err
-			}
-			return nil, ErrNoDocuments
+	}
+	if sr.cur != nil {
+		sr.readCursor()
+		if sr.err != nil {
+			return nil, sr.err
 		}
-		return sr.cur.Current, nil
 	}
+	if sr.rdr == nil {
+		return nil, ErrNoDocuments
+	}
+	return sr.rdr, nil
+}
+
+// readCursor reads the document from the cursor of the SingleResult, if any, and closes it.
+func (sr *SingleResult) readCursor() {
+	cur := sr.cur
+	sr.cur = nil
+	defer cur.Close(context.TODO())
+
+	if !cur.Next(context.TODO()) {
+		sr.err = cur.Err()
+		return
+	}
+	sr.rdr = cur.Current
+	sr.reg = cur.registry
+	sr.server = cur.ServerToken()
+	sr.disallowUnknownFields = cur.disallowUnknownFields
+}
+
+// ServerToken returns a token identifying the server that executed the operation that created this
+// SingleResult, or the zero ServerToken if it is not known.
+func (sr *SingleResult) ServerToken() ServerToken {
+	if sr.cur != nil {
+		return sr.cur.ServerToken()
+	}
+	return sr.server
+}
 
-	return nil, ErrNoDocuments
+// Concerns returns the concerns of the operation that created this SingleResult. They are also
+// reported if the operation failed after it was sent to the server, so that a failed operation can
+// be traced back to the read preference or write concern it used.
+func (sr *SingleResult) Concerns() OperationConcerns {
+	return sr.concerns
 }
 
 // Err will return the error from the operation that created this SingleResult.
@@ -0,0 +1,56 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSingleResultRaw(t *testing.T) {
+	t.Run("document", func(t *testing.T) {
+		doc, err := bson.Marshal(bson.D{{"foo", int32(1)}})
+		require.NoError(t, err)
+		sr := &SingleResult{rdr: doc, reg: bson.DefaultRegistry}
+
+		raw, err := sr.Raw()
+		require.NoError(t, err)
+		require.Equal(t, bson.Raw(doc), raw)
+		require.Equal(t, int32(1), raw.Lookup("foo").Int32())
+	})
+	t.Run("cursor is read once", func(t *testing.T) {
+		cursor, err := newCursor(newTestBatchCursor(1, 2), nil)
+		require.NoError(t, err)
+		sr := &SingleResult{cur: cursor, reg: bson.DefaultRegistry}
+
+		raw, err := sr.Raw()
+		require.NoError(t, err)
+		require.Equal(t, int32(0), raw.Lookup("foo").Int32())
+
+		var doc struct {
+			Foo int32 `bson:"foo"`
+		}
+		require.NoError(t, sr.Decode(&doc))
+		require.Equal(t, int32(0), doc.Foo)
+
+		raw, err = sr.DecodeBytes()
+		require.NoError(t, err)
+		require.Equal(t, int32(0), raw.Lookup("foo").Int32())
+	})
+	t.Run("no documents", func(t *testing.T) {
+		cursor, err := newCursor(newTestBatchCursor(0, 0), nil)
+		require.NoError(t, err)
+		sr := &SingleResult{cur: cursor, reg: bson.DefaultRegistry}
+
+		_, err = sr.Raw()
+		require.Equal(t, ErrNoDocuments, err)
+		require.Equal(t, ErrNoDocuments, sr.Decode(nil))
+		require.NoError(t, sr.Err())
+	})
+}
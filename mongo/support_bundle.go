@@ -0,0 +1,415 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/version"
+	"go.mongodb.org/mongo-driver/x/network/description"
+)
+
+// defaultDiagnosticEventCount is the number of recent events kept for SupportBundle when
+// ClientOptions.DiagnosticEventCount is not set.
+const defaultDiagnosticEventCount = 100
+
+// serverErrorCount is the number of recent errors kept for each server.
+const serverErrorCount = 10
+
+// diagnosticEvent is the summary of a monitoring event kept for SupportBundle. Command and reply
+// documents are never kept because they may contain credentials or application data.
+type diagnosticEvent struct {
+	Time         time.Time `json:"time"`
+	Type         string    `json:"type"`
+	Address      string    `json:"address,omitempty"`
+	ConnectionID string    `json:"connectionId,omitempty"`
+	DatabaseName string    `json:"databaseName,omitempty"`
+	CommandName  string    `json:"commandName,omitempty"`
+	RequestID    int64     `json:"requestId,omitempty"`
+	DurationMS   float64   `json:"durationMS,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	Failure      string    `json:"failure,omitempty"`
+}
+
+// serverError is an error recorded for a server. Operation is the name of the failed command, or
+// "heartbeat" or "checkOut" for failed server checks and connection checkouts.
+type serverError struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Code      int32     `json:"code,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// poolStats are the connection pool statistics for a server, computed from its pool events, and
+// its most recent errors, oldest first.
+type poolStats struct {
+	Address            string        `json:"address"`
+	MaxPoolSize        uint64        `json:"maxPoolSize"`
+	OpenConnections    int64         `json:"openConnections"`
+	CheckedOut         int64         `json:"checkedOut"`
+	ConnectionsCreated uint64        `json:"connectionsCreated"`
+	ConnectionsClosed  uint64        `json:"connectionsClosed"`
+	CheckOutsFailed    uint64        `json:"checkOutsFailed"`
+	Cleared            uint64        `json:"cleared"`
+	RecentErrors       []serverError `json:"recentErrors,omitempty"`
+}
+
+type bundleServer struct {
+	Address        string            `json:"address"`
+	Kind           string            `json:"kind"`
+	SetName        string            `json:"setName,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+	WireVersion    string            `json:"wireVersion,omitempty"`
+	AverageRTTMS   float64           `json:"averageRTTMS"`
+	LastUpdateTime time.Time         `json:"lastUpdateTime"`
+	LastError      string            `json:"lastError,omitempty"`
+}
+
+type bundleTopology struct {
+	Kind    string         `json:"kind"`
+	Servers []bundleServer `json:"servers"`
+}
+
+type supportBundle struct {
+	GeneratedAt   time.Time         `json:"generatedAt"`
+	DriverVersion string            `json:"driverVersion"`
+	Topology      bundleTopology    `json:"topology"`
+	Pools         []poolStats       `json:"pools"`
+	Events        []diagnosticEvent `json:"events"`
+}
+
+// eventRing records the most recent monitoring events of a client in a ring buffer and keeps
+// connection pool statistics and the most recent errors for each server.
+type eventRing struct {
+	mu     sync.Mutex
+	events []diagnosticEvent
+	next   int
+	full   bool
+	pools  map[string]*poolStats
+}
+
+func newEventRing(size int) *eventRing {
+	if size < 0 {
+		size = 0
+	}
+	return &eventRing{events: make([]diagnosticEvent, size), pools: make(map[string]*poolStats)}
+}
+
+func (r *eventRing) add(evt diagnosticEvent) {
+	if len(r.events) == 0 {
+		return
+	}
+	evt.Time = time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[r.next] = evt
+	r.next++
+	if r.next == len(r.events) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// snapshot returns the recorded events from oldest to newest and the pool statistics sorted by
+// address.
+func (r *eventRing) snapshot() ([]diagnosticEvent, []poolStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]diagnosticEvent, 0, len(r.events))
+	if r.full {
+		events = append(events, r.events[r.next:]...)
+	}
+	events = append(events, r.events[:r.next]...)
+
+	addrs := make([]string, 0, len(r.pools))
+	for addr := range r.pools {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	pools := make([]poolStats, 0, len(addrs))
+	for _, addr := range addrs {
+		ps := *r.pools[addr]
+		ps.RecentErrors = append([]serverError(nil), ps.RecentErrors...)
+		pools = append(pools, ps)
+	}
+	return events, pools
+}
+
+// addServerError records an error for the server at addr, dropping its oldest error if it already
+// has serverErrorCount errors. Errors are only recorded if events are.
+func (r *eventRing) addServerError(addr string, se serverError) {
+	if len(r.events) == 0 || addr == "" {
+		return
+	}
+	se.Time = time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ps := r.poolStatsLocked(addr)
+	if len(ps.RecentErrors) == serverErrorCount {
+		copy(ps.RecentErrors, ps.RecentErrors[1:])
+		ps.RecentErrors = ps.RecentErrors[:serverErrorCount-1]
+	}
+	ps.RecentErrors = append(ps.RecentErrors, se)
+}
+
+// poolStatsLocked returns the statistics for the server at addr, creating them if needed. r.mu must
+// be held.
+func (r *eventRing) poolStatsLocked(addr string) *poolStats {
+	ps, ok := r.pools[addr]
+	if !ok {
+		ps = &poolStats{Address: addr}
+		r.pools[addr] = ps
+	}
+	return ps
+}
+
+// commandMonitor returns a monitor that records command events and then forwards them to next,
+// which may be nil. If no events are recorded, next is returned.
+func (r *eventRing) commandMonitor(next *event.CommandMonitor) *event.CommandMonitor {
+	if len(r.events) == 0 {
+		return next
+	}
+	if next == nil {
+		next = &event.CommandMonitor{}
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			r.add(diagnosticEvent{
+				Type:         "CommandStarted",
+				Address:      evt.ServerAddress,
+				ConnectionID: evt.ConnectionID,
+				DatabaseName: evt.DatabaseName,
+				CommandName:  evt.CommandName,
+				RequestID:    evt.RequestID,
+			})
+			if next.Started != nil {
+				next.Started(ctx, evt)
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			r.add(diagnosticEvent{
+				Type:         "CommandSucceeded",
+				Address:      evt.ServerAddress,
+				ConnectionID: evt.ConnectionID,
+				CommandName:  evt.CommandName,
+				RequestID:    evt.RequestID,
+				DurationMS:   float64(evt.DurationNanos) / 1e6,
+			})
+			if next.Succeeded != nil {
+				next.Succeeded(ctx, evt)
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			r.add(diagnosticEvent{
+				Type:         "CommandFailed",
+				Address:      evt.ServerAddress,
+				ConnectionID: evt.ConnectionID,
+				CommandName:  evt.CommandName,
+				RequestID:    evt.RequestID,
+				DurationMS:   float64(evt.DurationNanos) / 1e6,
+				Failure:      evt.Failure,
+			})
+			se := serverError{Operation: evt.CommandName, Message: evt.Failure}
+			// Failures of commands the server replied to are formatted as "Error code <code>: <message>".
+			if n, _ := fmt.Sscanf(evt.Failure, "Error code %d:", &se.Code); n == 1 {
+				se.Message = strings.TrimPrefix(evt.Failure, fmt.Sprintf("Error code %d: ", se.Code))
+			}
+			r.addServerError(evt.ServerAddress, se)
+			if next.Failed != nil {
+				next.Failed(ctx, evt)
+			}
+		},
+	}
+}
+
+// poolMonitor returns a monitor that records connection pool events, updates the pool statistics,
+// and then forwards the events to next, which may be nil. Checkout and check-in events only update
+// the statistics.
+func (r *eventRing) poolMonitor(next *event.PoolMonitor) *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			r.updatePoolStats(evt)
+			if evt.Type != event.GetStarted && evt.Type != event.GetSucceeded && evt.Type != event.ConnectionReturned {
+				dEvt := diagnosticEvent{Type: evt.Type, Address: evt.Address, Reason: evt.Reason}
+				if evt.ConnectionID != 0 {
+					dEvt.ConnectionID = strconv.FormatUint(evt.ConnectionID, 10)
+				}
+				r.add(dEvt)
+			}
+			if evt.Type == event.GetFailed {
+				r.addServerError(evt.Address, serverError{Operation: "checkOut", Message: evt.Reason})
+			}
+			if next != nil && next.Event != nil {
+				next.Event(evt)
+			}
+		},
+	}
+}
+
+func (r *eventRing) updatePoolStats(evt *event.PoolEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ps := r.poolStatsLocked(evt.Address)
+	switch evt.Type {
+	case event.PoolCreated:
+		if evt.PoolOptions != nil {
+			ps.MaxPoolSize = evt.PoolOptions.MaxPoolSize
+		}
+	case event.PoolCleared:
+		ps.Cleared++
+	case event.ConnectionCreated:
+		ps.ConnectionsCreated++
+		ps.OpenConnections++
+	case event.ConnectionClosed:
+		ps.ConnectionsClosed++
+		ps.OpenConnections--
+	case event.GetFailed:
+		ps.CheckOutsFailed++
+	case event.GetSucceeded:
+		ps.CheckedOut++
+	case event.ConnectionReturned:
+		ps.CheckedOut--
+	}
+}
+
+// serverMonitor returns a monitor that records server discovery and monitoring events and then
+// forwards them to next, which may be nil. Heartbeats are only recorded when they fail. If no
+// events are recorded, next is returned.
+func (r *eventRing) serverMonitor(next *event.ServerMonitor) *event.ServerMonitor {
+	if len(r.events) == 0 {
+		return next
+	}
+	if next == nil {
+		next = &event.ServerMonitor{}
+	}
+
+	sm := *next
+	sm.ServerOpening = func(evt *event.ServerOpeningEvent) {
+		r.add(diagnosticEvent{Type: "ServerOpening", Address: evt.Address.String()})
+		if next.ServerOpening != nil {
+			next.ServerOpening(evt)
+		}
+	}
+	sm.ServerClosed = func(evt *event.ServerClosedEvent) {
+		r.add(diagnosticEvent{Type: "ServerClosed", Address: evt.Address.String()})
+		if next.ServerClosed != nil {
+			next.ServerClosed(evt)
+		}
+	}
+	sm.ServerDescriptionChanged = func(evt *event.ServerDescriptionChangedEvent) {
+		dEvt := diagnosticEvent{
+			Type:        "ServerDescriptionChanged",
+			Address:     evt.Address.String(),
+			Description: evt.PreviousDescription.Kind.String() + " -> " + evt.NewDescription.Kind.String(),
+		}
+		if evt.NewDescription.LastError != nil {
+			dEvt.Failure = evt.NewDescription.LastError.Error()
+		}
+		r.add(dEvt)
+		if next.ServerDescriptionChanged != nil {
+			next.ServerDescriptionChanged(evt)
+		}
+	}
+	sm.TopologyOpening = func(evt *event.TopologyOpeningEvent) {
+		r.add(diagnosticEvent{Type: "TopologyOpening"})
+		if next.TopologyOpening != nil {
+			next.TopologyOpening(evt)
+		}
+	}
+	sm.TopologyClosed = func(evt *event.TopologyClosedEvent) {
+		r.add(diagnosticEvent{Type: "TopologyClosed"})
+		if next.TopologyClosed != nil {
+			next.TopologyClosed(evt)
+		}
+	}
+	sm.TopologyDescriptionChanged = func(evt *event.TopologyDescriptionChangedEvent) {
+		r.add(diagnosticEvent{Type: "TopologyDescriptionChanged", Description: evt.NewDescription.String()})
+		if next.TopologyDescriptionChanged != nil {
+			next.TopologyDescriptionChanged(evt)
+		}
+	}
+	sm.ServerHeartbeatFailed = func(evt *event.ServerHeartbeatFailedEvent) {
+		dEvt := diagnosticEvent{
+			Type:         "ServerHeartbeatFailed",
+			ConnectionID: evt.ConnectionID,
+			DurationMS:   float64(evt.DurationNanos) / 1e6,
+		}
+		se := serverError{Operation: "heartbeat"}
+		if evt.Failure != nil {
+			dEvt.Failure = evt.Failure.Error()
+			se.Message = dEvt.Failure
+		}
+		r.add(dEvt)
+		// Heartbeat connection IDs are formatted as "<address>[-<id>]".
+		if i := strings.LastIndex(evt.ConnectionID, "[-"); i > 0 {
+			r.addServerError(evt.ConnectionID[:i], se)
+		}
+		if next.ServerHeartbeatFailed != nil {
+			next.ServerHeartbeatFailed(evt)
+		}
+	}
+	return &sm
+}
+
+// bundleTopologyFromDescription converts a topology description to its support bundle form.
+func bundleTopologyFromDescription(desc description.Topology) bundleTopology {
+	bt := bundleTopology{Kind: desc.Kind.String(), Servers: make([]bundleServer, 0, len(desc.Servers))}
+	for _, s := range desc.Servers {
+		bs := bundleServer{
+			Address:        s.Addr.String(),
+			Kind:           s.Kind.String(),
+			SetName:        s.SetName,
+			AverageRTTMS:   float64(s.AverageRTT) / float64(time.Millisecond),
+			LastUpdateTime: s.LastUpdateTime,
+		}
+		if len(s.Tags) > 0 {
+			bs.Tags = make(map[string]string, len(s.Tags))
+			for _, t := range s.Tags {
+				bs.Tags[t.Name] = t.Value
+			}
+		}
+		if s.WireVersion != nil {
+			bs.WireVersion = s.WireVersion.String()
+		}
+		if s.LastError != nil {
+			bs.LastError = s.LastError.Error()
+		}
+		bt.Servers = append(bt.Servers, bs)
+	}
+	return bt
+}
+
+// SupportBundle returns a JSON document describing the state of the client for attaching to bug
+// reports. It contains the current topology description, connection pool statistics and the last
+// failed commands, heartbeats, and connection checkouts of each server, and the most recent server
+// discovery and monitoring, connection pool, and command events, up to
+// ClientOptions.DiagnosticEventCount. It does not contain credentials or the command and reply
+// documents of commands.
+func (c *Client) SupportBundle() ([]byte, error) {
+	events, pools := c.events.snapshot()
+	return json.MarshalIndent(supportBundle{
+		GeneratedAt:   time.Now(),
+		DriverVersion: version.Driver,
+		Topology:      bundleTopologyFromDescription(c.topology.Description()),
+		Pools:         pools,
+		Events:        events,
+	}, "", "  ")
+}
@@ -0,0 +1,120 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestEventRing(t *testing.T) {
+	t.Run("keeps the most recent events", func(t *testing.T) {
+		r := newEventRing(2)
+		cm := r.commandMonitor(nil)
+		for _, name := range []string{"find", "insert", "update"} {
+			cm.Started(context.Background(), &event.CommandStartedEvent{CommandName: name})
+		}
+
+		events, _ := r.snapshot()
+		require.Len(t, events, 2)
+		require.Equal(t, "insert", events[0].CommandName)
+		require.Equal(t, "update", events[1].CommandName)
+	})
+	t.Run("pool statistics", func(t *testing.T) {
+		r := newEventRing(defaultDiagnosticEventCount)
+		var forwarded int
+		pm := r.poolMonitor(&event.PoolMonitor{Event: func(*event.PoolEvent) { forwarded++ }})
+		for _, typ := range []string{
+			event.ConnectionCreated, event.ConnectionCreated, event.GetSucceeded, event.GetSucceeded,
+			event.ConnectionReturned, event.ConnectionClosed, event.GetFailed, event.PoolCleared,
+		} {
+			pm.Event(&event.PoolEvent{Type: typ, Address: "localhost:27017", ConnectionID: 1})
+		}
+
+		events, pools := r.snapshot()
+		require.Equal(t, 8, forwarded)
+		require.Len(t, events, 5)
+		require.Len(t, pools, 1)
+		require.Len(t, pools[0].RecentErrors, 1)
+		pools[0].RecentErrors = nil
+		require.Equal(t, []poolStats{{
+			Address:            "localhost:27017",
+			OpenConnections:    1,
+			CheckedOut:         1,
+			ConnectionsCreated: 2,
+			ConnectionsClosed:  1,
+			CheckOutsFailed:    1,
+			Cleared:            1,
+		}}, pools)
+	})
+	t.Run("recent server errors", func(t *testing.T) {
+		r := newEventRing(defaultDiagnosticEventCount)
+		cm := r.commandMonitor(nil)
+		for i := 0; i < serverErrorCount; i++ {
+			cm.Failed(context.Background(), &event.CommandFailedEvent{
+				CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", ServerAddress: "localhost:27017"},
+				Failure:              "connection closed",
+			})
+		}
+		cm.Failed(context.Background(), &event.CommandFailedEvent{
+			CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "insert", ServerAddress: "localhost:27017"},
+			Failure:              "Error code 11000: E11000 duplicate key error",
+		})
+		r.poolMonitor(nil).Event(&event.PoolEvent{Type: event.GetFailed, Address: "localhost:27017", Reason: "timeout"})
+		r.serverMonitor(nil).ServerHeartbeatFailed(&event.ServerHeartbeatFailedEvent{
+			ConnectionID: "localhost:27018[-3]",
+			Failure:      errors.New("connection refused"),
+		})
+
+		_, pools := r.snapshot()
+		require.Len(t, pools, 2)
+		errs := pools[0].RecentErrors
+		require.Len(t, errs, serverErrorCount)
+		require.Equal(t, "find", errs[0].Operation)
+		require.Equal(t, "insert", errs[serverErrorCount-2].Operation)
+		require.Equal(t, int32(11000), errs[serverErrorCount-2].Code)
+		require.Equal(t, "E11000 duplicate key error", errs[serverErrorCount-2].Message)
+		require.Equal(t, serverError{Time: errs[serverErrorCount-1].Time, Operation: "checkOut", Message: "timeout"},
+			errs[serverErrorCount-1])
+		require.Equal(t, "localhost:27018", pools[1].Address)
+		require.Len(t, pools[1].RecentErrors, 1)
+		require.Equal(t, "heartbeat", pools[1].RecentErrors[0].Operation)
+		require.Equal(t, "connection refused", pools[1].RecentErrors[0].Message)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		r := newEventRing(0)
+		next := &event.CommandMonitor{}
+		require.Equal(t, next, r.commandMonitor(next))
+
+		r.poolMonitor(nil).Event(&event.PoolEvent{Type: event.ConnectionCreated, Address: "localhost:27017"})
+		events, pools := r.snapshot()
+		require.Len(t, events, 0)
+		require.Len(t, pools, 1)
+	})
+}
+
+func TestClient_SupportBundle(t *testing.T) {
+	c, err := NewClient(options.Client().ApplyURI("mongodb://localhost:27017").SetDiagnosticEventCount(10))
+	require.NoError(t, err)
+	c.events.add(diagnosticEvent{Type: "CommandStarted", CommandName: "ping"})
+
+	out, err := c.SupportBundle()
+	require.NoError(t, err)
+
+	var bundle supportBundle
+	require.NoError(t, json.Unmarshal(out, &bundle))
+	require.NotEmpty(t, bundle.DriverVersion)
+	require.Len(t, bundle.Events, 1)
+	require.Equal(t, "ping", bundle.Events[0].CommandName)
+	require.Len(t, c.events.events, 10)
+}
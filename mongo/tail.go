@@ -0,0 +1,141 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultTailRestartDelay is the time Tail waits before re-establishing a cursor by default.
+const defaultTailRestartDelay = time.Second
+
+const errorCursorNotFound int32 = 43
+
+// Tail tails a capped collection with a tailable awaitData cursor and sends the documents that
+// match filter on the returned channel as they are inserted. When the cursor is killed, loses its
+// position because the collection rolled over, is exhausted because the collection is empty, or
+// fails with a network error, Tail waits for the restart delay and re-establishes it from the _id of
+// the last document sent, so documents are sent in insertion order without duplicates.
+//
+// Both channels are closed when ctx is cancelled or another error occurs, which is sent on the
+// error channel first:
+//
+//	docs, errs := coll.Tail(ctx, bson.D{})
+//	for doc := range docs {
+//		// do something with doc....
+//	}
+//	if err := <-errs; err != nil && err != context.Canceled {
+//		log.Fatal(err)
+//	}
+func (coll *Collection) Tail(ctx context.Context, filter interface{}, opts ...*options.TailOptions) (<-chan bson.Raw, <-chan error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	to := options.MergeTailOptions(opts...)
+
+	bufferSize := 0
+	if to.BufferSize != nil && *to.BufferSize > 0 {
+		bufferSize = *to.BufferSize
+	}
+	docs := make(chan bson.Raw, bufferSize)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(docs)
+
+		if err := coll.tail(ctx, filter, to, docs); err != nil {
+			errs <- err
+		}
+	}()
+
+	return docs, errs
+}
+
+// tail re-establishes tailable cursors until ctx is cancelled or an error that is not restartable
+// occurs.
+func (coll *Collection) tail(ctx context.Context, filter interface{}, to *options.TailOptions, docs chan<- bson.Raw) error {
+	restartDelay := defaultTailRestartDelay
+	if to.RestartDelay != nil {
+		restartDelay = *to.RestartDelay
+	}
+	findOpts := options.Find().SetCursorType(options.TailableAwait)
+	if to.MaxAwaitTime != nil {
+		findOpts.SetMaxAwaitTime(*to.MaxAwaitTime)
+	}
+
+	lastID := to.StartAfter
+	for {
+		cur, err := coll.Find(ctx, tailFilter(filter, lastID), findOpts)
+		if err == nil {
+			lastID, err = tailCursor(ctx, cur, lastID, docs)
+
+			closeCtx, cancel := context.WithTimeout(context.Background(), streamCloseTimeout)
+			_ = cur.Close(closeCtx)
+			cancel()
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil && !isTailRestartable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(restartDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// tailCursor sends the documents of cur on docs until it is exhausted or fails, and returns the
+// _id of the last document sent.
+func tailCursor(ctx context.Context, cur *Cursor, lastID interface{}, docs chan<- bson.Raw) (interface{}, error) {
+	for cur.Next(ctx) {
+		doc := make(bson.Raw, len(cur.Current))
+		copy(doc, cur.Current)
+
+		select {
+		case docs <- doc:
+		case <-ctx.Done():
+			return lastID, ctx.Err()
+		}
+		if id, err := doc.LookupErr("_id"); err == nil {
+			lastID = id
+		}
+	}
+	return lastID, replaceErrors(cur.Err())
+}
+
+// tailFilter returns filter restricted to the documents after lastID, if any.
+func tailFilter(filter interface{}, lastID interface{}) interface{} {
+	if lastID == nil {
+		return filter
+	}
+	return bson.D{{"$and", bson.A{filter, bson.D{{"_id", bson.D{{"$gt", lastID}}}}}}}
+}
+
+// isTailRestartable returns true if Tail re-establishes its cursor after err.
+func isTailRestartable(err error) bool {
+	if cerr, ok := err.(CommandError); ok {
+		switch cerr.Code {
+		case errorCursorNotFound, errorCappedPositionLost, errorCursorKilled:
+			return true
+		}
+	}
+	return IsNetworkError(err)
+}
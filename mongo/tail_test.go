@@ -0,0 +1,44 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/network/command"
+)
+
+func TestTailFilter(t *testing.T) {
+	filter := bson.D{{"x", 1}}
+	require.Equal(t, filter, tailFilter(filter, nil))
+	require.Equal(t,
+		bson.D{{"$and", bson.A{filter, bson.D{{"_id", bson.D{{"$gt", int32(5)}}}}}}},
+		tailFilter(filter, int32(5)))
+}
+
+func TestIsTailRestartable(t *testing.T) {
+	testCases := []struct {
+		name        string
+		err         error
+		restartable bool
+	}{
+		{"cursor not found", CommandError{Code: 43}, true},
+		{"capped position lost", CommandError{Code: 136}, true},
+		{"cursor killed", CommandError{Code: 237}, true},
+		{"network error", CommandError{Code: 6, Labels: []string{command.NetworkError}}, true},
+		{"unauthorized", CommandError{Code: 13}, false},
+		{"other error", errors.New("decode error"), false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.restartable, isTailRestartable(tc.err))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InsertMeasurements inserts documents into a time series collection. Before the documents are
+// sent they are grouped by the value of metaField, in the order each value first appears, and
+// sorted by the value of timeField within each group. Measurements that share a meta field value
+// and arrive in time order fill the same bucket on the server, which keeps the number of buckets
+// small for high-frequency writers.
+//
+// Every document must contain timeField as a BSON datetime. Documents without metaField are grouped
+// together. The InsertedIDs of the result and the indexes of any write errors refer to the
+// positions of documents in the documents slice, not to the order in which they were sent. Since
+// the documents are reordered, an ordered insert that fails may leave documents that appear
+// earlier in documents uninserted.
+func (coll *Collection) InsertMeasurements(ctx context.Context, timeField, metaField string,
+	documents []interface{}, opts ...*options.InsertManyOptions) (*InsertManyResult, error) {
+
+	if len(documents) == 0 {
+		return nil, ErrEmptySlice
+	}
+
+	raws := make([]bson.Raw, len(documents))
+	for i, doc := range documents {
+		if doc == nil {
+			return nil, ErrNilDocument
+		}
+		b, err := bson.MarshalWithRegistry(coll.registry, doc)
+		if err != nil {
+			return nil, MarshalError{Value: doc, Err: err}
+		}
+		raws[i] = b
+	}
+
+	order, err := orderMeasurements(raws, timeField, metaField)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]interface{}, len(order))
+	for i, idx := range order {
+		sorted[i] = raws[idx]
+	}
+
+	res, err := coll.InsertMany(ctx, sorted, opts...)
+	if res != nil {
+		ids := make([]interface{}, len(res.InsertedIDs))
+		for i, id := range res.InsertedIDs {
+			ids[order[i]] = id
+		}
+		res.InsertedIDs = ids
+	}
+	if bwe, ok := err.(BulkWriteException); ok {
+		for i := range bwe.WriteErrors {
+			if idx := bwe.WriteErrors[i].Index; idx >= 0 && idx < len(order) {
+				bwe.WriteErrors[i].Index = order[idx]
+			}
+		}
+		err = bwe
+	}
+	return res, err
+}
+
+// measurement is the sort key of a document passed to InsertMeasurements.
+type measurement struct {
+	index int   // the position of the document in the input
+	group int   // the order in which the meta field value of the document first appeared
+	time  int64 // the time field of the document in milliseconds since the epoch
+}
+
+type measurements []measurement
+
+func (m measurements) Len() int      { return len(m) }
+func (m measurements) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m measurements) Less(i, j int) bool {
+	if m[i].group != m[j].group {
+		return m[i].group < m[j].group
+	}
+	return m[i].time < m[j].time
+}
+
+// orderMeasurements returns the indexes of docs in the order they should be inserted.
+func orderMeasurements(docs []bson.Raw, timeField, metaField string) ([]int, error) {
+	groups := make(map[string]int)
+	ms := make(measurements, len(docs))
+	for i, doc := range docs {
+		t, err := doc.LookupErr(timeField)
+		if err != nil {
+			return nil, fmt.Errorf("document %d is missing the time field %q", i, timeField)
+		}
+		if t.Type != bsontype.DateTime {
+			return nil, fmt.Errorf("time field %q of document %d is a %s, not a datetime", timeField, i, t.Type)
+		}
+
+		// The meta field is compared by its raw bytes, so values of different types or with
+		// differently ordered embedded fields are placed in different groups.
+		var key string
+		if meta, err := doc.LookupErr(metaField); err == nil {
+			key = string(append([]byte{byte(meta.Type)}, meta.Value...))
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = len(groups)
+			groups[key] = group
+		}
+
+		ms[i] = measurement{index: i, group: group, time: t.DateTime()}
+	}
+
+	sort.Stable(ms)
+
+	order := make([]int, len(ms))
+	for i, m := range ms {
+		order[i] = m.index
+	}
+	return order, nil
+}
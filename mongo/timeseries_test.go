@@ -0,0 +1,58 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestOrderMeasurements(t *testing.T) {
+	doc := func(meta interface{}, ms int64) bson.Raw {
+		d := bson.D{{"ts", primitive.DateTime(ms)}}
+		if meta != nil {
+			d = append(d, bson.E{Key: "meta", Value: meta})
+		}
+		b, err := bson.Marshal(d)
+		require.NoError(t, err)
+		return b
+	}
+
+	t.Run("groups by meta field and sorts by time", func(t *testing.T) {
+		docs := []bson.Raw{
+			doc("b", 3),
+			doc("a", 2),
+			doc("b", 1),
+			doc(nil, 5),
+			doc("a", 1),
+			doc(nil, 4),
+			doc(bson.D{{"sensor", 1}}, 7),
+			doc(bson.D{{"sensor", 1}}, 6),
+		}
+		order, err := orderMeasurements(docs, "ts", "meta")
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 0, 4, 1, 5, 3, 7, 6}, order)
+	})
+	t.Run("keeps input order of equal times", func(t *testing.T) {
+		order, err := orderMeasurements([]bson.Raw{doc("a", 1), doc("a", 1), doc("a", 0)}, "ts", "meta")
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 0, 1}, order)
+	})
+	t.Run("missing time field", func(t *testing.T) {
+		_, err := orderMeasurements([]bson.Raw{doc("a", 1)}, "time", "meta")
+		require.Error(t, err)
+	})
+	t.Run("time field is not a datetime", func(t *testing.T) {
+		b, err := bson.Marshal(bson.D{{"ts", "yesterday"}})
+		require.NoError(t, err)
+		_, err = orderMeasurements([]bson.Raw{b}, "ts", "meta")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,250 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// unifiedTypeAliases maps the type names used by the $$type operator to BSON types.
+var unifiedTypeAliases = map[string][]bsontype.Type{
+	"double":              {bsontype.Double},
+	"string":              {bsontype.String},
+	"object":              {bsontype.EmbeddedDocument},
+	"array":               {bsontype.Array},
+	"binData":             {bsontype.Binary},
+	"undefined":           {bsontype.Undefined},
+	"objectId":            {bsontype.ObjectID},
+	"bool":                {bsontype.Boolean},
+	"date":                {bsontype.DateTime},
+	"null":                {bsontype.Null},
+	"regex":               {bsontype.Regex},
+	"dbPointer":           {bsontype.DBPointer},
+	"javascript":          {bsontype.JavaScript},
+	"symbol":              {bsontype.Symbol},
+	"javascriptWithScope": {bsontype.CodeWithScope},
+	"int":                 {bsontype.Int32},
+	"timestamp":           {bsontype.Timestamp},
+	"long":                {bsontype.Int64},
+	"decimal":             {bsontype.Decimal128},
+	"minKey":              {bsontype.MinKey},
+	"maxKey":              {bsontype.MaxKey},
+	"number":              {bsontype.Int32, bsontype.Int64, bsontype.Double, bsontype.Decimal128},
+}
+
+// matchValues returns an error if actual does not satisfy expected. A missing actual value is
+// represented by the zero RawValue. Root-level documents may contain keys that are not present in
+// the expected document; nested documents must match exactly. The entity map is only consulted
+// for $$sessionLsid and may be nil.
+func matchValues(entities *unifiedEntityMap, key string, expected, actual bson.RawValue, root bool) error {
+	if doc, ok := expected.DocumentOK(); ok {
+		if op, ok := specialMatchOperator(doc); ok {
+			return evaluateMatchOperator(entities, key, op, doc.Lookup(op), actual, root)
+		}
+	}
+
+	if actual.Type == 0 {
+		return fmt.Errorf("key %q: expected %s, got nothing", key, expected)
+	}
+
+	switch expected.Type {
+	case bsontype.EmbeddedDocument:
+		actualDoc, ok := actual.DocumentOK()
+		if !ok {
+			return fmt.Errorf("key %q: expected document, got %s", key, actual.Type)
+		}
+		return matchDocuments(entities, key, expected.Document(), actualDoc, root)
+	case bsontype.Array:
+		actualArr, ok := actual.ArrayOK()
+		if !ok {
+			return fmt.Errorf("key %q: expected array, got %s", key, actual.Type)
+		}
+		expectedVals, err := expected.Array().Values()
+		if err != nil {
+			return err
+		}
+		actualVals, err := actualArr.Values()
+		if err != nil {
+			return err
+		}
+		if len(expectedVals) != len(actualVals) {
+			return fmt.Errorf("key %q: expected array of length %d, got %d", key, len(expectedVals), len(actualVals))
+		}
+		for i := range expectedVals {
+			if err := matchValues(entities, fmt.Sprintf("%s.%d", key, i), expectedVals[i], actualVals[i], false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if isUnifiedNumber(expected) && isUnifiedNumber(actual) {
+		if expected.AsFloat64() != actual.AsFloat64() {
+			return fmt.Errorf("key %q: expected %s, got %s", key, expected, actual)
+		}
+		return nil
+	}
+	if !expected.Equal(actual) {
+		return fmt.Errorf("key %q: expected %s, got %s", key, expected, actual)
+	}
+	return nil
+}
+
+func matchDocuments(entities *unifiedEntityMap, key string, expected, actual bson.Raw, root bool) error {
+	elems, err := expected.Elements()
+	if err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		k := elem.Key()
+		actualVal, _ := actual.LookupErr(k)
+		if err := matchValues(entities, joinMatchKey(key, k), elem.Value(), actualVal, false); err != nil {
+			return err
+		}
+	}
+	if root {
+		return nil
+	}
+
+	actualElems, err := actual.Elements()
+	if err != nil {
+		return err
+	}
+	for _, elem := range actualElems {
+		if _, err := expected.LookupErr(elem.Key()); err != nil {
+			return fmt.Errorf("key %q: unexpected key %q in %s", key, elem.Key(), actual)
+		}
+	}
+	return nil
+}
+
+func evaluateMatchOperator(entities *unifiedEntityMap, key, op string, arg, actual bson.RawValue, root bool) error {
+	switch op {
+	case "$$exists":
+		if exists := actual.Type != 0; exists != arg.Boolean() {
+			return fmt.Errorf("key %q: expected exists to be %v", key, arg.Boolean())
+		}
+		return nil
+	case "$$type":
+		var names []string
+		if arr, ok := arg.ArrayOK(); ok {
+			vals, err := arr.Values()
+			if err != nil {
+				return err
+			}
+			for _, v := range vals {
+				names = append(names, v.StringValue())
+			}
+		} else {
+			names = []string{arg.StringValue()}
+		}
+		for _, name := range names {
+			types, ok := unifiedTypeAliases[name]
+			if !ok {
+				return fmt.Errorf("key %q: unknown type alias %q", key, name)
+			}
+			for _, t := range types {
+				if actual.Type == t {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("key %q: expected type %v, got %s", key, names, actual.Type)
+	case "$$unsetOrMatches":
+		if actual.Type == 0 {
+			return nil
+		}
+		return matchValues(entities, key, arg, actual, root)
+	case "$$sessionLsid":
+		if entities == nil {
+			return fmt.Errorf("key %q: no entities to resolve $$sessionLsid", key)
+		}
+		lsid, ok := entities.sessionLsids[arg.StringValue()]
+		if !ok {
+			return fmt.Errorf("key %q: no session entity %q", key, arg.StringValue())
+		}
+		return matchValues(entities, key, bson.RawValue{Type: bsontype.EmbeddedDocument, Value: lsid}, actual, false)
+	}
+	return fmt.Errorf("key %q: unsupported operator %s", key, op)
+}
+
+// specialMatchOperator returns the operator name if doc is a single-key document whose key
+// starts with "$$".
+func specialMatchOperator(doc bson.Raw) (string, bool) {
+	elems, err := doc.Elements()
+	if err != nil || len(elems) != 1 {
+		return "", false
+	}
+	if k := elems[0].Key(); strings.HasPrefix(k, "$$") {
+		return k, true
+	}
+	return "", false
+}
+
+func isUnifiedNumber(val bson.RawValue) bool {
+	switch val.Type {
+	case bsontype.Int32, bsontype.Int64, bsontype.Double:
+		return true
+	}
+	return false
+}
+
+func joinMatchKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func TestUnifiedMatchValues(t *testing.T) {
+	rawValue := func(t *testing.T, ejson string) bson.RawValue {
+		var doc bson.Raw
+		require.NoError(t, bson.UnmarshalExtJSON([]byte(`{"v": `+ejson+`}`), false, &doc))
+		return doc.Lookup("v")
+	}
+
+	testCases := []struct {
+		name     string
+		expected string
+		actual   string
+		root     bool
+		match    bool
+	}{
+		{"equal documents", `{"x": 1}`, `{"x": 1}`, false, true},
+		{"numeric types are interchangeable", `{"x": 1}`, `{"x": {"$numberLong": "1"}}`, false, true},
+		{"extra keys allowed at root", `{"x": 1}`, `{"x": 1, "y": 2}`, true, true},
+		{"extra keys rejected when nested", `{"x": 1}`, `{"x": 1, "y": 2}`, false, false},
+		{"missing key", `{"x": 1}`, `{"y": 1}`, true, false},
+		{"array length mismatch", `[1, 2]`, `[1]`, false, false},
+		{"exists true", `{"x": {"$$exists": true}}`, `{"x": 1}`, true, true},
+		{"exists false", `{"x": {"$$exists": false}}`, `{"x": 1}`, true, false},
+		{"exists false on missing key", `{"x": {"$$exists": false}}`, `{}`, true, true},
+		{"type match", `{"x": {"$$type": "string"}}`, `{"x": "foo"}`, true, true},
+		{"type mismatch", `{"x": {"$$type": "string"}}`, `{"x": 1}`, true, false},
+		{"type list", `{"x": {"$$type": ["int", "long"]}}`, `{"x": {"$numberLong": "3"}}`, true, true},
+		{"type number", `{"x": {"$$type": "number"}}`, `{"x": 1.5}`, true, true},
+		{"unsetOrMatches unset", `{"x": {"$$unsetOrMatches": 1}}`, `{}`, true, true},
+		{"unsetOrMatches match", `{"x": {"$$unsetOrMatches": 1}}`, `{"x": 1}`, true, true},
+		{"unsetOrMatches mismatch", `{"x": {"$$unsetOrMatches": 1}}`, `{"x": 2}`, true, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := matchValues(nil, "", rawValue(t, tc.expected), rawValue(t, tc.actual), tc.root)
+			if tc.match {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,891 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/internal/testutil"
+	"go.mongodb.org/mongo-driver/internal/testutil/helpers"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+const unifiedTestsDir = "../data/unified-test-format"
+
+// unifiedSchemaVersion is the newest schemaVersion of the unified test format the runner understands.
+const unifiedSchemaVersion = "1.0"
+
+// unifiedIgnoredCommands are never recorded by client entities: the handshake and authentication
+// commands are security sensitive, and configureFailPoint is issued by the runner itself.
+var unifiedIgnoredCommands = map[string]bool{
+	"hello":              true,
+	"ismaster":           true,
+	"isMaster":           true,
+	"saslStart":          true,
+	"saslContinue":       true,
+	"getnonce":           true,
+	"authenticate":       true,
+	"configureFailPoint": true,
+}
+
+var unifiedCtx = context.Background()
+
+type unifiedTestFile struct {
+	Description       string                     `json:"description"`
+	SchemaVersion     string                     `json:"schemaVersion"`
+	RunOnRequirements []unifiedRunOnRequirement  `json:"runOnRequirements"`
+	CreateEntities    []map[string]unifiedEntity `json:"createEntities"`
+	InitialData       []unifiedCollectionData    `json:"initialData"`
+	Tests             []unifiedTestCase          `json:"tests"`
+}
+
+type unifiedRunOnRequirement struct {
+	MinServerVersion string   `json:"minServerVersion"`
+	MaxServerVersion string   `json:"maxServerVersion"`
+	Topologies       []string `json:"topologies"`
+}
+
+type unifiedEntity struct {
+	ID                  string                 `json:"id"`
+	Client              string                 `json:"client"`
+	Database            string                 `json:"database"`
+	DatabaseName        string                 `json:"databaseName"`
+	CollectionName      string                 `json:"collectionName"`
+	URIOptions          map[string]interface{} `json:"uriOptions"`
+	UseMultipleMongoses *bool                  `json:"useMultipleMongoses"`
+	ObserveEvents       []string               `json:"observeEvents"`
+	IgnoreCommands      []string               `json:"ignoreCommandMonitoringEvents"`
+	SessionOptions      map[string]interface{} `json:"sessionOptions"`
+	BucketOptions       map[string]interface{} `json:"bucketOptions"`
+}
+
+type unifiedCollectionData struct {
+	CollectionName string            `json:"collectionName"`
+	DatabaseName   string            `json:"databaseName"`
+	Documents      []json.RawMessage `json:"documents"`
+}
+
+type unifiedTestCase struct {
+	Description       string                    `json:"description"`
+	SkipReason        string                    `json:"skipReason"`
+	RunOnRequirements []unifiedRunOnRequirement `json:"runOnRequirements"`
+	Operations        []unifiedOperation        `json:"operations"`
+	ExpectEvents      []unifiedExpectedEvents   `json:"expectEvents"`
+	Outcome           []unifiedCollectionData   `json:"outcome"`
+}
+
+type unifiedOperation struct {
+	Name               string              `json:"name"`
+	Object             string              `json:"object"`
+	Arguments          json.RawMessage     `json:"arguments"`
+	ExpectError        *unifiedExpectError `json:"expectError"`
+	ExpectResult       json.RawMessage     `json:"expectResult"`
+	SaveResultAsEntity string              `json:"saveResultAsEntity"`
+}
+
+type unifiedExpectError struct {
+	IsError            bool            `json:"isError"`
+	IsClientError      *bool           `json:"isClientError"`
+	ErrorContains      string          `json:"errorContains"`
+	ErrorCode          *int32          `json:"errorCode"`
+	ErrorCodeName      string          `json:"errorCodeName"`
+	ErrorLabelsContain []string        `json:"errorLabelsContain"`
+	ErrorLabelsOmit    []string        `json:"errorLabelsOmit"`
+	ExpectResult       json.RawMessage `json:"expectResult"`
+}
+
+type unifiedExpectedEvents struct {
+	Client string                       `json:"client"`
+	Events []map[string]json.RawMessage `json:"events"`
+}
+
+// unifiedClient is a client entity along with the command monitoring events it has observed.
+type unifiedClient struct {
+	*mongo.Client
+	observe map[string]bool
+	ignore  map[string]bool
+
+	mu     sync.Mutex
+	events []unifiedEvent
+}
+
+type unifiedEvent struct {
+	kind        string
+	commandName string
+	database    string
+	command     bson.Raw
+	reply       bson.Raw
+}
+
+func (c *unifiedClient) record(kind string, evt unifiedEvent) {
+	if !c.observe[kind] || c.ignore[evt.commandName] || unifiedIgnoredCommands[evt.commandName] {
+		return
+	}
+	evt.kind = kind
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, evt)
+}
+
+func (c *unifiedClient) monitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			c.record("commandStartedEvent", unifiedEvent{commandName: e.CommandName, database: e.DatabaseName, command: e.Command})
+		},
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			c.record("commandSucceededEvent", unifiedEvent{commandName: e.CommandName, reply: e.Reply})
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			c.record("commandFailedEvent", unifiedEvent{commandName: e.CommandName})
+		},
+	}
+}
+
+// unifiedEntityMap holds the entities created for a single test.
+type unifiedEntityMap struct {
+	clients      map[string]*unifiedClient
+	databases    map[string]*mongo.Database
+	collections  map[string]*mongo.Collection
+	sessions     map[string]mongo.Session
+	sessionLsids map[string]bson.Raw
+	buckets      map[string]*gridfs.Bucket
+	results      map[string]bson.RawValue
+}
+
+func newUnifiedEntityMap() *unifiedEntityMap {
+	return &unifiedEntityMap{
+		clients:      make(map[string]*unifiedClient),
+		databases:    make(map[string]*mongo.Database),
+		collections:  make(map[string]*mongo.Collection),
+		sessions:     make(map[string]mongo.Session),
+		sessionLsids: make(map[string]bson.Raw),
+		buckets:      make(map[string]*gridfs.Bucket),
+		results:      make(map[string]bson.RawValue),
+	}
+}
+
+func (em *unifiedEntityMap) close() {
+	for _, sess := range em.sessions {
+		sess.EndSession(unifiedCtx)
+	}
+	for _, client := range em.clients {
+		_ = client.Disconnect(unifiedCtx)
+	}
+}
+
+// unifiedFailPoint is a fail point enabled during a test, to be disabled when the test ends.
+type unifiedFailPoint struct {
+	client *mongo.Client
+	name   string
+}
+
+func TestUnifiedSpec(t *testing.T) {
+	testutil.Integration(t)
+
+	for _, file := range testhelpers.FindJSONFilesInDir(t, unifiedTestsDir) {
+		runUnifiedTestFile(t, path.Join(unifiedTestsDir, file))
+	}
+}
+
+func runUnifiedTestFile(t *testing.T, filepath string) {
+	content, err := ioutil.ReadFile(filepath)
+	require.NoError(t, err)
+
+	var testfile unifiedTestFile
+	require.NoError(t, json.Unmarshal(content, &testfile))
+
+	t.Run(testfile.Description, func(t *testing.T) {
+		if testutil.CompareVersions(t, testfile.SchemaVersion, unifiedSchemaVersion) > 0 {
+			t.Skipf("unsupported schema version %s", testfile.SchemaVersion)
+		}
+
+		setupClient, err := mongo.Connect(unifiedCtx, options.Client().ApplyURI(unifiedURI(t)))
+		require.NoError(t, err)
+		defer func() { _ = setupClient.Disconnect(unifiedCtx) }()
+
+		serverVersion := unifiedServerVersion(t, setupClient)
+		if !unifiedRequirementsMet(t, testfile.RunOnRequirements, serverVersion) {
+			t.Skip("runOnRequirements not met")
+		}
+
+		for _, test := range testfile.Tests {
+			t.Run(test.Description, func(t *testing.T) {
+				if test.SkipReason != "" {
+					t.Skip(test.SkipReason)
+				}
+				if !unifiedRequirementsMet(t, test.RunOnRequirements, serverVersion) {
+					t.Skip("runOnRequirements not met")
+				}
+				runUnifiedTestCase(t, &testfile, &test, setupClient)
+			})
+		}
+	})
+}
+
+func runUnifiedTestCase(t *testing.T, testfile *unifiedTestFile, test *unifiedTestCase, setupClient *mongo.Client) {
+	insertUnifiedData(t, setupClient, testfile.InitialData)
+
+	entities := newUnifiedEntityMap()
+	defer entities.close()
+	for _, def := range testfile.CreateEntities {
+		createUnifiedEntity(t, entities, def)
+	}
+
+	var failPoints []unifiedFailPoint
+	defer func() {
+		for _, fp := range failPoints {
+			_ = fp.client.Database("admin").RunCommand(unifiedCtx, bson.D{
+				{"configureFailPoint", fp.name},
+				{"mode", "off"},
+			}).Err()
+		}
+	}()
+
+	for i := range test.Operations {
+		op := &test.Operations[i]
+		if op.Object == "testRunner" {
+			if fp := executeUnifiedTestRunnerOperation(t, entities, op); fp != nil {
+				failPoints = append(failPoints, *fp)
+			}
+			continue
+		}
+
+		res, err := executeUnifiedOperation(t, entities, op)
+		verifyUnifiedOperationOutcome(t, entities, op, res, err)
+		if op.SaveResultAsEntity != "" {
+			entities.results[op.SaveResultAsEntity] = res
+		}
+	}
+
+	for _, expected := range test.ExpectEvents {
+		verifyUnifiedEvents(t, entities, expected)
+	}
+
+	for _, outcome := range test.Outcome {
+		coll := setupClient.Database(outcome.DatabaseName).Collection(outcome.CollectionName)
+		cur, err := coll.Find(unifiedCtx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+		require.NoError(t, err)
+		var actual []bson.Raw
+		require.NoError(t, cur.All(unifiedCtx, &actual))
+
+		require.Equal(t, len(outcome.Documents), len(actual), "document count mismatch in %s", outcome.CollectionName)
+		for i, doc := range outcome.Documents {
+			expected := unifiedRawDocument(t, doc)
+			require.NoError(t, matchDocuments(entities, "", expected, actual[i], false))
+		}
+	}
+}
+
+func unifiedServerVersion(t *testing.T, client *mongo.Client) string {
+	res, err := client.Database("admin").RunCommand(unifiedCtx, bson.D{{"buildInfo", 1}}).DecodeBytes()
+	require.NoError(t, err)
+	version := res.Lookup("version").StringValue()
+	if idx := strings.IndexByte(version, '-'); idx != -1 {
+		version = version[:idx]
+	}
+	return version
+}
+
+func unifiedURI(t *testing.T) string {
+	cs := testutil.ConnString(t)
+	return cs.String()
+}
+
+// unifiedTopology maps the TOPOLOGY environment variable used by the test harness to the topology
+// names in runOnRequirements.
+func unifiedTopology() string {
+	switch os.Getenv("TOPOLOGY") {
+	case "replica_set":
+		return "replicaset"
+	case "sharded_cluster":
+		return "sharded"
+	}
+	return "single"
+}
+
+func unifiedRequirementsMet(t *testing.T, reqs []unifiedRunOnRequirement, serverVersion string) bool {
+	if len(reqs) == 0 {
+		return true
+	}
+	topology := unifiedTopology()
+	for _, req := range reqs {
+		if req.MinServerVersion != "" && testutil.CompareVersions(t, serverVersion, req.MinServerVersion) < 0 {
+			continue
+		}
+		if req.MaxServerVersion != "" && testutil.CompareVersions(t, serverVersion, req.MaxServerVersion) > 0 {
+			continue
+		}
+		if len(req.Topologies) > 0 && !containsString(req.Topologies, topology) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
+
+func insertUnifiedData(t *testing.T, client *mongo.Client, data []unifiedCollectionData) {
+	wcMajority := options.Collection().SetWriteConcern(writeconcern.New(writeconcern.WMajority()))
+	for _, cd := range data {
+		coll := client.Database(cd.DatabaseName).Collection(cd.CollectionName, wcMajority)
+		require.NoError(t, coll.Drop(unifiedCtx))
+
+		if len(cd.Documents) == 0 {
+			err := client.Database(cd.DatabaseName).RunCommand(unifiedCtx, bson.D{
+				{"create", cd.CollectionName},
+				{"writeConcern", bson.D{{"w", "majority"}}},
+			}).Err()
+			require.NoError(t, err)
+			continue
+		}
+
+		docs := make([]interface{}, 0, len(cd.Documents))
+		for _, doc := range cd.Documents {
+			docs = append(docs, unifiedRawDocument(t, doc))
+		}
+		_, err := coll.InsertMany(unifiedCtx, docs)
+		require.NoError(t, err)
+	}
+}
+
+func createUnifiedEntity(t *testing.T, entities *unifiedEntityMap, def map[string]unifiedEntity) {
+	for kind, e := range def {
+		switch kind {
+		case "client":
+			entities.clients[e.ID] = createUnifiedClient(t, e)
+		case "database":
+			client, ok := entities.clients[e.Client]
+			require.True(t, ok, "no client entity %q", e.Client)
+			entities.databases[e.ID] = client.Database(e.DatabaseName)
+		case "collection":
+			db, ok := entities.databases[e.Database]
+			require.True(t, ok, "no database entity %q", e.Database)
+			entities.collections[e.ID] = db.Collection(e.CollectionName)
+		case "session":
+			client, ok := entities.clients[e.Client]
+			require.True(t, ok, "no client entity %q", e.Client)
+			sess, err := client.StartSession(unifiedSessionOptions(e.SessionOptions))
+			require.NoError(t, err)
+			entities.sessions[e.ID] = sess
+			entities.sessionLsids[e.ID] = sess.ID()
+		case "bucket":
+			db, ok := entities.databases[e.Database]
+			require.True(t, ok, "no database entity %q", e.Database)
+			bucket, err := gridfs.NewBucket(db, unifiedBucketOptions(e.BucketOptions))
+			require.NoError(t, err)
+			entities.buckets[e.ID] = bucket
+		default:
+			t.Fatalf("unrecognized entity type %q", kind)
+		}
+	}
+}
+
+func createUnifiedClient(t *testing.T, e unifiedEntity) *unifiedClient {
+	uri := unifiedURI(t)
+	if len(e.URIOptions) > 0 {
+		opts := make([]string, 0, len(e.URIOptions))
+		for k, v := range e.URIOptions {
+			opts = append(opts, fmt.Sprintf("%s=%v", k, v))
+		}
+		uri = testutil.AddOptionsToURI(uri, strings.Join(opts, "&"))
+	}
+
+	uc := &unifiedClient{
+		observe: make(map[string]bool),
+		ignore:  make(map[string]bool),
+	}
+	for _, kind := range e.ObserveEvents {
+		uc.observe[kind] = true
+	}
+	for _, name := range e.IgnoreCommands {
+		uc.ignore[name] = true
+	}
+
+	opts := options.Client().ApplyURI(uri).SetMonitor(uc.monitor())
+	if e.UseMultipleMongoses != nil && !*e.UseMultipleMongoses && len(opts.Hosts) > 1 {
+		opts.SetHosts(opts.Hosts[:1])
+	}
+
+	client, err := mongo.Connect(unifiedCtx, opts)
+	require.NoError(t, err)
+	uc.Client = client
+	return uc
+}
+
+func unifiedSessionOptions(opts map[string]interface{}) *options.SessionOptions {
+	sessOpts := options.Session()
+	for name, opt := range opts {
+		switch name {
+		case "causalConsistency":
+			sessOpts.SetCausalConsistency(opt.(bool))
+		case "defaultTransactionOptions":
+			transOpts := opt.(map[string]interface{})
+			if wc, ok := transOpts["writeConcern"].(map[string]interface{}); ok {
+				sessOpts.SetDefaultWriteConcern(unifiedWriteConcern(wc))
+			}
+			if rp, ok := transOpts["readPreference"].(map[string]interface{}); ok {
+				sessOpts.SetDefaultReadPreference(unifiedReadPref(rp))
+			}
+		}
+	}
+	return sessOpts
+}
+
+func unifiedBucketOptions(opts map[string]interface{}) *options.BucketOptions {
+	bucketOpts := options.GridFSBucket()
+	for name, opt := range opts {
+		switch name {
+		case "bucketName":
+			bucketOpts.SetName(opt.(string))
+		case "chunkSizeBytes":
+			bucketOpts.SetChunkSizeBytes(int32(opt.(float64)))
+		}
+	}
+	return bucketOpts
+}
+
+func unifiedWriteConcern(wc map[string]interface{}) *writeconcern.WriteConcern {
+	var wcOpts []writeconcern.Option
+	switch w := wc["w"].(type) {
+	case string:
+		if w == "majority" {
+			wcOpts = append(wcOpts, writeconcern.WMajority())
+			break
+		}
+		wcOpts = append(wcOpts, writeconcern.WTagSet(w))
+	case float64:
+		wcOpts = append(wcOpts, writeconcern.W(int(w)))
+	}
+	if j, ok := wc["journal"].(bool); ok {
+		wcOpts = append(wcOpts, writeconcern.J(j))
+	}
+	return writeconcern.New(wcOpts...)
+}
+
+func unifiedReadPref(rp map[string]interface{}) *readpref.ReadPref {
+	mode, err := readpref.ModeFromString(rp["mode"].(string))
+	if err != nil {
+		return readpref.Primary()
+	}
+	pref, err := readpref.New(mode)
+	if err != nil {
+		return readpref.Primary()
+	}
+	return pref
+}
+
+// unifiedRawDocument converts a relaxed extended JSON document from a test file to BSON.
+func unifiedRawDocument(t *testing.T, data json.RawMessage) bson.Raw {
+	var doc bson.Raw
+	require.NoError(t, bson.UnmarshalExtJSON(data, false, &doc))
+	return doc
+}
+
+// unifiedRawValue converts a relaxed extended JSON value from a test file to BSON.
+func unifiedRawValue(t *testing.T, data json.RawMessage) bson.RawValue {
+	doc := unifiedRawDocument(t, json.RawMessage(`{"v": `+string(data)+`}`))
+	return doc.Lookup("v")
+}
+
+// unifiedResultValue converts an operation result to BSON so it can be matched against expectResult.
+func unifiedResultValue(res interface{}) (bson.RawValue, error) {
+	doc, err := bson.Marshal(bson.D{{"v", res}})
+	if err != nil {
+		return bson.RawValue{}, err
+	}
+	return bson.Raw(doc).Lookup("v"), nil
+}
+
+func executeUnifiedTestRunnerOperation(t *testing.T, entities *unifiedEntityMap, op *unifiedOperation) *unifiedFailPoint {
+	args := unifiedRawDocument(t, op.Arguments)
+	switch op.Name {
+	case "failPoint":
+		client, ok := entities.clients[args.Lookup("client").StringValue()]
+		require.True(t, ok, "no client entity %q", args.Lookup("client").StringValue())
+		fp := args.Lookup("failPoint").Document()
+		require.NoError(t, client.Database("admin").RunCommand(unifiedCtx, fp).Err())
+		return &unifiedFailPoint{client: client.Client, name: fp.Lookup("configureFailPoint").StringValue()}
+	case "assertCollectionExists", "assertCollectionNotExists":
+		client, err := mongo.Connect(unifiedCtx, options.Client().ApplyURI(unifiedURI(t)))
+		require.NoError(t, err)
+		defer func() { _ = client.Disconnect(unifiedCtx) }()
+
+		collName := args.Lookup("collectionName").StringValue()
+		names, err := client.Database(args.Lookup("databaseName").StringValue()).ListCollectionNames(
+			unifiedCtx, bson.D{{"name", collName}})
+		require.NoError(t, err)
+		require.Equal(t, op.Name == "assertCollectionExists", containsString(names, collName),
+			"unexpected existence of collection %q", collName)
+	default:
+		t.Skipf("unsupported testRunner operation %q", op.Name)
+	}
+	return nil
+}
+
+// executeUnifiedOperation dispatches op to the entity named by its object field. If the arguments
+// name a session, the operation runs inside that session.
+func executeUnifiedOperation(t *testing.T, entities *unifiedEntityMap, op *unifiedOperation) (bson.RawValue, error) {
+	var args bson.Raw
+	if op.Arguments != nil {
+		args = unifiedRawDocument(t, op.Arguments)
+	}
+
+	if sess, ok := entities.sessions[op.Object]; ok {
+		return bson.RawValue{}, executeUnifiedSessionOperation(t, sess, op.Name, args)
+	}
+
+	var res interface{}
+	run := func(ctx context.Context) error {
+		var err error
+		switch {
+		case entities.collections[op.Object] != nil:
+			res, err = executeUnifiedCollectionOperation(t, ctx, entities.collections[op.Object], op.Name, args)
+		case entities.databases[op.Object] != nil:
+			res, err = executeUnifiedDatabaseOperation(t, ctx, entities.databases[op.Object], op.Name, args)
+		case entities.clients[op.Object] != nil:
+			res, err = executeUnifiedClientOperation(t, ctx, entities.clients[op.Object], op.Name, args)
+		case entities.buckets[op.Object] != nil:
+			res, err = executeUnifiedBucketOperation(t, entities.buckets[op.Object], op.Name, args)
+		default:
+			t.Fatalf("no entity %q", op.Object)
+		}
+		return err
+	}
+
+	var err error
+	if sessVal, lookupErr := args.LookupErr("session"); lookupErr == nil {
+		sess, ok := entities.sessions[sessVal.StringValue()]
+		require.True(t, ok, "no session entity %q", sessVal.StringValue())
+		err = mongo.WithSession(unifiedCtx, sess, func(sc mongo.SessionContext) error { return run(sc) })
+	} else {
+		err = run(unifiedCtx)
+	}
+	if err != nil || res == nil {
+		return bson.RawValue{}, err
+	}
+
+	val, err := unifiedResultValue(res)
+	require.NoError(t, err)
+	return val, nil
+}
+
+func executeUnifiedSessionOperation(t *testing.T, sess mongo.Session, name string, args bson.Raw) error {
+	switch name {
+	case "startTransaction":
+		return sess.StartTransaction()
+	case "commitTransaction":
+		return sess.CommitTransaction(unifiedCtx)
+	case "abortTransaction":
+		return sess.AbortTransaction(unifiedCtx)
+	case "endSession":
+		sess.EndSession(unifiedCtx)
+		return nil
+	}
+	t.Skipf("unsupported session operation %q", name)
+	return nil
+}
+
+func executeUnifiedClientOperation(t *testing.T, ctx context.Context, client *unifiedClient, name string, args bson.Raw) (interface{}, error) {
+	switch name {
+	case "listDatabaseNames":
+		var filter interface{} = bson.D{}
+		if val, err := args.LookupErr("filter"); err == nil {
+			filter = val.Document()
+		}
+		return client.ListDatabaseNames(ctx, filter)
+	}
+	t.Skipf("unsupported client operation %q", name)
+	return nil, nil
+}
+
+func executeUnifiedDatabaseOperation(t *testing.T, ctx context.Context, db *mongo.Database, name string, args bson.Raw) (interface{}, error) {
+	switch name {
+	case "runCommand":
+		return db.RunCommand(ctx, args.Lookup("command").Document()).DecodeBytes()
+	case "createCollection":
+		return nil, db.CreateCollection(ctx, args.Lookup("collection").StringValue())
+	case "dropCollection":
+		return nil, db.Collection(args.Lookup("collection").StringValue()).Drop(ctx)
+	case "listCollectionNames":
+		var filter interface{} = bson.D{}
+		if val, err := args.LookupErr("filter"); err == nil {
+			filter = val.Document()
+		}
+		return db.ListCollectionNames(ctx, filter)
+	case "aggregate":
+		cur, err := db.Aggregate(ctx, args.Lookup("pipeline").Array())
+		if err != nil {
+			return nil, err
+		}
+		return unifiedCursorResult(ctx, cur)
+	}
+	t.Skipf("unsupported database operation %q", name)
+	return nil, nil
+}
+
+func executeUnifiedCollectionOperation(t *testing.T, ctx context.Context, coll *mongo.Collection, name string, args bson.Raw) (interface{}, error) {
+	var filter interface{} = bson.D{}
+	if val, err := args.LookupErr("filter"); err == nil {
+		filter = val.Document()
+	}
+
+	switch name {
+	case "insertOne":
+		res, err := coll.InsertOne(ctx, args.Lookup("document").Document())
+		if err != nil {
+			return nil, err
+		}
+		return bson.D{{"insertedId", res.InsertedID}}, nil
+	case "insertMany":
+		vals, err := args.Lookup("documents").Array().Values()
+		require.NoError(t, err)
+		docs := make([]interface{}, 0, len(vals))
+		for _, v := range vals {
+			docs = append(docs, v.Document())
+		}
+		res, err := coll.InsertMany(ctx, docs)
+		if err != nil {
+			return nil, err
+		}
+		ids := make(bson.D, 0, len(res.InsertedIDs))
+		for i, id := range res.InsertedIDs {
+			ids = append(ids, bson.E{Key: fmt.Sprint(i), Value: id})
+		}
+		return bson.D{{"insertedIds", ids}}, nil
+	case "find":
+		findOpts := options.Find()
+		if val, err := args.LookupErr("sort"); err == nil {
+			findOpts.SetSort(val.Document())
+		}
+		if val, err := args.LookupErr("projection"); err == nil {
+			findOpts.SetProjection(val.Document())
+		}
+		if val, err := args.LookupErr("limit"); err == nil {
+			findOpts.SetLimit(val.AsInt64())
+		}
+		if val, err := args.LookupErr("skip"); err == nil {
+			findOpts.SetSkip(val.AsInt64())
+		}
+		if val, err := args.LookupErr("batchSize"); err == nil {
+			findOpts.SetBatchSize(val.AsInt32())
+		}
+		cur, err := coll.Find(ctx, filter, findOpts)
+		if err != nil {
+			return nil, err
+		}
+		return unifiedCursorResult(ctx, cur)
+	case "aggregate":
+		cur, err := coll.Aggregate(ctx, args.Lookup("pipeline").Array())
+		if err != nil {
+			return nil, err
+		}
+		return unifiedCursorResult(ctx, cur)
+	case "countDocuments":
+		return coll.CountDocuments(ctx, filter)
+	case "distinct":
+		return coll.Distinct(ctx, args.Lookup("fieldName").StringValue(), filter)
+	case "deleteOne", "deleteMany":
+		var res *mongo.DeleteResult
+		var err error
+		if name == "deleteOne" {
+			res, err = coll.DeleteOne(ctx, filter)
+		} else {
+			res, err = coll.DeleteMany(ctx, filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return bson.D{{"deletedCount", res.DeletedCount}}, nil
+	case "updateOne", "updateMany", "replaceOne":
+		var res *mongo.UpdateResult
+		var err error
+		switch name {
+		case "updateOne":
+			res, err = coll.UpdateOne(ctx, filter, args.Lookup("update").Document(), unifiedUpdateOptions(args))
+		case "updateMany":
+			res, err = coll.UpdateMany(ctx, filter, args.Lookup("update").Document(), unifiedUpdateOptions(args))
+		case "replaceOne":
+			replaceOpts := options.Replace()
+			if val, err := args.LookupErr("upsert"); err == nil {
+				replaceOpts.SetUpsert(val.Boolean())
+			}
+			res, err = coll.ReplaceOne(ctx, filter, args.Lookup("replacement").Document(), replaceOpts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		doc := bson.D{
+			{"matchedCount", res.MatchedCount},
+			{"modifiedCount", res.ModifiedCount},
+			{"upsertedCount", res.UpsertedCount},
+		}
+		if res.UpsertedID != nil {
+			doc = append(doc, bson.E{Key: "upsertedId", Value: res.UpsertedID})
+		}
+		return doc, nil
+	case "findOneAndDelete":
+		return coll.FindOneAndDelete(ctx, filter).DecodeBytes()
+	}
+	t.Skipf("unsupported collection operation %q", name)
+	return nil, nil
+}
+
+func unifiedUpdateOptions(args bson.Raw) *options.UpdateOptions {
+	updateOpts := options.Update()
+	if val, err := args.LookupErr("upsert"); err == nil {
+		updateOpts.SetUpsert(val.Boolean())
+	}
+	return updateOpts
+}
+
+func executeUnifiedBucketOperation(t *testing.T, bucket *gridfs.Bucket, name string, args bson.Raw) (interface{}, error) {
+	switch name {
+	case "upload":
+		source, err := hex.DecodeString(args.Lookup("source", "$$hexBytes").StringValue())
+		require.NoError(t, err)
+		return bucket.UploadFromStream(args.Lookup("filename").StringValue(), bytes.NewReader(source))
+	case "download":
+		var buf bytes.Buffer
+		if _, err := bucket.DownloadToStream(args.Lookup("id"), &buf); err != nil {
+			return nil, err
+		}
+		return hex.EncodeToString(buf.Bytes()), nil
+	case "delete":
+		return nil, bucket.Delete(args.Lookup("id"))
+	case "drop":
+		return nil, bucket.Drop()
+	}
+	t.Skipf("unsupported bucket operation %q", name)
+	return nil, nil
+}
+
+func unifiedCursorResult(ctx context.Context, cur *mongo.Cursor) ([]bson.Raw, error) {
+	docs := []bson.Raw{}
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func verifyUnifiedOperationOutcome(t *testing.T, entities *unifiedEntityMap, op *unifiedOperation, res bson.RawValue, err error) {
+	if op.ExpectError == nil {
+		require.NoError(t, err, "unexpected error from %s", op.Name)
+		if op.ExpectResult != nil {
+			expected := unifiedRawValue(t, op.ExpectResult)
+			require.NoError(t, matchValues(entities, "", expected, res, true), "result mismatch for %s", op.Name)
+		}
+		return
+	}
+
+	expected := op.ExpectError
+	require.Error(t, err, "expected %s to fail", op.Name)
+
+	var cerr *mongo.CommandError
+	var code int32
+	var hasCode bool
+	switch e := err.(type) {
+	case mongo.CommandError:
+		cerr = &e
+		code, hasCode = e.Code, true
+	case mongo.WriteException:
+		if len(e.WriteErrors) > 0 {
+			code, hasCode = int32(e.WriteErrors[0].Code), true
+		} else if e.WriteConcernError != nil {
+			code, hasCode = int32(e.WriteConcernError.Code), true
+		}
+	case mongo.BulkWriteException:
+		if len(e.WriteErrors) > 0 {
+			code, hasCode = int32(e.WriteErrors[0].Code), true
+		} else if e.WriteConcernError != nil {
+			code, hasCode = int32(e.WriteConcernError.Code), true
+		}
+	}
+
+	if expected.IsClientError != nil {
+		require.Equal(t, *expected.IsClientError, !hasCode, "unexpected error kind: %v", err)
+	}
+	if expected.ErrorContains != "" {
+		require.Contains(t, strings.ToLower(err.Error()), strings.ToLower(expected.ErrorContains))
+	}
+	if expected.ErrorCode != nil {
+		require.True(t, hasCode, "expected server error with code %d, got %v", *expected.ErrorCode, err)
+		require.Equal(t, *expected.ErrorCode, code)
+	}
+	if expected.ErrorCodeName != "" {
+		require.NotNil(t, cerr, "expected command error %s, got %v", expected.ErrorCodeName, err)
+		require.Equal(t, expected.ErrorCodeName, cerr.Name)
+	}
+	for _, label := range expected.ErrorLabelsContain {
+		require.NotNil(t, cerr, "expected error label %s, got %v", label, err)
+		require.True(t, cerr.HasErrorLabel(label), "error missing label %s", label)
+	}
+	for _, label := range expected.ErrorLabelsOmit {
+		if cerr != nil {
+			require.False(t, cerr.HasErrorLabel(label), "error has label %s", label)
+		}
+	}
+	if expected.ExpectResult != nil {
+		require.NoError(t, matchValues(entities, "", unifiedRawValue(t, expected.ExpectResult), res, true))
+	}
+}
+
+func verifyUnifiedEvents(t *testing.T, entities *unifiedEntityMap, expected unifiedExpectedEvents) {
+	client, ok := entities.clients[expected.Client]
+	require.True(t, ok, "no client entity %q", expected.Client)
+
+	client.mu.Lock()
+	actual := client.events
+	client.mu.Unlock()
+
+	require.Equal(t, len(expected.Events), len(actual), "event count mismatch for %s", expected.Client)
+	for i, expectedEvt := range expected.Events {
+		for kind, raw := range expectedEvt {
+			evt := actual[i]
+			require.Equal(t, kind, evt.kind, "event %d", i)
+
+			doc := unifiedRawDocument(t, raw)
+			if val, err := doc.LookupErr("commandName"); err == nil {
+				require.Equal(t, val.StringValue(), evt.commandName, "event %d", i)
+			}
+			if val, err := doc.LookupErr("databaseName"); err == nil {
+				require.Equal(t, val.StringValue(), evt.database, "event %d", i)
+			}
+			if val, err := doc.LookupErr("command"); err == nil {
+				require.NoError(t, matchDocuments(entities, "command", val.Document(), evt.command, true), "event %d", i)
+			}
+			if val, err := doc.LookupErr("reply"); err == nil {
+				require.NoError(t, matchDocuments(entities, "reply", val.Document(), evt.reply, true), "event %d", i)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// DiffUpdate returns an update document that changes the document before into the document after,
+// so that a modified document can be saved with UpdateOne instead of ReplaceOne. The documents can
+// be of any type that can be marshaled to a BSON document, such as a struct, a bson.D or a
+// bson.Raw.
+//
+// The update contains a $set of the fields that were added or whose values changed and an $unset of
+// the fields that were removed. Embedded documents are compared field by field, so only the
+// changed fields of an embedded document are set, while arrays and other values are set as a
+// whole. An embedded document is set as a whole if it has a field whose name contains a "." or
+// starts with a "$", because such fields cannot be referred to by a dotted path. An empty update
+// is returned if the documents are equal.
+func DiffUpdate(before, after interface{}) (bson.D, error) {
+	b, err := marshalDiffDocument(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := marshalDiffDocument(after)
+	if err != nil {
+		return nil, err
+	}
+	if !diffable(b) || !diffable(a) {
+		return nil, errors.New("cannot diff documents with field names that contain a '.' or start with a '$'")
+	}
+
+	var set, unset bson.D
+	if err := diffDocuments("", b, a, &set, &unset); err != nil {
+		return nil, err
+	}
+
+	update := bson.D{}
+	if len(set) > 0 {
+		update = append(update, bson.E{Key: "$set", Value: set})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
+	}
+	return update, nil
+}
+
+func marshalDiffDocument(val interface{}) (bson.Raw, error) {
+	if val == nil {
+		return nil, ErrNilDocument
+	}
+	if raw, ok := val.(bson.Raw); ok {
+		return raw, raw.Validate()
+	}
+	b, err := bson.Marshal(val)
+	if err != nil {
+		return nil, MarshalError{Value: val, Err: err}
+	}
+	return bson.Raw(b), nil
+}
+
+// diffDocuments appends to set and unset the changes that turn the document before into the
+// document after. The changed fields are prefixed with the dotted path of the documents.
+func diffDocuments(prefix string, before, after bson.Raw, set, unset *bson.D) error {
+	beforeElems, err := before.Elements()
+	if err != nil {
+		return err
+	}
+	afterElems, err := after.Elements()
+	if err != nil {
+		return err
+	}
+
+	for _, elem := range afterElems {
+		key := elem.Key()
+		val := elem.Value()
+		old, err := before.LookupErr(key)
+		if err != nil {
+			*set = append(*set, bson.E{Key: prefix + key, Value: val})
+			continue
+		}
+		if old.Equal(val) {
+			continue
+		}
+		if old.Type == bsontype.EmbeddedDocument && val.Type == bsontype.EmbeddedDocument &&
+			diffable(old.Document()) && diffable(val.Document()) {
+			if err := diffDocuments(prefix+key+".", old.Document(), val.Document(), set, unset); err != nil {
+				return err
+			}
+			continue
+		}
+		*set = append(*set, bson.E{Key: prefix + key, Value: val})
+	}
+
+	for _, elem := range beforeElems {
+		key := elem.Key()
+		if _, err := after.LookupErr(key); err != nil {
+			*unset = append(*unset, bson.E{Key: prefix + key, Value: ""})
+		}
+	}
+	return nil
+}
+
+// diffable returns true if the fields of doc can be referred to by a dotted path.
+func diffable(doc bson.Raw) bool {
+	elems, err := doc.Elements()
+	if err != nil {
+		return false
+	}
+	for _, elem := range elems {
+		key := elem.Key()
+		if strings.Contains(key, ".") || strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDiffUpdate(t *testing.T) {
+	type address struct {
+		City string `bson:"city"`
+		Zip  string `bson:"zip"`
+	}
+	type person struct {
+		Name    string   `bson:"name"`
+		Age     int32    `bson:"age"`
+		Tags    []string `bson:"tags"`
+		Address address  `bson:"address"`
+		Nick    string   `bson:"nick,omitempty"`
+	}
+
+	t.Run("structs", func(t *testing.T) {
+		before := person{Name: "ada", Age: 36, Tags: []string{"a"}, Address: address{"London", "N1"}, Nick: "al"}
+		after := person{Name: "ada", Age: 37, Tags: []string{"a", "b"}, Address: address{"London", "N2"}}
+
+		update, err := DiffUpdate(before, after)
+		require.NoError(t, err)
+		requireUpdate(t, bson.D{
+			{"$set", bson.D{{"age", int32(37)}, {"tags", bson.A{"a", "b"}}, {"address.zip", "N2"}}},
+			{"$unset", bson.D{{"nick", ""}}},
+		}, update)
+	})
+	t.Run("raw documents", func(t *testing.T) {
+		before, err := bson.Marshal(bson.D{{"a", int32(1)}, {"b", bson.D{{"c", int32(2)}}}})
+		require.NoError(t, err)
+		after, err := bson.Marshal(bson.D{{"b", bson.D{{"c", int32(2)}, {"d", int32(3)}}}, {"e", "x"}})
+		require.NoError(t, err)
+
+		update, err := DiffUpdate(bson.Raw(before), bson.Raw(after))
+		require.NoError(t, err)
+		requireUpdate(t, bson.D{
+			{"$set", bson.D{{"b.d", int32(3)}, {"e", "x"}}},
+			{"$unset", bson.D{{"a", ""}}},
+		}, update)
+	})
+	t.Run("type change sets the whole value", func(t *testing.T) {
+		update, err := DiffUpdate(bson.D{{"a", bson.D{{"b", int32(1)}}}}, bson.D{{"a", int32(1)}})
+		require.NoError(t, err)
+		requireUpdate(t, bson.D{{"$set", bson.D{{"a", int32(1)}}}}, update)
+	})
+	t.Run("undiffable embedded document is set as a whole", func(t *testing.T) {
+		update, err := DiffUpdate(
+			bson.D{{"a", bson.D{{"x.y", int32(1)}}}},
+			bson.D{{"a", bson.D{{"x.y", int32(2)}}}},
+		)
+		require.NoError(t, err)
+		requireUpdate(t, bson.D{{"$set", bson.D{{"a", bson.D{{"x.y", int32(2)}}}}}}, update)
+	})
+	t.Run("equal documents", func(t *testing.T) {
+		p := person{Name: "ada", Tags: []string{"a"}}
+		update, err := DiffUpdate(p, p)
+		require.NoError(t, err)
+		require.Empty(t, update)
+	})
+	t.Run("undiffable top-level fields", func(t *testing.T) {
+		_, err := DiffUpdate(bson.D{{"$a", int32(1)}}, bson.D{})
+		require.Error(t, err)
+	})
+	t.Run("nil document", func(t *testing.T) {
+		_, err := DiffUpdate(nil, bson.D{})
+		require.Equal(t, ErrNilDocument, err)
+	})
+}
+
+func requireUpdate(t *testing.T, expected, actual bson.D) {
+	t.Helper()
+
+	expectedBytes, err := bson.Marshal(expected)
+	require.NoError(t, err)
+	actualBytes, err := bson.Marshal(actual)
+	require.NoError(t, err)
+	require.Equal(t, bson.Raw(expectedBytes).String(), bson.Raw(actualBytes).String())
+}
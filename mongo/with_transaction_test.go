@@ -0,0 +1,312 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
+	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+func TestWithTransaction(t *testing.T) {
+	dbName := "admin"
+	dbAdmin := createTestDatabase(t, &dbName)
+	version, err := getServerVersion(dbAdmin)
+	require.NoError(t, err)
+	if shouldSkipTransactionsTest(t, version, []string{"replicaset"}) {
+		t.Skip()
+	}
+
+	client := createTestClient(t)
+	coll := client.Database("WithTransactionTestDB").Collection("WithTransactionTestColl")
+	_, err = coll.InsertOne(ctx, bson.D{{"x", 0}})
+	require.NoError(t, err)
+	defer func() { _ = coll.Drop(ctx) }()
+
+	t.Run("commits and returns callback result", func(t *testing.T) {
+		sess, err := client.StartSession()
+		require.NoError(t, err)
+		defer sess.EndSession(ctx)
+
+		res, err := sess.WithTransaction(ctx, func(sessCtx SessionContext) (interface{}, error) {
+			return coll.InsertOne(sessCtx, bson.D{{"x", 1}})
+		})
+		require.NoError(t, err)
+		require.NotNil(t, res.(*InsertOneResult).InsertedID)
+
+		count, err := coll.CountDocuments(ctx, bson.D{{"x", 1}})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("returns callback error and aborts", func(t *testing.T) {
+		sess, err := client.StartSession()
+		require.NoError(t, err)
+		defer sess.EndSession(ctx)
+
+		callbackErr := errors.New("callback error")
+		_, err = sess.WithTransaction(ctx, func(sessCtx SessionContext) (interface{}, error) {
+			if _, err := coll.InsertOne(sessCtx, bson.D{{"x", 2}}); err != nil {
+				return nil, err
+			}
+			return nil, callbackErr
+		})
+		require.Equal(t, callbackErr, err)
+
+		count, err := coll.CountDocuments(ctx, bson.D{{"x", 2}})
+		require.NoError(t, err)
+		require.Equal(t, int64(0), count)
+	})
+
+	t.Run("stops retrying transient errors after timeout", func(t *testing.T) {
+		defaultTimeout := withTransactionTimeout
+		withTransactionTimeout = 0
+		defer func() { withTransactionTimeout = defaultTimeout }()
+
+		sess, err := client.StartSession()
+		require.NoError(t, err)
+		defer sess.EndSession(ctx)
+
+		var attempts int
+		transientErr := CommandError{Labels: []string{command.TransientTransactionError}}
+		_, err = sess.WithTransaction(context.Background(), func(SessionContext) (interface{}, error) {
+			attempts++
+			time.Sleep(time.Millisecond)
+			return nil, transientErr
+		})
+		require.Equal(t, transientErr, err)
+		require.Equal(t, 1, attempts)
+	})
+}
+
+// testTransaction runs withTransaction on a session that is not connected to a server. commitErrs
+// are returned by successive commits and callbackErrs by successive calls of the callback; once
+// exhausted, both succeed.
+type testTransaction struct {
+	commitErrs   []error
+	callbackErrs []error
+
+	commits, aborts, callbacks int
+}
+
+func (tt *testTransaction) run(t *testing.T) error {
+	t.Helper()
+
+	id, err := uuid.New()
+	require.NoError(t, err)
+	client, err := session.NewClientSession(session.NewPool(nil), id, session.Explicit)
+	require.NoError(t, err)
+	sess := &sessionImpl{Client: client}
+
+	commit := func(context.Context) error {
+		tt.commits++
+		if len(tt.commitErrs) > 0 {
+			err := tt.commitErrs[0]
+			tt.commitErrs = tt.commitErrs[1:]
+			return err
+		}
+		return client.CommitTransaction()
+	}
+	abort := func(context.Context) error {
+		tt.aborts++
+		return client.AbortTransaction()
+	}
+
+	_, err = sess.withTransaction(context.Background(), func(SessionContext) (interface{}, error) {
+		tt.callbacks++
+		if len(tt.callbackErrs) > 0 {
+			err := tt.callbackErrs[0]
+			tt.callbackErrs = tt.callbackErrs[1:]
+			return nil, err
+		}
+		return nil, nil
+	}, commit, abort)
+	return err
+}
+
+func TestWithTransactionRetries(t *testing.T) {
+	transientErr := CommandError{Labels: []string{command.TransientTransactionError}}
+	unknownErr := CommandError{Code: 91, Labels: []string{command.UnknownTransactionCommitResult}}
+
+	t.Run("retries callback on TransientTransactionError", func(t *testing.T) {
+		tt := &testTransaction{callbackErrs: []error{transientErr}}
+		require.NoError(t, tt.run(t))
+		require.Equal(t, 2, tt.callbacks)
+		require.Equal(t, 1, tt.aborts)
+		require.Equal(t, 1, tt.commits)
+	})
+
+	t.Run("retries commit on UnknownTransactionCommitResult", func(t *testing.T) {
+		tt := &testTransaction{commitErrs: []error{unknownErr, unknownErr}}
+		require.NoError(t, tt.run(t))
+		require.Equal(t, 1, tt.callbacks)
+		require.Equal(t, 3, tt.commits)
+	})
+
+	t.Run("does not retry commit on MaxTimeMSExpired", func(t *testing.T) {
+		maxTimeErr := CommandError{Code: errorCodeMaxTimeMSExpired, Labels: []string{command.UnknownTransactionCommitResult}}
+		tt := &testTransaction{commitErrs: []error{maxTimeErr}}
+		require.Equal(t, maxTimeErr, tt.run(t))
+		require.Equal(t, 1, tt.callbacks)
+		require.Equal(t, 1, tt.commits)
+	})
+
+	t.Run("retries transaction on transient commit error", func(t *testing.T) {
+		tt := &testTransaction{commitErrs: []error{transientErr}}
+		require.NoError(t, tt.run(t))
+		require.Equal(t, 2, tt.callbacks)
+		require.Equal(t, 2, tt.commits)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		otherErr := CommandError{Code: 2}
+		tt := &testTransaction{commitErrs: []error{otherErr}}
+		require.Equal(t, otherErr, tt.run(t))
+		require.Equal(t, 1, tt.callbacks)
+		require.Equal(t, 1, tt.commits)
+	})
+}
+
+// transactionServer is a fake replica set primary. The first commitTransaction it receives fails
+// with a TransientTransactionError, and the other commands succeed.
+type transactionServer struct {
+	commits int32
+}
+
+func (ts *transactionServer) reply(cmd bson.Raw) bson.Raw {
+	var doc bson.D
+	elems, _ := cmd.Elements()
+	switch name := elems[0].Key(); {
+	case strings.EqualFold(name, "isMaster") || name == "hello":
+		doc = bson.D{
+			{"ok", 1},
+			{"ismaster", true},
+			{"setName", "rs"},
+			{"hosts", bson.A{"localhost:27017"}},
+			{"me", "localhost:27017"},
+			{"maxWireVersion", 8},
+			{"maxBsonObjectSize", 16 * 1024 * 1024},
+			{"maxMessageSizeBytes", 48000000},
+			{"maxWriteBatchSize", 100000},
+			{"logicalSessionTimeoutMinutes", 30},
+		}
+	case name == "insert":
+		doc = bson.D{{"ok", 1}, {"n", 1}}
+	case name == "commitTransaction" && atomic.AddInt32(&ts.commits, 1) == 1:
+		doc = bson.D{
+			{"ok", 0},
+			{"code", 112},
+			{"codeName", "WriteConflict"},
+			{"errmsg", "write conflict"},
+			{"errorLabels", bson.A{command.TransientTransactionError}},
+		}
+	default:
+		doc = bson.D{{"ok", 1}}
+	}
+	b, _ := bson.Marshal(doc)
+	return b
+}
+
+func (ts *transactionServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var sizeBuf [4]byte
+		if _, err := io.ReadFull(conn, sizeBuf[:]); err != nil {
+			return
+		}
+		b := make([]byte, binary.LittleEndian.Uint32(sizeBuf[:]))
+		copy(b, sizeBuf[:])
+		if _, err := io.ReadFull(conn, b[4:]); err != nil {
+			return
+		}
+		header, err := wiremessage.ReadHeader(b, 0)
+		if err != nil {
+			return
+		}
+
+		var wm []byte
+		replyHeader := wiremessage.Header{RequestID: wiremessage.NextRequestID(), ResponseTo: header.RequestID}
+		switch header.OpCode {
+		case wiremessage.OpQuery:
+			var query wiremessage.Query
+			if err = query.UnmarshalWireMessage(b); err != nil {
+				return
+			}
+			wm, err = wiremessage.Reply{
+				MsgHeader:      replyHeader,
+				NumberReturned: 1,
+				Documents:      []bson.Raw{ts.reply(query.Query)},
+			}.MarshalWireMessage()
+		case wiremessage.OpMsg:
+			var msg wiremessage.Msg
+			if err = msg.UnmarshalWireMessage(b); err != nil {
+				return
+			}
+			var body bson.Raw
+			for _, section := range msg.Sections {
+				if sb, ok := section.(wiremessage.SectionBody); ok {
+					body = sb.Document
+				}
+			}
+			wm, err = wiremessage.Msg{
+				MsgHeader: replyHeader,
+				Sections:  []wiremessage.Section{wiremessage.SectionBody{Document: ts.reply(body)}},
+			}.MarshalWireMessage()
+		default:
+			return
+		}
+		if err != nil {
+			return
+		}
+		if _, err = conn.Write(wm); err != nil {
+			return
+		}
+	}
+}
+
+func TestWithTransactionTransientCommitError(t *testing.T) {
+	ts := &transactionServer{}
+	dialer := options.DialContextFunc(func(context.Context, string, string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go ts.serve(server)
+		return client, nil
+	})
+	client, err := Connect(context.Background(), options.Client().
+		ApplyURI("mongodb://localhost:27017/?replicaSet=rs").
+		SetDialer(dialer).
+		SetServerSelectionTimeout(5*time.Second))
+	require.NoError(t, err)
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	coll := client.Database("db").Collection("coll")
+	sess, err := client.StartSession()
+	require.NoError(t, err)
+	defer sess.EndSession(context.Background())
+
+	var callbacks int
+	_, err = sess.WithTransaction(context.Background(), func(sessCtx SessionContext) (interface{}, error) {
+		callbacks++
+		return coll.InsertOne(sessCtx, bson.D{{"x", 1}})
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, callbacks)
+	require.Equal(t, int32(2), atomic.LoadInt32(&ts.commits))
+}
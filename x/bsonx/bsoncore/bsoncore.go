@@ -29,6 +29,7 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/bsontype"
@@ -39,7 +40,7 @@ import (
 func AppendType(dst []byte, t bsontype.Type) []byte { return append(dst, byte(t)) }
 
 // AppendKey will append key to dst and return the extended buffer.
-func AppendKey(dst []byte, key string) []byte { return append(dst, key+string(0x00)...) }
+func AppendKey(dst []byte, key string) []byte { return append(append(dst, key...), 0x00) }
 
 // AppendHeader will append Type t and key to dst and return the extended
 // buffer.
@@ -173,10 +174,9 @@ func ReadString(src []byte) (string, []byte, bool) {
 // AppendDocumentStart reserves a document's length and returns the index where the length begins.
 // This index can later be used to write the length of the document.
 //
-// TODO(skriptble): We really need AppendDocumentStart and AppendDocumentEnd.
-// AppendDocumentStart would handle calling ReserveLength and providing the index of the start of
-// the document. AppendDocumentEnd would handle taking that start index, adding the null byte,
-// calculating the length, and filling in the length at the start of the document.
+// AppendDocumentStart reserves the length bytes for a document and returns the index of the start of
+// those length bytes. The elements of the document can then be appended to the returned buffer and
+// the document completed by calling AppendDocumentEnd with the index.
 func AppendDocumentStart(dst []byte) (index int32, b []byte) { return ReserveLength(dst) }
 
 // AppendDocumentStartInline functions the same as AppendDocumentStart but takes a pointer to the
@@ -233,6 +233,12 @@ func BuildDocumentFromElements(dst []byte, elems ...[]byte) []byte {
 	return dst
 }
 
+// BuildDocumentElement will append a BSON embedded document element using key and a document
+// created from the given elements to dst and return the extended buffer.
+func BuildDocumentElement(dst []byte, key string, elems ...[]byte) []byte {
+	return BuildDocumentFromElements(AppendHeader(dst, bsontype.EmbeddedDocument, key), elems...)
+}
+
 // ReadDocument will read a document from src. If there are not enough bytes it
 // will return false.
 func ReadDocument(src []byte) (doc Document, rem []byte, ok bool) { return readLengthBytes(src) }
@@ -260,6 +266,27 @@ func AppendArrayElement(dst []byte, key string, arr []byte) []byte {
 	return AppendArray(AppendHeader(dst, bsontype.Array, key), arr)
 }
 
+// BuildArray will create an array with the given values and will append it to dst. The keys of
+// the array are generated from the index of each value without allocating.
+func BuildArray(dst []byte, values ...Value) []byte {
+	idx, dst := ReserveLength(dst)
+	for i, val := range values {
+		dst = AppendType(dst, val.Type)
+		dst = strconv.AppendInt(dst, int64(i), 10)
+		dst = append(dst, 0x00)
+		dst = append(dst, val.Data...)
+	}
+	dst = append(dst, 0x00)
+	dst = UpdateLength(dst, idx, int32(len(dst[idx:])))
+	return dst
+}
+
+// BuildArrayElement will append a BSON array element using key and an array created from the given
+// values to dst and return the extended buffer.
+func BuildArrayElement(dst []byte, key string, values ...Value) []byte {
+	return BuildArray(AppendHeader(dst, bsontype.Array, key), values...)
+}
+
 // ReadArray will read an array from src. If there are not enough bytes it
 // will return false.
 func ReadArray(src []byte) (arr Document, rem []byte, ok bool) { return readLengthBytes(src) }
@@ -592,6 +619,12 @@ func AppendMinKeyElement(dst []byte, key string) []byte {
 	return AppendHeader(dst, bsontype.MinKey, key)
 }
 
+// AppendValueElement will append a BSON element using key and the type and data of value to dst
+// and return the extended buffer.
+func AppendValueElement(dst []byte, key string, value Value) []byte {
+	return append(AppendHeader(dst, value.Type, key), value.Data...)
+}
+
 // EqualValue will return true if the two values are equal.
 func EqualValue(t1, t2 bsontype.Type, v1, v2 []byte) bool {
 	if t1 != t2 {
@@ -870,6 +870,62 @@ func TestBuild(t *testing.T) {
 	}
 }
 
+func TestBuildArray(t *testing.T) {
+	values := []Value{
+		{Type: bsontype.Int32, Data: AppendInt32(nil, 1)},
+		{Type: bsontype.String, Data: AppendString(nil, "a")},
+	}
+	wantArr := BuildDocumentFromElements(nil,
+		AppendInt32Element(nil, "0", 1),
+		AppendStringElement(nil, "1", "a"),
+	)
+
+	t.Run("BuildArray", func(t *testing.T) {
+		got := BuildArray(nil, values...)
+		if !bytes.Equal(got, wantArr) {
+			t.Errorf("Arrays do not match. got %v; want %v", got, wantArr)
+		}
+	})
+	t.Run("BuildArrayElement", func(t *testing.T) {
+		got := BuildArrayElement(nil, "arr", values...)
+		want := AppendArrayElement(nil, "arr", wantArr)
+		if !bytes.Equal(got, want) {
+			t.Errorf("Elements do not match. got %v; want %v", got, want)
+		}
+	})
+	t.Run("BuildDocumentElement", func(t *testing.T) {
+		got := BuildDocumentElement(nil, "doc", AppendInt32Element(nil, "x", 1))
+		want := AppendDocumentElement(nil, "doc", BuildDocumentFromElements(nil, AppendInt32Element(nil, "x", 1)))
+		if !bytes.Equal(got, want) {
+			t.Errorf("Elements do not match. got %v; want %v", got, want)
+		}
+	})
+	t.Run("AppendValueElement", func(t *testing.T) {
+		got := AppendValueElement(nil, "s", values[1])
+		want := AppendStringElement(nil, "s", "a")
+		if !bytes.Equal(got, want) {
+			t.Errorf("Elements do not match. got %v; want %v", got, want)
+		}
+	})
+	t.Run("does not allocate", func(t *testing.T) {
+		buf := make([]byte, 0, 256)
+		allocs := testing.AllocsPerRun(100, func() {
+			dst := buf[:0]
+			idx, dst := AppendDocumentStart(dst)
+			dst = AppendStringElement(dst, "insert", "coll")
+			dst = AppendInt64Element(dst, "n", 42)
+			dst = AppendKey(AppendType(dst, bsontype.Boolean), "ordered")
+			dst = AppendBoolean(dst, true)
+			dst = BuildArrayElement(dst, "values", values...)
+			dst = AppendValueElement(dst, "v", values[0])
+			_, _ = AppendDocumentEnd(dst, idx)
+		})
+		if allocs != 0 {
+			t.Errorf("Expected no allocations, got %v", allocs)
+		}
+	})
+}
+
 func compareDecimal128(d1, d2 primitive.Decimal128) bool {
 	d1H, d1L := d1.GetBytes()
 	d2H, d2L := d2.GetBytes()
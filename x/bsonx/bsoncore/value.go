@@ -57,41 +57,108 @@ func (v Value) IsNumber() bool {
 	}
 }
 
-// AsInt32 returns a BSON number as an int32. If the BSON type is not a numeric one, this method
-// will panic.
+// AsInt32 returns a BSON number as an int32. Doubles are truncated and int64s are converted as by
+// the Go conversion int32(i). If the BSON type is not double, int32, or int64, this method will
+// panic.
 //
 // TODO(skriptble): Add support for Decimal128.
-func (v Value) AsInt32() int32 { return 0 }
+func (v Value) AsInt32() int32 {
+	v.panicIfNotConvertible("bsoncore.Value.AsInt32")
+	i32, ok := v.AsInt32OK()
+	if !ok {
+		panic(NewInsufficientBytesError(v.Data, v.Data))
+	}
+	return i32
+}
 
 // AsInt32OK functions the same as AsInt32 but returns a boolean instead of panicking. False
 // indicates an error.
 //
 // TODO(skriptble): Add support for Decimal128.
-func (v Value) AsInt32OK() (int32, bool) { return 0, false }
+func (v Value) AsInt32OK() (int32, bool) {
+	switch v.Type {
+	case bsontype.Double:
+		f64, ok := v.DoubleOK()
+		return int32(f64), ok
+	case bsontype.Int32:
+		return v.Int32OK()
+	case bsontype.Int64:
+		i64, ok := v.Int64OK()
+		return int32(i64), ok
+	}
+	return 0, false
+}
 
-// AsInt64 returns a BSON number as an int64. If the BSON type is not a numeric one, this method
-// will panic.
+// AsInt64 returns a BSON number as an int64. Doubles are truncated. If the BSON type is not double,
+// int32, or int64, this method will panic.
 //
 // TODO(skriptble): Add support for Decimal128.
-func (v Value) AsInt64() int64 { return 0 }
+func (v Value) AsInt64() int64 {
+	v.panicIfNotConvertible("bsoncore.Value.AsInt64")
+	i64, ok := v.AsInt64OK()
+	if !ok {
+		panic(NewInsufficientBytesError(v.Data, v.Data))
+	}
+	return i64
+}
 
 // AsInt64OK functions the same as AsInt64 but returns a boolean instead of panicking. False
 // indicates an error.
 //
 // TODO(skriptble): Add support for Decimal128.
-func (v Value) AsInt64OK() (int64, bool) { return 0, false }
+func (v Value) AsInt64OK() (int64, bool) {
+	switch v.Type {
+	case bsontype.Double:
+		f64, ok := v.DoubleOK()
+		return int64(f64), ok
+	case bsontype.Int32:
+		i32, ok := v.Int32OK()
+		return int64(i32), ok
+	case bsontype.Int64:
+		return v.Int64OK()
+	}
+	return 0, false
+}
 
-// AsFloat64 returns a BSON number as an float64. If the BSON type is not a numeric one, this method
-// will panic.
+// AsFloat64 returns a BSON number as an float64. If the BSON type is not double, int32, or int64,
+// this method will panic.
 //
 // TODO(skriptble): Add support for Decimal128.
-func (v Value) AsFloat64() float64 { return 0 }
+func (v Value) AsFloat64() float64 {
+	v.panicIfNotConvertible("bsoncore.Value.AsFloat64")
+	f64, ok := v.AsFloat64OK()
+	if !ok {
+		panic(NewInsufficientBytesError(v.Data, v.Data))
+	}
+	return f64
+}
 
 // AsFloat64OK functions the same as AsFloat64 but returns a boolean instead of panicking. False
 // indicates an error.
 //
 // TODO(skriptble): Add support for Decimal128.
-func (v Value) AsFloat64OK() (float64, bool) { return 0, false }
+func (v Value) AsFloat64OK() (float64, bool) {
+	switch v.Type {
+	case bsontype.Double:
+		return v.DoubleOK()
+	case bsontype.Int32:
+		i32, ok := v.Int32OK()
+		return float64(i32), ok
+	case bsontype.Int64:
+		i64, ok := v.Int64OK()
+		return float64(i64), ok
+	}
+	return 0, false
+}
+
+// panicIfNotConvertible panics if v cannot be converted by the As methods.
+func (v Value) panicIfNotConvertible(method string) {
+	switch v.Type {
+	case bsontype.Double, bsontype.Int32, bsontype.Int64:
+	default:
+		panic(ElementTypeError{method, v.Type})
+	}
+}
 
 // Add will add this value to another. This is currently only implemented for strings and numbers.
 // If either value is a string, the other type is coerced into a string and added to the other.
@@ -609,6 +609,71 @@ func TestValue(t *testing.T) {
 			nil,
 			[]interface{}{primitive.NewDecimal128(12345, 67890), true},
 		},
+		{
+			"AsInt32/Not Number", Value.AsInt32, Value{Type: bsontype.String},
+			ElementTypeError{"bsoncore.Value.AsInt32", bsontype.String},
+			nil,
+		},
+		{
+			"AsInt32/Insufficient Bytes", Value.AsInt32, Value{Type: bsontype.Int64, Data: []byte{0x01, 0x02}},
+			NewInsufficientBytesError([]byte{0x01, 0x02}, []byte{0x01, 0x02}),
+			nil,
+		},
+		{
+			"AsInt32/Double", Value.AsInt32, Value{Type: bsontype.Double, Data: AppendDouble(nil, 3.9)},
+			nil,
+			[]interface{}{int32(3)},
+		},
+		{
+			"AsInt32OK/Int64", Value.AsInt32OK, Value{Type: bsontype.Int64, Data: AppendInt64(nil, 42)},
+			nil,
+			[]interface{}{int32(42), true},
+		},
+		{
+			"AsInt32OK/Decimal128", Value.AsInt32OK, Value{Type: bsontype.Decimal128, Data: AppendDecimal128(nil, primitive.NewDecimal128(0, 1))},
+			nil,
+			[]interface{}{int32(0), false},
+		},
+		{
+			"AsInt64/Not Number", Value.AsInt64, Value{Type: bsontype.Boolean},
+			ElementTypeError{"bsoncore.Value.AsInt64", bsontype.Boolean},
+			nil,
+		},
+		{
+			"AsInt64/Int32", Value.AsInt64, Value{Type: bsontype.Int32, Data: AppendInt32(nil, -7)},
+			nil,
+			[]interface{}{int64(-7)},
+		},
+		{
+			"AsInt64OK/Double", Value.AsInt64OK, Value{Type: bsontype.Double, Data: AppendDouble(nil, -2.5)},
+			nil,
+			[]interface{}{int64(-2), true},
+		},
+		{
+			"AsInt64OK/Insufficient Bytes", Value.AsInt64OK, Value{Type: bsontype.Int32, Data: []byte{0x01}},
+			nil,
+			[]interface{}{int64(0), false},
+		},
+		{
+			"AsFloat64/Not Number", Value.AsFloat64, Value{Type: bsontype.Null},
+			ElementTypeError{"bsoncore.Value.AsFloat64", bsontype.Null},
+			nil,
+		},
+		{
+			"AsFloat64/Int64", Value.AsFloat64, Value{Type: bsontype.Int64, Data: AppendInt64(nil, 1<<40)},
+			nil,
+			[]interface{}{float64(1 << 40)},
+		},
+		{
+			"AsFloat64OK/Int32", Value.AsFloat64OK, Value{Type: bsontype.Int32, Data: AppendInt32(nil, 5)},
+			nil,
+			[]interface{}{float64(5), true},
+		},
+		{
+			"AsFloat64OK/String", Value.AsFloat64OK, Value{Type: bsontype.String, Data: AppendString(nil, "5")},
+			nil,
+			[]interface{}{float64(0), false},
+		},
 	}
 
 	for _, tc := range testCases {
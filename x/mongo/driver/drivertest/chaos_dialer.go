@@ -0,0 +1,192 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package drivertest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrInjectedReset is returned by reads and writes on connections that a ChaosDialer reset.
+var ErrInjectedReset = errors.New("drivertest: injected connection reset")
+
+// Dialer dials network connections. It is satisfied by *net.Dialer, by MockDeployment and by the
+// ContextDialer of the client options.
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// ChaosSchedule configures the faults a ChaosDialer injects. Faults are drawn from a random source
+// seeded with Seed, so a schedule run against the same sequence of reads and writes injects the
+// same faults.
+type ChaosSchedule struct {
+	// Seed seeds the random source faults are drawn from.
+	Seed int64
+	// Latency is added before every read and write.
+	Latency time.Duration
+	// Jitter is the upper bound of a random delay added to Latency.
+	Jitter time.Duration
+	// PartialWriteRate is the probability, between 0 and 1, that a write sends only the first half
+	// of its bytes and then resets the connection.
+	PartialWriteRate float64
+	// ResetRate is the probability, between 0 and 1, that a read or write resets the connection
+	// instead of being performed.
+	ResetRate float64
+}
+
+// ChaosStats counts the faults a ChaosDialer injected.
+type ChaosStats struct {
+	Delays        int
+	PartialWrites int
+	Resets        int
+}
+
+// ChaosDialer wraps a Dialer and injects latency, partial writes and connection resets into the
+// connections it dials according to a ChaosSchedule, so that error handling can be exercised
+// against the real driver stack. It is safe to use concurrently.
+//
+// A ChaosDialer is used as the dialer of a client:
+//
+//	d := drivertest.NewChaosDialer(nil, drivertest.ChaosSchedule{Seed: 1, ResetRate: 0.1})
+//	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetDialer(d))
+type ChaosDialer struct {
+	dialer Dialer
+
+	mu       sync.Mutex
+	schedule ChaosSchedule
+	rand     *rand.Rand
+	paused   bool
+	stats    ChaosStats
+}
+
+// NewChaosDialer creates a ChaosDialer that dials with dialer, or with a net.Dialer if dialer is
+// nil, and injects faults according to schedule.
+func NewChaosDialer(dialer Dialer, schedule ChaosSchedule) *ChaosDialer {
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+	return &ChaosDialer{
+		dialer:   dialer,
+		schedule: schedule,
+		rand:     rand.New(rand.NewSource(schedule.Seed)),
+	}
+}
+
+// DialContext implements the ContextDialer interface of the client options.
+func (d *ChaosDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := d.dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &chaosConn{Conn: conn, dialer: d}, nil
+}
+
+// SetSchedule replaces the schedule and reseeds the random source with its seed.
+func (d *ChaosDialer) SetSchedule(schedule ChaosSchedule) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.schedule = schedule
+	d.rand = rand.New(rand.NewSource(schedule.Seed))
+}
+
+// Pause stops injecting faults until Resume is called, for example while a client connects.
+func (d *ChaosDialer) Pause() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.paused = true
+}
+
+// Resume resumes injecting faults after Pause.
+func (d *ChaosDialer) Resume() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.paused = false
+}
+
+// Stats returns the number of faults injected so far.
+func (d *ChaosDialer) Stats() ChaosStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.stats
+}
+
+type chaosFault int
+
+const (
+	faultNone chaosFault = iota
+	faultPartialWrite
+	faultReset
+)
+
+// next draws the delay and fault for the next read or write.
+func (d *ChaosDialer) next(write bool) (time.Duration, chaosFault) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.paused {
+		return 0, faultNone
+	}
+
+	s := d.schedule
+	delay := s.Latency
+	if s.Jitter > 0 {
+		delay += time.Duration(d.rand.Int63n(int64(s.Jitter)))
+	}
+	if delay > 0 {
+		d.stats.Delays++
+	}
+
+	fault := faultNone
+	switch r := d.rand.Float64(); {
+	case r < s.ResetRate:
+		fault = faultReset
+		d.stats.Resets++
+	case write && r < s.ResetRate+s.PartialWriteRate:
+		fault = faultPartialWrite
+		d.stats.PartialWrites++
+	}
+	return delay, fault
+}
+
+// chaosConn is a connection dialed by a ChaosDialer.
+type chaosConn struct {
+	net.Conn
+	dialer *ChaosDialer
+}
+
+func (c *chaosConn) Read(b []byte) (int, error) {
+	delay, fault := c.dialer.next(false)
+	time.Sleep(delay)
+	if fault == faultReset {
+		_ = c.Conn.Close()
+		return 0, ErrInjectedReset
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *chaosConn) Write(b []byte) (int, error) {
+	delay, fault := c.dialer.next(true)
+	time.Sleep(delay)
+	switch fault {
+	case faultReset:
+		_ = c.Conn.Close()
+		return 0, ErrInjectedReset
+	case faultPartialWrite:
+		n, _ := c.Conn.Write(b[:len(b)/2])
+		_ = c.Conn.Close()
+		return n, ErrInjectedReset
+	}
+	return c.Conn.Write(b)
+}
@@ -0,0 +1,76 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package drivertest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/drivertest"
+)
+
+func TestChaosDialer(t *testing.T) {
+	ctx := context.Background()
+	ping := bson.D{{"ping", 1}}
+
+	connect := func(t *testing.T, d *drivertest.ChaosDialer) *mongo.Client {
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://mock").SetDialer(d).
+			SetServerSelectionTimeout(time.Second).SetRetryWrites(false).SetRetryReads(false))
+		require.NoError(t, err)
+		return client
+	}
+
+	t.Run("latency", func(t *testing.T) {
+		md := drivertest.NewMockDeployment()
+		md.AddResponses("ping", drivertest.Response{})
+		d := drivertest.NewChaosDialer(md, drivertest.ChaosSchedule{})
+		client := connect(t, d)
+		defer func() { _ = client.Disconnect(ctx) }()
+		require.NoError(t, client.Database("admin").RunCommand(ctx, ping).Err())
+
+		d.SetSchedule(drivertest.ChaosSchedule{Latency: 50 * time.Millisecond})
+		start := time.Now()
+		require.NoError(t, client.Database("admin").RunCommand(ctx, ping).Err())
+		require.True(t, time.Since(start) >= 100*time.Millisecond, "expected a delayed write and read")
+		require.True(t, d.Stats().Delays >= 2)
+	})
+	t.Run("resets", func(t *testing.T) {
+		for _, schedule := range []drivertest.ChaosSchedule{{ResetRate: 1}, {PartialWriteRate: 1}} {
+			md := drivertest.NewMockDeployment()
+			md.AddResponses("ping", drivertest.Response{})
+			d := drivertest.NewChaosDialer(md, drivertest.ChaosSchedule{})
+			client := connect(t, d)
+			require.NoError(t, client.Database("admin").RunCommand(ctx, ping).Err())
+
+			d.SetSchedule(schedule)
+			require.Error(t, client.Database("admin").RunCommand(ctx, ping).Err())
+			stats := d.Stats()
+			require.True(t, stats.Resets+stats.PartialWrites > 0)
+			d.Pause()
+			_ = client.Disconnect(ctx)
+		}
+	})
+	t.Run("seeded schedules repeat", func(t *testing.T) {
+		draw := func() drivertest.ChaosStats {
+			md := drivertest.NewMockDeployment()
+			d := drivertest.NewChaosDialer(md, drivertest.ChaosSchedule{Seed: 42, ResetRate: 0.3, PartialWriteRate: 0.3})
+			for i := 0; i < 20; i++ {
+				conn, err := d.DialContext(ctx, "tcp", "mock")
+				require.NoError(t, err)
+				_, _ = conn.Write([]byte{})
+				_ = conn.Close()
+			}
+			return d.Stats()
+		}
+		require.Equal(t, draw(), draw())
+	})
+}
@@ -0,0 +1,304 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package drivertest provides an in-memory mock of a MongoDB deployment so that code using the
+// driver can be unit tested without a running mongod.
+//
+// A MockDeployment is a dialer. Clients that dial it with
+//
+//	options.Client().ApplyURI("mongodb://mock").SetDialer(d)
+//
+// speak the wire protocol to an in-memory standalone server, which answers the handshake and
+// server monitoring itself and replies to every other command with the responses scripted with
+// AddResponses. TLS, compression and authentication are not supported.
+//
+// A ChaosDialer wraps another dialer, such as a MockDeployment or a net.Dialer connected to a real
+// deployment, and injects latency, partial writes and connection resets into its connections.
+package drivertest // import "go.mongodb.org/mongo-driver/x/mongo/driver/drivertest"
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+// CommandNotFound is the code of the error replied to commands without a scripted response.
+const CommandNotFound = 59
+
+// maxMessageSize bounds the size of the messages read by the mock server.
+const maxMessageSize = 48000000
+
+// Response is a scripted response to a command.
+type Response struct {
+	// Reply is the reply document, which is marshaled with bson.Marshal. A nil Reply is replied
+	// to as {ok: 1}.
+	Reply interface{}
+	// Latency is the time the server waits before replying.
+	Latency time.Duration
+	// NetworkError closes the connection instead of replying, so that the command fails with a
+	// network error.
+	NetworkError bool
+}
+
+// MockDeployment is an in-memory standalone server that replies to commands with scripted
+// responses. It is safe to use concurrently.
+type MockDeployment struct {
+	mu        sync.Mutex
+	responses map[string][]Response
+	commands  []bson.Raw
+}
+
+// NewMockDeployment creates a MockDeployment without any scripted responses.
+func NewMockDeployment() *MockDeployment {
+	return &MockDeployment{responses: make(map[string][]Response)}
+}
+
+// AddResponses scripts the responses to the command with the given name, such as "find" or
+// "insert". Responses are used in the order they are added, and the last one is repeated once the
+// others are used. Unless responses are scripted for them, the isMaster command is answered with a
+// standalone server description, the endSessions and killCursors commands succeed, and other
+// commands fail with a CommandNotFound error.
+func (d *MockDeployment) AddResponses(command string, responses ...Response) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.responses[command] = append(d.responses[command], responses...)
+}
+
+// Commands returns the commands received by the deployment, other than isMaster, in the order they
+// were received. The document sequences of OP_MSG messages, such as the documents of an insert,
+// are included in the commands as arrays.
+func (d *MockDeployment) Commands() []bson.Raw {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	commands := make([]bson.Raw, len(d.commands))
+	copy(commands, d.commands)
+	return commands
+}
+
+// Reset removes the scripted responses and the received commands.
+func (d *MockDeployment) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.responses = make(map[string][]Response)
+	d.commands = nil
+}
+
+// DialContext implements the ContextDialer interface of the client options.
+func (d *MockDeployment) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	client, server := net.Pipe()
+	go d.serve(server)
+	return client, nil
+}
+
+// serve replies to the messages sent on conn until it is closed.
+func (d *MockDeployment) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	for {
+		msg, err := readMessage(conn)
+		if err != nil {
+			return
+		}
+
+		reply, resp, err := d.respond(msg)
+		if err != nil {
+			return
+		}
+		if resp.Latency > 0 {
+			time.Sleep(resp.Latency)
+		}
+		if resp.NetworkError {
+			return
+		}
+		if reply == nil {
+			continue
+		}
+		if _, err := conn.Write(reply); err != nil {
+			return
+		}
+	}
+}
+
+// respond returns the reply to msg, or nil if it is not replied to, and the response it was built
+// from.
+func (d *MockDeployment) respond(msg []byte) ([]byte, Response, error) {
+	header, err := wiremessage.ReadHeader(msg, 0)
+	if err != nil {
+		return nil, Response{}, err
+	}
+
+	var cmd bson.Raw
+	acknowledged := true
+	switch header.OpCode {
+	case wiremessage.OpMsg:
+		var m wiremessage.Msg
+		if err := m.UnmarshalWireMessage(msg); err != nil {
+			return nil, Response{}, err
+		}
+		cmd, err = msgCommand(m)
+		if err != nil {
+			return nil, Response{}, err
+		}
+		acknowledged = m.AcknowledgedWrite()
+	case wiremessage.OpQuery:
+		var q wiremessage.Query
+		if err := q.UnmarshalWireMessage(msg); err != nil {
+			return nil, Response{}, err
+		}
+		cmd = q.Query
+		if inner, ok := cmd.Lookup("$query").DocumentOK(); ok {
+			cmd = inner
+		}
+	default:
+		return nil, Response{}, wiremessage.ErrUnknownOpCode
+	}
+
+	var name string
+	if elem, err := cmd.IndexErr(0); err == nil {
+		name = elem.Key()
+	}
+	resp := d.nextResponse(name, cmd)
+	if !acknowledged {
+		return nil, resp, nil
+	}
+
+	var doc bson.Raw
+	switch reply := resp.Reply.(type) {
+	case nil:
+		doc, err = bson.Marshal(bson.D{{"ok", 1}})
+	case bson.Raw:
+		doc = reply
+	default:
+		doc, err = bson.Marshal(reply)
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	replyHeader := wiremessage.Header{RequestID: wiremessage.NextRequestID(), ResponseTo: header.RequestID}
+	if header.OpCode == wiremessage.OpQuery {
+		b, err := wiremessage.Reply{MsgHeader: replyHeader, NumberReturned: 1, Documents: []bson.Raw{doc}}.MarshalWireMessage()
+		return b, resp, err
+	}
+	b, err := wiremessage.Msg{
+		MsgHeader: replyHeader,
+		Sections:  []wiremessage.Section{wiremessage.SectionBody{Document: doc}},
+	}.MarshalWireMessage()
+	return b, resp, err
+}
+
+// nextResponse records cmd and returns the response to the command with the given name.
+func (d *MockDeployment) nextResponse(name string, cmd bson.Raw) Response {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	isMaster := name == "isMaster" || name == "ismaster"
+	if !isMaster {
+		d.commands = append(d.commands, cmd)
+	}
+
+	responses := d.responses[name]
+	switch {
+	case len(responses) > 1:
+		d.responses[name] = responses[1:]
+		return responses[0]
+	case len(responses) == 1:
+		return responses[0]
+	case isMaster:
+		return Response{Reply: isMasterReply}
+	case name == "endSessions" || name == "killCursors":
+		return Response{}
+	}
+	return Response{Reply: ErrorReply(CommandNotFound, "no response scripted for command "+strconv.Quote(name))}
+}
+
+var isMasterReply = bson.D{
+	{"ismaster", true},
+	{"maxBsonObjectSize", int32(16777216)},
+	{"maxMessageSizeBytes", int32(maxMessageSize)},
+	{"maxWriteBatchSize", int32(100000)},
+	{"logicalSessionTimeoutMinutes", int32(30)},
+	{"minWireVersion", int32(0)},
+	{"maxWireVersion", int32(8)},
+	{"ok", 1},
+}
+
+// ErrorReply returns the reply of a command that failed with the given code and message.
+func ErrorReply(code int32, msg string) bson.D {
+	return bson.D{{"ok", 0}, {"errmsg", msg}, {"code", code}}
+}
+
+// CursorReply returns the reply of a find or aggregate command on the namespace ns that returns
+// docs in its first batch and has no further batches.
+func CursorReply(ns string, docs ...interface{}) bson.D {
+	return bson.D{
+		{"cursor", bson.D{{"firstBatch", bson.A(docs)}, {"id", int64(0)}, {"ns", ns}}},
+		{"ok", 1},
+	}
+}
+
+// msgCommand returns the body of m with its document sequences appended as arrays.
+func msgCommand(m wiremessage.Msg) (bson.Raw, error) {
+	var body bson.Raw
+	var sequences []wiremessage.SectionDocumentSequence
+	for _, section := range m.Sections {
+		switch s := section.(type) {
+		case wiremessage.SectionBody:
+			body = s.Document
+		case wiremessage.SectionDocumentSequence:
+			sequences = append(sequences, s)
+		}
+	}
+	if len(sequences) == 0 || len(body) < 5 {
+		return body, nil
+	}
+
+	idx, doc := bsoncore.AppendDocumentStart(nil)
+	doc = append(doc, body[4:len(body)-1]...)
+	for _, seq := range sequences {
+		var aidx int32
+		aidx, doc = bsoncore.AppendArrayElementStart(doc, seq.Identifier)
+		for i, d := range seq.Documents {
+			doc = bsoncore.AppendDocumentElement(doc, strconv.Itoa(i), d)
+		}
+		var err error
+		if doc, err = bsoncore.AppendArrayEnd(doc, aidx); err != nil {
+			return nil, err
+		}
+	}
+	doc, err := bsoncore.AppendDocumentEnd(doc, idx)
+	return bson.Raw(doc), err
+}
+
+// readMessage reads a complete wire message from r.
+func readMessage(r io.Reader) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	n := int32(binary.LittleEndian.Uint32(size[:]))
+	if n < 16 || n > maxMessageSize {
+		return nil, wiremessage.ErrHeaderInvalidLength
+	}
+
+	msg := make([]byte, n)
+	copy(msg, size[:])
+	if _, err := io.ReadFull(r, msg[4:]); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package drivertest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/drivertest"
+)
+
+func TestMockDeployment(t *testing.T) {
+	ctx := context.Background()
+	d := drivertest.NewMockDeployment()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://mock").SetDialer(d).
+		SetServerSelectionTimeout(time.Second).SetRetryWrites(false).SetRetryReads(false))
+	require.NoError(t, err)
+	defer func() { _ = client.Disconnect(ctx) }()
+	coll := client.Database("db").Collection("coll")
+
+	t.Run("scripted responses", func(t *testing.T) {
+		d.Reset()
+		d.AddResponses("insert", drivertest.Response{Reply: bson.D{{"ok", 1}, {"n", 1}}})
+		d.AddResponses("find", drivertest.Response{Reply: drivertest.CursorReply("db.coll", bson.D{{"x", int32(1)}})})
+
+		_, err := coll.InsertOne(ctx, bson.D{{"x", int32(1)}})
+		require.NoError(t, err)
+		var got struct{ X int32 }
+		require.NoError(t, coll.FindOne(ctx, bson.D{}).Decode(&got))
+		require.Equal(t, int32(1), got.X)
+
+		commands := d.Commands()
+		require.True(t, len(commands) >= 2, "expected the insert and find commands, got %v", commands)
+		require.Equal(t, "coll", commands[0].Lookup("insert").StringValue())
+		x, err := commands[0].LookupErr("documents", "0", "x")
+		require.NoError(t, err)
+		require.Equal(t, int32(1), x.Int32())
+		require.Equal(t, "coll", commands[1].Lookup("find").StringValue())
+	})
+	t.Run("errors", func(t *testing.T) {
+		d.Reset()
+		d.AddResponses("delete",
+			drivertest.Response{Reply: drivertest.ErrorReply(13, "unauthorized")},
+			drivertest.Response{NetworkError: true},
+		)
+
+		_, err := coll.DeleteOne(ctx, bson.D{})
+		cmdErr, ok := err.(mongo.CommandError)
+		require.True(t, ok, "expected a command error, got %v", err)
+		require.Equal(t, int32(13), cmdErr.Code)
+
+		_, err = coll.DeleteOne(ctx, bson.D{})
+		require.Error(t, err)
+
+		_, err = coll.UpdateOne(ctx, bson.D{}, bson.D{{"$set", bson.D{{"x", 1}}}})
+		require.Error(t, err, "commands without scripted responses should fail")
+	})
+	t.Run("latency", func(t *testing.T) {
+		d.Reset()
+		d.AddResponses("ping", drivertest.Response{Latency: 200 * time.Millisecond})
+
+		pingCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		ping := bson.D{{"ping", 1}}
+		require.Error(t, client.Database("admin").RunCommand(pingCtx, ping).Err())
+		require.NoError(t, client.Database("admin").RunCommand(ctx, ping).Err())
+	})
+}
@@ -28,6 +28,7 @@ func AbortTransaction(
 		// Retry if appropriate
 		if cerr.Retryable() {
 			res, err = abortTransaction(ctx, cmd, topo, selector, cerr)
+			recordWriteRetry(topo, cerr.Code, err, res.WriteConcernError)
 		}
 	}
 	return res, err
@@ -61,7 +62,7 @@ func abortTransaction(
 		return result.TransactionResult{}, oldErr
 	}
 
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		if oldErr != nil {
 			return result.TransactionResult{}, oldErr
@@ -20,6 +20,7 @@ import (
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
 	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/description"
 	"go.mongodb.org/mongo-driver/x/network/result"
 )
@@ -34,6 +35,7 @@ func Aggregate(
 	clientID uuid.UUID,
 	pool *session.Pool,
 	registry *bsoncodec.Registry,
+	retryRead bool,
 	opts ...*options.AggregateOptions,
 ) (*BatchCursor, error) {
 
@@ -59,7 +61,7 @@ func Aggregate(
 	}
 
 	desc := ss.Description()
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		return nil, err
 	}
@@ -81,65 +83,120 @@ func Aggregate(
 	}
 
 	aggOpts := options.MergeAggregateOptions(opts...)
+	if err = addAggregateOptions(&cmd, aggOpts, desc, registry); err != nil {
+		return nil, err
+	}
+
+	var retryConn connection.Connection
+	res, err := cmd.RoundTrip(ctx, desc, conn)
+	if err != nil && retryRead && !dollarOut && shouldRetryRead(topo, desc, cmd.Session, err) {
+		ss, err = retryReadOnce(ctx, topo, readSelector, cmd.Session, ss, err, func(desc description.SelectedServer, conn connection.Connection) error {
+			var rtErr error
+			res, rtErr = cmd.RoundTrip(ctx, desc, conn)
+			retryConn = topology.PinConnection(conn)
+			return rtErr
+		})
+		desc = ss.Description()
+	}
+	if retryConn != nil {
+		defer retryConn.Close()
+		conn = retryConn
+	}
+	if err != nil {
+		if wce, ok := err.(result.WriteConcernError); ok {
+			ss.ProcessWriteConcernError(&wce)
+		}
+		closeImplicitSession(cmd.Session)
+		return nil, err
+	}
+
+	if desc.WireVersion.Max < 4 {
+		var batchSize int32
+		if aggOpts.BatchSize != nil {
+			batchSize = *aggOpts.BatchSize
+		}
+		return buildLegacyCommandBatchCursor(res, batchSize, ss.Server)
+	}
+
+	bc, err := NewBatchCursor(bsoncore.Document(res), cmd.Session, cmd.Clock, ss.Server, cmd.CursorOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if aggOpts.TargetBatchBytes != nil {
+		bc.SetTargetBatchBytes(*aggOpts.TargetBatchBytes)
+	}
+	bc.PinConnection(conn)
+	return bc, nil
+}
+
+// addAggregateOptions appends the elements for the options aggOpts to the aggregate command and to
+// the getMore commands of its cursor.
+func addAggregateOptions(cmd *command.Aggregate, aggOpts *options.AggregateOptions, desc description.SelectedServer,
+	registry *bsoncodec.Registry) error {
+
+	gmo := aggOpts.GetMoreOptions
+	if gmo == nil {
+		gmo = options.GetMore()
+	}
 
 	if aggOpts.AllowDiskUse != nil {
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"allowDiskUse", bsonx.Boolean(*aggOpts.AllowDiskUse)})
 	}
-	var batchSize int32
 	if aggOpts.BatchSize != nil {
 		elem := bsonx.Elem{"batchSize", bsonx.Int32(*aggOpts.BatchSize)}
 		cmd.Opts = append(cmd.Opts, elem)
-		cmd.CursorOpts = append(cmd.CursorOpts, elem)
-		batchSize = *aggOpts.BatchSize
+		if sendOnGetMore(gmo.BatchSize, true) {
+			cmd.CursorOpts = append(cmd.CursorOpts, elem)
+		}
 	}
 	if aggOpts.BypassDocumentValidation != nil && desc.WireVersion.Includes(4) {
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"bypassDocumentValidation", bsonx.Boolean(*aggOpts.BypassDocumentValidation)})
 	}
 	if aggOpts.Collation != nil {
 		if desc.WireVersion.Max < 5 {
-			return nil, ErrCollation
+			return ErrCollation
 		}
 		collDoc, err := bsonx.ReadDoc(aggOpts.Collation.ToDocument())
 		if err != nil {
-			return nil, err
+			return err
 		}
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"collation", bsonx.Document(collDoc)})
 	}
 	if aggOpts.MaxTime != nil {
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"maxTimeMS", bsonx.Int64(int64(*aggOpts.MaxTime / time.Millisecond))})
 	}
-	if aggOpts.MaxAwaitTime != nil {
+	if aggOpts.MaxAwaitTime != nil && sendOnGetMore(gmo.MaxAwaitTime, true) {
 		// specified as maxTimeMS on getMore commands
 		cmd.CursorOpts = append(cmd.CursorOpts, bsonx.Elem{
 			"maxTimeMS", bsonx.Int64(int64(*aggOpts.MaxAwaitTime / time.Millisecond)),
 		})
 	}
 	if aggOpts.Comment != nil {
-		cmd.Opts = append(cmd.Opts, bsonx.Elem{"comment", bsonx.String(*aggOpts.Comment)})
+		elem, err := commentElement("comment", aggOpts.Comment, desc.WireVersion.Max, registry)
+		if err != nil {
+			return err
+		}
+		cmd.Opts = append(cmd.Opts, elem)
+		if sendOnGetMore(gmo.Comment, desc.WireVersion.Max >= 9) {
+			cmd.CursorOpts = append(cmd.CursorOpts, elem)
+		}
 	}
 	if aggOpts.Hint != nil {
 		hintElem, err := interfaceToElement("hint", aggOpts.Hint, registry)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		cmd.Opts = append(cmd.Opts, hintElem)
 	}
-
-	res, err := cmd.RoundTrip(ctx, desc, conn)
-	if err != nil {
-		if wce, ok := err.(result.WriteConcernError); ok {
-			ss.ProcessWriteConcernError(&wce)
+	if aggOpts.Let != nil {
+		letElem, err := letElement(aggOpts.Let, desc.WireVersion.Max, registry)
+		if err != nil {
+			return err
 		}
-		closeImplicitSession(cmd.Session)
-		return nil, err
-	}
-
-	if desc.WireVersion.Max < 4 {
-		return buildLegacyCommandBatchCursor(res, batchSize, ss.Server)
+		cmd.Opts = append(cmd.Opts, letElem)
 	}
-
-	return NewBatchCursor(bsoncore.Document(res), cmd.Session, cmd.Clock, ss.Server, cmd.CursorOpts...)
+	return nil
 }
 
 func buildLegacyCommandBatchCursor(rdr bson.Raw, batchSize int32, server *topology.Server) (*BatchCursor, error) {
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/x/network/address"
 	"go.mongodb.org/mongo-driver/x/network/command"
 	"go.mongodb.org/mongo-driver/x/network/connection"
@@ -30,6 +31,7 @@ func init() {
 	RegisterAuthenticatorFactory(PLAIN, newPlainAuthenticator)
 	RegisterAuthenticatorFactory(GSSAPI, newGSSAPIAuthenticator)
 	RegisterAuthenticatorFactory(MongoDBX509, newMongoDBX509Authenticator)
+	RegisterAuthenticatorFactory(MongoDBOIDC, newOIDCAuthenticator)
 }
 
 // CreateAuthenticator creates an authenticator.
@@ -99,6 +101,8 @@ type HandshakeOptions struct {
 	Authenticator         Authenticator
 	Compressors           []string
 	DBUser                string
+	DriverInfo            command.DriverInfo
+	LoadBalanced          bool
 	PerformAuthentication func(description.Server) bool
 }
 
@@ -106,9 +110,10 @@ type HandshakeOptions struct {
 func Handshaker(h connection.Handshaker, options *HandshakeOptions) connection.Handshaker {
 	return connection.HandshakerFunc(func(ctx context.Context, addr address.Address, rw wiremessage.ReadWriter) (description.Server, error) {
 		desc, err := (&command.Handshake{
-			Client:             command.ClientDoc(options.AppName),
+			Client:             command.ClientDocWithDriverInfo(options.AppName, options.DriverInfo),
 			Compressors:        options.Compressors,
 			SaslSupportedMechs: options.DBUser,
+			LoadBalanced:       options.LoadBalanced,
 		}).Handshake(ctx, addr, rw)
 
 		if err != nil {
@@ -121,11 +126,14 @@ func Handshaker(h connection.Handshaker, options *HandshakeOptions) connection.H
 				return serv.Kind == description.RSPrimary ||
 					serv.Kind == description.RSSecondary ||
 					serv.Kind == description.Mongos ||
-					serv.Kind == description.Standalone
+					serv.Kind == description.Standalone ||
+					serv.Kind == description.LoadBalancer
 			}
 		}
 		if performAuth(desc) && options.Authenticator != nil {
+			finish := connection.StartStage(ctx, event.StageAuthentication)
 			err = options.Authenticator.Auth(ctx, desc, rw)
+			finish(err)
 			if err != nil {
 				return description.Server{}, newAuthError("auth error", err)
 			}
@@ -144,6 +152,14 @@ type Authenticator interface {
 	Auth(context.Context, description.Server, wiremessage.ReadWriter) error
 }
 
+// Reauthenticator is an Authenticator that can authenticate a connection again when the server
+// returns a ReauthenticationRequired error, because the credential it authenticated with expired.
+type Reauthenticator interface {
+	Authenticator
+	// Reauth authenticates the connection again with a new credential.
+	Reauth(context.Context, description.Server, wiremessage.ReadWriter) error
+}
+
 func newAuthError(msg string, inner error) error {
 	return &Error{
 		message: msg,
@@ -13,4 +13,7 @@ type Cred struct {
 	Password    string
 	PasswordSet bool
 	Props       map[string]string
+
+	OIDCMachineCallback OIDCCallback
+	OIDCHumanCallback   OIDCCallback
 }
@@ -0,0 +1,291 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+// MongoDBOIDC is the mechanism name for MONGODB-OIDC.
+const MongoDBOIDC = "MONGODB-OIDC"
+
+// The authMechanismProperties of MONGODB-OIDC.
+const (
+	// OIDCEnvironmentProp is the environment whose built-in callback provides the tokens, either
+	// "azure" or "gcp".
+	OIDCEnvironmentProp = "ENVIRONMENT"
+	// OIDCTokenResourceProp is the resource, or audience, the tokens of the environment are
+	// requested for.
+	OIDCTokenResourceProp = "TOKEN_RESOURCE"
+)
+
+const (
+	oidcCallbackVersion      = 1
+	oidcMachineTimeout       = time.Minute
+	oidcHumanTimeout         = 5 * time.Minute
+	authenticationFailedCode = 18
+)
+
+// IDPInfo is the information about the identity provider the server returns to clients that
+// authenticate with the human flow of MONGODB-OIDC.
+type IDPInfo struct {
+	Issuer        string   `bson:"issuer"`
+	ClientID      string   `bson:"clientId"`
+	RequestScopes []string `bson:"requestScopes"`
+}
+
+// OIDCArgs are the arguments of an OIDCCallback.
+type OIDCArgs struct {
+	Version      int
+	IDPInfo      *IDPInfo // only set for the human flow
+	RefreshToken *string  // the refresh token of the last credential, if any, in the human flow
+}
+
+// OIDCCredential is the credential returned by an OIDCCallback.
+type OIDCCredential struct {
+	AccessToken  string
+	ExpiresAt    *time.Time
+	RefreshToken *string
+}
+
+// OIDCCallback returns the credential used to authenticate with MONGODB-OIDC. The context is
+// cancelled if the callback takes too long.
+type OIDCCallback func(context.Context, *OIDCArgs) (*OIDCCredential, error)
+
+func newOIDCAuthenticator(cred *Cred) (Authenticator, error) {
+	if cred.PasswordSet || cred.Password != "" {
+		return nil, newAuthError("password cannot be specified for MONGODB-OIDC", nil)
+	}
+
+	a := &OIDCAuthenticator{
+		username: cred.Username,
+		callback: cred.OIDCMachineCallback,
+	}
+	if cred.OIDCHumanCallback != nil {
+		if a.callback != nil {
+			return nil, newAuthError("machine and human OIDC callbacks cannot both be specified", nil)
+		}
+		a.callback = cred.OIDCHumanCallback
+		a.human = true
+	}
+	for prop := range cred.Props {
+		if prop != OIDCEnvironmentProp && prop != OIDCTokenResourceProp {
+			return nil, newAuthError(fmt.Sprintf("invalid auth property for MONGODB-OIDC: %s", prop), nil)
+		}
+	}
+	if env, ok := cred.Props[OIDCEnvironmentProp]; ok {
+		if a.callback != nil {
+			return nil, newAuthError("an OIDC callback cannot be specified with an OIDC environment", nil)
+		}
+		callback, err := oidcEnvironmentCallback(env, cred.Props[OIDCTokenResourceProp], cred.Username)
+		if err != nil {
+			return nil, err
+		}
+		a.callback = callback
+	} else if _, ok := cred.Props[OIDCTokenResourceProp]; ok {
+		return nil, newAuthError("TOKEN_RESOURCE requires an OIDC environment", nil)
+	}
+	if a.callback == nil {
+		return nil, newAuthError("MONGODB-OIDC requires an OIDC callback or environment", nil)
+	}
+	return a, nil
+}
+
+// OIDCAuthenticator uses the tokens returned by a callback to authenticate a connection with
+// MONGODB-OIDC. The tokens are cached and shared by all the connections it authenticates, and the
+// callback is not called concurrently.
+//
+// With the machine flow, the callback is called without any information about the identity
+// provider, such as a workload that reads its token from the environment. With the human flow,
+// the server first returns the identity provider to authenticate with, and the callback, usually
+// interacting with a person, is also given the refresh token of the last credential to renew it.
+type OIDCAuthenticator struct {
+	username string
+	callback OIDCCallback
+	human    bool
+
+	mu           sync.Mutex // guards the fields below and serializes the calls to the callback
+	accessToken  string
+	expiresAt    *time.Time
+	refreshToken *string
+	idpInfo      *IDPInfo
+}
+
+var _ Reauthenticator = (*OIDCAuthenticator)(nil)
+
+// Auth implements the Authenticator interface.
+func (a *OIDCAuthenticator) Auth(ctx context.Context, desc description.Server, rw wiremessage.ReadWriter) error {
+	a.mu.Lock()
+	idpInfo := a.idpInfo
+	a.mu.Unlock()
+
+	// The human flow asks the server for the identity provider until the callback returned a
+	// credential for it.
+	if a.human && idpInfo == nil {
+		return ConductSaslConversation(ctx, desc, rw, "$external", &oidcTwoStepClient{ctx: ctx, a: a})
+	}
+
+	token, cached, err := a.token(ctx, idpInfo)
+	if err != nil {
+		return newError(err, MongoDBOIDC)
+	}
+	err = ConductSaslConversation(ctx, desc, rw, "$external", &oidcOneStepClient{token: token})
+	if err == nil || !isAuthenticationFailure(err) {
+		return err
+	}
+
+	// The token may have expired or been revoked since it was cached, so a new one is requested.
+	a.invalidate(token)
+	if a.human {
+		a.mu.Lock()
+		a.idpInfo = nil
+		a.mu.Unlock()
+		return ConductSaslConversation(ctx, desc, rw, "$external", &oidcTwoStepClient{ctx: ctx, a: a})
+	}
+	if !cached {
+		return err
+	}
+	if token, _, err = a.token(ctx, nil); err != nil {
+		return newError(err, MongoDBOIDC)
+	}
+	return ConductSaslConversation(ctx, desc, rw, "$external", &oidcOneStepClient{token: token})
+}
+
+// Reauth implements the Reauthenticator interface. The cached token is discarded because the
+// server no longer accepts it.
+func (a *OIDCAuthenticator) Reauth(ctx context.Context, desc description.Server, rw wiremessage.ReadWriter) error {
+	a.mu.Lock()
+	a.accessToken = ""
+	a.expiresAt = nil
+	a.mu.Unlock()
+	return a.Auth(ctx, desc, rw)
+}
+
+// token returns the cached access token, or calls the callback for a new one if there is none or it
+// expired. The returned bool is true if the token was cached.
+func (a *OIDCAuthenticator) token(ctx context.Context, idpInfo *IDPInfo) (string, bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.accessToken != "" && (a.expiresAt == nil || time.Now().Before(*a.expiresAt)) {
+		return a.accessToken, true, nil
+	}
+
+	timeout := oidcMachineTimeout
+	args := &OIDCArgs{Version: oidcCallbackVersion}
+	if a.human {
+		timeout = oidcHumanTimeout
+		args.IDPInfo = idpInfo
+		args.RefreshToken = a.refreshToken
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cred, err := a.callback(ctx, args)
+	if err != nil {
+		return "", false, err
+	}
+	if cred == nil || cred.AccessToken == "" {
+		return "", false, errors.New("OIDC callback returned no access token")
+	}
+	a.accessToken = cred.AccessToken
+	a.expiresAt = cred.ExpiresAt
+	if a.human {
+		a.refreshToken = cred.RefreshToken
+		a.idpInfo = idpInfo
+	}
+	return a.accessToken, false, nil
+}
+
+// invalidate discards token if it is the cached access token, along with the refresh token that
+// may have been used to obtain it.
+func (a *OIDCAuthenticator) invalidate(token string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.accessToken == token {
+		a.accessToken = ""
+		a.expiresAt = nil
+		a.refreshToken = nil
+	}
+}
+
+func isAuthenticationFailure(err error) bool {
+	if e, ok := err.(*Error); ok {
+		err = e.inner
+	}
+	cerr, ok := err.(command.Error)
+	return ok && cerr.Code == authenticationFailedCode
+}
+
+// oidcOneStepClient authenticates with an access token in saslStart.
+type oidcOneStepClient struct {
+	token string
+}
+
+func (c *oidcOneStepClient) Start() (string, []byte, error) {
+	payload, err := bson.Marshal(bson.D{{"jwt", c.token}})
+	return MongoDBOIDC, payload, err
+}
+
+func (c *oidcOneStepClient) Next(challenge []byte) ([]byte, error) {
+	return nil, newAuthError("unexpected server challenge", nil)
+}
+
+func (c *oidcOneStepClient) Completed() bool {
+	return true
+}
+
+// oidcTwoStepClient requests the identity provider in saslStart and authenticates with the access
+// token returned by the callback for it in saslContinue.
+type oidcTwoStepClient struct {
+	ctx  context.Context
+	a    *OIDCAuthenticator
+	done bool
+}
+
+func (c *oidcTwoStepClient) Start() (string, []byte, error) {
+	var doc bson.D
+	if c.a.username != "" {
+		doc = append(doc, bson.E{Key: "n", Value: c.a.username})
+	}
+	payload, err := bson.Marshal(doc)
+	return MongoDBOIDC, payload, err
+}
+
+func (c *oidcTwoStepClient) Next(challenge []byte) ([]byte, error) {
+	if c.done {
+		return nil, newAuthError("unexpected server challenge", nil)
+	}
+	var idpInfo IDPInfo
+	if err := bson.Unmarshal(challenge, &idpInfo); err != nil {
+		return nil, newAuthError("invalid identity provider information", err)
+	}
+	if idpInfo.Issuer == "" {
+		return nil, newAuthError("identity provider information has no issuer", nil)
+	}
+
+	token, _, err := c.a.token(c.ctx, &idpInfo)
+	if err != nil {
+		return nil, err
+	}
+	c.done = true
+	return bson.Marshal(bson.D{{"jwt", token}})
+}
+
+func (c *oidcTwoStepClient) Completed() bool {
+	return c.done
+}
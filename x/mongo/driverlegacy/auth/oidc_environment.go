@@ -0,0 +1,112 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The endpoints the built-in OIDC environments request tokens from. They are variables so that
+// tests can replace them.
+var (
+	azureIMDSURL   = "http://169.254.169.254/metadata/identity/oauth2/token"
+	gcpMetadataURL = "http://metadata/computeMetadata/v1/instance/service-accounts/default/identity"
+)
+
+// oidcEnvironmentCallback returns the machine callback of the OIDC environment env, which requests
+// tokens for resource from the metadata service of the cloud provider. For Azure, username is the
+// client ID of the managed identity to use, if there are several.
+func oidcEnvironmentCallback(env, resource, username string) (OIDCCallback, error) {
+	if resource == "" {
+		return nil, newAuthError(fmt.Sprintf("TOKEN_RESOURCE is required for the %s OIDC environment", env), nil)
+	}
+
+	switch strings.ToLower(env) {
+	case "azure":
+		return func(ctx context.Context, _ *OIDCArgs) (*OIDCCredential, error) {
+			return azureToken(ctx, resource, username)
+		}, nil
+	case "gcp":
+		if username != "" {
+			return nil, newAuthError("username cannot be specified for the gcp OIDC environment", nil)
+		}
+		return func(ctx context.Context, _ *OIDCArgs) (*OIDCCredential, error) {
+			return gcpToken(ctx, resource)
+		}, nil
+	default:
+		return nil, newAuthError(fmt.Sprintf("unknown OIDC environment: %s", env), nil)
+	}
+}
+
+// azureToken requests a token for resource from the Azure Instance Metadata Service.
+func azureToken(ctx context.Context, resource, clientID string) (*OIDCCredential, error) {
+	query := url.Values{}
+	query.Set("api-version", "2018-02-01")
+	query.Set("resource", resource)
+	if clientID != "" {
+		query.Set("client_id", clientID)
+	}
+	body, err := getMetadata(ctx, azureIMDSURL+"?"+query.Encode(), "Metadata", "true")
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   string `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("invalid Azure token response: %v", err)
+	}
+	cred := &OIDCCredential{AccessToken: resp.AccessToken}
+	if secs, err := strconv.Atoi(resp.ExpiresIn); err == nil {
+		expiresAt := time.Now().Add(time.Duration(secs) * time.Second)
+		cred.ExpiresAt = &expiresAt
+	}
+	return cred, nil
+}
+
+// gcpToken requests an identity token for the audience resource from the GCP metadata server.
+func gcpToken(ctx context.Context, resource string) (*OIDCCredential, error) {
+	body, err := getMetadata(ctx, gcpMetadataURL+"?audience="+url.QueryEscape(resource), "Metadata-Flavor", "Google")
+	if err != nil {
+		return nil, err
+	}
+	return &OIDCCredential{AccessToken: strings.TrimSpace(string(body))}, nil
+}
+
+// getMetadata sends a GET request with the header key set to value to a metadata service.
+func getMetadata(ctx context.Context, endpoint, key, value string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(key, value)
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("cannot request token from metadata service: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read token from metadata service: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metadata service returned status %d: %s", resp.StatusCode, body)
+	}
+	return body, nil
+}
@@ -0,0 +1,66 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOIDCEnvironmentCallback(t *testing.T) {
+	t.Run("azure", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "true", r.Header.Get("Metadata"))
+			require.Equal(t, "api://app", r.URL.Query().Get("resource"))
+			require.Equal(t, "client", r.URL.Query().Get("client_id"))
+			_, _ = w.Write([]byte(`{"access_token":"azure-token","expires_in":"3599"}`))
+		}))
+		defer srv.Close()
+		defer func(u string) { azureIMDSURL = u }(azureIMDSURL)
+		azureIMDSURL = srv.URL
+
+		callback, err := oidcEnvironmentCallback("azure", "api://app", "client")
+		require.NoError(t, err)
+		cred, err := callback(context.Background(), &OIDCArgs{Version: oidcCallbackVersion})
+		require.NoError(t, err)
+		require.Equal(t, "azure-token", cred.AccessToken)
+		require.NotNil(t, cred.ExpiresAt)
+	})
+	t.Run("gcp", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			require.Equal(t, "aud", r.URL.Query().Get("audience"))
+			_, _ = w.Write([]byte("gcp-token\n"))
+		}))
+		defer srv.Close()
+		defer func(u string) { gcpMetadataURL = u }(gcpMetadataURL)
+		gcpMetadataURL = srv.URL
+
+		callback, err := oidcEnvironmentCallback("gcp", "aud", "")
+		require.NoError(t, err)
+		cred, err := callback(context.Background(), &OIDCArgs{Version: oidcCallbackVersion})
+		require.NoError(t, err)
+		require.Equal(t, "gcp-token", cred.AccessToken)
+	})
+	t.Run("error status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "no identity", http.StatusBadRequest)
+		}))
+		defer srv.Close()
+		defer func(u string) { gcpMetadataURL = u }(gcpMetadataURL)
+		gcpMetadataURL = srv.URL
+
+		callback, err := oidcEnvironmentCallback("gcp", "aud", "")
+		require.NoError(t, err)
+		_, err = callback(context.Background(), &OIDCArgs{Version: oidcCallbackVersion})
+		require.Error(t, err)
+	})
+}
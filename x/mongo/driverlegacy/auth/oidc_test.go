@@ -0,0 +1,134 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/internal"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	. "go.mongodb.org/mongo-driver/x/mongo/driverlegacy/auth"
+	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+var oidcServer = description.Server{WireVersion: &description.VersionRange{Max: 6}}
+
+func oidcConn(t *testing.T, replies ...bsonx.Doc) *internal.ChannelConn {
+	resps := make(chan wiremessage.WireMessage, len(replies))
+	writeReplies(t, resps, replies...)
+	return &internal.ChannelConn{Written: make(chan wiremessage.WireMessage, len(replies)), ReadResp: resps}
+}
+
+func saslDone(payload []byte) bsonx.Doc {
+	return bsonx.Doc{
+		{"ok", bsonx.Int32(1)},
+		{"conversationId", bsonx.Int32(1)},
+		{"payload", bsonx.Binary(0x00, payload)},
+		{"done", bsonx.Boolean(true)},
+	}
+}
+
+var authFailed = bsonx.Doc{
+	{"ok", bsonx.Int32(0)},
+	{"code", bsonx.Int32(18)},
+	{"errmsg", bsonx.String("Authentication failed")},
+}
+
+func TestOIDCAuthenticator_Machine(t *testing.T) {
+	var calls int
+	authenticator, err := CreateAuthenticator(MongoDBOIDC, &Cred{
+		OIDCMachineCallback: func(_ context.Context, args *OIDCArgs) (*OIDCCredential, error) {
+			calls++
+			require.Nil(t, args.IDPInfo)
+			return &OIDCCredential{AccessToken: "token"}, nil
+		},
+	})
+	require.NoError(t, err)
+
+	err = authenticator.Auth(context.Background(), oidcServer, oidcConn(t, saslDone(nil)))
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	// The token is cached.
+	err = authenticator.Auth(context.Background(), oidcServer, oidcConn(t, saslDone(nil)))
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	// A rejected cached token is replaced.
+	err = authenticator.Auth(context.Background(), oidcServer, oidcConn(t, authFailed, saslDone(nil)))
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+
+	// Reauthentication discards the cached token.
+	err = authenticator.(Reauthenticator).Reauth(context.Background(), oidcServer, oidcConn(t, saslDone(nil)))
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+}
+
+func TestOIDCAuthenticator_Human(t *testing.T) {
+	var args []OIDCArgs
+	refresh := "refresh"
+	authenticator, err := CreateAuthenticator(MongoDBOIDC, &Cred{
+		Username: "user",
+		OIDCHumanCallback: func(_ context.Context, a *OIDCArgs) (*OIDCCredential, error) {
+			args = append(args, *a)
+			return &OIDCCredential{AccessToken: "token", RefreshToken: &refresh}, nil
+		},
+	})
+	require.NoError(t, err)
+
+	idpInfo, err := bson.Marshal(bson.D{{"issuer", "https://idp"}, {"clientId", "client"}})
+	require.NoError(t, err)
+	start := bsonx.Doc{
+		{"ok", bsonx.Int32(1)},
+		{"conversationId", bsonx.Int32(1)},
+		{"payload", bsonx.Binary(0x00, idpInfo)},
+		{"done", bsonx.Boolean(false)},
+	}
+	err = authenticator.Auth(context.Background(), oidcServer, oidcConn(t, start, saslDone(nil)))
+	require.NoError(t, err)
+	require.Len(t, args, 1)
+	require.Equal(t, &IDPInfo{Issuer: "https://idp", ClientID: "client"}, args[0].IDPInfo)
+	require.Nil(t, args[0].RefreshToken)
+
+	// Reauthentication renews the token with the refresh token, without asking for the identity
+	// provider again.
+	err = authenticator.(Reauthenticator).Reauth(context.Background(), oidcServer, oidcConn(t, saslDone(nil)))
+	require.NoError(t, err)
+	require.Len(t, args, 2)
+	require.Equal(t, &refresh, args[1].RefreshToken)
+	require.Equal(t, "https://idp", args[1].IDPInfo.Issuer)
+}
+
+func TestOIDCAuthenticator_InvalidCred(t *testing.T) {
+	callback := func(context.Context, *OIDCArgs) (*OIDCCredential, error) { return nil, nil }
+
+	testCases := []struct {
+		name string
+		cred *Cred
+	}{
+		{"no callback", &Cred{}},
+		{"password", &Cred{Password: "pencil", PasswordSet: true, OIDCMachineCallback: callback}},
+		{"both callbacks", &Cred{OIDCMachineCallback: callback, OIDCHumanCallback: callback}},
+		{"callback and environment", &Cred{
+			OIDCMachineCallback: callback,
+			Props:               map[string]string{OIDCEnvironmentProp: "azure", OIDCTokenResourceProp: "res"},
+		}},
+		{"environment without resource", &Cred{Props: map[string]string{OIDCEnvironmentProp: "gcp"}}},
+		{"unknown environment", &Cred{Props: map[string]string{OIDCEnvironmentProp: "aws", OIDCTokenResourceProp: "res"}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := CreateAuthenticator(MongoDBOIDC, tc.cred)
+			require.Error(t, err)
+		})
+	}
+}
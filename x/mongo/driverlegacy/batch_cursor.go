@@ -6,11 +6,13 @@ import (
 	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/x/bsonx"
 	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
 	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/wiremessage"
 )
 
@@ -23,11 +25,18 @@ type BatchCursor struct {
 	id            int64
 	err           error
 	server        *topology.Server
+	pinnedConn    connection.Connection
 	opts          []bsonx.Elem
 	currentBatch  *bsoncore.DocumentSequence
 	firstBatch    bool
 	batchNumber   int
 
+	postBatchResumeToken bsoncore.Document
+	operationTime        *primitive.Timestamp
+
+	targetBatchBytes int32
+	avgDocumentSize  float64 // moving average of the size of the documents returned
+
 	// legacy server (< 3.2) fields
 	batchSize   int32
 	limit       int32
@@ -85,6 +94,8 @@ func NewBatchCursor(result bsoncore.Document, clientSession *session.Client, clo
 		}
 	}
 
+	bc.updateBatchMetadata(result)
+
 	// close session if everything fits in first batch
 	if bc.id == 0 {
 		bc.closeImplicitSession()
@@ -97,6 +108,21 @@ func NewEmptyBatchCursor() *BatchCursor {
 	return &BatchCursor{currentBatch: new(bsoncore.DocumentSequence)}
 }
 
+// NewBatchCursorFromID creates a BatchCursor that continues the server cursor with the given ID and
+// namespace on server, such as a cursor another process detached with Detach. clientSession must be
+// a session with the ID returned by Detach, or nil if it did not return one.
+func NewBatchCursorFromID(ns command.Namespace, id int64, clientSession *session.Client, clock *session.ClusterClock, server *topology.Server, opts ...bsonx.Elem) *BatchCursor {
+	return &BatchCursor{
+		clientSession: clientSession,
+		clock:         clock,
+		namespace:     ns,
+		id:            id,
+		server:        server,
+		opts:          opts,
+		currentBatch:  new(bsoncore.DocumentSequence),
+	}
+}
+
 // NewLegacyBatchCursor creates a new BatchCursor for server versions 3.0 and below from the
 // provided parameters.
 //
@@ -174,9 +200,116 @@ func (bc *BatchCursor) Next(ctx context.Context) bool {
 // DocumentSequence is only valid until the next call to Next or Close.
 func (bc *BatchCursor) Batch() *bsoncore.DocumentSequence { return bc.currentBatch }
 
+// PinConnection pins the connection the cursor was created on, so its getMore and killCursors
+// commands are sent on that connection. This is only needed for connections to a load balancer and
+// has no effect for other connections or if the cursor is already exhausted. The connection is
+// unpinned when the cursor is exhausted or closed.
+func (bc *BatchCursor) PinConnection(conn connection.Connection) {
+	if bc.id != 0 {
+		bc.pinnedConn = topology.PinConnection(conn)
+	}
+}
+
+func (bc *BatchCursor) connection(ctx context.Context) (connection.Connection, error) {
+	if bc.pinnedConn != nil {
+		return topology.PinConnection(bc.pinnedConn), nil
+	}
+	return bc.server.Connection(ctx)
+}
+
+func (bc *BatchCursor) unpinConnection() {
+	if bc.pinnedConn != nil {
+		_ = bc.pinnedConn.Close()
+		bc.pinnedConn = nil
+	}
+}
+
+// PostBatchResumeToken returns the postBatchResumeToken of the most recent batch that had one, or
+// nil if none did. Servers return it for change streams and for reads of the oplog and of clustered
+// collections that request it with $_requestResumeToken.
+func (bc *BatchCursor) PostBatchResumeToken() bsoncore.Document { return bc.postBatchResumeToken }
+
+// OperationTime returns the operationTime of the response to the most recent batch that had one, or
+// nil if none did.
+func (bc *BatchCursor) OperationTime() *primitive.Timestamp { return bc.operationTime }
+
+// SetTargetBatchBytes makes the getMore commands of bc request batches of about n bytes. The batch
+// size of each getMore is derived from a moving average of the size of the documents returned so
+// far, so that a few unusually small or large documents do not make the batch size swing. It
+// replaces any batchSize the getMore commands were created with.
+func (bc *BatchCursor) SetTargetBatchBytes(n int32) {
+	bc.targetBatchBytes = n
+}
+
+// observeBatch updates the average document size with the documents of the current batch.
+func (bc *BatchCursor) observeBatch() {
+	if bc.targetBatchBytes <= 0 || bc.currentBatch == nil {
+		return
+	}
+	count := bc.currentBatch.DocumentCount()
+	if count == 0 {
+		return
+	}
+	size := float64(len(bc.currentBatch.Data)) / float64(count)
+	if bc.avgDocumentSize == 0 {
+		bc.avgDocumentSize = size
+		return
+	}
+	bc.avgDocumentSize += (size - bc.avgDocumentSize) / 4
+}
+
+// getMoreOpts returns the options of the next getMore command, with the batch size derived from the
+// target batch size in bytes if one is set.
+func (bc *BatchCursor) getMoreOpts() []bsonx.Elem {
+	if bc.targetBatchBytes <= 0 || bc.avgDocumentSize == 0 {
+		return bc.opts
+	}
+	batchSize := int32(float64(bc.targetBatchBytes) / bc.avgDocumentSize)
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	opts := make([]bsonx.Elem, 0, len(bc.opts)+1)
+	for _, opt := range bc.opts {
+		if opt.Key != "batchSize" {
+			opts = append(opts, opt)
+		}
+	}
+	return append(opts, bsonx.Elem{"batchSize", bsonx.Int32(batchSize)})
+}
+
 // Server returns a pointer to the cursor's server.
 func (bc *BatchCursor) Server() *topology.Server { return bc.server }
 
+// Namespace returns the namespace of the cursor.
+func (bc *BatchCursor) Namespace() command.Namespace { return bc.namespace }
+
+// Detach stops bc from using its server cursor, without killing it, so that it can be continued by a
+// BatchCursor created with NewBatchCursorFromID, usually in another process. It returns the ID of
+// the implicit session the cursor was created in, if any, which must be used to continue it. The
+// current batch is kept, but Next does not run getMore commands and Close does nothing once bc is
+// detached. Cursors created in an explicit session or pinned to a connection cannot be detached.
+func (bc *BatchCursor) Detach() (bsonx.Doc, error) {
+	if bc.id == 0 || bc.server == nil {
+		return nil, errors.New("cursor is exhausted or closed")
+	}
+	if bc.pinnedConn != nil {
+		return nil, errors.New("cursor pinned to a connection cannot be detached")
+	}
+
+	var sessionID bsonx.Doc
+	if bc.clientSession != nil {
+		if bc.clientSession.SessionType != session.Implicit {
+			return nil, errors.New("cursor created in an explicit session cannot be detached")
+		}
+		sessionID = bc.clientSession.SessionID
+		bc.clientSession.Detach()
+	}
+	bc.id = 0
+	bc.server = nil
+	return sessionID, nil
+}
+
 // Err returns the latest error encountered.
 func (bc *BatchCursor) Err() error { return bc.err }
 
@@ -195,7 +328,8 @@ func (bc *BatchCursor) Close(ctx context.Context) error {
 	}
 
 	defer bc.closeImplicitSession()
-	conn, err := bc.server.Connection(ctx)
+	defer bc.unpinConnection()
+	conn, err := bc.connection(ctx)
 	if err != nil {
 		return err
 	}
@@ -229,12 +363,13 @@ func (bc *BatchCursor) clearBatch() {
 }
 
 func (bc *BatchCursor) getMore(ctx context.Context) {
+	bc.observeBatch()
 	bc.clearBatch()
 	if bc.id == 0 {
 		return
 	}
 
-	conn, err := bc.server.Connection(ctx)
+	conn, err := bc.connection(ctx)
 	if err != nil {
 		bc.err = err
 		return
@@ -244,7 +379,7 @@ func (bc *BatchCursor) getMore(ctx context.Context) {
 		Clock:   bc.clock,
 		ID:      bc.id,
 		NS:      bc.namespace,
-		Opts:    bc.opts,
+		Opts:    bc.getMoreOpts(),
 		Session: bc.clientSession,
 	}).RoundTrip(ctx, bc.server.SelectedDescription(), conn)
 	if err != nil {
@@ -273,6 +408,7 @@ func (bc *BatchCursor) getMore(ctx context.Context) {
 
 	// if this is the last getMore, close the session
 	if bc.id == 0 {
+		bc.unpinConnection()
 		bc.closeImplicitSession()
 	}
 
@@ -290,10 +426,22 @@ func (bc *BatchCursor) getMore(ctx context.Context) {
 	bc.currentBatch.Style = bsoncore.ArrayStyle
 	bc.currentBatch.Data = arr
 	bc.currentBatch.ResetIterator()
+	bc.updateBatchMetadata(bsoncore.Document(response))
 
 	return
 }
 
+// updateBatchMetadata records the postBatchResumeToken and operationTime of response, the response
+// to the command that returned the current batch, if it has them.
+func (bc *BatchCursor) updateBatchMetadata(response bsoncore.Document) {
+	if token, ok := response.Lookup("cursor", "postBatchResumeToken").DocumentOK(); ok {
+		bc.postBatchResumeToken = append(bsoncore.Document(nil), token...)
+	}
+	if t, i, ok := response.Lookup("operationTime").TimestampOK(); ok {
+		bc.operationTime = &primitive.Timestamp{T: t, I: i}
+	}
+}
+
 func (bc *BatchCursor) legacy() bool {
 	return bc.server.Description().WireVersion == nil || bc.server.Description().WireVersion.Max < 4
 }
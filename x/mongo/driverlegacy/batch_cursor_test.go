@@ -1,7 +1,19 @@
 package driverlegacy
 
 import (
+	"context"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
+	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/command"
 )
 
 func TestBatchCursor(t *testing.T) {
@@ -24,4 +36,109 @@ func TestBatchCursor(t *testing.T) {
 			t.Errorf("Expect next to return false, but returned true")
 		}
 	})
+	t.Run("batch metadata", func(t *testing.T) {
+		token := bsonx.Doc{{"_data", bsonx.String("82")}}
+		result, err := bsonx.Doc{
+			{"cursor", bsonx.Document(bsonx.Doc{
+				{"firstBatch", bsonx.Array(bsonx.Arr{})},
+				{"id", bsonx.Int64(0)},
+				{"ns", bsonx.String("db.coll")},
+				{"postBatchResumeToken", bsonx.Document(token)},
+			})},
+			{"operationTime", bsonx.Timestamp(10, 2)},
+			{"ok", bsonx.Int32(1)},
+		}.MarshalBSON()
+		require.NoError(t, err)
+
+		bc, err := NewBatchCursor(bsoncore.Document(result), nil, nil, nil)
+		require.NoError(t, err)
+		want, err := token.MarshalBSON()
+		require.NoError(t, err)
+		require.Equal(t, bsoncore.Document(want), bc.PostBatchResumeToken())
+		require.Equal(t, &primitive.Timestamp{T: 10, I: 2}, bc.OperationTime())
+	})
+	t.Run("target batch bytes", func(t *testing.T) {
+		batch := func(docs int, size int) *bsoncore.DocumentSequence {
+			arr := make(bsonx.Arr, 0, docs)
+			for i := 0; i < docs; i++ {
+				// A document with a string of n bytes takes n+13 bytes, and its array element 3 more.
+				arr = append(arr, bsonx.Document(bsonx.Doc{{"a", bsonx.String(strings.Repeat("x", size-16))}}))
+			}
+			_, data, err := bsonx.Array(arr).MarshalAppendBSONValue(nil)
+			require.NoError(t, err)
+			return &bsoncore.DocumentSequence{Style: bsoncore.ArrayStyle, Data: data}
+		}
+		batchSize := func(bc *BatchCursor) int32 {
+			for _, opt := range bc.getMoreOpts() {
+				if opt.Key == "batchSize" {
+					return opt.Value.Int32()
+				}
+			}
+			return 0
+		}
+
+		bc := &BatchCursor{opts: []bsonx.Elem{{"batchSize", bsonx.Int32(5)}, {"comment", bsonx.String("c")}}}
+		bc.currentBatch = batch(10, 100)
+		bc.observeBatch()
+		require.Equal(t, int32(5), batchSize(bc))
+
+		bc.SetTargetBatchBytes(1000)
+		bc.observeBatch()
+		opts := bc.getMoreOpts()
+		require.Len(t, opts, 2)
+		require.Equal(t, "comment", opts[0].Key)
+		require.InDelta(t, 10, batchSize(bc), 1)
+
+		// Larger documents lower the batch size gradually.
+		bc.currentBatch = batch(2, 500)
+		bc.observeBatch()
+		require.True(t, batchSize(bc) < 10 && batchSize(bc) > 2, "batch size %d", batchSize(bc))
+
+		// Documents larger than the target still return one document per batch.
+		bc.SetTargetBatchBytes(1)
+		require.Equal(t, int32(1), batchSize(bc))
+	})
+}
+
+func TestBatchCursorDetach(t *testing.T) {
+	server, err := topology.NewServer(address.Address("localhost:27017"), nil)
+	require.NoError(t, err)
+	ns := command.Namespace{DB: "db", Collection: "coll"}
+	id, err := uuid.New()
+	require.NoError(t, err)
+
+	t.Run("implicit session", func(t *testing.T) {
+		pool := session.NewPool(nil)
+		sess, err := session.NewClientSession(pool, id, session.Implicit)
+		require.NoError(t, err)
+		bc := NewBatchCursorFromID(ns, 42, sess, nil, server)
+
+		sessionID, err := bc.Detach()
+		require.NoError(t, err)
+		require.Equal(t, sess.SessionID, sessionID)
+		require.Equal(t, 0, pool.CheckedOut())
+		require.Empty(t, pool.IDSlice(), "detached session was returned to the pool")
+
+		require.False(t, bc.Next(context.Background()))
+		require.NoError(t, bc.Close(context.Background()))
+		_, err = bc.Detach()
+		require.Error(t, err)
+	})
+	t.Run("explicit session", func(t *testing.T) {
+		sess, err := session.NewClientSession(session.NewPool(nil), id, session.Explicit)
+		require.NoError(t, err)
+		_, err = NewBatchCursorFromID(ns, 42, sess, nil, server).Detach()
+		require.Error(t, err)
+	})
+	t.Run("continued session", func(t *testing.T) {
+		sessionID := bsonx.Doc{{"id", bsonx.Binary(session.UUIDSubtype, id[:])}}
+		sess := session.NewClientSessionFromID(id, sessionID)
+		bc := NewBatchCursorFromID(ns, 42, sess, nil, server)
+		require.Equal(t, int64(42), bc.ID())
+		require.Equal(t, ns, bc.Namespace())
+
+		detached, err := bc.Detach()
+		require.NoError(t, err)
+		require.Equal(t, sessionID, detached)
+	})
 }
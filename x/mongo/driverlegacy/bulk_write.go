@@ -31,6 +31,7 @@ type BulkWriteError struct {
 type BulkWriteException struct {
 	WriteConcernError *result.WriteConcernError
 	WriteErrors       []BulkWriteError
+	Labels            []string
 }
 
 func (BulkWriteException) Error() string {
@@ -40,6 +41,7 @@ func (BulkWriteException) Error() string {
 type bulkWriteBatch struct {
 	models   []WriteModel
 	canRetry bool
+	indexes  []int // the index of each model in the models passed to BulkWrite
 }
 
 // BulkWrite handles the full dispatch cycle for a bulk write operation.
@@ -94,7 +96,6 @@ func BulkWrite(
 	}
 
 	var lastErr error
-	var opIndex int64 // the operation index for the upsertedIDs map
 	continueOnError := !ordered
 	for _, batch := range batches {
 		if len(batch.models) == 0 {
@@ -104,12 +105,15 @@ func BulkWrite(
 		batchRes, batchErr, err := runBatch(ctx, ns, topo, selector, ss, sess, clock, writeConcern, retryWrite,
 			bwOpts.BypassDocumentValidation, continueOnError, batch, registry)
 
-		mergeResults(&bwRes, batchRes, opIndex)
+		mergeResults(&bwRes, batchRes, batch.indexes)
 		bwErr.WriteConcernError = batchErr.WriteConcernError
 		for i := range batchErr.WriteErrors {
-			batchErr.WriteErrors[i].Index = batchErr.WriteErrors[i].Index + int(opIndex)
+			if idx := batchErr.WriteErrors[i].Index; idx >= 0 && idx < len(batch.indexes) {
+				batchErr.WriteErrors[i].Index = batch.indexes[idx]
+			}
 		}
 		bwErr.WriteErrors = append(bwErr.WriteErrors, batchErr.WriteErrors...)
+		bwErr.Labels = mergeLabels(bwErr.Labels, batchErr.Labels)
 
 		if !continueOnError && (err != nil || len(batchErr.WriteErrors) > 0 || batchErr.WriteConcernError != nil) {
 			if err != nil {
@@ -122,8 +126,6 @@ func BulkWrite(
 		if err != nil {
 			lastErr = err
 		}
-
-		opIndex += int64(len(batch.models))
 	}
 
 	bwRes.MatchedCount -= bwRes.UpsertedCount
@@ -168,6 +170,7 @@ func runBatch(
 		batchRes.InsertedCount = int64(res.N)
 		writeErrors = res.WriteErrors
 		batchErr.WriteConcernError = res.WriteConcernError
+		batchErr.Labels = res.ErrorLabels
 	case DeleteOneModel, DeleteManyModel:
 		res, err := runDelete(ctx, ns, topo, selector, ss, sess, clock, wc, retryWrite, batch, continueOnError, registry)
 		if err != nil {
@@ -177,6 +180,7 @@ func runBatch(
 		batchRes.DeletedCount = int64(res.N)
 		writeErrors = res.WriteErrors
 		batchErr.WriteConcernError = res.WriteConcernError
+		batchErr.Labels = res.ErrorLabels
 	case ReplaceOneModel, UpdateOneModel, UpdateManyModel:
 		res, err := runUpdate(ctx, ns, topo, selector, ss, sess, clock, wc, retryWrite, batch, bypassDocValidation,
 			continueOnError, registry)
@@ -189,6 +193,7 @@ func runBatch(
 		batchRes.UpsertedCount = int64(len(res.Upserted))
 		writeErrors = res.WriteErrors
 		batchErr.WriteConcernError = res.WriteConcernError
+		batchErr.Labels = res.ErrorLabels
 		for _, upsert := range res.Upserted {
 			batchRes.UpsertedIDs[upsert.Index] = upsert.ID
 		}
@@ -196,15 +201,36 @@ func runBatch(
 
 	batchErr.WriteErrors = make([]BulkWriteError, 0, len(writeErrors))
 	for _, we := range writeErrors {
+		var model WriteModel
+		if we.Index >= 0 && we.Index < len(batch.models) {
+			model = batch.models[we.Index]
+		}
 		batchErr.WriteErrors = append(batchErr.WriteErrors, BulkWriteError{
 			WriteError: we,
-			Model:      batch.models[0],
+			Model:      model,
 		})
 	}
 
 	return batchRes, batchErr, nil
 }
 
+// mergeLabels appends the error labels in add that are not already in labels.
+func mergeLabels(labels, add []string) []string {
+	for _, label := range add {
+		var found bool
+		for _, l := range labels {
+			if l == label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
 func runInsert(
 	ctx context.Context,
 	ns command.Namespace,
@@ -258,13 +284,16 @@ func runInsert(
 	cmd.Session.IncrementTxnNumber()
 
 	res, origErr := insert(ctx, &cmd, ss, nil)
-	if shouldRetry(origErr, res.WriteConcernError) {
+	if shouldRetry(origErr, res.WriteConcernError, cmd.WriteConcern, topo.RetryWriteConcernTimeouts()) {
 		newServer, err := topo.SelectServer(ctx, selector)
 		if err != nil || !retrySupported(topo, ss.Description(), cmd.Session, cmd.WriteConcern) {
 			return res, origErr
 		}
 
-		return insert(ctx, &cmd, newServer, origErr)
+		code := retryErrorCode(origErr, res.WriteConcernError)
+		res, err = insert(ctx, &cmd, newServer, origErr)
+		recordWriteRetry(topo, code, err, res.WriteConcernError)
+		return res, err
 	}
 
 	return res, origErr
@@ -326,13 +355,16 @@ func runDelete(
 	cmd.Session.IncrementTxnNumber()
 
 	res, origErr := delete(ctx, &cmd, ss, nil)
-	if shouldRetry(origErr, res.WriteConcernError) {
+	if shouldRetry(origErr, res.WriteConcernError, cmd.WriteConcern, topo.RetryWriteConcernTimeouts()) {
 		newServer, err := topo.SelectServer(ctx, selector)
 		if err != nil || !retrySupported(topo, ss.Description(), cmd.Session, cmd.WriteConcern) {
 			return res, origErr
 		}
 
-		return delete(ctx, &cmd, newServer, origErr)
+		code := retryErrorCode(origErr, res.WriteConcernError)
+		res, err = delete(ctx, &cmd, newServer, origErr)
+		recordWriteRetry(topo, code, err, res.WriteConcernError)
+		return res, err
 	}
 
 	return res, origErr
@@ -404,13 +436,16 @@ func runUpdate(
 	cmd.Session.IncrementTxnNumber()
 
 	res, origErr := update(ctx, &cmd, ss, nil)
-	if shouldRetry(origErr, res.WriteConcernError) {
+	if shouldRetry(origErr, res.WriteConcernError, cmd.WriteConcern, topo.RetryWriteConcernTimeouts()) {
 		newServer, err := topo.SelectServer(ctx, selector)
 		if err != nil || !retrySupported(topo, ss.Description(), cmd.Session, cmd.WriteConcern) {
 			return res, origErr
 		}
 
-		return update(ctx, &cmd, newServer, origErr)
+		code := retryErrorCode(origErr, res.WriteConcernError)
+		res, err = update(ctx, &cmd, newServer, origErr)
+		recordWriteRetry(topo, code, err, res.WriteConcernError)
+		return res, err
 	}
 
 	return res, origErr
@@ -468,7 +503,7 @@ func createBatches(models []WriteModel, ordered bool) []bulkWriteBatch {
 	updateInd := -1
 	deleteInd := -1
 
-	for _, model := range models {
+	for idx, model := range models {
 		switch converted := model.(type) {
 		case InsertOneModel:
 			if insertInd == -1 {
@@ -478,6 +513,7 @@ func createBatches(models []WriteModel, ordered bool) []bulkWriteBatch {
 			}
 
 			batches[insertInd].models = append(batches[insertInd].models, model)
+			batches[insertInd].indexes = append(batches[insertInd].indexes, idx)
 		case DeleteOneModel, DeleteManyModel:
 			if deleteInd == -1 {
 				deleteInd = numBatches
@@ -485,6 +521,7 @@ func createBatches(models []WriteModel, ordered bool) []bulkWriteBatch {
 			}
 
 			batches[deleteInd].models = append(batches[deleteInd].models, model)
+			batches[deleteInd].indexes = append(batches[deleteInd].indexes, idx)
 			if _, ok := converted.(DeleteManyModel); ok {
 				batches[deleteInd].canRetry = false
 			}
@@ -495,6 +532,7 @@ func createBatches(models []WriteModel, ordered bool) []bulkWriteBatch {
 			}
 
 			batches[updateInd].models = append(batches[updateInd].models, model)
+			batches[updateInd].indexes = append(batches[updateInd].indexes, idx)
 			if _, ok := converted.(UpdateManyModel); ok {
 				batches[updateInd].canRetry = false
 			}
@@ -509,7 +547,7 @@ func createOrderedBatches(models []WriteModel) []bulkWriteBatch {
 	var prevKind command.WriteCommandKind = -1
 	i := -1 // batch index
 
-	for _, model := range models {
+	for idx, model := range models {
 		var createNewBatch bool
 		var canRetry bool
 		var newKind command.WriteCommandKind
@@ -539,10 +577,12 @@ func createOrderedBatches(models []WriteModel) []bulkWriteBatch {
 			batches = append(batches, bulkWriteBatch{
 				models:   []WriteModel{model},
 				canRetry: canRetry,
+				indexes:  []int{idx},
 			})
 			i++
 		} else {
 			batches[i].models = append(batches[i].models, model)
+			batches[i].indexes = append(batches[i].indexes, idx)
 			if !canRetry {
 				batches[i].canRetry = false // don't make it true if it was already false
 			}
@@ -554,9 +594,14 @@ func createOrderedBatches(models []WriteModel) []bulkWriteBatch {
 	return batches
 }
 
-func shouldRetry(cmdErr error, wcErr *result.WriteConcernError) bool {
+func shouldRetry(
+	cmdErr error,
+	wcErr *result.WriteConcernError,
+	wc *writeconcern.WriteConcern,
+	retryWCTimeouts bool,
+) bool {
 	if cerr, ok := cmdErr.(command.Error); ok && cerr.Retryable() ||
-		wcErr != nil && command.IsWriteConcernErrorRetryable(wcErr) {
+		writeConcernErrorRetryable(wcErr, wc, retryWCTimeouts) {
 		return true
 	}
 
@@ -651,7 +696,9 @@ func createDeleteDoc(
 	return doc, nil
 }
 
-func mergeResults(aggResult *result.BulkWrite, newResult result.BulkWrite, opIndex int64) {
+// mergeResults adds newResult, the result of a batch whose models had the given indexes in the
+// models passed to BulkWrite, to aggResult.
+func mergeResults(aggResult *result.BulkWrite, newResult result.BulkWrite, indexes []int) {
 	aggResult.InsertedCount += newResult.InsertedCount
 	aggResult.MatchedCount += newResult.MatchedCount
 	aggResult.ModifiedCount += newResult.ModifiedCount
@@ -659,6 +706,8 @@ func mergeResults(aggResult *result.BulkWrite, newResult result.BulkWrite, opInd
 	aggResult.UpsertedCount += newResult.UpsertedCount
 
 	for index, upsertID := range newResult.UpsertedIDs {
-		aggResult.UpsertedIDs[index+opIndex] = upsertID
+		if index >= 0 && index < int64(len(indexes)) {
+			aggResult.UpsertedIDs[int64(indexes[index])] = upsertID
+		}
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/x/network/result"
 )
 
 func TestBulkWrite(t *testing.T) {
@@ -27,18 +28,18 @@ func TestBulkWrite(t *testing.T) {
 		}
 
 		expectedOrdered := []bulkWriteBatch{
-			{[]WriteModel{InsertOneModel{}, InsertOneModel{}}, true},
-			{[]WriteModel{UpdateOneModel{}, UpdateManyModel{}}, false},
-			{[]WriteModel{DeleteOneModel{}, DeleteManyModel{}}, false},
-			{[]WriteModel{InsertOneModel{}}, true},
-			{[]WriteModel{UpdateManyModel{}}, false},
-			{[]WriteModel{DeleteOneModel{}}, true},
+			{[]WriteModel{InsertOneModel{}, InsertOneModel{}}, true, []int{0, 1}},
+			{[]WriteModel{UpdateOneModel{}, UpdateManyModel{}}, false, []int{2, 3}},
+			{[]WriteModel{DeleteOneModel{}, DeleteManyModel{}}, false, []int{4, 5}},
+			{[]WriteModel{InsertOneModel{}}, true, []int{6}},
+			{[]WriteModel{UpdateManyModel{}}, false, []int{7}},
+			{[]WriteModel{DeleteOneModel{}}, true, []int{8}},
 		}
 
 		expectedUnordered := []bulkWriteBatch{
-			{[]WriteModel{InsertOneModel{}, InsertOneModel{}, InsertOneModel{}}, true},
-			{[]WriteModel{UpdateOneModel{}, UpdateManyModel{}, UpdateManyModel{}}, false},
-			{[]WriteModel{DeleteOneModel{}, DeleteManyModel{}, DeleteOneModel{}}, false},
+			{[]WriteModel{InsertOneModel{}, InsertOneModel{}, InsertOneModel{}}, true, []int{0, 1, 6}},
+			{[]WriteModel{UpdateOneModel{}, UpdateManyModel{}, UpdateManyModel{}}, false, []int{2, 3, 7}},
+			{[]WriteModel{DeleteOneModel{}, DeleteManyModel{}, DeleteOneModel{}}, false, []int{4, 5, 8}},
 		}
 
 		testCases := []struct {
@@ -63,4 +64,18 @@ func TestBulkWrite(t *testing.T) {
 			})
 		}
 	})
+	t.Run("TestMergeResults", func(t *testing.T) {
+		agg := result.BulkWrite{UpsertedIDs: make(map[int64]interface{})}
+		mergeResults(&agg, result.BulkWrite{
+			MatchedCount: 1,
+			UpsertedIDs:  map[int64]interface{}{0: "a", 2: "b"},
+		}, []int{2, 3, 7})
+		mergeResults(&agg, result.BulkWrite{
+			MatchedCount: 2,
+			UpsertedIDs:  map[int64]interface{}{1: "c"},
+		}, []int{0, 5})
+
+		require.Equal(t, int64(3), agg.MatchedCount)
+		require.Equal(t, map[int64]interface{}{2: "a", 7: "b", 5: "c"}, agg.UpsertedIDs)
+	})
 }
@@ -39,6 +39,7 @@ func CommitTransaction(
 		// Retry if appropriate
 		if cerr.Retryable() {
 			res, err = commitTransaction(ctx, cmd, topo, selector, cerr)
+			recordWriteRetry(topo, cerr.Code, err, res.WriteConcernError)
 			if cerr2, ok := err.(command.Error); ok && err != nil {
 				// Retry failures also get label
 				cerr2.Labels = append(cerr2.Labels, command.UnknownTransactionCommitResult)
@@ -78,7 +79,7 @@ func commitTransaction(
 		return result.TransactionResult{}, oldErr
 	}
 
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		if oldErr != nil {
 			return result.TransactionResult{}, oldErr
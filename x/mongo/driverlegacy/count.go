@@ -18,6 +18,7 @@ import (
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
 	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/description"
 )
 
@@ -31,6 +32,7 @@ func Count(
 	clientID uuid.UUID,
 	pool *session.Pool,
 	registry *bsoncodec.Registry,
+	retryRead bool,
 	opts ...*options.CountOptions,
 ) (int64, error) {
 
@@ -40,7 +42,7 @@ func Count(
 	}
 
 	desc := ss.Description()
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		return 0, err
 	}
@@ -93,5 +95,14 @@ func Count(
 		cmd.Opts = append(cmd.Opts, hintElem)
 	}
 
-	return cmd.RoundTrip(ctx, desc, conn)
+	res, err := cmd.RoundTrip(ctx, desc, conn)
+	if err != nil && retryRead && shouldRetryRead(topo, desc, cmd.Session, err) {
+		_, err = retryReadOnce(ctx, topo, selector, cmd.Session, ss, err, func(desc description.SelectedServer, conn connection.Connection) error {
+			var rtErr error
+			res, rtErr = cmd.RoundTrip(ctx, desc, conn)
+			return rtErr
+		})
+	}
+
+	return res, err
 }
@@ -0,0 +1,501 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driverlegacy
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/mongocrypt"
+)
+
+const defaultKmsPort = "443"
+
+// CollectionInfoFn returns the collection information of the collection matched by filter in db,
+// or nil if no collection matches.
+type CollectionInfoFn func(ctx context.Context, db string, filter bsoncore.Document) (bsoncore.Document, error)
+
+// KeyRetrieverFn returns the data keys matched by filter from the key vault collection.
+type KeyRetrieverFn func(ctx context.Context, filter bsoncore.Document) ([]bsoncore.Document, error)
+
+// MarkCommandFn sends cmd, a command run against db, to mongocryptd and returns its reply, which
+// marks the fields of the command that must be encrypted.
+type MarkCommandFn func(ctx context.Context, db string, cmd bsoncore.Document) (bsoncore.Document, error)
+
+// CryptOptions specifies options to configure a Crypt instance. KeyFn is always required.
+// CollInfoFn and MarkFn are only required for automatic encryption. The results of CollInfoFn are
+// cached for CollInfoCacheTTL, or not cached if it is not positive.
+type CryptOptions struct {
+	MongoCrypt           *mongocrypt.MongoCrypt
+	CollInfoFn           CollectionInfoFn
+	CollInfoCacheTTL     time.Duration
+	KeyFn                KeyRetrieverFn
+	MarkFn               MarkCommandFn
+	BypassAutoEncryption bool
+}
+
+// Crypt performs client-side field level encryption by running libmongocrypt contexts to
+// completion, fetching collection information, markings, and keys from the database and
+// decrypting keys with the KMS as needed.
+type Crypt struct {
+	mongoCrypt           *mongocrypt.MongoCrypt
+	collInfoFn           CollectionInfoFn
+	keyFn                KeyRetrieverFn
+	markFn               MarkCommandFn
+	bypassAutoEncryption bool
+
+	collInfoTTL   time.Duration
+	collInfoMu    sync.Mutex
+	collInfoCache map[string]collInfoEntry // keyed by database and filter
+}
+
+// collInfoEntry is a cached result of the CollectionInfoFn. A nil document records that no
+// collection matched the filter.
+type collInfoEntry struct {
+	doc     bsoncore.Document
+	expires time.Time
+}
+
+// NewCrypt creates a new Crypt instance configured with the given options.
+func NewCrypt(opts *CryptOptions) *Crypt {
+	return &Crypt{
+		mongoCrypt:           opts.MongoCrypt,
+		collInfoFn:           opts.CollInfoFn,
+		keyFn:                opts.KeyFn,
+		markFn:               opts.MarkFn,
+		bypassAutoEncryption: opts.BypassAutoEncryption,
+		collInfoTTL:          opts.CollInfoCacheTTL,
+		collInfoCache:        make(map[string]collInfoEntry),
+	}
+}
+
+// Encrypt encrypts the given command, which is run against db. Commands that do not need to be
+// encrypted are returned unchanged.
+func (c *Crypt) Encrypt(ctx context.Context, db string, cmd bsoncore.Document) (bsoncore.Document, error) {
+	if c.bypassAutoEncryption {
+		return cmd, nil
+	}
+	if changesCollections(cmd) {
+		c.clearCollInfo()
+	}
+
+	cryptCtx, err := c.mongoCrypt.CreateEncryptionContext(db, cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer cryptCtx.Close()
+
+	encrypted, err := c.executeStateMachine(ctx, cryptCtx, db)
+	if err != nil || encrypted == nil {
+		return cmd, err
+	}
+	return encrypted, nil
+}
+
+// Decrypt decrypts the given command response.
+func (c *Crypt) Decrypt(ctx context.Context, cmdResponse bsoncore.Document) (bsoncore.Document, error) {
+	if isSchemaChangeError(cmdResponse) {
+		c.clearCollInfo()
+	}
+
+	cryptCtx, err := c.mongoCrypt.CreateDecryptionContext(cmdResponse)
+	if err != nil {
+		return nil, err
+	}
+	defer cryptCtx.Close()
+
+	decrypted, err := c.executeStateMachine(ctx, cryptCtx, "")
+	if err != nil || decrypted == nil {
+		return cmdResponse, err
+	}
+	return decrypted, nil
+}
+
+// CreateDataKey creates a data key using the given KMS provider and options. The returned document
+// must be inserted into the key vault collection.
+func (c *Crypt) CreateDataKey(ctx context.Context, kmsProvider string, opts *mongocrypt.DataKeyOptions) (bsoncore.Document, error) {
+	cryptCtx, err := c.mongoCrypt.CreateDataKeyContext(kmsProvider, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cryptCtx.Close()
+
+	return c.executeStateMachine(ctx, cryptCtx, "")
+}
+
+// EncryptExplicit encrypts the given value with the given options and returns the subtype and data
+// of the resulting BSON binary value.
+func (c *Crypt) EncryptExplicit(ctx context.Context, val bsoncore.Value, opts *mongocrypt.ExplicitEncryptionOptions) (byte, []byte, error) {
+	doc := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendValueElement(nil, "v", val))
+
+	cryptCtx, err := c.mongoCrypt.CreateExplicitEncryptionContext(doc, opts)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer cryptCtx.Close()
+
+	res, err := c.executeStateMachine(ctx, cryptCtx, "")
+	if err != nil {
+		return 0, nil, err
+	}
+
+	subtype, data, ok := res.Lookup("v").BinaryOK()
+	if !ok {
+		return 0, nil, errors.New("explicit encryption did not return a binary value")
+	}
+	return subtype, data, nil
+}
+
+// EncryptExplicitExpression encrypts the bounds of expr, a range query expression such as
+// {$and: [{field: {$gte: min}}, {field: {$lt: max}}]}, with the given options and returns the
+// expression with the bounds replaced by their encrypted payloads.
+func (c *Crypt) EncryptExplicitExpression(ctx context.Context, expr bsoncore.Document, opts *mongocrypt.ExplicitEncryptionOptions) (bsoncore.Document, error) {
+	doc := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendDocumentElement(nil, "v", expr))
+
+	cryptCtx, err := c.mongoCrypt.CreateExplicitEncryptionExpressionContext(doc, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cryptCtx.Close()
+
+	res, err := c.executeStateMachine(ctx, cryptCtx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, ok := res.Lookup("v").DocumentOK()
+	if !ok {
+		return nil, errors.New("explicit encryption did not return a document")
+	}
+	return encrypted, nil
+}
+
+// RewrapDataKey decrypts the data keys matched by filter and encrypts them again with the master
+// key given in opts. It returns the rewrapped keys, which must be written back to the key vault
+// collection.
+func (c *Crypt) RewrapDataKey(ctx context.Context, filter bsoncore.Document, opts *mongocrypt.RewrapManyDataKeyOptions) ([]bsoncore.Document, error) {
+	cryptCtx, err := c.mongoCrypt.CreateRewrapManyDataKeyContext(filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cryptCtx.Close()
+
+	res, err := c.executeStateMachine(ctx, cryptCtx, "")
+	if err != nil || res == nil {
+		return nil, err
+	}
+
+	arr, ok := res.Lookup("v").ArrayOK()
+	if !ok {
+		return nil, errors.New("rewrapping data keys did not return an array")
+	}
+	vals, err := arr.Values()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]bsoncore.Document, 0, len(vals))
+	for _, val := range vals {
+		key, ok := val.DocumentOK()
+		if !ok {
+			return nil, errors.New("rewrapped data key is not a document")
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// DecryptExplicit decrypts the BSON binary value with the given subtype and data.
+func (c *Crypt) DecryptExplicit(ctx context.Context, subtype byte, data []byte) (bsoncore.Value, error) {
+	v := bsoncore.Value{Type: bsontype.Binary, Data: bsoncore.AppendBinary(nil, subtype, data)}
+	doc := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendValueElement(nil, "v", v))
+
+	cryptCtx, err := c.mongoCrypt.CreateExplicitDecryptionContext(doc)
+	if err != nil {
+		return bsoncore.Value{}, err
+	}
+	defer cryptCtx.Close()
+
+	res, err := c.executeStateMachine(ctx, cryptCtx, "")
+	if err != nil {
+		return bsoncore.Value{}, err
+	}
+	return res.Lookup("v"), nil
+}
+
+// Close cleans up any resources associated with the Crypt instance.
+func (c *Crypt) Close() {
+	c.mongoCrypt.Close()
+}
+
+// cryptContext is the subset of *mongocrypt.Context used to run a context to completion.
+type cryptContext interface {
+	State() mongocrypt.State
+	NextOperation() (bsoncore.Document, error)
+	AddOperationResult(bsoncore.Document) error
+	CompleteOperation() error
+	NextKmsContext() *mongocrypt.KmsContext
+	FinishKmsContexts() error
+	Finish() (bsoncore.Document, error)
+}
+
+// executeStateMachine runs cryptCtx until it is ready and returns its result. A nil document is
+// returned if the context had nothing to do.
+func (c *Crypt) executeStateMachine(ctx context.Context, cryptCtx cryptContext, db string) (bsoncore.Document, error) {
+	var err error
+	for {
+		switch state := cryptCtx.State(); state {
+		case mongocrypt.NeedMongoCollInfo:
+			err = c.collectionInfo(ctx, cryptCtx, db)
+		case mongocrypt.NeedMongoMarkings:
+			err = c.markCommand(ctx, cryptCtx, db)
+		case mongocrypt.NeedMongoKeys:
+			err = c.retrieveKeys(ctx, cryptCtx)
+		case mongocrypt.NeedKms:
+			err = c.decryptKeys(ctx, cryptCtx)
+		case mongocrypt.Ready:
+			return cryptCtx.Finish()
+		case mongocrypt.Done:
+			return nil, nil
+		default:
+			// The error of a context in the error state is returned by any operation on it.
+			if _, err = cryptCtx.Finish(); err == nil {
+				err = fmt.Errorf("invalid Crypt state: %v", state)
+			}
+			return nil, err
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Crypt) collectionInfo(ctx context.Context, cryptCtx cryptContext, db string) error {
+	if c.collInfoFn == nil {
+		return errors.New("collection information is not available for explicit encryption")
+	}
+	filter, err := cryptCtx.NextOperation()
+	if err != nil {
+		return err
+	}
+
+	collInfo, err := c.cachedCollInfo(ctx, db, filter)
+	if err != nil {
+		return err
+	}
+	if collInfo != nil {
+		if err = cryptCtx.AddOperationResult(collInfo); err != nil {
+			return err
+		}
+	}
+
+	return cryptCtx.CompleteOperation()
+}
+
+// cachedCollInfo returns the collection information matched by filter in db from the cache, or
+// fetches it with the CollectionInfoFn and caches it if it is not cached or has expired.
+func (c *Crypt) cachedCollInfo(ctx context.Context, db string, filter bsoncore.Document) (bsoncore.Document, error) {
+	if c.collInfoTTL <= 0 {
+		return c.collInfoFn(ctx, db, filter)
+	}
+
+	key := db + "\x00" + string(filter)
+	c.collInfoMu.Lock()
+	entry, ok := c.collInfoCache[key]
+	c.collInfoMu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.doc, nil
+	}
+
+	collInfo, err := c.collInfoFn(ctx, db, filter)
+	if err != nil {
+		return nil, err
+	}
+	c.collInfoMu.Lock()
+	c.collInfoCache[key] = collInfoEntry{doc: collInfo, expires: time.Now().Add(c.collInfoTTL)}
+	c.collInfoMu.Unlock()
+	return collInfo, nil
+}
+
+// clearCollInfo empties the collection information cache.
+func (c *Crypt) clearCollInfo() {
+	c.collInfoMu.Lock()
+	c.collInfoCache = make(map[string]collInfoEntry)
+	c.collInfoMu.Unlock()
+}
+
+// collectionCommands are the commands that create, drop, or change the schema of collections, after
+// which cached collection information may be stale.
+var collectionCommands = map[string]struct{}{
+	"create":           {},
+	"drop":             {},
+	"dropDatabase":     {},
+	"collMod":          {},
+	"renameCollection": {},
+}
+
+// changesCollections returns true if cmd is one of the collectionCommands.
+func changesCollections(cmd bsoncore.Document) bool {
+	elem, err := cmd.IndexErr(0)
+	if err != nil {
+		return false
+	}
+	_, ok := collectionCommands[elem.Key()]
+	return ok
+}
+
+// schemaChangeCodes are the codes of the errors returned when a command does not match the current
+// schema of its collection, which may have changed since its collection information was fetched.
+var schemaChangeCodes = map[int32]struct{}{
+	26:  {}, // NamespaceNotFound
+	121: {}, // DocumentValidationFailure
+}
+
+// isSchemaChangeError returns true if reply is the reply to a failed command, or to a write with
+// a write error, whose error code is one of the schemaChangeCodes.
+func isSchemaChangeError(reply bsoncore.Document) bool {
+	if code, ok := reply.Lookup("code").AsInt32OK(); ok {
+		if _, found := schemaChangeCodes[code]; found {
+			return true
+		}
+	}
+	writeErrors, ok := reply.Lookup("writeErrors").ArrayOK()
+	if !ok {
+		return false
+	}
+	vals, err := writeErrors.Values()
+	if err != nil {
+		return false
+	}
+	for _, val := range vals {
+		we, ok := val.DocumentOK()
+		if !ok {
+			continue
+		}
+		if code, ok := we.Lookup("code").AsInt32OK(); ok {
+			if _, found := schemaChangeCodes[code]; found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *Crypt) markCommand(ctx context.Context, cryptCtx cryptContext, db string) error {
+	if c.markFn == nil {
+		return errors.New("mongocryptd is not available for explicit encryption")
+	}
+	markedCmd, err := cryptCtx.NextOperation()
+	if err != nil {
+		return err
+	}
+
+	res, err := c.markFn(ctx, db, markedCmd)
+	if err != nil {
+		return err
+	}
+	if err = cryptCtx.AddOperationResult(res); err != nil {
+		return err
+	}
+
+	return cryptCtx.CompleteOperation()
+}
+
+func (c *Crypt) retrieveKeys(ctx context.Context, cryptCtx cryptContext) error {
+	filter, err := cryptCtx.NextOperation()
+	if err != nil {
+		return err
+	}
+
+	keys, err := c.keyFn(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err = cryptCtx.AddOperationResult(key); err != nil {
+			return err
+		}
+	}
+
+	return cryptCtx.CompleteOperation()
+}
+
+func (c *Crypt) decryptKeys(ctx context.Context, cryptCtx cryptContext) error {
+	for {
+		kmsCtx := cryptCtx.NextKmsContext()
+		if kmsCtx == nil {
+			break
+		}
+
+		if err := c.decryptKey(ctx, kmsCtx); err != nil {
+			return err
+		}
+	}
+
+	return cryptCtx.FinishKmsContexts()
+}
+
+// decryptKey sends the request of kmsCtx to the KMS over TLS and feeds the response back.
+func (c *Crypt) decryptKey(ctx context.Context, kmsCtx *mongocrypt.KmsContext) error {
+	host, err := kmsCtx.HostName()
+	if err != nil {
+		return err
+	}
+	msg, err := kmsCtx.Message()
+	if err != nil {
+		return err
+	}
+
+	addr := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		addr = net.JoinHostPort(host, defaultKmsPort)
+	}
+	hostname, _, _ := net.SplitHostPort(addr)
+
+	rawConn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	conn := tls.Client(rawConn, &tls.Config{ServerName: hostname})
+	defer func() { _ = conn.Close() }()
+
+	deadline := time.Time{}
+	if dl, ok := ctx.Deadline(); ok {
+		deadline = dl
+	}
+	if err = conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	if _, err = conn.Write(msg); err != nil {
+		return err
+	}
+
+	for {
+		bytesNeeded := kmsCtx.BytesNeeded()
+		if bytesNeeded == 0 {
+			return nil
+		}
+
+		res := make([]byte, bytesNeeded)
+		n, err := conn.Read(res)
+		if n > 0 {
+			if feedErr := kmsCtx.FeedResponse(res[:n]); feedErr != nil {
+				return feedErr
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
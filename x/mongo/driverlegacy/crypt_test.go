@@ -0,0 +1,177 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driverlegacy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/mongocrypt"
+)
+
+// fakeCryptContext moves through states, recording the operation results it is fed.
+type fakeCryptContext struct {
+	states    []mongocrypt.State
+	op        bsoncore.Document
+	fed       []bsoncore.Document
+	completed int
+	result    bsoncore.Document
+	err       error
+}
+
+func (f *fakeCryptContext) State() mongocrypt.State { return f.states[0] }
+func (f *fakeCryptContext) NextOperation() (bsoncore.Document, error) {
+	return f.op, nil
+}
+func (f *fakeCryptContext) AddOperationResult(doc bsoncore.Document) error {
+	f.fed = append(f.fed, doc)
+	return nil
+}
+func (f *fakeCryptContext) CompleteOperation() error {
+	f.completed++
+	f.states = f.states[1:]
+	return nil
+}
+func (f *fakeCryptContext) NextKmsContext() *mongocrypt.KmsContext { return nil }
+func (f *fakeCryptContext) FinishKmsContexts() error {
+	f.states = f.states[1:]
+	return nil
+}
+func (f *fakeCryptContext) Finish() (bsoncore.Document, error) { return f.result, f.err }
+
+func TestCryptExecuteStateMachine(t *testing.T) {
+	doc := func(key string) bsoncore.Document {
+		return bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, key, 1))
+	}
+	filter := doc("filter")
+	collInfo := doc("collInfo")
+	marked := doc("marked")
+	keys := []bsoncore.Document{doc("key1"), doc("key2")}
+	result := doc("result")
+
+	var collInfoDB, markDB string
+	crypt := NewCrypt(&CryptOptions{
+		CollInfoFn: func(_ context.Context, db string, f bsoncore.Document) (bsoncore.Document, error) {
+			require.Equal(t, filter, f)
+			collInfoDB = db
+			return collInfo, nil
+		},
+		MarkFn: func(_ context.Context, db string, cmd bsoncore.Document) (bsoncore.Document, error) {
+			markDB = db
+			return marked, nil
+		},
+		KeyFn: func(_ context.Context, f bsoncore.Document) ([]bsoncore.Document, error) {
+			return keys, nil
+		},
+	})
+
+	t.Run("runs every state", func(t *testing.T) {
+		cryptCtx := &fakeCryptContext{
+			states: []mongocrypt.State{
+				mongocrypt.NeedMongoCollInfo,
+				mongocrypt.NeedMongoMarkings,
+				mongocrypt.NeedMongoKeys,
+				mongocrypt.NeedKms,
+				mongocrypt.Ready,
+			},
+			op:     filter,
+			result: result,
+		}
+
+		res, err := crypt.executeStateMachine(context.Background(), cryptCtx, "db")
+		require.NoError(t, err)
+		require.Equal(t, result, res)
+		require.Equal(t, "db", collInfoDB)
+		require.Equal(t, "db", markDB)
+		require.Equal(t, []bsoncore.Document{collInfo, marked, keys[0], keys[1]}, cryptCtx.fed)
+		require.Equal(t, 3, cryptCtx.completed)
+	})
+	t.Run("done", func(t *testing.T) {
+		cryptCtx := &fakeCryptContext{states: []mongocrypt.State{mongocrypt.Done}}
+
+		res, err := crypt.executeStateMachine(context.Background(), cryptCtx, "db")
+		require.NoError(t, err)
+		require.Nil(t, res)
+	})
+	t.Run("error state", func(t *testing.T) {
+		ctxErr := errors.New("bad schema")
+		cryptCtx := &fakeCryptContext{states: []mongocrypt.State{mongocrypt.StateError}, err: ctxErr}
+
+		_, err := crypt.executeStateMachine(context.Background(), cryptCtx, "db")
+		require.Equal(t, ctxErr, err)
+	})
+	t.Run("explicit encryption has no collection info", func(t *testing.T) {
+		explicit := NewCrypt(&CryptOptions{KeyFn: crypt.keyFn})
+		cryptCtx := &fakeCryptContext{states: []mongocrypt.State{mongocrypt.NeedMongoCollInfo}}
+
+		_, err := explicit.executeStateMachine(context.Background(), cryptCtx, "db")
+		require.Error(t, err)
+	})
+}
+
+func TestCryptCollInfoCache(t *testing.T) {
+	filter := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendStringElement(nil, "name", "coll"))
+	var calls int
+	crypt := NewCrypt(&CryptOptions{
+		CollInfoFn: func(context.Context, string, bsoncore.Document) (bsoncore.Document, error) {
+			calls++
+			return nil, nil
+		},
+		CollInfoCacheTTL: time.Minute,
+	})
+	collInfo := func(db string) {
+		cryptCtx := &fakeCryptContext{
+			states: []mongocrypt.State{mongocrypt.NeedMongoCollInfo, mongocrypt.Done},
+			op:     filter,
+		}
+		_, err := crypt.executeStateMachine(context.Background(), cryptCtx, db)
+		require.NoError(t, err)
+	}
+
+	collInfo("db")
+	collInfo("db")
+	require.Equal(t, 1, calls, "a collection that does not exist is cached too")
+	collInfo("other")
+	require.Equal(t, 2, calls)
+
+	drop := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendStringElement(nil, "drop", "coll"))
+	require.True(t, changesCollections(drop))
+	crypt.clearCollInfo()
+	collInfo("db")
+	require.Equal(t, 3, calls)
+
+	find := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendStringElement(nil, "find", "coll"))
+	require.False(t, changesCollections(find))
+
+	t.Run("isSchemaChangeError", func(t *testing.T) {
+		doc := func(elems ...[]byte) bsoncore.Document {
+			return bsoncore.BuildDocumentFromElements(nil, elems...)
+		}
+		writeError := func(code int32) bsoncore.Document {
+			return doc(bsoncore.AppendArrayElement(nil, "writeErrors", bsoncore.BuildArray(nil,
+				bsoncore.Value{Type: bsontype.EmbeddedDocument, Data: doc(bsoncore.AppendInt32Element(nil, "code", code))},
+			)))
+		}
+
+		require.True(t, isSchemaChangeError(doc(bsoncore.AppendInt32Element(nil, "code", 26))))
+		require.True(t, isSchemaChangeError(writeError(121)))
+		require.False(t, isSchemaChangeError(writeError(11000)))
+		require.False(t, isSchemaChangeError(doc(bsoncore.AppendInt32Element(nil, "ok", 1))))
+	})
+	t.Run("disabled", func(t *testing.T) {
+		calls = 0
+		crypt.collInfoTTL = 0
+		collInfo("db")
+		collInfo("db")
+		require.Equal(t, 2, calls)
+	})
+}
@@ -61,6 +61,33 @@ func Delete(
 		}
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"collation", bsonx.Document(collDoc)})
 	}
+	if deleteOpts.Comment != nil {
+		if ss.Description().WireVersion.Max < 9 {
+			return result.Delete{}, ErrWriteComment
+		}
+		commentElem, err := commentElement("comment", deleteOpts.Comment, ss.Description().WireVersion.Max, nil)
+		if err != nil {
+			return result.Delete{}, err
+		}
+		cmd.Opts = append(cmd.Opts, commentElem)
+	}
+	if deleteOpts.Hint != nil {
+		if ss.Description().WireVersion.Max < 9 {
+			return result.Delete{}, ErrDeleteHint
+		}
+		hintElem, err := interfaceToElement("hint", deleteOpts.Hint, nil)
+		if err != nil {
+			return result.Delete{}, err
+		}
+		cmd.Opts = append(cmd.Opts, hintElem)
+	}
+	if deleteOpts.Let != nil {
+		letElem, err := letElement(deleteOpts.Let, ss.Description().WireVersion.Max, nil)
+		if err != nil {
+			return result.Delete{}, err
+		}
+		cmd.Opts = append(cmd.Opts, letElem)
+	}
 
 	// Execute in a single trip if retry writes not supported, or retry not enabled
 	if !retrySupported(topo, ss.Description(), cmd.Session, cmd.WriteConcern) || !retryWrite {
@@ -77,7 +104,7 @@ func Delete(
 
 	// Retry if appropriate
 	if cerr, ok := originalErr.(command.Error); (ok && cerr.Retryable()) ||
-		(res.WriteConcernError != nil && command.IsWriteConcernErrorRetryable(res.WriteConcernError)) {
+		writeConcernErrorRetryable(res.WriteConcernError, cmd.WriteConcern, topo.RetryWriteConcernTimeouts()) {
 		ss, err := topo.SelectServer(ctx, selector)
 
 		// Return original error if server selection fails or new server does not support retryable writes
@@ -85,7 +112,10 @@ func Delete(
 			return res, originalErr
 		}
 
-		return delete(ctx, &cmd, ss, cerr)
+		code := retryErrorCode(originalErr, res.WriteConcernError)
+		res, err = delete(ctx, &cmd, ss, cerr)
+		recordWriteRetry(topo, code, err, res.WriteConcernError)
+		return res, err
 	}
 	return res, originalErr
 }
@@ -98,7 +128,7 @@ func delete(
 ) (result.Delete, error) {
 	desc := ss.Description()
 
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		if oldErr != nil {
 			return result.Delete{}, oldErr
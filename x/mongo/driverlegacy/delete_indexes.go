@@ -38,7 +38,7 @@ func DropIndexes(
 		return nil, err
 	}
 
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		return nil, err
 	}
@@ -7,12 +7,15 @@
 package driverlegacy // import "go.mongodb.org/mongo-driver/x/mongo/driverlegacy"
 
 import (
+	"context"
 	"errors"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/x/bsonx"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
+	"go.mongodb.org/mongo-driver/x/network/connection"
 )
 
 // ErrCollation is caused if a collation is given for an invalid server version.
@@ -21,6 +24,33 @@ var ErrCollation = errors.New("collation cannot be set for server versions < 3.4
 // ErrArrayFilters is caused if array filters are given for an invalid server version.
 var ErrArrayFilters = errors.New("array filters cannot be set for server versions < 3.6")
 
+// ErrUpdatePipeline is caused if an aggregation pipeline update is given for an invalid server version.
+var ErrUpdatePipeline = errors.New("update pipelines cannot be used for server versions < 4.2")
+
+// ErrUpdateHint is caused if a hint is given for an update for an invalid server version.
+var ErrUpdateHint = errors.New("hint cannot be set for updates for server versions < 4.2")
+
+// ErrDeleteHint is caused if a hint is given for a delete for an invalid server version.
+var ErrDeleteHint = errors.New("hint cannot be set for deletes for server versions < 4.4")
+
+// ErrFindAndModifyHint is caused if a hint is given for a findAndModify for an invalid server version.
+var ErrFindAndModifyHint = errors.New("hint cannot be set for findAndModify for server versions < 4.4")
+
+// ErrCommentType is caused if a comment that is not a string is given for an invalid server version.
+var ErrCommentType = errors.New("comments other than strings cannot be set for server versions < 4.4")
+
+// ErrWriteComment is caused if a comment is given for a write or findAndModify for an invalid server version.
+var ErrWriteComment = errors.New("comment cannot be set for writes and findAndModify for server versions < 4.4")
+
+// ErrLet is caused if let variables are given for an invalid server version.
+var ErrLet = errors.New("let cannot be set for server versions < 5.0")
+
+// ErrExplainFind is caused if a find is explained for an invalid server version.
+var ErrExplainFind = errors.New("find cannot be explained for server versions < 3.2")
+
+// ErrExplainAggregate is caused if an aggregate is explained for an invalid server version.
+var ErrExplainAggregate = errors.New("aggregate cannot be explained for server versions < 3.6")
+
 func interfaceToDocument(val interface{}, registry *bsoncodec.Registry) (bsonx.Doc, error) {
 	if val == nil {
 		return bsonx.Doc{}, nil
@@ -60,8 +90,65 @@ func interfaceToElement(key string, i interface{}, registry *bsoncodec.Registry)
 	}
 }
 
+// commentElement returns the comment of a command, which can be any BSON value for server
+// versions >= 4.4 and a string otherwise.
+func commentElement(key string, comment interface{}, maxWireVersion int32, registry *bsoncodec.Registry) (bsonx.Elem, error) {
+	if s, ok := comment.(string); ok {
+		return bsonx.Elem{key, bsonx.String(s)}, nil
+	}
+	if maxWireVersion < 9 {
+		return bsonx.Elem{}, ErrCommentType
+	}
+	doc, err := interfaceToDocument(bson.D{{Key: key, Value: comment}}, registry)
+	if err != nil {
+		return bsonx.Elem{}, err
+	}
+	return doc[0], nil
+}
+
+// letElement returns the let variables of a command.
+func letElement(let interface{}, maxWireVersion int32, registry *bsoncodec.Registry) (bsonx.Elem, error) {
+	if maxWireVersion < 13 {
+		return bsonx.Elem{}, ErrLet
+	}
+	doc, err := interfaceToDocument(let, registry)
+	if err != nil {
+		return bsonx.Elem{}, err
+	}
+	return bsonx.Elem{"let", bsonx.Document(doc)}, nil
+}
+
 func closeImplicitSession(sess *session.Client) {
 	if sess != nil && sess.SessionType == session.Implicit {
 		sess.EndSession()
 	}
 }
+
+// sendOnGetMore returns whether an option of a command that creates a cursor is sent again on its
+// getMore commands: set if the user chose, or def otherwise.
+func sendOnGetMore(set *bool, def bool) bool {
+	if set != nil {
+		return *set
+	}
+	return def
+}
+
+// sessionConnection gets a connection to ss for an operation run with sess, which may be nil. Behind
+// a load balancer, every command of a transaction must be sent on the same connection, so the
+// connection used by the first command of a transaction is pinned to sess and returned again until
+// the transaction is committed or aborted.
+func sessionConnection(ctx context.Context, ss *topology.SelectedServer, sess *session.Client) (connection.Connection, error) {
+	if sess != nil && sess.PinnedConnection != nil {
+		if sess.TransactionRunning() || sess.Committing || sess.Aborting {
+			return topology.PinConnection(sess.PinnedConnection), nil
+		}
+		sess.UnpinConnection()
+	}
+
+	conn, err := ss.Connection(ctx)
+	if err != nil || !sess.TransactionRunning() {
+		return conn, err
+	}
+	sess.PinnedConnection = topology.PinConnection(conn)
+	return conn, nil
+}
@@ -0,0 +1,49 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driverlegacy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+)
+
+func TestSendOnGetMore(t *testing.T) {
+	gmo := options.GetMore().SetBatchSize(false).SetComment(true)
+
+	require.False(t, sendOnGetMore(gmo.BatchSize, true), "expected the user's choice to override the default")
+	require.True(t, sendOnGetMore(gmo.Comment, false), "expected the user's choice to override the default")
+	require.True(t, sendOnGetMore(gmo.MaxAwaitTime, true), "expected the default when the user did not choose")
+	require.False(t, sendOnGetMore(gmo.MaxAwaitTime, false), "expected the default when the user did not choose")
+}
+
+func TestCommentElement(t *testing.T) {
+	elem, err := commentElement("comment", "trace", 8, nil)
+	require.NoError(t, err)
+	require.True(t, elem.Equal(bsonx.Elem{"comment", bsonx.String("trace")}))
+
+	_, err = commentElement("comment", bson.D{{"caller", "orders"}}, 8, nil)
+	require.Equal(t, ErrCommentType, err)
+
+	elem, err = commentElement("comment", bson.D{{"caller", "orders"}}, 9, nil)
+	require.NoError(t, err)
+	want := bsonx.Elem{"comment", bsonx.Document(bsonx.Doc{{"caller", bsonx.String("orders")}})}
+	require.True(t, elem.Equal(want), "got %v; want %v", elem, want)
+}
+
+func TestLetElement(t *testing.T) {
+	_, err := letElement(bson.D{{"x", 1}}, 12, nil)
+	require.Equal(t, ErrLet, err)
+
+	elem, err := letElement(bson.D{{"x", int32(1)}}, 13, nil)
+	require.NoError(t, err)
+	want := bsonx.Elem{"let", bsonx.Document(bsonx.Doc{{"x", bsonx.Int32(1)}})}
+	require.True(t, elem.Equal(want), "got %v; want %v", elem, want)
+}
@@ -16,6 +16,7 @@ import (
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
 	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/description"
 	"go.mongodb.org/mongo-driver/x/network/result"
 )
@@ -29,6 +30,7 @@ func Distinct(
 	selector description.ServerSelector,
 	clientID uuid.UUID,
 	pool *session.Pool,
+	retryRead bool,
 	opts ...*options.DistinctOptions,
 ) (result.Distinct, error) {
 
@@ -41,7 +43,7 @@ func Distinct(
 	}
 
 	desc := ss.Description()
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		return result.Distinct{}, err
 	}
@@ -80,5 +82,14 @@ func Distinct(
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"collation", bsonx.Document(collDoc)})
 	}
 
-	return cmd.RoundTrip(ctx, desc, conn)
+	res, err := cmd.RoundTrip(ctx, desc, conn)
+	if err != nil && retryRead && shouldRetryRead(topo, desc, cmd.Session, err) {
+		_, err = retryReadOnce(ctx, topo, selector, cmd.Session, ss, err, func(desc description.SelectedServer, conn connection.Connection) error {
+			var rtErr error
+			res, rtErr = cmd.RoundTrip(ctx, desc, conn)
+			return rtErr
+		})
+	}
+
+	return res, err
 }
@@ -7,6 +7,7 @@
 package dns
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -24,14 +25,43 @@ type Resolver struct {
 // DefaultResolver is a Resolver that uses the default Resolver from the net package.
 var DefaultResolver = &Resolver{net.LookupSRV, net.LookupTXT}
 
+// Lookuper performs DNS lookups. *net.Resolver implements Lookuper.
+type Lookuper interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// NewResolver creates a Resolver that performs SRV and TXT lookups with l.
+func NewResolver(l Lookuper) *Resolver {
+	return &Resolver{
+		LookupSRV: func(service, proto, name string) (string, []*net.SRV, error) {
+			return l.LookupSRV(context.Background(), service, proto, name)
+		},
+		LookupTXT: func(name string) ([]string, error) {
+			return l.LookupTXT(context.Background(), name)
+		},
+	}
+}
+
+// DefaultSRVServiceName is the service name of the SRV records looked up for mongodb+srv connection
+// strings that do not set srvServiceName.
+const DefaultSRVServiceName = "mongodb"
+
 // ParseHosts uses the srv string to get the hosts.
 func (r *Resolver) ParseHosts(host string, stopOnErr bool) ([]string, error) {
+	return r.ParseHostsWithService(host, DefaultSRVServiceName, stopOnErr)
+}
+
+// ParseHostsWithService works like ParseHosts, but looks up the SRV records of the given service
+// name instead of "mongodb".
+func (r *Resolver) ParseHostsWithService(host, srvName string, stopOnErr bool) ([]string, error) {
 	parsedHosts := strings.Split(host, ",")
 
 	if len(parsedHosts) != 1 {
 		return nil, fmt.Errorf("URI with SRV must include one and only one hostname")
 	}
-	return r.fetchSeedlistFromSRV(parsedHosts[0], stopOnErr)
+	return r.fetchSeedlistFromSRV(parsedHosts[0], srvName, stopOnErr)
 }
 
 // GetConnectionArgsFromTXT gets the TXT record associated with the host and returns the connection arguments.
@@ -64,7 +94,7 @@ func (r *Resolver) GetConnectionArgsFromTXT(host string) ([]string, error) {
 	return connectionArgsFromTXT, nil
 }
 
-func (r *Resolver) fetchSeedlistFromSRV(host string, stopOnErr bool) ([]string, error) {
+func (r *Resolver) fetchSeedlistFromSRV(host, srvName string, stopOnErr bool) ([]string, error) {
 	var err error
 
 	_, _, err = net.SplitHostPort(host)
@@ -75,7 +105,7 @@ func (r *Resolver) fetchSeedlistFromSRV(host string, stopOnErr bool) ([]string,
 		return nil, fmt.Errorf("URI with srv must not include a port number")
 	}
 
-	_, addresses, err := r.LookupSRV("mongodb", "tcp", host)
+	_, addresses, err := r.LookupSRV(srvName, "tcp", host)
 	if err != nil {
 		return nil, err
 	}
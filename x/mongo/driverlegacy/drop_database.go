@@ -33,7 +33,7 @@ func DropDatabase(
 		return nil, err
 	}
 
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		return nil, err
 	}
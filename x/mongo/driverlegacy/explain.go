@@ -0,0 +1,135 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driverlegacy
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
+	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/description"
+)
+
+// ExplainFind handles the full cycle dispatch and execution of an explain command for a find command
+// against the provided topology. The find command is built from opts as Find builds it.
+func ExplainFind(
+	ctx context.Context,
+	cmd command.Find,
+	verbosity string,
+	topo *topology.Topology,
+	selector description.ServerSelector,
+	clientID uuid.UUID,
+	pool *session.Pool,
+	registry *bsoncodec.Registry,
+	opts ...*options.FindOptions,
+) (bson.Raw, error) {
+
+	if cmd.Session != nil && cmd.Session.PinnedServer != nil {
+		selector = cmd.Session.PinnedServer
+	}
+	ss, err := topo.SelectServer(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := ss.Description()
+	if desc.WireVersion.Max < 4 {
+		return nil, ErrExplainFind
+	}
+
+	rp, err := getReadPrefBasedOnTransaction(cmd.ReadPref, cmd.Session)
+	if err != nil {
+		return nil, err
+	}
+	cmd.ReadPref = rp
+
+	// If no explicit session and deployment supports sessions, start implicit session.
+	if cmd.Session == nil && topo.SupportsSessions() {
+		cmd.Session, err = session.NewClientSession(pool, clientID, session.Implicit)
+		if err != nil {
+			return nil, err
+		}
+		defer cmd.Session.EndSession()
+	}
+
+	if err = addFindOptions(&cmd, options.MergeFindOptions(opts...), desc, registry); err != nil {
+		return nil, err
+	}
+	return explain(ctx, &cmd, cmd.Session, verbosity, ss)
+}
+
+// ExplainAggregate handles the full cycle dispatch and execution of an explain command for an
+// aggregate command against the provided topology. The aggregate command is built from opts as
+// Aggregate builds it.
+func ExplainAggregate(
+	ctx context.Context,
+	cmd command.Aggregate,
+	verbosity string,
+	topo *topology.Topology,
+	readSelector, writeSelector description.ServerSelector,
+	clientID uuid.UUID,
+	pool *session.Pool,
+	registry *bsoncodec.Registry,
+	opts ...*options.AggregateOptions,
+) (bson.Raw, error) {
+
+	selector := readSelector
+	if cmd.HasDollarOut() {
+		selector = writeSelector
+	}
+	if cmd.Session != nil && cmd.Session.PinnedServer != nil {
+		selector = cmd.Session.PinnedServer
+	}
+	ss, err := topo.SelectServer(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := ss.Description()
+	if desc.WireVersion.Max < 6 {
+		return nil, ErrExplainAggregate
+	}
+
+	rp, err := getReadPrefBasedOnTransaction(cmd.ReadPref, cmd.Session)
+	if err != nil {
+		return nil, err
+	}
+	cmd.ReadPref = rp
+
+	// If no explicit session and deployment supports sessions, start implicit session.
+	if cmd.Session == nil && topo.SupportsSessions() {
+		cmd.Session, err = session.NewClientSession(pool, clientID, session.Implicit)
+		if err != nil {
+			return nil, err
+		}
+		defer cmd.Session.EndSession()
+	}
+
+	if err = addAggregateOptions(&cmd, options.MergeAggregateOptions(opts...), desc, registry); err != nil {
+		return nil, err
+	}
+	return explain(ctx, &cmd, cmd.Session, verbosity, ss)
+}
+
+// explain sends an explain command for the explained command, which uses sess, on the selected server.
+func explain(ctx context.Context, explained command.Explainable, sess *session.Client, verbosity string,
+	ss *topology.SelectedServer) (bson.Raw, error) {
+
+	conn, err := sessionConnection(ctx, ss, sess)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	cmd := command.Explain{Command: explained, Verbosity: verbosity}
+	return cmd.RoundTrip(ctx, ss.Description(), conn)
+}
@@ -37,6 +37,7 @@ func Find(
 	clientID uuid.UUID,
 	pool *session.Pool,
 	registry *bsoncodec.Registry,
+	retryRead bool,
 	opts ...*options.FindOptions,
 ) (*BatchCursor, error) {
 
@@ -49,7 +50,7 @@ func Find(
 	}
 
 	desc := ss.Description()
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		return nil, err
 	}
@@ -74,13 +75,56 @@ func Find(
 	}
 
 	fo := options.MergeFindOptions(opts...)
+	if err = addFindOptions(&cmd, fo, desc, registry); err != nil {
+		return nil, err
+	}
+
+	var retryConn connection.Connection
+	res, err := cmd.RoundTrip(ctx, desc, conn)
+	if err != nil && retryRead && shouldRetryRead(topo, desc, cmd.Session, err) {
+		ss, err = retryReadOnce(ctx, topo, selector, cmd.Session, ss, err, func(desc description.SelectedServer, conn connection.Connection) error {
+			var rtErr error
+			res, rtErr = cmd.RoundTrip(ctx, desc, conn)
+			retryConn = topology.PinConnection(conn)
+			return rtErr
+		})
+	}
+	if retryConn != nil {
+		defer retryConn.Close()
+		conn = retryConn
+	}
+	if err != nil {
+		closeImplicitSession(cmd.Session)
+		return nil, err
+	}
+
+	bc, err := NewBatchCursor(bsoncore.Document(res), cmd.Session, cmd.Clock, ss.Server, cmd.CursorOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if fo.TargetBatchBytes != nil {
+		bc.SetTargetBatchBytes(*fo.TargetBatchBytes)
+	}
+	bc.PinConnection(conn)
+	return bc, nil
+}
+
+// addFindOptions appends the elements for the options fo to the find command and to the getMore
+// commands of its cursor.
+func addFindOptions(cmd *command.Find, fo *options.FindOptions, desc description.SelectedServer, registry *bsoncodec.Registry) error {
+	gmo := fo.GetMoreOptions
+	if gmo == nil {
+		gmo = options.GetMore()
+	}
 	if fo.AllowPartialResults != nil {
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"allowPartialResults", bsonx.Boolean(*fo.AllowPartialResults)})
 	}
 	if fo.BatchSize != nil {
 		elem := bsonx.Elem{"batchSize", bsonx.Int32(*fo.BatchSize)}
 		cmd.Opts = append(cmd.Opts, elem)
-		cmd.CursorOpts = append(cmd.CursorOpts, elem)
+		if sendOnGetMore(gmo.BatchSize, true) {
+			cmd.CursorOpts = append(cmd.CursorOpts, elem)
+		}
 
 		if fo.Limit != nil && *fo.BatchSize != 0 && *fo.Limit <= int64(*fo.BatchSize) {
 			cmd.Opts = append(cmd.Opts, bsonx.Elem{"singleBatch", bsonx.Boolean(true)})
@@ -88,16 +132,23 @@ func Find(
 	}
 	if fo.Collation != nil {
 		if desc.WireVersion.Max < 5 {
-			return nil, ErrCollation
+			return ErrCollation
 		}
 		collDoc, err := bsonx.ReadDoc(fo.Collation.ToDocument())
 		if err != nil {
-			return nil, err
+			return err
 		}
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"collation", bsonx.Document(collDoc)})
 	}
 	if fo.Comment != nil {
-		cmd.Opts = append(cmd.Opts, bsonx.Elem{"comment", bsonx.String(*fo.Comment)})
+		elem, err := commentElement("comment", fo.Comment, desc.WireVersion.Max, registry)
+		if err != nil {
+			return err
+		}
+		cmd.Opts = append(cmd.Opts, elem)
+		if sendOnGetMore(gmo.Comment, desc.WireVersion.Max >= 9) {
+			cmd.CursorOpts = append(cmd.CursorOpts, elem)
+		}
 	}
 	if fo.CursorType != nil {
 		switch *fo.CursorType {
@@ -110,23 +161,31 @@ func Find(
 	if fo.Hint != nil {
 		hintElem, err := interfaceToElement("hint", fo.Hint, registry)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		cmd.Opts = append(cmd.Opts, hintElem)
 	}
+	if fo.Let != nil {
+		letElem, err := letElement(fo.Let, desc.WireVersion.Max, registry)
+		if err != nil {
+			return err
+		}
+		cmd.Opts = append(cmd.Opts, letElem)
+	}
 	if fo.Limit != nil {
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"limit", bsonx.Int64(*fo.Limit)})
 	}
 	if fo.Max != nil {
 		maxElem, err := interfaceToElement("max", fo.Max, registry)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		cmd.Opts = append(cmd.Opts, maxElem)
 	}
-	if fo.MaxAwaitTime != nil {
+	tailableAwait := fo.CursorType != nil && *fo.CursorType == options.TailableAwait
+	if fo.MaxAwaitTime != nil && sendOnGetMore(gmo.MaxAwaitTime, tailableAwait) {
 		// Specified as maxTimeMS on the in the getMore command and not given in initial find command.
 		cmd.CursorOpts = append(cmd.CursorOpts, bsonx.Elem{"maxTimeMS", bsonx.Int64(int64(*fo.MaxAwaitTime / time.Millisecond))})
 	}
@@ -136,7 +195,7 @@ func Find(
 	if fo.Min != nil {
 		minElem, err := interfaceToElement("min", fo.Min, registry)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		cmd.Opts = append(cmd.Opts, minElem)
@@ -150,7 +209,7 @@ func Find(
 	if fo.Projection != nil {
 		projElem, err := interfaceToElement("projection", fo.Projection, registry)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		cmd.Opts = append(cmd.Opts, projElem)
@@ -170,19 +229,12 @@ func Find(
 	if fo.Sort != nil {
 		sortElem, err := interfaceToElement("sort", fo.Sort, registry)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		cmd.Opts = append(cmd.Opts, sortElem)
 	}
-
-	res, err := cmd.RoundTrip(ctx, desc, conn)
-	if err != nil {
-		closeImplicitSession(cmd.Session)
-		return nil, err
-	}
-
-	return NewBatchCursor(bsoncore.Document(res), cmd.Session, cmd.Clock, ss.Server, cmd.CursorOpts...)
+	return nil
 }
 
 // legacyFind handles the dispatch and execution of a find operation against a pre-3.2 server.
@@ -297,7 +349,11 @@ func createLegacyOptionsDoc(fo *options.FindOptions, registry *bsoncodec.Registr
 		return nil, ErrCollation
 	}
 	if fo.Comment != nil {
-		optsDoc = append(optsDoc, bsonx.Elem{"$comment", bsonx.String(*fo.Comment)})
+		commentElem, err := commentElement("$comment", fo.Comment, 0, registry)
+		if err != nil {
+			return nil, err
+		}
+		optsDoc = append(optsDoc, commentElem)
 	}
 	if fo.Hint != nil {
 		hintElem, err := interfaceToElement("$hint", fo.Hint, registry)
@@ -307,6 +363,9 @@ func createLegacyOptionsDoc(fo *options.FindOptions, registry *bsoncodec.Registr
 
 		optsDoc = append(optsDoc, hintElem)
 	}
+	if fo.Let != nil {
+		return nil, ErrLet
+	}
 	if fo.Max != nil {
 		maxElem, err := interfaceToElement("$max", fo.Max, registry)
 		if err != nil {
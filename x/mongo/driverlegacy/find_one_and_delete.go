@@ -65,6 +65,33 @@ func FindOneAndDelete(
 		}
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"collation", bsonx.Document(collDoc)})
 	}
+	if do.Comment != nil {
+		if ss.Description().WireVersion.Max < 9 {
+			return result.FindAndModify{}, ErrWriteComment
+		}
+		commentElem, err := commentElement("comment", do.Comment, ss.Description().WireVersion.Max, registry)
+		if err != nil {
+			return result.FindAndModify{}, err
+		}
+		cmd.Opts = append(cmd.Opts, commentElem)
+	}
+	if do.Hint != nil {
+		if ss.Description().WireVersion.Max < 9 {
+			return result.FindAndModify{}, ErrFindAndModifyHint
+		}
+		hintElem, err := interfaceToElement("hint", do.Hint, registry)
+		if err != nil {
+			return result.FindAndModify{}, err
+		}
+		cmd.Opts = append(cmd.Opts, hintElem)
+	}
+	if do.Let != nil {
+		letElem, err := letElement(do.Let, ss.Description().WireVersion.Max, registry)
+		if err != nil {
+			return result.FindAndModify{}, err
+		}
+		cmd.Opts = append(cmd.Opts, letElem)
+	}
 	if do.MaxTime != nil {
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"maxTimeMs", bsonx.Int64(int64(*do.MaxTime / time.Millisecond))})
 	}
@@ -100,7 +127,7 @@ func FindOneAndDelete(
 
 	// Retry if appropriate
 	if cerr, ok := originalErr.(command.Error); (ok && cerr.Retryable()) ||
-		(res.WriteConcernError != nil && command.IsWriteConcernErrorRetryable(res.WriteConcernError)) {
+		writeConcernErrorRetryable(res.WriteConcernError, cmd.WriteConcern, topo.RetryWriteConcernTimeouts()) {
 		ss, err := topo.SelectServer(ctx, selector)
 
 		// Return original error if server selection fails or new server does not support retryable writes
@@ -108,7 +135,10 @@ func FindOneAndDelete(
 			return res, originalErr
 		}
 
-		return findOneAndDelete(ctx, cmd, ss, cerr)
+		code := retryErrorCode(originalErr, res.WriteConcernError)
+		res, err = findOneAndDelete(ctx, cmd, ss, cerr)
+		recordWriteRetry(topo, code, err, res.WriteConcernError)
+		return res, err
 	}
 
 	return res, originalErr
@@ -121,7 +151,7 @@ func findOneAndDelete(
 	oldErr error,
 ) (result.FindAndModify, error) {
 	desc := ss.Description()
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		if oldErr != nil {
 			return result.FindAndModify{}, oldErr
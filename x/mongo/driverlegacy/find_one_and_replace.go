@@ -68,6 +68,33 @@ func FindOneAndReplace(
 		}
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"collation", bsonx.Document(collDoc)})
 	}
+	if ro.Comment != nil {
+		if ss.Description().WireVersion.Max < 9 {
+			return result.FindAndModify{}, ErrWriteComment
+		}
+		commentElem, err := commentElement("comment", ro.Comment, ss.Description().WireVersion.Max, registry)
+		if err != nil {
+			return result.FindAndModify{}, err
+		}
+		cmd.Opts = append(cmd.Opts, commentElem)
+	}
+	if ro.Hint != nil {
+		if ss.Description().WireVersion.Max < 9 {
+			return result.FindAndModify{}, ErrFindAndModifyHint
+		}
+		hintElem, err := interfaceToElement("hint", ro.Hint, registry)
+		if err != nil {
+			return result.FindAndModify{}, err
+		}
+		cmd.Opts = append(cmd.Opts, hintElem)
+	}
+	if ro.Let != nil {
+		letElem, err := letElement(ro.Let, ss.Description().WireVersion.Max, registry)
+		if err != nil {
+			return result.FindAndModify{}, err
+		}
+		cmd.Opts = append(cmd.Opts, letElem)
+	}
 	if ro.MaxTime != nil {
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"maxTimeMS", bsonx.Int64(int64(*ro.MaxTime / time.Millisecond))})
 	}
@@ -109,7 +136,7 @@ func FindOneAndReplace(
 
 	// Retry if appropriate
 	if cerr, ok := originalErr.(command.Error); (ok && cerr.Retryable()) ||
-		(res.WriteConcernError != nil && command.IsWriteConcernErrorRetryable(res.WriteConcernError)) {
+		writeConcernErrorRetryable(res.WriteConcernError, cmd.WriteConcern, topo.RetryWriteConcernTimeouts()) {
 		ss, err := topo.SelectServer(ctx, selector)
 
 		// Return original error if server selection fails or new server does not support retryable writes
@@ -117,7 +144,10 @@ func FindOneAndReplace(
 			return res, originalErr
 		}
 
-		return findOneAndReplace(ctx, cmd, ss, cerr)
+		code := retryErrorCode(originalErr, res.WriteConcernError)
+		res, err = findOneAndReplace(ctx, cmd, ss, cerr)
+		recordWriteRetry(topo, code, err, res.WriteConcernError)
+		return res, err
 	}
 
 	return res, originalErr
@@ -130,7 +160,7 @@ func findOneAndReplace(
 	oldErr error,
 ) (result.FindAndModify, error) {
 	desc := ss.Description()
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		if oldErr != nil {
 			return result.FindAndModify{}, oldErr
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/x/bsonx"
@@ -54,6 +55,10 @@ func FindOneAndUpdate(
 		defer cmd.Session.EndSession()
 	}
 
+	if ss.Description().WireVersion.Max < 8 && cmd.Update.Type() == bsontype.Array {
+		return result.FindAndModify{}, ErrUpdatePipeline
+	}
+
 	uo := options.MergeFindOneAndUpdateOptions(opts...)
 	if uo.ArrayFilters != nil {
 		filters, err := uo.ArrayFilters.ToArray()
@@ -84,6 +89,33 @@ func FindOneAndUpdate(
 		}
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"collation", bsonx.Document(collDoc)})
 	}
+	if uo.Comment != nil {
+		if ss.Description().WireVersion.Max < 9 {
+			return result.FindAndModify{}, ErrWriteComment
+		}
+		commentElem, err := commentElement("comment", uo.Comment, ss.Description().WireVersion.Max, registry)
+		if err != nil {
+			return result.FindAndModify{}, err
+		}
+		cmd.Opts = append(cmd.Opts, commentElem)
+	}
+	if uo.Hint != nil {
+		if ss.Description().WireVersion.Max < 9 {
+			return result.FindAndModify{}, ErrFindAndModifyHint
+		}
+		hintElem, err := interfaceToElement("hint", uo.Hint, registry)
+		if err != nil {
+			return result.FindAndModify{}, err
+		}
+		cmd.Opts = append(cmd.Opts, hintElem)
+	}
+	if uo.Let != nil {
+		letElem, err := letElement(uo.Let, ss.Description().WireVersion.Max, registry)
+		if err != nil {
+			return result.FindAndModify{}, err
+		}
+		cmd.Opts = append(cmd.Opts, letElem)
+	}
 	if uo.MaxTime != nil {
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"maxTimeMS", bsonx.Int64(int64(*uo.MaxTime / time.Millisecond))})
 	}
@@ -125,7 +157,7 @@ func FindOneAndUpdate(
 
 	// Retry if appropriate
 	if cerr, ok := originalErr.(command.Error); (ok && cerr.Retryable()) ||
-		(res.WriteConcernError != nil && command.IsWriteConcernErrorRetryable(res.WriteConcernError)) {
+		writeConcernErrorRetryable(res.WriteConcernError, cmd.WriteConcern, topo.RetryWriteConcernTimeouts()) {
 		ss, err := topo.SelectServer(ctx, selector)
 
 		// Return original error if server selection fails or new server does not support retryable writes
@@ -133,7 +165,10 @@ func FindOneAndUpdate(
 			return res, originalErr
 		}
 
-		return findOneAndUpdate(ctx, cmd, ss, cerr)
+		code := retryErrorCode(originalErr, res.WriteConcernError)
+		res, err = findOneAndUpdate(ctx, cmd, ss, cerr)
+		recordWriteRetry(topo, code, err, res.WriteConcernError)
+		return res, err
 	}
 
 	return res, originalErr
@@ -146,7 +181,7 @@ func findOneAndUpdate(
 	oldErr error,
 ) (result.FindAndModify, error) {
 	desc := ss.Description()
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		if oldErr != nil {
 			return result.FindAndModify{}, oldErr
@@ -76,7 +76,7 @@ func Insert(
 
 	// Retry if appropriate
 	if cerr, ok := originalErr.(command.Error); (ok && cerr.Retryable()) ||
-		(res.WriteConcernError != nil && command.IsWriteConcernErrorRetryable(res.WriteConcernError)) {
+		writeConcernErrorRetryable(res.WriteConcernError, cmd.WriteConcern, topo.RetryWriteConcernTimeouts()) {
 		ss, err := topo.SelectServer(ctx, selector)
 
 		// Return original error if server selection fails or new server does not support retryable writes
@@ -84,7 +84,10 @@ func Insert(
 			return res, originalErr
 		}
 
-		return insert(ctx, &cmd, ss, cerr)
+		code := retryErrorCode(originalErr, res.WriteConcernError)
+		res, err = insert(ctx, &cmd, ss, cerr)
+		recordWriteRetry(topo, code, err, res.WriteConcernError)
+		return res, err
 	}
 
 	return res, originalErr
@@ -97,7 +100,7 @@ func insert(
 	oldErr error,
 ) (result.Insert, error) {
 	desc := ss.Description()
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		if oldErr != nil {
 			return result.Insert{}, oldErr
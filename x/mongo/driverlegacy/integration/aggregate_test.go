@@ -108,6 +108,7 @@ func TestAggregate(t *testing.T) {
 			clientID,
 			pool,
 			bson.DefaultRegistry,
+			false,
 			options.Aggregate().SetMaxAwaitTime(10*time.Millisecond).SetBatchSize(2),
 		)
 		noerr(t, err)
@@ -34,6 +34,7 @@ func ListCollections(
 	selector description.ServerSelector,
 	clientID uuid.UUID,
 	pool *session.Pool,
+	retryRead bool,
 	opts ...*options.ListCollectionsOptions,
 ) (*ListCollectionsBatchCursor, error) {
 
@@ -42,7 +43,7 @@ func ListCollections(
 		return nil, err
 	}
 
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		return nil, err
 	}
@@ -67,11 +68,32 @@ func ListCollections(
 	}
 
 	lc := options.MergeListCollectionsOptions(opts...)
+	if lc.AuthorizedCollections != nil {
+		cmd.Opts = append(cmd.Opts, bsonx.Elem{"authorizedCollections", bsonx.Boolean(*lc.AuthorizedCollections)})
+	}
+	if lc.BatchSize != nil {
+		elem := bsonx.Elem{"batchSize", bsonx.Int32(*lc.BatchSize)}
+		cmd.Opts = append(cmd.Opts, bsonx.Elem{"cursor", bsonx.Document(bsonx.Doc{elem})})
+		cmd.CursorOpts = append(cmd.CursorOpts, elem)
+	}
 	if lc.NameOnly != nil {
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"nameOnly", bsonx.Boolean(*lc.NameOnly)})
 	}
 
+	var retryConn connection.Connection
 	res, err := cmd.RoundTrip(ctx, ss.Description(), conn)
+	if err != nil && retryRead && shouldRetryRead(topo, ss.Description(), cmd.Session, err) {
+		ss, err = retryReadOnce(ctx, topo, selector, cmd.Session, ss, err, func(desc description.SelectedServer, conn connection.Connection) error {
+			var rtErr error
+			res, rtErr = cmd.RoundTrip(ctx, desc, conn)
+			retryConn = topology.PinConnection(conn)
+			return rtErr
+		})
+	}
+	if retryConn != nil {
+		defer retryConn.Close()
+		conn = retryConn
+	}
 	if err != nil {
 		closeImplicitSession(cmd.Session)
 		return nil, err
@@ -82,6 +104,7 @@ func ListCollections(
 		closeImplicitSession(cmd.Session)
 		return nil, err
 	}
+	batchCursor.PinConnection(conn)
 
 	return NewListCollectionsBatchCursor(batchCursor)
 }
@@ -36,7 +36,7 @@ func ListDatabases(
 		return result.ListDatabases{}, err
 	}
 
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		return result.ListDatabases{}, err
 	}
@@ -52,6 +52,9 @@ func ListDatabases(
 	}
 
 	ld := options.MergeListDatabasesOptions(opts...)
+	if ld.AuthorizedDatabases != nil {
+		cmd.Opts = append(cmd.Opts, bsonx.Elem{"authorizedDatabases", bsonx.Boolean(*ld.AuthorizedDatabases)})
+	}
 	if ld.NameOnly != nil {
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"nameOnly", bsonx.Boolean(*ld.NameOnly)})
 	}
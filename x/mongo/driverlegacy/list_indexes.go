@@ -31,6 +31,7 @@ func ListIndexes(
 	selector description.ServerSelector,
 	clientID uuid.UUID,
 	pool *session.Pool,
+	retryRead bool,
 	opts ...*options.ListIndexesOptions,
 ) (*BatchCursor, error) {
 
@@ -39,7 +40,7 @@ func ListIndexes(
 		return nil, err
 	}
 
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		return nil, err
 	}
@@ -67,13 +68,31 @@ func ListIndexes(
 		}
 	}
 
+	var retryConn connection.Connection
 	res, err := cmd.RoundTrip(ctx, ss.Description(), conn)
+	if err != nil && retryRead && shouldRetryRead(topo, ss.Description(), cmd.Session, err) {
+		ss, err = retryReadOnce(ctx, topo, selector, cmd.Session, ss, err, func(desc description.SelectedServer, conn connection.Connection) error {
+			var rtErr error
+			res, rtErr = cmd.RoundTrip(ctx, desc, conn)
+			retryConn = topology.PinConnection(conn)
+			return rtErr
+		})
+	}
+	if retryConn != nil {
+		defer retryConn.Close()
+		conn = retryConn
+	}
 	if err != nil {
 		closeImplicitSession(cmd.Session)
 		return nil, err
 	}
 
-	return NewBatchCursor(bsoncore.Document(res), cmd.Session, cmd.Clock, ss.Server, cmd.CursorOpts...)
+	bc, err := NewBatchCursor(bsoncore.Document(res), cmd.Session, cmd.Clock, ss.Server, cmd.CursorOpts...)
+	if err != nil {
+		return nil, err
+	}
+	bc.PinConnection(conn)
+	return bc, nil
 }
 
 func legacyListIndexes(
@@ -0,0 +1,59 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+//go:build cse
+// +build cse
+
+package mongocrypt
+
+// #include <mongocrypt.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"unsafe"
+)
+
+// binary is a wrapper type around a mongocrypt_binary_t.
+type binary struct {
+	p       *C.uint8_t // C memory holding a copy of the data, if any
+	wrapped *C.mongocrypt_binary_t
+}
+
+// newBinary creates an empty binary instance to be used as an out parameter.
+func newBinary() *binary {
+	return &binary{
+		wrapped: C.mongocrypt_binary_new(),
+	}
+}
+
+// newBinaryFromBytes creates a binary instance that contains a copy of the given data. The data
+// is copied because C code must not retain pointers to Go memory.
+func newBinaryFromBytes(data []byte) *binary {
+	if len(data) == 0 {
+		return newBinary()
+	}
+
+	addr := (*C.uint8_t)(C.CBytes(data))
+	return &binary{
+		p:       addr,
+		wrapped: C.mongocrypt_binary_new_from_data(addr, C.uint32_t(len(data))),
+	}
+}
+
+// toBytes returns a copy of the data of the binary instance.
+func (b *binary) toBytes() []byte {
+	dataPtr := C.mongocrypt_binary_data(b.wrapped)
+	dataLen := C.mongocrypt_binary_len(b.wrapped)
+	return C.GoBytes(unsafe.Pointer(dataPtr), C.int(dataLen))
+}
+
+// close cleans up any resources associated with the binary instance.
+func (b *binary) close() {
+	if b.p != nil {
+		C.free(unsafe.Pointer(b.p))
+	}
+	C.mongocrypt_binary_destroy(b.wrapped)
+}
@@ -0,0 +1,70 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongocrypt
+
+import (
+	"sort"
+
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// keyAltNameDocument returns the document libmongocrypt expects when setting a key alt name.
+func keyAltNameDocument(name string) bsoncore.Document {
+	return bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendStringElement(nil, "keyAltName", name))
+}
+
+// namespaceMapDocument returns a document with one field per namespace of m, in sorted order.
+func namespaceMapDocument(m map[string]bsoncore.Document) bsoncore.Document {
+	namespaces := make([]string, 0, len(m))
+	for ns := range m {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	idx, doc := bsoncore.AppendDocumentStart(nil)
+	for _, ns := range namespaces {
+		doc = bsoncore.AppendDocumentElement(doc, ns, m[ns])
+	}
+	doc, _ = bsoncore.AppendDocumentEnd(doc, idx)
+	return doc
+}
+
+// rangeOptionsDocument returns the document libmongocrypt expects when setting the options of the
+// range algorithm.
+func rangeOptionsDocument(opts *ExplicitRangeOptions) bsoncore.Document {
+	idx, doc := bsoncore.AppendDocumentStart(nil)
+	if opts.Min != nil {
+		doc = bsoncore.AppendValueElement(doc, "min", *opts.Min)
+	}
+	if opts.Max != nil {
+		doc = bsoncore.AppendValueElement(doc, "max", *opts.Max)
+	}
+	if opts.Sparsity != nil {
+		doc = bsoncore.AppendInt64Element(doc, "sparsity", *opts.Sparsity)
+	}
+	if opts.Precision != nil {
+		doc = bsoncore.AppendInt32Element(doc, "precision", *opts.Precision)
+	}
+	doc, _ = bsoncore.AppendDocumentEnd(doc, idx)
+	return doc
+}
+
+// keyEncryptionKeyDocument returns the document libmongocrypt expects when setting the master key
+// that data keys are encrypted with: the fields of masterKey prefixed by the provider.
+func keyEncryptionKeyDocument(provider string, masterKey bsoncore.Document) bsoncore.Document {
+	idx, doc := bsoncore.AppendDocumentStart(nil)
+	doc = bsoncore.AppendStringElement(doc, "provider", provider)
+	elems, _ := masterKey.Elements()
+	for _, elem := range elems {
+		if elem.Key() == "provider" {
+			continue
+		}
+		doc = append(doc, elem...)
+	}
+	doc, _ = bsoncore.AppendDocumentEnd(doc, idx)
+	return doc
+}
@@ -0,0 +1,59 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongocrypt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+func TestDocuments(t *testing.T) {
+	t.Run("namespaceMapDocument", func(t *testing.T) {
+		schema := bsoncore.Document(bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendStringElement(nil, "bsonType", "object")))
+		doc := namespaceMapDocument(map[string]bsoncore.Document{"db.b": schema, "db.a": schema})
+
+		elems, err := doc.Elements()
+		require.NoError(t, err)
+		require.Len(t, elems, 2)
+		require.Equal(t, "db.a", elems[0].Key())
+		require.Equal(t, "db.b", elems[1].Key())
+		require.Equal(t, schema, elems[0].Value().Document())
+	})
+	t.Run("rangeOptionsDocument", func(t *testing.T) {
+		min := bsoncore.Value{Type: bsontype.Int32, Data: bsoncore.AppendInt32(nil, 0)}
+		max := bsoncore.Value{Type: min.Type, Data: bsoncore.AppendInt32(nil, 200)}
+		sparsity := int64(1)
+
+		doc := rangeOptionsDocument(&ExplicitRangeOptions{Min: &min, Max: &max, Sparsity: &sparsity})
+		want := bsoncore.Document(bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, "min", 0),
+			bsoncore.AppendInt32Element(nil, "max", 200),
+			bsoncore.AppendInt64Element(nil, "sparsity", 1),
+		))
+		require.Equal(t, want, doc)
+	})
+	t.Run("keyEncryptionKeyDocument", func(t *testing.T) {
+		masterKey := bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendStringElement(nil, "provider", "local"),
+			bsoncore.AppendStringElement(nil, "region", "us-east-1"),
+			bsoncore.AppendStringElement(nil, "key", "arn"),
+		)
+
+		doc := keyEncryptionKeyDocument("aws", masterKey)
+		want := bsoncore.Document(bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendStringElement(nil, "provider", "aws"),
+			bsoncore.AppendStringElement(nil, "region", "us-east-1"),
+			bsoncore.AppendStringElement(nil, "key", "arn"),
+		))
+		require.Equal(t, want, doc)
+		want = bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendStringElement(nil, "provider", "local"))
+		require.Equal(t, want, keyEncryptionKeyDocument("local", nil))
+	})
+}
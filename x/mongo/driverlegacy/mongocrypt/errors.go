@@ -0,0 +1,26 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongocrypt
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotEnabled is returned by NewMongoCrypt when the driver was built without the cse build tag.
+var ErrNotEnabled = errors.New("client-side encryption not enabled. add the cse build tag to support")
+
+// Error represents an error from an operation on a MongoCrypt instance.
+type Error struct {
+	Code    int32
+	Message string
+}
+
+// Error implements the error interface.
+func (e Error) Error() string {
+	return fmt.Sprintf("mongocrypt error %d: %v", e.Code, e.Message)
+}
@@ -0,0 +1,343 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+//go:build cse
+// +build cse
+
+package mongocrypt
+
+// #cgo linux solaris darwin pkg-config: libmongocrypt
+// #cgo windows CFLAGS: -I"c:/libmongocrypt/include"
+// #cgo windows LDFLAGS: -lmongocrypt -Lc:/libmongocrypt/bin
+// #include <mongocrypt.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"errors"
+	"unsafe"
+
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// MongoCrypt wraps a mongocrypt_t. It is safe for concurrent use; every operation is performed on
+// a Context created from it.
+type MongoCrypt struct {
+	wrapped *C.mongocrypt_t
+}
+
+// NewMongoCrypt constructs a new MongoCrypt instance configured using the provided options.
+func NewMongoCrypt(opts *MongoCryptOptions) (*MongoCrypt, error) {
+	if opts == nil || (opts.AwsProviderOpts == nil && opts.LocalProviderOpts == nil) {
+		return nil, errors.New("at least one KMS provider must be configured")
+	}
+
+	crypt := &MongoCrypt{wrapped: C.mongocrypt_new()}
+	if crypt.wrapped == nil {
+		return nil, errors.New("could not create new mongocrypt object")
+	}
+
+	if opts.AwsProviderOpts != nil {
+		if err := crypt.setAwsProviderOpts(opts.AwsProviderOpts); err != nil {
+			crypt.Close()
+			return nil, err
+		}
+	}
+	if opts.LocalProviderOpts != nil {
+		if err := crypt.setLocalProviderOpts(opts.LocalProviderOpts); err != nil {
+			crypt.Close()
+			return nil, err
+		}
+	}
+	if len(opts.LocalSchemaMap) > 0 {
+		if err := crypt.setLocalSchemaMap(opts.LocalSchemaMap); err != nil {
+			crypt.Close()
+			return nil, err
+		}
+	}
+
+	if len(opts.EncryptedFieldsMap) > 0 {
+		if err := crypt.setEncryptedFieldsMap(opts.EncryptedFieldsMap); err != nil {
+			crypt.Close()
+			return nil, err
+		}
+	}
+
+	if !C.mongocrypt_init(crypt.wrapped) {
+		err := crypt.createErrorFromStatus()
+		crypt.Close()
+		return nil, err
+	}
+	return crypt, nil
+}
+
+// CreateEncryptionContext creates a Context to use for encrypting cmd, a command run against db.
+func (m *MongoCrypt) CreateEncryptionContext(db string, cmd bsoncore.Document) (*Context, error) {
+	ctx := newContext(C.mongocrypt_ctx_new(m.wrapped))
+	if ctx.wrapped == nil {
+		return nil, m.createErrorFromStatus()
+	}
+
+	cmdBinary := newBinaryFromBytes(cmd)
+	defer cmdBinary.close()
+	dbStr := C.CString(db)
+	defer C.free(unsafe.Pointer(dbStr))
+
+	if ok := C.mongocrypt_ctx_encrypt_init(ctx.wrapped, dbStr, C.int32_t(len(db)), cmdBinary.wrapped); !ok {
+		return nil, ctx.closeWithError()
+	}
+	return ctx, nil
+}
+
+// CreateDecryptionContext creates a Context to use for decrypting a command response.
+func (m *MongoCrypt) CreateDecryptionContext(cmd bsoncore.Document) (*Context, error) {
+	ctx := newContext(C.mongocrypt_ctx_new(m.wrapped))
+	if ctx.wrapped == nil {
+		return nil, m.createErrorFromStatus()
+	}
+
+	cmdBinary := newBinaryFromBytes(cmd)
+	defer cmdBinary.close()
+
+	if ok := C.mongocrypt_ctx_decrypt_init(ctx.wrapped, cmdBinary.wrapped); !ok {
+		return nil, ctx.closeWithError()
+	}
+	return ctx, nil
+}
+
+// CreateDataKeyContext creates a Context to use for creating a data key encrypted by the given
+// KMS provider, which must be either "aws" or "local".
+func (m *MongoCrypt) CreateDataKeyContext(kmsProvider string, opts *DataKeyOptions) (*Context, error) {
+	ctx := newContext(C.mongocrypt_ctx_new(m.wrapped))
+	if ctx.wrapped == nil {
+		return nil, m.createErrorFromStatus()
+	}
+	if opts == nil {
+		opts = &DataKeyOptions{}
+	}
+
+	switch kmsProvider {
+	case "aws":
+		region, _ := opts.MasterKey.Lookup("region").StringValueOK()
+		key, _ := opts.MasterKey.Lookup("key").StringValueOK()
+		if ok := ctx.setMasterKeyAws(region, key); !ok {
+			return nil, ctx.closeWithError()
+		}
+		if endpoint, ok := opts.MasterKey.Lookup("endpoint").StringValueOK(); ok {
+			if ok := ctx.setMasterKeyAwsEndpoint(endpoint); !ok {
+				return nil, ctx.closeWithError()
+			}
+		}
+	case "local":
+		if ok := C.mongocrypt_ctx_setopt_masterkey_local(ctx.wrapped); !ok {
+			return nil, ctx.closeWithError()
+		}
+	default:
+		ctx.Close()
+		return nil, errors.New("unrecognized KMS provider " + kmsProvider)
+	}
+
+	for _, name := range opts.KeyAltNames {
+		if err := ctx.setKeyAltName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok := C.mongocrypt_ctx_datakey_init(ctx.wrapped); !ok {
+		return nil, ctx.closeWithError()
+	}
+	return ctx, nil
+}
+
+// CreateExplicitEncryptionContext creates a Context to use for explicitly encrypting the value of
+// the "v" field of doc.
+func (m *MongoCrypt) CreateExplicitEncryptionContext(doc bsoncore.Document, opts *ExplicitEncryptionOptions) (*Context, error) {
+	ctx, err := m.newExplicitEncryptionContext(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	docBinary := newBinaryFromBytes(doc)
+	defer docBinary.close()
+	if ok := C.mongocrypt_ctx_explicit_encrypt_init(ctx.wrapped, docBinary.wrapped); !ok {
+		return nil, ctx.closeWithError()
+	}
+	return ctx, nil
+}
+
+// CreateExplicitEncryptionExpressionContext creates a Context to use for explicitly encrypting the
+// bounds of the range query expression in the "v" field of doc.
+func (m *MongoCrypt) CreateExplicitEncryptionExpressionContext(doc bsoncore.Document, opts *ExplicitEncryptionOptions) (*Context, error) {
+	ctx, err := m.newExplicitEncryptionContext(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	docBinary := newBinaryFromBytes(doc)
+	defer docBinary.close()
+	if ok := C.mongocrypt_ctx_explicit_encrypt_expression_init(ctx.wrapped, docBinary.wrapped); !ok {
+		return nil, ctx.closeWithError()
+	}
+	return ctx, nil
+}
+
+// newExplicitEncryptionContext creates a Context with the given explicit encryption options set.
+func (m *MongoCrypt) newExplicitEncryptionContext(opts *ExplicitEncryptionOptions) (*Context, error) {
+	ctx := newContext(C.mongocrypt_ctx_new(m.wrapped))
+	if ctx.wrapped == nil {
+		return nil, m.createErrorFromStatus()
+	}
+
+	if opts.KeyID != nil {
+		keyIDBinary := newBinaryFromBytes(opts.KeyID.Data)
+		defer keyIDBinary.close()
+
+		if ok := C.mongocrypt_ctx_setopt_key_id(ctx.wrapped, keyIDBinary.wrapped); !ok {
+			return nil, ctx.closeWithError()
+		}
+	}
+	if opts.KeyAltName != nil {
+		if err := ctx.setKeyAltName(*opts.KeyAltName); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.RangeOptions != nil {
+		rangeBinary := newBinaryFromBytes(rangeOptionsDocument(opts.RangeOptions))
+		defer rangeBinary.close()
+
+		if ok := C.mongocrypt_ctx_setopt_algorithm_range(ctx.wrapped, rangeBinary.wrapped); !ok {
+			return nil, ctx.closeWithError()
+		}
+	}
+
+	algoStr := C.CString(opts.Algorithm)
+	defer C.free(unsafe.Pointer(algoStr))
+	if ok := C.mongocrypt_ctx_setopt_algorithm(ctx.wrapped, algoStr, C.int(len(opts.Algorithm))); !ok {
+		return nil, ctx.closeWithError()
+	}
+
+	if opts.QueryType != "" {
+		queryStr := C.CString(opts.QueryType)
+		defer C.free(unsafe.Pointer(queryStr))
+		if ok := C.mongocrypt_ctx_setopt_query_type(ctx.wrapped, queryStr, C.int(len(opts.QueryType))); !ok {
+			return nil, ctx.closeWithError()
+		}
+	}
+	if opts.ContentionFactor != nil {
+		if ok := C.mongocrypt_ctx_setopt_contention_factor(ctx.wrapped, C.int64_t(*opts.ContentionFactor)); !ok {
+			return nil, ctx.closeWithError()
+		}
+	}
+	return ctx, nil
+}
+
+// CreateExplicitDecryptionContext creates a Context to use for explicitly decrypting the value of
+// the "v" field of doc.
+func (m *MongoCrypt) CreateExplicitDecryptionContext(doc bsoncore.Document) (*Context, error) {
+	ctx := newContext(C.mongocrypt_ctx_new(m.wrapped))
+	if ctx.wrapped == nil {
+		return nil, m.createErrorFromStatus()
+	}
+
+	docBinary := newBinaryFromBytes(doc)
+	defer docBinary.close()
+
+	if ok := C.mongocrypt_ctx_explicit_decrypt_init(ctx.wrapped, docBinary.wrapped); !ok {
+		return nil, ctx.closeWithError()
+	}
+	return ctx, nil
+}
+
+// CreateRewrapManyDataKeyContext creates a Context to use for decrypting the data keys matched by
+// filter and encrypting them again with the master key given in opts.
+func (m *MongoCrypt) CreateRewrapManyDataKeyContext(filter bsoncore.Document, opts *RewrapManyDataKeyOptions) (*Context, error) {
+	ctx := newContext(C.mongocrypt_ctx_new(m.wrapped))
+	if ctx.wrapped == nil {
+		return nil, m.createErrorFromStatus()
+	}
+
+	if opts != nil && opts.Provider != "" {
+		kekBinary := newBinaryFromBytes(keyEncryptionKeyDocument(opts.Provider, opts.MasterKey))
+		defer kekBinary.close()
+
+		if ok := C.mongocrypt_ctx_setopt_key_encryption_key(ctx.wrapped, kekBinary.wrapped); !ok {
+			return nil, ctx.closeWithError()
+		}
+	}
+
+	filterBinary := newBinaryFromBytes(filter)
+	defer filterBinary.close()
+	if ok := C.mongocrypt_ctx_rewrap_many_datakey_init(ctx.wrapped, filterBinary.wrapped); !ok {
+		return nil, ctx.closeWithError()
+	}
+	return ctx, nil
+}
+
+// Close cleans up any resources associated with the given MongoCrypt instance.
+func (m *MongoCrypt) Close() {
+	C.mongocrypt_destroy(m.wrapped)
+}
+
+func (m *MongoCrypt) setAwsProviderOpts(opts *AwsKmsProviderOptions) error {
+	keyID := C.CString(opts.AccessKeyID)
+	defer C.free(unsafe.Pointer(keyID))
+	secret := C.CString(opts.SecretAccessKey)
+	defer C.free(unsafe.Pointer(secret))
+
+	ok := C.mongocrypt_setopt_kms_provider_aws(m.wrapped, keyID, C.int32_t(len(opts.AccessKeyID)),
+		secret, C.int32_t(len(opts.SecretAccessKey)))
+	if !ok {
+		return m.createErrorFromStatus()
+	}
+	return nil
+}
+
+func (m *MongoCrypt) setLocalProviderOpts(opts *LocalKmsProviderOptions) error {
+	keyBinary := newBinaryFromBytes(opts.MasterKey)
+	defer keyBinary.close()
+
+	if ok := C.mongocrypt_setopt_kms_provider_local(m.wrapped, keyBinary.wrapped); !ok {
+		return m.createErrorFromStatus()
+	}
+	return nil
+}
+
+func (m *MongoCrypt) setLocalSchemaMap(schemaMap map[string]bsoncore.Document) error {
+	schemaMapBinary := newBinaryFromBytes(namespaceMapDocument(schemaMap))
+	defer schemaMapBinary.close()
+
+	if ok := C.mongocrypt_setopt_schema_map(m.wrapped, schemaMapBinary.wrapped); !ok {
+		return m.createErrorFromStatus()
+	}
+	return nil
+}
+
+func (m *MongoCrypt) setEncryptedFieldsMap(efMap map[string]bsoncore.Document) error {
+	efMapBinary := newBinaryFromBytes(namespaceMapDocument(efMap))
+	defer efMapBinary.close()
+
+	if ok := C.mongocrypt_setopt_encrypted_field_config_map(m.wrapped, efMapBinary.wrapped); !ok {
+		return m.createErrorFromStatus()
+	}
+	return nil
+}
+
+func (m *MongoCrypt) createErrorFromStatus() error {
+	status := C.mongocrypt_status_new()
+	defer C.mongocrypt_status_destroy(status)
+	C.mongocrypt_status(m.wrapped, status)
+	return errorFromStatus(status)
+}
+
+// errorFromStatus converts a mongocrypt_status_t to an Error.
+func errorFromStatus(status *C.mongocrypt_status_t) error {
+	var msgLen C.uint32_t
+	msg := C.mongocrypt_status_message(status, &msgLen)
+	return Error{
+		Code:    int32(C.mongocrypt_status_code(status)),
+		Message: C.GoStringN(msg, C.int(msgLen)),
+	}
+}
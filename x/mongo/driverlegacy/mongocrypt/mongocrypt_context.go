@@ -0,0 +1,139 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+//go:build cse
+// +build cse
+
+package mongocrypt
+
+// #include <mongocrypt.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"unsafe"
+
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// Context represents a mongocrypt_ctx_t handle. A Context is driven through its states by the
+// caller until it is Ready, at which point Finish returns the result of the operation.
+type Context struct {
+	wrapped *C.mongocrypt_ctx_t
+}
+
+func newContext(wrapped *C.mongocrypt_ctx_t) *Context {
+	return &Context{wrapped: wrapped}
+}
+
+// State returns the current State of the Context.
+func (c *Context) State() State {
+	return State(int(C.mongocrypt_ctx_state(c.wrapped)))
+}
+
+// NextOperation gets the document for the next database operation to run.
+func (c *Context) NextOperation() (bsoncore.Document, error) {
+	opDocBinary := newBinary() // out param owned by libmongocrypt
+	defer opDocBinary.close()
+
+	if ok := C.mongocrypt_ctx_mongo_op(c.wrapped, opDocBinary.wrapped); !ok {
+		return nil, c.createErrorFromStatus()
+	}
+	return opDocBinary.toBytes(), nil
+}
+
+// AddOperationResult feeds the result of a database operation to mongocrypt.
+func (c *Context) AddOperationResult(result bsoncore.Document) error {
+	resultBinary := newBinaryFromBytes(result)
+	defer resultBinary.close()
+
+	if ok := C.mongocrypt_ctx_mongo_feed(c.wrapped, resultBinary.wrapped); !ok {
+		return c.createErrorFromStatus()
+	}
+	return nil
+}
+
+// CompleteOperation signals a database operation has been completed.
+func (c *Context) CompleteOperation() error {
+	if ok := C.mongocrypt_ctx_mongo_done(c.wrapped); !ok {
+		return c.createErrorFromStatus()
+	}
+	return nil
+}
+
+// NextKmsContext returns the next KmsContext, or nil if there are no more.
+func (c *Context) NextKmsContext() *KmsContext {
+	kmsCtx := C.mongocrypt_ctx_next_kms_ctx(c.wrapped)
+	if kmsCtx == nil {
+		return nil
+	}
+	return newKmsContext(kmsCtx)
+}
+
+// FinishKmsContexts signals that all KMS requests have been completed.
+func (c *Context) FinishKmsContexts() error {
+	if ok := C.mongocrypt_ctx_kms_done(c.wrapped); !ok {
+		return c.createErrorFromStatus()
+	}
+	return nil
+}
+
+// Finish performs the final operations for the context and returns the resulting document.
+func (c *Context) Finish() (bsoncore.Document, error) {
+	docBinary := newBinary() // out param owned by libmongocrypt
+	defer docBinary.close()
+
+	if ok := C.mongocrypt_ctx_finalize(c.wrapped, docBinary.wrapped); !ok {
+		return nil, c.createErrorFromStatus()
+	}
+	return docBinary.toBytes(), nil
+}
+
+// Close cleans up any resources associated with the given Context instance.
+func (c *Context) Close() {
+	C.mongocrypt_ctx_destroy(c.wrapped)
+}
+
+func (c *Context) setMasterKeyAws(region, key string) bool {
+	regionStr := C.CString(region)
+	defer C.free(unsafe.Pointer(regionStr))
+	keyStr := C.CString(key)
+	defer C.free(unsafe.Pointer(keyStr))
+
+	return bool(C.mongocrypt_ctx_setopt_masterkey_aws(c.wrapped, regionStr, C.int32_t(len(region)),
+		keyStr, C.int32_t(len(key))))
+}
+
+func (c *Context) setMasterKeyAwsEndpoint(endpoint string) bool {
+	endpointStr := C.CString(endpoint)
+	defer C.free(unsafe.Pointer(endpointStr))
+
+	return bool(C.mongocrypt_ctx_setopt_masterkey_aws_endpoint(c.wrapped, endpointStr,
+		C.int32_t(len(endpoint))))
+}
+
+func (c *Context) setKeyAltName(name string) error {
+	nameBinary := newBinaryFromBytes(keyAltNameDocument(name))
+	defer nameBinary.close()
+
+	if ok := C.mongocrypt_ctx_setopt_key_alt_name(c.wrapped, nameBinary.wrapped); !ok {
+		return c.closeWithError()
+	}
+	return nil
+}
+
+// closeWithError returns the error of the context and then closes it.
+func (c *Context) closeWithError() error {
+	err := c.createErrorFromStatus()
+	c.Close()
+	return err
+}
+
+func (c *Context) createErrorFromStatus() error {
+	status := C.mongocrypt_status_new()
+	defer C.mongocrypt_status_destroy(status)
+	C.mongocrypt_ctx_status(c.wrapped, status)
+	return errorFromStatus(status)
+}
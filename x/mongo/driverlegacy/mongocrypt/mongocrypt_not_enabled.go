@@ -0,0 +1,130 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+//go:build !cse
+// +build !cse
+
+package mongocrypt
+
+import (
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// MongoCrypt represents a mongocrypt_t handle. Without the cse build tag it cannot be created.
+type MongoCrypt struct{}
+
+// NewMongoCrypt returns ErrNotEnabled because the driver was built without the cse build tag.
+func NewMongoCrypt(*MongoCryptOptions) (*MongoCrypt, error) {
+	return nil, ErrNotEnabled
+}
+
+// CreateEncryptionContext panics because the driver was built without the cse build tag.
+func (m *MongoCrypt) CreateEncryptionContext(string, bsoncore.Document) (*Context, error) {
+	panic(ErrNotEnabled)
+}
+
+// CreateDecryptionContext panics because the driver was built without the cse build tag.
+func (m *MongoCrypt) CreateDecryptionContext(bsoncore.Document) (*Context, error) {
+	panic(ErrNotEnabled)
+}
+
+// CreateDataKeyContext panics because the driver was built without the cse build tag.
+func (m *MongoCrypt) CreateDataKeyContext(string, *DataKeyOptions) (*Context, error) {
+	panic(ErrNotEnabled)
+}
+
+// CreateExplicitEncryptionContext panics because the driver was built without the cse build tag.
+func (m *MongoCrypt) CreateExplicitEncryptionContext(bsoncore.Document, *ExplicitEncryptionOptions) (*Context, error) {
+	panic(ErrNotEnabled)
+}
+
+// CreateExplicitDecryptionContext panics because the driver was built without the cse build tag.
+func (m *MongoCrypt) CreateExplicitDecryptionContext(bsoncore.Document) (*Context, error) {
+	panic(ErrNotEnabled)
+}
+
+// CreateExplicitEncryptionExpressionContext panics because the driver was built without the cse
+// build tag.
+func (m *MongoCrypt) CreateExplicitEncryptionExpressionContext(bsoncore.Document, *ExplicitEncryptionOptions) (*Context, error) {
+	panic(ErrNotEnabled)
+}
+
+// CreateRewrapManyDataKeyContext panics because the driver was built without the cse build tag.
+func (m *MongoCrypt) CreateRewrapManyDataKeyContext(bsoncore.Document, *RewrapManyDataKeyOptions) (*Context, error) {
+	panic(ErrNotEnabled)
+}
+
+// Close panics because the driver was built without the cse build tag.
+func (m *MongoCrypt) Close() {
+	panic(ErrNotEnabled)
+}
+
+// Context represents a mongocrypt_ctx_t handle. Without the cse build tag it cannot be created.
+type Context struct{}
+
+// State panics because the driver was built without the cse build tag.
+func (c *Context) State() State {
+	panic(ErrNotEnabled)
+}
+
+// NextOperation panics because the driver was built without the cse build tag.
+func (c *Context) NextOperation() (bsoncore.Document, error) {
+	panic(ErrNotEnabled)
+}
+
+// AddOperationResult panics because the driver was built without the cse build tag.
+func (c *Context) AddOperationResult(bsoncore.Document) error {
+	panic(ErrNotEnabled)
+}
+
+// CompleteOperation panics because the driver was built without the cse build tag.
+func (c *Context) CompleteOperation() error {
+	panic(ErrNotEnabled)
+}
+
+// NextKmsContext panics because the driver was built without the cse build tag.
+func (c *Context) NextKmsContext() *KmsContext {
+	panic(ErrNotEnabled)
+}
+
+// FinishKmsContexts panics because the driver was built without the cse build tag.
+func (c *Context) FinishKmsContexts() error {
+	panic(ErrNotEnabled)
+}
+
+// Finish panics because the driver was built without the cse build tag.
+func (c *Context) Finish() (bsoncore.Document, error) {
+	panic(ErrNotEnabled)
+}
+
+// Close panics because the driver was built without the cse build tag.
+func (c *Context) Close() {
+	panic(ErrNotEnabled)
+}
+
+// KmsContext represents a mongocrypt_kms_ctx_t handle. Without the cse build tag it cannot be
+// created.
+type KmsContext struct{}
+
+// HostName panics because the driver was built without the cse build tag.
+func (kc *KmsContext) HostName() (string, error) {
+	panic(ErrNotEnabled)
+}
+
+// Message panics because the driver was built without the cse build tag.
+func (kc *KmsContext) Message() ([]byte, error) {
+	panic(ErrNotEnabled)
+}
+
+// BytesNeeded panics because the driver was built without the cse build tag.
+func (kc *KmsContext) BytesNeeded() int32 {
+	panic(ErrNotEnabled)
+}
+
+// FeedResponse panics because the driver was built without the cse build tag.
+func (kc *KmsContext) FeedResponse([]byte) error {
+	panic(ErrNotEnabled)
+}
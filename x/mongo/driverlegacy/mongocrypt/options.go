@@ -0,0 +1,73 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongocrypt
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// AwsKmsProviderOptions specifies the credentials for the AWS KMS provider.
+type AwsKmsProviderOptions struct {
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// LocalKmsProviderOptions specifies the master key for the local KMS provider.
+type LocalKmsProviderOptions struct {
+	MasterKey []byte
+}
+
+// MongoCryptOptions specifies options to configure a MongoCrypt instance. At least one KMS provider
+// must be set.
+type MongoCryptOptions struct {
+	AwsProviderOpts   *AwsKmsProviderOptions
+	LocalProviderOpts *LocalKmsProviderOptions
+	// Maps namespaces of the form db.collection to JSON schemas. Schemas in this map are used
+	// instead of the ones stored on the server.
+	LocalSchemaMap map[string]bsoncore.Document
+	// Maps namespaces of the form db.collection to the encryptedFields documents of collections
+	// using Queryable Encryption.
+	EncryptedFieldsMap map[string]bsoncore.Document
+}
+
+// DataKeyOptions specifies options for creating a data key.
+type DataKeyOptions struct {
+	KeyAltNames []string
+	// The master key used to encrypt the data key. It is required for the AWS KMS provider and
+	// must contain the region and key fields, and may contain an endpoint field.
+	MasterKey bsoncore.Document
+}
+
+// ExplicitEncryptionOptions specifies options for explicitly encrypting a value. Exactly one of
+// KeyID and KeyAltName must be set.
+type ExplicitEncryptionOptions struct {
+	KeyID      *primitive.Binary
+	KeyAltName *string
+	Algorithm  string
+	// The query type of a value encrypted with the Indexed or Range algorithm that is used in a
+	// query, either "equality" or "range".
+	QueryType        string
+	ContentionFactor *int64
+	RangeOptions     *ExplicitRangeOptions
+}
+
+// ExplicitRangeOptions specifies the index options of a field encrypted with the Range algorithm.
+// They must match the options in the encryptedFields of the collection.
+type ExplicitRangeOptions struct {
+	Min       *bsoncore.Value
+	Max       *bsoncore.Value
+	Sparsity  *int64
+	Precision *int32
+}
+
+// RewrapManyDataKeyOptions specifies options for rewrapping data keys. If Provider is empty the
+// keys are rewrapped with their current master keys.
+type RewrapManyDataKeyOptions struct {
+	Provider  string
+	MasterKey bsoncore.Document
+}
@@ -0,0 +1,47 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package mongocrypt contains bindings to libmongocrypt, the library that implements client-side
+// field level encryption. The bindings use cgo and are only built with the cse build tag. Without
+// it, NewMongoCrypt returns an error.
+package mongocrypt // import "go.mongodb.org/mongo-driver/x/mongo/driverlegacy/mongocrypt"
+
+// State represents a state that a Context can be in. The values match the
+// mongocrypt_ctx_state_t values of libmongocrypt.
+type State int
+
+// These constants are valid values for the State type.
+const (
+	StateError        State = 0
+	NeedMongoCollInfo State = 1
+	NeedMongoMarkings State = 2
+	NeedMongoKeys     State = 3
+	NeedKms           State = 4
+	Ready             State = 5
+	Done              State = 6
+)
+
+// String implements the fmt.Stringer interface.
+func (s State) String() string {
+	switch s {
+	case StateError:
+		return "Error"
+	case NeedMongoCollInfo:
+		return "NeedMongoCollInfo"
+	case NeedMongoMarkings:
+		return "NeedMongoMarkings"
+	case NeedMongoKeys:
+		return "NeedMongoKeys"
+	case NeedKms:
+		return "NeedKms"
+	case Ready:
+		return "Ready"
+	case Done:
+		return "Done"
+	default:
+		return "Unknown State"
+	}
+}
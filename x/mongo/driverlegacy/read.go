@@ -15,6 +15,7 @@ import (
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/topology"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
 	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/description"
 )
 
@@ -37,7 +38,7 @@ func Read(
 		return nil, err
 	}
 
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		return nil, err
 	}
@@ -86,3 +87,97 @@ func checkTransactionReadPref(pref *readpref.ReadPref) error {
 	}
 	return nil
 }
+
+// sessionSupporter reports whether a deployment supports sessions. It is implemented by
+// *topology.Topology.
+type sessionSupporter interface {
+	SupportsSessions() bool
+}
+
+// readServer is a server a read can be retried against. It is implemented by
+// *topology.SelectedServer.
+type readServer interface {
+	Description() description.SelectedServer
+	Connection(context.Context) (connection.Connection, error)
+}
+
+// RetryRead executes a read a second time against a newly selected server if originalErr, the error
+// returned by the first attempt against originalServer, is retryable and the deployment supports
+// retryable reads. roundTrip executes the read against the server and connection it is given. The
+// server the read was last executed against is returned along with the error of that attempt.
+func RetryRead(
+	ctx context.Context,
+	topo *topology.Topology,
+	selector description.ServerSelector,
+	sess *session.Client,
+	originalServer *topology.SelectedServer,
+	originalErr error,
+	roundTrip func(description.SelectedServer, connection.Connection) error,
+) (*topology.SelectedServer, error) {
+	if !shouldRetryRead(topo, originalServer.Description(), sess, originalErr) {
+		return originalServer, originalErr
+	}
+	return retryReadOnce(ctx, topo, selector, sess, originalServer, originalErr, roundTrip)
+}
+
+// readRetrySupported returns true if a read operation executed against the given server using the
+// given session can be retried.
+func readRetrySupported(topo sessionSupporter, desc description.SelectedServer, sess *session.Client) bool {
+	return topo.SupportsSessions() &&
+		description.SessionsSupported(desc.WireVersion) &&
+		!(sess != nil && (sess.TransactionInProgress() || sess.TransactionStarting()))
+}
+
+// shouldRetryRead returns true if err is a retryable error and the read that produced it against
+// the given server can be retried.
+func shouldRetryRead(topo sessionSupporter, desc description.SelectedServer, sess *session.Client, err error) bool {
+	cerr, ok := err.(command.Error)
+	return ok && cerr.RetryableRead() && readRetrySupported(topo, desc, sess)
+}
+
+// retryReadOnce selects a new server and executes a read operation against it a second time using
+// roundTrip, and records the retry in the retry statistics of topo. The newly selected server is
+// returned along with the error from roundTrip. If server selection fails, the new server does not
+// support retryable reads, or a connection cannot be checked out, the original server and error are
+// returned instead.
+func retryReadOnce(
+	ctx context.Context,
+	topo *topology.Topology,
+	selector description.ServerSelector,
+	sess *session.Client,
+	originalServer *topology.SelectedServer,
+	originalErr error,
+	roundTrip func(description.SelectedServer, connection.Connection) error,
+) (*topology.SelectedServer, error) {
+	selectServer := func(ctx context.Context) (readServer, error) {
+		return topo.SelectServer(ctx, selector)
+	}
+	ss, err := retryRead(ctx, topo, selectServer, sess, roundTrip)
+	if ss == nil {
+		return originalServer, originalErr
+	}
+	topo.RecordRetry(topology.RetryRead, retryErrorCode(originalErr, nil), err)
+	return ss.(*topology.SelectedServer), err
+}
+
+// retryRead implements retryReadOnce. It returns a nil server if the read was not executed again.
+func retryRead(
+	ctx context.Context,
+	topo sessionSupporter,
+	selectServer func(context.Context) (readServer, error),
+	sess *session.Client,
+	roundTrip func(description.SelectedServer, connection.Connection) error,
+) (readServer, error) {
+	ss, err := selectServer(ctx)
+	if err != nil || !readRetrySupported(topo, ss.Description(), sess) {
+		return nil, nil
+	}
+
+	conn, err := ss.Connection(ctx)
+	if err != nil {
+		return nil, nil
+	}
+	defer conn.Close()
+
+	return ss, roundTrip(ss.Description(), conn)
+}
@@ -39,7 +39,7 @@ func ReadCursor(
 	}
 
 	desc := ss.Description()
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +67,7 @@ func ReadCursor(
 		}
 		return nil, err
 	}
+	cursor.PinConnection(conn)
 
 	return cursor, nil
 }
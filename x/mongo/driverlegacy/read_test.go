@@ -0,0 +1,146 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driverlegacy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
+	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/connection"
+	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+type testDeployment bool
+
+func (td testDeployment) SupportsSessions() bool { return bool(td) }
+
+type testConnection struct {
+	closed bool
+}
+
+func (tc *testConnection) WriteWireMessage(context.Context, wiremessage.WireMessage) error {
+	return nil
+}
+func (tc *testConnection) ReadWireMessage(context.Context) (wiremessage.WireMessage, error) {
+	return nil, nil
+}
+func (tc *testConnection) Close() error  { tc.closed = true; return nil }
+func (tc *testConnection) Expired() bool { return false }
+func (tc *testConnection) Alive() bool   { return true }
+func (tc *testConnection) ID() string    { return "test" }
+
+type testReadServer struct {
+	wireVersion *description.VersionRange
+	conn        *testConnection
+	connErr     error
+}
+
+func (ts *testReadServer) Description() description.SelectedServer {
+	return description.SelectedServer{Server: description.Server{WireVersion: ts.wireVersion}}
+}
+
+func (ts *testReadServer) Connection(context.Context) (connection.Connection, error) {
+	if ts.connErr != nil {
+		return nil, ts.connErr
+	}
+	return ts.conn, nil
+}
+
+func TestShouldRetryRead(t *testing.T) {
+	retryable := command.Error{Code: 91, Message: "shutdown in progress"}
+	supported := description.SelectedServer{Server: description.Server{WireVersion: &description.VersionRange{Max: 6}}}
+	unsupported := description.SelectedServer{Server: description.Server{WireVersion: &description.VersionRange{Max: 5}}}
+
+	id, err := uuid.New()
+	require.NoError(t, err)
+	txn, err := session.NewClientSession(session.NewPool(nil), id, session.Explicit)
+	require.NoError(t, err)
+	require.NoError(t, txn.StartTransaction(nil))
+
+	testCases := []struct {
+		name  string
+		topo  testDeployment
+		desc  description.SelectedServer
+		sess  *session.Client
+		err   error
+		retry bool
+	}{
+		{"retryable error", true, supported, nil, retryable, true},
+		{"network error", true, supported, nil, command.Error{Labels: []string{command.NetworkError}}, true},
+		{"non-retryable error", true, supported, nil, command.Error{Code: 2, Message: "bad value"}, false},
+		{"non-command error", true, supported, nil, errors.New("boom"), false},
+		{"sessions not supported by deployment", false, supported, nil, retryable, false},
+		{"sessions not supported by server", true, unsupported, nil, retryable, false},
+		{"transaction", true, supported, txn, retryable, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.retry, shouldRetryRead(tc.topo, tc.desc, tc.sess, tc.err))
+		})
+	}
+}
+
+func TestRetryRead(t *testing.T) {
+	first := errors.New("first attempt")
+	retryErr := command.Error{Code: 91, Message: "shutdown in progress"}
+
+	t.Run("retries once", func(t *testing.T) {
+		server := &testReadServer{wireVersion: &description.VersionRange{Max: 6}, conn: &testConnection{}}
+		var selections, roundTrips int
+		selectServer := func(context.Context) (readServer, error) {
+			selections++
+			return server, nil
+		}
+		roundTrip := func(desc description.SelectedServer, conn connection.Connection) error {
+			roundTrips++
+			require.True(t, conn == server.conn)
+			return retryErr
+		}
+
+		ss, err := retryRead(context.Background(), testDeployment(true), selectServer, nil, roundTrip)
+		require.True(t, ss == server)
+		require.Equal(t, retryErr, err)
+		require.Equal(t, 1, selections)
+		require.Equal(t, 1, roundTrips)
+		require.True(t, server.conn.closed)
+	})
+
+	testCases := []struct {
+		name      string
+		server    *testReadServer
+		selectErr error
+	}{
+		{"selection fails", nil, first},
+		{"selected server does not support sessions", &testReadServer{wireVersion: &description.VersionRange{Max: 5}}, nil},
+		{"connection fails", &testReadServer{wireVersion: &description.VersionRange{Max: 6}, connErr: first}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			selectServer := func(context.Context) (readServer, error) {
+				if tc.selectErr != nil {
+					return nil, tc.selectErr
+				}
+				return tc.server, nil
+			}
+			roundTrip := func(description.SelectedServer, connection.Connection) error {
+				t.Fatal("read should not be executed again")
+				return nil
+			}
+
+			ss, err := retryRead(context.Background(), testDeployment(true), selectServer, nil, roundTrip)
+			require.Nil(t, ss)
+			require.NoError(t, err)
+		})
+	}
+}
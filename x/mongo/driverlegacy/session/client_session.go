@@ -8,13 +8,17 @@ package session // import "go.mongodb.org/mongo-driver/x/mongo/driverlegacy/sess
 
 import (
 	"errors"
+	"sync/atomic"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/x/bsonx"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
+	"go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/description"
 )
 
@@ -39,6 +43,13 @@ var ErrCommitAfterAbort = errors.New("cannot call commitTransaction after callin
 // ErrUnackWCUnsupported is returned if an unacknowledged write concern is supported for a transaciton.
 var ErrUnackWCUnsupported = errors.New("transactions do not support unacknowledged write concerns")
 
+// ErrSnapshotTransaction is returned if startTransaction() is called on a snapshot session.
+var ErrSnapshotTransaction = errors.New("transactions are not supported in snapshot sessions")
+
+// ErrTransactionReadConcernLevel is returned if a transaction is started with a read concern level
+// other than local, majority or snapshot.
+var ErrTransactionReadConcernLevel = errors.New("transactions only support the local, majority and snapshot read concern levels")
+
 // Type describes the type of the session
 type Type uint8
 
@@ -74,6 +85,11 @@ type Client struct {
 	Aborting       bool
 	RetryWrite     bool
 
+	// Snapshot is true if reads in the session use the snapshot read concern. SnapshotTime is the
+	// cluster time of the snapshot, set from the first snapshot read's response.
+	Snapshot     bool
+	SnapshotTime *primitive.Timestamp
+
 	// options for the current transaction
 	// most recently set by transactionopt
 	CurrentRc *readconcern.ReadConcern
@@ -89,6 +105,13 @@ type Client struct {
 	state         state
 	PinnedServer  *description.Server
 	RecoveryToken bson.Raw
+
+	// PinnedConnection is the connection the commands of the current transaction are sent on when
+	// connected through a load balancer.
+	PinnedConnection connection.Connection
+
+	opCount   int64
+	auditHook func(Operation)
 }
 
 func getClusterTime(clusterTime bson.Raw) (uint32, uint32) {
@@ -140,6 +163,11 @@ func NewClientSession(pool *Pool, clientID uuid.UUID, sessionType Type, opts ...
 	if mergedOpts.CausalConsistency != nil {
 		c.Consistent = *mergedOpts.CausalConsistency
 	}
+	if mergedOpts.Snapshot != nil && *mergedOpts.Snapshot {
+		// snapshot reads are consistent with each other, so causal consistency is not used
+		c.Snapshot = true
+		c.Consistent = false
+	}
 	if mergedOpts.DefaultReadPreference != nil {
 		c.transactionRp = mergedOpts.DefaultReadPreference
 	}
@@ -149,6 +177,7 @@ func NewClientSession(pool *Pool, clientID uuid.UUID, sessionType Type, opts ...
 	if mergedOpts.DefaultWriteConcern != nil {
 		c.transactionWc = mergedOpts.DefaultWriteConcern
 	}
+	c.auditHook = mergedOpts.AuditHook
 
 	servSess, err := pool.GetSession()
 	if err != nil {
@@ -160,6 +189,18 @@ func NewClientSession(pool *Pool, clientID uuid.UUID, sessionType Type, opts ...
 	return c, nil
 }
 
+// NewClientSessionFromID creates an implicit Client for the existing server session with the given
+// ID, such as the session of a cursor handed off by another process. The server session is not
+// taken from a pool and is not returned to one when the Client ends.
+func NewClientSessionFromID(clientID uuid.UUID, sessionID bsonx.Doc) *Client {
+	return &Client{
+		Server:      &Server{SessionID: sessionID, LastUsed: time.Now()},
+		Consistent:  true,
+		ClientID:    clientID,
+		SessionType: Implicit,
+	}
+}
+
 // AdvanceClusterTime updates the session's cluster time.
 func (c *Client) AdvanceClusterTime(clusterTime bson.Raw) error {
 	if c.Terminated {
@@ -199,6 +240,25 @@ func (c *Client) UpdateUseTime() error {
 	return nil
 }
 
+// OperationCount returns the number of commands that have been sent in the session.
+func (c *Client) OperationCount() int64 {
+	return c.opCount
+}
+
+// RecordOperation counts a command sent in the session and reports it to the audit hook of the
+// session, if there is one.
+func (c *Client) RecordOperation(namespace, commandName string) {
+	c.opCount++
+	if c.auditHook != nil {
+		c.auditHook(Operation{
+			Number:      c.opCount,
+			Namespace:   namespace,
+			CommandName: commandName,
+			Time:        time.Now(),
+		})
+	}
+}
+
 // UpdateRecoveryToken updates the session's recovery token from the server response.
 func (c *Client) UpdateRecoveryToken(response bson.Raw) {
 	if c == nil {
@@ -213,10 +273,50 @@ func (c *Client) UpdateRecoveryToken(response bson.Raw) {
 	c.RecoveryToken = token.Document()
 }
 
-// ClearPinnedServer sets the PinnedServer to nil.
+// UpdateSnapshotTime sets the cluster time of a snapshot session's snapshot from the server response
+// if it has not been set yet. The time is taken from the atClusterTime field of the response's
+// cursor or, for commands without a cursor, of the response itself.
+func (c *Client) UpdateSnapshotTime(response bson.Raw) {
+	if c == nil || !c.Snapshot || c.SnapshotTime != nil {
+		return
+	}
+
+	val, err := response.LookupErr("cursor", "atClusterTime")
+	if err != nil {
+		val, err = response.LookupErr("atClusterTime")
+	}
+	if err != nil {
+		return
+	}
+	t, i, ok := val.TimestampOK()
+	if !ok {
+		return
+	}
+
+	c.SnapshotTime = &primitive.Timestamp{T: t, I: i}
+}
+
+// ClearPinnedServer sets the PinnedServer to nil and unpins the PinnedConnection.
 func (c *Client) ClearPinnedServer() {
 	if c != nil {
 		c.PinnedServer = nil
+		c.UnpinConnection()
+	}
+}
+
+// MarkDirty marks the server session of the client as dirty after an operation using it hit a
+// network error, so that it is discarded when the session ends.
+func (c *Client) MarkDirty() {
+	if c != nil && c.Server != nil {
+		c.Server.MarkDirty()
+	}
+}
+
+// UnpinConnection closes the PinnedConnection, if any, and sets it to nil.
+func (c *Client) UnpinConnection() {
+	if c != nil && c.PinnedConnection != nil {
+		_ = c.PinnedConnection.Close()
+		c.PinnedConnection = nil
 	}
 }
 
@@ -227,11 +327,30 @@ func (c *Client) EndSession() {
 	}
 
 	c.Terminated = true
-	c.pool.ReturnSession(c.Server)
+	c.finishTransaction()
+	c.UnpinConnection()
+	if c.pool != nil {
+		c.pool.ReturnSession(c.Server)
+	}
 
 	return
 }
 
+// Detach ends the session without returning its server session to the pool, because the server
+// session is still used elsewhere, such as by a cursor handed off to another process.
+func (c *Client) Detach() {
+	if c.Terminated {
+		return
+	}
+
+	c.Terminated = true
+	c.finishTransaction()
+	c.UnpinConnection()
+	if c.pool != nil {
+		c.pool.detachSession()
+	}
+}
+
 // TransactionInProgress returns true if the client session is in an active transaction.
 func (c *Client) TransactionInProgress() bool {
 	return c.state == InProgress
@@ -256,6 +375,9 @@ func (c *Client) TransactionCommitted() bool {
 // CheckStartTransaction checks to see if allowed to start transaction and returns
 // an error if not allowed
 func (c *Client) CheckStartTransaction() error {
+	if c.Snapshot {
+		return ErrSnapshotTransaction
+	}
 	if c.state == InProgress || c.state == Starting {
 		return ErrTransactInProgress
 	}
@@ -296,8 +418,20 @@ func (c *Client) StartTransaction(opts *TransactionOptions) error {
 		return ErrUnackWCUnsupported
 	}
 
+	if c.CurrentRc != nil {
+		switch c.CurrentRc.GetLevel() {
+		case "linearizable", "available":
+			c.clearTransactionOpts()
+			return ErrTransactionReadConcernLevel
+		}
+	}
+
 	c.state = Starting
+	if c.pool != nil {
+		atomic.AddInt32(&c.pool.transactions, 1)
+	}
 	c.PinnedServer = nil
+	c.UnpinConnection()
 	return nil
 }
 
@@ -319,6 +453,7 @@ func (c *Client) CommitTransaction() error {
 	if err != nil {
 		return err
 	}
+	c.finishTransaction()
 	c.state = Committed
 	return nil
 }
@@ -343,11 +478,20 @@ func (c *Client) AbortTransaction() error {
 	if err != nil {
 		return err
 	}
+	c.finishTransaction()
 	c.state = Aborted
 	c.clearTransactionOpts()
 	return nil
 }
 
+// finishTransaction removes the transaction of the session from the open transactions of the pool
+// if it is running.
+func (c *Client) finishTransaction() {
+	if c.TransactionRunning() && c.pool != nil {
+		atomic.AddInt32(&c.pool.transactions, -1)
+	}
+}
+
 // ApplyCommand advances the state machine upon command execution.
 func (c *Client) ApplyCommand(desc description.Server) {
 	if c.Committing {
@@ -374,5 +518,6 @@ func (c *Client) clearTransactionOpts() {
 	c.CurrentRp = nil
 	c.CurrentRc = nil
 	c.PinnedServer = nil
+	c.UnpinConnection()
 	c.RecoveryToken = nil
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/internal/testutil/helpers"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
 	"go.mongodb.org/mongo-driver/x/network/description"
@@ -192,4 +193,66 @@ func TestClientSession(t *testing.T) {
 			t.Errorf("expected error, got %v", err)
 		}
 	})
+	t.Run("TestTransactionReadConcernLevel", func(t *testing.T) {
+		id, _ := uuid.New()
+		sess, err := NewClientSession(&Pool{}, id, Explicit, sessionOpts)
+		require.Nil(t, err, "Unexpected error")
+
+		for _, rc := range []*readconcern.ReadConcern{readconcern.Linearizable(), readconcern.Available()} {
+			err = sess.StartTransaction(&TransactionOptions{ReadConcern: rc})
+			require.Equal(t, ErrTransactionReadConcernLevel, err)
+			require.False(t, sess.TransactionStarting())
+			require.Nil(t, sess.CurrentRc)
+		}
+		for _, rc := range []*readconcern.ReadConcern{readconcern.Local(), readconcern.Majority(), readconcern.Snapshot()} {
+			require.Nil(t, sess.StartTransaction(&TransactionOptions{ReadConcern: rc}))
+			require.Nil(t, sess.AbortTransaction())
+		}
+	})
+	t.Run("TestSnapshot", func(t *testing.T) {
+		snapshot := true
+		id, _ := uuid.New()
+		sess, err := NewClientSession(&Pool{}, id, Explicit, sessionOpts, &ClientOptions{Snapshot: &snapshot})
+		require.Nil(t, err, "Unexpected error")
+		require.True(t, sess.Snapshot)
+		require.False(t, sess.Consistent)
+		require.Equal(t, ErrSnapshotTransaction, sess.StartTransaction(nil))
+
+		sess.UpdateSnapshotTime(bsoncore.BuildDocument(nil, bsoncore.AppendInt32Element(nil, "n", 1)))
+		require.Nil(t, sess.SnapshotTime)
+
+		sess.UpdateSnapshotTime(bsoncore.BuildDocument(nil, bsoncore.AppendTimestampElement(nil, "atClusterTime", 10, 5)))
+		compareOperationTimes(t, &primitive.Timestamp{T: 10, I: 5}, sess.SnapshotTime)
+
+		// the snapshot time is chosen by the first read and does not change afterwards
+		sess.UpdateSnapshotTime(bsoncore.BuildDocument(nil, bsoncore.AppendTimestampElement(nil, "atClusterTime", 20, 1)))
+		compareOperationTimes(t, &primitive.Timestamp{T: 10, I: 5}, sess.SnapshotTime)
+	})
+	t.Run("TestOpenTransactions", func(t *testing.T) {
+		pool := &Pool{}
+		id, _ := uuid.New()
+		sess1, err := NewClientSession(pool, id, Explicit, sessionOpts)
+		require.Nil(t, err, "Unexpected error")
+		sess2, err := NewClientSession(pool, id, Explicit, sessionOpts)
+		require.Nil(t, err, "Unexpected error")
+
+		require.Nil(t, sess1.StartTransaction(nil))
+		require.Nil(t, sess2.StartTransaction(nil))
+		require.Equal(t, 2, pool.OpenTransactions())
+
+		require.Nil(t, sess1.CommitTransaction())
+		require.Equal(t, 1, pool.OpenTransactions())
+		// retrying the commit does not finish the transaction again
+		require.Nil(t, sess1.CommitTransaction())
+		require.Equal(t, 1, pool.OpenTransactions())
+
+		sess2.EndSession()
+		require.Equal(t, 0, pool.OpenTransactions())
+
+		require.Nil(t, sess1.StartTransaction(nil))
+		require.Equal(t, 1, pool.OpenTransactions())
+		require.Nil(t, sess1.AbortTransaction())
+		require.Equal(t, 0, pool.OpenTransactions())
+		sess1.EndSession()
+	})
 }
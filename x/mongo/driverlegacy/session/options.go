@@ -7,6 +7,8 @@
 package session
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
@@ -18,6 +20,17 @@ type ClientOptions struct {
 	DefaultReadConcern    *readconcern.ReadConcern
 	DefaultWriteConcern   *writeconcern.WriteConcern
 	DefaultReadPreference *readpref.ReadPref
+	Snapshot              *bool
+	AuditHook             func(Operation)
+}
+
+// Operation describes a command sent in a session. Number is the position of the command among
+// those sent in the session, starting at 1.
+type Operation struct {
+	Number      int64
+	Namespace   string
+	CommandName string
+	Time        time.Time
 }
 
 // TransactionOptions represents all possible options for starting a transaction in a session.
@@ -45,6 +58,12 @@ func mergeClientOptions(opts ...*ClientOptions) *ClientOptions {
 		if opt.DefaultWriteConcern != nil {
 			c.DefaultWriteConcern = opt.DefaultWriteConcern
 		}
+		if opt.Snapshot != nil {
+			c.Snapshot = opt.Snapshot
+		}
+		if opt.AuditHook != nil {
+			c.AuditHook = opt.AuditHook
+		}
 	}
 
 	return c
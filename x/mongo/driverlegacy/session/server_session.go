@@ -22,6 +22,7 @@ type Server struct {
 	SessionID bsonx.Doc
 	TxnNumber int64
 	LastUsed  time.Time
+	Dirty     bool // whether an operation using the session hit a network error
 }
 
 // returns whether or not a session has expired given a timeout in minutes
@@ -54,6 +55,12 @@ func newServerSession() (*Server, error) {
 	}, nil
 }
 
+// MarkDirty marks the session as dirty. A dirty session is discarded instead of being returned to
+// the pool, because the server may still run its last command or transaction in an unknown state.
+func (ss *Server) MarkDirty() {
+	ss.Dirty = true
+}
+
 // IncrementTxnNumber increments the transaction number.
 func (ss *Server) IncrementTxnNumber() {
 	ss.TxnNumber++
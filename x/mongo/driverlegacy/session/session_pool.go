@@ -8,6 +8,7 @@ package session
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"go.mongodb.org/mongo-driver/x/bsonx"
 	"go.mongodb.org/mongo-driver/x/network/description"
@@ -28,7 +29,8 @@ type Pool struct {
 	timeout  uint32
 	mutex    sync.Mutex // mutex to protect list and sessionTimeout
 
-	checkedOut int // number of sessions checked out of pool
+	checkedOut   int   // number of sessions checked out of pool
+	transactions int32 // number of transactions started but not committed or aborted, accessed atomically
 }
 
 func (p *Pool) createServerSession() (*Server, error) {
@@ -99,7 +101,7 @@ func (p *Pool) GetSession() (*Server, error) {
 	return p.createServerSession()
 }
 
-// ReturnSession returns a session to the pool if it has not expired.
+// ReturnSession returns a session to the pool if it has not expired and is not dirty.
 func (p *Pool) ReturnSession(ss *Server) {
 	if ss == nil {
 		return
@@ -119,8 +121,8 @@ func (p *Pool) ReturnSession(ss *Server) {
 		p.tail = p.tail.prev
 	}
 
-	// session expired
-	if ss.expired(p.timeout) {
+	// session expired, or used by an operation that hit a network error
+	if ss.Dirty || ss.expired(p.timeout) {
 		return
 	}
 
@@ -143,6 +145,14 @@ func (p *Pool) ReturnSession(ss *Server) {
 	p.head = newNode
 }
 
+// detachSession records that a checked out session will not be returned to the pool.
+func (p *Pool) detachSession() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.checkedOut--
+}
+
 // IDSlice returns a slice of session IDs for each session in the pool
 func (p *Pool) IDSlice() []bsonx.Doc {
 	p.mutex.Lock()
@@ -173,3 +183,12 @@ func (p *Pool) String() string {
 func (p *Pool) CheckedOut() int {
 	return p.checkedOut
 }
+
+// OpenTransactions returns the number of transactions of the sessions using the pool that have been
+// started and have not been committed, aborted, or ended with their session yet.
+func (p *Pool) OpenTransactions() int {
+	if p == nil {
+		return 0
+	}
+	return int(atomic.LoadInt32(&p.transactions))
+}
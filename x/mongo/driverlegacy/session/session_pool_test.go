@@ -44,6 +44,24 @@ func TestSessionPool(t *testing.T) {
 		}
 	})
 
+	t.Run("TestDirtyDiscarded", func(t *testing.T) {
+		descChan := make(chan description.Topology)
+		p := NewPool(descChan)
+		p.timeout = 30
+
+		first, err := p.GetSession()
+		testhelpers.RequireNil(t, err, "error getting session %s", err)
+		first.MarkDirty()
+		p.ReturnSession(first)
+
+		if len(p.IDSlice()) != 0 {
+			t.Errorf("dirty session was returned to the pool")
+		}
+		if p.CheckedOut() != 0 {
+			t.Errorf("checked out count mismatch. got %d expected 0", p.CheckedOut())
+		}
+	})
+
 	t.Run("TestExpiredRemoved", func(t *testing.T) {
 		descChan := make(chan description.Topology)
 		p := NewPool(descChan)
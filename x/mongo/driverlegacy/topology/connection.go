@@ -19,7 +19,10 @@ import (
 
 	"strings"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/auth"
 	"go.mongodb.org/mongo-driver/x/network/address"
 	"go.mongodb.org/mongo-driver/x/network/command"
 	connectionlegacy "go.mongodb.org/mongo-driver/x/network/connection"
@@ -325,31 +328,281 @@ func (c *Connection) Address() address.Address {
 // error is returned, the pool on the server can be cleared.
 type sconn struct {
 	connectionlegacy.Connection
-	s  *Server
-	id uint64
+	s        *Server
+	id       uint64
+	gateDone func(error)
+	limiter  *OperationLimiter
+	inUse    bool // whether the connection is counted as checked out by the server
+
+	interceptDone func(bson.Raw, error)
+	span          event.Span
+	sent          wiremessage.WireMessage // the last command sent, retried after reauthentication
 }
 
 var notMasterCodes = []int32{10107, 13435}
 var recoveringCodes = []int32{11600, 11602, 13436, 189, 91}
 
+const reauthenticationRequiredCode = 391
+
 func (sc *sconn) ReadWireMessage(ctx context.Context) (wiremessage.WireMessage, error) {
 	wm, err := sc.Connection.ReadWireMessage(ctx)
+	if sent := sc.sent; err == nil && sent != nil {
+		sc.sent = nil
+		if isReauthenticationRequired(wm) {
+			wm, err = sc.reauthenticate(ctx, sent)
+		}
+	}
 	if err != nil {
 		sc.processErr(err)
+		sc.finishGate(err)
+		sc.finishIntercept(nil, err)
+		sc.finishSpan(err)
 	} else {
 		e := command.DecodeError(wm)
 		sc.processErr(e)
+		sc.finishGate(e)
+		sc.finishIntercept(replyDocument(wm), e)
+		sc.finishSpan(e)
 	}
+	sc.releaseSlot()
 	return wm, err
 }
 
 func (sc *sconn) WriteWireMessage(ctx context.Context, wm wiremessage.WireMessage) error {
+	if sc.s.cfg.readOnly {
+		if name := writeCommand(wm); name != "" {
+			return command.ReadOnlyError{Command: name}
+		}
+	}
+	if filter := sc.s.cfg.nsFilter; filter != nil {
+		name, namespaces := commandNamespaces(wm)
+		for _, ns := range namespaces {
+			if !filter(ns.db, ns.coll) {
+				return command.NamespaceDeniedError{Namespace: ns.db + "." + ns.coll, Command: name}
+			}
+		}
+	}
+	if limiter := sc.s.cfg.opLimiter; limiter != nil {
+		// A command that was not replied to, such as an unacknowledged write, no longer holds its slot.
+		sc.releaseSlot()
+		if err := limiter.acquire(ctx); err != nil {
+			return err
+		}
+		sc.limiter = limiter
+	}
+	if gate := sc.s.cfg.operationGate; gate != nil {
+		db, cmd := commandInfo(wm)
+		done, err := gate(ctx, sc.s.Description(), db, cmd)
+		if err != nil {
+			sc.releaseSlot()
+			return err
+		}
+		sc.finishGate(nil)
+		sc.gateDone = done
+	}
+	if intercept := sc.s.cfg.cmdInterceptor; intercept != nil {
+		var err error
+		wm, err = sc.interceptCommand(ctx, intercept, wm)
+		if err != nil {
+			sc.finishGate(err)
+			sc.releaseSlot()
+			return err
+		}
+	}
+	if tracer := sc.s.cfg.tracer; tracer != nil {
+		sc.finishSpan(nil)
+		name, attrs := commandAttributes(wm, sc.s.address)
+		ctx, sc.span = startSpan(ctx, tracer, name, attrs...)
+	}
+	if _, ok := sc.s.cfg.authenticator.(auth.Reauthenticator); ok {
+		sc.sent = wm
+	}
 	err := sc.Connection.WriteWireMessage(ctx, wm)
 	sc.processErr(err)
+	if err != nil {
+		sc.finishGate(err)
+		sc.finishIntercept(nil, err)
+		sc.finishSpan(err)
+		sc.releaseSlot()
+	}
 	return err
 }
 
+func (sc *sconn) Close() error {
+	// Commands that are not replied to, such as unacknowledged writes, are finished when the
+	// connection is returned.
+	sc.finishGate(nil)
+	sc.finishIntercept(nil, nil)
+	sc.finishSpan(nil)
+	sc.releaseSlot()
+	if sc.inUse {
+		sc.inUse = false
+		sc.s.checkIn()
+	}
+	return sc.Connection.Close()
+}
+
+// reauthenticate authenticates the connection again after the server required it to run the
+// command sent, and sends the command again. The reply to the retried command is returned.
+func (sc *sconn) reauthenticate(ctx context.Context, sent wiremessage.WireMessage) (wiremessage.WireMessage, error) {
+	ra := sc.s.cfg.authenticator.(auth.Reauthenticator)
+	if err := ra.Reauth(ctx, sc.s.Description(), sc.Connection); err != nil {
+		return nil, err
+	}
+	if err := sc.Connection.WriteWireMessage(ctx, sent); err != nil {
+		return nil, err
+	}
+	return sc.Connection.ReadWireMessage(ctx)
+}
+
+// isReauthenticationRequired returns true if wm is the reply to a command the server requires the
+// connection to be authenticated again to run.
+func isReauthenticationRequired(wm wiremessage.WireMessage) bool {
+	reply := replyDocument(wm)
+	if reply == nil {
+		return false
+	}
+	code, err := reply.LookupErr("code")
+	if err != nil {
+		return false
+	}
+	c, ok := code.Int32OK()
+	return ok && c == reauthenticationRequiredCode
+}
+
+// interceptCommand passes the command in wm to the command interceptor if it is sent with OP_MSG,
+// and returns the message to send instead.
+func (sc *sconn) interceptCommand(ctx context.Context, intercept CommandInterceptor, wm wiremessage.WireMessage) (wiremessage.WireMessage, error) {
+	msg, ok := wm.(wiremessage.Msg)
+	if !ok {
+		return wm, nil
+	}
+	for i, section := range msg.Sections {
+		body, ok := section.(wiremessage.SectionBody)
+		if !ok {
+			continue
+		}
+		db, _ := body.Document.Lookup("$db").StringValueOK()
+		cmd, done, err := intercept(ctx, sc.s.Description(), db, body.Document)
+		if err != nil {
+			return nil, err
+		}
+		sc.finishIntercept(nil, nil)
+		sc.interceptDone = done
+		if cmd != nil {
+			// Copy the sections so that the message of the caller is not modified.
+			sections := make([]wiremessage.Section, len(msg.Sections))
+			copy(sections, msg.Sections)
+			sections[i] = wiremessage.SectionBody{PayloadType: body.PayloadType, Document: cmd}
+			msg.Sections = sections
+		}
+		return msg, nil
+	}
+	return wm, nil
+}
+
+// finishIntercept reports the reply to the last intercepted command to the command interceptor.
+func (sc *sconn) finishIntercept(reply bson.Raw, err error) {
+	if sc.interceptDone != nil {
+		done := sc.interceptDone
+		sc.interceptDone = nil
+		done(reply, err)
+	}
+}
+
+// replyDocument returns the reply document in wm, or nil if there is none.
+func replyDocument(wm wiremessage.WireMessage) bson.Raw {
+	switch m := wm.(type) {
+	case wiremessage.Msg:
+		for _, section := range m.Sections {
+			if body, ok := section.(wiremessage.SectionBody); ok {
+				return body.Document
+			}
+		}
+	case wiremessage.Reply:
+		if len(m.Documents) > 0 {
+			return m.Documents[0]
+		}
+	}
+	return nil
+}
+
+// finishSpan ends the span of the last command sent, if any.
+func (sc *sconn) finishSpan(err error) {
+	if sc.span != nil {
+		span := sc.span
+		sc.span = nil
+		span.End(err)
+	}
+}
+
+// finishGate reports the outcome of the last command sent to the operation gate.
+func (sc *sconn) finishGate(err error) {
+	if sc.gateDone != nil {
+		done := sc.gateDone
+		sc.gateDone = nil
+		done(err)
+	}
+}
+
+// releaseSlot releases the operation limiter slot held by the command in flight, if any.
+func (sc *sconn) releaseSlot() {
+	if sc.limiter != nil {
+		sc.limiter.release()
+		sc.limiter = nil
+	}
+}
+
+// commandInfo returns the database and name of the command in wm, for the operation gate.
+func commandInfo(wm wiremessage.WireMessage) (string, string) {
+	switch m := wm.(type) {
+	case wiremessage.Msg:
+		for _, section := range m.Sections {
+			if body, ok := section.(wiremessage.SectionBody); ok {
+				db, _ := body.Document.Lookup("$db").StringValueOK()
+				return db, firstKey(body.Document)
+			}
+		}
+	case wiremessage.Query:
+		db := databaseName(m.FullCollectionName)
+		if !strings.HasSuffix(m.FullCollectionName, ".$cmd") {
+			return db, "find"
+		}
+		query := m.Query
+		if inner, ok := query.Lookup("$query").DocumentOK(); ok {
+			query = inner
+		}
+		return db, firstKey(query)
+	case wiremessage.GetMore:
+		return databaseName(m.FullCollectionName), "getMore"
+	case wiremessage.KillCursors:
+		return m.DatabaseName, "killCursors"
+	}
+	return "", ""
+}
+
+func databaseName(fullCollectionName string) string {
+	if i := strings.Index(fullCollectionName, "."); i >= 0 {
+		return fullCollectionName[:i]
+	}
+	return fullCollectionName
+}
+
+func firstKey(doc bson.Raw) string {
+	elem, err := doc.IndexErr(0)
+	if err != nil {
+		return ""
+	}
+	return elem.Key()
+}
+
 func (sc *sconn) processErr(err error) {
+	// A load balancer is never marked unknown because it is not monitored and could not be
+	// selected again.
+	if sc.s.cfg.loadBalanced {
+		return
+	}
+
 	// Invalidate server description if not master or node recovering error occurs
 	if cerr, ok := err.(command.Error); ok && (isRecoveringError(cerr) || isNotMasterError(cerr)) {
 		desc := sc.s.Description()
@@ -408,6 +661,10 @@ func configureTLS(ctx context.Context, nc net.Conn, addr address.Address, config
 		}
 
 		hostname = hostname[:colonPos]
+		if addr.Network() == "unix" {
+			// The certificates of servers listening on unix domain sockets are verified as local ones.
+			hostname = "localhost"
+		}
 		config.ServerName = hostname
 	}
 
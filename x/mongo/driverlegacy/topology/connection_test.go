@@ -16,8 +16,13 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
 	"go.mongodb.org/mongo-driver/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/auth"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
 	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/command"
 	connectionlegacy "go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/description"
 	"go.mongodb.org/mongo-driver/x/network/wiremessage"
@@ -75,7 +80,7 @@ func TestConnectionProcessErrSpec(t *testing.T) {
 	innerErr := netErr{}
 	connectErr := connectionlegacy.Error{ConnectionID: "blah", Wrapped: innerErr}
 	c := connect{&connectErr}
-	sc := sconn{c, s, 1}
+	sc := sconn{Connection: c, s: s, id: 1}
 	err = sc.WriteWireMessage(ctx, nil)
 	require.NotNil(t, err)
 	desc = s.Description()
@@ -83,6 +88,328 @@ func TestConnectionProcessErrSpec(t *testing.T) {
 	require.Equal(t, desc.Kind, (description.ServerKind)(description.Unknown))
 }
 
+type writeOK struct {
+	connect
+}
+
+func (writeOK) WriteWireMessage(ctx context.Context, wm wiremessage.WireMessage) error {
+	return nil
+}
+
+func TestOperationGate(t *testing.T) {
+	ctx := context.Background()
+	rejected := errors.New("rejected")
+	var entered []string
+	var finished []error
+	gate := func(ctx context.Context, desc description.Server, database, command string) (func(error), error) {
+		entered = append(entered, database+"."+command)
+		if command == "drop" {
+			return nil, rejected
+		}
+		return func(err error) { finished = append(finished, err) }, nil
+	}
+	s, err := NewServer(address.Address("localhost"), nil, WithOperationGate(func(OperationGate) OperationGate { return gate }))
+	require.NoError(t, err)
+	s.connectionstate = connected
+
+	msg := func(command string) wiremessage.WireMessage {
+		doc := bsoncore.BuildDocument(nil, bsoncore.AppendStringElement(
+			bsoncore.AppendInt32Element(nil, command, 1), "$db", "test"))
+		return wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: bson.Raw(doc)}}}
+	}
+
+	sc := &sconn{Connection: writeOK{}, s: s, id: 1}
+	require.Equal(t, rejected, sc.WriteWireMessage(ctx, msg("drop")))
+	require.NoError(t, sc.WriteWireMessage(ctx, msg("insert")))
+	require.Len(t, finished, 0)
+	require.NoError(t, sc.Close())
+	require.Equal(t, []error{nil}, finished)
+
+	connectErr := connectionlegacy.Error{ConnectionID: "blah", Wrapped: netErr{}}
+	sc = &sconn{Connection: connect{&connectErr}, s: s, id: 2}
+	require.Equal(t, connectErr, sc.WriteWireMessage(ctx, msg("find")))
+	require.Equal(t, []error{nil, connectErr}, finished)
+	require.Equal(t, []string{"test.drop", "test.insert", "test.find"}, entered)
+}
+
+// scriptedConn records the messages written to it and replies with the queued replies in order.
+type scriptedConn struct {
+	connect
+	written []wiremessage.WireMessage
+	replies []wiremessage.WireMessage
+}
+
+func (c *scriptedConn) WriteWireMessage(ctx context.Context, wm wiremessage.WireMessage) error {
+	c.written = append(c.written, wm)
+	return nil
+}
+
+func (c *scriptedConn) ReadWireMessage(ctx context.Context) (wiremessage.WireMessage, error) {
+	wm := c.replies[0]
+	c.replies = c.replies[1:]
+	return wm, nil
+}
+
+type countingReauthenticator struct {
+	reauths int
+}
+
+func (a *countingReauthenticator) Auth(context.Context, description.Server, wiremessage.ReadWriter) error {
+	return nil
+}
+
+func (a *countingReauthenticator) Reauth(context.Context, description.Server, wiremessage.ReadWriter) error {
+	a.reauths++
+	return nil
+}
+
+func TestConnectionReauthentication(t *testing.T) {
+	ctx := context.Background()
+	authenticator := &countingReauthenticator{}
+	s, err := NewServer(address.Address("localhost"), nil,
+		WithAuthenticator(func(auth.Authenticator) auth.Authenticator { return authenticator }))
+	require.NoError(t, err)
+	s.connectionstate = connected
+
+	reply := func(doc bsoncore.Document) wiremessage.WireMessage {
+		return wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: bson.Raw(doc)}}}
+	}
+	reauthRequired := reply(bsoncore.BuildDocument(nil, bsoncore.AppendInt32Element(
+		bsoncore.AppendInt32Element(bsoncore.AppendDoubleElement(nil, "ok", 0), "code", 391),
+		"n", 0)))
+	ok := reply(bsoncore.BuildDocument(nil, bsoncore.AppendDoubleElement(nil, "ok", 1)))
+	find := reply(bsoncore.BuildDocument(nil, bsoncore.AppendStringElement(
+		bsoncore.AppendInt32Element(nil, "find", 1), "$db", "test")))
+
+	conn := &scriptedConn{replies: []wiremessage.WireMessage{reauthRequired, ok}}
+	sc := &sconn{Connection: conn, s: s, id: 1}
+	require.NoError(t, sc.WriteWireMessage(ctx, find))
+	wm, err := sc.ReadWireMessage(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ok, wm)
+	require.Equal(t, 1, authenticator.reauths)
+	require.Equal(t, []wiremessage.WireMessage{find, find}, conn.written)
+
+	// The command is only retried once.
+	conn.replies = []wiremessage.WireMessage{reauthRequired, reauthRequired}
+	require.NoError(t, sc.WriteWireMessage(ctx, find))
+	_, err = sc.ReadWireMessage(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, authenticator.reauths)
+	require.Len(t, conn.replies, 0)
+}
+
+func TestOperationLimiter(t *testing.T) {
+	limiter := NewOperationLimiter(1)
+	s, err := NewServer(address.Address("localhost"), nil,
+		WithOperationLimiter(func(*OperationLimiter) *OperationLimiter { return limiter }))
+	require.NoError(t, err)
+	s.connectionstate = connected
+
+	first := &sconn{Connection: writeOK{}, s: s, id: 1}
+	require.NoError(t, first.WriteWireMessage(context.Background(), nil))
+
+	t.Run("context done while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		sc := &sconn{Connection: writeOK{}, s: s, id: 2}
+		err := sc.WriteWireMessage(ctx, nil)
+		require.Equal(t, command.OperationLimitError{Limit: 1, Wrapped: context.DeadlineExceeded}, err)
+	})
+	t.Run("count documents returns the limit error", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		sess := &session.Client{Server: &session.Server{}}
+		cmd := command.CountDocuments{NS: command.Namespace{DB: "db", Collection: "coll"}, Session: sess}
+		desc := description.SelectedServer{Server: description.Server{WireVersion: &description.VersionRange{Max: 8}}}
+		_, err := cmd.RoundTrip(ctx, desc, &sconn{Connection: writeOK{}, s: s, id: 5})
+		require.Equal(t, command.OperationLimitError{Limit: 1, Wrapped: context.DeadlineExceeded}, err)
+		require.False(t, sess.Server.Dirty, "the session should not be marked dirty when nothing was sent")
+	})
+	t.Run("waits for a slot", func(t *testing.T) {
+		sc := &sconn{Connection: writeOK{}, s: s, id: 3}
+		errs := make(chan error, 1)
+		go func() { errs <- sc.WriteWireMessage(context.Background(), nil) }()
+
+		select {
+		case <-errs:
+			t.Fatal("command should wait for the slot of the first command")
+		case <-time.After(10 * time.Millisecond):
+		}
+		require.NoError(t, first.Close())
+		require.NoError(t, <-errs)
+		require.NoError(t, sc.Close())
+	})
+	t.Run("failed writes release their slot", func(t *testing.T) {
+		connectErr := connectionlegacy.Error{ConnectionID: "blah", Wrapped: netErr{}}
+		sc := &sconn{Connection: connect{&connectErr}, s: s, id: 4}
+		require.Equal(t, connectErr, sc.WriteWireMessage(context.Background(), nil))
+		require.Len(t, limiter.slots, 0)
+	})
+}
+
+func TestReadOnly(t *testing.T) {
+	s, err := NewServer(address.Address("localhost"), nil, WithReadOnly(func(bool) bool { return true }))
+	require.NoError(t, err)
+	s.connectionstate = connected
+	sc := &sconn{Connection: writeOK{}, s: s, id: 1}
+
+	msg := func(cmd bson.D) wiremessage.WireMessage {
+		doc, err := bson.Marshal(cmd)
+		require.NoError(t, err)
+		return wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: doc}}}
+	}
+	inline := bson.D{{"inline", 1}}
+	testCases := []struct {
+		name    string
+		wm      wiremessage.WireMessage
+		command string
+	}{
+		{"find", msg(bson.D{{"find", "coll"}}), ""},
+		{"insert", msg(bson.D{{"insert", "coll"}}), "insert"},
+		{"dropDatabase", msg(bson.D{{"dropDatabase", 1}}), "dropDatabase"},
+		{"aggregate", msg(bson.D{{"aggregate", "coll"}, {"pipeline", bson.A{bson.D{{"$match", bson.D{}}}}}}), ""},
+		{"aggregate with $out", msg(bson.D{{"aggregate", "coll"}, {"pipeline", bson.A{bson.D{{"$out", "other"}}}}}), "aggregate"},
+		{"inline mapReduce", msg(bson.D{{"mapReduce", "coll"}, {"out", inline}}), ""},
+		{"mapReduce", msg(bson.D{{"mapReduce", "coll"}, {"out", "other"}}), "mapReduce"},
+		{"OP_INSERT", wiremessage.Insert{FullCollectionName: "db.coll"}, "insert"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := sc.WriteWireMessage(context.Background(), tc.wm)
+			if tc.command == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, command.ReadOnlyError{Command: tc.command}, err)
+		})
+	}
+}
+
+func TestNamespaceFilter(t *testing.T) {
+	msg := func(cmd bson.D) wiremessage.WireMessage {
+		doc, err := bson.Marshal(append(cmd, bson.E{"$db", "db"}))
+		require.NoError(t, err)
+		return wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: doc}}}
+	}
+
+	t.Run("namespaces", func(t *testing.T) {
+		lookup := bson.D{{"$lookup", bson.D{{"from", "other"}, {"pipeline", bson.A{bson.D{{"$unionWith", "third"}}}}}}}
+		out := bson.D{{"$merge", bson.D{{"into", bson.D{{"db", "reports"}, {"coll", "daily"}}}}}}
+		testCases := []struct {
+			name       string
+			wm         wiremessage.WireMessage
+			command    string
+			namespaces []namespace
+		}{
+			{"find", msg(bson.D{{"find", "coll"}}), "find", []namespace{{"db", "coll"}}},
+			{"database command", msg(bson.D{{"dropDatabase", 1}}), "dropDatabase", []namespace{{"db", "*"}}},
+			{"getMore", msg(bson.D{{"getMore", int64(1)}, {"collection", "coll"}}), "getMore", []namespace{{"db", "coll"}}},
+			{"endSessions", msg(bson.D{{"endSessions", bson.A{}}}), "endSessions", nil},
+			{
+				"renameCollection",
+				msg(bson.D{{"renameCollection", "db.a"}, {"to", "other.b"}}),
+				"renameCollection",
+				[]namespace{{"db", "a"}, {"other", "b"}},
+			},
+			{
+				"aggregate",
+				msg(bson.D{{"aggregate", "coll"}, {"pipeline", bson.A{lookup, out}}}),
+				"aggregate",
+				[]namespace{{"db", "coll"}, {"db", "other"}, {"db", "third"}, {"reports", "daily"}},
+			},
+			{"OP_QUERY find", wiremessage.Query{FullCollectionName: "db.coll"}, "find", []namespace{{"db", "coll"}}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				name, namespaces := commandNamespaces(tc.wm)
+				require.Equal(t, tc.command, name)
+				require.Equal(t, tc.namespaces, namespaces)
+			})
+		}
+	})
+	t.Run("rejected", func(t *testing.T) {
+		filter := func(database, collection string) bool { return collection != "secret" }
+		s, err := NewServer(address.Address("localhost"), nil, WithNamespaceFilter(func(NamespaceFilter) NamespaceFilter { return filter }))
+		require.NoError(t, err)
+		s.connectionstate = connected
+		sc := &sconn{Connection: writeOK{}, s: s, id: 1}
+
+		require.NoError(t, sc.WriteWireMessage(context.Background(), msg(bson.D{{"find", "coll"}})))
+		err = sc.WriteWireMessage(context.Background(), msg(bson.D{{"aggregate", "coll"}, {"pipeline", bson.A{bson.D{{"$out", "secret"}}}}}))
+		require.Equal(t, command.NamespaceDeniedError{Namespace: "db.secret", Command: "aggregate"}, err)
+	})
+}
+
+type recordingConn struct {
+	connect
+	written wiremessage.WireMessage
+	reply   wiremessage.WireMessage
+}
+
+func (rc *recordingConn) WriteWireMessage(ctx context.Context, wm wiremessage.WireMessage) error {
+	rc.written = wm
+	return nil
+}
+
+func (rc *recordingConn) ReadWireMessage(ctx context.Context) (wiremessage.WireMessage, error) {
+	return rc.reply, nil
+}
+
+func TestCommandInterceptor(t *testing.T) {
+	ctx := context.Background()
+	rejected := errors.New("rejected")
+	comment := bsoncore.BuildDocument(nil, bsoncore.AppendStringElement(
+		bsoncore.AppendInt32Element(nil, "find", 1), "comment", "intercepted"))
+	var replies []bson.Raw
+	var errs []error
+	intercept := func(ctx context.Context, desc description.Server, database string, cmd bson.Raw) (bson.Raw, func(bson.Raw, error), error) {
+		require.Equal(t, "test", database)
+		done := func(reply bson.Raw, err error) {
+			replies = append(replies, reply)
+			errs = append(errs, err)
+		}
+		switch firstKey(cmd) {
+		case "drop":
+			return nil, nil, rejected
+		case "find":
+			return bson.Raw(comment), done, nil
+		}
+		return nil, done, nil
+	}
+	s, err := NewServer(address.Address("localhost"), nil,
+		WithCommandInterceptor(func(CommandInterceptor) CommandInterceptor { return intercept }))
+	require.NoError(t, err)
+	s.connectionstate = connected
+
+	msg := func(command string) wiremessage.Msg {
+		doc := bsoncore.BuildDocument(nil, bsoncore.AppendStringElement(
+			bsoncore.AppendInt32Element(nil, command, 1), "$db", "test"))
+		return wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: bson.Raw(doc)}}}
+	}
+	reply := bsoncore.BuildDocument(nil, bsoncore.AppendInt32Element(nil, "ok", 1))
+	conn := &recordingConn{reply: wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: bson.Raw(reply)}}}}
+	sc := &sconn{Connection: conn, s: s, id: 1}
+
+	require.Equal(t, rejected, sc.WriteWireMessage(ctx, msg("drop")))
+	require.Nil(t, conn.written)
+
+	find := msg("find")
+	require.NoError(t, sc.WriteWireMessage(ctx, find))
+	require.Equal(t, bson.Raw(comment), replyDocument(conn.written))
+	require.Equal(t, "find", firstKey(find.Sections[0].(wiremessage.SectionBody).Document), "message of the caller was modified")
+	_, err = sc.ReadWireMessage(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []bson.Raw{bson.Raw(reply)}, replies)
+
+	insert := msg("insert")
+	require.NoError(t, sc.WriteWireMessage(ctx, insert))
+	require.Equal(t, insert, conn.written)
+	require.NoError(t, sc.Close())
+	require.Equal(t, []bson.Raw{bson.Raw(reply), nil}, replies)
+	require.Equal(t, []error{nil, nil}, errs)
+}
+
 func TestConnection(t *testing.T) {
 	t.Run("connection", func(t *testing.T) {
 		t.Run("newConnection", func(t *testing.T) {
@@ -1,6 +1,10 @@
 package topology
 
-import "fmt"
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/x/network/description"
+)
 
 // ConnectionError represents a connection error.
 type ConnectionError struct {
@@ -17,3 +21,27 @@ func (e ConnectionError) Error() string {
 	}
 	return fmt.Sprintf("connection(%s) %s", e.ConnectionID, e.message)
 }
+
+// Unwrap returns the underlying error.
+func (e ConnectionError) Unwrap() error {
+	return e.Wrapped
+}
+
+// ServerSelectionError represents a failure to select a server, either because none became suitable
+// before the server selection timeout or because the selector returned an error.
+type ServerSelectionError struct {
+	Desc    description.Topology // The topology description when selection failed.
+	Wrapped error
+
+	topology string
+}
+
+// Error implements the error interface.
+func (e ServerSelectionError) Error() string {
+	return fmt.Sprintf("server selection error: %v\ncurrent topology: %s", e.Wrapped, e.topology)
+}
+
+// Unwrap returns the underlying error.
+func (e ServerSelectionError) Unwrap() error {
+	return e.Wrapped
+}
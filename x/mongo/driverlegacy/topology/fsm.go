@@ -99,6 +99,8 @@ func (f *fsm) apply(s description.Server) (description.Topology, error) {
 		f.applyToReplicaSetWithPrimary(s)
 	case description.Single:
 		f.applyToSingle(s)
+	case description.LoadBalanced:
+		f.replaceServer(s)
 	}
 
 	return f.Topology, nil
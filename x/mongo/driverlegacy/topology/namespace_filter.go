@@ -0,0 +1,185 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+// unscopedCommands are the commands that do not read or modify the data of a namespace, such as the
+// commands that end sessions and transactions, and are therefore not checked by namespace filters.
+var unscopedCommands = map[string]bool{
+	"abortTransaction":  true,
+	"buildInfo":         true,
+	"buildinfo":         true,
+	"commitTransaction": true,
+	"endSessions":       true,
+	"getLastError":      true,
+	"hello":             true,
+	"isMaster":          true,
+	"ismaster":          true,
+	"ping":              true,
+}
+
+// namespace is a database and a collection in it. The collection is "*" for namespaces that
+// denote a whole database.
+type namespace struct {
+	db, coll string
+}
+
+// commandNamespaces returns the name of the command sent by wm and the namespaces it operates on.
+// These are the namespace the command is run on, the target of renameCollection commands and the
+// collections that aggregation pipelines look up, union with or write to. It returns no namespaces
+// for commands that do not operate on a namespace.
+func commandNamespaces(wm wiremessage.WireMessage) (string, []namespace) {
+	var db string
+	var cmd bson.Raw
+	switch m := wm.(type) {
+	case wiremessage.Insert:
+		return "insert", []namespace{splitNamespace(m.FullCollectionName)}
+	case wiremessage.Update:
+		return "update", []namespace{splitNamespace(m.FullCollectionName)}
+	case wiremessage.Delete:
+		return "delete", []namespace{splitNamespace(m.FullCollectionName)}
+	case wiremessage.GetMore:
+		return "getMore", []namespace{splitNamespace(m.FullCollectionName)}
+	case wiremessage.Msg:
+		for _, section := range m.Sections {
+			if body, ok := section.(wiremessage.SectionBody); ok {
+				cmd = body.Document
+				db, _ = cmd.Lookup("$db").StringValueOK()
+				break
+			}
+		}
+	case wiremessage.Query:
+		ns := splitNamespace(m.FullCollectionName)
+		if ns.coll != "$cmd" {
+			return "find", []namespace{ns}
+		}
+		db = ns.db
+		cmd = m.Query
+		if inner, ok := cmd.Lookup("$query").DocumentOK(); ok {
+			cmd = inner
+		}
+	default:
+		// Killing cursors by ID with OP_KILL_CURSORS does not expose their namespace.
+		return "", nil
+	}
+
+	elem, err := cmd.IndexErr(0)
+	if err != nil {
+		return "", nil
+	}
+	name := elem.Key()
+	if unscopedCommands[name] {
+		return name, nil
+	}
+
+	switch name {
+	case "getMore":
+		coll, _ := cmd.Lookup("collection").StringValueOK()
+		return name, []namespace{{db, coll}}
+	case "renameCollection":
+		from, _ := elem.Value().StringValueOK()
+		to, _ := cmd.Lookup("to").StringValueOK()
+		return name, []namespace{splitNamespace(from), splitNamespace(to)}
+	}
+
+	ns := namespace{db, "*"}
+	if coll, ok := elem.Value().StringValueOK(); ok {
+		ns.coll = coll
+	}
+	namespaces := []namespace{ns}
+	if name == "aggregate" {
+		pipeline, _ := cmd.Lookup("pipeline").ArrayOK()
+		namespaces = pipelineNamespaces(db, pipeline, namespaces)
+	}
+	return name, namespaces
+}
+
+// pipelineNamespaces appends the namespaces referenced by the stages of pipeline, run on the
+// database db, to namespaces.
+func pipelineNamespaces(db string, pipeline bson.Raw, namespaces []namespace) []namespace {
+	stages, err := pipeline.Values()
+	if err != nil {
+		return namespaces
+	}
+	for _, stageVal := range stages {
+		stage, ok := stageVal.DocumentOK()
+		if !ok {
+			continue
+		}
+		elem, err := stage.IndexErr(0)
+		if err != nil {
+			continue
+		}
+		val := elem.Value()
+		switch elem.Key() {
+		case "$lookup", "$graphLookup":
+			spec, _ := val.DocumentOK()
+			if from, ok := spec.Lookup("from").StringValueOK(); ok {
+				namespaces = append(namespaces, namespace{db, from})
+			}
+			if sub, ok := spec.Lookup("pipeline").ArrayOK(); ok {
+				namespaces = pipelineNamespaces(db, sub, namespaces)
+			}
+		case "$unionWith":
+			if coll, ok := val.StringValueOK(); ok {
+				namespaces = append(namespaces, namespace{db, coll})
+				continue
+			}
+			spec, _ := val.DocumentOK()
+			if coll, ok := spec.Lookup("coll").StringValueOK(); ok {
+				namespaces = append(namespaces, namespace{db, coll})
+			}
+			if sub, ok := spec.Lookup("pipeline").ArrayOK(); ok {
+				namespaces = pipelineNamespaces(db, sub, namespaces)
+			}
+		case "$facet":
+			spec, _ := val.DocumentOK()
+			facets, _ := spec.Elements()
+			for _, facet := range facets {
+				if sub, ok := facet.Value().ArrayOK(); ok {
+					namespaces = pipelineNamespaces(db, sub, namespaces)
+				}
+			}
+		case "$out":
+			namespaces = append(namespaces, outputNamespace(db, val))
+		case "$merge":
+			into := val
+			if spec, ok := val.DocumentOK(); ok {
+				into = spec.Lookup("into")
+			}
+			namespaces = append(namespaces, outputNamespace(db, into))
+		}
+	}
+	return namespaces
+}
+
+// outputNamespace returns the namespace written to by an $out or $merge stage, which is either a
+// collection name in db or a document with the database and collection.
+func outputNamespace(db string, val bson.RawValue) namespace {
+	if coll, ok := val.StringValueOK(); ok {
+		return namespace{db, coll}
+	}
+	spec, _ := val.DocumentOK()
+	if outDB, ok := spec.Lookup("db").StringValueOK(); ok {
+		db = outDB
+	}
+	coll, _ := spec.Lookup("coll").StringValueOK()
+	return namespace{db, coll}
+}
+
+func splitNamespace(ns string) namespace {
+	if i := strings.Index(ns, "."); i >= 0 {
+		return namespace{ns[:i], ns[i+1:]}
+	}
+	return namespace{ns, ""}
+}
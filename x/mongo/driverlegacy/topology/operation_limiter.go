@@ -0,0 +1,45 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/x/network/command"
+)
+
+// OperationLimiter limits the number of commands that are in flight at once. A single limiter can be
+// shared by all the servers of a topology. Commands that exceed the limit wait in line for a slot
+// until their context is done.
+type OperationLimiter struct {
+	slots chan struct{}
+}
+
+// NewOperationLimiter creates an OperationLimiter that allows max commands in flight at once.
+func NewOperationLimiter(max int) *OperationLimiter {
+	return &OperationLimiter{slots: make(chan struct{}, max)}
+}
+
+// acquire waits for a free slot. If ctx is done first, a command.OperationLimitError is returned.
+func (l *OperationLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return command.OperationLimitError{Limit: cap(l.slots), Wrapped: ctx.Err()}
+	}
+}
+
+func (l *OperationLimiter) release() {
+	<-l.slots
+}
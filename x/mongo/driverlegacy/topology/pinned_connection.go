@@ -0,0 +1,53 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"sync/atomic"
+
+	connectionlegacy "go.mongodb.org/mongo-driver/x/network/connection"
+)
+
+// pinnableConn is a reference to a connection to a load balancer. Behind a load balancer, the
+// getMore commands of a cursor and the commands of a transaction must be sent on the connection the
+// cursor or transaction was started on, so such a connection can be shared by several references
+// created by PinConnection. It is returned to its pool when every reference to it is closed.
+type pinnableConn struct {
+	*sconn
+	refs   *int32
+	closed int32
+}
+
+func newPinnableConn(sc *sconn) *pinnableConn {
+	refs := int32(1)
+	return &pinnableConn{sconn: sc, refs: &refs}
+}
+
+// Close closes this reference to the connection. Closing a reference more than once has no effect.
+func (pc *pinnableConn) Close() error {
+	if !atomic.CompareAndSwapInt32(&pc.closed, 0, 1) {
+		return nil
+	}
+	if atomic.AddInt32(pc.refs, -1) > 0 {
+		return nil
+	}
+	return pc.sconn.Close()
+}
+
+// PinConnection returns a new reference to conn, which must be open, for a cursor or transaction
+// that must send all of its commands on the same connection. conn is returned to its pool only once
+// it and every reference returned by PinConnection have been closed. PinConnection returns nil if
+// conn is not a connection to a load balancer, because connections to other servers never need to be
+// pinned.
+func PinConnection(conn connectionlegacy.Connection) connectionlegacy.Connection {
+	pc, ok := conn.(*pinnableConn)
+	if !ok {
+		return nil
+	}
+	atomic.AddInt32(pc.refs, 1)
+	return &pinnableConn{sconn: pc.sconn, refs: pc.refs}
+}
@@ -0,0 +1,46 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	connectionlegacy "go.mongodb.org/mongo-driver/x/network/connection"
+)
+
+type closeCounter struct {
+	connectionlegacy.Connection
+	closed int
+}
+
+func (cc *closeCounter) Close() error {
+	cc.closed++
+	return nil
+}
+
+func TestPinConnection(t *testing.T) {
+	t.Run("not a load balancer", func(t *testing.T) {
+		require.Nil(t, PinConnection(&sconn{Connection: &closeCounter{}}))
+	})
+	t.Run("closed with the last reference", func(t *testing.T) {
+		cc := &closeCounter{}
+		conn := newPinnableConn(&sconn{Connection: cc})
+		pinned := PinConnection(conn)
+		require.NotNil(t, pinned)
+		again := PinConnection(pinned)
+		require.NotNil(t, again)
+
+		require.NoError(t, conn.Close())
+		require.NoError(t, conn.Close())
+		require.NoError(t, pinned.Close())
+		require.Equal(t, 0, cc.closed)
+
+		require.NoError(t, again.Close())
+		require.Equal(t, 1, cc.closed)
+	})
+}
@@ -266,3 +266,53 @@ func TestPollSRVRecords(t *testing.T) {
 		_ = topo.Disconnect(context.Background())
 	})
 }
+
+func TestProcessSRVResultsMaxHosts(t *testing.T) {
+	topo, err := New(
+		WithSeedList(func(...string) []string { return []string{"localhost:27017"} }),
+		WithSRVMaxHosts(func(int) int { return 2 }),
+	)
+	require.NoError(t, err, "Could not create the topology: %v", err)
+	err = topo.Connect(context.Background())
+	require.NoError(t, err, "Could not connect to the topology: %v", err)
+	defer func() { _ = topo.Disconnect(context.Background()) }()
+
+	require.True(t, topo.processSRVResults([]string{"localhost:27017", "localhost:27018", "localhost:27019"}))
+	servers := topo.Description().Servers
+	require.Len(t, servers, 2)
+	found := false
+	for _, s := range servers {
+		found = found || s.Addr == address.Address("localhost:27017").Canonicalize()
+	}
+	require.True(t, found, "existing server was removed: %v", servers)
+}
+
+func TestProcessSRVResultsRemovalThreshold(t *testing.T) {
+	topo, err := New(
+		WithSeedList(func(...string) []string { return []string{"localhost:27017", "localhost:27018"} }),
+		WithSRVRemovalThreshold(func(int) int { return 2 }),
+	)
+	require.NoError(t, err, "Could not create the topology: %v", err)
+	err = topo.Connect(context.Background())
+	require.NoError(t, err, "Could not connect to the topology: %v", err)
+	defer func() { _ = topo.Disconnect(context.Background()) }()
+	servers := func() []address.Address {
+		topo.serversLock.Lock()
+		defer topo.serversLock.Unlock()
+		var addrs []address.Address
+		for addr := range topo.servers {
+			addrs = append(addrs, addr)
+		}
+		return addrs
+	}
+
+	// A host missing from a single poll is kept, and its count is reset when it reappears.
+	require.True(t, topo.processSRVResults([]string{"localhost:27017"}))
+	require.Len(t, servers(), 2)
+	require.True(t, topo.processSRVResults([]string{"localhost:27017", "localhost:27018"}))
+	require.True(t, topo.processSRVResults([]string{"localhost:27017"}))
+	require.Len(t, servers(), 2)
+
+	require.True(t, topo.processSRVResults([]string{"localhost:27017"}))
+	require.Equal(t, []address.Address{address.Address("localhost:27017").Canonicalize()}, servers())
+}
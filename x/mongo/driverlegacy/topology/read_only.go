@@ -0,0 +1,119 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+// writeCommands are the commands that modify data, indexes, collections, databases, users or roles.
+var writeCommands = map[string]bool{
+	"applyOps":                 true,
+	"cloneCollectionAsCapped":  true,
+	"collMod":                  true,
+	"compact":                  true,
+	"convertToCapped":          true,
+	"create":                   true,
+	"createIndexes":            true,
+	"createRole":               true,
+	"createSearchIndexes":      true,
+	"createUser":               true,
+	"delete":                   true,
+	"deleteIndexes":            true,
+	"drop":                     true,
+	"dropAllRolesFromDatabase": true,
+	"dropAllUsersFromDatabase": true,
+	"dropDatabase":             true,
+	"dropIndexes":              true,
+	"dropRole":                 true,
+	"dropSearchIndex":          true,
+	"dropUser":                 true,
+	"findAndModify":            true,
+	"findandmodify":            true,
+	"grantPrivilegesToRole":    true,
+	"grantRolesToRole":         true,
+	"grantRolesToUser":         true,
+	"insert":                   true,
+	"reIndex":                  true,
+	"renameCollection":         true,
+	"revokePrivilegesFromRole": true,
+	"revokeRolesFromRole":      true,
+	"revokeRolesFromUser":      true,
+	"update":                   true,
+	"updateRole":               true,
+	"updateSearchIndex":        true,
+	"updateUser":               true,
+}
+
+// writeCommand returns the name of the command sent by wm if it may modify data, or an empty string
+// otherwise. Aggregations are writes if they end with an $out or $merge stage, and mapReduce
+// commands are writes unless their results are returned inline.
+func writeCommand(wm wiremessage.WireMessage) string {
+	var cmd bson.Raw
+	switch m := wm.(type) {
+	case wiremessage.Insert:
+		return "insert"
+	case wiremessage.Update:
+		return "update"
+	case wiremessage.Delete:
+		return "delete"
+	case wiremessage.Msg:
+		for _, section := range m.Sections {
+			if body, ok := section.(wiremessage.SectionBody); ok {
+				cmd = body.Document
+				break
+			}
+		}
+	case wiremessage.Query:
+		cmd = m.Query
+		if inner, ok := cmd.Lookup("$query").DocumentOK(); ok {
+			cmd = inner
+		}
+	}
+
+	name := firstKey(cmd)
+	switch name {
+	case "":
+		return ""
+	case "aggregate":
+		if outputsToCollection(cmd) {
+			return name
+		}
+		return ""
+	case "mapReduce", "mapreduce":
+		if out, ok := cmd.Lookup("out").DocumentOK(); ok {
+			if _, err := out.LookupErr("inline"); err == nil {
+				return ""
+			}
+		}
+		return name
+	}
+	if writeCommands[name] {
+		return name
+	}
+	return ""
+}
+
+// outputsToCollection returns true if the pipeline of the aggregate command cmd ends with an $out or
+// $merge stage.
+func outputsToCollection(cmd bson.Raw) bool {
+	pipeline, ok := cmd.Lookup("pipeline").ArrayOK()
+	if !ok {
+		return false
+	}
+	stages, err := pipeline.Values()
+	if err != nil || len(stages) == 0 {
+		return false
+	}
+	stage, ok := stages[len(stages)-1].DocumentOK()
+	if !ok {
+		return false
+	}
+	last := firstKey(stage)
+	return last == "$out" || last == "$merge"
+}
@@ -0,0 +1,88 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import "sync"
+
+// RetryKind is the kind of operation that was retried.
+type RetryKind int
+
+// These constants are the kinds of operations that can be retried.
+const (
+	RetryRead RetryKind = iota
+	RetryWrite
+)
+
+// RetryCounts are the numbers of retries of one kind of operation. Retried is the number of
+// operations that were executed a second time, Succeeded and Failed are the numbers of those second
+// attempts that succeeded and failed, and ErrorCodes is the number of retries by the code of the
+// error that triggered them. Errors without a code, such as network errors, are counted under 0.
+type RetryCounts struct {
+	Retried    uint64
+	Succeeded  uint64
+	Failed     uint64
+	ErrorCodes map[int32]uint64
+}
+
+// RetryStats are the retry counts of the reads and writes executed against a topology.
+type RetryStats struct {
+	Reads  RetryCounts
+	Writes RetryCounts
+}
+
+type retryStats struct {
+	sync.Mutex
+	reads  RetryCounts
+	writes RetryCounts
+}
+
+func (rs *retryStats) record(kind RetryKind, code int32, err error) {
+	rs.Lock()
+	defer rs.Unlock()
+
+	counts := &rs.reads
+	if kind == RetryWrite {
+		counts = &rs.writes
+	}
+	counts.Retried++
+	if err == nil {
+		counts.Succeeded++
+	} else {
+		counts.Failed++
+	}
+	if counts.ErrorCodes == nil {
+		counts.ErrorCodes = make(map[int32]uint64)
+	}
+	counts.ErrorCodes[code]++
+}
+
+func (rs *retryStats) snapshot() RetryStats {
+	rs.Lock()
+	defer rs.Unlock()
+
+	return RetryStats{Reads: copyRetryCounts(rs.reads), Writes: copyRetryCounts(rs.writes)}
+}
+
+func copyRetryCounts(rc RetryCounts) RetryCounts {
+	codes := make(map[int32]uint64, len(rc.ErrorCodes))
+	for code, n := range rc.ErrorCodes {
+		codes[code] = n
+	}
+	rc.ErrorCodes = codes
+	return rc
+}
+
+// RecordRetry records that an operation of the given kind was executed a second time because its
+// first attempt failed with an error with the given code. err is the error of the second attempt.
+func (t *Topology) RecordRetry(kind RetryKind, code int32, err error) {
+	t.retries.record(kind, code, err)
+}
+
+// RetryStats returns the retry counts of the operations executed against the topology.
+func (t *Topology) RetryStats() RetryStats {
+	return t.retries.snapshot()
+}
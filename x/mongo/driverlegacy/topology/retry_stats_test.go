@@ -0,0 +1,49 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryStats(t *testing.T) {
+	topo, err := New()
+	require.NoError(t, err)
+	require.Equal(t, RetryStats{
+		Reads:  RetryCounts{ErrorCodes: map[int32]uint64{}},
+		Writes: RetryCounts{ErrorCodes: map[int32]uint64{}},
+	}, topo.RetryStats())
+
+	failed := errors.New("retry failed")
+	topo.RecordRetry(RetryRead, 91, nil)
+	topo.RecordRetry(RetryRead, 0, failed)
+	topo.RecordRetry(RetryWrite, 10107, nil)
+	topo.RecordRetry(RetryWrite, 10107, nil)
+	topo.RecordRetry(RetryWrite, 189, failed)
+
+	stats := topo.RetryStats()
+	require.Equal(t, RetryStats{
+		Reads: RetryCounts{
+			Retried:    2,
+			Succeeded:  1,
+			Failed:     1,
+			ErrorCodes: map[int32]uint64{91: 1, 0: 1},
+		},
+		Writes: RetryCounts{
+			Retried:    3,
+			Succeeded:  2,
+			Failed:     1,
+			ErrorCodes: map[int32]uint64{10107: 2, 189: 1},
+		},
+	}, stats)
+
+	stats.Writes.ErrorCodes[10107] = 0
+	require.Equal(t, uint64(2), topo.RetryStats().Writes.ErrorCodes[10107], "stats should be copied")
+}
@@ -46,6 +46,40 @@ type SelectedServer struct {
 	Kind description.TopologyKind
 }
 
+// checkIn records that an operation returned the connection it checked out.
+func (s *Server) checkIn() {
+	s.inUseLock.Lock()
+	defer s.inUseLock.Unlock()
+
+	s.inUse--
+	if s.inUse == 0 && s.idle != nil {
+		close(s.idle)
+		s.idle = nil
+	}
+}
+
+// waitIdle waits until the connections checked out by operations have been returned, or until
+// ctx is done.
+func (s *Server) waitIdle(ctx context.Context) error {
+	s.inUseLock.Lock()
+	if s.inUse == 0 {
+		s.inUseLock.Unlock()
+		return nil
+	}
+	if s.idle == nil {
+		s.idle = make(chan struct{})
+	}
+	idle := s.idle
+	s.inUseLock.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Description returns a description of the server as of the last heartbeat.
 func (ss *SelectedServer) Description() description.SelectedServer {
 	sdesc := ss.Server.Description()
@@ -91,8 +125,16 @@ type Server struct {
 
 	desc atomic.Value // holds a description.Server
 
+	cancelMonitor context.CancelFunc // cancels the checks of the monitoring goroutines
+
+	rttLock       sync.Mutex
 	averageRTTSet bool
 	averageRTT    time.Duration
+	rttSamples    []time.Duration // the last rttSamplesSize round trip times, oldest first
+
+	inUseLock sync.Mutex
+	inUse     int           // the number of connections checked out by operations
+	idle      chan struct{} // closed when inUse drops to zero
 
 	subLock             sync.Mutex
 	subscribers         map[uint64]chan description.Server
@@ -144,7 +186,10 @@ func NewServer(addr address.Address, topo func(description.Server), opts ...Serv
 		maxConns = uint64(cfg.maxConns)
 	}
 
-	s.pool, err = connectionlegacy.NewPool(addr, uint64(cfg.maxIdleConns), maxConns, cfg.connectionOpts...)
+	connOpts := append(append([]connectionlegacy.Option(nil), cfg.connectionOpts...), connectionlegacy.WithMinRTT(func(func() time.Duration) func() time.Duration {
+		return s.currentMinRTT
+	}))
+	s.pool, err = connectionlegacy.NewPool(addr, uint64(cfg.maxIdleConns), maxConns, connOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -158,8 +203,17 @@ func (s *Server) Connect(ctx context.Context) error {
 	if !atomic.CompareAndSwapInt32(&s.connectionstate, disconnected, connected) {
 		return ErrServerConnected
 	}
+	s.publishServerOpeningEvent()
+	if s.cfg.loadBalanced {
+		// A load balancer is not monitored. Its description is updated from the handshake of the
+		// first connection made to it.
+		s.desc.Store(description.Server{Addr: s.address, Kind: description.LoadBalancer})
+		return s.pool.Connect(ctx)
+	}
 	s.desc.Store(description.Server{Addr: s.address})
-	go s.update()
+	var monitorCtx context.Context
+	monitorCtx, s.cancelMonitor = context.WithCancel(context.Background())
+	go s.update(monitorCtx)
 	s.closewg.Add(1)
 	return s.pool.Connect(ctx)
 }
@@ -181,8 +235,12 @@ func (s *Server) Disconnect(ctx context.Context) error {
 	s.updateTopologyCallback.Store((func(description.Server))(nil))
 
 	// For every call to Connect there must be at least 1 goroutine that is
-	// waiting on the done channel.
-	s.done <- struct{}{}
+	// waiting on the done channel, except for load balancers, which are not monitored.
+	// A streaming check may be waiting for the server, so it is cancelled first.
+	if !s.cfg.loadBalanced {
+		s.cancelMonitor()
+		s.done <- struct{}{}
+	}
 	err := s.pool.Disconnect(ctx)
 	if err != nil {
 		return err
@@ -190,6 +248,7 @@ func (s *Server) Disconnect(ctx context.Context) error {
 
 	s.closewg.Wait()
 	atomic.StoreInt32(&s.connectionstate, disconnected)
+	s.publishServerClosedEvent()
 
 	return nil
 }
@@ -199,13 +258,21 @@ func (s *Server) Connection(ctx context.Context) (connectionlegacy.Connection, e
 	if atomic.LoadInt32(&s.connectionstate) != connected {
 		return nil, ErrServerClosed
 	}
-	conn, desc, err := s.pool.Get(ctx)
+	getCtx := ctx
+	var span event.Span
+	if s.cfg.tracer != nil {
+		getCtx, span = startSpan(ctx, s.cfg.tracer, event.SpanConnectionCheckOut, peerAttributes(s.address)...)
+	}
+	conn, desc, err := s.pool.Get(getCtx)
+	if span != nil {
+		span.End(err)
+	}
 	if err != nil {
 		if _, ok := err.(*auth.Error); ok {
 			// authentication error --> drain connection
 			_ = s.pool.Drain()
 		}
-		if _, ok := err.(*connectionlegacy.NetworkError); ok {
+		if _, ok := err.(*connectionlegacy.NetworkError); ok && !s.cfg.loadBalanced {
 			// update description to unknown and clears the connection pool
 			if desc != nil {
 				desc.Kind = description.Unknown
@@ -217,10 +284,21 @@ func (s *Server) Connection(ctx context.Context) (connectionlegacy.Connection, e
 		}
 		return nil, err
 	}
+	s.inUseLock.Lock()
+	s.inUse++
+	s.inUseLock.Unlock()
+	sc := &sconn{Connection: conn, s: s, inUse: true}
+	if s.cfg.loadBalanced {
+		// The description is updated before returning so that the first operation sees the wire
+		// version of the servers behind the load balancer.
+		if desc != nil && s.Description().WireVersion == nil {
+			s.updateDescription(*desc, false)
+		}
+		return newPinnableConn(sc), nil
+	}
 	if desc != nil {
 		go s.updateDescription(*desc, false)
 	}
-	sc := &sconn{Connection: conn, s: s}
 	return sc, nil
 }
 
@@ -280,7 +358,7 @@ func (s *Server) RequestImmediateCheck() {
 // ProcessWriteConcernError checks if a WriteConcernError is an isNotMaster or
 // isRecovering error, and if so updates the server accordingly.
 func (s *Server) ProcessWriteConcernError(err *result.WriteConcernError) {
-	if err == nil || !wceIsNotMasterOrRecovering(err) {
+	if err == nil || s.cfg.loadBalanced || !wceIsNotMasterOrRecovering(err) {
 		return
 	}
 	desc := s.Description()
@@ -306,7 +384,12 @@ func wceIsNotMasterOrRecovering(wce *result.WriteConcernError) bool {
 
 // update handles performing heartbeats and updating any subscribers of the
 // newest description.Server retrieved.
-func (s *Server) update() {
+//
+// Servers that report a topology version are monitored with the streaming protocol: an awaitable
+// isMaster is sent as soon as the previous one succeeded, and the server streams a reply whenever its
+// state changes, which detects failovers without waiting for the heartbeat interval. The round trip
+// time of these servers is measured by a separate goroutine. Older servers are polled.
+func (s *Server) update(ctx context.Context) {
 	defer s.closewg.Done()
 	heartbeatTicker := time.NewTicker(s.cfg.heartbeatInterval)
 	rateLimiter := time.NewTicker(minHeartbeatInterval)
@@ -328,9 +411,12 @@ func (s *Server) update() {
 
 	var conn connectionlegacy.Connection
 	var desc description.Server
+	var streaming, measuringRTT bool
 
-	desc, conn = s.heartbeat(nil)
-	s.updateDescription(desc, true)
+	desc, conn, streaming = s.heartbeat(ctx, nil, desc, false)
+	if ctx.Err() == nil {
+		s.updateDescription(desc, true)
+	}
 
 	closeServer := func() {
 		doneOnce = true
@@ -347,22 +433,34 @@ func (s *Server) update() {
 		conn.Close()
 	}
 	for {
-		select {
-		case <-heartbeatTicker.C:
-		case <-checkNow:
-		case <-done:
-			closeServer()
-			return
-		}
+		// The server waits before replying to an awaitable isMaster, so it is sent without waiting
+		// for the next heartbeat.
+		if awaitable := conn != nil && desc.TopologyVersion != nil; !awaitable || ctx.Err() != nil {
+			select {
+			case <-heartbeatTicker.C:
+			case <-checkNow:
+			case <-done:
+				closeServer()
+				return
+			}
 
-		select {
-		case <-rateLimiter.C:
-		case <-done:
-			closeServer()
-			return
+			select {
+			case <-rateLimiter.C:
+			case <-done:
+				closeServer()
+				return
+			}
+		} else if !measuringRTT {
+			measuringRTT = true
+			s.closewg.Add(1)
+			go s.measureRTT(ctx)
 		}
 
-		desc, conn = s.heartbeat(conn)
+		desc, conn, streaming = s.heartbeat(ctx, conn, desc, streaming)
+		if ctx.Err() != nil {
+			// The server is being disconnected.
+			continue
+		}
 		s.updateDescription(desc, false)
 	}
 }
@@ -376,7 +474,19 @@ func (s *Server) updateDescription(desc description.Server, initial bool) {
 		//  ¯\_(ツ)_/¯
 		_ = recover()
 	}()
+	prev := s.Description()
 	s.desc.Store(desc)
+	// The server event is published before the topology is updated so that it precedes the
+	// topology description changed event it causes. A panic while publishing must not prevent the
+	// topology and subscribers from being updated or the pool from being drained.
+	func() {
+		defer func() {
+			_ = recover()
+		}()
+		if !prev.Equal(desc) {
+			s.publishServerDescriptionChangedEvent(prev, desc)
+		}
+	}()
 
 	topo := s.updateTopologyCallback.Load().(func(description.Server))
 	if topo != nil {
@@ -406,13 +516,21 @@ func (s *Server) updateDescription(desc description.Server, initial bool) {
 }
 
 // heartbeat sends a heartbeat to the server using the given connection. The connection can be nil.
-func (s *Server) heartbeat(conn connectionlegacy.Connection) (description.Server, connectionlegacy.Connection) {
+// If prev, the description returned by the previous heartbeat, has a topology version, the
+// heartbeat is an awaitable isMaster. If streaming is true, the server streams the replies to an
+// awaitable isMaster on the connection, and the heartbeat reads the next one. The returned bool
+// reports whether the server streams the next reply.
+func (s *Server) heartbeat(
+	ctx context.Context,
+	conn connectionlegacy.Connection,
+	prev description.Server,
+	streaming bool,
+) (description.Server, connectionlegacy.Connection, bool) {
 	const maxRetry = 2
 	var saved error
 	var desc description.Server
 	var set bool
 	var err error
-	ctx := context.Background()
 
 	for i := 1; i <= maxRetry; i++ {
 		if conn != nil && conn.Expired() {
@@ -420,30 +538,16 @@ func (s *Server) heartbeat(conn connectionlegacy.Connection) (description.Server
 			conn = nil
 		}
 
+		// The first isMaster on a connection is never awaitable.
+		awaited := conn != nil && (streaming || prev.TopologyVersion != nil)
 		if conn == nil {
-			opts := []connectionlegacy.Option{
-				connectionlegacy.WithConnectTimeout(func(time.Duration) time.Duration { return s.cfg.heartbeatTimeout }),
-				connectionlegacy.WithReadTimeout(func(time.Duration) time.Duration { return s.cfg.heartbeatTimeout }),
-				connectionlegacy.WithWriteTimeout(func(time.Duration) time.Duration { return s.cfg.heartbeatTimeout }),
-			}
-			opts = append(opts, s.cfg.connectionOpts...)
-			// We override whatever handshaker is currently attached to the options with an empty
-			// one because need to make sure we don't do auth.
-			opts = append(opts, connectionlegacy.WithHandshaker(func(h connectionlegacy.Handshaker) connectionlegacy.Handshaker {
-				return nil
-			}))
-
-			// Override any command monitors specified in options with nil to avoid monitoring heartbeats.
-			opts = append(opts, connectionlegacy.WithMonitor(func(*event.CommandMonitor) *event.CommandMonitor {
-				return nil
-			}))
-			conn, _, err = connectionlegacy.New(ctx, s.address, opts...)
+			conn, err = s.newHeartbeatConnection(ctx)
 			if err != nil {
 				saved = err
-				if conn != nil {
-					conn.Close()
-				}
 				conn = nil
+				if ctx.Err() != nil {
+					break
+				}
 				if _, ok := err.(*connectionlegacy.NetworkError); ok {
 					_ = s.pool.Drain()
 					// If the server is not connected, give up and exit loop
@@ -456,14 +560,33 @@ func (s *Server) heartbeat(conn connectionlegacy.Connection) (description.Server
 		}
 
 		now := time.Now()
+		connID := conn.ID()
+		s.publishServerHeartbeatStartedEvent(connID, awaited)
 
+		var isMaster result.IsMaster
 		isMasterCmd := &command.IsMaster{Compressors: s.cfg.compressionOpts}
-		isMaster, err := isMasterCmd.RoundTrip(ctx, conn)
+		switch {
+		case awaited && streaming:
+			isMaster, err = isMasterCmd.Stream(ctx, conn)
+		case awaited:
+			isMasterCmd.TopologyVersion = prev.TopologyVersion
+			isMasterCmd.MaxAwaitTime = s.cfg.heartbeatInterval
+			isMaster, err = isMasterCmd.RoundTrip(ctx, conn)
+		default:
+			checkCtx, cancel := context.WithTimeout(ctx, s.cfg.heartbeatTimeout)
+			isMaster, err = isMasterCmd.RoundTrip(checkCtx, conn)
+			cancel()
+		}
+		streaming = err == nil && isMasterCmd.MoreToCome()
 		// we do a retry if the server is connected, if succeed return new server desc (see below)
 		if err != nil {
 			saved = err
 			conn.Close()
 			conn = nil
+			if ctx.Err() != nil {
+				break
+			}
+			s.publishServerHeartbeatFailedEvent(connID, time.Since(now), err, awaited)
 			if _, ok := err.(connectionlegacy.NetworkError); ok {
 				_ = s.pool.Drain()
 				// If the server is not connected, give up and exit loop
@@ -479,10 +602,20 @@ func (s *Server) heartbeat(conn connectionlegacy.Connection) (description.Server
 			s.cfg.clock.AdvanceClusterTime(clusterTime)
 		}
 
+		// An awaited isMaster takes as long as the server waits, so the round trip time of streamed
+		// servers is measured separately.
 		delay := time.Since(now)
-		desc = description.NewServer(s.address, isMaster).SetAverageRTT(s.updateAverageRTT(delay))
+		var rtt time.Duration
+		if awaited {
+			rtt = s.currentAverageRTT()
+		} else {
+			rtt = s.updateAverageRTT(delay)
+		}
+		desc = description.NewServer(s.address, isMaster).SetAverageRTT(rtt)
+		desc.MinRTT = s.currentMinRTT()
 		desc.HeartbeatInterval = s.cfg.heartbeatInterval
 		set = true
+		s.publishServerHeartbeatSucceededEvent(connID, delay, desc, awaited)
 
 		break
 	}
@@ -493,14 +626,100 @@ func (s *Server) heartbeat(conn connectionlegacy.Connection) (description.Server
 			LastError: saved,
 			Kind:      description.Unknown,
 		}
+		streaming = false
 	}
 
-	return desc, conn
+	return desc, conn, streaming
+}
+
+// newHeartbeatConnection creates a connection used to monitor the server. Its read timeout allows
+// for the heartbeat interval the server may wait before replying to an awaitable isMaster.
+func (s *Server) newHeartbeatConnection(ctx context.Context) (connectionlegacy.Connection, error) {
+	opts := []connectionlegacy.Option{
+		connectionlegacy.WithConnectTimeout(func(time.Duration) time.Duration { return s.cfg.heartbeatTimeout }),
+		connectionlegacy.WithReadTimeout(func(time.Duration) time.Duration {
+			return s.cfg.heartbeatTimeout + s.cfg.heartbeatInterval
+		}),
+		connectionlegacy.WithWriteTimeout(func(time.Duration) time.Duration { return s.cfg.heartbeatTimeout }),
+	}
+	opts = append(opts, s.cfg.connectionOpts...)
+	// We override whatever handshaker is currently attached to the options with an empty
+	// one because need to make sure we don't do auth.
+	opts = append(opts, connectionlegacy.WithHandshaker(func(h connectionlegacy.Handshaker) connectionlegacy.Handshaker {
+		return nil
+	}))
+
+	// Override any command monitors specified in options with nil to avoid monitoring heartbeats.
+	opts = append(opts, connectionlegacy.WithMonitor(func(*event.CommandMonitor) *event.CommandMonitor {
+		return nil
+	}))
+	// Heartbeats are never encrypted.
+	opts = append(opts, connectionlegacy.WithCrypt(func(connectionlegacy.Crypt) connectionlegacy.Crypt {
+		return nil
+	}))
+	conn, _, err := connectionlegacy.New(ctx, s.address, opts...)
+	if err != nil {
+		if conn != nil {
+			conn.Close()
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+// measureRTT measures the round trip time of a server monitored with the streaming protocol by
+// sending an isMaster on a separate connection every heartbeat interval until ctx is cancelled.
+func (s *Server) measureRTT(ctx context.Context) {
+	defer s.closewg.Done()
+	ticker := time.NewTicker(s.cfg.heartbeatInterval)
+	defer ticker.Stop()
+
+	var conn connectionlegacy.Connection
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+	for {
+		if conn == nil {
+			conn, _ = s.newHeartbeatConnection(ctx)
+		}
+		if conn != nil {
+			now := time.Now()
+			checkCtx, cancel := context.WithTimeout(ctx, s.cfg.heartbeatTimeout)
+			_, err := (&command.IsMaster{Compressors: s.cfg.compressionOpts}).RoundTrip(checkCtx, conn)
+			cancel()
+			if err != nil {
+				conn.Close()
+				conn = nil
+			} else {
+				s.updateAverageRTT(time.Since(now))
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
 }
 
+// rttSamplesSize is the number of round trip times the minimum round trip time is computed from.
+const rttSamplesSize = 10
+
+// updateAverageRTT records delay, a measured round trip time, and returns the exponentially
+// weighted moving average of the round trip times.
 func (s *Server) updateAverageRTT(delay time.Duration) time.Duration {
+	s.rttLock.Lock()
+	defer s.rttLock.Unlock()
+	if len(s.rttSamples) == rttSamplesSize {
+		s.rttSamples = append(s.rttSamples[:0], s.rttSamples[1:]...)
+	}
+	s.rttSamples = append(s.rttSamples, delay)
 	if !s.averageRTTSet {
 		s.averageRTT = delay
+		s.averageRTTSet = true
 	} else {
 		alpha := 0.2
 		s.averageRTT = time.Duration(alpha*float64(delay) + (1-alpha)*float64(s.averageRTT))
@@ -508,6 +727,31 @@ func (s *Server) updateAverageRTT(delay time.Duration) time.Duration {
 	return s.averageRTT
 }
 
+// currentAverageRTT returns the average round trip time, which is measured by the first check on a
+// connection before any awaitable isMaster is sent.
+func (s *Server) currentAverageRTT() time.Duration {
+	s.rttLock.Lock()
+	defer s.rttLock.Unlock()
+	return s.averageRTT
+}
+
+// currentMinRTT returns the minimum of the last round trip times, or 0 if fewer than two were
+// measured.
+func (s *Server) currentMinRTT() time.Duration {
+	s.rttLock.Lock()
+	defer s.rttLock.Unlock()
+	if len(s.rttSamples) < 2 {
+		return 0
+	}
+	min := s.rttSamples[0]
+	for _, rtt := range s.rttSamples[1:] {
+		if rtt < min {
+			min = rtt
+		}
+	}
+	return min
+}
+
 // Drain will drain the connection pool of this server. This is mainly here so the
 // pool for the server doesn't need to be directly exposed and so that when an error
 // is returned from reading or writing, a client can drain the pool for this server.
@@ -535,6 +779,78 @@ func (s *Server) String() string {
 	return str
 }
 
+func (s *Server) publishServerOpeningEvent() {
+	if s.cfg.serverMonitor == nil || s.cfg.serverMonitor.ServerOpening == nil {
+		return
+	}
+
+	s.cfg.serverMonitor.ServerOpening(&event.ServerOpeningEvent{
+		Address:    s.address,
+		TopologyID: s.cfg.topologyID,
+	})
+}
+
+func (s *Server) publishServerClosedEvent() {
+	if s.cfg.serverMonitor == nil || s.cfg.serverMonitor.ServerClosed == nil {
+		return
+	}
+
+	s.cfg.serverMonitor.ServerClosed(&event.ServerClosedEvent{
+		Address:    s.address,
+		TopologyID: s.cfg.topologyID,
+	})
+}
+
+func (s *Server) publishServerDescriptionChangedEvent(prev description.Server, current description.Server) {
+	if s.cfg.serverMonitor == nil || s.cfg.serverMonitor.ServerDescriptionChanged == nil {
+		return
+	}
+
+	s.cfg.serverMonitor.ServerDescriptionChanged(&event.ServerDescriptionChangedEvent{
+		Address:             s.address,
+		TopologyID:          s.cfg.topologyID,
+		PreviousDescription: prev,
+		NewDescription:      current,
+	})
+}
+
+func (s *Server) publishServerHeartbeatStartedEvent(connID string, awaited bool) {
+	if s.cfg.serverMonitor == nil || s.cfg.serverMonitor.ServerHeartbeatStarted == nil {
+		return
+	}
+
+	s.cfg.serverMonitor.ServerHeartbeatStarted(&event.ServerHeartbeatStartedEvent{
+		ConnectionID: connID,
+		Awaited:      awaited,
+	})
+}
+
+func (s *Server) publishServerHeartbeatSucceededEvent(connID string, duration time.Duration, desc description.Server, awaited bool) {
+	if s.cfg.serverMonitor == nil || s.cfg.serverMonitor.ServerHeartbeatSucceeded == nil {
+		return
+	}
+
+	s.cfg.serverMonitor.ServerHeartbeatSucceeded(&event.ServerHeartbeatSucceededEvent{
+		DurationNanos: duration.Nanoseconds(),
+		Reply:         desc,
+		ConnectionID:  connID,
+		Awaited:       awaited,
+	})
+}
+
+func (s *Server) publishServerHeartbeatFailedEvent(connID string, duration time.Duration, err error, awaited bool) {
+	if s.cfg.serverMonitor == nil || s.cfg.serverMonitor.ServerHeartbeatFailed == nil {
+		return
+	}
+
+	s.cfg.serverMonitor.ServerHeartbeatFailed(&event.ServerHeartbeatFailedEvent{
+		DurationNanos: duration.Nanoseconds(),
+		Failure:       err,
+		ConnectionID:  connID,
+		Awaited:       awaited,
+	})
+}
+
 // ServerSubscription represents a subscription to the description.Server updates for
 // a specific server.
 type ServerSubscription struct {
@@ -0,0 +1,49 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"context"
+	"math/rand"
+
+	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/description"
+)
+
+type preferredServerKey struct{}
+
+type selectedServerKey struct{}
+
+// WithPreferredServer returns a context in which SelectServer selects the server at addr whenever
+// it is one of the suitable servers, instead of picking one of them at random.
+func WithPreferredServer(ctx context.Context, addr address.Address) context.Context {
+	return context.WithValue(ctx, preferredServerKey{}, addr)
+}
+
+// WithSelectedServerRecorder returns a context in which SelectServer stores the address of each
+// server it selects in addr.
+func WithSelectedServerRecorder(ctx context.Context, addr *address.Address) context.Context {
+	return context.WithValue(ctx, selectedServerKey{}, addr)
+}
+
+// pickServer returns the preferred server of ctx if it is suitable, or a random suitable server.
+func pickServer(ctx context.Context, suitable []description.Server) description.Server {
+	if preferred, ok := ctx.Value(preferredServerKey{}).(address.Address); ok {
+		for _, s := range suitable {
+			if s.Addr == preferred {
+				return s
+			}
+		}
+	}
+	return suitable[rand.Intn(len(suitable))]
+}
+
+func recordSelectedServer(ctx context.Context, addr address.Address) {
+	if rec, ok := ctx.Value(selectedServerKey{}).(*address.Address); ok {
+		*rec = addr
+	}
+}
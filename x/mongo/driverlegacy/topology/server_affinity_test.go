@@ -0,0 +1,45 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/description"
+)
+
+func TestServerAffinity(t *testing.T) {
+	suitable := []description.Server{
+		{Addr: address.Address("one"), Kind: description.RSSecondary},
+		{Addr: address.Address("two"), Kind: description.RSSecondary},
+		{Addr: address.Address("three"), Kind: description.RSSecondary},
+	}
+
+	t.Run("preferred server is suitable", func(t *testing.T) {
+		ctx := WithPreferredServer(context.Background(), address.Address("two"))
+		for i := 0; i < 10; i++ {
+			require.Equal(t, address.Address("two"), pickServer(ctx, suitable).Addr)
+		}
+	})
+	t.Run("preferred server is not suitable", func(t *testing.T) {
+		ctx := WithPreferredServer(context.Background(), address.Address("primary"))
+		require.Contains(t, suitable, pickServer(ctx, suitable))
+	})
+	t.Run("records selected server", func(t *testing.T) {
+		var addr address.Address
+		ctx := WithSelectedServerRecorder(context.Background(), &addr)
+		recordSelectedServer(ctx, address.Address("one"))
+		require.Equal(t, address.Address("one"), addr)
+
+		// Contexts without a recorder are ignored.
+		recordSelectedServer(context.Background(), address.Address("two"))
+		require.Equal(t, address.Address("one"), addr)
+	})
+}
@@ -7,26 +7,41 @@
 package topology
 
 import (
+	"context"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/auth"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
 	connectionlegacy "go.mongodb.org/mongo-driver/x/network/connection"
+	"go.mongodb.org/mongo-driver/x/network/description"
 )
 
 var defaultRegistry = bson.NewRegistryBuilder().Build()
 
 type serverConfig struct {
+	authenticator     auth.Authenticator
 	clock             *session.ClusterClock
+	cmdInterceptor    CommandInterceptor
 	compressionOpts   []string
 	connectionOpts    []connectionlegacy.Option
 	appname           string
 	heartbeatInterval time.Duration
 	heartbeatTimeout  time.Duration
+	loadBalanced      bool
 	maxConns          uint16
 	maxIdleConns      uint16
+	nsFilter          NamespaceFilter
+	operationGate     OperationGate
+	opLimiter         *OperationLimiter
+	readOnly          bool
 	registry          *bsoncodec.Registry
+	serverMonitor     *event.ServerMonitor
+	topologyID        primitive.ObjectID
+	tracer            event.Tracer
 }
 
 func newServerConfig(opts ...ServerOption) (*serverConfig, error) {
@@ -103,6 +118,76 @@ func WithMaxIdleConnections(fn func(uint16) uint16) ServerOption {
 	}
 }
 
+// OperationGate is called with the description of the server before each command of an operation is
+// sent to it. If it returns an error, the command is not sent and the error is returned instead.
+// Otherwise the returned function, which may be nil, is called once with the error the command
+// failed with, or nil, when the command is finished.
+type OperationGate func(ctx context.Context, desc description.Server, database, command string) (func(error), error)
+
+// WithOperationGate configures the gate that decides whether commands may be sent to the server.
+func WithOperationGate(fn func(OperationGate) OperationGate) ServerOption {
+	return func(cfg *serverConfig) error {
+		cfg.operationGate = fn(cfg.operationGate)
+		return nil
+	}
+}
+
+// WithOperationLimiter configures the limiter that bounds the number of commands in flight at once.
+func WithOperationLimiter(fn func(*OperationLimiter) *OperationLimiter) ServerOption {
+	return func(cfg *serverConfig) error {
+		cfg.opLimiter = fn(cfg.opLimiter)
+		return nil
+	}
+}
+
+// NamespaceFilter is called with the namespace of each command sent to the server and returns
+// whether it may be sent. Commands on a database as a whole, rather than on one of its collections,
+// are called with the collection name "*". If it returns false, the command is not sent and a
+// command.NamespaceDeniedError is returned instead.
+type NamespaceFilter func(database, collection string) bool
+
+// WithNamespaceFilter configures the filter that decides which namespaces commands may be sent to.
+func WithNamespaceFilter(fn func(NamespaceFilter) NamespaceFilter) ServerOption {
+	return func(cfg *serverConfig) error {
+		cfg.nsFilter = fn(cfg.nsFilter)
+		return nil
+	}
+}
+
+// WithReadOnly configures whether write commands are rejected instead of being sent to the server.
+func WithReadOnly(fn func(bool) bool) ServerOption {
+	return func(cfg *serverConfig) error {
+		cfg.readOnly = fn(cfg.readOnly)
+		return nil
+	}
+}
+
+// CommandInterceptor is called with the description of the server before each command sent to it
+// with OP_MSG, and returns the command document to send instead, or nil to send cmd unchanged. If it
+// returns an error, the command is not sent and the error is returned instead. Otherwise the
+// returned function, which may be nil, is called once when the command is finished with the reply
+// document, or nil if there was none, and the error the command failed with, or nil.
+type CommandInterceptor func(ctx context.Context, desc description.Server, database string, cmd bson.Raw) (bson.Raw, func(bson.Raw, error), error)
+
+// WithCommandInterceptor configures the interceptor that may modify the commands sent to the server
+// and observe their replies.
+func WithCommandInterceptor(fn func(CommandInterceptor) CommandInterceptor) ServerOption {
+	return func(cfg *serverConfig) error {
+		cfg.cmdInterceptor = fn(cfg.cmdInterceptor)
+		return nil
+	}
+}
+
+// WithAuthenticator configures the authenticator the connections to the server are authenticated
+// with. If it is an auth.Reauthenticator, a connection is authenticated again when the server
+// returns a ReauthenticationRequired error, and the command is retried once.
+func WithAuthenticator(fn func(auth.Authenticator) auth.Authenticator) ServerOption {
+	return func(cfg *serverConfig) error {
+		cfg.authenticator = fn(cfg.authenticator)
+		return nil
+	}
+}
+
 // WithClock configures the ClusterClock for the server to use.
 func WithClock(fn func(clock *session.ClusterClock) *session.ClusterClock) ServerOption {
 	return func(cfg *serverConfig) error {
@@ -119,3 +204,37 @@ func WithRegistry(fn func(*bsoncodec.Registry) *bsoncodec.Registry) ServerOption
 		return nil
 	}
 }
+
+// WithServerMonitor configures the monitor for all SDAM events for a server.
+func WithServerMonitor(fn func(*event.ServerMonitor) *event.ServerMonitor) ServerOption {
+	return func(cfg *serverConfig) error {
+		cfg.serverMonitor = fn(cfg.serverMonitor)
+		return nil
+	}
+}
+
+// WithServerTracer configures the tracer that starts spans around the connection checkouts of the
+// server and the commands sent to it.
+func WithServerTracer(fn func(event.Tracer) event.Tracer) ServerOption {
+	return func(cfg *serverConfig) error {
+		cfg.tracer = fn(cfg.tracer)
+		return nil
+	}
+}
+
+// withTopologyID configures the ID of the topology the server is a part of, which is included in
+// the server's monitoring events.
+func withTopologyID(id primitive.ObjectID) ServerOption {
+	return func(cfg *serverConfig) error {
+		cfg.topologyID = id
+		return nil
+	}
+}
+
+// withLoadBalanced configures a server that is a load balancer. Load balancers are not monitored.
+func withLoadBalanced(loadBalanced bool) ServerOption {
+	return func(cfg *serverConfig) error {
+		cfg.loadBalanced = loadBalanced
+		return nil
+	}
+}
@@ -57,3 +57,22 @@ func TestServerSelectionRTTSpec(t *testing.T) {
 		}(t, file)
 	}
 }
+
+func TestServerMinRTT(t *testing.T) {
+	var server Server
+	require.Equal(t, time.Duration(0), server.currentMinRTT())
+
+	server.updateAverageRTT(50 * time.Millisecond)
+	require.Equal(t, time.Duration(0), server.currentMinRTT(), "a single sample is not enough")
+
+	server.updateAverageRTT(10 * time.Millisecond)
+	for i := 0; i < rttSamplesSize-1; i++ {
+		server.updateAverageRTT(20 * time.Millisecond)
+	}
+	require.Equal(t, 10*time.Millisecond, server.currentMinRTT())
+
+	// The oldest samples are forgotten.
+	server.updateAverageRTT(30 * time.Millisecond)
+	require.Equal(t, 20*time.Millisecond, server.currentMinRTT())
+	require.Len(t, server.rttSamples, rttSamplesSize)
+}
@@ -0,0 +1,57 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/x/network/address"
+	connectionlegacy "go.mongodb.org/mongo-driver/x/network/connection"
+	"go.mongodb.org/mongo-driver/x/network/description"
+)
+
+// ServerStats is a snapshot of the state of a server of a topology.
+type ServerStats struct {
+	Address    address.Address
+	Kind       description.ServerKind
+	AverageRTT time.Duration // The average round trip time of the heartbeats of the server.
+	Pool       connectionlegacy.PoolStats
+}
+
+// Stats returns a snapshot of the state of the server.
+func (s *Server) Stats() ServerStats {
+	return ServerStats{
+		Address:    s.address,
+		Kind:       s.Description().Kind,
+		AverageRTT: s.currentAverageRTT(),
+		Pool:       s.pool.Stats(),
+	}
+}
+
+// ServerStats returns a snapshot of the state of each server of the topology, sorted by address.
+func (t *Topology) ServerStats() []ServerStats {
+	t.serversLock.Lock()
+	servers := make([]*Server, 0, len(t.servers))
+	for _, s := range t.servers {
+		servers = append(servers, s)
+	}
+	t.serversLock.Unlock()
+
+	stats := make([]ServerStats, 0, len(servers))
+	for _, s := range servers {
+		stats = append(stats, s.Stats())
+	}
+	sort.Sort(byAddress(stats))
+	return stats
+}
+
+type byAddress []ServerStats
+
+func (ba byAddress) Len() int           { return len(ba) }
+func (ba byAddress) Less(i, j int) bool { return ba[i].Address < ba[j].Address }
+func (ba byAddress) Swap(i, j int)      { ba[i], ba[j] = ba[j], ba[i] }
@@ -8,15 +8,24 @@ package topology
 
 import (
 	"context"
+	"encoding/binary"
+	"io"
+	"net"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/x/bsonx"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/auth"
 	"go.mongodb.org/mongo-driver/x/network/address"
 	connectionlegacy "go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/description"
 	"go.mongodb.org/mongo-driver/x/network/result"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
 )
 
 type testpool struct {
@@ -49,6 +58,10 @@ func (p *testpool) Drain() error {
 	return nil
 }
 
+func (p *testpool) Stats() connectionlegacy.PoolStats {
+	return connectionlegacy.PoolStats{}
+}
+
 func NewTestPool(connectionError bool, networkError bool, desc *description.Server) (connectionlegacy.Pool, error) {
 	p := &testpool{
 		connectionError: connectionError,
@@ -156,4 +169,180 @@ func TestServer(t *testing.T) {
 		require.True(t, updated)
 
 	})
+	t.Run("description changed event", func(t *testing.T) {
+		var events []*event.ServerDescriptionChangedEvent
+		monitor := &event.ServerMonitor{
+			ServerDescriptionChanged: func(evt *event.ServerDescriptionChangedEvent) {
+				events = append(events, evt)
+			},
+		}
+		s, err := NewServer(address.Address("localhost"), nil,
+			WithServerMonitor(func(*event.ServerMonitor) *event.ServerMonitor { return monitor }))
+		require.NoError(t, err)
+		s.pool, err = NewTestPool(false, false, nil)
+		require.NoError(t, err)
+
+		primary := description.Server{Addr: s.address, Kind: description.Standalone}
+		s.updateDescription(primary, true)
+		// an unchanged description must not publish an event
+		primary.AverageRTT = 5
+		s.updateDescription(primary, false)
+
+		require.Len(t, events, 1)
+		require.Equal(t, description.ServerKind(description.Unknown), events[0].PreviousDescription.Kind)
+		require.Equal(t, description.ServerKind(description.Standalone), events[0].NewDescription.Kind)
+		require.Equal(t, s.address, events[0].Address)
+	})
+	t.Run("description changed event precedes topology update", func(t *testing.T) {
+		var order []string
+		monitor := &event.ServerMonitor{
+			ServerDescriptionChanged: func(*event.ServerDescriptionChangedEvent) {
+				order = append(order, "server")
+			},
+		}
+		topo := func(description.Server) { order = append(order, "topology") }
+		s, err := NewServer(address.Address("localhost"), topo,
+			WithServerMonitor(func(*event.ServerMonitor) *event.ServerMonitor { return monitor }))
+		require.NoError(t, err)
+		s.pool, err = NewTestPool(false, false, nil)
+		require.NoError(t, err)
+
+		s.updateDescription(description.Server{Addr: s.address, Kind: description.Standalone}, true)
+		require.Equal(t, []string{"server", "topology"}, order)
+	})
+}
+
+// streamingServer is a fake server that supports awaitable isMaster commands. It streams a reply with
+// the next topology version each time a value is sent on push.
+type streamingServer struct {
+	processID primitive.ObjectID
+	push      chan struct{}
+	awaited   int32
+}
+
+func (ss *streamingServer) reply(counter int64) bson.Raw {
+	doc, _ := bsonx.Doc{
+		{"ok", bsonx.Int32(1)},
+		{"ismaster", bsonx.Boolean(true)},
+		{"maxWireVersion", bsonx.Int32(9)},
+		{"topologyVersion", bsonx.Document(bsonx.Doc{
+			{"processId", bsonx.ObjectID(ss.processID)},
+			{"counter", bsonx.Int64(counter)},
+		})},
+	}.MarshalBSON()
+	return doc
+}
+
+func (ss *streamingServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var sizeBuf [4]byte
+		if _, err := io.ReadFull(conn, sizeBuf[:]); err != nil {
+			return
+		}
+		b := make([]byte, binary.LittleEndian.Uint32(sizeBuf[:]))
+		copy(b, sizeBuf[:])
+		if _, err := io.ReadFull(conn, b[4:]); err != nil {
+			return
+		}
+		header, _ := wiremessage.ReadHeader(b, 0)
+
+		if header.OpCode == wiremessage.OpQuery {
+			wm, _ := wiremessage.Reply{
+				MsgHeader:      wiremessage.Header{RequestID: wiremessage.NextRequestID(), ResponseTo: header.RequestID},
+				NumberReturned: 1,
+				Documents:      []bson.Raw{ss.reply(0)},
+			}.MarshalWireMessage()
+			if _, err := conn.Write(wm); err != nil {
+				return
+			}
+			continue
+		}
+
+		var msg wiremessage.Msg
+		if err := msg.UnmarshalWireMessage(b); err != nil || msg.FlagBits&wiremessage.ExhaustAllowed == 0 {
+			return
+		}
+		atomic.AddInt32(&ss.awaited, 1)
+		responseTo := header.RequestID
+		for counter := int64(1); ; counter++ {
+			if counter > 1 {
+				if _, ok := <-ss.push; !ok {
+					return
+				}
+			}
+			requestID := wiremessage.NextRequestID()
+			wm, _ := wiremessage.Msg{
+				MsgHeader: wiremessage.Header{RequestID: requestID, ResponseTo: responseTo},
+				FlagBits:  wiremessage.MoreToCome,
+				Sections:  []wiremessage.Section{wiremessage.SectionBody{Document: ss.reply(counter)}},
+			}.MarshalWireMessage()
+			if _, err := conn.Write(wm); err != nil {
+				return
+			}
+			responseTo = requestID
+		}
+	}
+}
+
+func TestServerStreaming(t *testing.T) {
+	ss := &streamingServer{processID: primitive.NewObjectID(), push: make(chan struct{})}
+	dialer := connectionlegacy.DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go ss.serve(server)
+		return client, nil
+	})
+
+	var awaited int32
+	s, err := NewServer(address.Address("localhost:27017"), nil,
+		WithConnectionOptions(func(...connectionlegacy.Option) []connectionlegacy.Option {
+			return []connectionlegacy.Option{connectionlegacy.WithDialer(func(connectionlegacy.Dialer) connectionlegacy.Dialer {
+				return dialer
+			})}
+		}),
+		// The replies are streamed, so they are received long before the next heartbeat.
+		WithHeartbeatInterval(func(time.Duration) time.Duration { return time.Minute }),
+		WithServerMonitor(func(*event.ServerMonitor) *event.ServerMonitor {
+			return &event.ServerMonitor{
+				ServerHeartbeatSucceeded: func(e *event.ServerHeartbeatSucceededEvent) {
+					if e.Awaited {
+						atomic.AddInt32(&awaited, 1)
+					}
+				},
+			}
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, s.Connect(context.Background()))
+	sub, err := s.Subscribe()
+	require.NoError(t, err)
+
+	waitForCounter := func(counter int64) {
+		timeout := time.After(10 * time.Second)
+		for {
+			select {
+			case desc := <-sub.C:
+				if desc.TopologyVersion != nil && desc.TopologyVersion.Counter == counter {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timed out waiting for topology version %d", counter)
+			}
+		}
+	}
+	waitForCounter(1)
+	ss.push <- struct{}{}
+	waitForCounter(2)
+	ss.push <- struct{}{}
+	waitForCounter(3)
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&ss.awaited), "expected a single awaitable isMaster")
+	require.Equal(t, int32(3), atomic.LoadInt32(&awaited))
+	require.True(t, s.Description().AverageRTTSet)
+
+	// Disconnecting cancels the check waiting for the next streamed reply.
+	start := time.Now()
+	require.NoError(t, s.Disconnect(context.Background()))
+	require.True(t, time.Since(start) < 5*time.Second)
+	close(ss.push)
 }
@@ -22,6 +22,9 @@ import (
 	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/logger"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/dns"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
 	"go.mongodb.org/mongo-driver/x/network/address"
@@ -44,6 +47,9 @@ var ErrTopologyConnected = errors.New("topology is connected or connecting")
 // selection process took longer than allowed by the timeout.
 var ErrServerSelectionTimeout = errors.New("server selection timeout")
 
+// errNoSRVHosts is reported when polling the SRV records succeeds but returns no valid hosts.
+var errNoSRVHosts = errors.New("no valid hosts found in SRV records")
+
 // MonitorMode represents the way in which a server is monitored.
 type MonitorMode uint8
 
@@ -51,6 +57,10 @@ type MonitorMode uint8
 const (
 	AutomaticMode MonitorMode = iota
 	SingleMode
+	// LoadBalancedMode connects to a single load balancer in front of mongos instances. The load
+	// balancer is not monitored, and cursors and transactions are pinned to the connection their
+	// first command ran on.
+	LoadBalancedMode
 )
 
 // Topology represents a MongoDB deployment.
@@ -61,6 +71,9 @@ type Topology struct {
 
 	cfg *config
 
+	id            primitive.ObjectID
+	serverMonitor *event.ServerMonitor
+
 	desc atomic.Value // holds a description.Topology
 
 	dnsResolver *dns.Resolver
@@ -70,12 +83,15 @@ type Topology struct {
 	pollingDone       chan struct{}
 	pollingwg         sync.WaitGroup
 	rescanSRVInterval time.Duration
-	pollHeartbeatTime atomic.Value // holds a bool
+	pollHeartbeatTime atomic.Value   // holds a bool
+	srvMissing        map[string]int // the number of consecutive polls each host was missing from
 
 	fsm *fsm
 
 	SessionPool *session.Pool
 
+	retries retryStats
+
 	// This should really be encapsulated into it's own type. This will likely
 	// require a redesign so we can share a minimum of data between the
 	// subscribers and the topology.
@@ -100,11 +116,18 @@ func New(opts ...Option) (*Topology, error) {
 		return nil, err
 	}
 
+	serverCfg, err := newServerConfig(cfg.serverOpts...)
+	if err != nil {
+		return nil, err
+	}
+
 	t := &Topology{
 		cfg:               cfg,
+		id:                primitive.NewObjectID(),
+		serverMonitor:     serverCfg.serverMonitor,
 		done:              make(chan struct{}),
 		pollingDone:       make(chan struct{}),
-		rescanSRVInterval: 60 * time.Second,
+		rescanSRVInterval: 60 * time.Second, // the Go resolver does not expose SRV record TTLs
 		fsm:               newFSM(),
 		subscribers:       make(map[uint64]chan description.Topology),
 		servers:           make(map[address.Address]*Server),
@@ -121,6 +144,10 @@ func New(opts ...Option) (*Topology, error) {
 		t.fsm.Kind = description.Single
 	}
 
+	if cfg.mode == LoadBalancedMode {
+		t.fsm.Kind = description.LoadBalanced
+	}
+
 	return t, nil
 }
 
@@ -132,16 +159,30 @@ func (t *Topology) Connect(ctx context.Context) error {
 	}
 
 	t.desc.Store(description.Topology{})
+	t.publishTopologyOpeningEvent()
+
 	var err error
 	t.serversLock.Lock()
+	prev := t.fsm.Topology
 	for _, a := range t.cfg.seedList {
 		addr := address.Address(a).Canonicalize()
-		t.fsm.Servers = append(t.fsm.Servers, description.Server{Addr: addr})
+		desc := description.Server{Addr: addr}
+		if t.cfg.mode == LoadBalancedMode {
+			desc.Kind = description.LoadBalancer
+		}
+		t.fsm.Servers = append(t.fsm.Servers, desc)
 		err = t.addServer(ctx, addr)
 	}
+	if t.cfg.mode == LoadBalancedMode {
+		// The load balancer is never monitored, so it is selectable right away.
+		t.desc.Store(t.fsm.Topology)
+	}
+	// Published before serversLock is released so that it precedes the events caused by the
+	// servers started above.
+	t.publishTopologyDescriptionChangedEvent(prev, t.fsm.Topology)
 	t.serversLock.Unlock()
 
-	if srvPollingRequired(t.cfg.cs.Original) {
+	if t.srvPollingRequired() {
 		go t.pollSRVRecords()
 		t.pollingwg.Add(1)
 	}
@@ -183,7 +224,7 @@ func (t *Topology) Disconnect(ctx context.Context) error {
 	t.subscriptionsClosed = true
 	t.subLock.Unlock()
 
-	if srvPollingRequired(t.cfg.cs.Original) {
+	if t.srvPollingRequired() {
 		t.pollingDone <- struct{}{}
 		t.pollingwg.Wait()
 	}
@@ -191,11 +232,48 @@ func (t *Topology) Disconnect(ctx context.Context) error {
 	t.desc.Store(description.Topology{})
 
 	atomic.StoreInt32(&t.connectionstate, disconnected)
+	t.publishTopologyClosedEvent()
 	return nil
 }
 
-func srvPollingRequired(connstr string) bool {
-	return strings.HasPrefix(connstr, "mongodb+srv://")
+// WaitIdle waits until the operations in progress have returned the connections they checked out
+// of the servers of the topology, or until ctx is done, in which case ctx.Err() is returned.
+// Operations started while waiting are waited for too.
+func (t *Topology) WaitIdle(ctx context.Context) error {
+	t.serversLock.Lock()
+	servers := make([]*Server, 0, len(t.servers))
+	for _, server := range t.servers {
+		servers = append(servers, server)
+	}
+	t.serversLock.Unlock()
+
+	for _, server := range servers {
+		if err := server.waitIdle(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InUseConnections returns the number of connections checked out of the servers of the topology by
+// operations in progress, which WaitIdle waits for.
+func (t *Topology) InUseConnections() int {
+	t.serversLock.Lock()
+	defer t.serversLock.Unlock()
+
+	var inUse int
+	for _, server := range t.servers {
+		server.inUseLock.Lock()
+		inUse += server.inUse
+		server.inUseLock.Unlock()
+	}
+	return inUse
+}
+
+// srvPollingRequired returns true if the SRV records of the connection string of the topology are
+// polled. A load balancer is never replaced, so its SRV records are not polled.
+func (t *Topology) srvPollingRequired() bool {
+	return strings.HasPrefix(t.cfg.uri, "mongodb+srv://") && t.cfg.mode != LoadBalancedMode
 }
 
 // Description returns a description of the topology.
@@ -255,13 +333,29 @@ func (t *Topology) SupportsSessions() bool {
 	return t.Description().SessionTimeoutMinutes != 0 && t.Description().Kind != description.Single
 }
 
+// RetryWriteConcernTimeouts returns true if retryable writes with a majority write concern are
+// retried when their write concern times out.
+func (t *Topology) RetryWriteConcernTimeouts() bool {
+	return t.cfg.retryWCTimeouts
+}
+
 // SelectServer selects a server given a selector.SelectServer complies with the
 // server selection spec, and will time out after severSelectionTimeout or when the
 // parent context is done.
-func (t *Topology) SelectServer(ctx context.Context, ss description.ServerSelector) (*SelectedServer, error) {
+func (t *Topology) SelectServer(ctx context.Context, ss description.ServerSelector) (srv *SelectedServer, err error) {
 	if atomic.LoadInt32(&t.connectionstate) != connected {
 		return nil, ErrTopologyClosed
 	}
+	if t.cfg.tracer != nil {
+		var span event.Span
+		ctx, span = startSpan(ctx, t.cfg.tracer, event.SpanServerSelection)
+		defer func() {
+			if srv != nil {
+				span.SetAttributes(peerAttributes(srv.Description().Addr)...)
+			}
+			span.End(err)
+		}()
+	}
 	var ssTimeoutCh <-chan time.Time
 
 	if t.cfg.serverSelectionTimeout > 0 {
@@ -276,18 +370,25 @@ func (t *Topology) SelectServer(ctx context.Context, ss description.ServerSelect
 	}
 	defer sub.Unsubscribe()
 
+	t.logServerSelection(logger.LevelDebug, "Server selection started", t.Description)
+
 	for {
 		suitable, err := t.selectServer(ctx, sub.C, ss, ssTimeoutCh)
 		if err != nil {
+			t.logServerSelection(logger.LevelDebug, "Server selection failed", t.Description, "failure", err)
 			return nil, err
 		}
 
-		selected := suitable[rand.Intn(len(suitable))]
+		selected := pickServer(ctx, suitable)
 		selectedS, err := t.FindServer(selected)
 		switch {
 		case err != nil:
+			t.logServerSelection(logger.LevelDebug, "Server selection failed", t.Description, "failure", err)
 			return nil, err
 		case selectedS != nil:
+			t.logServerSelection(logger.LevelDebug, "Server selection succeeded", t.Description,
+				"serverAddress", selected.Addr.String())
+			recordSelectedServer(ctx, selected.Addr)
 			return selectedS, nil
 		default:
 			// We don't have an actual server for the provided description.
@@ -298,6 +399,16 @@ func (t *Topology) SelectServer(ctx context.Context, ss description.ServerSelect
 	}
 }
 
+// logServerSelection logs a server selection message followed by the topology description returned
+// by desc. The description is only built when the message is logged.
+func (t *Topology) logServerSelection(level logger.Level, msg string, desc func() description.Topology, keysAndValues ...interface{}) {
+	if !t.cfg.logger.Enabled(logger.ComponentServerSelection, level) {
+		return
+	}
+	keysAndValues = append(keysAndValues, "topologyDescription", desc().String())
+	t.cfg.logger.Print(logger.ComponentServerSelection, level, msg, keysAndValues...)
+}
+
 // FindServer will attempt to find a server that fits the given server description.
 // This method will return nil, nil if a matching server could not be found.
 func (t *Topology) FindServer(selected description.Server) (*SelectedServer, error) {
@@ -319,20 +430,26 @@ func (t *Topology) FindServer(selected description.Server) (*SelectedServer, err
 }
 
 func wrapServerSelectionError(err error, t *Topology) error {
-	return fmt.Errorf("server selection error: %v\ncurrent topology: %s", err, t.String())
+	return ServerSelectionError{Desc: t.Description(), Wrapped: err, topology: t.String()}
 }
 
 // selectServer is the core piece of server selection. It handles getting
 // topology descriptions and running sever selection on those descriptions.
 func (t *Topology) selectServer(ctx context.Context, subscriptionCh <-chan description.Topology, ss description.ServerSelector, timeoutCh <-chan time.Time) ([]description.Server, error) {
 	var current description.Topology
+	var waiting bool
 	for {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		case <-timeoutCh:
 			return nil, wrapServerSelectionError(ErrServerSelectionTimeout, t)
-		case current = <-subscriptionCh:
+		case desc, ok := <-subscriptionCh:
+			if !ok {
+				// The subscription is closed when the topology is disconnected.
+				return nil, ErrTopologyClosed
+			}
+			current = desc
 		}
 
 		var allowed []description.Server
@@ -351,6 +468,13 @@ func (t *Topology) selectServer(ctx context.Context, subscriptionCh <-chan descr
 			return suitable, nil
 		}
 
+		if !waiting {
+			waiting = true
+			desc := current
+			t.logServerSelection(logger.LevelInfo, "Waiting for suitable server to become available",
+				func() description.Topology { return desc },
+				"serverSelectionTimeoutMS", int64(t.cfg.serverSelectionTimeout/time.Millisecond))
+		}
 		t.RequestImmediateCheck()
 	}
 }
@@ -364,6 +488,7 @@ func (t *Topology) pollSRVRecords() {
 	pollTicker := time.NewTicker(t.rescanSRVInterval)
 	defer pollTicker.Stop()
 	t.pollHeartbeatTime.Store(false)
+	var failures int
 	var doneOnce bool
 	defer func() {
 		//  ¯\_(ツ)_/¯
@@ -373,11 +498,15 @@ func (t *Topology) pollSRVRecords() {
 	}()
 
 	// remove the scheme
-	uri := t.cfg.cs.Original[14:]
+	uri := t.cfg.uri[14:]
 	hosts := uri
 	if idx := strings.IndexAny(uri, "/?@"); idx != -1 {
 		hosts = uri[:idx]
 	}
+	srvName := dns.DefaultSRVServiceName
+	if t.cfg.srvServiceName != "" {
+		srvName = t.cfg.srvServiceName
+	}
 
 	for {
 		select {
@@ -391,9 +520,15 @@ func (t *Topology) pollSRVRecords() {
 			break
 		}
 
-		parsedHosts, err := t.dnsResolver.ParseHosts(hosts, false)
-		// DNS problem or no verified hosts returned
+		parsedHosts, err := t.dnsResolver.ParseHostsWithService(hosts, srvName, false)
+		// DNS problem or no verified hosts returned. The known hosts are kept until the records can
+		// be resolved again.
 		if err != nil || len(parsedHosts) == 0 {
+			if err == nil {
+				err = errNoSRVHosts
+			}
+			failures++
+			t.publishSRVPollFailedEvent(failures, err)
 			if !t.pollHeartbeatTime.Load().(bool) {
 				pollTicker.Stop()
 				pollTicker = time.NewTicker(heartbeatInterval)
@@ -401,6 +536,7 @@ func (t *Topology) pollSRVRecords() {
 			}
 			continue
 		}
+		failures = 0
 		if t.pollHeartbeatTime.Load().(bool) {
 			pollTicker.Stop()
 			pollTicker = time.NewTicker(t.rescanSRVInterval)
@@ -424,11 +560,15 @@ func (t *Topology) processSRVResults(parsedHosts []string) bool {
 		return false
 	}
 	diff := t.fsm.Topology.DiffHostlist(parsedHosts)
+	diff.Removed = t.srvHostsToRemove(diff.Removed)
 
 	if len(diff.Added) == 0 && len(diff.Removed) == 0 {
 		return true
 	}
 
+	prev := t.fsm.Topology
+	prev.Servers = append([]description.Server(nil), prev.Servers...)
+
 	for _, r := range diff.Removed {
 		addr := address.Address(r).Canonicalize()
 		s, ok := t.servers[addr]
@@ -443,7 +583,22 @@ func (t *Topology) processSRVResults(parsedHosts []string) bool {
 		delete(t.servers, addr)
 		t.fsm.removeServerByAddr(addr)
 	}
-	for _, a := range diff.Added {
+	added := diff.Added
+	if maxHosts := t.cfg.srvMaxHosts; maxHosts > 0 {
+		// Keep at most srvMaxHosts servers, adding a random subset of the new hosts.
+		n := maxHosts - len(t.fsm.Servers)
+		if n < 0 {
+			n = 0
+		}
+		if n < len(added) {
+			subset := make([]string, 0, n)
+			for _, i := range rand.Perm(len(added))[:n] {
+				subset = append(subset, added[i])
+			}
+			added = subset
+		}
+	}
+	for _, a := range added {
 		addr := address.Address(a).Canonicalize()
 		_ = t.addServer(context.TODO(), addr)
 		t.fsm.addServer(addr)
@@ -455,6 +610,7 @@ func (t *Topology) processSRVResults(parsedHosts []string) bool {
 		SessionTimeoutMinutes: t.fsm.SessionTimeoutMinutes,
 	}
 	t.desc.Store(newDesc)
+	t.publishTopologyDescriptionChangedEvent(prev, newDesc)
 
 	t.subLock.Lock()
 	for _, ch := range t.subscribers {
@@ -471,6 +627,24 @@ func (t *Topology) processSRVResults(parsedHosts []string) bool {
 
 }
 
+// srvHostsToRemove records that the hosts in missing were missing from the last poll of the SRV
+// records, and returns those that were missing from enough consecutive polls to be removed. It is
+// called with the servers lock held.
+func (t *Topology) srvHostsToRemove(missing []string) []string {
+	counts := make(map[string]int, len(missing))
+	var remove []string
+	for _, host := range missing {
+		count := t.srvMissing[host] + 1
+		if count >= t.cfg.srvRemovalThreshold {
+			remove = append(remove, host)
+			continue
+		}
+		counts[host] = count
+	}
+	t.srvMissing = counts
+	return remove
+}
+
 func (t *Topology) apply(ctx context.Context, desc description.Server) {
 	var err error
 
@@ -506,6 +680,11 @@ func (t *Topology) apply(ctx context.Context, desc description.Server) {
 	}
 
 	t.desc.Store(current)
+	// The event is published while serversLock is held so that concurrent updates are delivered
+	// in order. See the documentation of event.ServerMonitor.
+	if !prev.Equal(current) {
+		t.publishTopologyDescriptionChangedEvent(prev, current)
+	}
 
 	t.subLock.Lock()
 	for _, ch := range t.subscribers {
@@ -528,7 +707,9 @@ func (t *Topology) addServer(ctx context.Context, addr address.Address) error {
 	topoFunc := func(desc description.Server) {
 		t.apply(context.TODO(), desc)
 	}
-	svr, err := ConnectServer(ctx, addr, topoFunc, t.cfg.serverOpts...)
+	opts := append([]ServerOption{}, t.cfg.serverOpts...)
+	opts = append(opts, withTopologyID(t.id), withLoadBalanced(t.cfg.mode == LoadBalancedMode))
+	svr, err := ConnectServer(ctx, addr, topoFunc, opts...)
 	if err != nil {
 		return err
 	}
@@ -548,6 +729,55 @@ func (t *Topology) String() string {
 	return str
 }
 
+func (t *Topology) publishTopologyOpeningEvent() {
+	if t.serverMonitor == nil || t.serverMonitor.TopologyOpening == nil {
+		return
+	}
+
+	t.serverMonitor.TopologyOpening(&event.TopologyOpeningEvent{
+		TopologyID: t.id,
+	})
+}
+
+func (t *Topology) publishTopologyClosedEvent() {
+	if t.serverMonitor == nil || t.serverMonitor.TopologyClosed == nil {
+		return
+	}
+
+	t.serverMonitor.TopologyClosed(&event.TopologyClosedEvent{
+		TopologyID: t.id,
+	})
+}
+
+func (t *Topology) publishSRVPollFailedEvent(failures int, err error) {
+	if t.serverMonitor == nil || t.serverMonitor.SRVPollFailed == nil {
+		return
+	}
+
+	var hosts []string
+	for _, server := range t.Description().Servers {
+		hosts = append(hosts, server.Addr.String())
+	}
+	t.serverMonitor.SRVPollFailed(&event.SRVPollFailedEvent{
+		TopologyID:          t.id,
+		Hosts:               hosts,
+		ConsecutiveFailures: failures,
+		Failure:             err,
+	})
+}
+
+func (t *Topology) publishTopologyDescriptionChangedEvent(prev description.Topology, current description.Topology) {
+	if t.serverMonitor == nil || t.serverMonitor.TopologyDescriptionChanged == nil {
+		return
+	}
+
+	t.serverMonitor.TopologyDescriptionChanged(&event.TopologyDescriptionChangedEvent{
+		TopologyID:          t.id,
+		PreviousDescription: prev,
+		NewDescription:      current,
+	})
+}
+
 // Subscription is a subscription to updates to the description of the Topology that created this
 // Subscription.
 type Subscription struct {
@@ -11,6 +11,8 @@ import (
 	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/logger"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/auth"
 	"go.mongodb.org/mongo-driver/x/network/command"
 	connectionlegacy "go.mongodb.org/mongo-driver/x/network/connection"
@@ -26,13 +28,21 @@ type config struct {
 	seedList               []string
 	serverOpts             []ServerOption
 	cs                     connstring.ConnString
+	uri                    string
+	srvMaxHosts            int
+	srvRemovalThreshold    int
+	srvServiceName         string
+	retryWCTimeouts        bool
 	serverSelectionTimeout time.Duration
+	logger                 *logger.Logger
+	tracer                 event.Tracer
 }
 
 func newConfig(opts ...Option) (*config, error) {
 	cfg := &config{
 		seedList:               []string{"localhost:27017"},
 		serverSelectionTimeout: 30 * time.Second,
+		srvRemovalThreshold:    1,
 	}
 
 	for _, opt := range opts {
@@ -50,6 +60,9 @@ func WithConnString(fn func(connstring.ConnString) connstring.ConnString) Option
 	return func(c *config) error {
 		cs := fn(c.cs)
 		c.cs = cs
+		c.uri = cs.Original
+		c.srvMaxHosts = cs.SRVMaxHosts
+		c.srvServiceName = cs.SRVServiceName
 
 		if cs.ServerSelectionTimeoutSet {
 			c.serverSelectionTimeout = cs.ServerSelectionTimeout
@@ -66,6 +79,10 @@ func WithConnString(fn func(connstring.ConnString) connstring.ConnString) Option
 			c.mode = SingleMode
 		}
 
+		if cs.LoadBalanced {
+			c.mode = LoadBalancedMode
+		}
+
 		c.seedList = cs.Hosts
 
 		if cs.ConnectTimeout > 0 {
@@ -140,7 +157,8 @@ func WithConnString(fn func(connstring.ConnString) connstring.ConnString) Option
 			connOpts = append(connOpts, connectionlegacy.WithTLSConfig(func(*connectionlegacy.TLSConfig) *connectionlegacy.TLSConfig { return tlsConfig }))
 		}
 
-		if cs.Username != "" || cs.AuthMechanism == auth.MongoDBX509 || cs.AuthMechanism == auth.GSSAPI {
+		if cs.Username != "" || cs.AuthMechanism == auth.MongoDBX509 || cs.AuthMechanism == auth.GSSAPI ||
+			cs.AuthMechanism == auth.MongoDBOIDC {
 			cred := &auth.Cred{
 				Source:      "admin",
 				Username:    cs.Username,
@@ -158,7 +176,7 @@ func WithConnString(fn func(connstring.ConnString) connstring.ConnString) Option
 						cred.Username = x509Username
 					}
 					fallthrough
-				case auth.GSSAPI, auth.PLAIN:
+				case auth.GSSAPI, auth.PLAIN, auth.MongoDBOIDC:
 					cred.Source = "$external"
 				default:
 					cred.Source = cs.Database
@@ -169,12 +187,14 @@ func WithConnString(fn func(connstring.ConnString) connstring.ConnString) Option
 			if err != nil {
 				return err
 			}
+			c.serverOpts = append(c.serverOpts, WithAuthenticator(func(auth.Authenticator) auth.Authenticator { return authenticator }))
 
 			connOpts = append(connOpts, connectionlegacy.WithHandshaker(func(h connectionlegacy.Handshaker) connectionlegacy.Handshaker {
 				options := &auth.HandshakeOptions{
 					AppName:       cs.AppName,
 					Authenticator: authenticator,
 					Compressors:   cs.Compressors,
+					LoadBalanced:  cs.LoadBalanced,
 				}
 				if cs.AuthMechanism == "" {
 					// Required for SASL mechanism negotiation during handshake
@@ -185,7 +205,11 @@ func WithConnString(fn func(connstring.ConnString) connstring.ConnString) Option
 		} else {
 			// We need to add a non-auth Handshaker to the connection options
 			connOpts = append(connOpts, connectionlegacy.WithHandshaker(func(h connectionlegacy.Handshaker) connectionlegacy.Handshaker {
-				return &command.Handshake{Client: command.ClientDoc(cs.AppName), Compressors: cs.Compressors}
+				return &command.Handshake{
+					Client:       command.ClientDoc(cs.AppName),
+					Compressors:  cs.Compressors,
+					LoadBalanced: cs.LoadBalanced,
+				}
 			}))
 		}
 
@@ -217,6 +241,22 @@ func WithConnString(fn func(connstring.ConnString) connstring.ConnString) Option
 	}
 }
 
+// WithLogger configures the logger used for server selection log messages.
+func WithLogger(fn func(*logger.Logger) *logger.Logger) Option {
+	return func(cfg *config) error {
+		cfg.logger = fn(cfg.logger)
+		return nil
+	}
+}
+
+// WithTracer configures the tracer that starts a span around each server selection.
+func WithTracer(fn func(event.Tracer) event.Tracer) Option {
+	return func(cfg *config) error {
+		cfg.tracer = fn(cfg.tracer)
+		return nil
+	}
+}
+
 // WithMode configures the topology's monitor mode.
 func WithMode(fn func(MonitorMode) MonitorMode) Option {
 	return func(cfg *config) error {
@@ -250,6 +290,52 @@ func WithServerOptions(fn func(...ServerOption) []ServerOption) Option {
 	}
 }
 
+// WithRetryWriteConcernTimeouts configures whether retryable writes with a majority write concern
+// that fail because the write concern timed out are retried.
+func WithRetryWriteConcernTimeouts(fn func(bool) bool) Option {
+	return func(cfg *config) error {
+		cfg.retryWCTimeouts = fn(cfg.retryWCTimeouts)
+		return nil
+	}
+}
+
+// WithSRVMaxHosts configures the maximum number of hosts found by polling the SRV records of a
+// mongodb+srv connection string that the topology connects to. 0 means there is no limit.
+func WithSRVMaxHosts(fn func(int) int) Option {
+	return func(cfg *config) error {
+		cfg.srvMaxHosts = fn(cfg.srvMaxHosts)
+		return nil
+	}
+}
+
+// WithSRVRemovalThreshold configures the number of consecutive polls of the SRV records of a
+// mongodb+srv connection string a host must be missing from before it is removed from the topology,
+// so that incomplete DNS answers do not disconnect healthy servers. The default is 1.
+func WithSRVRemovalThreshold(fn func(int) int) Option {
+	return func(cfg *config) error {
+		cfg.srvRemovalThreshold = fn(cfg.srvRemovalThreshold)
+		return nil
+	}
+}
+
+// WithSRVServiceName configures the service name of the SRV records polled for a mongodb+srv
+// connection string. The default is "mongodb".
+func WithSRVServiceName(fn func(string) string) Option {
+	return func(cfg *config) error {
+		cfg.srvServiceName = fn(cfg.srvServiceName)
+		return nil
+	}
+}
+
+// WithURI configures the connection string the topology was created from. If it is a mongodb+srv
+// connection string, its SRV records are polled for changes to the hosts of a sharded cluster.
+func WithURI(fn func(string) string) Option {
+	return func(cfg *config) error {
+		cfg.uri = fn(cfg.uri)
+		return nil
+	}
+}
+
 // WithServerSelectionTimeout configures a topology's server selection timeout.
 // A server selection timeout of 0 means there is no timeout for server selection.
 func WithServerSelectionTimeout(fn func(time.Duration) time.Duration) Option {
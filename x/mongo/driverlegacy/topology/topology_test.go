@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/x/network/address"
 	"go.mongodb.org/mongo-driver/x/network/command"
 	"go.mongodb.org/mongo-driver/x/network/description"
@@ -191,6 +192,9 @@ func TestServerSelection(t *testing.T) {
 		if err == nil {
 			t.Fatalf("did not receive error from server selection")
 		}
+		if sse, ok := err.(ServerSelectionError); !ok || sse.Wrapped != ErrServerSelectionTimeout {
+			t.Errorf("expected a ServerSelectionError wrapping ErrServerSelectionTimeout, got %v", err)
+		}
 	})
 	t.Run("Error", func(t *testing.T) {
 		desc := description.Topology{
@@ -308,6 +312,49 @@ func TestServerSelection(t *testing.T) {
 			t.Errorf("Incorrect sever selected. got %s; want %s", srvs[0].Addr, desc.Servers[1].Addr)
 		}
 	})
+	t.Run("Closed subscription", func(t *testing.T) {
+		topo, err := New()
+		noerr(t, err)
+		subCh := make(chan description.Topology)
+		close(subCh)
+
+		_, err = topo.selectServer(context.Background(), subCh, selectFirst, nil)
+		if err != ErrTopologyClosed {
+			t.Errorf("Incorrect error. got %v; want %v", err, ErrTopologyClosed)
+		}
+	})
+}
+
+func TestTopologyWaitIdle(t *testing.T) {
+	topo, err := New()
+	noerr(t, err)
+	s, err := NewServer(address.Address("one"), nil)
+	noerr(t, err)
+	topo.servers[s.address] = s
+
+	noerr(t, topo.WaitIdle(context.Background()))
+
+	sc := &sconn{Connection: writeOK{}, s: s, inUse: true}
+	s.inUse = 1
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err = topo.WaitIdle(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Incorrect error. got %v; want %v", err, context.DeadlineExceeded)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- topo.WaitIdle(context.Background()) }()
+	noerr(t, sc.Close())
+	noerr(t, sc.Close())
+	select {
+	case err = <-done:
+		noerr(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out while waiting for the connection to be checked in")
+	}
+	if s.inUse != 0 {
+		t.Errorf("Incorrect number of connections in use. got %d; want 0", s.inUse)
+	}
 }
 
 func TestSessionTimeout(t *testing.T) {
@@ -440,3 +487,33 @@ func TestSessionTimeout(t *testing.T) {
 		}
 	})
 }
+
+func TestTopologyDescriptionChangedEvent(t *testing.T) {
+	var events []*event.TopologyDescriptionChangedEvent
+	monitor := &event.ServerMonitor{
+		TopologyDescriptionChanged: func(evt *event.TopologyDescriptionChangedEvent) {
+			events = append(events, evt)
+		},
+	}
+	topo, err := New(WithServerOptions(func(opts ...ServerOption) []ServerOption {
+		return append(opts, WithServerMonitor(func(*event.ServerMonitor) *event.ServerMonitor { return monitor }))
+	}))
+	noerr(t, err)
+	topo.servers["foo:27017"] = nil
+	topo.fsm.Servers = []description.Server{{Addr: "foo:27017"}}
+
+	desc := description.Server{Addr: "foo:27017", Kind: description.Standalone}
+	topo.apply(context.Background(), desc)
+	// applying the same description again must not publish an event
+	topo.apply(context.Background(), desc)
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].TopologyID != topo.id {
+		t.Errorf("topology ID mismatch. got: %s. expected: %s", events[0].TopologyID, topo.id)
+	}
+	if events[0].NewDescription.Kind != description.Single {
+		t.Errorf("topology kind mismatch. got: %s. expected: %s", events[0].NewDescription.Kind, description.Single)
+	}
+}
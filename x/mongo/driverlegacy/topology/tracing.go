@@ -0,0 +1,67 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+var dbSystemAttribute = event.SpanAttribute{Key: event.AttributeDBSystem, Value: "mongodb"}
+
+// startSpan starts a span with the db.system attribute and the given attributes. Spans are started
+// with a background context when ctx is nil, which the driver permits.
+func startSpan(ctx context.Context, tracer event.Tracer, name string, attrs ...event.SpanAttribute) (context.Context, event.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return tracer.Start(ctx, name, append([]event.SpanAttribute{dbSystemAttribute}, attrs...)...)
+}
+
+// peerAttributes returns the net.peer.name and net.peer.port attributes of addr. The port is
+// omitted for unix domain sockets.
+func peerAttributes(addr address.Address) []event.SpanAttribute {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return []event.SpanAttribute{{Key: event.AttributeNetPeerName, Value: addr.String()}}
+	}
+
+	attrs := []event.SpanAttribute{{Key: event.AttributeNetPeerName, Value: host}}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, event.SpanAttribute{Key: event.AttributeNetPeerPort, Value: p})
+	}
+	return attrs
+}
+
+// commandAttributes returns the attributes of the span of the command in wm.
+func commandAttributes(wm wiremessage.WireMessage, addr address.Address) (string, []event.SpanAttribute) {
+	db, cmd := commandInfo(wm)
+	attrs := []event.SpanAttribute{
+		{Key: event.AttributeDBName, Value: db},
+		{Key: event.AttributeDBOperation, Value: cmd},
+	}
+	if msg, ok := wm.(wiremessage.Msg); ok {
+		for _, section := range msg.Sections {
+			body, ok := section.(wiremessage.SectionBody)
+			if !ok {
+				continue
+			}
+			if elem, err := body.Document.IndexErr(0); err == nil {
+				if coll, ok := elem.Value().StringValueOK(); ok {
+					attrs = append(attrs, event.SpanAttribute{Key: event.AttributeDBCollection, Value: coll})
+				}
+			}
+			break
+		}
+	}
+	return cmd, append(attrs, peerAttributes(addr)...)
+}
@@ -0,0 +1,113 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package topology
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+type spanKey struct{}
+
+type testSpan struct {
+	name  string
+	attrs []event.SpanAttribute
+	ended bool
+	err   error
+}
+
+func (s *testSpan) SetAttributes(attrs ...event.SpanAttribute) { s.attrs = append(s.attrs, attrs...) }
+
+func (s *testSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, attrs ...event.SpanAttribute) (context.Context, event.Span) {
+	span := &testSpan{name: name, attrs: attrs}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// spanConn records the span in the context of the last write.
+type spanConn struct {
+	recordingConn
+	span interface{}
+}
+
+func (sc *spanConn) WriteWireMessage(ctx context.Context, wm wiremessage.WireMessage) error {
+	sc.span = ctx.Value(spanKey{})
+	return sc.recordingConn.WriteWireMessage(ctx, wm)
+}
+
+func TestTracer(t *testing.T) {
+	tracer := &testTracer{}
+	s, err := NewServer(address.Address("localhost"), nil,
+		WithServerTracer(func(event.Tracer) event.Tracer { return tracer }))
+	require.NoError(t, err)
+	s.connectionstate = connected
+
+	msg := func(cmd bson.D) wiremessage.WireMessage {
+		doc, err := bson.Marshal(cmd)
+		require.NoError(t, err)
+		return wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: doc}}}
+	}
+	reply, err := bson.Marshal(bson.D{{"ok", 1}})
+	require.NoError(t, err)
+	conn := &spanConn{recordingConn: recordingConn{reply: wiremessage.Msg{
+		Sections: []wiremessage.Section{wiremessage.SectionBody{Document: reply}},
+	}}}
+	sc := &sconn{Connection: conn, s: s, id: 1}
+
+	require.NoError(t, sc.WriteWireMessage(context.Background(), msg(bson.D{{"find", "coll"}, {"$db", "test"}})))
+	require.Len(t, tracer.spans, 1)
+	find := tracer.spans[0]
+	require.Equal(t, find, conn.span, "the command was not written with the context of its span")
+	require.Equal(t, "find", find.name)
+	require.Equal(t, []event.SpanAttribute{
+		{Key: event.AttributeDBSystem, Value: "mongodb"},
+		{Key: event.AttributeDBName, Value: "test"},
+		{Key: event.AttributeDBOperation, Value: "find"},
+		{Key: event.AttributeDBCollection, Value: "coll"},
+		{Key: event.AttributeNetPeerName, Value: "localhost"},
+		{Key: event.AttributeNetPeerPort, Value: 27017},
+	}, find.attrs)
+	require.False(t, find.ended)
+	_, err = sc.ReadWireMessage(context.Background())
+	require.NoError(t, err)
+	require.True(t, find.ended)
+	require.NoError(t, find.err)
+
+	require.NoError(t, sc.WriteWireMessage(context.Background(), msg(bson.D{{"ping", 1}, {"$db", "admin"}})))
+	require.Len(t, tracer.spans, 2)
+	ping := tracer.spans[1]
+	require.Equal(t, "ping", ping.name)
+	require.NotContains(t, ping.attrs, event.SpanAttribute{Key: event.AttributeDBCollection, Value: "1"})
+	require.NoError(t, sc.Close())
+	require.True(t, ping.ended)
+	require.NoError(t, ping.err)
+}
+
+func TestPeerAttributes(t *testing.T) {
+	require.Equal(t, []event.SpanAttribute{
+		{Key: event.AttributeNetPeerName, Value: "db.example.com"},
+		{Key: event.AttributeNetPeerPort, Value: 27018},
+	}, peerAttributes(address.Address("DB.example.com:27018")))
+	require.Equal(t, []event.SpanAttribute{
+		{Key: event.AttributeNetPeerName, Value: "/tmp/mongodb-27017.sock"},
+	}, peerAttributes(address.Address("/tmp/mongodb-27017.sock")))
+}
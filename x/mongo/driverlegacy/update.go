@@ -9,6 +9,7 @@ package driverlegacy
 import (
 	"context"
 
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/x/bsonx"
 
@@ -53,6 +54,10 @@ func Update(
 
 	updateOpts := options.MergeUpdateOptions(opts...)
 
+	if ss.Description().WireVersion.Max < 8 && hasUpdatePipeline(cmd.Docs) {
+		return result.Update{}, ErrUpdatePipeline
+	}
+
 	if updateOpts.ArrayFilters != nil {
 		if ss.Description().WireVersion.Max < 6 {
 			return result.Update{}, ErrArrayFilters
@@ -84,6 +89,33 @@ func Update(
 		}
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"collation", bsonx.Document(collDoc)})
 	}
+	if updateOpts.Comment != nil {
+		if ss.Description().WireVersion.Max < 9 {
+			return result.Update{}, ErrWriteComment
+		}
+		commentElem, err := commentElement("comment", updateOpts.Comment, ss.Description().WireVersion.Max, nil)
+		if err != nil {
+			return result.Update{}, err
+		}
+		cmd.Opts = append(cmd.Opts, commentElem)
+	}
+	if updateOpts.Hint != nil {
+		if ss.Description().WireVersion.Max < 8 {
+			return result.Update{}, ErrUpdateHint
+		}
+		hintElem, err := interfaceToElement("hint", updateOpts.Hint, nil)
+		if err != nil {
+			return result.Update{}, err
+		}
+		cmd.Opts = append(cmd.Opts, hintElem)
+	}
+	if updateOpts.Let != nil {
+		letElem, err := letElement(updateOpts.Let, ss.Description().WireVersion.Max, nil)
+		if err != nil {
+			return result.Update{}, err
+		}
+		cmd.Opts = append(cmd.Opts, letElem)
+	}
 	if updateOpts.Upsert != nil {
 		cmd.Opts = append(cmd.Opts, bsonx.Elem{"upsert", bsonx.Boolean(*updateOpts.Upsert)})
 	}
@@ -103,7 +135,7 @@ func Update(
 
 	// Retry if appropriate
 	if cerr, ok := originalErr.(command.Error); (ok && cerr.Retryable()) ||
-		(res.WriteConcernError != nil && command.IsWriteConcernErrorRetryable(res.WriteConcernError)) {
+		writeConcernErrorRetryable(res.WriteConcernError, cmd.WriteConcern, topo.RetryWriteConcernTimeouts()) {
 		ss, err := topo.SelectServer(ctx, selector)
 
 		// Return original error if server selection fails or new server does not support retryable writes
@@ -111,7 +143,10 @@ func Update(
 			return res, originalErr
 		}
 
-		return update(ctx, &cmd, ss, cerr)
+		code := retryErrorCode(originalErr, res.WriteConcernError)
+		res, err = update(ctx, &cmd, ss, cerr)
+		recordWriteRetry(topo, code, err, res.WriteConcernError)
+		return res, err
 	}
 	return res, originalErr
 
@@ -125,7 +160,7 @@ func update(
 ) (result.Update, error) {
 	desc := ss.Description()
 
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		if oldErr != nil {
 			return result.Update{}, oldErr
@@ -149,3 +184,14 @@ func update(
 	ss.ProcessWriteConcernError(res.WriteConcernError)
 	return res, err
 }
+
+// hasUpdatePipeline returns true if any of the update statements in docs is an aggregation pipeline.
+func hasUpdatePipeline(docs []bsonx.Doc) bool {
+	for _, doc := range docs {
+		if u, err := doc.LookupErr("u"); err == nil && u.Type() == bsontype.Array {
+			return true
+		}
+	}
+
+	return false
+}
@@ -16,6 +16,7 @@ import (
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
 	"go.mongodb.org/mongo-driver/x/network/command"
 	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/result"
 )
 
 // Write handles the full cycle dispatch and execution of a write command against the provided
@@ -38,7 +39,7 @@ func Write(
 	}
 
 	desc := ss.Description()
-	conn, err := ss.Connection(ctx)
+	conn, err := sessionConnection(ctx, ss, cmd.Session)
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +68,41 @@ func Write(
 	return cmd.RoundTrip(ctx, desc, conn)
 }
 
+// writeConcernErrorRetryable returns true if a retryable write that failed with wce can be retried.
+// Write concern timeouts are only retried if retryTimeouts is true and w is majority. The write was
+// applied by the primary and is waiting to be replicated, so the retry, which has the same
+// transaction number, is not executed again and only waits for the write concern again.
+func writeConcernErrorRetryable(wce *result.WriteConcernError, wc *writeconcern.WriteConcern, retryTimeouts bool) bool {
+	if wce == nil {
+		return false
+	}
+	if command.IsWriteConcernErrorRetryable(wce) {
+		return true
+	}
+	return retryTimeouts && wc != nil && wc.GetW() == "majority" && command.IsWriteConcernTimeout(wce)
+}
+
+// retryErrorCode returns the code of the error that caused a write to be retried: the code of
+// cmdErr if it is a command error, otherwise the code of wce.
+func retryErrorCode(cmdErr error, wce *result.WriteConcernError) int32 {
+	if cerr, ok := cmdErr.(command.Error); ok {
+		return cerr.Code
+	}
+	if wce != nil {
+		return int32(wce.Code)
+	}
+	return 0
+}
+
+// recordWriteRetry records a retried write in the retry statistics of topo. The retry failed if it
+// returned an error or a write concern error.
+func recordWriteRetry(topo *topology.Topology, code int32, err error, wce *result.WriteConcernError) {
+	if err == nil && wce != nil {
+		err = *wce
+	}
+	topo.RecordRetry(topology.RetryWrite, code, err)
+}
+
 // Retryable writes are supported if the server supports sessions, the operation is not
 // within a transaction, and the write is acknowledged
 func retrySupported(
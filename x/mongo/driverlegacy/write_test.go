@@ -0,0 +1,69 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package driverlegacy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/network/command"
+	"go.mongodb.org/mongo-driver/x/network/result"
+)
+
+func TestWriteConcernErrorRetryable(t *testing.T) {
+	wtimeout := &result.WriteConcernError{
+		Code:    64,
+		ErrMsg:  "waiting for replication timed out",
+		ErrInfo: bson.Raw(bsoncore.BuildDocument(nil, bsoncore.AppendBooleanElement(nil, "wtimeout", true))),
+	}
+	shutdown := &result.WriteConcernError{Code: 91, ErrMsg: "shutdown in progress"}
+	majority := writeconcern.New(writeconcern.WMajority(), writeconcern.WTimeout(time.Second))
+	w2 := writeconcern.New(writeconcern.W(2), writeconcern.WTimeout(time.Second))
+
+	testCases := []struct {
+		name          string
+		wce           *result.WriteConcernError
+		wc            *writeconcern.WriteConcern
+		retryTimeouts bool
+		retry         bool
+	}{
+		{"no error", nil, majority, true, false},
+		{"retryable error", shutdown, w2, false, true},
+		{"timeout not retried by default", wtimeout, majority, false, false},
+		{"majority timeout", wtimeout, majority, true, true},
+		{"non-majority timeout", wtimeout, w2, true, false},
+		{"timeout without write concern", wtimeout, nil, true, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.retry, writeConcernErrorRetryable(tc.wce, tc.wc, tc.retryTimeouts))
+		})
+	}
+}
+
+func TestRetryErrorCode(t *testing.T) {
+	wce := &result.WriteConcernError{Code: 91, ErrMsg: "shutdown in progress"}
+
+	require.Equal(t, int32(189), retryErrorCode(command.Error{Code: 189}, wce))
+	require.Equal(t, int32(91), retryErrorCode(nil, wce))
+	require.Equal(t, int32(0), retryErrorCode(command.Error{Labels: []string{command.NetworkError}}, nil))
+	require.Equal(t, int32(0), retryErrorCode(nil, nil))
+}
+
+func TestHasUpdatePipeline(t *testing.T) {
+	update := bsonx.Doc{{"q", bsonx.Document(bsonx.Doc{})}, {"u", bsonx.Document(bsonx.Doc{{"$set", bsonx.Document(bsonx.Doc{})}})}}
+	pipeline := bsonx.Doc{{"q", bsonx.Document(bsonx.Doc{})}, {"u", bsonx.Array(bsonx.Arr{})}}
+
+	require.False(t, hasUpdatePipeline([]bsonx.Doc{update}))
+	require.True(t, hasUpdatePipeline([]bsonx.Doc{update, pipeline}))
+}
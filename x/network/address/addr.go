@@ -17,27 +17,29 @@ const defaultPort = "27017"
 type Address string
 
 // Network is the network protocol for this address. In most cases this will be
-// "tcp" or "unix".
+// "tcp" or "unix". Addresses ending in ".sock" are the paths of unix domain sockets.
 func (a Address) Network() string {
-	if strings.HasSuffix(string(a), "sock") {
+	if strings.HasSuffix(string(a), ".sock") {
 		return "unix"
 	}
 	return "tcp"
 }
 
 // String is the canonical version of this address, e.g. localhost:27017,
-// 1.2.3.4:27017, example.com:27017.
+// 1.2.3.4:27017, example.com:27017. The paths of unix domain sockets are case sensitive, so they
+// are returned unchanged.
 func (a Address) String() string {
+	if a.Network() == "unix" {
+		return string(a)
+	}
 	// TODO: unicode case folding?
 	s := strings.ToLower(string(a))
 	if len(s) == 0 {
 		return ""
 	}
-	if a.Network() != "unix" {
-		_, _, err := net.SplitHostPort(s)
-		if err != nil && strings.Contains(err.Error(), "missing port in address") {
-			s += ":" + defaultPort
-		}
+	_, _, err := net.SplitHostPort(s)
+	if err != nil && strings.Contains(err.Error(), "missing port in address") {
+		s += ":" + defaultPort
 	}
 
 	return s
@@ -22,6 +22,9 @@ func TestAddress_String(t *testing.T) {
 		{"A:27017", "a:27017"},
 		{"a:27017", "a:27017"},
 		{"a.sock", "a.sock"},
+		{"/tmp/MongoDB-27017.sock", "/tmp/MongoDB-27017.sock"},
+		{"/tmp/a:1.sock", "/tmp/a:1.sock"},
+		{"mysock", "mysock:27017"},
 	}
 
 	for _, test := range tests {
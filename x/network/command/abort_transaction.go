@@ -19,6 +19,7 @@ import (
 
 // AbortTransaction represents the abortTransaction() command
 type AbortTransaction struct {
+	Clock   *session.ClusterClock
 	Session *session.Client
 	err     error
 	result  result.TransactionResult
@@ -35,6 +36,7 @@ func (at *AbortTransaction) encode(desc description.SelectedServer) *Write {
 	return &Write{
 		DB:           "admin",
 		Command:      cmd,
+		Clock:        at.Clock,
 		Session:      at.Session,
 		WriteConcern: at.Session.CurrentWc,
 	}
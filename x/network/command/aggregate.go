@@ -22,7 +22,8 @@ import (
 
 // Aggregate represents the aggregate command.
 //
-// The aggregate command performs an aggregation.
+// The aggregate command performs an aggregation. If NS has no collection, the aggregation is run
+// against the database, as is required for pipelines that start with stages such as $currentOp.
 type Aggregate struct {
 	NS           Namespace
 	Pipeline     bsonx.Arr
@@ -49,12 +50,20 @@ func (a *Aggregate) Encode(desc description.SelectedServer) (wiremessage.WireMes
 }
 
 func (a *Aggregate) encode(desc description.SelectedServer) (*Read, error) {
-	if err := a.NS.Validate(); err != nil {
-		return nil, err
+	target := bsonx.Int32(1)
+	if a.NS.Collection == "" {
+		if err := a.NS.validateDB(); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := a.NS.Validate(); err != nil {
+			return nil, err
+		}
+		target = bsonx.String(a.NS.Collection)
 	}
 
 	command := bsonx.Doc{
-		{"aggregate", bsonx.String(a.NS.Collection)},
+		{"aggregate", target},
 		{"pipeline", bsonx.Array(a.Pipeline)},
 	}
 
@@ -98,7 +107,7 @@ func (a *Aggregate) encode(desc description.SelectedServer) (*Read, error) {
 	}, nil
 }
 
-// HasDollarOut returns true if the Pipeline field contains a $out stage.
+// HasDollarOut returns true if the Pipeline field ends with a $out or $merge stage.
 func (a *Aggregate) HasDollarOut() bool {
 	if a.Pipeline == nil {
 		return false
@@ -113,7 +122,7 @@ func (a *Aggregate) HasDollarOut() bool {
 	if !ok || len(doc) != 1 {
 		return false
 	}
-	return doc[0].Key == "$out"
+	return doc[0].Key == "$out" || doc[0].Key == "$merge"
 }
 
 // Decode will decode the wire message using the provided server description. Errors during decoding
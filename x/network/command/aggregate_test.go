@@ -7,6 +7,7 @@
 package command
 
 import (
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/internal/testutil/helpers"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/x/bsonx"
@@ -68,3 +69,22 @@ func TestAggregate(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregateDatabase(t *testing.T) {
+	desc := description.SelectedServer{Server: description.Server{WireVersion: &description.VersionRange{Max: 6}}}
+
+	cmd := Aggregate{NS: Namespace{DB: "admin"}, Pipeline: bsonx.Arr{}}
+	readCmd, err := cmd.encode(desc)
+	testhelpers.RequireNil(t, err, "error encoding: %s", err)
+	if target := readCmd.Command.Lookup("aggregate"); target.Type() != bsontype.Int32 || target.Int32() != 1 {
+		t.Fatalf("expected aggregate: 1, got %v", target)
+	}
+	if readCmd.DB != "admin" {
+		t.Fatalf("expected the command to be run against admin, got %s", readCmd.DB)
+	}
+
+	cmd = Aggregate{NS: Namespace{DB: "a.b"}, Pipeline: bsonx.Arr{}}
+	if _, err = cmd.encode(desc); err == nil {
+		t.Fatal("expected an error for an invalid database name")
+	}
+}
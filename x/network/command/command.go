@@ -13,6 +13,8 @@ import (
 
 	"fmt"
 
+	"strconv"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -192,8 +194,8 @@ func marshalCommand(cmd bsonx.Doc) (bson.Raw, error) {
 	return cmd.MarshalBSON()
 }
 
-// adds session related fields to a BSON doc representing a command
-func addSessionFields(cmd bsonx.Doc, desc description.SelectedServer, client *session.Client) (bsonx.Doc, error) {
+// adds session related fields to a BSON doc representing a command sent to the database db
+func addSessionFields(cmd bsonx.Doc, db string, desc description.SelectedServer, client *session.Client) (bsonx.Doc, error) {
 	if client == nil || !description.SessionsSupported(desc.WireVersion) || desc.SessionTimeoutMinutes == 0 {
 		return cmd, nil
 	}
@@ -214,10 +216,26 @@ func addSessionFields(cmd bsonx.Doc, desc description.SelectedServer, client *se
 	}
 
 	client.ApplyCommand(desc.Server) // advance the state machine based on a command executing
+	if len(cmd) > 0 {
+		client.RecordOperation(commandNamespace(cmd, db), cmd[0].Key)
+	}
 
 	return cmd, nil
 }
 
+// commandNamespace returns the namespace of the collection cmd operates on, or db if it does not
+// operate on a collection.
+func commandNamespace(cmd bsonx.Doc, db string) string {
+	coll, ok := cmd[0].Value.StringValueOK()
+	if cmd[0].Key == "getMore" {
+		coll, ok = cmd.Lookup("collection").StringValueOK()
+	}
+	if !ok || coll == "" {
+		return db
+	}
+	return db + "." + coll
+}
+
 // if in a transaction, add the transaction fields
 func addTransaction(cmd bsonx.Doc, client *session.Client) bsonx.Doc {
 	cmd = append(cmd, bsonx.Elem{"txnNumber", bsonx.Int64(client.TxnNumber)})
@@ -276,6 +294,10 @@ func addReadConcern(cmd bsonx.Doc, desc description.SelectedServer, rc *readconc
 		return cmd, nil
 	}
 
+	if !readConcernLevelSupported(rc.GetLevel(), desc.WireVersion) {
+		return cmd, ErrUnsupportedReadConcernLevel
+	}
+
 	t, data, err := rc.MarshalBSONValue()
 	if err != nil {
 		return cmd, err
@@ -298,6 +320,53 @@ func addReadConcern(cmd bsonx.Doc, desc description.SelectedServer, rc *readconc
 	return cmd, nil
 }
 
+// minReadConcernWireVersions maps read concern levels to the minimum wire version of a server that
+// supports them.
+var minReadConcernWireVersions = map[string]int32{
+	"linearizable": 5,
+	"available":    6,
+	"snapshot":     7,
+}
+
+// readConcernLevelSupported returns true if a server with the given wire version range supports the
+// read concern level. If the wire version is unknown, the level is assumed to be supported.
+func readConcernLevelSupported(level string, wv *description.VersionRange) bool {
+	min, ok := minReadConcernWireVersions[level]
+	return !ok || wv == nil || wv.Max >= min
+}
+
+// snapshotReadsWireVersion is the minimum wire version of a server that supports snapshot reads
+// outside of transactions.
+const snapshotReadsWireVersion = 13
+
+// snapshotReadCommands are the commands that read at the snapshot of a snapshot session.
+var snapshotReadCommands = map[string]bool{
+	"find":      true,
+	"aggregate": true,
+	"distinct":  true,
+}
+
+// add a snapshot read concern to a BSON doc representing a find, aggregate or distinct command sent
+// in a snapshot session. Once the session's snapshot time is known, it is sent as atClusterTime so
+// every read in the session sees the same snapshot.
+func addSnapshotReadConcern(cmd bsonx.Doc, desc description.SelectedServer, sess *session.Client) (bsonx.Doc, error) {
+	if sess == nil || !sess.Snapshot || len(cmd) == 0 || !snapshotReadCommands[cmd[0].Key] {
+		return cmd, nil
+	}
+
+	if desc.WireVersion != nil && desc.WireVersion.Max < snapshotReadsWireVersion {
+		return cmd, ErrSnapshotReadsUnsupported
+	}
+
+	rcDoc := bsonx.Doc{{"level", bsonx.String("snapshot")}}
+	if sess.SnapshotTime != nil {
+		rcDoc = append(rcDoc, bsonx.Elem{"atClusterTime", bsonx.Timestamp(sess.SnapshotTime.T, sess.SnapshotTime.I)})
+	}
+
+	cmd = cmd.Delete("readConcern")
+	return append(cmd, bsonx.Elem{"readConcern", bsonx.Document(rcDoc)}), nil
+}
+
 // add a write concern to a BSON doc representing a command
 func addWriteConcern(cmd bsonx.Doc, wc *writeconcern.WriteConcern) (bsonx.Doc, error) {
 	if wc == nil {
@@ -393,47 +462,81 @@ func opmsgCreateDocSequence(arr bsonx.Arr, identifier string) (wiremessage.Secti
 	return docSequence, nil
 }
 
-func splitBatches(docs []bsonx.Doc, maxCount, targetBatchSize int) ([][]bsonx.Doc, error) {
-	batches := [][]bsonx.Doc{}
+// splitBatches splits docs into batches of at most maxCount documents that each fit in a single
+// command. A document can be at most maxDocumentSize bytes, and the documents of a batch, along
+// with the array element headers that hold them, are limited to maxDocumentSize bytes so the
+// command stays within the maxDocumentSize plus 16KiB accepted by the server.
+func splitBatches(docs []bsonx.Doc, maxCount, maxDocumentSize int) ([][]bsonx.Doc, error) {
+	lengths, err := batchLengths(len(docs), func(i int) int {
+		raw, _ := docs[i].MarshalBSON()
+		return len(raw)
+	}, maxCount, maxDocumentSize)
+	if err != nil {
+		return nil, err
+	}
+
+	batches := make([][]bsonx.Doc, 0, len(lengths))
+	for _, n := range lengths {
+		batches = append(batches, docs[:n])
+		docs = docs[n:]
+	}
+	return batches, nil
+}
+
+// splitRawBatches is splitBatches for documents that are already encoded.
+func splitRawBatches(docs []bson.Raw, maxCount, maxDocumentSize int) ([][]bson.Raw, error) {
+	lengths, err := batchLengths(len(docs), func(i int) int { return len(docs[i]) }, maxCount, maxDocumentSize)
+	if err != nil {
+		return nil, err
+	}
 
-	if targetBatchSize > reservedCommandBufferBytes {
-		targetBatchSize -= reservedCommandBufferBytes
+	batches := make([][]bson.Raw, 0, len(lengths))
+	for _, n := range lengths {
+		batches = append(batches, docs[:n])
+		docs = docs[n:]
 	}
+	return batches, nil
+}
 
+// batchLengths returns the number of documents in each batch when the count documents, whose
+// encoded sizes are given by size, are split as described by splitBatches.
+func batchLengths(count int, size func(int) int, maxCount, maxDocumentSize int) ([]int, error) {
 	if maxCount <= 0 {
 		maxCount = 1
 	}
 
+	lengths := []int{}
 	startAt := 0
-splitInserts:
 	for {
-		size := 0
-		batch := []bsonx.Doc{}
-	assembleBatch:
-		for idx := startAt; idx < len(docs); idx++ {
-			raw, _ := docs[idx].MarshalBSON()
-
-			if len(raw) > targetBatchSize {
+		total, n := 0, 0
+		for idx := startAt; idx < count; idx++ {
+			docSize := size(idx)
+			if docSize > maxDocumentSize {
 				return nil, ErrDocumentTooLarge
 			}
-			if size+len(raw) > targetBatchSize {
-				break assembleBatch
+			docSize += arrayElementOverhead(n)
+			if n > 0 && total+docSize > maxDocumentSize {
+				break
 			}
 
-			size += len(raw)
-			batch = append(batch, docs[idx])
+			total += docSize
+			n++
 			startAt++
-			if len(batch) == maxCount {
-				break assembleBatch
+			if n == maxCount {
+				break
 			}
 		}
-		batches = append(batches, batch)
-		if startAt == len(docs) {
-			break splitInserts
+		lengths = append(lengths, n)
+		if startAt == count {
+			return lengths, nil
 		}
 	}
+}
 
-	return batches, nil
+// arrayElementOverhead returns the number of bytes, other than the value, taken by the element at
+// index i of a BSON array: the type byte and the null-terminated decimal key.
+func arrayElementOverhead(i int) int {
+	return 2 + len(strconv.Itoa(i))
 }
 
 func encodeBatch(
@@ -484,6 +587,15 @@ func batchesToWireMessage(batches []*WriteBatch, desc description.SelectedServer
 	return wms, nil
 }
 
+// offsetWriteErrors adds offset to the index of each write error, converting indexes relative to a
+// batch into indexes relative to the command the batch was split from.
+func offsetWriteErrors(wes []result.WriteError, offset int64) []result.WriteError {
+	for i := range wes {
+		wes[i].Index += int(offset)
+	}
+	return wes
+}
+
 // Roundtrips the write batches, returning the result structs (as interface),
 // the write batches that weren't round tripped and any errors
 func roundTripBatches(
@@ -496,7 +608,7 @@ func roundTripBatches(
 	cmdKind WriteCommandKind,
 ) (interface{}, []*WriteBatch, error) {
 	var res interface{}
-	var upsertIndex int64 // the operation index for the upserted IDs map
+	var opIndex int64 // the index of the first operation of the batch in the command
 
 	// hold onto txnNumber, reset it when loop exits to ensure reuse of same
 	// transaction number if retry is needed
@@ -527,7 +639,7 @@ func roundTripBatches(
 				return res, batches, err
 			}
 
-			conv.WriteErrors = append(conv.WriteErrors, r.WriteErrors...)
+			conv.WriteErrors = append(conv.WriteErrors, offsetWriteErrors(r.WriteErrors, opIndex)...)
 
 			if r.WriteConcernError != nil {
 				conv.WriteConcernError = r.WriteConcernError
@@ -556,7 +668,7 @@ func roundTripBatches(
 				return conv, batches, err
 			}
 
-			conv.WriteErrors = append(conv.WriteErrors, r.WriteErrors...)
+			conv.WriteErrors = append(conv.WriteErrors, offsetWriteErrors(r.WriteErrors, opIndex)...)
 
 			if r.WriteConcernError != nil {
 				conv.WriteConcernError = r.WriteConcernError
@@ -570,7 +682,7 @@ func roundTripBatches(
 			conv.ModifiedCount += r.ModifiedCount
 			for _, upsert := range r.Upserted {
 				conv.Upserted = append(conv.Upserted, result.Upsert{
-					Index: upsert.Index + upsertIndex,
+					Index: upsert.Index + opIndex,
 					ID:    upsert.ID,
 				})
 			}
@@ -580,7 +692,6 @@ func roundTripBatches(
 			}
 
 			res = conv
-			upsertIndex += int64(cmd.numDocs)
 		case DeleteCommand:
 			if res == nil {
 				res = result.Delete{}
@@ -593,7 +704,7 @@ func roundTripBatches(
 				return conv, batches, err
 			}
 
-			conv.WriteErrors = append(conv.WriteErrors, r.WriteErrors...)
+			conv.WriteErrors = append(conv.WriteErrors, offsetWriteErrors(r.WriteErrors, opIndex)...)
 
 			if r.WriteConcernError != nil {
 				conv.WriteConcernError = r.WriteConcernError
@@ -612,6 +723,8 @@ func roundTripBatches(
 			res = conv
 		}
 
+		opIndex += int64(cmd.numDocs)
+
 		// Increment txnNumber for each batch
 		if sess != nil && sess.RetryWrite {
 			sess.IncrementTxnNumber()
@@ -10,7 +10,11 @@ import (
 	"testing"
 	"time"
 
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
 	"go.mongodb.org/mongo-driver/x/network/description"
 	"go.mongodb.org/mongo-driver/x/network/wiremessage"
 )
@@ -156,3 +160,73 @@ func TestCommandEncode(t *testing.T) {
 		}
 	})
 }
+
+func TestAddReadConcernValidatesLevel(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rc          *readconcern.ReadConcern
+		wireVersion *description.VersionRange
+		err         error
+	}{
+		{"majority on 3.0", readconcern.Majority(), &description.VersionRange{Max: 3}, nil},
+		{"linearizable on 3.2", readconcern.Linearizable(), &description.VersionRange{Max: 4}, ErrUnsupportedReadConcernLevel},
+		{"linearizable on 3.4", readconcern.Linearizable(), &description.VersionRange{Max: 5}, nil},
+		{"available on 3.4", readconcern.Available(), &description.VersionRange{Max: 5}, ErrUnsupportedReadConcernLevel},
+		{"available on 3.6", readconcern.Available(), &description.VersionRange{Max: 6}, nil},
+		{"snapshot on 3.6", readconcern.Snapshot(), &description.VersionRange{Max: 6}, ErrUnsupportedReadConcernLevel},
+		{"snapshot on 4.0", readconcern.Snapshot(), &description.VersionRange{Max: 7}, nil},
+		{"snapshot with unknown wire version", readconcern.Snapshot(), nil, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			desc := description.SelectedServer{Server: description.Server{WireVersion: tc.wireVersion}}
+			_, err := addReadConcern(nil, desc, tc.rc, nil)
+			if err != tc.err {
+				t.Errorf("errors do not match. got %v; want %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestAddSessionFieldsRecordsOperations(t *testing.T) {
+	var ops []session.Operation
+	id, _ := uuid.New()
+	sess, err := session.NewClientSession(&session.Pool{}, id, session.Explicit, &session.ClientOptions{
+		AuditHook: func(op session.Operation) { ops = append(ops, op) },
+	})
+	noerr(t, err)
+	desc := description.SelectedServer{Server: description.Server{
+		WireVersion:           &description.VersionRange{Max: 6},
+		SessionTimeoutMinutes: 30,
+	}}
+
+	cmds := []bsonx.Doc{
+		{{"find", bsonx.String("orders")}},
+		{{"getMore", bsonx.Int64(42)}, {"collection", bsonx.String("orders")}},
+		{{"commitTransaction", bsonx.Int32(1)}},
+	}
+	for _, cmd := range cmds {
+		_, err = addSessionFields(cmd, "app", desc, sess)
+		noerr(t, err)
+	}
+
+	want := []struct {
+		ns, name string
+	}{
+		{"app.orders", "find"},
+		{"app.orders", "getMore"},
+		{"app", "commitTransaction"},
+	}
+	if len(ops) != len(want) {
+		t.Fatalf("recorded %d operations; want %d", len(ops), len(want))
+	}
+	for i, op := range ops {
+		if op.Number != int64(i+1) || op.Namespace != want[i].ns || op.CommandName != want[i].name || op.Time.IsZero() {
+			t.Errorf("operation %d does not match. got %+v; want %v", i, op, want[i])
+		}
+	}
+	if count := sess.OperationCount(); count != 3 {
+		t.Errorf("operation count does not match. got %d; want 3", count)
+	}
+}
@@ -19,6 +19,7 @@ import (
 
 // CommitTransaction represents the commitTransaction() command
 type CommitTransaction struct {
+	Clock   *session.ClusterClock
 	Session *session.Client
 	err     error
 	result  result.TransactionResult
@@ -39,6 +40,7 @@ func (ct *CommitTransaction) encode(desc description.SelectedServer) *Write {
 	return &Write{
 		DB:           "admin",
 		Command:      cmd,
+		Clock:        ct.Clock,
 		Session:      ct.Session,
 		WriteConcern: ct.Session.CurrentWc,
 	}
@@ -0,0 +1,78 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
+	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+func TestTransactionCommandsClusterTime(t *testing.T) {
+	clusterTime := func(t *testing.T, ts uint32) bson.Raw {
+		b, err := bsonx.Doc{{"$clusterTime", bsonx.Document(bsonx.Doc{
+			{"clusterTime", bsonx.Timestamp(ts, 1)},
+		})}}.MarshalBSON()
+		require.NoError(t, err)
+		return b
+	}
+	desc := description.SelectedServer{
+		Server: description.Server{Kind: description.RSPrimary, WireVersion: &description.VersionRange{Max: 8}},
+		Kind:   description.ReplicaSetWithPrimary,
+	}
+
+	testCases := []struct {
+		name   string
+		encode func(*session.ClusterClock, *session.Client) *Write
+	}{
+		{"commitTransaction", func(clock *session.ClusterClock, sess *session.Client) *Write {
+			return (&CommitTransaction{Clock: clock, Session: sess}).encode(desc)
+		}},
+		{"abortTransaction", func(clock *session.ClusterClock, sess *session.Client) *Write {
+			return (&AbortTransaction{Clock: clock, Session: sess}).encode(desc)
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			id, err := uuid.New()
+			require.NoError(t, err)
+			sess, err := session.NewClientSession(session.NewPool(nil), id, session.Explicit)
+			require.NoError(t, err)
+			clock := &session.ClusterClock{}
+			clock.AdvanceClusterTime(clusterTime(t, 10))
+
+			cmd := tc.encode(clock, sess)
+			wm, err := cmd.Encode(desc)
+			require.NoError(t, err)
+			msg := wm.(wiremessage.Msg)
+			doc, err := msg.GetMainDocument()
+			require.NoError(t, err)
+			sent, err := doc.LookupErr("$clusterTime", "clusterTime")
+			require.NoError(t, err)
+			ts, _ := sent.Timestamp()
+			require.Equal(t, uint32(10), ts)
+
+			reply, err := bsonx.Doc{
+				{"ok", bsonx.Int32(1)},
+				{"$clusterTime", bsonx.Document(bsonx.Doc{{"clusterTime", bsonx.Timestamp(20, 1)}})},
+			}.MarshalBSON()
+			require.NoError(t, err)
+			_, err = cmd.Decode(desc, wiremessage.Msg{
+				Sections: []wiremessage.Section{wiremessage.SectionBody{Document: reply}},
+			}).Result()
+			require.NoError(t, err)
+			require.Equal(t, clusterTime(t, 20), clock.GetClusterTime())
+			require.Equal(t, clusterTime(t, 20), sess.ClusterTime)
+		})
+	}
+}
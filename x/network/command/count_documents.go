@@ -112,11 +112,13 @@ func (c *CountDocuments) RoundTrip(ctx context.Context, desc description.Selecte
 
 	err = rw.WriteWireMessage(ctx, wm)
 	if err != nil {
-		if _, ok := err.(Error); ok {
+		switch err.(type) {
+		case Error, ReadOnlyError, NamespaceDeniedError, OperationLimitError:
 			return 0, err
 		}
 		// Connection errors are transient
 		c.Session.ClearPinnedServer()
+		c.Session.MarkDirty()
 		return 0, Error{Message: err.Error(), Labels: []string{TransientTransactionError, NetworkError}}
 	}
 
@@ -127,6 +129,7 @@ func (c *CountDocuments) RoundTrip(ctx context.Context, desc description.Selecte
 		}
 		// Connection errors are transient
 		c.Session.ClearPinnedServer()
+		c.Session.MarkDirty()
 		return 0, Error{Message: err.Error(), Labels: []string{TransientTransactionError, NetworkError}}
 	}
 
@@ -72,11 +72,13 @@ func (d *Delete) encodeBatch(docs []bsonx.Doc, desc description.SelectedServer)
 
 	var options []bsonx.Elem
 	for _, opt := range d.Opts {
-		if opt.Key == "collation" {
+		switch opt.Key {
+		case "collation", "hint":
+			// options that are encoded on each individual document
 			for idx := range copyDocs {
 				copyDocs[idx] = append(copyDocs[idx], opt)
 			}
-		} else {
+		default:
 			options = append(options, opt)
 		}
 	}
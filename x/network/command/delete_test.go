@@ -0,0 +1,35 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package command
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/network/description"
+)
+
+func TestDelete(t *testing.T) {
+	t.Run("encodes per-statement options on each delete", func(t *testing.T) {
+		hint := bsonx.Document(bsonx.Doc{{"x", bsonx.Int32(1)}})
+		cmd := Delete{
+			NS:      Namespace{DB: "foo", Collection: "bar"},
+			Deletes: []bsonx.Doc{{{"q", bsonx.Document(bsonx.Doc{})}, {"limit", bsonx.Int32(1)}}},
+			Opts:    []bsonx.Elem{{"hint", hint}},
+		}
+		batch, err := cmd.encodeBatch(cmd.Deletes, description.SelectedServer{})
+		noerr(t, err)
+
+		statement := batch.Command.Lookup("deletes").Array()[0].Document()
+		if got, err := statement.LookupErr("hint"); err != nil || !got.Equal(hint) {
+			t.Errorf("expected the hint on the delete statement, got %v", statement)
+		}
+		if _, err := batch.Command.LookupErr("hint"); err == nil {
+			t.Errorf("expected the hint to be omitted from the command, got %v", batch.Command)
+		}
+	})
+}
@@ -30,18 +30,65 @@ var (
 	ErrDocumentTooLarge = errors.New("an inserted document is too large")
 	// ErrNonPrimaryRP occurs when a nonprimary read preference is used with a transaction.
 	ErrNonPrimaryRP = errors.New("read preference in a transaction must be primary")
+	// ErrUnsupportedReadConcernLevel occurs when a read concern level is used with a server that does
+	// not support it.
+	ErrUnsupportedReadConcernLevel = errors.New("read concern level is not supported by the server")
+	// ErrLinearizableReadPref occurs when a linearizable read concern is used with a non-primary read
+	// preference. Linearizable reads can only be run on the primary.
+	ErrLinearizableReadPref = errors.New("linearizable read concern requires a primary read preference")
+	// ErrSnapshotReadsUnsupported occurs when a snapshot session is used to read from a server older
+	// than MongoDB 5.0.
+	ErrSnapshotReadsUnsupported = errors.New("snapshot reads require MongoDB 5.0 or later")
 	// UnknownTransactionCommitResult is an error label for unknown transaction commit results.
 	UnknownTransactionCommitResult = "UnknownTransactionCommitResult"
 	// TransientTransactionError is an error label for transient errors with transactions.
 	TransientTransactionError = "TransientTransactionError"
 	// NetworkError is an error label for network errors.
 	NetworkError = "NetworkError"
+	// RetryableWriteError is an error label the server attaches to errors for which a retryable write
+	// may be retried.
+	RetryableWriteError = "RetryableWriteError"
 	// ReplyDocumentMismatch is an error label for OP_QUERY field mismatch errors.
 	ReplyDocumentMismatch = "malformed OP_REPLY: NumberReturned does not match number of documents returned"
 )
 
 var retryableCodes = []int32{11600, 11602, 10107, 13435, 13436, 189, 91, 7, 6, 89, 9001}
 
+// ReadOnlyError is returned when a write command is sent through a connection that only allows
+// commands that do not modify data.
+type ReadOnlyError struct {
+	Command string
+}
+
+// Error implements the error interface.
+func (e ReadOnlyError) Error() string {
+	return fmt.Sprintf("%s is a write command and cannot be run by a read-only client", e.Command)
+}
+
+// NamespaceDeniedError is returned when a command is sent through a connection that does not allow
+// commands on its namespace.
+type NamespaceDeniedError struct {
+	Namespace string
+	Command   string
+}
+
+// Error implements the error interface.
+func (e NamespaceDeniedError) Error() string {
+	return fmt.Sprintf("%s command on namespace %s is not allowed by the namespace filter of the client", e.Command, e.Namespace)
+}
+
+// OperationLimitError is returned when a command waited for one of the in-flight operation slots of
+// a client until its context was done. The command is not sent.
+type OperationLimitError struct {
+	Limit   int
+	Wrapped error
+}
+
+// Error implements the error interface.
+func (e OperationLimitError) Error() string {
+	return fmt.Sprintf("gave up waiting for one of %d in-flight operation slots: %v", e.Limit, e.Wrapped)
+}
+
 // QueryFailureError is an error representing a command failure as a document.
 type QueryFailureError struct {
 	Message  string
@@ -102,6 +149,26 @@ func (e Error) HasErrorLabel(label string) bool {
 
 // Retryable returns true if the error is retryable
 func (e Error) Retryable() bool {
+	for _, label := range e.Labels {
+		if label == NetworkError || label == RetryableWriteError {
+			return true
+		}
+	}
+	for _, code := range retryableCodes {
+		if e.Code == code {
+			return true
+		}
+	}
+	if strings.Contains(e.Message, "not master") || strings.Contains(e.Message, "node is recovering") {
+		return true
+	}
+
+	return false
+}
+
+// RetryableRead returns true if the error is retryable for a read operation. Unlike Retryable, it
+// does not consider the RetryableWriteError label, which servers only attach to write errors.
+func (e Error) RetryableRead() bool {
 	for _, label := range e.Labels {
 		if label == NetworkError {
 			return true
@@ -133,6 +200,16 @@ func IsWriteConcernErrorRetryable(wce *result.WriteConcernError) bool {
 	return false
 }
 
+// IsWriteConcernTimeout returns true if the write concern error reports that the write concern was
+// not satisfied within its wtimeout.
+func IsWriteConcernTimeout(wce *result.WriteConcernError) bool {
+	if wce.Code != 64 {
+		return false
+	}
+	timeout, ok := wce.ErrInfo.Lookup("wtimeout").BooleanOK()
+	return ok && timeout
+}
+
 // IsNotFound indicates if the error is from a namespace not being found.
 func IsNotFound(err error) bool {
 	e, ok := err.(Error)
@@ -0,0 +1,81 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package command
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/network/result"
+)
+
+func TestErrorRetryable(t *testing.T) {
+	testCases := []struct {
+		name      string
+		err       Error
+		retryable bool
+	}{
+		{"network error label", Error{Labels: []string{NetworkError}}, true},
+		{"retryable write error label", Error{Labels: []string{RetryableWriteError}}, true},
+		{"retryable code", Error{Code: 91}, true},
+		{"not master message", Error{Message: "not master"}, true},
+		{"transient transaction label", Error{Labels: []string{TransientTransactionError}}, false},
+		{"non-retryable code", Error{Code: 11000}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.err.Retryable(); got != tc.retryable {
+				t.Errorf("expected Retryable to return %v, got %v", tc.retryable, got)
+			}
+		})
+	}
+}
+
+func TestErrorRetryableRead(t *testing.T) {
+	testCases := []struct {
+		name      string
+		err       Error
+		retryable bool
+	}{
+		{"network error label", Error{Labels: []string{NetworkError}}, true},
+		{"retryable write error label", Error{Labels: []string{RetryableWriteError}}, false},
+		{"retryable code", Error{Code: 91}, true},
+		{"node is recovering message", Error{Message: "node is recovering"}, true},
+		{"non-retryable code", Error{Code: 11000}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.err.RetryableRead(); got != tc.retryable {
+				t.Errorf("expected RetryableRead to return %v, got %v", tc.retryable, got)
+			}
+		})
+	}
+}
+
+func TestIsWriteConcernTimeout(t *testing.T) {
+	wtimeout := bsoncore.BuildDocument(nil, bsoncore.AppendBooleanElement(nil, "wtimeout", true))
+	testCases := []struct {
+		name    string
+		wce     result.WriteConcernError
+		timeout bool
+	}{
+		{"wtimeout", result.WriteConcernError{Code: 64, ErrInfo: bson.Raw(wtimeout)}, true},
+		{"no errInfo", result.WriteConcernError{Code: 64}, false},
+		{"other code", result.WriteConcernError{Code: 100, ErrInfo: bson.Raw(wtimeout)}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsWriteConcernTimeout(&tc.wce); got != tc.timeout {
+				t.Errorf("expected IsWriteConcernTimeout to return %v, got %v", tc.timeout, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package command
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+// Explainable is a command that can be explained. It is implemented by *Find and *Aggregate.
+type Explainable interface {
+	encode(desc description.SelectedServer) (*Read, error)
+}
+
+// Explain represents the explain command.
+//
+// The explain command returns information about how the server executes another command, such as
+// the plan it chose, at the given verbosity. The explained command is not run to completion and
+// does not create a cursor. It is sent to the database of the explained command with its read
+// preference and session.
+type Explain struct {
+	Command   Explainable
+	Verbosity string
+
+	result bson.Raw
+	err    error
+}
+
+// Encode will encode this command into a wire message for the given server description.
+func (e *Explain) Encode(desc description.SelectedServer) (wiremessage.WireMessage, error) {
+	cmd, err := e.encode(desc)
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd.Encode(desc)
+}
+
+func (e *Explain) encode(desc description.SelectedServer) (*Read, error) {
+	explained, err := e.Command.encode(desc)
+	if err != nil {
+		return nil, err
+	}
+
+	command := bsonx.Doc{{"explain", bsonx.Document(explained.Command)}}
+	if e.Verbosity != "" {
+		command = append(command, bsonx.Elem{"verbosity", bsonx.String(e.Verbosity)})
+	}
+
+	return &Read{
+		Clock:    explained.Clock,
+		DB:       explained.DB,
+		ReadPref: explained.ReadPref,
+		Command:  command,
+		Session:  explained.Session,
+	}, nil
+}
+
+// Decode will decode the wire message using the provided server description. Errors during decoding
+// are deferred until either the Result or Err methods are called.
+func (e *Explain) Decode(desc description.SelectedServer, wm wiremessage.WireMessage) *Explain {
+	rdr, err := (&Read{}).Decode(desc, wm).Result()
+	if err != nil {
+		e.err = err
+		return e
+	}
+
+	return e.decode(desc, rdr)
+}
+
+func (e *Explain) decode(desc description.SelectedServer, rdr bson.Raw) *Explain {
+	e.result = rdr
+	return e
+}
+
+// Result returns the result of a decoded wire message and server description.
+func (e *Explain) Result() (bson.Raw, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	return e.result, nil
+}
+
+// Err returns the error set on this command.
+func (e *Explain) Err() error { return e.err }
+
+// RoundTrip handles the execution of this command using the provided wiremessage.ReadWriter.
+func (e *Explain) RoundTrip(ctx context.Context, desc description.SelectedServer, rw wiremessage.ReadWriter) (bson.Raw, error) {
+	cmd, err := e.encode(desc)
+	if err != nil {
+		return nil, err
+	}
+
+	rdr, err := cmd.RoundTrip(ctx, desc, rw)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.decode(desc, rdr).Result()
+}
@@ -24,7 +24,7 @@ import (
 type FindOneAndUpdate struct {
 	NS           Namespace
 	Query        bsonx.Doc
-	Update       bsonx.Doc
+	Update       bsonx.Val // an update document or an aggregation pipeline
 	Opts         []bsonx.Elem
 	WriteConcern *writeconcern.WriteConcern
 	Clock        *session.ClusterClock
@@ -52,7 +52,7 @@ func (f *FindOneAndUpdate) encode(desc description.SelectedServer) (*Write, erro
 	command := bsonx.Doc{
 		{"findAndModify", bsonx.String(f.NS.Collection)},
 		{"query", bsonx.Document(f.Query)},
-		{"update", bsonx.Document(f.Update)},
+		{"update", f.Update},
 	}
 	command = append(command, f.Opts...)
 
@@ -8,24 +8,36 @@ package command
 
 import (
 	"context"
+	"errors"
 	"runtime"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/version"
 	"go.mongodb.org/mongo-driver/x/bsonx"
 	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/connection"
 	"go.mongodb.org/mongo-driver/x/network/description"
 	"go.mongodb.org/mongo-driver/x/network/result"
 	"go.mongodb.org/mongo-driver/x/network/wiremessage"
 )
 
+// ErrLoadBalancedNotSupported is returned by a handshake in load balanced mode when the server does
+// not support connecting through a load balancer.
+var ErrLoadBalancedNotSupported = errors.New("the driver attempted to connect in load balanced mode, " +
+	"but the server does not support this mode")
+
 // Handshake represents a generic MongoDB Handshake. It calls isMaster and
 // buildInfo.
 //
 // The isMaster and buildInfo commands are used to build a server description.
+//
+// If LoadBalanced is true, the server must reply with a serviceId, and the returned description is
+// of kind description.LoadBalancer.
 type Handshake struct {
 	Client             bsonx.Doc
 	Compressors        []string
 	SaslSupportedMechs string
+	LoadBalanced       bool
 
 	ismstr result.IsMaster
 	err    error
@@ -38,6 +50,7 @@ func (h *Handshake) Encode() (wiremessage.WireMessage, error) {
 		Client:             h.Client,
 		Compressors:        h.Compressors,
 		SaslSupportedMechs: h.SaslSupportedMechs,
+		LoadBalanced:       h.LoadBalanced,
 	}).Encode()
 	if err != nil {
 		return wm, err
@@ -63,7 +76,14 @@ func (h *Handshake) Result(addr address.Address) (description.Server, error) {
 	if h.err != nil {
 		return description.Server{}, h.err
 	}
-	return description.NewServer(addr, h.ismstr), nil
+	desc := description.NewServer(addr, h.ismstr)
+	if h.LoadBalanced {
+		if desc.ServiceID.IsZero() {
+			return description.Server{}, ErrLoadBalancedNotSupported
+		}
+		desc.Kind = description.LoadBalancer
+	}
+	return desc, nil
 }
 
 // Err returns the error set on this Handshake.
@@ -72,7 +92,10 @@ func (h *Handshake) Err() error { return h.err }
 // Handshake implements the connection.Handshaker interface. It is identical
 // to the RoundTrip methods on other types in this package. It will execute
 // the isMaster command.
-func (h *Handshake) Handshake(ctx context.Context, addr address.Address, rw wiremessage.ReadWriter) (description.Server, error) {
+func (h *Handshake) Handshake(ctx context.Context, addr address.Address, rw wiremessage.ReadWriter) (desc description.Server, err error) {
+	finish := connection.StartStage(ctx, event.StageHandshake)
+	defer func() { finish(err) }()
+
 	wm, err := h.Encode()
 	if err != nil {
 		return description.Server{}, err
@@ -90,28 +113,81 @@ func (h *Handshake) Handshake(ctx context.Context, addr address.Address, rw wire
 	return h.Decode(wm).Result(addr)
 }
 
+// MaxAppNameLength is the maximum length in bytes of the application name sent in the client
+// metadata document of the handshake.
+const MaxAppNameLength = 128
+
+// maxClientDocSize is the maximum size in bytes of the client metadata document. Servers reject
+// handshakes with larger documents.
+const maxClientDocSize = 512
+
+// DriverInfo describes a library that wraps the driver. Its fields are appended to the driver name
+// and version and to the platform of the client metadata document, separated by "|".
+type DriverInfo struct {
+	Name     string
+	Version  string
+	Platform string
+}
+
 // ClientDoc creates a client information document for use in an isMaster
 // command.
 func ClientDoc(app string) bsonx.Doc {
-	doc := bsonx.Doc{
-		{"driver",
-			bsonx.Document(bsonx.Doc{
-				{"name", bsonx.String("mongo-go-driver")},
-				{"version", bsonx.String(version.Driver)},
-			}),
-		},
-		{"os",
-			bsonx.Document(bsonx.Doc{
-				{"type", bsonx.String(runtime.GOOS)},
-				{"architecture", bsonx.String(runtime.GOARCH)},
-			}),
-		},
-		{"platform", bsonx.String(runtime.Version())},
-	}
+	return ClientDocWithDriverInfo(app, DriverInfo{})
+}
 
+// ClientDocWithDriverInfo creates a client information document for use in an isMaster command
+// that includes the fields of info.
+func ClientDocWithDriverInfo(app string, info DriverInfo) bsonx.Doc {
+	return clientDoc(app, info, runtime.GOOS, runtime.GOARCH, runtime.Version())
+}
+
+// clientDoc creates a client metadata document. If the document is larger than maxClientDocSize,
+// the fields of os other than type are omitted and then platform is truncated until it fits.
+func clientDoc(app string, info DriverInfo, goos, goarch, platform string) bsonx.Doc {
+	var doc bsonx.Doc
 	if app != "" {
 		doc = append(doc, bsonx.Elem{"application", bsonx.Document(bsonx.Doc{{"name", bsonx.String(app)}})})
 	}
-
+	platform = appendDriverInfo(platform, info.Platform)
+	doc = append(doc,
+		bsonx.Elem{"driver", bsonx.Document(bsonx.Doc{
+			{"name", bsonx.String(appendDriverInfo("mongo-go-driver", info.Name))},
+			{"version", bsonx.String(appendDriverInfo(version.Driver, info.Version))},
+		})},
+		bsonx.Elem{"os", bsonx.Document(bsonx.Doc{
+			{"type", bsonx.String(goos)},
+			{"name", bsonx.String(goos)},
+			{"architecture", bsonx.String(goarch)},
+		})},
+		bsonx.Elem{"platform", bsonx.String(platform)},
+	)
+
+	excess := clientDocSize(doc) - maxClientDocSize
+	if excess <= 0 {
+		return doc
+	}
+	doc = doc.Set("os", bsonx.Document(bsonx.Doc{{"type", bsonx.String(goos)}}))
+
+	excess = clientDocSize(doc) - maxClientDocSize
+	switch {
+	case excess <= 0:
+	case excess < len(platform):
+		doc = doc.Set("platform", bsonx.String(platform[:len(platform)-excess]))
+	default:
+		doc = doc.Delete("platform")
+	}
 	return doc
 }
+
+// appendDriverInfo appends the field of a DriverInfo to the value of the driver, if it is set.
+func appendDriverInfo(driver, info string) string {
+	if info == "" {
+		return driver
+	}
+	return driver + "|" + info
+}
+
+func clientDocSize(doc bsonx.Doc) int {
+	b, _ := doc.MarshalBSON()
+	return len(b)
+}
@@ -0,0 +1,142 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package command
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/version"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/result"
+)
+
+func TestHandshake(t *testing.T) {
+	t.Run("Result", func(t *testing.T) {
+		serviceID := primitive.NewObjectID()
+		testCases := []struct {
+			name         string
+			loadBalanced bool
+			ismstr       result.IsMaster
+			kind         description.ServerKind
+			err          error
+		}{
+			{"mongos", false, result.IsMaster{OK: 1, Msg: "isdbgrid"}, description.Mongos, nil},
+			{"load balancer", true, result.IsMaster{OK: 1, Msg: "isdbgrid", ServiceID: serviceID}, description.LoadBalancer, nil},
+			{"load balancer without serviceId", true, result.IsMaster{OK: 1, Msg: "isdbgrid"}, description.Unknown, ErrLoadBalancedNotSupported},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				h := &Handshake{LoadBalanced: tc.loadBalanced, ismstr: tc.ismstr}
+				desc, err := h.Result(address.Address("localhost:27017"))
+				if err != tc.err {
+					t.Fatalf("errors do not match. got %v; want %v", err, tc.err)
+				}
+				if desc.Kind != tc.kind {
+					t.Errorf("kinds do not match. got %v; want %v", desc.Kind, tc.kind)
+				}
+				if tc.err == nil && desc.ServiceID != tc.ismstr.ServiceID {
+					t.Errorf("service IDs do not match. got %v; want %v", desc.ServiceID, tc.ismstr.ServiceID)
+				}
+			})
+		}
+	})
+}
+
+func TestClientDoc(t *testing.T) {
+	driver := bsonx.Document(bsonx.Doc{
+		{"name", bsonx.String("mongo-go-driver")},
+		{"version", bsonx.String(version.Driver)},
+	})
+	fullOS := bsonx.Document(bsonx.Doc{
+		{"type", bsonx.String(runtime.GOOS)},
+		{"name", bsonx.String(runtime.GOOS)},
+		{"architecture", bsonx.String(runtime.GOARCH)},
+	})
+	typeOnlyOS := bsonx.Document(bsonx.Doc{{"type", bsonx.String(runtime.GOOS)}})
+
+	t.Run("full", func(t *testing.T) {
+		want := bsonx.Doc{
+			{"application", bsonx.Document(bsonx.Doc{{"name", bsonx.String("app")}})},
+			{"driver", driver},
+			{"os", fullOS},
+			{"platform", bsonx.String(runtime.Version())},
+		}
+		if got := ClientDoc("app"); !got.Equal(want) {
+			t.Errorf("documents do not match. got %v; want %v", got, want)
+		}
+	})
+	t.Run("without application", func(t *testing.T) {
+		if _, err := ClientDoc("").LookupErr("application"); err == nil {
+			t.Error("expected no application field")
+		}
+	})
+
+	t.Run("driver info", func(t *testing.T) {
+		doc := ClientDocWithDriverInfo("app", DriverInfo{Name: "myframework", Version: "1.2.3", Platform: "linux-amd64"})
+		want := bsonx.Document(bsonx.Doc{
+			{"name", bsonx.String("mongo-go-driver|myframework")},
+			{"version", bsonx.String(version.Driver + "|1.2.3")},
+		})
+		if got := doc.Lookup("driver"); !got.Equal(want) {
+			t.Errorf("driver fields do not match. got %v; want %v", got, want)
+		}
+		if got := doc.Lookup("platform").StringValue(); got != runtime.Version()+"|linux-amd64" {
+			t.Errorf("platforms do not match. got %q; want %q", got, runtime.Version()+"|linux-amd64")
+		}
+
+		doc = ClientDocWithDriverInfo("app", DriverInfo{Name: "myframework"})
+		if got := doc.Lookup("driver", "version").StringValue(); got != version.Driver {
+			t.Errorf("versions do not match. got %q; want %q", got, version.Driver)
+		}
+	})
+
+	app := strings.Repeat("a", MaxAppNameLength)
+	testCases := []struct {
+		name     string
+		app      string
+		platform string
+		os       bsonx.Val
+	}{
+		{"fits", app, "go1.12", fullOS},
+		{"os omitted", app, strings.Repeat("p", 220), typeOnlyOS},
+		{"platform truncated", app, strings.Repeat("p", 300), typeOnlyOS},
+		{"platform omitted", strings.Repeat("a", 380), "go1.12", typeOnlyOS},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := clientDoc(tc.app, DriverInfo{}, runtime.GOOS, runtime.GOARCH, tc.platform)
+			if size := clientDocSize(doc); size > maxClientDocSize {
+				t.Fatalf("document is %d bytes; want at most %d", size, maxClientDocSize)
+			}
+			if os := doc.Lookup("os"); !os.Equal(tc.os) {
+				t.Errorf("os fields do not match. got %v; want %v", os, tc.os)
+			}
+			platform, err := doc.LookupErr("platform")
+			switch tc.name {
+			case "platform omitted":
+				if err == nil {
+					t.Errorf("expected platform to be omitted")
+				}
+			case "platform truncated":
+				if err != nil || !strings.HasPrefix(tc.platform, platform.StringValue()) ||
+					len(platform.StringValue()) == len(tc.platform) || clientDocSize(doc) != maxClientDocSize {
+					t.Errorf("expected platform to be truncated to fit exactly, got %v", platform)
+				}
+			default:
+				if err != nil || platform.StringValue() != tc.platform {
+					t.Errorf("expected platform %q, got %v", tc.platform, platform)
+				}
+			}
+		})
+	}
+}
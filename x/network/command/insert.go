@@ -18,10 +18,6 @@ import (
 	"go.mongodb.org/mongo-driver/x/network/wiremessage"
 )
 
-// this is the amount of reserved buffer space in a message that the
-// driver reserves for command overhead.
-const reservedCommandBufferBytes = 16 * 10 * 10 * 10
-
 // Insert represents the insert command.
 //
 // The insert command inserts a set of documents into the database.
@@ -33,6 +29,7 @@ type Insert struct {
 	Clock           *session.ClusterClock
 	NS              Namespace
 	Docs            []bsonx.Doc
+	RawDocs         []bson.Raw // Inserted instead of Docs, if set, without being decoded.
 	Opts            []bsonx.Elem
 	WriteConcern    *writeconcern.WriteConcern
 	Session         *session.Client
@@ -58,13 +55,18 @@ func (i *Insert) encodeBatch(docs []bsonx.Doc, desc description.SelectedServer)
 		return nil, err
 	}
 
-	for _, opt := range i.Opts {
-		if opt.Key == "ordered" && !opt.Value.Boolean() {
-			i.ContinueOnError = true
-			break
-		}
-	}
+	return i.writeBatch(command, len(docs)), nil
+}
+
+func (i *Insert) encodeRawBatch(docs []bson.Raw) *WriteBatch {
+	command := append(bsonx.Doc{{"insert", bsonx.String(i.NS.Collection)}}, i.Opts...)
+	batch := i.writeBatch(command, len(docs))
+	batch.DocumentsKey = "documents"
+	batch.Documents = docs
+	return batch
+}
 
+func (i *Insert) writeBatch(command bsonx.Doc, numDocs int) *WriteBatch {
 	return &WriteBatch{
 		&Write{
 			Clock:        i.Clock,
@@ -73,11 +75,29 @@ func (i *Insert) encodeBatch(docs []bsonx.Doc, desc description.SelectedServer)
 			WriteConcern: i.WriteConcern,
 			Session:      i.Session,
 		},
-		len(docs),
-	}, nil
+		numDocs,
+	}
 }
 
 func (i *Insert) encode(desc description.SelectedServer) error {
+	for _, opt := range i.Opts {
+		if opt.Key == "ordered" && !opt.Value.Boolean() {
+			i.ContinueOnError = true
+			break
+		}
+	}
+
+	if i.RawDocs != nil {
+		batches, err := splitRawBatches(i.RawDocs, int(desc.MaxBatchCount), int(desc.MaxDocumentSize))
+		if err != nil {
+			return err
+		}
+		for _, docs := range batches {
+			i.batches = append(i.batches, i.encodeRawBatch(docs))
+		}
+		return nil
+	}
+
 	batches, err := splitBatches(i.Docs, int(desc.MaxBatchCount), int(desc.MaxDocumentSize))
 	if err != nil {
 		return err
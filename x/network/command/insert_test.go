@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/x/bsonx"
 	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
 )
 
 func TestInsertCommandSplitting(t *testing.T) {
@@ -84,6 +85,70 @@ func TestInsertCommandSplitting(t *testing.T) {
 		}
 
 	})
+	t.Run("documents_at_max_size", func(t *testing.T) {
+		i := &Insert{}
+		for n := 0; n < 3; n++ {
+			i.Docs = append(i.Docs, bsonx.Doc{{"a", bsonx.Int32(int32(n))}})
+		}
+
+		// each document is 12 bytes, so a document fits alone but two do not
+		batches, err := splitBatches(i.Docs, 100, 12)
+		assert.NoError(t, err)
+		assert.Len(t, batches, 3)
+		for _, b := range batches {
+			assert.Len(t, b, 1)
+		}
+	})
+	t.Run("array_element_overhead", func(t *testing.T) {
+		i := &Insert{}
+		for n := 0; n < 20; n++ {
+			i.Docs = append(i.Docs, bsonx.Doc{{"a", bsonx.Int32(int32(n))}})
+		}
+
+		// 12 byte documents take 15 bytes each in an array, up to index 9
+		batches, err := splitBatches(i.Docs, 100, 10*15)
+		assert.NoError(t, err)
+		assert.Len(t, batches, 2)
+		assert.Len(t, batches[0], 10)
+		assert.Len(t, batches[1], 10)
+	})
+	t.Run("raw_documents", func(t *testing.T) {
+		i := &Insert{NS: Namespace{DB: "db", Collection: "coll"}}
+		for n := 0; n < 20; n++ {
+			raw, err := bsonx.Doc{{"a", bsonx.Int32(int32(n))}}.MarshalBSON()
+			assert.NoError(t, err)
+			i.RawDocs = append(i.RawDocs, raw)
+		}
+
+		batches, err := splitRawBatches(i.RawDocs, 100, 10*15)
+		assert.NoError(t, err)
+		assert.Len(t, batches, 2)
+		assert.Len(t, batches[0], 10)
+		assert.Len(t, batches[1], 10)
+
+		desc := description.SelectedServer{
+			Server: description.Server{
+				WireVersion: &description.VersionRange{Min: 0, Max: wiremessage.OpmsgWireVersion},
+			},
+		}
+		wm, err := i.encodeRawBatch(batches[0]).Encode(desc)
+		assert.NoError(t, err)
+		msg := wm.(wiremessage.Msg)
+		assert.Len(t, msg.Sections, 2)
+		seq := msg.Sections[1].(wiremessage.SectionDocumentSequence)
+		assert.Equal(t, "documents", seq.Identifier)
+		assert.Equal(t, batches[0], seq.Documents)
+
+		desc.WireVersion.Max = wiremessage.OpmsgWireVersion - 1
+		wm, err = i.encodeRawBatch(batches[1]).Encode(desc)
+		assert.NoError(t, err)
+		docs, err := wm.(wiremessage.Query).Query.LookupErr("documents")
+		assert.NoError(t, err)
+		vals, err := docs.Array().Values()
+		assert.NoError(t, err)
+		assert.Len(t, vals, 10)
+		assert.Equal(t, []byte(batches[1][0]), []byte(vals[0].Document()))
+	})
 	t.Run("document_larger_than_max_size", func(t *testing.T) {
 		i := &Insert{}
 		i.Docs = append(i.Docs, bsonx.Doc{{"a", bsonx.String("bcdefghijklmnopqrstuvwxyz")}})
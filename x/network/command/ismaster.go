@@ -9,6 +9,7 @@ package command
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/x/bsonx"
@@ -23,13 +24,22 @@ import (
 // for monitoring a MongoDB server.
 //
 // Since IsMaster can only be run on a connection, there is no Dispatch method.
+//
+// If TopologyVersion is set, the command is awaitable: the server replies when its topology version
+// differs from TopologyVersion or after MaxAwaitTime. An awaitable command is sent with the
+// exhaustAllowed flag, so the server keeps streaming replies to it, which are read with Stream,
+// for as long as MoreToCome returns true.
 type IsMaster struct {
 	Client             bsonx.Doc
 	Compressors        []string
 	SaslSupportedMechs string
+	LoadBalanced       bool
+	TopologyVersion    *result.TopologyVersion
+	MaxAwaitTime       time.Duration
 
-	err error
-	res result.IsMaster
+	err        error
+	res        result.IsMaster
+	moreToCome bool
 }
 
 // Encode will encode this command into a wire message for the given server description.
@@ -49,6 +59,13 @@ func (im *IsMaster) Encode() (wiremessage.WireMessage, error) {
 	}
 
 	cmd = append(cmd, bsonx.Elem{"compression", bsonx.Array(array)})
+	if im.LoadBalanced {
+		cmd = append(cmd, bsonx.Elem{"loadBalanced", bsonx.Boolean(true)})
+	}
+
+	if im.TopologyVersion != nil {
+		return im.encodeAwaitable(cmd)
+	}
 
 	rdr, err := cmd.MarshalBSON()
 	if err != nil {
@@ -64,15 +81,43 @@ func (im *IsMaster) Encode() (wiremessage.WireMessage, error) {
 	return query, nil
 }
 
+// encodeAwaitable encodes an awaitable isMaster command. Awaitable commands are only supported by
+// servers that support OP_MSG.
+func (im *IsMaster) encodeAwaitable(cmd bsonx.Doc) (wiremessage.WireMessage, error) {
+	cmd = append(cmd,
+		bsonx.Elem{"topologyVersion", bsonx.Document(bsonx.Doc{
+			{"processId", bsonx.ObjectID(im.TopologyVersion.ProcessID)},
+			{"counter", bsonx.Int64(im.TopologyVersion.Counter)},
+		})},
+		bsonx.Elem{"maxAwaitTimeMS", bsonx.Int64(int64(im.MaxAwaitTime / time.Millisecond))},
+	)
+	rdr, err := opmsgAddGlobals(cmd, "admin", nil)
+	if err != nil {
+		return nil, err
+	}
+	return wiremessage.Msg{
+		MsgHeader: wiremessage.Header{RequestID: wiremessage.NextRequestID()},
+		FlagBits:  wiremessage.ExhaustAllowed,
+		Sections:  []wiremessage.Section{wiremessage.SectionBody{Document: rdr}},
+	}, nil
+}
+
 // Decode will decode the wire message using the provided server description. Errors during decoding
 // are deferred until either the Result or Err methods are called.
 func (im *IsMaster) Decode(wm wiremessage.WireMessage) *IsMaster {
-	reply, ok := wm.(wiremessage.Reply)
-	if !ok {
-		im.err = fmt.Errorf("unsupported response wiremessage type %T", wm)
-		return im
+	im.res, im.err, im.moreToCome = result.IsMaster{}, nil, false
+
+	var rdr bson.Raw
+	var err error
+	switch converted := wm.(type) {
+	case wiremessage.Reply:
+		rdr, err = decodeCommandOpReply(converted)
+	case wiremessage.Msg:
+		im.moreToCome = converted.FlagBits&wiremessage.MoreToCome != 0
+		rdr, err = decodeCommandOpMsg(converted)
+	default:
+		err = fmt.Errorf("unsupported response wiremessage type %T", wm)
 	}
-	rdr, err := decodeCommandOpReply(reply)
 	if err != nil {
 		im.err = err
 		return im
@@ -102,6 +147,10 @@ func (im *IsMaster) Result() (result.IsMaster, error) {
 // Err returns the error set on this command.
 func (im *IsMaster) Err() error { return im.err }
 
+// MoreToCome returns true if the server will send another reply to this command without a new
+// request, which must be read with Stream.
+func (im *IsMaster) MoreToCome() bool { return im.moreToCome }
+
 // RoundTrip handles the execution of this command using the provided wiremessage.ReadWriter.
 func (im *IsMaster) RoundTrip(ctx context.Context, rw wiremessage.ReadWriter) (result.IsMaster, error) {
 	wm, err := im.Encode()
@@ -119,3 +168,13 @@ func (im *IsMaster) RoundTrip(ctx context.Context, rw wiremessage.ReadWriter) (r
 	}
 	return im.Decode(wm).Result()
 }
+
+// Stream reads the next reply the server streams for an awaitable command after a reply for which
+// MoreToCome returned true.
+func (im *IsMaster) Stream(ctx context.Context, r wiremessage.Reader) (result.IsMaster, error) {
+	wm, err := r.ReadWireMessage(ctx)
+	if err != nil {
+		return result.IsMaster{}, err
+	}
+	return im.Decode(wm).Result()
+}
@@ -14,20 +14,23 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/x/bsonx"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
 	"go.mongodb.org/mongo-driver/x/network/description"
 	"go.mongodb.org/mongo-driver/x/network/wiremessage"
 )
 
-// Read represents a generic database read command.
+// Read represents a generic database read command. A WriteConcern is only added for commands, such as
+// those run through RunCommand, that are sent as reads but may write.
 type Read struct {
-	DB          string
-	Command     bsonx.Doc
-	ReadPref    *readpref.ReadPref
-	ReadConcern *readconcern.ReadConcern
-	Clock       *session.ClusterClock
-	Session     *session.Client
+	DB           string
+	Command      bsonx.Doc
+	ReadPref     *readpref.ReadPref
+	ReadConcern  *readconcern.ReadConcern
+	WriteConcern *writeconcern.WriteConcern
+	Clock        *session.ClusterClock
+	Session      *session.Client
 
 	result bson.Raw
 	err    error
@@ -46,7 +49,7 @@ func (r *Read) createReadPref(serverKind description.ServerKind, topologyKind de
 
 	switch rp.Mode() {
 	case readpref.PrimaryMode:
-		if serverKind == description.Mongos {
+		if serverKind == description.Mongos || serverKind == description.LoadBalancer {
 			return nil
 		}
 		if topologyKind == description.Single {
@@ -89,6 +92,19 @@ func (r *Read) createReadPref(serverKind description.ServerKind, topologyKind de
 	return doc
 }
 
+// appendHedge appends the hedge options of rp to doc, a $readPreference document, if they are set
+// and server is a mongos that supports hedged reads, which requires MongoDB 4.4.
+func appendHedge(doc bsonx.Doc, rp *readpref.ReadPref, server description.Server) bsonx.Doc {
+	if doc == nil || rp == nil || server.Kind != description.Mongos ||
+		server.WireVersion == nil || server.WireVersion.Max < 9 {
+		return doc
+	}
+	if enabled, ok := rp.HedgeEnabled(); ok {
+		doc = append(doc, bsonx.Elem{"hedge", bsonx.Document(bsonx.Doc{{"enabled", bsonx.Boolean(enabled)}})})
+	}
+	return doc
+}
+
 // addReadPref will add a read preference to the query document.
 //
 // NOTE: This method must always return either a valid bson.Reader or an error.
@@ -117,6 +133,7 @@ func (r *Read) encodeOpMsg(desc description.SelectedServer, cmd bsonx.Doc) (wire
 	}
 
 	readPrefDoc := r.createReadPref(desc.Server.Kind, desc.Kind, false)
+	readPrefDoc = appendHedge(readPrefDoc, r.ReadPref, desc.Server)
 	fullDocRdr, err := opmsgAddGlobals(cmd, r.DB, readPrefDoc)
 	if err != nil {
 		return nil, err
@@ -196,13 +213,28 @@ func (r *Read) decodeOpReply(wm wiremessage.WireMessage) {
 
 // Encode will encode this command into a wire message for the given server description.
 func (r *Read) Encode(desc description.SelectedServer) (wiremessage.WireMessage, error) {
+	if r.ReadConcern != nil && r.ReadConcern.GetLevel() == "linearizable" &&
+		r.ReadPref != nil && r.ReadPref.Mode() != readpref.PrimaryMode {
+		return nil, ErrLinearizableReadPref
+	}
+
 	cmd := r.Command.Copy()
 	cmd, err := addReadConcern(cmd, desc, r.ReadConcern, r.Session)
 	if err != nil {
 		return nil, err
 	}
 
-	cmd, err = addSessionFields(cmd, desc, r.Session)
+	cmd, err = addSnapshotReadConcern(cmd, desc, r.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd, err = addWriteConcern(cmd, r.WriteConcern)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd, err = addSessionFields(cmd, r.DB, desc, r.Session)
 	if err != nil {
 		return nil, err
 	}
@@ -241,6 +273,9 @@ func (r *Read) Decode(desc description.SelectedServer, wm wiremessage.WireMessag
 	_ = updateClusterTimes(r.Session, r.Clock, r.result)
 	_ = updateOperationTime(r.Session, r.result)
 	r.Session.UpdateRecoveryToken(r.result)
+	if r.err == nil {
+		r.Session.UpdateSnapshotTime(r.result)
+	}
 	return r
 }
 
@@ -267,11 +302,13 @@ func (r *Read) RoundTrip(ctx context.Context, desc description.SelectedServer, r
 
 	err = rw.WriteWireMessage(ctx, wm)
 	if err != nil {
-		if _, ok := err.(Error); ok {
+		switch err.(type) {
+		case Error, ReadOnlyError, NamespaceDeniedError, OperationLimitError:
 			return nil, err
 		}
 		// Connection errors are transient
 		r.Session.ClearPinnedServer()
+		r.Session.MarkDirty()
 		return nil, Error{Message: err.Error(), Labels: []string{TransientTransactionError, NetworkError}}
 	}
 	wm, err = rw.ReadWireMessage(ctx)
@@ -281,6 +318,7 @@ func (r *Read) RoundTrip(ctx context.Context, desc description.SelectedServer, r
 		}
 		// Connection errors are transient
 		r.Session.ClearPinnedServer()
+		r.Session.MarkDirty()
 		return nil, Error{Message: err.Error(), Labels: []string{TransientTransactionError, NetworkError}}
 	}
 
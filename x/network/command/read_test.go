@@ -0,0 +1,169 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package command
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
+	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+func TestRead(t *testing.T) {
+	t.Run("Encode", func(t *testing.T) {
+		t.Run("should encode concerns in the command body", func(t *testing.T) {
+			r := Read{
+				DB:           "foobar",
+				Command:      bsonx.Doc{{"fakeCommand", bsonx.Int32(1)}},
+				ReadConcern:  readconcern.Majority(),
+				WriteConcern: writeconcern.New(writeconcern.WMajority()),
+			}
+			wm, err := r.Encode(description.SelectedServer{
+				Server: description.Server{
+					WireVersion: &description.VersionRange{Min: 0, Max: wiremessage.OpmsgWireVersion},
+				},
+			})
+			noerr(t, err)
+			msg, ok := wm.(wiremessage.Msg)
+			if !ok {
+				t.Fatalf("Expected an OP_MSG wire message, but got something else. got %v", wm)
+			}
+			got := bsoncore.Document(msg.Sections[0].(wiremessage.SectionBody).Document)
+			if level := got.Lookup("readConcern", "level").StringValue(); level != "majority" {
+				t.Errorf("Expected readConcern level majority, got %v", got)
+			}
+			if w := got.Lookup("writeConcern", "w").StringValue(); w != "majority" {
+				t.Errorf("Expected writeConcern w majority, got %v", got)
+			}
+		})
+		t.Run("should encode hedge options for mongos 4.4 and later", func(t *testing.T) {
+			testCases := []struct {
+				name        string
+				rp          *readpref.ReadPref
+				kind        description.ServerKind
+				wireVersion int32
+				hedge       bsonx.Val
+			}{
+				{"enabled", readpref.Nearest(readpref.WithHedgeEnabled(true)), description.Mongos, 9,
+					bsonx.Document(bsonx.Doc{{"enabled", bsonx.Boolean(true)}})},
+				{"disabled", readpref.SecondaryPreferred(readpref.WithHedgeEnabled(false)), description.Mongos, 9,
+					bsonx.Document(bsonx.Doc{{"enabled", bsonx.Boolean(false)}})},
+				{"not set", readpref.Nearest(), description.Mongos, 9, bsonx.Val{}},
+				{"mongos 4.2", readpref.Nearest(readpref.WithHedgeEnabled(true)), description.Mongos, 8, bsonx.Val{}},
+				{"replica set", readpref.Nearest(readpref.WithHedgeEnabled(true)), description.RSSecondary, 9, bsonx.Val{}},
+			}
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					r := Read{DB: "foobar", Command: bsonx.Doc{{"find", bsonx.String("coll")}}, ReadPref: tc.rp}
+					wm, err := r.Encode(description.SelectedServer{
+						Server: description.Server{
+							Kind:        tc.kind,
+							WireVersion: &description.VersionRange{Min: 0, Max: tc.wireVersion},
+						},
+						Kind: description.Sharded,
+					})
+					noerr(t, err)
+					got := bsonx.Doc{}
+					noerr(t, got.UnmarshalBSON(wm.(wiremessage.Msg).Sections[0].(wiremessage.SectionBody).Document))
+					hedge, _ := got.LookupErr("$readPreference", "hedge")
+					if !hedge.Equal(tc.hedge) {
+						t.Errorf("hedge options do not match. got %v; want %v", hedge, tc.hedge)
+					}
+				})
+			}
+		})
+		t.Run("should reject linearizable reads with a non-primary read preference", func(t *testing.T) {
+			desc := description.SelectedServer{
+				Server: description.Server{WireVersion: &description.VersionRange{Max: wiremessage.OpmsgWireVersion}},
+			}
+			testCases := []struct {
+				name string
+				rc   *readconcern.ReadConcern
+				rp   *readpref.ReadPref
+				err  error
+			}{
+				{"primary", readconcern.Linearizable(), readpref.Primary(), nil},
+				{"no read preference", readconcern.Linearizable(), nil, nil},
+				{"secondary", readconcern.Linearizable(), readpref.Secondary(), ErrLinearizableReadPref},
+				{"primaryPreferred", readconcern.Linearizable(), readpref.PrimaryPreferred(), ErrLinearizableReadPref},
+				{"majority on a secondary", readconcern.Majority(), readpref.Secondary(), nil},
+			}
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					r := Read{
+						DB:          "foobar",
+						Command:     bsonx.Doc{{"find", bsonx.String("coll")}},
+						ReadConcern: tc.rc,
+						ReadPref:    tc.rp,
+					}
+					_, err := r.Encode(desc)
+					if err != tc.err {
+						t.Errorf("errors do not match. got %v; want %v", err, tc.err)
+					}
+				})
+			}
+		})
+		t.Run("should encode a snapshot read concern in snapshot sessions", func(t *testing.T) {
+			snapshot := true
+			id, _ := uuid.New()
+			sess, err := session.NewClientSession(&session.Pool{}, id, session.Explicit, &session.ClientOptions{Snapshot: &snapshot})
+			noerr(t, err)
+			desc := description.SelectedServer{
+				Server: description.Server{WireVersion: &description.VersionRange{Min: 0, Max: 13}},
+			}
+			encode := func(cmd bsonx.Doc, desc description.SelectedServer) (bsonx.Doc, error) {
+				r := Read{DB: "foobar", Command: cmd, ReadConcern: readconcern.Majority(), Session: sess}
+				wm, err := r.Encode(desc)
+				if err != nil {
+					return nil, err
+				}
+				got := bsonx.Doc{}
+				noerr(t, got.UnmarshalBSON(wm.(wiremessage.Msg).Sections[0].(wiremessage.SectionBody).Document))
+				return got, nil
+			}
+
+			got, err := encode(bsonx.Doc{{"find", bsonx.String("coll")}}, desc)
+			noerr(t, err)
+			rc, _ := got.LookupErr("readConcern")
+			want := bsonx.Document(bsonx.Doc{{"level", bsonx.String("snapshot")}})
+			if !rc.Equal(want) {
+				t.Errorf("read concerns do not match. got %v; want %v", rc, want)
+			}
+
+			sess.UpdateSnapshotTime(bsoncore.BuildDocument(nil, bsoncore.AppendDocumentElement(nil, "cursor",
+				bsoncore.BuildDocument(nil, bsoncore.AppendTimestampElement(nil, "atClusterTime", 10, 2)))))
+			for _, name := range []string{"find", "aggregate", "distinct"} {
+				got, err = encode(bsonx.Doc{{name, bsonx.String("coll")}}, desc)
+				noerr(t, err)
+				rc, _ = got.LookupErr("readConcern")
+				want = bsonx.Document(bsonx.Doc{{"level", bsonx.String("snapshot")}, {"atClusterTime", bsonx.Timestamp(10, 2)}})
+				if !rc.Equal(want) {
+					t.Errorf("read concerns for %s do not match. got %v; want %v", name, rc, want)
+				}
+			}
+
+			got, err = encode(bsonx.Doc{{"count", bsonx.String("coll")}}, desc)
+			noerr(t, err)
+			if level := got.Lookup("readConcern", "level").StringValue(); level != "majority" {
+				t.Errorf("expected readConcern level majority for count, got %v", got)
+			}
+
+			desc.WireVersion.Max = 12
+			_, err = encode(bsonx.Doc{{"find", bsonx.String("coll")}}, desc)
+			if err != ErrSnapshotReadsUnsupported {
+				t.Errorf("errors do not match. got %v; want %v", err, ErrSnapshotReadsUnsupported)
+			}
+		})
+	})
+}
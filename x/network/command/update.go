@@ -73,7 +73,7 @@ func (u *Update) encodeBatch(docs []bsonx.Doc, desc description.SelectedServer)
 	var options []bsonx.Elem
 	for _, opt := range u.Opts {
 		switch opt.Key {
-		case "upsert", "collation", "arrayFilters":
+		case "upsert", "collation", "arrayFilters", "hint":
 			// options that are encoded on each individual document
 			for idx := range copyDocs {
 				copyDocs[idx] = append(copyDocs[idx], opt)
@@ -0,0 +1,38 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package command
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/network/description"
+)
+
+func TestUpdate(t *testing.T) {
+	t.Run("encodes per-statement options on each update", func(t *testing.T) {
+		pipeline := bsonx.Array(bsonx.Arr{bsonx.Document(bsonx.Doc{{"$set", bsonx.Document(bsonx.Doc{{"x", bsonx.Int32(1)}})}})})
+		cmd := Update{
+			NS:   Namespace{DB: "foo", Collection: "bar"},
+			Docs: []bsonx.Doc{{{"q", bsonx.Document(bsonx.Doc{})}, {"u", pipeline}}},
+			Opts: []bsonx.Elem{{"hint", bsonx.String("x_1")}, {"ordered", bsonx.Boolean(true)}},
+		}
+		batch, err := cmd.encodeBatch(cmd.Docs, description.SelectedServer{})
+		noerr(t, err)
+
+		statement := batch.Command.Lookup("updates").Array()[0].Document()
+		if hint, err := statement.LookupErr("hint"); err != nil || hint.StringValue() != "x_1" {
+			t.Errorf("expected the hint on the update statement, got %v", statement)
+		}
+		if u := statement.Lookup("u"); !u.Equal(pipeline) {
+			t.Errorf("expected the pipeline as the update. got %v; want %v", u, pipeline)
+		}
+		if _, err := batch.Command.LookupErr("hint"); err == nil {
+			t.Errorf("expected the hint to be omitted from the command, got %v", batch.Command)
+		}
+	})
+}
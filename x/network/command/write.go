@@ -29,6 +29,11 @@ type Write struct {
 	Clock        *session.ClusterClock
 	Session      *session.Client
 
+	// Documents are added to the command as the array named DocumentsKey. They are sent as an
+	// OP_MSG document sequence, so they are never decoded unless the server requires OP_QUERY.
+	DocumentsKey string
+	Documents    []bson.Raw
+
 	result bson.Raw
 	err    error
 }
@@ -63,6 +68,15 @@ func (w *Write) encodeOpMsg(desc description.SelectedServer, cmd bsonx.Doc) (wir
 			return nil, err
 		}
 
+		msg.Sections = append(msg.Sections, docSequence)
+	} else if w.DocumentsKey != "" {
+		docSequence := wiremessage.SectionDocumentSequence{
+			PayloadType: wiremessage.DocumentSequence,
+			Identifier:  w.DocumentsKey,
+			Documents:   w.Documents,
+		}
+		docSequence.Size = int32(docSequence.PayloadLen())
+
 		msg.Sections = append(msg.Sections, docSequence)
 	}
 
@@ -76,6 +90,18 @@ func (w *Write) encodeOpMsg(desc description.SelectedServer, cmd bsonx.Doc) (wir
 
 // Encode w as OP_QUERY
 func (w *Write) encodeOpQuery(desc description.SelectedServer, cmd bsonx.Doc) (wiremessage.WireMessage, error) {
+	if w.DocumentsKey != "" {
+		arr := make(bsonx.Arr, 0, len(w.Documents))
+		for _, doc := range w.Documents {
+			d, err := bsonx.ReadDoc(doc)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, bsonx.Document(d))
+		}
+		cmd = append(cmd, bsonx.Elem{w.DocumentsKey, bsonx.Array(arr)})
+	}
+
 	rdr, err := marshalCommand(cmd)
 	if err != nil {
 		return nil, err
@@ -145,7 +171,7 @@ func (w *Write) Encode(desc description.SelectedServer) (wiremessage.WireMessage
 		}
 	} else {
 		// only encode session ID for acknowledged writes
-		cmd, err = addSessionFields(cmd, desc, w.Session)
+		cmd, err = addSessionFields(cmd, w.DB, desc, w.Session)
 		if err != nil {
 			return nil, err
 		}
@@ -217,11 +243,13 @@ func (w *Write) RoundTrip(ctx context.Context, desc description.SelectedServer,
 
 	err = rw.WriteWireMessage(ctx, wm)
 	if err != nil {
-		if _, ok := err.(Error); ok {
+		switch err.(type) {
+		case Error, ReadOnlyError, NamespaceDeniedError, OperationLimitError:
 			return nil, err
 		}
 		// Connection errors are transient
 		w.Session.ClearPinnedServer()
+		w.Session.MarkDirty()
 		return nil, Error{Message: err.Error(), Labels: []string{TransientTransactionError, NetworkError}}
 	}
 
@@ -239,6 +267,7 @@ func (w *Write) RoundTrip(ctx context.Context, desc description.SelectedServer,
 		}
 		// Connection errors are transient
 		w.Session.ClearPinnedServer()
+		w.Session.MarkDirty()
 		return nil, Error{Message: err.Error(), Labels: []string{TransientTransactionError, NetworkError}}
 	}
 
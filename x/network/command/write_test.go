@@ -2,11 +2,15 @@ package command
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"testing"
 
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/x/bsonx"
 	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/session"
+	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/uuid"
 	"go.mongodb.org/mongo-driver/x/network/description"
 	"go.mongodb.org/mongo-driver/x/network/wiremessage"
 )
@@ -38,4 +42,34 @@ func TestWrite(t *testing.T) {
 			}
 		})
 	})
+	t.Run("RoundTrip marks the session dirty on network errors", func(t *testing.T) {
+		id, err := uuid.New()
+		noerr(t, err)
+		sess, err := session.NewClientSession(session.NewPool(nil), id, session.Implicit)
+		noerr(t, err)
+
+		w := Write{DB: "foobar", Command: bsonx.Doc{{"fakeCommand", bsonx.Int32(1)}}, Session: sess}
+		desc := description.SelectedServer{
+			Server: description.Server{
+				WireVersion: &description.VersionRange{Min: 0, Max: wiremessage.OpmsgWireVersion},
+			},
+		}
+		_, err = w.RoundTrip(context.Background(), desc, failingReadWriter{})
+		if cerr, ok := err.(Error); !ok || !cerr.HasErrorLabel(NetworkError) {
+			t.Fatalf("Expected a network error, got %v", err)
+		}
+		if !sess.Server.Dirty {
+			t.Errorf("Expected the server session to be marked dirty")
+		}
+	})
+}
+
+type failingReadWriter struct{}
+
+func (failingReadWriter) ReadWireMessage(context.Context) (wiremessage.WireMessage, error) {
+	return nil, errors.New("connection reset")
+}
+
+func (failingReadWriter) WriteWireMessage(context.Context, wiremessage.WireMessage) error {
+	return nil
 }
@@ -32,6 +32,7 @@ import (
 	"go.mongodb.org/mongo-driver/x/network/address"
 	"go.mongodb.org/mongo-driver/x/network/compressor"
 	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/ocsp"
 	"go.mongodb.org/mongo-driver/x/network/wiremessage"
 )
 
@@ -93,6 +94,7 @@ type connection struct {
 	conn        net.Conn
 	compressBuf []byte                // buffer to compress messages
 	compressor  compressor.Compressor // use for compressing messages
+	compressPol compressionPolicy
 	// server can compress response with any compressor supported by driver
 	compressorMap    map[wiremessage.CompressorID]compressor.Compressor
 	commandMap       map[int64]*commandMetadata // map for monitoring commands sent to server
@@ -101,7 +103,12 @@ type connection struct {
 	idleDeadline     time.Time
 	lifetimeDeadline time.Time
 	cmdMonitor       *event.CommandMonitor
+	crypt            Crypt
+	minRTT           func() time.Duration
+	traffic          *trafficCounter
 	readTimeout      time.Duration
+	sendMaxTime      bool
+	serverAPI        *ServerAPI
 	uncompressBuf    []byte // buffer to uncompress messages
 	writeTimeout     time.Duration
 	readBuf          []byte
@@ -118,14 +125,30 @@ func New(ctx context.Context, addr address.Address, opts ...Option) (Connection,
 		return nil, nil, err
 	}
 
-	nc, err := cfg.dialer.DialContext(ctx, addr.Network(), addr.String())
+	id := fmt.Sprintf("%s[-%d]", addr, nextClientConnectionID())
+	sr := newStageReporter(cfg.connMonitor, addr.String(), id)
+
+	dialAddr := addr
+	if mapped, ok := cfg.addressMap[addr.String()]; ok {
+		dialAddr = address.Address(mapped)
+	}
+	var nc net.Conn
+	if rd, ok := cfg.dialer.(*resolvingDialer); ok {
+		nc, err = rd.dial(ctx, dialAddr.Network(), dialAddr.String(), sr)
+	} else {
+		finish := sr.start(ctx, event.StageTCPConnect)
+		nc, err = cfg.dialer.DialContext(ctx, dialAddr.Network(), dialAddr.String())
+		finish(err)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
 
 	if cfg.tlsConfig != nil {
 		tlsConfig := cfg.tlsConfig.Clone()
-		nc, err = configureTLS(ctx, nc, addr, tlsConfig)
+		finish := sr.start(ctx, event.StageTLSHandshake)
+		nc, err = configureTLS(ctx, nc, addr, tlsConfig, cfg.ocspConfig)
+		finish(err)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -136,7 +159,6 @@ func New(ctx context.Context, addr address.Address, opts ...Option) (Connection,
 		lifetimeDeadline = time.Now().Add(cfg.lifeTimeout)
 	}
 
-	id := fmt.Sprintf("%s[-%d]", addr, nextClientConnectionID())
 	compressorMap := make(map[wiremessage.CompressorID]compressor.Compressor)
 
 	for _, comp := range cfg.compressors {
@@ -159,11 +181,13 @@ func New(ctx context.Context, addr address.Address, opts ...Option) (Connection,
 		conn:             nc,
 		compressBuf:      make([]byte, 256),
 		compressorMap:    compressorMap,
+		compressPol:      newCompressionPolicy(cfg),
 		commandMap:       make(map[int64]*commandMetadata),
 		addr:             addr,
 		idleTimeout:      cfg.idleTimeout,
 		lifetimeDeadline: lifetimeDeadline,
 		readTimeout:      cfg.readTimeout,
+		serverAPI:        cfg.serverAPI,
 		writeTimeout:     cfg.writeTimeout,
 		readBuf:          make([]byte, 256),
 		uncompressBuf:    make([]byte, 256),
@@ -175,36 +199,66 @@ func New(ctx context.Context, addr address.Address, opts ...Option) (Connection,
 
 	var desc *description.Server
 	if cfg.handshaker != nil {
-		d, err := cfg.handshaker.Handshake(ctx, c.addr, c)
+		d, err := cfg.handshaker.Handshake(context.WithValue(ctx, stageReporterKey{}, sr), c.addr, c)
 		if err != nil {
 			return nil, nil, err
 		}
 
 		if len(d.Compression) > 0 {
-		clientMethodLoop:
-			for _, comp := range c.compressorMap {
-				method := comp.Name()
-
-				for _, serverMethod := range d.Compression {
-					if method != serverMethod {
-						continue
-					}
-
-					c.compressor = comp // found matching compressor
-					break clientMethodLoop
-				}
-			}
-
+			c.compressor = negotiateCompressor(cfg, c.addr.String(), compressorMap, d.Compression)
 		}
 
 		desc = &d
 	}
 
 	c.cmdMonitor = cfg.cmdMonitor // attach the command monitor later to avoid monitoring auth
+	c.crypt = cfg.crypt           // and the crypt to avoid encrypting the handshake
+	c.sendMaxTime = true          // and only set maxTimeMS on commands sent after the handshake
+	c.minRTT = cfg.minRTT
+	c.traffic = cfg.traffic
 	return c, desc, nil
 }
 
-func configureTLS(ctx context.Context, nc net.Conn, addr address.Address, config *TLSConfig) (net.Conn, error) {
+// negotiateCompressor returns the compressor used to compress the messages sent on a connection,
+// or nil if they are not compressed. It is the first of the configured compressors the server
+// supports, unless a compressor selector chooses another.
+func negotiateCompressor(cfg *config, addr string, compressors map[wiremessage.CompressorID]compressor.Compressor,
+	serverCompressors []string) compressor.Compressor {
+
+	byName := make(map[string]compressor.Compressor, len(compressors))
+	for _, comp := range compressors {
+		byName[comp.Name()] = comp
+	}
+
+	var common []string
+	for _, name := range cfg.compressors {
+		if _, ok := byName[name]; !ok {
+			continue
+		}
+		for _, serverName := range serverCompressors {
+			if name == serverName {
+				common = append(common, name)
+				break
+			}
+		}
+	}
+	if len(common) == 0 {
+		return nil
+	}
+
+	if cfg.compressSelect == nil {
+		return byName[common[0]]
+	}
+	chosen := cfg.compressSelect(addr, common)
+	for _, name := range common {
+		if name == chosen {
+			return byName[name]
+		}
+	}
+	return nil
+}
+
+func configureTLS(ctx context.Context, nc net.Conn, addr address.Address, config *TLSConfig, ocspCfg *ocsp.Config) (net.Conn, error) {
 	if !config.InsecureSkipVerify {
 		hostname := addr.String()
 		colonPos := strings.LastIndex(hostname, ":")
@@ -213,6 +267,10 @@ func configureTLS(ctx context.Context, nc net.Conn, addr address.Address, config
 		}
 
 		hostname = hostname[:colonPos]
+		if addr.Network() == "unix" {
+			// The certificates of servers listening on unix domain sockets are verified as local ones.
+			hostname = "localhost"
+		}
 		config.ServerName = hostname
 	}
 
@@ -231,6 +289,13 @@ func configureTLS(ctx context.Context, nc net.Conn, addr address.Address, config
 	case <-ctx.Done():
 		return nil, errors.New("server connection cancelled/timeout during TLS handshake")
 	}
+
+	if ocspCfg != nil {
+		if err := ocsp.Verify(ctx, client.ConnectionState(), ocspCfg); err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+	}
 	return client, nil
 }
 
@@ -259,6 +324,45 @@ func canCompress(cmd string) bool {
 	return true
 }
 
+// compressionPolicy decides which of the commands that may be compressed are compressed.
+type compressionPolicy struct {
+	allow   map[string]struct{}
+	deny    map[string]struct{}
+	minSize int
+}
+
+func newCompressionPolicy(cfg *config) compressionPolicy {
+	cp := compressionPolicy{minSize: cfg.compressMin}
+	if len(cfg.compressAllow) > 0 {
+		cp.allow = make(map[string]struct{}, len(cfg.compressAllow))
+		for _, cmd := range cfg.compressAllow {
+			cp.allow[cmd] = struct{}{}
+		}
+	}
+	if len(cfg.compressDeny) > 0 {
+		cp.deny = make(map[string]struct{}, len(cfg.compressDeny))
+		for _, cmd := range cfg.compressDeny {
+			cp.deny[cmd] = struct{}{}
+		}
+	}
+	return cp
+}
+
+// allowed returns true if the command cmd may be compressed.
+func (cp compressionPolicy) allowed(cmd string) bool {
+	if !canCompress(cmd) {
+		return false
+	}
+	if _, ok := cp.deny[cmd]; ok {
+		return false
+	}
+	if cp.allow != nil {
+		_, ok := cp.allow[cmd]
+		return ok
+	}
+	return true
+}
+
 func (c *connection) compressMessage(wm wiremessage.WireMessage) (wiremessage.WireMessage, error) {
 	var requestID int32
 	var responseTo int32
@@ -272,7 +376,7 @@ func (c *connection) compressMessage(wm wiremessage.WireMessage) (wiremessage.Wi
 		}
 
 		key := firstElem.Key()
-		if !canCompress(key) {
+		if !c.compressPol.allowed(key) {
 			return wm, nil // return original message because this command can't be compressed
 		}
 		requestID = converted.MsgHeader.RequestID
@@ -285,7 +389,7 @@ func (c *connection) compressMessage(wm wiremessage.WireMessage) (wiremessage.Wi
 		}
 
 		key := firstElem.Key()
-		if !canCompress(key) {
+		if !c.compressPol.allowed(key) {
 			return wm, nil
 		}
 
@@ -303,6 +407,9 @@ func (c *connection) compressMessage(wm wiremessage.WireMessage) (wiremessage.Wi
 	}
 
 	c.wireMessageBuf = c.wireMessageBuf[16:] // strip header
+	if len(c.wireMessageBuf) < c.compressPol.minSize {
+		return wm, nil // compressing small messages costs more than it saves
+	}
 	c.compressBuf = c.compressBuf[:0]
 	compressedBytes, err := c.compressor.CompressBytes(c.wireMessageBuf, c.compressBuf)
 	if err != nil {
@@ -330,13 +437,15 @@ func (c *connection) uncompressMessage(compressed wiremessage.Compressed) ([]byt
 	// used to find the correct method for uncompressing data
 	uncompressor := c.compressorMap[compressed.CompressorID]
 
-	// reset uncompressBuf
-	c.uncompressBuf = c.uncompressBuf[:0]
-	if int(compressed.UncompressedSize) > cap(c.uncompressBuf) {
-		c.uncompressBuf = make([]byte, 0, compressed.UncompressedSize)
+	// The message is uncompressed after room for its header, so that the full message is built in
+	// uncompressBuf without copying it.
+	size := 16 + int(compressed.UncompressedSize)
+	if size > cap(c.uncompressBuf) {
+		c.uncompressBuf = make([]byte, size)
 	}
+	c.uncompressBuf = c.uncompressBuf[:size]
 
-	uncompressedMessage, err := uncompressor.UncompressBytes(compressed.CompressedMessage, c.uncompressBuf[:compressed.UncompressedSize])
+	uncompressedMessage, err := uncompressor.UncompressBytes(compressed.CompressedMessage, c.uncompressBuf[16:])
 
 	if err != nil {
 		return nil, 0, err
@@ -357,15 +466,18 @@ func (c *connection) uncompressMessage(compressed wiremessage.Compressed) ([]byt
 		return nil, 0, fmt.Errorf("opcode %s not implemented", compressed.OriginalOpCode)
 	}
 
-	var fullMessage []byte
-	fullMessage = origHeader.AppendHeader(fullMessage)
+	// The uncompressed message usually already follows the header, in which case it is not moved.
+	fullMessage := origHeader.AppendHeader(c.uncompressBuf[:0])
 	fullMessage = append(fullMessage, uncompressedMessage...)
 	return fullMessage, origHeader.OpCode, nil
 }
 
+// canMonitor returns false for security-sensitive commands whose command and reply documents must
+// be redacted from monitoring events. Command names are compared case-insensitively.
 func canMonitor(cmd string) bool {
-	if cmd == "authenticate" || cmd == "saslStart" || cmd == "saslContinue" || cmd == "getnonce" || cmd == "createUser" ||
-		cmd == "updateUser" || cmd == "copydbgetnonce" || cmd == "copydbsaslstart" || cmd == "copydb" {
+	switch strings.ToLower(cmd) {
+	case "authenticate", "saslstart", "saslcontinue", "getnonce", "createuser", "updateuser",
+		"copydbgetnonce", "copydbsaslstart", "copydb":
 		return false
 	}
 
@@ -378,7 +490,8 @@ func (c *connection) commandStartedEvent(ctx context.Context, wm wiremessage.Wir
 	}
 
 	startedEvent := &event.CommandStartedEvent{
-		ConnectionID: c.id,
+		ConnectionID:  c.id,
+		ServerAddress: c.addr.String(),
 	}
 
 	var cmd bsonx.Doc
@@ -456,6 +569,7 @@ func (c *connection) commandStartedEvent(ctx context.Context, wm wiremessage.Wir
 			CommandName:   startedEvent.CommandName,
 			RequestID:     startedEvent.RequestID,
 			ConnectionID:  c.id,
+			ServerAddress: c.addr.String(),
 		}
 
 		c.cmdMonitor.Succeeded(ctx, &event.CommandSucceededEvent{
@@ -526,7 +640,10 @@ func (c *connection) commandFinishedEvent(ctx context.Context, wm wiremessage.Wi
 	case wiremessage.Msg:
 		requestID = int64(converted.MsgHeader.ResponseTo)
 	}
-	cmdMetadata := c.commandMap[requestID]
+	cmdMetadata, ok := c.commandMap[requestID]
+	if !ok {
+		return nil
+	}
 	delete(c.commandMap, requestID)
 
 	switch converted := wm.(type) {
@@ -554,6 +671,7 @@ func (c *connection) commandFinishedEvent(ctx context.Context, wm wiremessage.Wi
 		CommandName:   cmdMetadata.Name,
 		RequestID:     requestID,
 		ConnectionID:  c.id,
+		ServerAddress: c.addr.String(),
 	}
 
 	if success {
@@ -597,11 +715,38 @@ func (c *connection) commandFinishedEvent(ctx context.Context, wm wiremessage.Wi
 	return nil
 }
 
+// commandFailedEvents publishes a CommandFailedEvent for each command that is still awaiting a reply
+// when reading from the connection fails.
+func (c *connection) commandFailedEvents(ctx context.Context, err error) {
+	if c.cmdMonitor == nil {
+		return
+	}
+
+	for requestID, cmdMetadata := range c.commandMap {
+		delete(c.commandMap, requestID)
+		if c.cmdMonitor.Failed == nil {
+			continue
+		}
+
+		c.cmdMonitor.Failed(ctx, &event.CommandFailedEvent{
+			Failure: err.Error(),
+			CommandFinishedEvent: event.CommandFinishedEvent{
+				DurationNanos: cmdMetadata.TimeDifference(),
+				CommandName:   cmdMetadata.Name,
+				RequestID:     requestID,
+				ConnectionID:  c.id,
+				ServerAddress: c.addr.String(),
+			},
+		})
+	}
+}
+
 func (c *connection) WriteWireMessage(ctx context.Context, wm wiremessage.WireMessage) error {
 	var err error
 	if c.dead {
 		return Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			message:      "connection is dead",
 		}
 	}
@@ -610,6 +755,7 @@ func (c *connection) WriteWireMessage(ctx context.Context, wm wiremessage.WireMe
 	case <-ctx.Done():
 		return Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			Wrapped:      ctx.Err(),
 			message:      "failed to write",
 		}
@@ -628,11 +774,52 @@ func (c *connection) WriteWireMessage(ctx context.Context, wm wiremessage.WireMe
 	if err := c.conn.SetWriteDeadline(deadline); err != nil {
 		return Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			Wrapped:      err,
 			message:      "failed to set write deadline",
 		}
 	}
 
+	if c.serverAPI != nil {
+		wm, err = addServerAPI(c.serverAPI, wm)
+		if err != nil {
+			return Error{
+				ConnectionID: c.id,
+				Address:      c.addr,
+				Wrapped:      err,
+				message:      "unable to add server API version to wire message",
+			}
+		}
+	}
+
+	if c.crypt != nil {
+		wm, err = encryptMessage(ctx, c.crypt, wm)
+		if err != nil {
+			return Error{
+				ConnectionID: c.id,
+				Address:      c.addr,
+				Wrapped:      err,
+				message:      "unable to encrypt wire message",
+			}
+		}
+	}
+
+	if c.sendMaxTime {
+		var minRTT time.Duration
+		if c.minRTT != nil {
+			minRTT = c.minRTT()
+		}
+		wm, err = addMaxTime(ctx, wm, minRTT)
+		if err != nil {
+			return Error{
+				ConnectionID: c.id,
+				Address:      c.addr,
+				Wrapped:      err,
+				message:      "operation timed out before the command was sent",
+			}
+		}
+	}
+
 	// Truncate the write buffer
 	c.writeBuf = c.writeBuf[:0]
 
@@ -643,6 +830,7 @@ func (c *connection) WriteWireMessage(ctx context.Context, wm wiremessage.WireMe
 		if err != nil {
 			return Error{
 				ConnectionID: c.id,
+				Address:      c.addr,
 				Wrapped:      err,
 				message:      "unable to compress wire message",
 			}
@@ -654,21 +842,30 @@ func (c *connection) WriteWireMessage(ctx context.Context, wm wiremessage.WireMe
 	if err != nil {
 		return Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			Wrapped:      err,
 			message:      "unable to encode wire message",
 		}
 	}
 
+	stop := c.watchContext(ctx)
 	_, err = c.conn.Write(c.writeBuf)
+	stop()
 	if err != nil {
+		// Part of the message may have been written, so the connection cannot be reused.
 		c.Close()
+		if ctxErr := contextError(ctx); ctxErr != nil {
+			err = ctxErr
+		}
 		return Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			Wrapped:      err,
 			message:      "unable to write wire message to network",
 		}
 	}
 
+	c.traffic.sent(len(c.writeBuf))
 	c.bumpIdleDeadline()
 	err = c.commandStartedEvent(ctx, wm)
 	if err != nil {
@@ -678,9 +875,32 @@ func (c *connection) WriteWireMessage(ctx context.Context, wm wiremessage.WireMe
 }
 
 func (c *connection) ReadWireMessage(ctx context.Context) (wiremessage.WireMessage, error) {
+	wm, err := c.readWireMessage(ctx)
+	if err != nil {
+		c.commandFailedEvents(ctx, err)
+		return nil, err
+	}
+
+	if c.crypt != nil {
+		wm, err = decryptMessage(ctx, c.crypt, wm)
+		if err != nil {
+			return nil, Error{
+				ConnectionID: c.id,
+				Address:      c.addr,
+				Wrapped:      err,
+				message:      "unable to decrypt wire message",
+			}
+		}
+	}
+
+	return wm, nil
+}
+
+func (c *connection) readWireMessage(ctx context.Context) (wiremessage.WireMessage, error) {
 	if c.dead {
 		return nil, Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			message:      "connection is dead",
 		}
 	}
@@ -692,6 +912,7 @@ func (c *connection) ReadWireMessage(ctx context.Context) (wiremessage.WireMessa
 		c.Close()
 		return nil, Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			Wrapped:      ctx.Err(),
 			message:      "failed to read",
 		}
@@ -710,17 +931,27 @@ func (c *connection) ReadWireMessage(ctx context.Context) (wiremessage.WireMessa
 	if err := c.conn.SetReadDeadline(deadline); err != nil {
 		return nil, Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			Wrapped:      ctx.Err(),
 			message:      "failed to set read deadline",
 		}
 	}
 
+	// The reply to the command is still sent by the server if the read is aborted, so the connection
+	// is closed after any read error.
+	stop := c.watchContext(ctx)
+	defer stop()
+
 	var sizeBuf [4]byte
 	_, err := io.ReadFull(c.conn, sizeBuf[:])
 	if err != nil {
 		c.Close()
+		if ctxErr := contextError(ctx); ctxErr != nil {
+			err = ctxErr
+		}
 		return nil, Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			Wrapped:      err,
 			message:      "unable to decode message length",
 		}
@@ -741,18 +972,25 @@ func (c *connection) ReadWireMessage(ctx context.Context) (wiremessage.WireMessa
 	_, err = io.ReadFull(c.conn, c.readBuf[4:])
 	if err != nil {
 		c.Close()
+		if ctxErr := contextError(ctx); ctxErr != nil {
+			err = ctxErr
+		}
 		return nil, Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			Wrapped:      err,
 			message:      "unable to read full message",
 		}
 	}
 
+	c.traffic.received(len(c.readBuf))
+
 	hdr, err := wiremessage.ReadHeader(c.readBuf, 0)
 	if err != nil {
 		c.Close()
 		return nil, Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			Wrapped:      err,
 			message:      "unable to decode header",
 		}
@@ -768,6 +1006,7 @@ func (c *connection) ReadWireMessage(ctx context.Context) (wiremessage.WireMessa
 			defer c.Close()
 			return nil, Error{
 				ConnectionID: c.id,
+				Address:      c.addr,
 				Wrapped:      err,
 				message:      "unable to decode OP_COMPRESSED",
 			}
@@ -778,6 +1017,7 @@ func (c *connection) ReadWireMessage(ctx context.Context) (wiremessage.WireMessa
 			defer c.Close()
 			return nil, Error{
 				ConnectionID: c.id,
+				Address:      c.addr,
 				Wrapped:      err,
 				message:      "unable to uncompress message",
 			}
@@ -795,6 +1035,7 @@ func (c *connection) ReadWireMessage(ctx context.Context) (wiremessage.WireMessa
 			c.Close()
 			return nil, Error{
 				ConnectionID: c.id,
+				Address:      c.addr,
 				Wrapped:      err,
 				message:      "unable to decode OP_REPLY",
 			}
@@ -807,6 +1048,7 @@ func (c *connection) ReadWireMessage(ctx context.Context) (wiremessage.WireMessa
 			c.Close()
 			return nil, Error{
 				ConnectionID: c.id,
+				Address:      c.addr,
 				Wrapped:      err,
 				message:      "unable to decode OP_MSG",
 			}
@@ -816,6 +1058,7 @@ func (c *connection) ReadWireMessage(ctx context.Context) (wiremessage.WireMessa
 		c.Close()
 		return nil, Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			message:      fmt.Sprintf("opcode %s not implemented", hdr.OpCode),
 		}
 	}
@@ -835,12 +1078,49 @@ func (c *connection) bumpIdleDeadline() {
 	}
 }
 
+// watchContext aborts a blocking read or write on the connection once ctx is done by moving the
+// deadline of the net.Conn into the past. The returned function stops watching ctx and must be
+// called once the read or write returns, before the deadline is set again.
+func (c *connection) watchContext(ctx context.Context) func() {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			_ = c.conn.SetDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// contextError returns the error of ctx if it is done or its deadline has passed, in which case a
+// failed read or write was caused by ctx rather than the network.
+func contextError(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
 func (c *connection) Close() error {
 	c.dead = true
 	err := c.conn.Close()
 	if err != nil {
 		return Error{
 			ConnectionID: c.id,
+			Address:      c.addr,
 			Wrapped:      err,
 			message:      "failed to close net.Conn",
 		}
@@ -853,6 +1133,15 @@ func (c *connection) ID() string {
 	return c.id
 }
 
+// Compressor returns the name of the compressor negotiated for the connection, or an empty string
+// if messages sent on it are not compressed.
+func (c *connection) Compressor() string {
+	if c.compressor == nil {
+		return ""
+	}
+	return c.compressor.Name()
+}
+
 func (c *connection) initialize(ctx context.Context, appName string) error {
 	return nil
 }
@@ -8,9 +8,23 @@ package connection
 
 import (
 	"context"
+	"errors"
+	"io"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/compressor"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
 )
 
 // bootstrapConnection creates a listener that will listen for a single connection
@@ -87,3 +101,311 @@ func (d *dialer) lenclosed() int {
 	defer d.Unlock()
 	return len(d.closed)
 }
+
+func TestCanMonitor(t *testing.T) {
+	for _, name := range []string{"saslStart", "SASLSTART", "authenticate", "copydbSaslStart", "createUser"} {
+		if canMonitor(name) {
+			t.Errorf("expected %s to be redacted", name)
+		}
+	}
+	for _, name := range []string{"find", "insert", "isMaster"} {
+		if !canMonitor(name) {
+			t.Errorf("expected %s to be monitored", name)
+		}
+	}
+}
+
+func TestConnectionReadFailurePublishesFailedEvents(t *testing.T) {
+	client, server := net.Pipe()
+	_ = server.Close()
+
+	var failed []*event.CommandFailedEvent
+	c := &connection{
+		addr:       address.Address("localhost:27017"),
+		id:         "localhost:27017[-1]",
+		conn:       client,
+		commandMap: map[int64]*commandMetadata{42: createMetadata("find", false, "")},
+		cmdMonitor: &event.CommandMonitor{
+			Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+				failed = append(failed, evt)
+			},
+		},
+	}
+
+	if _, err := c.ReadWireMessage(context.Background()); err == nil {
+		t.Fatal("expected error reading from closed connection")
+	}
+	if len(failed) != 1 {
+		t.Fatalf("expected 1 failed event, got %d", len(failed))
+	}
+	evt := failed[0]
+	if evt.CommandName != "find" || evt.RequestID != 42 || evt.ServerAddress != "localhost:27017" || evt.Failure == "" {
+		t.Errorf("unexpected failed event: %+v", evt)
+	}
+	if len(c.commandMap) != 0 {
+		t.Errorf("expected pending commands to be cleared, got %d", len(c.commandMap))
+	}
+}
+
+func TestConnectionContextCancellation(t *testing.T) {
+	newConn := func() (*connection, net.Conn) {
+		client, server := net.Pipe()
+		return &connection{
+			addr:       address.Address("localhost:27017"),
+			id:         "localhost:27017[-1]",
+			conn:       client,
+			commandMap: make(map[int64]*commandMetadata),
+		}, server
+	}
+	checkErr := func(t *testing.T, c *connection, err error) {
+		connErr, ok := err.(Error)
+		if !ok {
+			t.Fatalf("expected a connection Error, got %T: %v", err, err)
+		}
+		if connErr.Wrapped != context.Canceled {
+			t.Errorf("expected the error to wrap context.Canceled, got %v", connErr.Wrapped)
+		}
+		if connErr.Address != c.addr {
+			t.Errorf("expected the error to have address %v, got %v", c.addr, connErr.Address)
+		}
+		if !c.dead {
+			t.Errorf("expected the connection to be closed")
+		}
+	}
+
+	t.Run("read", func(t *testing.T) {
+		c, server := newConn()
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+		_, err := c.ReadWireMessage(ctx)
+		checkErr(t, c, err)
+	})
+	t.Run("write", func(t *testing.T) {
+		c, server := newConn()
+		defer server.Close()
+
+		body := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "ping", 1))
+		msg := wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: body}}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+		}()
+		err := c.WriteWireMessage(ctx, msg)
+		checkErr(t, c, err)
+	})
+}
+
+func TestConnectionTraffic(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	tc := &trafficCounter{}
+	c := &connection{
+		addr:       address.Address("localhost:27017"),
+		id:         "localhost:27017[-1]",
+		conn:       client,
+		commandMap: make(map[int64]*commandMetadata),
+		traffic:    tc,
+	}
+
+	body := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "ping", 1))
+	msg := wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: body}}}
+	wm, err := msg.AppendWireMessage(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The server echoes the message back.
+	go func() {
+		buf := make([]byte, len(wm))
+		if _, err := io.ReadFull(server, buf); err == nil {
+			_, _ = server.Write(buf)
+		}
+	}()
+
+	if err := c.WriteWireMessage(context.Background(), msg); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if _, err := c.ReadWireMessage(context.Background()); err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	want := TrafficStats{
+		BytesSent:        uint64(len(wm)),
+		BytesReceived:    uint64(len(wm)),
+		MessagesSent:     1,
+		MessagesReceived: 1,
+	}
+	if got := tc.snapshot(); got != want {
+		t.Errorf("unexpected traffic. got %+v; want %+v", got, want)
+	}
+}
+
+func TestConnectionAddressMap(t *testing.T) {
+	var dialed []string
+	dialer := DialerFunc(func(_ context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, network+" "+addr)
+		client, server := net.Pipe()
+		_ = server.Close()
+		return client, nil
+	})
+	addressMap := map[string]string{
+		"DB1.internal":       "localhost:30001",
+		"db2.internal:27018": "/tmp/tunnel.sock",
+	}
+
+	for _, addr := range []address.Address{"db1.internal:27017", "db2.internal:27018", "db3.internal:27017"} {
+		conn, _, err := New(context.Background(), addr,
+			WithDialer(func(Dialer) Dialer { return dialer }),
+			WithAddressMap(func(map[string]string) map[string]string { return addressMap }),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error connecting to %s: %v", addr, err)
+		}
+		if got := conn.(*connection).addr; got != addr {
+			t.Errorf("expected connection address %s, got %s", addr, got)
+		}
+		_ = conn.Close()
+	}
+
+	want := []string{"tcp localhost:30001", "unix /tmp/tunnel.sock", "tcp db3.internal:27017"}
+	if !reflect.DeepEqual(dialed, want) {
+		t.Errorf("dialed addresses do not match. got %v; want %v", dialed, want)
+	}
+}
+
+func TestConnectionUnixDomainSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mongo-go-driver")
+	if err != nil {
+		t.Fatalf("could not create a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "MongoDB-27017.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("unix domain sockets are not supported: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		nc, err := l.Accept()
+		if err == nil {
+			_ = nc.Close()
+		}
+	}()
+
+	// The host resolver must not be used for unix domain sockets.
+	resolver := hostResolverFunc(func(context.Context, string) ([]string, error) {
+		return nil, errors.New("unexpected host resolution")
+	})
+	conn, _, err := New(context.Background(), address.Address(path),
+		WithHostResolver(func(HostResolver) HostResolver { return resolver }),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error connecting to %s: %v", path, err)
+	}
+	_ = conn.Close()
+}
+
+func TestConnectionCompressionPolicy(t *testing.T) {
+	msg := func(cmd string, size int) wiremessage.Msg {
+		doc := bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendInt32Element(nil, cmd, 1),
+			bsoncore.AppendStringElement(nil, "padding", strings.Repeat("a", size)),
+		)
+		return wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: doc}}}
+	}
+
+	testCases := []struct {
+		name     string
+		opts     []Option
+		cmd      string
+		size     int
+		compress bool
+	}{
+		{"default", nil, "find", 10, true},
+		{"mandatory exclusion", nil, "saslStart", 10, false},
+		{"below minimum size", []Option{WithCompressionMinSize(func(int) int { return 1024 })}, "find", 10, false},
+		{"above minimum size", []Option{WithCompressionMinSize(func(int) int { return 1024 })}, "find", 2048, true},
+		{"allow list match", []Option{WithCompressionAllowList(func([]string) []string { return []string{"insert"} })}, "insert", 10, true},
+		{"allow list miss", []Option{WithCompressionAllowList(func([]string) []string { return []string{"insert"} })}, "find", 10, false},
+		{"allow list cannot override exclusions", []Option{WithCompressionAllowList(func([]string) []string { return []string{"saslStart"} })}, "saslStart", 10, false},
+		{"deny list match", []Option{WithCompressionDenyList(func([]string) []string { return []string{"find"} })}, "find", 10, false},
+		{"deny list miss", []Option{WithCompressionDenyList(func([]string) []string { return []string{"find"} })}, "insert", 10, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := newConfig(tc.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			c := &connection{compressor: compressor.CreateSnappy(), compressPol: newCompressionPolicy(cfg)}
+
+			wm, err := c.compressMessage(msg(tc.cmd, tc.size))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, compressed := wm.(wiremessage.Compressed); compressed != tc.compress {
+				t.Errorf("expected compressed to be %v, got %v", tc.compress, compressed)
+			}
+		})
+	}
+}
+
+func TestNegotiateCompressor(t *testing.T) {
+	zlibComp, err := compressor.CreateZlib(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	snappyComp := compressor.CreateSnappy()
+	compressors := map[wiremessage.CompressorID]compressor.Compressor{
+		zlibComp.CompressorID():   zlibComp,
+		snappyComp.CompressorID(): snappyComp,
+	}
+	withCompressors := func(names ...string) Option {
+		return WithCompressors(func([]string) []string { return names })
+	}
+	withSelector := func(fn func(string, []string) string) Option {
+		return WithCompressorSelector(func(func(string, []string) string) func(string, []string) string { return fn })
+	}
+
+	testCases := []struct {
+		name   string
+		opts   []Option
+		server []string
+		want   string
+	}{
+		{"client preference", []Option{withCompressors("zlib", "snappy")}, []string{"snappy", "zlib"}, "zlib"},
+		{"client preference reversed", []Option{withCompressors("snappy", "zlib")}, []string{"zlib", "snappy"}, "snappy"},
+		{"server subset", []Option{withCompressors("zlib", "snappy")}, []string{"snappy"}, "snappy"},
+		{"unsupported compressors skipped", []Option{withCompressors("zstd", "zlib")}, []string{"zstd", "zlib"}, "zlib"},
+		{"no common compressor", []Option{withCompressors("zlib")}, []string{"snappy"}, ""},
+		{"selector override", []Option{withCompressors("zlib", "snappy"), withSelector(func(addr string, common []string) string {
+			return common[len(common)-1]
+		})}, []string{"zlib", "snappy"}, "snappy"},
+		{"selector disables compression", []Option{withCompressors("zlib"), withSelector(func(string, []string) string {
+			return ""
+		})}, []string{"zlib"}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := newConfig(tc.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got string
+			if comp := negotiateCompressor(cfg, "localhost:27017", compressors, tc.server); comp != nil {
+				got = comp.Name()
+			}
+			if got != tc.want {
+				t.Errorf("expected compressor %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+// Crypt is implemented by types that encrypt the commands sent over a connection and decrypt the
+// replies to them, for client-side field level encryption.
+type Crypt interface {
+	// Encrypt returns cmd, a command run against db, with the fields that must be encrypted
+	// replaced by their encrypted values.
+	Encrypt(ctx context.Context, db string, cmd bsoncore.Document) (bsoncore.Document, error)
+	// Decrypt returns cmdResponse with all encrypted values replaced by their decrypted values.
+	Decrypt(ctx context.Context, cmdResponse bsoncore.Document) (bsoncore.Document, error)
+}
+
+// ErrCryptRequiresOpMsg is returned when a command would be sent to a server that does not support
+// OP_MSG over a connection that encrypts commands. Automatic encryption requires MongoDB 4.2 or
+// later.
+var ErrCryptRequiresOpMsg = errors.New("auto-encryption requires a server that supports OP_MSG")
+
+// encryptMessage encrypts the command of wm, which must be an OP_MSG. Any document sequences of
+// the message are folded into the command document, so the returned message has a single section.
+func encryptMessage(ctx context.Context, crypt Crypt, wm wiremessage.WireMessage) (wiremessage.WireMessage, error) {
+	msg, ok := wm.(wiremessage.Msg)
+	if !ok {
+		return nil, ErrCryptRequiresOpMsg
+	}
+
+	cmd, err := msgCommand(msg)
+	if err != nil {
+		return nil, err
+	}
+	db, _ := cmd.Lookup("$db").StringValueOK()
+
+	cmd, wireFields, err := splitWireFields(cmd)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := crypt.Encrypt(ctx, db, cmd)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) < 5 {
+		return nil, errors.New("encrypted command is not a valid document")
+	}
+	encrypted = bsoncore.BuildDocument(nil, append(append([]byte(nil), encrypted[4:len(encrypted)-1]...), wireFields...))
+
+	return wiremessage.Msg{
+		MsgHeader: wiremessage.Header{
+			RequestID:  msg.MsgHeader.RequestID,
+			ResponseTo: msg.MsgHeader.ResponseTo,
+			OpCode:     wiremessage.OpMsg,
+		},
+		FlagBits: msg.FlagBits &^ wiremessage.ChecksumPresent,
+		Sections: []wiremessage.Section{
+			wiremessage.SectionBody{PayloadType: wiremessage.SingleDocument, Document: bson.Raw(encrypted)},
+		},
+	}, nil
+}
+
+// decryptMessage decrypts the reply document of wm if it is an OP_MSG. Other messages are returned
+// unchanged.
+func decryptMessage(ctx context.Context, crypt Crypt, wm wiremessage.WireMessage) (wiremessage.WireMessage, error) {
+	msg, ok := wm.(wiremessage.Msg)
+	if !ok || len(msg.Sections) == 0 {
+		return wm, nil
+	}
+	body, ok := msg.Sections[0].(wiremessage.SectionBody)
+	if !ok {
+		return wm, nil
+	}
+
+	decrypted, err := crypt.Decrypt(ctx, bsoncore.Document(body.Document))
+	if err != nil {
+		return nil, err
+	}
+
+	sections := make([]wiremessage.Section, len(msg.Sections))
+	copy(sections, msg.Sections)
+	sections[0] = wiremessage.SectionBody{PayloadType: body.PayloadType, Document: bson.Raw(decrypted)}
+	msg.Sections = sections
+	msg.MsgHeader.MessageLength = int32(msg.Len())
+	return msg, nil
+}
+
+// wireFields are the fields added to a command by the driver rather than the user. They are
+// removed from commands before encryption and added back afterwards.
+var wireFields = map[string]struct{}{
+	"$db":                  {},
+	"$clusterTime":         {},
+	"$readPreference":      {},
+	"lsid":                 {},
+	"txnNumber":            {},
+	"startTransaction":     {},
+	"autocommit":           {},
+	"apiVersion":           {},
+	"apiStrict":            {},
+	"apiDeprecationErrors": {},
+}
+
+// splitWireFields returns the elements of cmd that are not wire fields as a document, and the
+// elements that are as a concatenation of elements.
+func splitWireFields(cmd bsoncore.Document) (bsoncore.Document, []byte, error) {
+	elems, err := cmd.Elements()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	idx, stripped := bsoncore.AppendDocumentStart(nil)
+	var wire []byte
+	for _, elem := range elems {
+		if _, ok := wireFields[elem.Key()]; ok {
+			wire = append(wire, elem...)
+			continue
+		}
+		stripped = append(stripped, elem...)
+	}
+	stripped, err = bsoncore.AppendDocumentEnd(stripped, idx)
+	return stripped, wire, err
+}
+
+// msgCommand returns the command document of msg with the documents of each document sequence
+// appended to it as an array named by the identifier of the sequence.
+func msgCommand(msg wiremessage.Msg) (bsoncore.Document, error) {
+	var body bson.Raw
+	var sequences []wiremessage.SectionDocumentSequence
+	for _, section := range msg.Sections {
+		switch s := section.(type) {
+		case wiremessage.SectionBody:
+			body = s.Document
+		case wiremessage.SectionDocumentSequence:
+			sequences = append(sequences, s)
+		}
+	}
+	if len(body) < 5 {
+		return nil, errors.New("OP_MSG does not contain a command document")
+	}
+	if len(sequences) == 0 {
+		return bsoncore.Document(body), nil
+	}
+
+	idx, cmd := bsoncore.AppendDocumentStart(nil)
+	cmd = append(cmd, body[4:len(body)-1]...) // the elements of the body
+	for _, seq := range sequences {
+		var arrIdx int32
+		arrIdx, cmd = bsoncore.AppendArrayElementStart(cmd, seq.Identifier)
+		for i, doc := range seq.Documents {
+			cmd = bsoncore.AppendDocumentElement(cmd, strconv.Itoa(i), doc)
+		}
+		cmd, _ = bsoncore.AppendArrayEnd(cmd, arrIdx)
+	}
+	return bsoncore.AppendDocumentEnd(cmd, idx)
+}
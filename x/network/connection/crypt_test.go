@@ -0,0 +1,87 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+// markingCrypt records the commands it encrypts and appends an "encrypted" field to them.
+type markingCrypt struct {
+	db  string
+	cmd bsoncore.Document
+}
+
+func (mc *markingCrypt) Encrypt(_ context.Context, db string, cmd bsoncore.Document) (bsoncore.Document, error) {
+	mc.db, mc.cmd = db, cmd
+	elems := append(append([]byte(nil), cmd[4:len(cmd)-1]...), bsoncore.AppendBooleanElement(nil, "encrypted", true)...)
+	return bsoncore.BuildDocument(nil, elems), nil
+}
+
+func (mc *markingCrypt) Decrypt(_ context.Context, cmdResponse bsoncore.Document) (bsoncore.Document, error) {
+	return bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendBooleanElement(nil, "decrypted", true)), nil
+}
+
+func TestCrypt(t *testing.T) {
+	t.Run("encrypt", func(t *testing.T) {
+		body := bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendStringElement(nil, "insert", "coll"),
+			bsoncore.AppendStringElement(nil, "$db", "db"),
+			bsoncore.AppendInt64Element(nil, "txnNumber", 1),
+		)
+		doc := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "x", 1))
+		msg := wiremessage.Msg{
+			MsgHeader: wiremessage.Header{RequestID: 7},
+			Sections: []wiremessage.Section{
+				wiremessage.SectionBody{Document: bson.Raw(body)},
+				wiremessage.SectionDocumentSequence{Identifier: "documents", Documents: []bson.Raw{bson.Raw(doc)}},
+			},
+		}
+
+		crypt := &markingCrypt{}
+		wm, err := encryptMessage(context.Background(), crypt, msg)
+		require.NoError(t, err)
+
+		require.Equal(t, "db", crypt.db)
+		wantCmd := bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendStringElement(nil, "insert", "coll"),
+			bsoncore.BuildArrayElement(nil, "documents", bsoncore.Value{Type: bson.TypeEmbeddedDocument, Data: doc}),
+		)
+		require.Equal(t, bsoncore.Document(wantCmd), crypt.cmd)
+
+		encrypted, ok := wm.(wiremessage.Msg)
+		require.True(t, ok)
+		require.Equal(t, int32(7), encrypted.MsgHeader.RequestID)
+		require.Len(t, encrypted.Sections, 1)
+		sent := bsoncore.Document(encrypted.Sections[0].(wiremessage.SectionBody).Document)
+		require.True(t, sent.Lookup("encrypted").Boolean())
+		require.Equal(t, "db", sent.Lookup("$db").StringValue())
+		require.Equal(t, int64(1), sent.Lookup("txnNumber").Int64())
+
+		_, err = encrypted.MarshalWireMessage()
+		require.NoError(t, err)
+	})
+	t.Run("encrypt requires OP_MSG", func(t *testing.T) {
+		_, err := encryptMessage(context.Background(), &markingCrypt{}, wiremessage.Query{})
+		require.Equal(t, ErrCryptRequiresOpMsg, err)
+	})
+	t.Run("decrypt", func(t *testing.T) {
+		reply := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendDoubleElement(nil, "ok", 1))
+		msg := wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: bson.Raw(reply)}}}
+
+		wm, err := decryptMessage(context.Background(), &markingCrypt{}, msg)
+		require.NoError(t, err)
+		decrypted := wm.(wiremessage.Msg).Sections[0].(wiremessage.SectionBody).Document
+		require.True(t, decrypted.Lookup("decrypted").Boolean())
+	})
+}
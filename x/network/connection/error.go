@@ -6,11 +6,16 @@
 
 package connection
 
-import "fmt"
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/x/network/address"
+)
 
 // Error represents a connection error.
 type Error struct {
 	ConnectionID string
+	Address      address.Address // The address of the server the connection is to.
 	Wrapped      error
 
 	message string
@@ -24,6 +29,11 @@ func (e Error) Error() string {
 	return fmt.Sprintf("connection(%s) %s", e.ConnectionID, e.message)
 }
 
+// Unwrap returns the underlying error.
+func (e Error) Unwrap() error {
+	return e.Wrapped
+}
+
 // NetworkError represents an error that occurred while reading from or writing
 // to a network socket.
 type NetworkError struct {
@@ -35,6 +45,11 @@ func (ne NetworkError) Error() string {
 	return fmt.Sprintf("connection(%s): %s", ne.ConnectionID, ne.Wrapped.Error())
 }
 
+// Unwrap returns the underlying error.
+func (ne NetworkError) Unwrap() error {
+	return ne.Wrapped
+}
+
 // PoolError is an error returned from a Pool method.
 type PoolError string
 
@@ -11,20 +11,36 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/ocsp"
 )
 
 type config struct {
+	addressMap     map[string]string
 	appName        string
 	connectTimeout time.Duration
 	dialer         Dialer
 	handshaker     Handshaker
+	hostResolver   HostResolver
 	idleTimeout    time.Duration
 	lifeTimeout    time.Duration
+	ocspConfig     *ocsp.Config
 	cmdMonitor     *event.CommandMonitor
+	connMonitor    *event.ConnectionMonitor
+	crypt          Crypt
+	minPoolSize    uint64
+	minRTT         func() time.Duration
+	traffic        *trafficCounter
+	poolMonitor    *event.PoolMonitor
 	readTimeout    time.Duration
+	serverAPI      *ServerAPI
 	writeTimeout   time.Duration
 	tlsConfig      *TLSConfig
 	compressors    []string
+	compressSelect func(string, []string) string
+	compressAllow  []string
+	compressDeny   []string
+	compressMin    int
 	zlibLevel      *int
 }
 
@@ -43,12 +59,27 @@ func newConfig(opts ...Option) (*config, error) {
 		}
 	}
 
+	if len(cfg.addressMap) > 0 {
+		canonical := make(map[string]string, len(cfg.addressMap))
+		for from, to := range cfg.addressMap {
+			canonical[address.Address(from).String()] = to
+		}
+		cfg.addressMap = canonical
+	}
+
 	if cfg.dialer == nil {
+		if cfg.connMonitor != nil && cfg.hostResolver == nil {
+			// Resolve host names separately so that the time spent resolving them is reported.
+			cfg.hostResolver = net.DefaultResolver
+		}
 		cfg.dialer = &net.Dialer{
 			KeepAlive: tcpKeepalive,
 			Timeout:   cfg.connectTimeout,
 		}
 	}
+	if cfg.hostResolver != nil {
+		cfg.dialer = &resolvingDialer{dialer: cfg.dialer, resolver: cfg.hostResolver}
+	}
 
 	return cfg, nil
 }
@@ -56,6 +87,16 @@ func newConfig(opts ...Option) (*config, error) {
 // Option is used to configure a connection.
 type Option func(*config) error
 
+// WithAddressMap configures a map from server addresses to the addresses that are dialed to
+// connect to them, such as the local ports of SSH tunnels. Connections are still identified by the
+// server address, which is also used for TLS hostname verification.
+func WithAddressMap(fn func(map[string]string) map[string]string) Option {
+	return func(c *config) error {
+		c.addressMap = fn(c.addressMap)
+		return nil
+	}
+}
+
 // WithAppName sets the application name which gets sent to MongoDB when it
 // first connects.
 func WithAppName(fn func(string) string) Option {
@@ -73,6 +114,43 @@ func WithCompressors(fn func([]string) []string) Option {
 	}
 }
 
+// WithCompressorSelector sets a function that chooses the compressor of each connection. It is
+// called with the address of the server and the compressors both the client and the server
+// support, in the client's order of preference, and returns the name of the compressor to use, or
+// an empty string to not compress messages on the connection.
+func WithCompressorSelector(fn func(func(string, []string) string) func(string, []string) string) Option {
+	return func(c *config) error {
+		c.compressSelect = fn(c.compressSelect)
+		return nil
+	}
+}
+
+// WithCompressionAllowList configures the commands that are compressed. If the list is empty, every
+// command that may be compressed is.
+func WithCompressionAllowList(fn func([]string) []string) Option {
+	return func(c *config) error {
+		c.compressAllow = fn(c.compressAllow)
+		return nil
+	}
+}
+
+// WithCompressionDenyList configures commands that are never compressed, in addition to the
+// commands that must not be compressed, such as authentication commands.
+func WithCompressionDenyList(fn func([]string) []string) Option {
+	return func(c *config) error {
+		c.compressDeny = fn(c.compressDeny)
+		return nil
+	}
+}
+
+// WithCompressionMinSize configures the size in bytes below which messages are not compressed.
+func WithCompressionMinSize(fn func(int) int) Option {
+	return func(c *config) error {
+		c.compressMin = fn(c.compressMin)
+		return nil
+	}
+}
+
 // WithConnectTimeout configures the maximum amount of time a dial will wait for a
 // connect to complete. The default is 30 seconds.
 func WithConnectTimeout(fn func(time.Duration) time.Duration) Option {
@@ -99,6 +177,15 @@ func WithHandshaker(fn func(Handshaker) Handshaker) Option {
 	}
 }
 
+// WithHostResolver configures the HostResolver used to resolve host names before they are dialed.
+// By default host names are resolved by the Dialer.
+func WithHostResolver(fn func(HostResolver) HostResolver) Option {
+	return func(c *config) error {
+		c.hostResolver = fn(c.hostResolver)
+		return nil
+	}
+}
+
 // WithIdleTimeout configures the maximum idle time to allow for a connection.
 func WithIdleTimeout(fn func(time.Duration) time.Duration) Option {
 	return func(c *config) error {
@@ -131,6 +218,15 @@ func WithWriteTimeout(fn func(time.Duration) time.Duration) Option {
 	}
 }
 
+// WithServerAPI configures the server API version declared on every command sent over the
+// connection, including the commands sent during the handshake.
+func WithServerAPI(fn func(*ServerAPI) *ServerAPI) Option {
+	return func(c *config) error {
+		c.serverAPI = fn(c.serverAPI)
+		return nil
+	}
+}
+
 // WithTLSConfig configures the TLS options for a connection.
 func WithTLSConfig(fn func(*TLSConfig) *TLSConfig) Option {
 	return func(c *config) error {
@@ -139,6 +235,32 @@ func WithTLSConfig(fn func(*TLSConfig) *TLSConfig) Option {
 	}
 }
 
+// WithOCSP configures how the revocation of server certificates is checked after the TLS handshake.
+// If it is nil, which is the default, revocation is not checked.
+func WithOCSP(fn func(*ocsp.Config) *ocsp.Config) Option {
+	return func(c *config) error {
+		c.ocspConfig = fn(c.ocspConfig)
+		return nil
+	}
+}
+
+// WithMinPoolSize configures the minimum number of connections a pool keeps open, creating
+// connections in the background when there are fewer. It is only used by pools.
+func WithMinPoolSize(fn func(uint64) uint64) Option {
+	return func(c *config) error {
+		c.minPoolSize = fn(c.minPoolSize)
+		return nil
+	}
+}
+
+// WithPoolMonitor configures a monitor for connection pool events. It is only used by pools.
+func WithPoolMonitor(fn func(*event.PoolMonitor) *event.PoolMonitor) Option {
+	return func(c *config) error {
+		c.poolMonitor = fn(c.poolMonitor)
+		return nil
+	}
+}
+
 // WithMonitor configures a event for command monitoring.
 func WithMonitor(fn func(*event.CommandMonitor) *event.CommandMonitor) Option {
 	return func(c *config) error {
@@ -147,6 +269,41 @@ func WithMonitor(fn func(*event.CommandMonitor) *event.CommandMonitor) Option {
 	}
 }
 
+// WithConnectionMonitor configures a monitor for the stages of establishing connections.
+func WithConnectionMonitor(fn func(*event.ConnectionMonitor) *event.ConnectionMonitor) Option {
+	return func(c *config) error {
+		c.connMonitor = fn(c.connMonitor)
+		return nil
+	}
+}
+
+// WithCrypt configures a Crypt used to encrypt the commands sent over the connection and decrypt
+// the replies to them. Commands sent during the handshake are not encrypted.
+func WithCrypt(fn func(Crypt) Crypt) Option {
+	return func(c *config) error {
+		c.crypt = fn(c.crypt)
+		return nil
+	}
+}
+
+// WithMinRTT configures a function returning the minimum round trip time to the server. It is
+// subtracted from the time remaining before the deadline of an operation timeout to derive the
+// maxTimeMS of commands, so the server gives up on them before the client stops waiting for them.
+func WithMinRTT(fn func(func() time.Duration) func() time.Duration) Option {
+	return func(c *config) error {
+		c.minRTT = fn(c.minRTT)
+		return nil
+	}
+}
+
+// withTrafficCounter configures the counter of the wire messages of connections.
+func withTrafficCounter(tc *trafficCounter) Option {
+	return func(c *config) error {
+		c.traffic = tc
+		return nil
+	}
+}
+
 // WithZlibLevel sets the zLib compression level.
 func WithZlibLevel(fn func(*int) *int) Option {
 	return func(c *config) error {
@@ -10,7 +10,9 @@ import (
 	"context"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/x/network/address"
 	"go.mongodb.org/mongo-driver/x/network/description"
 	"go.mongodb.org/mongo-driver/x/network/wiremessage"
@@ -24,6 +26,13 @@ var ErrPoolClosed = PoolError("pool is closed")
 // larger than the capacity.
 var ErrSizeLargerThanCapacity = PoolError("size is larger than capacity")
 
+// ErrMinSizeLargerThanSize is returned from an attempt to create a pool with a minimum size
+// larger than the number of idle connections it can hold.
+var ErrMinSizeLargerThanSize = PoolError("min size is larger than size")
+
+// minSizeInterval is how often a pool with a minimum size checks that it has enough connections.
+var minSizeInterval = 10 * time.Second
+
 // ErrPoolConnected is returned from an attempt to connect an already connected pool
 var ErrPoolConnected = PoolError("pool is connected")
 
@@ -59,6 +68,8 @@ type Pool interface {
 	// multiple times after a single Connect call must result in an error.
 	Disconnect(context.Context) error
 	Drain() error
+	// Stats returns a snapshot of the state of the pool.
+	Stats() PoolStats
 }
 
 type pool struct {
@@ -70,7 +81,14 @@ type pool struct {
 	connected  int32
 	nextid     uint64
 	capacity   uint64
+	minSize    uint64
 	inflight   map[uint64]*pooledConnection
+	monitor    *event.PoolMonitor
+	done       chan struct{} // Closed when the pool disconnects to stop populating it.
+	waiting    int64         // The number of checkouts in progress.
+
+	checkOutLatency latencyHistogram
+	traffic         *trafficCounter
 
 	sync.Mutex
 }
@@ -82,6 +100,13 @@ func NewPool(addr address.Address, size, capacity uint64, opts ...Option) (Pool,
 	if size > capacity {
 		return nil, ErrSizeLargerThanCapacity
 	}
+	cfg, err := newConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.minPoolSize > size {
+		return nil, ErrMinSizeLargerThanSize
+	}
 	p := &pool{
 		address:    addr,
 		conns:      make(chan *pooledConnection, size),
@@ -89,14 +114,35 @@ func NewPool(addr address.Address, size, capacity uint64, opts ...Option) (Pool,
 		sem:        semaphore.NewWeighted(int64(capacity)),
 		connected:  disconnected,
 		capacity:   capacity,
+		minSize:    cfg.minPoolSize,
 		inflight:   make(map[uint64]*pooledConnection),
-		opts:       opts,
+		monitor:    cfg.poolMonitor,
+		traffic:    &trafficCounter{},
 	}
+	// The connections of the pool count their wire messages in its traffic counter.
+	p.opts = append(opts[:len(opts):len(opts)], withTrafficCounter(p.traffic))
+	p.publish(&event.PoolEvent{
+		Type: event.PoolCreated,
+		PoolOptions: &event.MonitorPoolOptions{
+			MaxPoolSize:     capacity,
+			MaxIdlePoolSize: size,
+		},
+	})
 	return p, nil
 }
 
+// publish sends evt to the pool monitor, if there is one.
+func (p *pool) publish(evt *event.PoolEvent) {
+	if p.monitor == nil || p.monitor.Event == nil {
+		return
+	}
+	evt.Address = p.address.String()
+	p.monitor.Event(evt)
+}
+
 func (p *pool) Drain() error {
 	atomic.AddUint64(&p.generation, 1)
+	p.publish(&event.PoolEvent{Type: event.PoolCleared})
 	return nil
 }
 
@@ -105,6 +151,10 @@ func (p *pool) Connect(ctx context.Context) error {
 		return ErrPoolConnected
 	}
 	atomic.AddUint64(&p.generation, 1)
+	if p.minSize > 0 {
+		p.done = make(chan struct{})
+		go p.maintain(p.done)
+	}
 	return nil
 }
 
@@ -112,6 +162,10 @@ func (p *pool) Disconnect(ctx context.Context) error {
 	if !atomic.CompareAndSwapInt32(&p.connected, connected, disconnecting) {
 		return ErrPoolDisconnected
 	}
+	if p.done != nil {
+		close(p.done)
+		p.done = nil
+	}
 
 	// We first clear out the idle connections, then we attempt to acquire the entire capacity
 	// semaphore. If the context is either cancelled, the deadline expires, or there is a timeout
@@ -123,7 +177,7 @@ loop:
 		select {
 		case pc := <-p.conns:
 			// This error would be overwritten by the semaphore
-			_ = p.closeConnection(pc)
+			_ = p.closeConnection(pc, event.ReasonPoolClosed)
 		default:
 			break loop
 		}
@@ -147,20 +201,42 @@ loop:
 		p.sem.Release(int64(p.capacity))
 	}
 	atomic.StoreInt32(&p.connected, disconnected)
+	p.publish(&event.PoolEvent{Type: event.PoolClosedEvent})
 	return nil
 }
 
 func (p *pool) Get(ctx context.Context) (Connection, *description.Server, error) {
+	p.publish(&event.PoolEvent{Type: event.GetStarted})
+	atomic.AddInt64(&p.waiting, 1)
+	defer atomic.AddInt64(&p.waiting, -1)
+	start := time.Now()
+
 	if atomic.LoadInt32(&p.connected) != connected {
+		p.publish(&event.PoolEvent{Type: event.GetFailed, Reason: event.ReasonPoolClosed})
 		return nil, nil, ErrPoolClosed
 	}
 
 	err := p.sem.Acquire(ctx, 1)
 	if err != nil {
+		p.publish(&event.PoolEvent{Type: event.GetFailed, Reason: event.ReasonTimedOut})
+		return nil, nil, err
+	}
+
+	conn, desc, err := p.get(ctx)
+	if err != nil {
+		reason := event.ReasonConnectionErrored
+		switch {
+		case err == ErrPoolClosed:
+			reason = event.ReasonPoolClosed
+		case ctx.Err() != nil:
+			reason = event.ReasonTimedOut
+		}
+		p.publish(&event.PoolEvent{Type: event.GetFailed, Reason: reason})
 		return nil, nil, err
 	}
 
-	return p.get(ctx)
+	p.checkOutLatency.record(time.Since(start))
+	return conn, desc, nil
 }
 
 func (p *pool) get(ctx context.Context) (Connection, *description.Server, error) {
@@ -168,10 +244,11 @@ func (p *pool) get(ctx context.Context) (Connection, *description.Server, error)
 	select {
 	case c := <-p.conns:
 		if c.Expired() {
-			go p.closeConnection(c)
+			go p.closeConnection(c, p.expiredReason(c))
 			return p.get(ctx)
 		}
 
+		p.publish(&event.PoolEvent{Type: event.GetSucceeded, ConnectionID: c.id})
 		return &acquired{Connection: c, sem: p.sem}, nil, nil
 	case <-ctx.Done():
 		p.sem.Release(1)
@@ -189,39 +266,113 @@ func (p *pool) get(ctx context.Context) (Connection, *description.Server, error)
 			generation: g,
 			id:         atomic.AddUint64(&p.nextid, 1),
 		}
+		// The connection is handshaked when it is created, so it is ready to use immediately.
+		p.publish(&event.PoolEvent{Type: event.ConnectionCreated, ConnectionID: pc.id})
+		p.publish(&event.PoolEvent{Type: event.ConnectionReady, ConnectionID: pc.id, Compressor: compressorName(c)})
 		p.Lock()
 		if atomic.LoadInt32(&p.connected) != connected {
 			p.Unlock()
 			p.sem.Release(1)
-			p.closeConnection(pc)
+			p.closeConnection(pc, event.ReasonPoolClosed)
 			return nil, nil, ErrPoolClosed
 		}
-		defer p.Unlock()
 		p.inflight[pc.id] = pc
+		p.Unlock()
+		p.publish(&event.PoolEvent{Type: event.GetSucceeded, ConnectionID: pc.id})
 		return &acquired{Connection: pc, sem: p.sem}, desc, nil
 	}
 }
 
-func (p *pool) closeConnection(pc *pooledConnection) error {
+// maintain populates the pool up to its minimum size right away and then every minSizeInterval,
+// until done is closed.
+func (p *pool) maintain(done chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-done
+		cancel()
+	}()
+
+	ticker := time.NewTicker(minSizeInterval)
+	defer ticker.Stop()
+	for {
+		p.populate(ctx)
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+	}
+}
+
+// populate creates idle connections until the pool has at least minSize open connections. It stops
+// at the first connection that cannot be created, which is retried at the next interval.
+func (p *pool) populate(ctx context.Context) {
+	for {
+		p.Lock()
+		open := uint64(len(p.inflight))
+		p.Unlock()
+		if open >= p.minSize || atomic.LoadInt32(&p.connected) != connected {
+			return
+		}
+
+		g := atomic.LoadUint64(&p.generation)
+		c, _, err := New(ctx, p.address, p.opts...)
+		if err != nil {
+			return
+		}
+		pc := &pooledConnection{
+			Connection: c,
+			p:          p,
+			generation: g,
+			id:         atomic.AddUint64(&p.nextid, 1),
+		}
+		p.publish(&event.PoolEvent{Type: event.ConnectionCreated, ConnectionID: pc.id})
+		p.publish(&event.PoolEvent{Type: event.ConnectionReady, ConnectionID: pc.id, Compressor: compressorName(c)})
+		p.Lock()
+		if atomic.LoadInt32(&p.connected) != connected {
+			p.Unlock()
+			_ = p.closeConnection(pc, event.ReasonPoolClosed)
+			return
+		}
+		p.inflight[pc.id] = pc
+		p.Unlock()
+
+		select {
+		case p.conns <- pc:
+		default:
+			_ = p.closeConnection(pc, event.ReasonPoolFull)
+			return
+		}
+	}
+}
+
+func (p *pool) closeConnection(pc *pooledConnection, reason string) error {
 	if !atomic.CompareAndSwapInt32(&pc.closed, 0, 1) {
 		return nil
 	}
 	p.Lock()
 	delete(p.inflight, pc.id)
 	p.Unlock()
+	p.publish(&event.PoolEvent{Type: event.ConnectionClosed, ConnectionID: pc.id, Reason: reason})
 	return pc.Connection.Close()
 }
 
 func (p *pool) returnConnection(pc *pooledConnection) error {
-	if atomic.LoadInt32(&p.connected) != connected || pc.Expired() {
-		return p.closeConnection(pc)
+	p.publish(&event.PoolEvent{Type: event.ConnectionReturned, ConnectionID: pc.id})
+
+	if atomic.LoadInt32(&p.connected) != connected {
+		return p.closeConnection(pc, event.ReasonPoolClosed)
+	}
+	if pc.Expired() {
+		return p.closeConnection(pc, p.expiredReason(pc))
 	}
 
 	select {
 	case p.conns <- pc:
 		return nil
 	default:
-		return p.closeConnection(pc)
+		return p.closeConnection(pc, event.ReasonPoolFull)
 	}
 }
 
@@ -229,6 +380,26 @@ func (p *pool) isExpired(generation uint64) bool {
 	return generation < atomic.LoadUint64(&p.generation)
 }
 
+// expiredReason returns the reason an expired connection is closed.
+func (p *pool) expiredReason(pc *pooledConnection) string {
+	switch {
+	case !pc.Connection.Alive():
+		return event.ReasonConnectionErrored
+	case p.isExpired(pc.generation):
+		return event.ReasonStale
+	default:
+		return event.ReasonIdle
+	}
+}
+
+// compressorName returns the name of the compressor negotiated for c, if any.
+func compressorName(c Connection) string {
+	if cc, ok := c.(interface{ Compressor() string }); ok {
+		return cc.Compressor()
+	}
+	return ""
+}
+
 type pooledConnection struct {
 	Connection
 	p          *pool
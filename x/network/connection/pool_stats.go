@@ -0,0 +1,166 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CheckOutLatencyBounds are the upper bounds of the buckets of the checkout latency histograms of
+// pools.
+var CheckOutLatencyBounds = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// LatencyHistogram is a histogram of durations with cumulative buckets. Counts[i] is the number of
+// durations that were at most Bounds[i], Count is the number of all durations, and Sum is their
+// total.
+type LatencyHistogram struct {
+	Bounds []time.Duration
+	Counts []uint64
+	Count  uint64
+	Sum    time.Duration
+}
+
+// PoolStats is a snapshot of the state of a connection pool.
+type PoolStats struct {
+	MaxSize   uint64 // The maximum number of connections of the pool.
+	Open      uint64 // The number of open connections, idle or in use.
+	InUse     uint64 // The number of connections checked out of the pool.
+	WaitQueue uint64 // The number of checkouts in progress.
+
+	// CheckOutLatency is the histogram of the time taken by successful checkouts, including the time
+	// taken to create a connection when there was no idle one.
+	CheckOutLatency LatencyHistogram
+
+	// Traffic counts the wire messages exchanged by the connections of the pool.
+	Traffic TrafficStats
+}
+
+// TrafficStats counts the wire messages sent and received and their total size in bytes, as
+// written to and read from the network, after compression.
+type TrafficStats struct {
+	BytesSent        uint64
+	BytesReceived    uint64
+	MessagesSent     uint64
+	MessagesReceived uint64
+}
+
+// trafficCounter counts the wire messages of the connections of a pool. Its fields are accessed
+// atomically.
+type trafficCounter struct {
+	bytesSent        uint64
+	bytesReceived    uint64
+	messagesSent     uint64
+	messagesReceived uint64
+}
+
+func (tc *trafficCounter) sent(n int) {
+	if tc == nil {
+		return
+	}
+	atomic.AddUint64(&tc.bytesSent, uint64(n))
+	atomic.AddUint64(&tc.messagesSent, 1)
+}
+
+func (tc *trafficCounter) received(n int) {
+	if tc == nil {
+		return
+	}
+	atomic.AddUint64(&tc.bytesReceived, uint64(n))
+	atomic.AddUint64(&tc.messagesReceived, 1)
+}
+
+func (tc *trafficCounter) snapshot() TrafficStats {
+	return TrafficStats{
+		BytesSent:        atomic.LoadUint64(&tc.bytesSent),
+		BytesReceived:    atomic.LoadUint64(&tc.bytesReceived),
+		MessagesSent:     atomic.LoadUint64(&tc.messagesSent),
+		MessagesReceived: atomic.LoadUint64(&tc.messagesReceived),
+	}
+}
+
+// latencyHistogram records durations into buckets with the upper bounds of CheckOutLatencyBounds.
+type latencyHistogram struct {
+	mu     sync.Mutex
+	counts []uint64 // counts[i] is the number of durations in (bounds[i-1], bounds[i]]
+	count  uint64
+	sum    time.Duration
+}
+
+func (h *latencyHistogram) record(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.counts == nil {
+		h.counts = make([]uint64, len(CheckOutLatencyBounds))
+	}
+	for i, bound := range CheckOutLatencyBounds {
+		if d <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += d
+}
+
+func (h *latencyHistogram) snapshot() LatencyHistogram {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	lh := LatencyHistogram{
+		Bounds: append([]time.Duration(nil), CheckOutLatencyBounds...),
+		Counts: make([]uint64, len(CheckOutLatencyBounds)),
+		Count:  h.count,
+		Sum:    h.sum,
+	}
+	var cumulative uint64
+	for i := range lh.Counts {
+		if h.counts != nil {
+			cumulative += h.counts[i]
+		}
+		lh.Counts[i] = cumulative
+	}
+	return lh
+}
+
+// Stats returns a snapshot of the state of the pool.
+func (p *pool) Stats() PoolStats {
+	p.Lock()
+	open := uint64(len(p.inflight))
+	p.Unlock()
+
+	inUse := open
+	if idle := uint64(len(p.conns)); idle < inUse {
+		inUse -= idle
+	} else {
+		inUse = 0
+	}
+
+	return PoolStats{
+		MaxSize:         p.capacity,
+		Open:            open,
+		InUse:           inUse,
+		WaitQueue:       uint64(atomic.LoadInt64(&p.waiting)),
+		CheckOutLatency: p.checkOutLatency.snapshot(),
+		Traffic:         p.traffic.snapshot(),
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"testing"
 	"time"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/x/network/address"
 )
 
@@ -44,6 +45,12 @@ func TestPool(t *testing.T) {
 				t.Errorf("Should receive error when size is larger than capacity. got %v; want %v", err, ErrSizeLargerThanCapacity)
 			}
 		})
+		t.Run("min size cannot be larger than size", func(t *testing.T) {
+			_, err := NewPool(address.Address(""), 2, 4, WithMinPoolSize(func(uint64) uint64 { return 3 }))
+			if err != ErrMinSizeLargerThanSize {
+				t.Errorf("Should receive error when min size is larger than size. got %v; want %v", err, ErrMinSizeLargerThanSize)
+			}
+		})
 	})
 	t.Run("Disconnect", func(t *testing.T) {
 		t.Run("cannot disconnect twice", func(t *testing.T) {
@@ -625,6 +632,56 @@ func TestPool(t *testing.T) {
 			}
 		})
 	})
+	t.Run("MinPoolSize", func(t *testing.T) {
+		t.Run("populates the pool when it connects", func(t *testing.T) {
+			cleanup := make(chan struct{})
+			defer close(cleanup)
+			addr := bootstrapConnections(t, 2, func(nc net.Conn) {
+				<-cleanup
+				nc.Close()
+			})
+			d := newdialer(&net.Dialer{})
+			P, err := NewPool(address.Address(addr.String()), 3, 4,
+				WithDialer(func(Dialer) Dialer { return d }),
+				WithMinPoolSize(func(uint64) uint64 { return 2 }),
+			)
+			noerr(t, err)
+			p := P.(*pool)
+			err = p.Connect(context.Background())
+			noerr(t, err)
+			deadline := time.Now().Add(5 * time.Second)
+			for len(p.conns) < 2 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			if len(p.conns) != 2 {
+				t.Errorf("Pool should hold min size idle connections. got %d; want %d", len(p.conns), 2)
+			}
+			c, desc, err := p.Get(context.Background())
+			noerr(t, err)
+			if desc != nil {
+				t.Errorf("Get should return an idle connection created by the pool.")
+			}
+			noerr(t, c.Close())
+			noerr(t, p.Disconnect(context.Background()))
+			if d.lenopened() != 2 || d.lenclosed() != 2 {
+				t.Errorf("Pool should open and close min size connections. opened %d, closed %d; want %d", d.lenopened(), d.lenclosed(), 2)
+			}
+		})
+		t.Run("stops populating the pool when it disconnects", func(t *testing.T) {
+			d := newdialer(&net.Dialer{})
+			P, err := NewPool(address.Address(""), 2, 2,
+				WithDialer(func(Dialer) Dialer { return d }),
+				WithMinPoolSize(func(uint64) uint64 { return 1 }),
+			)
+			noerr(t, err)
+			p := P.(*pool)
+			noerr(t, p.Connect(context.Background()))
+			noerr(t, p.Disconnect(context.Background()))
+			if p.done != nil {
+				t.Errorf("Disconnect should stop populating the pool.")
+			}
+		})
+	})
 	t.Run("Connection", func(t *testing.T) {
 		t.Run("Connection Close Does Not Error After Pool Is Disconnected", func(t *testing.T) {
 			cleanup := make(chan struct{})
@@ -677,3 +734,189 @@ func TestPool(t *testing.T) {
 		})
 	})
 }
+
+func TestPoolMonitor(t *testing.T) {
+	var lock sync.Mutex
+	var events []*event.PoolEvent
+	monitor := &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			lock.Lock()
+			events = append(events, evt)
+			lock.Unlock()
+		},
+	}
+	eventTypes := func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		types := make([]string, 0, len(events))
+		for _, evt := range events {
+			types = append(types, evt.Type)
+		}
+		events = nil
+		return types
+	}
+	assertTypes := func(t *testing.T, expected ...string) {
+		t.Helper()
+		got := eventTypes()
+		if len(got) != len(expected) {
+			t.Fatalf("event types do not match. got %v; want %v", got, expected)
+		}
+		for i := range got {
+			if got[i] != expected[i] {
+				t.Fatalf("event types do not match. got %v; want %v", got, expected)
+			}
+		}
+	}
+
+	cleanup := make(chan struct{})
+	defer close(cleanup)
+	addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+		<-cleanup
+		nc.Close()
+	})
+	p, err := NewPool(address.Address(addr.String()), 1, 1,
+		WithPoolMonitor(func(*event.PoolMonitor) *event.PoolMonitor { return monitor }))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertTypes(t, event.PoolCreated)
+
+	_, _, err = p.Get(context.Background())
+	if err != ErrPoolClosed {
+		t.Fatalf("Expected pool closed error. got %v; want %v", err, ErrPoolClosed)
+	}
+	assertTypes(t, event.GetStarted, event.GetFailed)
+
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	c, _, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertTypes(t, event.GetStarted, event.ConnectionCreated, event.ConnectionReady, event.GetSucceeded)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = p.Get(ctx)
+	if err == nil {
+		t.Fatal("Expected checkout to time out while the only connection is in use")
+	}
+	lock.Lock()
+	reason := events[len(events)-1].Reason
+	lock.Unlock()
+	if reason != event.ReasonTimedOut {
+		t.Errorf("Unexpected check out failed reason. got %s; want %s", reason, event.ReasonTimedOut)
+	}
+	assertTypes(t, event.GetStarted, event.GetFailed)
+
+	if err = c.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = p.Drain(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = p.Disconnect(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lock.Lock()
+	closeReason := events[2].Reason
+	lock.Unlock()
+	if closeReason != event.ReasonPoolClosed {
+		t.Errorf("Unexpected connection closed reason. got %s; want %s", closeReason, event.ReasonPoolClosed)
+	}
+	assertTypes(t, event.ConnectionReturned, event.PoolCleared, event.ConnectionClosed, event.PoolClosedEvent)
+}
+
+func TestPoolMonitorCallsPool(t *testing.T) {
+	cleanup := make(chan struct{})
+	defer close(cleanup)
+	addr := bootstrapConnections(t, 1, func(nc net.Conn) {
+		<-cleanup
+		nc.Close()
+	})
+
+	var p Pool
+	monitor := &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			if evt.Type != event.GetSucceeded {
+				return
+			}
+			// the pool must not hold its lock while publishing, or Disconnect deadlocks.
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			_ = p.Disconnect(ctx)
+		},
+	}
+	var err error
+	p, err = NewPool(address.Address(addr.String()), 1, 1,
+		WithPoolMonitor(func(*event.PoolMonitor) *event.PoolMonitor { return monitor }))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = p.Connect(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := p.Get(context.Background())
+		done <- err
+	}()
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Get deadlocked while publishing the check out succeeded event")
+	}
+}
+
+func TestPoolStats(t *testing.T) {
+	noerr := func(t *testing.T, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	cleanup := make(chan struct{})
+	defer close(cleanup)
+	addr := bootstrapConnections(t, 2, func(nc net.Conn) {
+		<-cleanup
+		nc.Close()
+	})
+
+	p, err := NewPool(address.Address(addr.String()), 2, 3)
+	noerr(t, err)
+	noerr(t, p.Connect(context.Background()))
+
+	stats := p.Stats()
+	if stats.MaxSize != 3 || stats.Open != 0 || stats.InUse != 0 || stats.WaitQueue != 0 {
+		t.Errorf("Unexpected stats of an empty pool: %+v", stats)
+	}
+	if len(stats.CheckOutLatency.Counts) != len(CheckOutLatencyBounds) || stats.CheckOutLatency.Count != 0 {
+		t.Errorf("Unexpected checkout latency of an empty pool: %+v", stats.CheckOutLatency)
+	}
+
+	c1, _, err := p.Get(context.Background())
+	noerr(t, err)
+	c2, _, err := p.Get(context.Background())
+	noerr(t, err)
+	noerr(t, c1.Close())
+
+	stats = p.Stats()
+	if stats.Open != 2 || stats.InUse != 1 {
+		t.Errorf("Unexpected connection counts. got open %d, in use %d; want 2, 1", stats.Open, stats.InUse)
+	}
+	latency := stats.CheckOutLatency
+	if latency.Count != 2 || latency.Counts[len(latency.Counts)-1] != 2 || latency.Sum <= 0 {
+		t.Errorf("Unexpected checkout latency: %+v", latency)
+	}
+	for i := 1; i < len(latency.Counts); i++ {
+		if latency.Counts[i] < latency.Counts[i-1] {
+			t.Errorf("Checkout latency counts are not cumulative: %v", latency.Counts)
+		}
+	}
+	noerr(t, c2.Close())
+}
@@ -0,0 +1,352 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+// ErrNoRecordedReply is returned when a connection dialed by a Replayer is read from without a
+// pending reply.
+var ErrNoRecordedReply = errors.New("no recorded reply to read")
+
+// Interaction is a request sent to a server and the reply the server sent to it, as complete wire
+// messages. Reply is empty for requests that do not get a reply, such as unacknowledged writes.
+type Interaction struct {
+	Command string `json:"command"`
+	Request []byte `json:"request"`
+	Reply   []byte `json:"reply,omitempty"`
+}
+
+// Cassette is a recording of the interactions between a client and a server.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadCassette reads a cassette saved by Cassette.Save from the file at path.
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cassette{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Save writes the cassette to the file at path.
+func (c *Cassette) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// Recorder is a Dialer that records the wire messages sent and received over the connections it
+// dials. The bytes are recorded as they are written to the network, so a recording client should
+// use neither TLS nor compression.
+type Recorder struct {
+	dialer Dialer
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a Recorder that dials connections with d. If d is nil, DefaultDialer is used.
+func NewRecorder(d Dialer) *Recorder {
+	if d == nil {
+		d = DefaultDialer
+	}
+	return &Recorder{dialer: d}
+}
+
+// DialContext implements the Dialer interface.
+func (r *Recorder) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	nc, err := r.dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingConn{Conn: nc, recorder: r, pending: make(map[int32]int)}, nil
+}
+
+// Cassette returns a copy of the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interactions := make([]Interaction, len(r.cassette.Interactions))
+	copy(interactions, r.cassette.Interactions)
+	return &Cassette{Interactions: interactions}
+}
+
+func (r *Recorder) addRequest(command string, request []byte) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{Command: command, Request: request})
+	return len(r.cassette.Interactions) - 1
+}
+
+func (r *Recorder) setReply(idx int, reply []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions[idx].Reply = reply
+}
+
+type recordingConn struct {
+	net.Conn
+	recorder *Recorder
+
+	mu      sync.Mutex
+	written []byte
+	read    []byte
+	pending map[int32]int // the index of the interaction of each request awaiting a reply
+}
+
+func (rc *recordingConn) Write(b []byte) (int, error) {
+	n, err := rc.Conn.Write(b)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	var msgs [][]byte
+	msgs, rc.written = splitMessages(append(rc.written, b[:n]...))
+	for _, msg := range msgs {
+		command, expectsReply := describeRequest(msg)
+		idx := rc.recorder.addRequest(command, msg)
+		if expectsReply {
+			rc.pending[requestID(msg)] = idx
+		}
+	}
+
+	return n, err
+}
+
+func (rc *recordingConn) Read(b []byte) (int, error) {
+	n, err := rc.Conn.Read(b)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	var msgs [][]byte
+	msgs, rc.read = splitMessages(append(rc.read, b[:n]...))
+	for _, msg := range msgs {
+		idx, ok := rc.pending[responseTo(msg)]
+		if !ok {
+			continue
+		}
+		delete(rc.pending, responseTo(msg))
+		rc.recorder.setReply(idx, msg)
+	}
+
+	return n, err
+}
+
+// Replayer is a Dialer whose connections reply to requests with the replies recorded in a cassette
+// instead of connecting to a server. A request is answered by the next interaction recorded for
+// the same command, and once those run out, by the last of them, so repeated commands such as
+// server heartbeats can be answered any number of times. A replaying client should use neither TLS
+// nor compression, and should not authenticate.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	byCommand    map[string][]int
+	next         map[string]int
+}
+
+// NewReplayer creates a Replayer that replays the interactions of c.
+func NewReplayer(c *Cassette) *Replayer {
+	r := &Replayer{
+		interactions: c.Interactions,
+		byCommand:    make(map[string][]int),
+		next:         make(map[string]int),
+	}
+	for i, interaction := range c.Interactions {
+		r.byCommand[interaction.Command] = append(r.byCommand[interaction.Command], i)
+	}
+	return r
+}
+
+// DialContext implements the Dialer interface.
+func (r *Replayer) DialContext(_ context.Context, network, address string) (net.Conn, error) {
+	return &replayConn{replayer: r, addr: replayAddr{network: network, address: address}}, nil
+}
+
+// reply returns the recorded reply to request, with its header updated to respond to request.
+func (r *Replayer) reply(request []byte) ([]byte, error) {
+	command, _ := describeRequest(request)
+
+	r.mu.Lock()
+	ids := r.byCommand[command]
+	if len(ids) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("no recorded interaction for command %s", command)
+	}
+	i := r.next[command]
+	if i < len(ids)-1 {
+		r.next[command]++
+	} else {
+		i = len(ids) - 1
+	}
+	recorded := r.interactions[ids[i]].Reply
+	r.mu.Unlock()
+
+	if len(recorded) == 0 {
+		return nil, nil
+	}
+	reply := make([]byte, len(recorded))
+	copy(reply, recorded)
+	binary.LittleEndian.PutUint32(reply[4:8], uint32(wiremessage.NextRequestID()))
+	binary.LittleEndian.PutUint32(reply[8:12], uint32(requestID(request)))
+	return reply, nil
+}
+
+type replayConn struct {
+	replayer *Replayer
+	addr     replayAddr
+
+	mu      sync.Mutex
+	written []byte
+	replies []byte
+	closed  bool
+}
+
+func (rc *replayConn) Write(b []byte) (int, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	var msgs [][]byte
+	msgs, rc.written = splitMessages(append(rc.written, b...))
+	for _, msg := range msgs {
+		reply, err := rc.replayer.reply(msg)
+		if err != nil {
+			return 0, err
+		}
+		rc.replies = append(rc.replies, reply...)
+	}
+
+	return len(b), nil
+}
+
+func (rc *replayConn) Read(b []byte) (int, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.closed {
+		return 0, io.EOF
+	}
+	if len(rc.replies) == 0 {
+		return 0, ErrNoRecordedReply
+	}
+	n := copy(b, rc.replies)
+	rc.replies = rc.replies[n:]
+	return n, nil
+}
+
+func (rc *replayConn) Close() error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.closed = true
+	return nil
+}
+
+func (rc *replayConn) LocalAddr() net.Addr              { return rc.addr }
+func (rc *replayConn) RemoteAddr() net.Addr             { return rc.addr }
+func (rc *replayConn) SetDeadline(time.Time) error      { return nil }
+func (rc *replayConn) SetReadDeadline(time.Time) error  { return nil }
+func (rc *replayConn) SetWriteDeadline(time.Time) error { return nil }
+
+type replayAddr struct {
+	network string
+	address string
+}
+
+func (ra replayAddr) Network() string { return ra.network }
+func (ra replayAddr) String() string  { return ra.address }
+
+// splitMessages splits the complete wire messages off the front of b, returning them and the bytes
+// of any incomplete message that remain.
+func splitMessages(b []byte) ([][]byte, []byte) {
+	var msgs [][]byte
+	for len(b) >= 4 {
+		size := int(int32(binary.LittleEndian.Uint32(b)))
+		if size < 16 || size > len(b) {
+			break
+		}
+		msg := make([]byte, size)
+		copy(msg, b[:size])
+		msgs = append(msgs, msg)
+		b = b[size:]
+	}
+	return msgs, b
+}
+
+func requestID(msg []byte) int32 {
+	return int32(binary.LittleEndian.Uint32(msg[4:8]))
+}
+
+func responseTo(msg []byte) int32 {
+	return int32(binary.LittleEndian.Uint32(msg[8:12]))
+}
+
+// describeRequest returns the name of the command sent by the request msg, or the name of its
+// opcode if it is not a command, and whether the server replies to it.
+func describeRequest(msg []byte) (string, bool) {
+	header, err := wiremessage.ReadHeader(msg, 0)
+	if err != nil {
+		return "", false
+	}
+
+	switch header.OpCode {
+	case wiremessage.OpMsg:
+		var m wiremessage.Msg
+		if err := m.UnmarshalWireMessage(msg); err != nil {
+			break
+		}
+		for _, section := range m.Sections {
+			if body, ok := section.(wiremessage.SectionBody); ok {
+				if elem, err := body.Document.IndexErr(0); err == nil {
+					return elem.Key(), m.AcknowledgedWrite()
+				}
+			}
+		}
+		return header.OpCode.String(), m.AcknowledgedWrite()
+	case wiremessage.OpQuery:
+		var q wiremessage.Query
+		if err := q.UnmarshalWireMessage(msg); err != nil {
+			break
+		}
+		if cmd, err := q.CommandDocument(); err == nil && len(cmd) > 0 {
+			return cmd[0].Key, true
+		}
+		return header.OpCode.String(), true
+	case wiremessage.OpGetMore:
+		return header.OpCode.String(), true
+	}
+	return header.OpCode.String(), false
+}
@@ -0,0 +1,143 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/x/bsonx"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	msg := func(t *testing.T, requestID, responseTo int32, flags wiremessage.MsgFlag, doc bsonx.Doc) []byte {
+		raw, err := doc.MarshalBSON()
+		require.NoError(t, err)
+		b, err := wiremessage.Msg{
+			MsgHeader: wiremessage.Header{RequestID: requestID, ResponseTo: responseTo},
+			FlagBits:  flags,
+			Sections:  []wiremessage.Section{wiremessage.SectionBody{Document: raw}},
+		}.MarshalWireMessage()
+		require.NoError(t, err)
+		return b
+	}
+	replyDoc := func(n int32) bsonx.Doc { return bsonx.Doc{{"ok", bsonx.Int32(1)}, {"n", bsonx.Int32(n)}} }
+
+	// the server replies to every acknowledged request with the number of requests it has received
+	server := DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			for n := int32(1); ; n++ {
+				header := make([]byte, 16)
+				if _, err := io.ReadFull(server, header); err != nil {
+					return
+				}
+				h, err := wiremessage.ReadHeader(header, 0)
+				if err != nil {
+					return
+				}
+				body := make([]byte, h.MessageLength-16)
+				if _, err := io.ReadFull(server, body); err != nil {
+					return
+				}
+				var m wiremessage.Msg
+				if err := m.UnmarshalWireMessage(append(header, body...)); err != nil || !m.AcknowledgedWrite() {
+					continue
+				}
+				if _, err := server.Write(msg(t, 100+n, h.RequestID, 0, replyDoc(n))); err != nil {
+					return
+				}
+			}
+		}()
+		return client, nil
+	})
+	roundTrip := func(t *testing.T, nc net.Conn, requestID int32, flags wiremessage.MsgFlag, cmd bsonx.Doc) []byte {
+		_, err := nc.Write(msg(t, requestID, 0, flags, cmd))
+		require.NoError(t, err)
+		if flags&wiremessage.MoreToCome != 0 {
+			return nil
+		}
+
+		header := make([]byte, 16)
+		_, err = io.ReadFull(nc, header)
+		require.NoError(t, err)
+		h, err := wiremessage.ReadHeader(header, 0)
+		require.NoError(t, err)
+		require.Equal(t, requestID, h.ResponseTo)
+		body := make([]byte, h.MessageLength-16)
+		_, err = io.ReadFull(nc, body)
+		require.NoError(t, err)
+		return append(header, body...)
+	}
+	replyN := func(t *testing.T, reply []byte) int32 {
+		var m wiremessage.Msg
+		require.NoError(t, m.UnmarshalWireMessage(reply))
+		return m.Sections[0].(wiremessage.SectionBody).Document.Lookup("n").Int32()
+	}
+
+	recorder := NewRecorder(server)
+	nc, err := recorder.DialContext(context.Background(), "tcp", "localhost:27017")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), replyN(t, roundTrip(t, nc, 1, 0, bsonx.Doc{{"isMaster", bsonx.Int32(1)}})))
+	require.Equal(t, int32(2), replyN(t, roundTrip(t, nc, 2, 0, bsonx.Doc{{"find", bsonx.String("coll")}})))
+	roundTrip(t, nc, 3, wiremessage.MoreToCome, bsonx.Doc{{"insert", bsonx.String("coll")}})
+	require.Equal(t, int32(4), replyN(t, roundTrip(t, nc, 4, 0, bsonx.Doc{{"find", bsonx.String("coll")}})))
+	require.NoError(t, nc.Close())
+
+	cassette := recorder.Cassette()
+	require.Len(t, cassette.Interactions, 4)
+	for i, command := range []string{"isMaster", "find", "insert", "find"} {
+		require.Equal(t, command, cassette.Interactions[i].Command)
+	}
+	require.Empty(t, cassette.Interactions[2].Reply)
+
+	dir, err := ioutil.TempDir("", "cassette")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+	require.NoError(t, cassette.Save(path))
+	cassette, err = LoadCassette(path)
+	require.NoError(t, err)
+
+	replayer := NewReplayer(cassette)
+	nc, err = replayer.DialContext(context.Background(), "tcp", "localhost:27017")
+	require.NoError(t, err)
+	require.Equal(t, "localhost:27017", nc.RemoteAddr().String())
+
+	t.Run("replies in recorded order", func(t *testing.T) {
+		require.Equal(t, int32(2), replyN(t, roundTrip(t, nc, 10, 0, bsonx.Doc{{"find", bsonx.String("coll")}})))
+		require.Equal(t, int32(4), replyN(t, roundTrip(t, nc, 11, 0, bsonx.Doc{{"find", bsonx.String("coll")}})))
+	})
+	t.Run("repeats the last reply", func(t *testing.T) {
+		for i := int32(20); i < 23; i++ {
+			require.Equal(t, int32(1), replyN(t, roundTrip(t, nc, i, 0, bsonx.Doc{{"isMaster", bsonx.Int32(1)}})))
+		}
+		require.Equal(t, int32(4), replyN(t, roundTrip(t, nc, 23, 0, bsonx.Doc{{"find", bsonx.String("coll")}})))
+	})
+	t.Run("request without reply", func(t *testing.T) {
+		roundTrip(t, nc, 30, wiremessage.MoreToCome, bsonx.Doc{{"insert", bsonx.String("coll")}})
+		_, err := nc.Read(make([]byte, 16))
+		require.Equal(t, ErrNoRecordedReply, err)
+	})
+	t.Run("unrecorded command", func(t *testing.T) {
+		_, err := nc.Write(msg(t, 40, 0, 0, bsonx.Doc{{"delete", bsonx.String("coll")}}))
+		require.Error(t, err)
+	})
+	t.Run("closed", func(t *testing.T) {
+		require.NoError(t, nc.Close())
+		_, err := nc.Read(make([]byte, 16))
+		require.Equal(t, io.EOF, err)
+	})
+}
@@ -0,0 +1,179 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// HostResolver resolves host names to addresses. *net.Resolver implements HostResolver.
+type HostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// resolvingDialer resolves host names with a HostResolver and dials the resulting addresses in
+// order until one succeeds.
+type resolvingDialer struct {
+	dialer   Dialer
+	resolver HostResolver
+}
+
+func (rd *resolvingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return rd.dial(ctx, network, address, nil)
+}
+
+// dial works like DialContext and reports the DNS resolution and TCP connect stages to sr.
+func (rd *resolvingDialer) dial(ctx context.Context, network, address string, sr *stageReporter) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if network == "unix" || err != nil || net.ParseIP(host) != nil {
+		// Unix domain sockets and IP addresses do not need to be resolved.
+		finish := sr.start(ctx, event.StageTCPConnect)
+		conn, err := rd.dialer.DialContext(ctx, network, address)
+		finish(err)
+		return conn, err
+	}
+
+	finish := sr.start(ctx, event.StageDNSResolution)
+	addrs, err := rd.resolver.LookupHost(ctx, host)
+	if err == nil && len(addrs) == 0 {
+		err = fmt.Errorf("no addresses found for host %s", host)
+	}
+	finish(err)
+	if err != nil {
+		return nil, err
+	}
+
+	finish = sr.start(ctx, event.StageTCPConnect)
+	defer func() { finish(err) }()
+	conn, err := rd.dialAddrs(ctx, network, port, addrs)
+	if err == nil || ctx.Err() != nil {
+		return conn, err
+	}
+
+	// The host may have moved, such as after a CNAME flip, so a cached resolution is refreshed and
+	// any new addresses are dialed.
+	cache, ok := rd.resolver.(hostInvalidator)
+	if !ok {
+		return nil, err
+	}
+	cache.Invalidate(host)
+	fresh, lookupErr := rd.resolver.LookupHost(ctx, host)
+	if lookupErr != nil {
+		return nil, err
+	}
+	fresh = newAddrs(fresh, addrs)
+	if len(fresh) == 0 {
+		return nil, err
+	}
+	conn, err = rd.dialAddrs(ctx, network, port, fresh)
+	return conn, err
+}
+
+// dialAddrs dials the given addresses in order until one succeeds.
+func (rd *resolvingDialer) dialAddrs(ctx context.Context, network, port string, addrs []string) (net.Conn, error) {
+	var err error
+	for _, addr := range addrs {
+		var conn net.Conn
+		conn, err = rd.dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+		if err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, err
+}
+
+// newAddrs returns the addresses in addrs that are not in old.
+func newAddrs(addrs, old []string) []string {
+	var added []string
+	for _, addr := range addrs {
+		found := false
+		for _, o := range old {
+			if addr == o {
+				found = true
+				break
+			}
+		}
+		if !found {
+			added = append(added, addr)
+		}
+	}
+	return added
+}
+
+// hostInvalidator is implemented by HostResolvers that cache addresses, such as *HostCache, so that
+// a host whose cached addresses cannot be dialed is resolved again.
+type hostInvalidator interface {
+	Invalidate(host string)
+}
+
+// HostCache is a HostResolver that caches the addresses of host names for a fixed time to live, so
+// that connections to a host do not wait for a DNS lookup on every dial. The Go resolver does not
+// report the TTLs of DNS records, so the time to live should not exceed the TTLs of the records
+// being cached. When none of the cached addresses of a host can be dialed, the host is resolved
+// again. A HostCache is safe for concurrent use and is shared by the connections to all servers
+// of a topology.
+type HostCache struct {
+	resolver HostResolver
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]hostCacheEntry
+}
+
+type hostCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// NewHostCache creates a HostCache that resolves host names with resolver and caches their
+// addresses for ttl.
+func NewHostCache(resolver HostResolver, ttl time.Duration) *HostCache {
+	return &HostCache{
+		resolver: resolver,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[string]hostCacheEntry),
+	}
+}
+
+// LookupHost implements the HostResolver interface. Cached addresses are returned until they
+// expire; failed lookups are not cached.
+func (hc *HostCache) LookupHost(ctx context.Context, host string) ([]string, error) {
+	hc.mu.Lock()
+	entry, ok := hc.entries[host]
+	hc.mu.Unlock()
+	if ok && hc.now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := hc.resolver.LookupHost(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return addrs, err
+	}
+
+	hc.mu.Lock()
+	hc.entries[host] = hostCacheEntry{addrs: addrs, expires: hc.now().Add(hc.ttl)}
+	hc.mu.Unlock()
+	return addrs, nil
+}
+
+// Invalidate removes the cached addresses of host, so the next lookup resolves it again.
+func (hc *HostCache) Invalidate(host string) {
+	hc.mu.Lock()
+	delete(hc.entries, host)
+	hc.mu.Unlock()
+}
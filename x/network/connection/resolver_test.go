@@ -0,0 +1,160 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/x/network/address"
+)
+
+type hostResolverFunc func(ctx context.Context, host string) ([]string, error)
+
+func (hrf hostResolverFunc) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return hrf(ctx, host)
+}
+
+func TestResolvingDialer(t *testing.T) {
+	errDial := errors.New("dial failed")
+	hosts := map[string][]string{"db.example.com": {"10.0.0.1", "10.0.0.2"}}
+	resolver := hostResolverFunc(func(_ context.Context, host string) ([]string, error) {
+		addrs, ok := hosts[host]
+		if !ok {
+			return nil, &net.DNSError{Err: "no such host", Name: host}
+		}
+		return addrs, nil
+	})
+
+	testCases := []struct {
+		name     string
+		address  string
+		failing  map[string]bool
+		expected []string
+		err      bool
+	}{
+		{"resolves host", "db.example.com:27017", nil, []string{"10.0.0.1:27017"}, false},
+		{"falls back to next address", "db.example.com:27017", map[string]bool{"10.0.0.1:27017": true},
+			[]string{"10.0.0.1:27017", "10.0.0.2:27017"}, false},
+		{"all addresses fail", "db.example.com:27017",
+			map[string]bool{"10.0.0.1:27017": true, "10.0.0.2:27017": true},
+			[]string{"10.0.0.1:27017", "10.0.0.2:27017"}, true},
+		{"unknown host", "other.example.com:27017", nil, nil, true},
+		{"IP address", "127.0.0.1:27017", nil, []string{"127.0.0.1:27017"}, false},
+		{"unix socket", "/tmp/mongodb-27017.sock", nil, []string{"/tmp/mongodb-27017.sock"}, false},
+		{"unix socket with colon", "/tmp/db.example.com:27017.sock", nil, []string{"/tmp/db.example.com:27017.sock"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var dialed []string
+			dialer := DialerFunc(func(_ context.Context, _, address string) (net.Conn, error) {
+				dialed = append(dialed, address)
+				if tc.failing[address] {
+					return nil, errDial
+				}
+				client, server := net.Pipe()
+				_ = server.Close()
+				return client, nil
+			})
+
+			cfg, err := newConfig(
+				WithDialer(func(Dialer) Dialer { return dialer }),
+				WithHostResolver(func(HostResolver) HostResolver { return resolver }),
+			)
+			require.NoError(t, err)
+
+			conn, err := cfg.dialer.DialContext(context.Background(), address.Address(tc.address).Network(), tc.address)
+			require.Equal(t, tc.expected, dialed)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			_ = conn.Close()
+		})
+	}
+}
+
+func TestHostCache(t *testing.T) {
+	var lookups int
+	addrs := []string{"10.0.0.1"}
+	resolver := hostResolverFunc(func(_ context.Context, host string) ([]string, error) {
+		lookups++
+		if host != "db.example.com" {
+			return nil, &net.DNSError{Err: "no such host", Name: host}
+		}
+		return addrs, nil
+	})
+	now := time.Now()
+	cache := NewHostCache(resolver, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	lookup := func() []string {
+		got, err := cache.LookupHost(context.Background(), "db.example.com")
+		require.NoError(t, err)
+		return got
+	}
+
+	require.Equal(t, []string{"10.0.0.1"}, lookup())
+	addrs = []string{"10.0.0.2"}
+	require.Equal(t, []string{"10.0.0.1"}, lookup(), "expected the cached addresses before the TTL expires")
+	require.Equal(t, 1, lookups)
+
+	now = now.Add(time.Minute)
+	require.Equal(t, []string{"10.0.0.2"}, lookup(), "expected the host to be resolved again after the TTL expires")
+	require.Equal(t, 2, lookups)
+
+	addrs = []string{"10.0.0.3"}
+	cache.Invalidate("db.example.com")
+	require.Equal(t, []string{"10.0.0.3"}, lookup(), "expected the host to be resolved again after it was invalidated")
+
+	_, err := cache.LookupHost(context.Background(), "other.example.com")
+	require.Error(t, err)
+	_, err = cache.LookupHost(context.Background(), "other.example.com")
+	require.Error(t, err)
+	require.Equal(t, 5, lookups, "expected failed lookups not to be cached")
+
+	t.Run("dial failures resolve the host again", func(t *testing.T) {
+		errDial := errors.New("dial failed")
+		addrs = []string{"10.0.0.1"}
+		cache := NewHostCache(resolver, time.Hour)
+		_, err := cache.LookupHost(context.Background(), "db.example.com")
+		require.NoError(t, err)
+
+		// the host moved to a new address after it was cached
+		addrs = []string{"10.0.0.1", "10.0.0.2"}
+		var dialed []string
+		dialer := DialerFunc(func(_ context.Context, _, address string) (net.Conn, error) {
+			dialed = append(dialed, address)
+			if address == "10.0.0.1:27017" {
+				return nil, errDial
+			}
+			client, server := net.Pipe()
+			_ = server.Close()
+			return client, nil
+		})
+		cfg, err := newConfig(
+			WithDialer(func(Dialer) Dialer { return dialer }),
+			WithHostResolver(func(HostResolver) HostResolver { return cache }),
+		)
+		require.NoError(t, err)
+
+		conn, err := cfg.dialer.DialContext(context.Background(), "tcp", "db.example.com:27017")
+		require.NoError(t, err)
+		_ = conn.Close()
+		require.Equal(t, []string{"10.0.0.1:27017", "10.0.0.2:27017"}, dialed)
+
+		got, err := cache.LookupHost(context.Background(), "db.example.com")
+		require.NoError(t, err)
+		require.Equal(t, addrs, got, "expected the refreshed addresses to be cached")
+	})
+}
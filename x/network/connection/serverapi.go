@@ -0,0 +1,112 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+// ServerAPI is the server API version declared on the commands sent over a connection.
+type ServerAPI struct {
+	Version           string
+	Strict            *bool
+	DeprecationErrors *bool
+}
+
+// appendFields appends the apiVersion, apiStrict, and apiDeprecationErrors fields to dst.
+func (sa *ServerAPI) appendFields(dst []byte) []byte {
+	dst = bsoncore.AppendStringElement(dst, "apiVersion", sa.Version)
+	if sa.Strict != nil {
+		dst = bsoncore.AppendBooleanElement(dst, "apiStrict", *sa.Strict)
+	}
+	if sa.DeprecationErrors != nil {
+		dst = bsoncore.AppendBooleanElement(dst, "apiDeprecationErrors", *sa.DeprecationErrors)
+	}
+	return dst
+}
+
+// addServerAPI returns wm with the server API fields of api added to its command document. OP_QUERY
+// messages that are not commands, and commands that already declare an API version, are returned
+// unchanged.
+func addServerAPI(api *ServerAPI, wm wiremessage.WireMessage) (wiremessage.WireMessage, error) {
+	switch msg := wm.(type) {
+	case wiremessage.Msg:
+		sections := make([]wiremessage.Section, len(msg.Sections))
+		copy(sections, msg.Sections)
+		for i, section := range sections {
+			body, ok := section.(wiremessage.SectionBody)
+			if !ok {
+				continue
+			}
+			doc, err := api.addToCommand(bsoncore.Document(body.Document))
+			if err != nil {
+				return nil, err
+			}
+			sections[i] = wiremessage.SectionBody{PayloadType: body.PayloadType, Document: bson.Raw(doc)}
+		}
+		msg.Sections = sections
+		msg.FlagBits &^= wiremessage.ChecksumPresent
+		msg.MsgHeader.MessageLength = int32(msg.Len())
+		return msg, nil
+	case wiremessage.Query:
+		if !strings.HasSuffix(msg.FullCollectionName, ".$cmd") {
+			return wm, nil
+		}
+		query, err := api.addToQuery(bsoncore.Document(msg.Query))
+		if err != nil {
+			return nil, err
+		}
+		msg.Query = bson.Raw(query)
+		msg.MsgHeader.MessageLength = int32(msg.Len())
+		return msg, nil
+	}
+	return wm, nil
+}
+
+// addToQuery adds the server API fields to the command of an OP_QUERY, which is wrapped in a
+// $query document when a read preference is sent to a mongos.
+func (sa *ServerAPI) addToQuery(query bsoncore.Document) (bsoncore.Document, error) {
+	elems, err := query.Elements()
+	if err != nil {
+		return nil, err
+	}
+	if len(elems) == 0 || elems[0].Key() != "$query" {
+		return sa.addToCommand(query)
+	}
+
+	cmd, ok := elems[0].Value().DocumentOK()
+	if !ok {
+		return sa.addToCommand(query)
+	}
+	cmd, err = sa.addToCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	idx, wrapped := bsoncore.AppendDocumentStart(nil)
+	wrapped = bsoncore.AppendDocumentElement(wrapped, "$query", cmd)
+	for _, elem := range elems[1:] {
+		wrapped = append(wrapped, elem...)
+	}
+	return bsoncore.AppendDocumentEnd(wrapped, idx)
+}
+
+// addToCommand returns cmd with the server API fields appended, or cmd itself if it already
+// declares an API version.
+func (sa *ServerAPI) addToCommand(cmd bsoncore.Document) (bsoncore.Document, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := cmd.LookupErr("apiVersion"); err == nil {
+		return cmd, nil
+	}
+	return bsoncore.BuildDocument(nil, sa.appendFields(append([]byte(nil), cmd[4:len(cmd)-1]...))), nil
+}
@@ -0,0 +1,72 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+func TestServerAPI(t *testing.T) {
+	strict := true
+	api := &ServerAPI{Version: "1", Strict: &strict}
+	apiFields := []byte(nil)
+	apiFields = bsoncore.AppendStringElement(apiFields, "apiVersion", "1")
+	apiFields = bsoncore.AppendBooleanElement(apiFields, "apiStrict", true)
+	ping := bsoncore.AppendInt32Element(nil, "ping", 1)
+
+	t.Run("OP_MSG", func(t *testing.T) {
+		body := bsoncore.BuildDocumentFromElements(nil, ping)
+		msg := wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: bson.Raw(body)}}}
+
+		wm, err := addServerAPI(api, msg)
+		require.NoError(t, err)
+
+		got := wm.(wiremessage.Msg)
+		want := bsoncore.BuildDocumentFromElements(nil, ping, apiFields)
+		require.Equal(t, bson.Raw(want), got.Sections[0].(wiremessage.SectionBody).Document)
+		require.Equal(t, int32(got.Len()), got.MsgHeader.MessageLength)
+	})
+	t.Run("OP_QUERY command", func(t *testing.T) {
+		query := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "isMaster", 1))
+		wm, err := addServerAPI(api, wiremessage.Query{FullCollectionName: "admin.$cmd", Query: bson.Raw(query)})
+		require.NoError(t, err)
+
+		want := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendInt32Element(nil, "isMaster", 1), apiFields)
+		require.Equal(t, bson.Raw(want), wm.(wiremessage.Query).Query)
+	})
+	t.Run("OP_QUERY wrapped command", func(t *testing.T) {
+		rp := bsoncore.AppendDocumentElement(nil, "$readPreference",
+			bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendStringElement(nil, "mode", "secondary")))
+		query := bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendDocumentElement(nil, "$query", bsoncore.BuildDocumentFromElements(nil, ping)), rp)
+		wm, err := addServerAPI(api, wiremessage.Query{FullCollectionName: "admin.$cmd", Query: bson.Raw(query)})
+		require.NoError(t, err)
+
+		want := bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendDocumentElement(nil, "$query", bsoncore.BuildDocumentFromElements(nil, ping, apiFields)), rp)
+		require.Equal(t, bson.Raw(want), wm.(wiremessage.Query).Query)
+	})
+	t.Run("OP_QUERY find is unchanged", func(t *testing.T) {
+		query := wiremessage.Query{FullCollectionName: "db.coll", Query: bson.Raw(bsoncore.BuildDocumentFromElements(nil))}
+		wm, err := addServerAPI(api, query)
+		require.NoError(t, err)
+		require.Equal(t, query, wm)
+	})
+	t.Run("declared version is kept", func(t *testing.T) {
+		body := bsoncore.BuildDocumentFromElements(nil, ping, bsoncore.AppendStringElement(nil, "apiVersion", "2"))
+		msg := wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: bson.Raw(body)}}}
+
+		wm, err := addServerAPI(api, msg)
+		require.NoError(t, err)
+		require.Equal(t, bson.Raw(body), wm.(wiremessage.Msg).Sections[0].(wiremessage.SectionBody).Document)
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+type stageReporterKey struct{}
+
+// stageReporter reports the stages of establishing one connection to a ConnectionMonitor. A nil
+// *stageReporter reports nothing.
+type stageReporter struct {
+	monitor *event.ConnectionMonitor
+	addr    string
+	id      string
+}
+
+func newStageReporter(monitor *event.ConnectionMonitor, addr, id string) *stageReporter {
+	if monitor == nil {
+		return nil
+	}
+	return &stageReporter{monitor: monitor, addr: addr, id: id}
+}
+
+// start reports that stage started and returns a function that reports that it finished with the
+// given error.
+func (sr *stageReporter) start(ctx context.Context, stage string) func(error) {
+	if sr == nil {
+		return func(error) {}
+	}
+
+	if sr.monitor.StageStarted != nil {
+		sr.monitor.StageStarted(ctx, &event.ConnectionStageStartedEvent{
+			Stage:         stage,
+			ServerAddress: sr.addr,
+			ConnectionID:  sr.id,
+		})
+	}
+	started := time.Now()
+	return func(err error) {
+		if sr.monitor.StageFinished == nil {
+			return
+		}
+		sr.monitor.StageFinished(ctx, &event.ConnectionStageFinishedEvent{
+			Stage:         stage,
+			ServerAddress: sr.addr,
+			ConnectionID:  sr.id,
+			DurationNanos: time.Since(started).Nanoseconds(),
+			Failure:       err,
+		})
+	}
+}
+
+// StartStage reports that a stage of establishing a connection started if ctx is the context a
+// Handshaker is called with by New. It returns a function that must be called with the error of
+// the stage when it finishes. Handshakers use it to report the MongoDB handshake and
+// authentication stages.
+func StartStage(ctx context.Context, stage string) func(error) {
+	sr, _ := ctx.Value(stageReporterKey{}).(*stageReporter)
+	return sr.start(ctx, stage)
+}
@@ -0,0 +1,76 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/x/network/address"
+	"go.mongodb.org/mongo-driver/x/network/description"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+func TestConnectionStages(t *testing.T) {
+	errAuth := errors.New("auth failed")
+	dialer := DialerFunc(func(context.Context, string, string) (net.Conn, error) {
+		client, server := net.Pipe()
+		_ = server.Close()
+		return client, nil
+	})
+	resolver := hostResolverFunc(func(context.Context, string) ([]string, error) {
+		return []string{"10.0.0.1"}, nil
+	})
+	handshaker := HandshakerFunc(func(ctx context.Context, addr address.Address, _ wiremessage.ReadWriter) (description.Server, error) {
+		StartStage(ctx, event.StageHandshake)(nil)
+		StartStage(ctx, event.StageAuthentication)(errAuth)
+		return description.Server{}, errAuth
+	})
+
+	var started, finished []string
+	var failure error
+	var ids []string
+	monitor := &event.ConnectionMonitor{
+		StageStarted: func(_ context.Context, evt *event.ConnectionStageStartedEvent) {
+			started = append(started, evt.Stage)
+			ids = append(ids, evt.ConnectionID)
+		},
+		StageFinished: func(_ context.Context, evt *event.ConnectionStageFinishedEvent) {
+			finished = append(finished, evt.Stage)
+			if evt.Failure != nil {
+				failure = evt.Failure
+			}
+			require.Equal(t, "db.example.com:27017", evt.ServerAddress)
+		},
+	}
+
+	_, _, err := New(context.Background(), address.Address("db.example.com:27017"),
+		WithDialer(func(Dialer) Dialer { return dialer }),
+		WithHostResolver(func(HostResolver) HostResolver { return resolver }),
+		WithHandshaker(func(Handshaker) Handshaker { return handshaker }),
+		WithConnectionMonitor(func(*event.ConnectionMonitor) *event.ConnectionMonitor { return monitor }),
+	)
+	require.Equal(t, errAuth, err)
+
+	want := []string{event.StageDNSResolution, event.StageTCPConnect, event.StageHandshake, event.StageAuthentication}
+	require.Equal(t, want, started)
+	require.Equal(t, want, finished)
+	require.Equal(t, errAuth, failure)
+	for _, id := range ids {
+		require.Equal(t, ids[0], id)
+	}
+
+	t.Run("outside of New", func(t *testing.T) {
+		started = nil
+		StartStage(context.Background(), event.StageHandshake)(nil)
+		require.Empty(t, started)
+	})
+}
@@ -0,0 +1,90 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+type operationTimeoutKey struct{}
+
+// WithOperationTimeout returns a copy of ctx that limits the time an operation may take. If ctx
+// does not have a deadline, the returned context expires after timeout. Commands written with the
+// returned context, other than getMore, are sent with maxTimeMS set to the time remaining before
+// its deadline so the server stops working on them once the operation has timed out.
+func WithOperationTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx = context.WithValue(ctx, operationTimeoutKey{}, true)
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// HasOperationTimeout returns true if ctx was returned by WithOperationTimeout or derived from such
+// a context.
+func HasOperationTimeout(ctx context.Context) bool {
+	set, _ := ctx.Value(operationTimeoutKey{}).(bool)
+	return set
+}
+
+// addMaxTime returns wm with maxTimeMS set to the time remaining before the deadline of ctx, less
+// minRTT, the minimum round trip time to the server, if ctx has an operation timeout. OP_QUERY
+// messages, getMore commands, and commands that already set maxTimeMS are returned unchanged. An
+// error wrapping context.DeadlineExceeded is returned if not enough time remains for a round trip.
+func addMaxTime(ctx context.Context, wm wiremessage.WireMessage, minRTT time.Duration) (wiremessage.WireMessage, error) {
+	if !HasOperationTimeout(ctx) {
+		return wm, nil
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return wm, nil
+	}
+	msg, ok := wm.(wiremessage.Msg)
+	if !ok || len(msg.Sections) == 0 {
+		return wm, nil
+	}
+	body, ok := msg.Sections[0].(wiremessage.SectionBody)
+	if !ok {
+		return wm, nil
+	}
+
+	cmd := bsoncore.Document(body.Document)
+	elems, err := cmd.Elements()
+	if err != nil {
+		return nil, err
+	}
+	if len(elems) == 0 || elems[0].Key() == "getMore" {
+		return wm, nil
+	}
+	if _, err = cmd.LookupErr("maxTimeMS"); err == nil {
+		return wm, nil
+	}
+
+	remaining := int64((deadline.Sub(time.Now()) - minRTT) / time.Millisecond)
+	if remaining <= 0 {
+		return nil, context.DeadlineExceeded
+	}
+
+	elemBytes := append([]byte(nil), cmd[4:len(cmd)-1]...)
+	elemBytes = bsoncore.AppendInt64Element(elemBytes, "maxTimeMS", remaining)
+
+	sections := make([]wiremessage.Section, len(msg.Sections))
+	copy(sections, msg.Sections)
+	sections[0] = wiremessage.SectionBody{
+		PayloadType: body.PayloadType,
+		Document:    bson.Raw(bsoncore.BuildDocument(nil, elemBytes)),
+	}
+	msg.Sections = sections
+	msg.FlagBits &^= wiremessage.ChecksumPresent
+	msg.MsgHeader.MessageLength = int32(msg.Len())
+	return msg, nil
+}
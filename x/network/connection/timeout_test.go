@@ -0,0 +1,85 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package connection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/network/wiremessage"
+)
+
+func TestAddMaxTime(t *testing.T) {
+	newMsg := func(elems ...[]byte) wiremessage.Msg {
+		body := bsoncore.BuildDocumentFromElements(nil, elems...)
+		return wiremessage.Msg{Sections: []wiremessage.Section{wiremessage.SectionBody{Document: bson.Raw(body)}}}
+	}
+	find := bsoncore.AppendStringElement(nil, "find", "coll")
+
+	t.Run("sets maxTimeMS", func(t *testing.T) {
+		ctx, cancel := WithOperationTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		wm, err := addMaxTime(ctx, newMsg(find), 0)
+		require.NoError(t, err)
+
+		msg := wm.(wiremessage.Msg)
+		body := bsoncore.Document(msg.Sections[0].(wiremessage.SectionBody).Document)
+		maxTime, ok := body.Lookup("maxTimeMS").Int64OK()
+		require.True(t, ok, "expected maxTimeMS to be set")
+		require.True(t, maxTime > 0 && maxTime <= int64(time.Minute/time.Millisecond), "unexpected maxTimeMS %d", maxTime)
+		require.Equal(t, int32(msg.Len()), msg.MsgHeader.MessageLength)
+	})
+	t.Run("unchanged", func(t *testing.T) {
+		ctx, cancel := WithOperationTimeout(context.Background(), time.Minute)
+		defer cancel()
+		deadlineCtx, deadlineCancel := context.WithTimeout(context.Background(), time.Minute)
+		defer deadlineCancel()
+
+		testCases := []struct {
+			name string
+			ctx  context.Context
+			msg  wiremessage.Msg
+		}{
+			{"no operation timeout", deadlineCtx, newMsg(find)},
+			{"getMore", ctx, newMsg(bsoncore.AppendInt64Element(nil, "getMore", 1))},
+			{"maxTimeMS set", ctx, newMsg(find, bsoncore.AppendInt64Element(nil, "maxTimeMS", 10))},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				wm, err := addMaxTime(tc.ctx, tc.msg, 0)
+				require.NoError(t, err)
+				require.Equal(t, tc.msg, wm)
+			})
+		}
+	})
+	t.Run("expired", func(t *testing.T) {
+		ctx, cancel := WithOperationTimeout(context.Background(), time.Nanosecond)
+		defer cancel()
+		<-ctx.Done()
+
+		_, err := addMaxTime(ctx, newMsg(find), 0)
+		require.Equal(t, context.DeadlineExceeded, err)
+	})
+	t.Run("less round trip time", func(t *testing.T) {
+		ctx, cancel := WithOperationTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		wm, err := addMaxTime(ctx, newMsg(find), 20*time.Second)
+		require.NoError(t, err)
+		body := bsoncore.Document(wm.(wiremessage.Msg).Sections[0].(wiremessage.SectionBody).Document)
+		maxTime := body.Lookup("maxTimeMS").Int64()
+		require.True(t, maxTime > 0 && maxTime <= int64(40*time.Second/time.Millisecond), "unexpected maxTimeMS %d", maxTime)
+
+		_, err = addMaxTime(ctx, newMsg(find), 2*time.Minute)
+		require.Equal(t, context.DeadlineExceeded, err)
+	})
+}
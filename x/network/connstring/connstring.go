@@ -7,23 +7,37 @@
 package connstring // import "go.mongodb.org/mongo-driver/x/network/connstring"
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"net"
 	"net/url"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/internal"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/tag"
 	"go.mongodb.org/mongo-driver/x/mongo/driverlegacy/dns"
+	"go.mongodb.org/mongo-driver/x/network/address"
 	"go.mongodb.org/mongo-driver/x/network/wiremessage"
 )
 
 // Parse parses the provided uri and returns a URI object.
 func Parse(s string) (ConnString, error) {
-	p := parser{dnsResolver: dns.DefaultResolver}
+	return ParseWithResolver(s, dns.DefaultResolver)
+}
+
+// ParseWithResolver works like Parse, but uses r for the SRV and TXT lookups of mongodb+srv
+// connection strings.
+func ParseWithResolver(s string, r *dns.Resolver) (ConnString, error) {
+	p := parser{dnsResolver: r}
 	err := p.parse(s)
 	if err != nil {
 		err = internal.WrapErrorf(err, "error parsing uri (%s)", s)
@@ -31,6 +45,71 @@ func Parse(s string) (ConnString, error) {
 	return p.ConnString, err
 }
 
+// ExpandEnv replaces each ${NAME} placeholder in the connection string s with the value of the
+// environment variable NAME. Values substituted into the user information and query sections of s
+// are percent-encoded, so usernames, passwords, and option values injected from the environment may
+// contain characters such as '@', ':', '/', '&', or '%' without changing the structure of the
+// connection string. Values substituted into the hosts and the database name are inserted as is.
+// An error is returned if a placeholder is not terminated or refers to an
+// environment variable that is not set.
+func ExpandEnv(s string) (string, error) {
+	return expandEnv(s, os.LookupEnv)
+}
+
+func expandEnv(s string, lookup func(string) (string, bool)) (string, error) {
+	// The user information ends at the first '@' after the scheme, which is the same rule the
+	// parser uses. Placeholder names cannot contain '@', so it can be located before expansion.
+	// Likewise, the query starts at the first '?' after the user information.
+	userInfoEnd := -1
+	queryStart := len(s)
+	if schemeEnd := strings.Index(s, "://"); schemeEnd != -1 {
+		if idx := strings.Index(s[schemeEnd:], "@"); idx != -1 {
+			userInfoEnd = schemeEnd + idx
+		}
+		hostsStart := schemeEnd
+		if userInfoEnd != -1 {
+			hostsStart = userInfoEnd
+		}
+		if idx := strings.Index(s[hostsStart:], "?"); idx != -1 {
+			queryStart = hostsStart + idx
+		}
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < len(s); {
+		start := strings.Index(s[i:], "${")
+		if start == -1 {
+			buf.WriteString(s[i:])
+			break
+		}
+		start += i
+		buf.WriteString(s[i:start])
+
+		end := strings.Index(s[start:], "}")
+		if end == -1 {
+			return "", fmt.Errorf("unterminated environment variable placeholder at position %d", start)
+		}
+		end += start
+
+		name := s[start+2 : end]
+		if name == "" {
+			return "", fmt.Errorf("empty environment variable placeholder at position %d", start)
+		}
+		val, ok := lookup(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s referenced in uri is not set", name)
+		}
+		if start < userInfoEnd || start > queryStart {
+			val = url.QueryEscape(val)
+		}
+		buf.WriteString(val)
+
+		i = end + 1
+	}
+
+	return buf.String(), nil
+}
+
 // ConnString represents a connection string to mongodb.
 type ConnString struct {
 	Original                           string
@@ -44,28 +123,38 @@ type ConnString struct {
 	ConnectTimeout                     time.Duration
 	ConnectTimeoutSet                  bool
 	Database                           string
+	DirectConnection                   bool
+	DirectConnectionSet                bool
 	HeartbeatInterval                  time.Duration
 	HeartbeatIntervalSet               bool
 	Hosts                              []string
 	J                                  bool
 	JSet                               bool
+	LoadBalanced                       bool
+	LoadBalancedSet                    bool
 	LocalThreshold                     time.Duration
 	LocalThresholdSet                  bool
 	MaxConnIdleTime                    time.Duration
 	MaxConnIdleTimeSet                 bool
 	MaxPoolSize                        uint16
 	MaxPoolSizeSet                     bool
+	MinPoolSize                        uint16
+	MinPoolSizeSet                     bool
 	Password                           string
 	PasswordSet                        bool
 	ReadConcernLevel                   string
 	ReadPreference                     string
 	ReadPreferenceTagSets              []map[string]string
+	RetryReads                         bool
+	RetryReadsSet                      bool
 	RetryWrites                        bool
 	RetryWritesSet                     bool
 	MaxStaleness                       time.Duration
 	MaxStalenessSet                    bool
 	ReplicaSet                         string
 	ServerSelectionTimeout             time.Duration
+	SRVMaxHosts                        int
+	SRVServiceName                     string
 	ServerSelectionTimeoutSet          bool
 	SocketTimeout                      time.Duration
 	SocketTimeoutSet                   bool
@@ -79,6 +168,8 @@ type ConnString struct {
 	SSLInsecureSet                     bool
 	SSLCaFile                          string
 	SSLCaFileSet                       bool
+	Timeout                            time.Duration
+	TimeoutSet                         bool
 	WString                            string
 	WNumber                            int
 	WNumberSet                         bool
@@ -90,14 +181,204 @@ type ConnString struct {
 	WTimeoutSet           bool
 	WTimeoutSetFromOption bool
 
+	TLSDisableCertificateRevocationCheck    bool
+	TLSDisableCertificateRevocationCheckSet bool
+	TLSDisableOCSPEndpointCheck             bool
+	TLSDisableOCSPEndpointCheckSet          bool
+
 	Options        map[string][]string
 	UnknownOptions map[string][]string
+
+	// Warnings describes the options that were ignored because their values are invalid but, as the
+	// connection string specification requires, do not make the connection string invalid.
+	Warnings []string
 }
 
 func (u *ConnString) String() string {
 	return u.Original
 }
 
+// redactedValue replaces credentials in connection strings returned by Redacted.
+const redactedValue = "xxxxx"
+
+// Build returns a connection string built from the fields of u, which parses into a ConnString
+// with the same fields. Options are only included if they are set, in a fixed order. The
+// connection string always has the mongodb scheme: for a connection string parsed from a
+// mongodb+srv one, it lists the hosts the SRV records resolved to and omits srvMaxHosts and
+// srvServiceName. Options and Warnings are ignored.
+func (u *ConnString) Build() string {
+	return u.build(false)
+}
+
+// Redacted returns the same connection string as Build, but with the password, the values of the
+// auth mechanism properties, and the client certificate key password replaced, so that it can be
+// logged.
+func (u *ConnString) Redacted() string {
+	return u.build(true)
+}
+
+func (u *ConnString) build(redact bool) string {
+	var buf bytes.Buffer
+	buf.WriteString("mongodb://")
+	if u.Username != "" || u.PasswordSet {
+		buf.WriteString(url.QueryEscape(u.Username))
+		if u.PasswordSet {
+			password := u.Password
+			if redact {
+				password = redactedValue
+			}
+			buf.WriteString(":" + url.QueryEscape(password))
+		}
+		buf.WriteString("@")
+	}
+	for i, host := range u.Hosts {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		// Hosts are only escaped when needed, for the paths of unix domain sockets.
+		if strings.Contains(host, "/") {
+			host = url.QueryEscape(host)
+		}
+		buf.WriteString(host)
+	}
+	buf.WriteString("/" + url.QueryEscape(u.Database))
+
+	var opts []string
+	add := func(key, value string) {
+		opts = append(opts, key+"="+url.QueryEscape(value))
+	}
+	addBool := func(key string, set, value bool) {
+		if set {
+			add(key, strconv.FormatBool(value))
+		}
+	}
+	addMS := func(key string, set bool, d time.Duration) {
+		if set {
+			add(key, strconv.FormatInt(int64(d/time.Millisecond), 10))
+		}
+	}
+
+	if u.AppName != "" {
+		add("appName", u.AppName)
+	}
+	if u.AuthMechanism != "" {
+		add("authMechanism", u.AuthMechanism)
+	}
+	if len(u.AuthMechanismProperties) > 0 {
+		props := u.AuthMechanismProperties
+		if redact {
+			// Properties such as AWS_SESSION_TOKEN are credentials, so all the values are redacted.
+			props = make(map[string]string, len(u.AuthMechanismProperties))
+			for name := range u.AuthMechanismProperties {
+				props[name] = redactedValue
+			}
+		}
+		add("authMechanismProperties", joinTags(props))
+	}
+	// The default auth source depends on the auth mechanism and the database, so it is always
+	// included when authentication is configured.
+	if u.AuthSource != "" && (u.Username != "" || u.AuthMechanism != "") {
+		add("authSource", u.AuthSource)
+	}
+	if len(u.Compressors) > 0 {
+		add("compressors", strings.Join(u.Compressors, ","))
+	}
+	addMS("connectTimeoutMS", u.ConnectTimeoutSet, u.ConnectTimeout)
+	if u.DirectConnectionSet {
+		addBool("directConnection", true, u.DirectConnection)
+	} else if u.ConnectSet {
+		connect := "automatic"
+		if u.Connect == SingleConnect {
+			connect = "direct"
+		}
+		add("connect", connect)
+	}
+	addMS("heartbeatFrequencyMS", u.HeartbeatIntervalSet, u.HeartbeatInterval)
+	addBool("journal", u.JSet, u.J)
+	addBool("loadBalanced", u.LoadBalancedSet, u.LoadBalanced)
+	addMS("localThresholdMS", u.LocalThresholdSet, u.LocalThreshold)
+	addMS("maxIdleTimeMS", u.MaxConnIdleTimeSet, u.MaxConnIdleTime)
+	if u.MaxPoolSizeSet {
+		add("maxPoolSize", strconv.Itoa(int(u.MaxPoolSize)))
+	}
+	if u.MaxStalenessSet {
+		add("maxStalenessSeconds", strconv.FormatInt(int64(u.MaxStaleness/time.Second), 10))
+	}
+	if u.MinPoolSizeSet {
+		add("minPoolSize", strconv.Itoa(int(u.MinPoolSize)))
+	}
+	if u.ReadConcernLevel != "" {
+		add("readConcernLevel", u.ReadConcernLevel)
+	}
+	if u.ReadPreference != "" {
+		add("readPreference", u.ReadPreference)
+	}
+	for _, tags := range u.ReadPreferenceTagSets {
+		add("readPreferenceTags", joinTags(tags))
+	}
+	if u.ReplicaSet != "" {
+		add("replicaSet", u.ReplicaSet)
+	}
+	addBool("retryReads", u.RetryReadsSet, u.RetryReads)
+	addBool("retryWrites", u.RetryWritesSet, u.RetryWrites)
+	addMS("serverSelectionTimeoutMS", u.ServerSelectionTimeoutSet, u.ServerSelectionTimeout)
+	addMS("socketTimeoutMS", u.SocketTimeoutSet, u.SocketTimeout)
+	addBool("ssl", u.SSLSet, u.SSL)
+	if u.SSLCaFileSet {
+		add("sslCertificateAuthorityFile", u.SSLCaFile)
+	}
+	if u.SSLClientCertificateKeyFileSet {
+		add("sslClientCertificateKeyFile", u.SSLClientCertificateKeyFile)
+	}
+	if u.SSLClientCertificateKeyPasswordSet && u.SSLClientCertificateKeyPassword != nil {
+		password := redactedValue
+		if !redact {
+			password = u.SSLClientCertificateKeyPassword()
+		}
+		add("sslClientCertificateKeyPassword", password)
+	}
+	addBool("sslInsecure", u.SSLInsecureSet, u.SSLInsecure)
+	addMS("timeoutMS", u.TimeoutSet, u.Timeout)
+	addBool("tlsDisableCertificateRevocationCheck", u.TLSDisableCertificateRevocationCheckSet,
+		u.TLSDisableCertificateRevocationCheck)
+	addBool("tlsDisableOCSPEndpointCheck", u.TLSDisableOCSPEndpointCheckSet, u.TLSDisableOCSPEndpointCheck)
+	if u.WNumberSet {
+		add("w", strconv.Itoa(u.WNumber))
+	} else if u.WString != "" {
+		add("w", u.WString)
+	}
+	addMS("wTimeoutMS", u.WTimeoutSet, u.WTimeout)
+	if u.ZlibLevelSet {
+		add("zlibCompressionLevel", strconv.Itoa(u.ZlibLevel))
+	}
+
+	unknown := make([]string, 0, len(u.UnknownOptions))
+	for key := range u.UnknownOptions {
+		unknown = append(unknown, key)
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		for _, value := range u.UnknownOptions[key] {
+			add(key, value)
+		}
+	}
+
+	if len(opts) > 0 {
+		buf.WriteString("?" + strings.Join(opts, "&"))
+	}
+	return buf.String()
+}
+
+// joinTags formats tags as a comma separated list of name:value pairs, sorted by name.
+func joinTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for name, value := range tags {
+		pairs = append(pairs, name+":"+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
 // ConnectMode informs the driver on how to connect
 // to the server.
 type ConnectMode uint8
@@ -184,7 +465,15 @@ func (p *parser) parse(original string) error {
 	parsedHosts := strings.Split(hosts, ",")
 
 	if isSRV {
-		parsedHosts, err = p.dnsResolver.ParseHosts(hosts, true)
+		if host, err := url.QueryUnescape(hosts); err == nil && address.Address(host).Network() == "unix" {
+			return errors.New("a mongodb+srv connection string cannot use a unix domain socket")
+		}
+		// The service name must be known before the SRV records are looked up.
+		srvName := dns.DefaultSRVServiceName
+		if name := lookupQueryArg(uri[len(hosts):], "srvservicename"); name != "" {
+			srvName = name
+		}
+		parsedHosts, err = p.dnsResolver.ParseHostsWithService(hosts, srvName, true)
 		if err != nil {
 			return err
 		}
@@ -238,6 +527,45 @@ func (p *parser) parse(original string) error {
 		return err
 	}
 
+	err = p.validateLoadBalanced()
+	if err != nil {
+		return err
+	}
+
+	err = p.validateDirectConnection(isSRV)
+	if err != nil {
+		return err
+	}
+
+	err = p.validateSRVOptions(isSRV)
+	if err != nil {
+		return err
+	}
+
+	err = p.validateOCSPOptions()
+	if err != nil {
+		return err
+	}
+
+	err = p.validateReadPreference()
+	if err != nil {
+		return err
+	}
+
+	err = p.validatePoolSize()
+	if err != nil {
+		return err
+	}
+
+	// Connect to a random subset of the hosts found if there are more than srvMaxHosts.
+	if p.SRVMaxHosts > 0 && len(p.Hosts) > p.SRVMaxHosts {
+		hosts := make([]string, 0, p.SRVMaxHosts)
+		for _, i := range rand.Perm(len(p.Hosts))[:p.SRVMaxHosts] {
+			hosts = append(hosts, p.Hosts[i])
+		}
+		p.Hosts = hosts
+	}
+
 	// Check for invalid write concern (i.e. w=0 and j=true)
 	if p.WNumberSet && p.WNumber == 0 && p.JSet && p.J {
 		return writeconcern.ErrInconsistent
@@ -269,7 +597,7 @@ func (p *parser) setDefaultAuthParams(dbName string) error {
 			p.AuthMechanismProperties["SERVICE_NAME"] = "mongodb"
 		}
 		fallthrough
-	case "mongodb-x509":
+	case "mongodb-x509", "mongodb-oidc":
 		if p.AuthSource == "" {
 			p.AuthSource = "$external"
 		} else if p.AuthSource != "$external" {
@@ -299,6 +627,122 @@ func (p *parser) setDefaultAuthParams(dbName string) error {
 	return nil
 }
 
+func (p *parser) validateLoadBalanced() error {
+	if !p.LoadBalanced {
+		return nil
+	}
+	if len(p.Hosts) > 1 {
+		return errors.New("loadBalanced cannot be set to true if multiple hosts are specified")
+	}
+	if p.ReplicaSet != "" {
+		return errors.New("loadBalanced cannot be set to true if a replica set name is specified")
+	}
+	if p.ConnectSet && p.Connect == SingleConnect {
+		return errors.New("loadBalanced cannot be set to true if connect is direct")
+	}
+	return nil
+}
+
+func (p *parser) validateDirectConnection(isSRV bool) error {
+	if !p.DirectConnection {
+		return nil
+	}
+	if isSRV {
+		return errors.New("directConnection cannot be set to true with the mongodb+srv scheme")
+	}
+	if len(p.Hosts) > 1 {
+		return errors.New("directConnection cannot be set to true if multiple hosts are specified")
+	}
+	return nil
+}
+
+// validateOCSPOptions rejects the OCSP options that contradict each other or sslInsecure, which
+// disables the verification of server certificates altogether.
+func (p *parser) validateOCSPOptions() error {
+	if p.TLSDisableCertificateRevocationCheckSet && p.TLSDisableOCSPEndpointCheckSet {
+		return errors.New("tlsDisableCertificateRevocationCheck and tlsDisableOCSPEndpointCheck cannot both be set")
+	}
+	if p.SSLInsecureSet && (p.TLSDisableCertificateRevocationCheckSet || p.TLSDisableOCSPEndpointCheckSet) {
+		return errors.New("sslInsecure cannot be set with tlsDisableCertificateRevocationCheck or tlsDisableOCSPEndpointCheck")
+	}
+	return nil
+}
+
+// minMaxStaleness is the smallest maximum staleness allowed, which is long enough for servers to
+// report how stale they are at least once.
+const minMaxStaleness = 90 * time.Second
+
+func (p *parser) validateReadPreference() error {
+	if p.ReadPreference != "" {
+		if _, err := readpref.ModeFromString(p.ReadPreference); err != nil {
+			return err
+		}
+	}
+	primary := p.ReadPreference == "" || strings.ToLower(p.ReadPreference) == "primary"
+	if primary && len(p.ReadPreferenceTagSets) > 0 {
+		return errors.New("readPreferenceTags cannot be used with read preference mode primary")
+	}
+	if primary && p.MaxStalenessSet {
+		return errors.New("maxStalenessSeconds cannot be used with read preference mode primary")
+	}
+	if p.MaxStalenessSet && p.MaxStaleness < minMaxStaleness {
+		return fmt.Errorf("maxStalenessSeconds must be at least %d seconds", minMaxStaleness/time.Second)
+	}
+	return nil
+}
+
+func (p *parser) validatePoolSize() error {
+	// A maxPoolSize of 0 means that there is no limit to the number of connections.
+	if p.MinPoolSizeSet && p.MaxPoolSizeSet && p.MaxPoolSize != 0 && p.MinPoolSize > p.MaxPoolSize {
+		return fmt.Errorf("minPoolSize (%d) cannot be greater than maxPoolSize (%d)", p.MinPoolSize, p.MaxPoolSize)
+	}
+	return nil
+}
+
+// ReadPref returns the read preference described by the readPreference, readPreferenceTags and
+// maxStalenessSeconds options, or nil if none of them is set.
+func (u *ConnString) ReadPref() (*readpref.ReadPref, error) {
+	if u.ReadPreference == "" && len(u.ReadPreferenceTagSets) == 0 && !u.MaxStalenessSet {
+		return nil, nil
+	}
+
+	mode := readpref.PrimaryMode
+	if u.ReadPreference != "" {
+		var err error
+		if mode, err = readpref.ModeFromString(u.ReadPreference); err != nil {
+			return nil, err
+		}
+	}
+
+	var opts []readpref.Option
+	if tagSets := tag.NewTagSetsFromMaps(u.ReadPreferenceTagSets); len(tagSets) > 0 {
+		opts = append(opts, readpref.WithTagSets(tagSets...))
+	}
+	if u.MaxStalenessSet {
+		opts = append(opts, readpref.WithMaxStaleness(u.MaxStaleness))
+	}
+	return readpref.New(mode, opts...)
+}
+
+func (p *parser) validateSRVOptions(isSRV bool) error {
+	if !isSRV {
+		if p.SRVMaxHosts != 0 {
+			return errors.New("srvMaxHosts can only be set with the mongodb+srv scheme")
+		}
+		if p.SRVServiceName != "" {
+			return errors.New("srvServiceName can only be set with the mongodb+srv scheme")
+		}
+		return nil
+	}
+	if p.SRVMaxHosts > 0 && p.ReplicaSet != "" {
+		return errors.New("srvMaxHosts cannot be set if a replica set name is specified")
+	}
+	if p.SRVMaxHosts > 0 && p.LoadBalanced {
+		return errors.New("srvMaxHosts cannot be set if loadBalanced is true")
+	}
+	return nil
+}
+
 func (p *parser) validateAuth() error {
 	switch strings.ToLower(p.AuthMechanism) {
 	case "mongodb-cr":
@@ -327,6 +771,27 @@ func (p *parser) validateAuth() error {
 				return fmt.Errorf("invalid auth property for GSSAPI")
 			}
 		}
+	case "mongodb-oidc":
+		if p.Password != "" {
+			return fmt.Errorf("password cannot be specified for MONGODB-OIDC")
+		}
+		for k, v := range p.AuthMechanismProperties {
+			switch k {
+			case "ENVIRONMENT":
+				if v != "azure" && v != "gcp" {
+					return fmt.Errorf("invalid ENVIRONMENT for MONGODB-OIDC: %s", v)
+				}
+				if p.AuthMechanismProperties["TOKEN_RESOURCE"] == "" {
+					return fmt.Errorf("TOKEN_RESOURCE required for the %s environment of MONGODB-OIDC", v)
+				}
+				if v == "gcp" && p.Username != "" {
+					return fmt.Errorf("username cannot be specified for the gcp environment of MONGODB-OIDC")
+				}
+			case "TOKEN_RESOURCE":
+			default:
+				return fmt.Errorf("invalid auth property for MONGODB-OIDC")
+			}
+		}
 	case "plain":
 		if p.Username == "" {
 			return fmt.Errorf("username required for PLAIN")
@@ -373,6 +838,15 @@ func (p *parser) addHost(host string) error {
 		return internal.WrapErrorf(err, "invalid host \"%s\"", host)
 	}
 
+	// Unix domain sockets are given as URL encoded paths, which have no port.
+	if address.Address(host).Network() == "unix" {
+		p.Hosts = append(p.Hosts, host)
+		return nil
+	}
+	if strings.Contains(host, "/") {
+		return fmt.Errorf("the path of a unix domain socket must end with .sock")
+	}
+
 	_, port, err := net.SplitHostPort(host)
 	// this is unfortunate that SplitHostPort actually requires
 	// a port to exist.
@@ -395,6 +869,10 @@ func (p *parser) addHost(host string) error {
 	return nil
 }
 
+// maxAppNameLength is the maximum length in bytes of an application name. Servers reject handshakes
+// with longer names.
+const maxAppNameLength = 128
+
 func (p *parser) addOption(pair string) error {
 	kv := strings.SplitN(pair, "=", 2)
 	if len(kv) != 2 || kv[0] == "" {
@@ -414,6 +892,9 @@ func (p *parser) addOption(pair string) error {
 	lowerKey := strings.ToLower(key)
 	switch lowerKey {
 	case "appname":
+		if len(value) > maxAppNameLength {
+			return fmt.Errorf("invalid value for %s: must not be longer than %d bytes", key, maxAppNameLength)
+		}
 		p.AppName = value
 	case "authmechanism":
 		p.AuthMechanism = value
@@ -430,9 +911,14 @@ func (p *parser) addOption(pair string) error {
 	case "authsource":
 		p.AuthSource = value
 	case "compressors":
-		compressors := strings.Split(value, ",")
-		if len(compressors) < 1 {
-			return fmt.Errorf("must have at least 1 compressor")
+		compressors := make([]string, 0)
+		for _, comp := range strings.Split(value, ",") {
+			switch comp {
+			case "snappy", "zlib":
+				compressors = append(compressors, comp)
+			default:
+				p.Warnings = append(p.Warnings, fmt.Sprintf("ignoring unsupported compressor: %s", comp))
+			}
 		}
 		p.Compressors = compressors
 	case "connect":
@@ -452,6 +938,20 @@ func (p *parser) addOption(pair string) error {
 		}
 		p.ConnectTimeout = time.Duration(n) * time.Millisecond
 		p.ConnectTimeoutSet = true
+	case "directconnection":
+		switch value {
+		case "true":
+			p.DirectConnection = true
+			p.Connect = SingleConnect
+		case "false":
+			p.DirectConnection = false
+			p.Connect = AutoConnect
+		default:
+			return fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+
+		p.DirectConnectionSet = true
+		p.ConnectSet = true
 	case "heartbeatintervalms", "heartbeatfrequencyms":
 		n, err := strconv.Atoi(value)
 		if err != nil || n < 0 {
@@ -470,6 +970,17 @@ func (p *parser) addOption(pair string) error {
 		}
 
 		p.JSet = true
+	case "loadbalanced":
+		switch value {
+		case "true":
+			p.LoadBalanced = true
+		case "false":
+			p.LoadBalanced = false
+		default:
+			return fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+
+		p.LoadBalancedSet = true
 	case "localthresholdms":
 		n, err := strconv.Atoi(value)
 		if err != nil || n < 0 {
@@ -486,37 +997,74 @@ func (p *parser) addOption(pair string) error {
 		p.MaxConnIdleTimeSet = true
 	case "maxpoolsize":
 		n, err := strconv.Atoi(value)
-		if err != nil || n < 0 {
+		if err != nil || n < 0 || n > math.MaxUint16 {
 			return fmt.Errorf("invalid value for %s: %s", key, value)
 		}
 		p.MaxPoolSize = uint16(n)
 		p.MaxPoolSizeSet = true
+	case "minpoolsize":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 || n > math.MaxUint16 {
+			return fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+		p.MinPoolSize = uint16(n)
+		p.MinPoolSizeSet = true
 	case "readconcernlevel":
 		p.ReadConcernLevel = value
 	case "readpreference":
 		p.ReadPreference = value
 	case "readpreferencetags":
+		// An empty tag set matches any server, so it is used as the last of the tag sets to fall
+		// back to any eligible server.
 		tags := make(map[string]string)
-		items := strings.Split(value, ",")
-		for _, item := range items {
-			parts := strings.Split(item, ":")
-			if len(parts) != 2 {
-				return fmt.Errorf("invalid value for %s: %s", key, value)
+		if value != "" {
+			for _, item := range strings.Split(value, ",") {
+				parts := strings.Split(item, ":")
+				if len(parts) != 2 {
+					p.Warnings = append(p.Warnings, fmt.Sprintf("ignoring invalid value for %s: %s", key, value))
+					return nil
+				}
+				tags[parts[0]] = parts[1]
 			}
-			tags[parts[0]] = parts[1]
 		}
 		p.ReadPreferenceTagSets = append(p.ReadPreferenceTagSets, tags)
-	case "maxstaleness":
+	case "maxstaleness", "maxstalenessseconds":
 		n, err := strconv.Atoi(value)
-		if err != nil || n < 0 {
-			return fmt.Errorf("invalid value for %s: %s", key, value)
+		if err != nil || n < -1 {
+			p.Warnings = append(p.Warnings, fmt.Sprintf("ignoring invalid value for %s: %s", key, value))
+			return nil
+		}
+		// -1 means that there is no maximum staleness.
+		if n == -1 {
+			p.MaxStaleness = 0
+			p.MaxStalenessSet = false
+			return nil
 		}
 		p.MaxStaleness = time.Duration(n) * time.Second
 		p.MaxStalenessSet = true
 	case "replicaset":
 		p.ReplicaSet = value
+	case "retryreads":
+		switch value {
+		case "true":
+			p.RetryReads = true
+		case "false":
+			p.RetryReads = false
+		default:
+			return fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+
+		p.RetryReadsSet = true
 	case "retrywrites":
-		p.RetryWrites = value == "true"
+		switch value {
+		case "true":
+			p.RetryWrites = true
+		case "false":
+			p.RetryWrites = false
+		default:
+			return fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+
 		p.RetryWritesSet = true
 	case "serverselectiontimeoutms":
 		n, err := strconv.Atoi(value)
@@ -525,6 +1073,17 @@ func (p *parser) addOption(pair string) error {
 		}
 		p.ServerSelectionTimeout = time.Duration(n) * time.Millisecond
 		p.ServerSelectionTimeoutSet = true
+	case "srvmaxhosts":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+		p.SRVMaxHosts = n
+	case "srvservicename":
+		if value == "" {
+			return fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+		p.SRVServiceName = value
 	case "sockettimeoutms":
 		n, err := strconv.Atoi(value)
 		if err != nil || n < 0 {
@@ -567,6 +1126,35 @@ func (p *parser) addOption(pair string) error {
 		p.SSLSet = true
 		p.SSLCaFile = value
 		p.SSLCaFileSet = true
+	case "tlsdisablecertificaterevocationcheck":
+		switch value {
+		case "true":
+			p.TLSDisableCertificateRevocationCheck = true
+		case "false":
+			p.TLSDisableCertificateRevocationCheck = false
+		default:
+			return fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+
+		p.TLSDisableCertificateRevocationCheckSet = true
+	case "tlsdisableocspendpointcheck":
+		switch value {
+		case "true":
+			p.TLSDisableOCSPEndpointCheck = true
+		case "false":
+			p.TLSDisableOCSPEndpointCheck = false
+		default:
+			return fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+
+		p.TLSDisableOCSPEndpointCheckSet = true
+	case "timeoutms":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid value for %s: %s", key, value)
+		}
+		p.Timeout = time.Duration(n) * time.Millisecond
+		p.TimeoutSet = true
 	case "w":
 		if w, err := strconv.Atoi(value); err == nil {
 			if w < 0 {
@@ -625,6 +1213,32 @@ func (p *parser) addOption(pair string) error {
 	return nil
 }
 
+// lookupQueryArg returns the unescaped value of the option key, which must be lowercase, in the query
+// string of uri, the part of a connection string after its hosts. It returns "" if the option is not
+// set or cannot be unescaped.
+func lookupQueryArg(uri, key string) string {
+	idx := strings.Index(uri, "?")
+	if idx == -1 {
+		return ""
+	}
+	for _, pair := range strings.FieldsFunc(uri[idx+1:], func(r rune) bool { return r == ';' || r == '&' }) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k, err := url.QueryUnescape(kv[0])
+		if err != nil || strings.ToLower(k) != key {
+			continue
+		}
+		v, err := url.QueryUnescape(kv[1])
+		if err != nil {
+			return ""
+		}
+		return v
+	}
+	return ""
+}
+
 func extractQueryArgsFromURI(uri string) ([]string, error) {
 	if len(uri) == 0 {
 		return nil, nil
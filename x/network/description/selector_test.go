@@ -255,6 +255,32 @@ func TestSelector_Single(t *testing.T) {
 	require.Equal([]Server{s}, result)
 }
 
+func TestSelector_LoadBalanced(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+	subject := readpref.Secondary()
+
+	s := Server{
+		Addr: address.Address("localhost:27017"),
+		Kind: LoadBalancer,
+	}
+	c := Topology{
+		Kind:    LoadBalanced,
+		Servers: []Server{s},
+	}
+
+	result, err := ReadPrefSelector(subject).SelectServer(c, c.Servers)
+
+	require.NoError(err)
+	require.Equal([]Server{s}, result)
+
+	result, err = WriteSelector().SelectServer(c, c.Servers)
+
+	require.NoError(err)
+	require.Equal([]Server{s}, result)
+}
+
 func TestSelector_Primary(t *testing.T) {
 	t.Parallel()
 
@@ -32,6 +32,7 @@ type Server struct {
 
 	AverageRTT            time.Duration
 	AverageRTTSet         bool
+	MinRTT                time.Duration
 	Compression           []string // compression methods returned by server
 	CanonicalAddr         address.Address
 	ElectionID            primitive.ObjectID
@@ -45,9 +46,11 @@ type Server struct {
 	Members               []address.Address
 	ReadOnly              bool
 	SessionTimeoutMinutes uint32
+	ServiceID             primitive.ObjectID // set by servers behind a load balancer
 	SetName               string
 	SetVersion            uint32
 	Tags                  tag.Set
+	TopologyVersion       *result.TopologyVersion // set by servers that support streaming monitoring
 	Kind                  ServerKind
 	WireVersion           *VersionRange
 
@@ -68,10 +71,12 @@ func NewServer(addr address.Address, isMaster result.IsMaster) Server {
 		MaxDocumentSize:       isMaster.MaxBSONObjectSize,
 		MaxMessageSize:        isMaster.MaxMessageSizeBytes,
 		SaslSupportedMechs:    isMaster.SaslSupportedMechs,
+		ServiceID:             isMaster.ServiceID,
 		SessionTimeoutMinutes: isMaster.LogicalSessionTimeoutMinutes,
 		SetName:               isMaster.SetName,
 		SetVersion:            isMaster.SetVersion,
 		Tags:                  tag.NewTagSetFromMap(isMaster.Tags),
+		TopologyVersion:       isMaster.TopologyVersion,
 	}
 
 	if i.CanonicalAddr == "" {
@@ -140,7 +145,8 @@ func (s Server) DataBearing() bool {
 	return s.Kind == RSPrimary ||
 		s.Kind == RSSecondary ||
 		s.Kind == Mongos ||
-		s.Kind == Standalone
+		s.Kind == Standalone ||
+		s.Kind == LoadBalancer
 }
 
 // SelectServer selects this server if it is in the list of given candidates.
@@ -152,3 +158,88 @@ func (s Server) SelectServer(_ Topology, candidates []Server) ([]Server, error)
 	}
 	return nil, nil
 }
+
+// Equal compares two server descriptions and returns true if they are equal. The round trip time
+// and update times are not compared because they change on every heartbeat.
+func (s Server) Equal(other Server) bool {
+	if s.Addr.String() != other.Addr.String() || s.CanonicalAddr.String() != other.CanonicalAddr.String() {
+		return false
+	}
+
+	if !sliceStringEqual(s.Compression, other.Compression) {
+		return false
+	}
+
+	if s.ElectionID != other.ElectionID {
+		return false
+	}
+
+	if errorString(s.LastError) != errorString(other.LastError) {
+		return false
+	}
+
+	if s.Kind != other.Kind {
+		return false
+	}
+
+	if s.MaxBatchCount != other.MaxBatchCount ||
+		s.MaxDocumentSize != other.MaxDocumentSize ||
+		s.MaxMessageSize != other.MaxMessageSize {
+		return false
+	}
+
+	if len(s.Members) != len(other.Members) {
+		return false
+	}
+	for i := range s.Members {
+		if s.Members[i].String() != other.Members[i].String() {
+			return false
+		}
+	}
+
+	if s.ReadOnly != other.ReadOnly ||
+		s.SessionTimeoutMinutes != other.SessionTimeoutMinutes ||
+		s.SetName != other.SetName ||
+		s.SetVersion != other.SetVersion {
+		return false
+	}
+
+	if len(s.Tags) != len(other.Tags) || !s.Tags.ContainsAll(other.Tags) {
+		return false
+	}
+
+	if (s.TopologyVersion == nil) != (other.TopologyVersion == nil) {
+		return false
+	}
+	if s.TopologyVersion != nil && *s.TopologyVersion != *other.TopologyVersion {
+		return false
+	}
+
+	if (s.WireVersion == nil) != (other.WireVersion == nil) {
+		return false
+	}
+	if s.WireVersion != nil && *s.WireVersion != *other.WireVersion {
+		return false
+	}
+
+	return true
+}
+
+func sliceStringEqual(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if v != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
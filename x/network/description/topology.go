@@ -20,6 +20,16 @@ type Topology struct {
 	SessionTimeoutMinutes uint32
 }
 
+// String implements the fmt.Stringer interface. It summarizes the kind of the topology and the
+// address and kind of each server.
+func (t Topology) String() string {
+	servers := make([]string, 0, len(t.Servers))
+	for _, s := range t.Servers {
+		servers = append(servers, "{Addr: "+s.Addr.String()+", Type: "+s.Kind.String()+"}")
+	}
+	return "Type: " + t.Kind.String() + ", Servers: [" + strings.Join(servers, ", ") + "]"
+}
+
 // Server returns the server for the given address. Returns false if the server
 // could not be found.
 func (t Topology) Server(addr address.Address) (Server, bool) {
@@ -31,6 +41,26 @@ func (t Topology) Server(addr address.Address) (Server, bool) {
 	return Server{}, false
 }
 
+// Equal compares two topology descriptions and returns true if they are equal. The servers are
+// compared by address regardless of their order.
+func (t Topology) Equal(other Topology) bool {
+	if t.Kind != other.Kind || t.SessionTimeoutMinutes != other.SessionTimeoutMinutes {
+		return false
+	}
+
+	if len(t.Servers) != len(other.Servers) {
+		return false
+	}
+	for _, s := range t.Servers {
+		os, ok := other.Server(s.Addr)
+		if !ok || !s.Equal(os) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // TopologyDiff is the difference between two different topology descriptions.
 type TopologyDiff struct {
 	Added   []Server
@@ -59,6 +59,7 @@ func TestTailableCursorLoopsUntilDocsAvailable(t *testing.T) {
 		clientID,
 		&session.Pool{},
 		bson.DefaultRegistry,
+		false,
 		options.Find().SetCursorType(options.TailableAwait),
 	)
 	noerr(t, err)
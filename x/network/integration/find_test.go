@@ -91,6 +91,7 @@ func TestFindPassesMaxAwaitTimeMSThroughToGetMore(t *testing.T) {
 		clientID,
 		&session.Pool{},
 		bson.DefaultRegistry,
+		false,
 		options.Find().SetBatchSize(3).SetCursorType(options.TailableAwait).SetMaxAwaitTime(250*time.Millisecond),
 	)
 
@@ -107,6 +107,7 @@ func TestCommandListCollections(t *testing.T) {
 			description.WriteSelector(),
 			clientID,
 			&session.Pool{},
+			false,
 		)
 		noerr(t, err)
 
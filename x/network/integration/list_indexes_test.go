@@ -33,6 +33,7 @@ func runCommand(t *testing.T, cmd command.ListIndexes, opts ...*options.ListInde
 		description.WriteSelector(),
 		clientID,
 		&session.Pool{},
+		false,
 		opts...,
 	)
 }
@@ -0,0 +1,197 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package ocsp verifies that server certificates have not been revoked, using the OCSP responses
+// stapled by servers or requested from the OCSP responders listed in the certificates.
+package ocsp // import "go.mongodb.org/mongo-driver/x/network/ocsp"
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// requestTimeout is the time allowed to get a response from the OCSP responders of a certificate.
+const requestTimeout = 5 * time.Second
+
+// maxResponseSize is the maximum size of a response read from an OCSP responder.
+const maxResponseSize = 1 << 20
+
+// ErrMissingStapledResponse is returned when the certificate of a server requires the server to
+// staple a valid OCSP response to the TLS handshake, but it did not.
+var ErrMissingStapledResponse = errors.New("server certificate requires a stapled OCSP response, " +
+	"but the server did not provide a valid one")
+
+// RevokedError is returned when the certificate of a server has been revoked.
+type RevokedError struct {
+	SerialNumber *big.Int
+	RevokedAt    time.Time
+}
+
+func (e *RevokedError) Error() string {
+	return fmt.Sprintf("server certificate with serial number %v was revoked at %v", e.SerialNumber, e.RevokedAt)
+}
+
+// Config configures how server certificates are verified.
+type Config struct {
+	// Cache holds the responses that are still valid so that they are not requested again. It can
+	// be nil.
+	Cache *Cache
+
+	// DisableEndpointChecking prevents requesting responses from OCSP responders, so that only
+	// stapled and cached responses are used.
+	DisableEndpointChecking bool
+
+	// HTTPClient sends the requests to OCSP responders. If it is nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// Verify checks that the certificate of the server of a TLS connection has not been revoked. It
+// returns a *RevokedError if a stapled, cached or requested OCSP response reports it as revoked,
+// and ErrMissingStapledResponse if the certificate requires a stapled response the server did not
+// provide. If no response can be obtained, the certificate is accepted.
+func Verify(ctx context.Context, state tls.ConnectionState, cfg *Config) error {
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) < 2 {
+		// The certificate was not verified or has no issuer to check its status with.
+		return nil
+	}
+	cert, issuer := state.VerifiedChains[0][0], state.VerifiedChains[0][1]
+
+	if len(state.OCSPResponse) > 0 {
+		resp, err := parseResponse(state.OCSPResponse, cert, issuer)
+		if err == nil && resp.usable(time.Now()) {
+			cfg.Cache.put(cert, issuer, resp)
+			return resp.err(cert)
+		}
+	}
+	if mustStaple(cert) {
+		return ErrMissingStapledResponse
+	}
+
+	if resp := cfg.Cache.get(cert, issuer); resp != nil {
+		return resp.err(cert)
+	}
+	if cfg.DisableEndpointChecking {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	if resp := cfg.request(ctx, cert, issuer); resp != nil {
+		cfg.Cache.put(cert, issuer, resp)
+		return resp.err(cert)
+	}
+	return nil
+}
+
+// request requests the status of cert from its OCSP responders in turn and returns the first usable
+// response, or nil if none of them returned one.
+func (cfg *Config) request(ctx context.Context, cert, issuer *x509.Certificate) *response {
+	if len(cert.OCSPServer) == 0 {
+		return nil
+	}
+	body, err := createRequest(cert, issuer)
+	if err != nil {
+		return nil
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	for _, server := range cert.OCSPServer {
+		req, err := http.NewRequest(http.MethodPost, server, bytes.NewReader(body))
+		if err != nil {
+			continue
+		}
+		req.Header.Set("Content-Type", "application/ocsp-request")
+		httpResp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			continue
+		}
+		der, err := ioutil.ReadAll(io.LimitReader(httpResp.Body, maxResponseSize))
+		_ = httpResp.Body.Close()
+		if err != nil || httpResp.StatusCode != http.StatusOK {
+			continue
+		}
+		resp, err := parseResponse(der, cert, issuer)
+		if err == nil && resp.usable(time.Now()) {
+			return resp
+		}
+	}
+	return nil
+}
+
+// usable returns true if r is current at now and reports whether the certificate is good or revoked.
+func (r *response) usable(now time.Time) bool {
+	if r.status == statusUnknown || r.thisUpdate.After(now.Add(5*time.Minute)) {
+		return false
+	}
+	return r.nextUpdate.IsZero() || now.Before(r.nextUpdate)
+}
+
+func (r *response) err(cert *x509.Certificate) error {
+	if r.status != statusRevoked {
+		return nil
+	}
+	return &RevokedError{SerialNumber: cert.SerialNumber, RevokedAt: r.revokedAt}
+}
+
+// Cache holds OCSP responses until their next update. A nil *Cache holds nothing.
+type Cache struct {
+	lock      sync.Mutex
+	responses map[string]*response
+}
+
+// NewCache creates an empty Cache.
+func NewCache() *Cache {
+	return &Cache{responses: make(map[string]*response)}
+}
+
+func cacheKey(cert, issuer *x509.Certificate) string {
+	return string(issuer.RawSubjectPublicKeyInfo) + "/" + cert.SerialNumber.String()
+}
+
+// put stores resp if it states when it expires.
+func (c *Cache) put(cert, issuer *x509.Certificate, resp *response) {
+	if c == nil || resp.nextUpdate.IsZero() {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.responses[cacheKey(cert, issuer)] = resp
+}
+
+// get returns the cached response for cert if it has not expired.
+func (c *Cache) get(cert, issuer *x509.Certificate) *response {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := cacheKey(cert, issuer)
+	resp, ok := c.responses[key]
+	if !ok {
+		return nil
+	}
+	if !resp.usable(time.Now()) {
+		delete(c.responses, key)
+		return nil
+	}
+	return resp
+}
@@ -0,0 +1,217 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package ocsp
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) issue(t *testing.T, serial int64, mustStaple bool, servers ...string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		OCSPServer:   servers,
+	}
+	if mustStaple {
+		features, err := asn1.Marshal([]int{statusRequestFeature})
+		require.NoError(t, err)
+		tmpl.ExtraExtensions = []pkix.Extension{{Id: oidTLSFeature, Value: features}}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+// respond creates a response for the status of cert signed by ca. A zero revokedAt reports it as
+// good.
+func (ca *testCA) respond(t *testing.T, cert *x509.Certificate, revokedAt, nextUpdate time.Time) []byte {
+	id, err := newCertID(cert, ca.cert)
+	require.NoError(t, err)
+
+	status := asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: statusGood}
+	if !revokedAt.IsZero() {
+		revocationTime, err := asn1.MarshalWithParams(revokedAt.UTC(), "generalized")
+		require.NoError(t, err)
+		status = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: statusRevoked, IsCompound: true, Bytes: revocationTime}
+	}
+	keyHash, err := asn1.Marshal(id.IssuerKeyHash)
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	tbs, err := asn1.Marshal(responseData{
+		RawResponderID: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, IsCompound: true, Bytes: keyHash},
+		ProducedAt:     now,
+		Responses: []singleResponse{{
+			CertID:     id,
+			Status:     status,
+			ThisUpdate: now,
+			NextUpdate: nextUpdate.UTC().Truncate(time.Second),
+		}},
+	})
+	require.NoError(t, err)
+
+	digest := sha256.Sum256(tbs)
+	sig, err := ca.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+	basic, err := asn1.Marshal(basicResponse{
+		TBSResponseData:    responseData{Raw: tbs},
+		SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}},
+		Signature:          asn1.BitString{Bytes: sig, BitLength: 8 * len(sig)},
+	})
+	require.NoError(t, err)
+	der, err := asn1.Marshal(responseASN1{
+		Status:   responseStatusSuccessful,
+		Response: responseBytes{ResponseType: oidOCSPBasic, Response: basic},
+	})
+	require.NoError(t, err)
+	return der
+}
+
+func connectionState(cert, issuer *x509.Certificate, stapled []byte) tls.ConnectionState {
+	return tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{cert, issuer}},
+		OCSPResponse:   stapled,
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ca := newTestCA(t)
+	nextUpdate := time.Now().Add(time.Hour)
+	revokedAt := time.Now().Add(-time.Minute)
+
+	t.Run("no response", func(t *testing.T) {
+		cert := ca.issue(t, 2, false)
+		require.NoError(t, Verify(context.Background(), connectionState(cert, ca.cert, nil), &Config{}))
+	})
+	t.Run("stapled good", func(t *testing.T) {
+		cert := ca.issue(t, 3, true)
+		stapled := ca.respond(t, cert, time.Time{}, nextUpdate)
+		require.NoError(t, Verify(context.Background(), connectionState(cert, ca.cert, stapled), &Config{}))
+	})
+	t.Run("stapled revoked", func(t *testing.T) {
+		cert := ca.issue(t, 4, false)
+		stapled := ca.respond(t, cert, revokedAt, nextUpdate)
+		err := Verify(context.Background(), connectionState(cert, ca.cert, stapled), &Config{})
+		revoked, ok := err.(*RevokedError)
+		require.True(t, ok, "expected a *RevokedError, got %v", err)
+		require.Equal(t, cert.SerialNumber, revoked.SerialNumber)
+	})
+	t.Run("must staple without a stapled response", func(t *testing.T) {
+		cert := ca.issue(t, 5, true)
+		err := Verify(context.Background(), connectionState(cert, ca.cert, nil), &Config{})
+		require.Equal(t, ErrMissingStapledResponse, err)
+	})
+	t.Run("must staple with a response signed by another issuer", func(t *testing.T) {
+		cert := ca.issue(t, 6, true)
+		stapled := newTestCA(t).respond(t, cert, time.Time{}, nextUpdate)
+		state := connectionState(cert, ca.cert, stapled)
+		require.Equal(t, ErrMissingStapledResponse, Verify(context.Background(), state, &Config{}))
+	})
+	t.Run("stapled expired response is ignored", func(t *testing.T) {
+		cert := ca.issue(t, 7, false)
+		stapled := ca.respond(t, cert, revokedAt, time.Now().Add(-time.Minute))
+		require.NoError(t, Verify(context.Background(), connectionState(cert, ca.cert, stapled), &Config{}))
+	})
+}
+
+func TestVerifyEndpoint(t *testing.T) {
+	ca := newTestCA(t)
+	var requests int
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		der, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req ocspRequest
+		_, err = asn1.Unmarshal(der, &req)
+		require.NoError(t, err)
+		require.Len(t, req.TBSRequest.RequestList, 1)
+		if body == nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	cert := ca.issue(t, 2, false, srv.URL)
+	state := connectionState(cert, ca.cert, nil)
+
+	t.Run("soft fail", func(t *testing.T) {
+		requests = 0
+		require.NoError(t, Verify(context.Background(), state, &Config{Cache: NewCache()}))
+		require.Equal(t, 1, requests)
+	})
+	t.Run("endpoint checking disabled", func(t *testing.T) {
+		requests = 0
+		body = ca.respond(t, cert, time.Now().Add(-time.Minute), time.Now().Add(time.Hour))
+		require.NoError(t, Verify(context.Background(), state, &Config{DisableEndpointChecking: true}))
+		require.Equal(t, 0, requests)
+	})
+	t.Run("revoked and cached", func(t *testing.T) {
+		requests = 0
+		cfg := &Config{Cache: NewCache()}
+		for i := 0; i < 2; i++ {
+			err := Verify(context.Background(), state, cfg)
+			_, ok := err.(*RevokedError)
+			require.True(t, ok, "expected a *RevokedError, got %v", err)
+		}
+		require.Equal(t, 1, requests)
+
+		// Cached responses are used even if endpoint checking is disabled.
+		cfg.DisableEndpointChecking = true
+		_, ok := Verify(context.Background(), state, cfg).(*RevokedError)
+		require.True(t, ok)
+	})
+}
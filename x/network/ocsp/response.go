@@ -0,0 +1,289 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package ocsp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	// Register the hash functions used by certificate IDs.
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+)
+
+// The ASN.1 structures of OCSP requests and responses are defined in RFC 6960.
+
+var (
+	oidOCSPBasic  = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 1}
+	oidSHA1       = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidTLSFeature = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 24}
+)
+
+// statusRequestFeature is the TLS feature that requires servers to staple OCSP responses.
+const statusRequestFeature = 5
+
+var hashOIDs = map[string]crypto.Hash{
+	oidSHA1.String():   crypto.SHA1,
+	oidSHA256.String(): crypto.SHA256,
+}
+
+var signatureAlgorithms = map[string]x509.SignatureAlgorithm{
+	"1.2.840.113549.1.1.5":  x509.SHA1WithRSA,
+	"1.2.840.113549.1.1.11": x509.SHA256WithRSA,
+	"1.2.840.113549.1.1.12": x509.SHA384WithRSA,
+	"1.2.840.113549.1.1.13": x509.SHA512WithRSA,
+	"1.2.840.10045.4.1":     x509.ECDSAWithSHA1,
+	"1.2.840.10045.4.3.2":   x509.ECDSAWithSHA256,
+	"1.2.840.10045.4.3.3":   x509.ECDSAWithSHA384,
+	"1.2.840.10045.4.3.4":   x509.ECDSAWithSHA512,
+}
+
+// The status of a certificate in a response.
+const (
+	statusGood = iota
+	statusRevoked
+	statusUnknown
+)
+
+const responseStatusSuccessful = 0
+
+type certID struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	NameHash      []byte
+	IssuerKeyHash []byte
+	SerialNumber  *big.Int
+}
+
+type request struct {
+	Cert certID
+}
+
+type tbsRequest struct {
+	Version     int `asn1:"explicit,tag:0,default:0,optional"`
+	RequestList []request
+}
+
+type ocspRequest struct {
+	TBSRequest tbsRequest
+}
+
+type responseASN1 struct {
+	Status   asn1.Enumerated
+	Response responseBytes `asn1:"explicit,tag:0,optional"`
+}
+
+type responseBytes struct {
+	ResponseType asn1.ObjectIdentifier
+	Response     []byte
+}
+
+type basicResponse struct {
+	TBSResponseData    responseData
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          asn1.BitString
+	Certificates       []asn1.RawValue `asn1:"explicit,tag:0,optional"`
+}
+
+type responseData struct {
+	Raw            asn1.RawContent
+	Version        int `asn1:"explicit,tag:0,default:0,optional"`
+	RawResponderID asn1.RawValue
+	ProducedAt     time.Time `asn1:"generalized"`
+	Responses      []singleResponse
+}
+
+type singleResponse struct {
+	CertID     certID
+	Status     asn1.RawValue // good [0], revoked [1] or unknown [2]
+	ThisUpdate time.Time     `asn1:"generalized"`
+	NextUpdate time.Time     `asn1:"generalized,explicit,tag:0,optional"`
+}
+
+type revokedInfo struct {
+	RevocationTime time.Time `asn1:"generalized"`
+}
+
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// response is the status of a certificate reported by a verified OCSP response.
+type response struct {
+	status     int
+	revokedAt  time.Time
+	thisUpdate time.Time
+	nextUpdate time.Time
+}
+
+// newCertID returns the ID of cert, issued by issuer, hashed with SHA-1.
+func newCertID(cert, issuer *x509.Certificate) (certID, error) {
+	nameHash, keyHash, err := issuerHashes(issuer, crypto.SHA1)
+	if err != nil {
+		return certID{}, err
+	}
+	return certID{
+		HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA1, Parameters: asn1.NullRawValue},
+		NameHash:      nameHash,
+		IssuerKeyHash: keyHash,
+		SerialNumber:  cert.SerialNumber,
+	}, nil
+}
+
+func issuerHashes(issuer *x509.Certificate, hash crypto.Hash) ([]byte, []byte, error) {
+	var spki subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(issuer.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return nil, nil, err
+	}
+	h := hash.New()
+	h.Write(issuer.RawSubject)
+	nameHash := h.Sum(nil)
+	h.Reset()
+	h.Write(spki.PublicKey.RightAlign())
+	return nameHash, h.Sum(nil), nil
+}
+
+// matches returns true if id identifies cert, issued by issuer.
+func (id certID) matches(cert, issuer *x509.Certificate) bool {
+	if id.SerialNumber == nil || id.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+		return false
+	}
+	hash, ok := hashOIDs[id.HashAlgorithm.Algorithm.String()]
+	if !ok {
+		return false
+	}
+	nameHash, keyHash, err := issuerHashes(issuer, hash)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(id.NameHash, nameHash) && bytes.Equal(id.IssuerKeyHash, keyHash)
+}
+
+// createRequest returns a DER encoded OCSP request for the status of cert, issued by issuer.
+func createRequest(cert, issuer *x509.Certificate) ([]byte, error) {
+	id, err := newCertID(cert, issuer)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(ocspRequest{TBSRequest: tbsRequest{RequestList: []request{{Cert: id}}}})
+}
+
+// parseResponse parses a DER encoded OCSP response for the status of cert, issued by issuer, and
+// verifies its signature. The response must be signed by the issuer or by a responder certificate
+// the issuer delegated OCSP signing to.
+func parseResponse(der []byte, cert, issuer *x509.Certificate) (*response, error) {
+	var resp responseASN1
+	rest, err := asn1.Unmarshal(der, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing data in OCSP response")
+	}
+	if resp.Status != responseStatusSuccessful {
+		return nil, fmt.Errorf("OCSP responder returned status %d", resp.Status)
+	}
+	if !resp.Response.ResponseType.Equal(oidOCSPBasic) {
+		return nil, errors.New("OCSP response is not a basic response")
+	}
+
+	var basic basicResponse
+	if _, err = asn1.Unmarshal(resp.Response.Response, &basic); err != nil {
+		return nil, err
+	}
+	if err = verifySignature(&basic, issuer); err != nil {
+		return nil, err
+	}
+
+	for _, single := range basic.TBSResponseData.Responses {
+		if !single.CertID.matches(cert, issuer) {
+			continue
+		}
+		r := &response{thisUpdate: single.ThisUpdate, nextUpdate: single.NextUpdate}
+		switch single.Status.Tag {
+		case statusGood:
+			r.status = statusGood
+		case statusRevoked:
+			var info revokedInfo
+			if _, err = asn1.UnmarshalWithParams(single.Status.FullBytes, &info, "tag:1"); err != nil {
+				return nil, err
+			}
+			r.status = statusRevoked
+			r.revokedAt = info.RevocationTime
+		default:
+			r.status = statusUnknown
+		}
+		return r, nil
+	}
+	return nil, errors.New("OCSP response does not contain the status of the certificate")
+}
+
+func verifySignature(basic *basicResponse, issuer *x509.Certificate) error {
+	alg, ok := signatureAlgorithms[basic.SignatureAlgorithm.Algorithm.String()]
+	if !ok {
+		return fmt.Errorf("unsupported OCSP signature algorithm %v", basic.SignatureAlgorithm.Algorithm)
+	}
+
+	signer := issuer
+	if len(basic.Certificates) > 0 {
+		responder, err := x509.ParseCertificate(basic.Certificates[0].FullBytes)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(responder.Raw, issuer.Raw) {
+			if err = responder.CheckSignatureFrom(issuer); err != nil {
+				return fmt.Errorf("OCSP responder certificate is not signed by the issuer: %v", err)
+			}
+			if !hasOCSPSigning(responder) {
+				return errors.New("OCSP responder certificate is not authorized to sign OCSP responses")
+			}
+			signer = responder
+		}
+	}
+
+	err := signer.CheckSignature(alg, basic.TBSResponseData.Raw, basic.Signature.RightAlign())
+	if err != nil {
+		return fmt.Errorf("invalid OCSP response signature: %v", err)
+	}
+	return nil
+}
+
+func hasOCSPSigning(cert *x509.Certificate) bool {
+	for _, usage := range cert.ExtKeyUsage {
+		if usage == x509.ExtKeyUsageOCSPSigning {
+			return true
+		}
+	}
+	return false
+}
+
+// mustStaple returns true if cert has the TLS feature extension requiring a stapled OCSP response.
+func mustStaple(cert *x509.Certificate) bool {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidTLSFeature) {
+			continue
+		}
+		var features []int
+		if _, err := asn1.Unmarshal(ext.Value, &features); err != nil {
+			return false
+		}
+		for _, feature := range features {
+			if feature == statusRequestFeature {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -26,6 +26,7 @@ type Insert struct {
 	N                 int
 	WriteErrors       []WriteError       `bson:"writeErrors"`
 	WriteConcernError *WriteConcernError `bson:"writeConcernError"`
+	ErrorLabels       []string           `bson:"errorLabels"`
 }
 
 // StartSession is a result from a StartSession command.
@@ -41,6 +42,7 @@ type Delete struct {
 	N                 int
 	WriteErrors       []WriteError       `bson:"writeErrors"`
 	WriteConcernError *WriteConcernError `bson:"writeConcernError"`
+	ErrorLabels       []string           `bson:"errorLabels"`
 }
 
 // Update is a result of an Update command.
@@ -50,6 +52,7 @@ type Update struct {
 	Upserted          []Upsert           `bson:"upserted"`
 	WriteErrors       []WriteError       `bson:"writeErrors"`
 	WriteConcernError *WriteConcernError `bson:"writeConcernError"`
+	ErrorLabels       []string           `bson:"errorLabels"`
 }
 
 // Distinct is a result from a Distinct command.
@@ -120,8 +123,18 @@ type IsMaster struct {
 	SaslSupportedMechs           []string           `bson:"saslSupportedMechs,omitempty"`
 	Secondary                    bool               `bson:"secondary,omitempty"`
 	SetName                      string             `bson:"setName,omitempty"`
+	ServiceID                    primitive.ObjectID `bson:"serviceId,omitempty"`
 	SetVersion                   uint32             `bson:"setVersion,omitempty"`
 	Tags                         map[string]string  `bson:"tags,omitempty"`
+	TopologyVersion              *TopologyVersion   `bson:"topologyVersion,omitempty"`
+}
+
+// TopologyVersion is the version of the topology state of a server, reported by servers that
+// support awaitable isMaster commands. The counter is incremented whenever the state changes, and
+// the process ID changes when the server restarts.
+type TopologyVersion struct {
+	ProcessID primitive.ObjectID `bson:"processId"`
+	Counter   int64              `bson:"counter"`
 }
 
 // BuildInfo is a result of a BuildInfo command.
@@ -149,16 +149,15 @@ func (m *Msg) UnmarshalWireMessage(b []byte) error {
 			// length of documents to read
 			// sequenceLen - 4 bytes for size field - identifierLength (including \0)
 			docsLen := int(sds.Size) - 4 - len(identifier) - 1
-			for docsLen > 0 {
-				rdr, size, err := readDocument(b, int32(position))
+			if docsLen > 0 {
+				docs, err := readDocuments(b, position, position+docsLen)
 				if err.Message != "" {
 					err.Type = ErrOpMsg
 					return err
 				}
 
-				position += size
-				sds.Documents = append(sds.Documents, rdr)
-				docsLen -= size
+				position += docsLen
+				sds.Documents = docs
 			}
 
 			sectionBytes -= int32(sds.Len())
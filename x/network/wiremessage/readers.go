@@ -34,18 +34,50 @@ func readInt64(b []byte, pos int32) int64 {
 // readDocument will attempt to read a bson.Reader from the given slice of bytes
 // from the given position.
 func readDocument(b []byte, pos int32) (bson.Raw, int, Error) {
+	rdr, size, err := sliceDocument(b, pos)
+	if err.Message != "" {
+		return nil, 0, err
+	}
+	copied := make(bson.Raw, size)
+	copy(copied, rdr)
+	return copied, size, err
+}
+
+// readDocuments reads the documents stored between pos and end in b. The documents are copied
+// into a single buffer instead of one buffer each, so that reading a batch of documents only
+// allocates once.
+func readDocuments(b []byte, pos, end int) ([]bson.Raw, Error) {
+	if end > len(b) || pos > end {
+		return nil, Error{Message: "document size is larger than available bytes"}
+	}
+	buf := make([]byte, end-pos)
+	copy(buf, b[pos:end])
+
+	var docs []bson.Raw
+	for pos = 0; pos < len(buf); {
+		rdr, size, err := sliceDocument(buf, int32(pos))
+		if err.Message != "" {
+			return nil, err
+		}
+		docs = append(docs, rdr)
+		pos += size
+	}
+	return docs, Error{Type: ErrNil}
+}
+
+// sliceDocument returns the document at the given position of b without copying it. The capacity
+// of the returned document is its length, so appending to it does not overwrite the following
+// bytes of b.
+func sliceDocument(b []byte, pos int32) (bson.Raw, int, Error) {
 	if int(pos)+4 > len(b) {
 		return nil, 0, Error{Message: "document too small to be valid"}
 	}
 	size := int(readInt32(b, int32(pos)))
-	if int(pos)+size > len(b) {
+	if size < 5 || int(pos)+size > len(b) {
 		return nil, 0, Error{Message: "document size is larger than available bytes"}
 	}
 	if b[int(pos)+size-1] != 0x00 {
 		return nil, 0, Error{Message: "document invalid, last byte is not null"}
 	}
-	// TODO(GODRIVER-138): When we add 3.0 support, alter this so we either do one larger make or use a pool.
-	rdr := make(bson.Raw, size)
-	copy(rdr, b[pos:int(pos)+size])
-	return rdr, size, Error{Type: ErrNil}
+	return bson.Raw(b[pos : int(pos)+size : int(pos)+size]), size, Error{Type: ErrNil}
 }
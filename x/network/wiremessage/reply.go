@@ -103,15 +103,13 @@ func (r *Reply) UnmarshalWireMessage(b []byte) error {
 	r.CursorID = readInt64(b, 20)
 	r.StartingFrom = readInt32(b, 28)
 	r.NumberReturned = readInt32(b, 32)
-	pos := 36
-	for pos < len(b) {
-		rdr, size, err := readDocument(b, int32(pos))
+	if len(b) > 36 {
+		docs, err := readDocuments(b, 36, len(b))
 		if err.Message != "" {
 			err.Type = ErrOpReply
 			return err
 		}
-		r.Documents = append(r.Documents, rdr)
-		pos += size
+		r.Documents = docs
 	}
 
 	return nil
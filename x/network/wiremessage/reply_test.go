@@ -59,4 +59,28 @@ func TestReply(t *testing.T) {
 			})
 		}
 	})
+	t.Run("UnmarshalWireMessage copies documents", func(t *testing.T) {
+		b := []byte{
+			0x38, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			0x0A, 0x00, 0x00, 0x00, 0x0A, 'f', 'o', 'o', 0x00, 0x00,
+			0x0A, 0x00, 0x00, 0x00, 0x0A, 'b', 'a', 'r', 0x00, 0x00,
+		}
+		var r Reply
+		if err := r.UnmarshalWireMessage(b); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for i := range b {
+			b[i] = 0xFF
+		}
+		first := append(r.Documents[0], 0x00)
+		first[5] = 'x'
+		want := bson.Raw{0x0A, 0x00, 0x00, 0x00, 0x0A, 'b', 'a', 'r', 0x00, 0x00}
+		if diff := cmp.Diff(r.Documents[1], want); diff != "" {
+			t.Errorf("Documents differ: (-got +want)\n%s", diff)
+		}
+	})
 }